@@ -0,0 +1,175 @@
+// cmd/codegen/constraints.go
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// constraintSource declares gen.Constraint.
+const constraintSource = `// Constraint is a limit on a field that the spec only states in the
+// field's description, such as "1-4096 characters" or "must be one of".
+type Constraint struct {
+	// Min and Max bound the length of a string or an array, or the value
+	// of a number. Max is 0 when the description gives no bounds.
+	Min, Max int64
+	// Unit is what a length counts: "characters" or "bytes" of a string,
+	// or "items" of an array. It is empty for the value of a number.
+	Unit string
+	// Parsed is set when a text's length counts after entities parsing.
+	Parsed bool
+	// Enum lists the values the field can take, if the spec lists them.
+	Enum []string
+}
+`
+
+// constraint is a limit parsed from a field description.
+type constraint struct {
+	min, max int64
+	unit     string
+	parsed   bool
+	enum     []string
+}
+
+var (
+	// lengthPattern matches string lengths, as in "1-4096 characters"
+	lengthPattern = regexp.MustCompile(`\b(\d+)-(\d+) (characters|bytes)\b`)
+	// itemsPattern matches array sizes, as in "list of 1-100 identifiers"
+	// or "must include 2-10 items"
+	itemsPattern = regexp.MustCompile(`\b(?:[Ll]ist of|include) (\d+)-(\d+)\b`)
+	// rangePattern matches number ranges, as in "Values between 1-100 are
+	// accepted" or "; 0-1500"
+	rangePattern = regexp.MustCompile(`(?:^|[\s,;])(\d+)-(\d+)(?:[.,;]|$| are accepted)`)
+	// quotedPattern matches the quoted values of a list
+	quotedPattern = regexp.MustCompile(`"([^"]+)"`)
+	// alternativesPattern matches quoted alternatives set off by commas,
+	// as in "Poll type, "quiz" or "regular", defaults to"
+	alternativesPattern = regexp.MustCompile(`, ("[^"]+"(?:, "[^"]+")* or "[^"]+"),`)
+	// discriminatorPattern matches the value a field of a union's type
+	// always has, as in "Type of the message origin, always "user"" or
+	// "Scope type, must be default"
+	discriminatorPattern = regexp.MustCompile(`(?:always|must be) "?([a-z0-9_]+)"?\.?$`)
+	// actionPattern matches the values of a "Choose one" list, as in
+	// "typing for text messages, record_video or upload_video for videos"
+	actionPattern = regexp.MustCompile(`\b([a-z_]+)(?: for | or )`)
+	// numberPattern matches the numbers of a list, which may be products
+	// as in "6 * 3600"
+	numberPattern = regexp.MustCompile(`\b(\d+)(?: \* (\d+))?`)
+	// hexPattern matches the hexadecimal forms given after numbers, as in
+	// "7322096 (0x6FB9F0)"
+	hexPattern = regexp.MustCompile(` \(0x[0-9A-Fa-f]+\)`)
+)
+
+// parseConstraint returns the constraint stated in a field's description,
+// or nil if there is none. Fields of several types only have one if they
+// are all arrays, such as the media of sendMediaGroup.
+func parseConstraint(field Field) *constraint {
+	if len(field.Types) == 0 {
+		return nil
+	}
+	for _, t := range field.Types[1:] {
+		if !strings.HasPrefix(t, "Array of ") || !strings.HasPrefix(field.Types[0], "Array of ") {
+			return nil
+		}
+	}
+	d := field.Description
+	c := &constraint{}
+	switch t := field.Types[0]; {
+	case strings.HasPrefix(t, "Array of "):
+		if m := itemsPattern.FindStringSubmatch(d); m != nil {
+			c.min, c.max, c.unit = atoi(m[1]), atoi(m[2]), "items"
+		}
+	case t == "String":
+		if m := lengthPattern.FindStringSubmatchIndex(d); m != nil {
+			c.min, c.max, c.unit = atoi(d[m[2]:m[3]]), atoi(d[m[4]:m[5]]), d[m[6]:m[7]]
+			c.parsed = strings.Contains(sentence(d[m[1]:]), "after entities parsing")
+		}
+		switch {
+		case strings.Contains(d, "must be one of"):
+			for _, m := range quotedPattern.FindAllStringSubmatch(sentence(after(d, "must be one of")), -1) {
+				c.enum = append(c.enum, m[1])
+			}
+		case strings.Contains(d, "Choose one"):
+			for _, m := range actionPattern.FindAllStringSubmatch(after(after(d, "Choose one"), ":"), -1) {
+				c.enum = append(c.enum, m[1])
+			}
+		case discriminatorPattern.MatchString(d):
+			c.enum = []string{discriminatorPattern.FindStringSubmatch(d)[1]}
+		default:
+			if m := alternativesPattern.FindStringSubmatch(d); m != nil {
+				for _, q := range quotedPattern.FindAllStringSubmatch(m[1], -1) {
+					c.enum = append(c.enum, q[1])
+				}
+			}
+		}
+	case t == "Integer" || t == "Float":
+		if strings.Contains(d, "must be one of") {
+			for _, m := range numberPattern.FindAllStringSubmatch(hexPattern.ReplaceAllString(sentence(after(d, "must be one of")), ""), -1) {
+				n := atoi(m[1])
+				if m[2] != "" {
+					n *= atoi(m[2])
+				}
+				c.enum = append(c.enum, strconv.FormatInt(n, 10))
+			}
+		} else if m := rangePattern.FindStringSubmatch(d); m != nil {
+			c.min, c.max = atoi(m[1]), atoi(m[2])
+		}
+	}
+	if c.max == 0 && len(c.enum) == 0 {
+		return nil
+	}
+	return c
+}
+
+// constraintLiteral returns the gen.Constraint literal of a field, or ""
+// if it has no constraint.
+func constraintLiteral(field Field) string {
+	c := parseConstraint(field)
+	if c == nil {
+		return ""
+	}
+	var parts []string
+	if c.max > 0 {
+		parts = append(parts, fmt.Sprintf("Min: %d, Max: %d", c.min, c.max))
+		if c.unit != "" {
+			parts = append(parts, fmt.Sprintf("Unit: %q", c.unit))
+		}
+		if c.parsed {
+			parts = append(parts, "Parsed: true")
+		}
+	}
+	if len(c.enum) > 0 {
+		parts = append(parts, fmt.Sprintf("Enum: %#v", c.enum))
+	}
+	return "&Constraint{" + strings.Join(parts, ", ") + "}"
+}
+
+// fieldSpecLiteral returns the gen.FieldSpec literal of a field.
+func fieldSpecLiteral(field Field) string {
+	literal := fmt.Sprintf("{Name: %q, Types: %#v, Required: %v", field.Name, field.Types, field.Required)
+	if c := constraintLiteral(field); c != "" {
+		literal += ", Constraint: " + c
+	}
+	return literal + "}"
+}
+
+// sentence returns s up to the end of its first sentence.
+func sentence(s string) string {
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// after returns what follows the first sep in s, or "" if s has no sep.
+func after(s, sep string) string {
+	_, rest, _ := strings.Cut(s, sep)
+	return rest
+}
+
+func atoi(s string) int64 {
+	n, _ := strconv.ParseInt(s, 10, 64)
+	return n
+}
@@ -0,0 +1,109 @@
+// cmd/codegen/constraints_test.go
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConstraint(t *testing.T) {
+	// Descriptions are taken from the spec
+	tests := []struct {
+		name  string
+		field Field
+		want  *constraint
+	}{
+		{"characters after entities parsing", Field{
+			Types:       []string{"String"},
+			Description: "Text of the message to be sent, 1-4096 characters after entities parsing",
+		}, &constraint{min: 1, max: 4096, unit: "characters", parsed: true}},
+		{"characters before entities parsing", Field{
+			Types:       []string{"String"},
+			Description: "Text of the notification. If not specified, nothing will be shown to the user, 0-200 characters",
+		}, &constraint{min: 0, max: 200, unit: "characters"}},
+		{"entities parsing with a line feed limit", Field{
+			Types:       []string{"String"},
+			Description: "Text that is shown when a user chooses an incorrect answer or taps on the lamp icon in a quiz-style poll, 0-200 characters with at most 2 line feeds after entities parsing",
+		}, &constraint{min: 0, max: 200, unit: "characters", parsed: true}},
+		{"bytes", Field{
+			Types:       []string{"String"},
+			Description: "Optional. Data to be sent in a callback query to the bot when the button is pressed, 1-64 bytes",
+		}, &constraint{min: 1, max: 64, unit: "bytes"}},
+		{"values between", Field{
+			Types:       []string{"Integer"},
+			Description: "Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.",
+		}, &constraint{min: 1, max: 100}},
+		{"range before a sentence", Field{
+			Types:       []string{"Integer"},
+			Description: "Amount of time in seconds the poll will be active after creation, 5-600. Can't be used together with close_date.",
+		}, &constraint{min: 5, max: 600}},
+		{"list of items", Field{
+			Types:       []string{"Array of Integer"},
+			Description: "A JSON-serialized list of 1-100 identifiers of messages to delete. See deleteMessage for limitations on which messages can be deleted",
+		}, &constraint{min: 1, max: 100, unit: "items"}},
+		{"arrays of several types", Field{
+			Types:       []string{"Array of InputMediaAudio", "Array of InputMediaDocument"},
+			Description: "A JSON-serialized array describing messages to be sent, must include 2-10 items",
+		}, &constraint{min: 2, max: 10, unit: "items"}},
+		{"strings one of", Field{
+			Types:       []string{"String"},
+			Description: `Format of the added sticker, must be one of "static" for a .WEBP or .PNG image, "animated" for a .TGS animation, "video" for a .WEBM video`,
+		}, &constraint{enum: []string{"static", "animated", "video"}}},
+		{"numbers one of", Field{
+			Types:       []string{"Integer"},
+			Description: "Color of the topic icon in RGB format. Currently, must be one of 7322096 (0x6FB9F0), 16766590 (0xFFD67E), 13338331 (0xCB86DB), 9367192 (0x8EEE98), 16749490 (0xFF93B2), or 16478047 (0xFB6F5F)",
+		}, &constraint{enum: []string{"7322096", "16766590", "13338331", "9367192", "16749490", "16478047"}}},
+		{"products one of", Field{
+			Types:       []string{"Integer"},
+			Description: "Period after which the story is moved to the archive, in seconds; must be one of 6 * 3600, 12 * 3600, 86400, or 2 * 86400",
+		}, &constraint{enum: []string{"21600", "43200", "86400", "172800"}}},
+		{"choose one", Field{
+			Types:       []string{"String"},
+			Description: "Type of action to broadcast. Choose one, depending on what the user is about to receive: typing for text messages, upload_photo for photos, record_video or upload_video for videos, record_voice or upload_voice for voice notes, upload_document for general files, choose_sticker for stickers, find_location for location data, record_video_note or upload_video_note for video notes.",
+		}, &constraint{enum: []string{"typing", "upload_photo", "record_video", "upload_video", "record_voice", "upload_voice", "upload_document", "choose_sticker", "find_location", "record_video_note", "upload_video_note"}}},
+		{"quoted alternatives", Field{
+			Types:       []string{"String"},
+			Description: `Poll type, "quiz" or "regular", defaults to "regular"`,
+		}, &constraint{enum: []string{"quiz", "regular"}}},
+		{"discriminator", Field{
+			Types:       []string{"String"},
+			Description: `Type of the message origin, always "user"`,
+		}, &constraint{enum: []string{"user"}}},
+		{"no constraint", Field{
+			Types:       []string{"String"},
+			Description: "Mode for parsing entities in the message text. See formatting options for more details.",
+		}, nil},
+		{"number without a range", Field{
+			Types:       []string{"Integer"},
+			Description: "Identifier of the target message. If the message belongs to a media group, the reaction is set to the first non-deleted message in the group instead.",
+		}, nil},
+		{"boolean", Field{
+			Types:       []string{"Boolean"},
+			Description: "Sends the message silently. Users will receive a notification with no sound.",
+		}, nil},
+		{"several types", Field{
+			Types:       []string{"Integer", "String"},
+			Description: "Unique identifier for the target chat or username of the target channel (in the format @channelusername)",
+		}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseConstraint(tt.field); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConstraint(%q) = %+v, want %+v", tt.field.Description, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConstraintLiteral(t *testing.T) {
+	field := Field{
+		Types:       []string{"String"},
+		Description: "Photo caption (may also be used when resending photos by file_id), 0-1024 characters after entities parsing",
+	}
+	if got, want := constraintLiteral(field), `&Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}`; got != want {
+		t.Errorf("constraintLiteral = %s, want %s", got, want)
+	}
+	if got := constraintLiteral(Field{Types: []string{"Boolean"}}); got != "" {
+		t.Errorf("expected no literal without a constraint, got %s", got)
+	}
+}
@@ -42,6 +42,14 @@ func generateFixtures(spec *Spec, outDir string) error {
 
 func writeFixture(f *os.File, t Type, spec *Spec) error {
 	fmt.Fprintf(f, "// New%s creates a fixture %s\n", t.Name, t.Name)
+	// A union's fixture is its first subtype
+	if isUnion(spec, t.Name) {
+		fmt.Fprintf(f, "func New%s() %s {\n", t.Name, t.Name)
+		fmt.Fprintf(f, "\treturn New%s()\n", t.Subtypes[0])
+		fmt.Fprintln(f, "}")
+		fmt.Fprintln(f)
+		return nil
+	}
 	fmt.Fprintf(f, "func New%s() *%s {\n", t.Name, t.Name)
 	fmt.Fprintf(f, "\treturn &%s{\n", t.Name)
 
@@ -105,6 +113,9 @@ func fixtureValue(field Field, spec *Spec) string {
 		return "true"
 	default:
 		// Check if it's a known type
+		if isUnion(spec, t) {
+			return fmt.Sprintf("New%s()", t)
+		}
 		if _, ok := spec.Types[t]; ok {
 			return fmt.Sprintf("*New%s()", t)
 		}
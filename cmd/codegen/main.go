@@ -40,11 +40,23 @@ func main() {
 	}
 	fmt.Println("Generated methods.go")
 
+	if err := generateTypeSpecs(spec, *outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate type specs: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated typespecs.go")
+
 	if err := generateFixtures(spec, *outDir); err != nil {
 		fmt.Fprintf(os.Stderr, "failed to generate fixtures: %v\n", err)
 		os.Exit(1)
 	}
 	fmt.Println("Generated fixtures.go")
+
+	if err := generateSchemas(spec, *outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to generate schemas: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println("Generated schemas.go")
 }
 
 func loadSpec(path string) (*Spec, error) {
@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
 )
 
 func generateMethods(spec *Spec, outDir string) error {
@@ -25,18 +26,33 @@ func generateMethods(spec *Spec, outDir string) error {
 	fmt.Fprintln(f, "\tName     string")
 	fmt.Fprintln(f, "\tTypes    []string")
 	fmt.Fprintln(f, "\tRequired bool")
+	fmt.Fprintln(f, "\t// Constraint is the limit the description states, if any")
+	fmt.Fprintln(f, "\tConstraint *Constraint")
 	fmt.Fprintln(f, "}")
 	fmt.Fprintln(f)
 
+	fmt.Fprint(f, constraintSource)
+	fmt.Fprintln(f)
+
 	// Generate MethodSpec type
 	fmt.Fprintln(f, "// MethodSpec describes a Bot API method")
 	fmt.Fprintln(f, "type MethodSpec struct {")
 	fmt.Fprintln(f, "\tName    string")
 	fmt.Fprintln(f, "\tReturns []string")
-	fmt.Fprintln(f, "\tFields  []FieldSpec")
+	fmt.Fprintln(f, "\t// Result is the parsed form of Returns")
+	fmt.Fprintln(f, "\tResult TypeRef")
+	fmt.Fprintln(f, "\tFields []FieldSpec")
 	fmt.Fprintln(f, "}")
 	fmt.Fprintln(f)
 
+	fmt.Fprint(f, typeRefSource)
+	fmt.Fprintln(f)
+
+	// Generate spec version
+	fmt.Fprintln(f, "// Version is the Bot API version the spec describes")
+	fmt.Fprintf(f, "const Version = %q\n", strings.TrimPrefix(spec.Version, "Bot API "))
+	fmt.Fprintln(f)
+
 	// Generate method registry
 	fmt.Fprintln(f, "// Methods is the registry of all Bot API methods")
 	fmt.Fprintln(f, "var Methods = map[string]MethodSpec{")
@@ -52,12 +68,12 @@ func generateMethods(spec *Spec, outDir string) error {
 		fmt.Fprintf(f, "\t%q: {\n", name)
 		fmt.Fprintf(f, "\t\tName:    %q,\n", m.Name)
 		fmt.Fprintf(f, "\t\tReturns: %#v,\n", m.Returns)
+		fmt.Fprintf(f, "\t\tResult:  %s,\n", typeRefLiteral(m.Returns))
 
 		if len(m.Fields) > 0 {
 			fmt.Fprintln(f, "\t\tFields: []FieldSpec{")
 			for _, field := range m.Fields {
-				fmt.Fprintf(f, "\t\t\t{Name: %q, Types: %#v, Required: %v},\n",
-					field.Name, field.Types, field.Required)
+				fmt.Fprintf(f, "\t\t\t%s,\n", fieldSpecLiteral(field))
 			}
 			fmt.Fprintln(f, "\t\t},")
 		}
@@ -0,0 +1,261 @@
+// cmd/codegen/schemas.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemaDialect is the JSON Schema version the generated documents use.
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// schema is a JSON Schema document, or a subschema of one.
+type schema struct {
+	Dialect              string        `json:"$schema,omitempty"`
+	ID                   string        `json:"$id,omitempty"`
+	Title                string        `json:"title,omitempty"`
+	Description          string        `json:"description,omitempty"`
+	Ref                  string        `json:"$ref,omitempty"`
+	Type                 string        `json:"type,omitempty"`
+	Enum                 []interface{} `json:"enum,omitempty"`
+	MinLength            *int64        `json:"minLength,omitempty"`
+	MaxLength            *int64        `json:"maxLength,omitempty"`
+	Minimum              *int64        `json:"minimum,omitempty"`
+	Maximum              *int64        `json:"maximum,omitempty"`
+	MinItems             *int64        `json:"minItems,omitempty"`
+	MaxItems             *int64        `json:"maxItems,omitempty"`
+	Items                *schema       `json:"items,omitempty"`
+	AnyOf                []*schema     `json:"anyOf,omitempty"`
+	Properties           properties    `json:"properties,omitempty"`
+	Required             []string      `json:"required,omitempty"`
+	AdditionalProperties *bool         `json:"additionalProperties,omitempty"`
+}
+
+// property is a field of an object schema.
+type property struct {
+	name   string
+	schema *schema
+}
+
+// properties are the fields of an object schema, which marshal in the
+// order the spec lists them rather than sorted.
+type properties []property
+
+func (p properties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, prop := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, _ := marshalJSON(prop.name, "")
+		value, err := marshalJSON(prop.schema, "")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// marshalJSON encodes v like json.MarshalIndent, but leaves the <, > and &
+// of descriptions unescaped.
+func marshalJSON(v interface{}, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", indent)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// schemaFile returns the file name of a type's schema, which is also how
+// other schemas refer to it.
+func schemaFile(name string) string {
+	return name + ".json"
+}
+
+// generateSchemas writes a JSON Schema document per type into the schema
+// directory of outDir, and schemas.go, which embeds them.
+func generateSchemas(spec *Spec, outDir string) error {
+	dir := filepath.Join(outDir, "schema")
+	// Start over so that types dropped from the spec lose their schemas
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(spec.Types))
+	for name := range spec.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		data, err := marshalJSON(typeSchema(spec, spec.Types[name]), "  ")
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, schemaFile(name)), data, 0644); err != nil {
+			return err
+		}
+	}
+
+	f, err := os.Create(filepath.Join(outDir, "schemas.go"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintln(f, "// Code generated by codegen. DO NOT EDIT.")
+	fmt.Fprintln(f, "package gen")
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "import \"embed\"")
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "// schemas holds the JSON Schema document of each Bot API type. Schemas")
+	fmt.Fprintln(f, "// refer to each other by file name, as in \"Message.json\".")
+	fmt.Fprintln(f, "//")
+	fmt.Fprintln(f, "//go:embed schema/*.json")
+	fmt.Fprintln(f, "var schemas embed.FS")
+	fmt.Fprintln(f)
+	fmt.Fprintln(f, "// Schema returns the JSON Schema document of the Bot API type name, and")
+	fmt.Fprintln(f, "// whether there is such a type.")
+	fmt.Fprintln(f, "func Schema(name string) ([]byte, bool) {")
+	fmt.Fprintln(f, "\tif _, ok := Types[name]; !ok {")
+	fmt.Fprintln(f, "\t\treturn nil, false")
+	fmt.Fprintln(f, "\t}")
+	fmt.Fprintf(f, "\tdata, err := schemas.ReadFile(\"schema/\" + name + %q)\n", ".json")
+	fmt.Fprintln(f, "\treturn data, err == nil")
+	fmt.Fprintln(f, "}")
+
+	return nil
+}
+
+// typeSchema returns the schema document of a type. Union types accept
+// any of their subtypes, InputFile is the string that names a file, and
+// the other types are objects without fields beyond the spec's.
+func typeSchema(spec *Spec, t Type) *schema {
+	s := &schema{
+		Dialect:     schemaDialect,
+		ID:          schemaFile(t.Name),
+		Title:       t.Name,
+		Description: strings.Join(t.Description, "\n"),
+	}
+	switch {
+	case len(t.Subtypes) > 0:
+		for _, sub := range t.Subtypes {
+			s.AnyOf = append(s.AnyOf, &schema{Ref: schemaFile(sub)})
+		}
+	case t.Name == "InputFile":
+		// Uploads are sent as multipart parts and named in JSON by their
+		// file_id, URL, or "attach://<name>"
+		s.Type = "string"
+	default:
+		closed := false
+		s.Type = "object"
+		s.AdditionalProperties = &closed
+		for _, field := range t.Fields {
+			s.Properties = append(s.Properties, property{field.Name, fieldSchema(spec, field)})
+			if field.Required {
+				s.Required = append(s.Required, field.Name)
+			}
+		}
+	}
+	return s
+}
+
+// fieldSchema returns the schema of a field, with the constraint its
+// description states.
+func fieldSchema(spec *Spec, field Field) *schema {
+	var s *schema
+	if len(field.Types) == 1 {
+		s = typeRefSchema(spec, field.Types[0])
+	} else {
+		s = &schema{}
+		for _, t := range field.Types {
+			s.AnyOf = append(s.AnyOf, typeRefSchema(spec, t))
+		}
+	}
+	s.Description = field.Description
+	if c := parseConstraint(field); c != nil {
+		applyConstraint(s, field, c)
+	}
+	return s
+}
+
+// applyConstraint adds the keywords of c to the schema of field. Lengths
+// in bytes and lengths counted after entities parsing have no keyword, and
+// are only stated in the description.
+func applyConstraint(s *schema, field Field, c *constraint) {
+	if c.max > 0 {
+		min, max := c.min, c.max
+		switch c.unit {
+		case "items":
+			// Fields of several array types bound each of them
+			targets := []*schema{s}
+			if len(s.AnyOf) > 0 {
+				targets = s.AnyOf
+			}
+			for _, t := range targets {
+				if min > 0 {
+					t.MinItems = &min
+				}
+				t.MaxItems = &max
+			}
+		case "characters":
+			if !c.parsed {
+				if min > 0 {
+					s.MinLength = &min
+				}
+				s.MaxLength = &max
+			}
+		case "":
+			s.Minimum, s.Maximum = &min, &max
+		}
+	}
+	for _, v := range c.enum {
+		if field.Types[0] == "String" {
+			s.Enum = append(s.Enum, v)
+		} else if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			s.Enum = append(s.Enum, n)
+		}
+	}
+}
+
+// typeRefSchema returns the schema of a spec type reference such as
+// "Integer" or "Array of PhotoSize".
+func typeRefSchema(spec *Spec, t string) *schema {
+	if elem, ok := strings.CutPrefix(t, "Array of "); ok {
+		return &schema{Type: "array", Items: typeRefSchema(spec, elem)}
+	}
+	switch t {
+	case "Integer":
+		return &schema{Type: "integer"}
+	case "Float":
+		return &schema{Type: "number"}
+	case "String":
+		return &schema{Type: "string"}
+	case "Boolean":
+		return &schema{Type: "boolean"}
+	case "True":
+		return &schema{Enum: []interface{}{true}}
+	}
+	if _, ok := spec.Types[t]; ok {
+		return &schema{Ref: schemaFile(t)}
+	}
+	// Types missing from the spec accept anything
+	return &schema{}
+}
@@ -0,0 +1,109 @@
+// cmd/codegen/typeref.go
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// typeRefSource declares gen.TypeRef, the parsed form of the type
+// expressions in the spec, along with its parser.
+const typeRefSource = `// TypeRef is a parsed Bot API type expression. Exactly one of Name, Elem,
+// and Union is set: "Message" is a named type, "Array of Message" an array
+// of Message, and "Message or Boolean" a union.
+type TypeRef struct {
+	Name  string
+	Elem  *TypeRef
+	Union []TypeRef
+}
+
+// ParseType parses a type expression as written in the spec. Several
+// expressions make a union.
+func ParseType(types ...string) TypeRef {
+	if len(types) == 1 {
+		return parseSingleType(types[0])
+	}
+	union := make([]TypeRef, 0, len(types))
+	for _, t := range types {
+		union = append(union, parseSingleType(t))
+	}
+	return TypeRef{Union: union}
+}
+
+func parseSingleType(t string) TypeRef {
+	if len(t) > len(arrayPrefix) && t[:len(arrayPrefix)] == arrayPrefix {
+		elem := parseSingleType(t[len(arrayPrefix):])
+		return TypeRef{Elem: &elem}
+	}
+	return TypeRef{Name: t}
+}
+
+const arrayPrefix = "Array of "
+
+// IsArray reports whether t is an array.
+func (t TypeRef) IsArray() bool { return t.Elem != nil }
+
+// IsUnion reports whether t is a union.
+func (t TypeRef) IsUnion() bool { return len(t.Union) > 0 }
+
+// Primary returns the first alternative of a union, or t itself. Responses
+// are generated as the primary type.
+func (t TypeRef) Primary() TypeRef {
+	if t.IsUnion() {
+		return t.Union[0]
+	}
+	return t
+}
+
+// Base returns the named type at the bottom of t's arrays, using the
+// primary alternative of unions.
+func (t TypeRef) Base() string {
+	t = t.Primary()
+	for t.IsArray() {
+		t = t.Elem.Primary()
+	}
+	return t.Name
+}
+
+// String formats t the way the spec writes it.
+func (t TypeRef) String() string {
+	switch {
+	case t.IsArray():
+		return arrayPrefix + t.Elem.String()
+	case t.IsUnion():
+		s := ""
+		for i, alt := range t.Union {
+			if i > 0 {
+				s += " or "
+			}
+			s += alt.String()
+		}
+		return s
+	default:
+		return t.Name
+	}
+}
+`
+
+// typeRefLiteral returns the gen.TypeRef literal of a type expression
+// given as the alternatives of a union.
+func typeRefLiteral(types []string) string {
+	switch len(types) {
+	case 0:
+		return "TypeRef{}"
+	case 1:
+		return singleTypeRefLiteral(types[0])
+	}
+	alts := make([]string, 0, len(types))
+	for _, t := range types {
+		alts = append(alts, singleTypeRefLiteral(t))
+	}
+	return "TypeRef{Union: []TypeRef{" + strings.Join(alts, ", ") + "}}"
+}
+
+func singleTypeRefLiteral(t string) string {
+	if elem, ok := strings.CutPrefix(t, "Array of "); ok {
+		return "TypeRef{Elem: &" + singleTypeRefLiteral(elem) + "}"
+	}
+	return fmt.Sprintf("TypeRef{Name: %q}", t)
+}
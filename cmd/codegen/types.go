@@ -29,7 +29,7 @@ func generateTypes(spec *Spec, outDir string) error {
 
 	for _, name := range names {
 		t := spec.Types[name]
-		if err := writeType(f, t); err != nil {
+		if err := writeType(f, t, spec); err != nil {
 			return err
 		}
 	}
@@ -37,16 +37,25 @@ func generateTypes(spec *Spec, outDir string) error {
 	return nil
 }
 
-func writeType(f *os.File, t Type) error {
+func writeType(f *os.File, t Type, spec *Spec) error {
 	// Write doc comment
 	if len(t.Description) > 0 {
 		fmt.Fprintf(f, "// %s %s\n", t.Name, t.Description[0])
 	}
 
+	// Union types are interfaces their subtypes implement
+	if isUnion(spec, t.Name) {
+		fmt.Fprintf(f, "type %s interface {\n", t.Name)
+		fmt.Fprintf(f, "\tis%s()\n", t.Name)
+		fmt.Fprintln(f, "}")
+		fmt.Fprintln(f)
+		return nil
+	}
+
 	fmt.Fprintf(f, "type %s struct {\n", t.Name)
 
 	for _, field := range t.Fields {
-		goType := toGoType(field.Types, !field.Required)
+		goType := toGoType(spec, field.Types, !field.Required)
 		jsonTag := field.Name
 		if !field.Required {
 			jsonTag += ",omitempty"
@@ -57,10 +66,22 @@ func writeType(f *os.File, t Type) error {
 	fmt.Fprintln(f, "}")
 	fmt.Fprintln(f)
 
+	for _, union := range t.SubtypeOf {
+		fmt.Fprintf(f, "func (*%s) is%s() {}\n", t.Name, union)
+		fmt.Fprintln(f)
+	}
+
 	return nil
 }
 
-func toGoType(types []string, optional bool) string {
+// isUnion reports whether a type is a union of subtypes, such as
+// ChatMember.
+func isUnion(spec *Spec, name string) bool {
+	t, ok := spec.Types[name]
+	return ok && len(t.Subtypes) > 0
+}
+
+func toGoType(spec *Spec, types []string, optional bool) string {
 	if len(types) == 0 {
 		return "interface{}"
 	}
@@ -70,7 +91,7 @@ func toGoType(types []string, optional bool) string {
 	// Handle arrays
 	if strings.HasPrefix(t, "Array of ") {
 		inner := strings.TrimPrefix(t, "Array of ")
-		return "[]" + toGoType([]string{inner}, false)
+		return "[]" + toGoType(spec, []string{inner}, false)
 	}
 
 	// Map Telegram types to Go types
@@ -99,6 +120,9 @@ func toGoType(types []string, optional bool) string {
 		if len(types) > 1 {
 			return "interface{}"
 		}
+		if isUnion(spec, t) {
+			return t
+		}
 		if optional {
 			return "*" + t
 		}
@@ -106,9 +130,19 @@ func toGoType(types []string, optional bool) string {
 	}
 }
 
+// initialisms are the words of field names written in upper case in Go.
+var initialisms = map[string]bool{
+	"id":  true,
+	"url": true,
+}
+
 func toCamelCase(s string) string {
 	parts := strings.Split(s, "_")
 	for i := range parts {
+		if initialisms[parts[i]] {
+			parts[i] = strings.ToUpper(parts[i])
+			continue
+		}
 		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
 	}
 	return strings.Join(parts, "")
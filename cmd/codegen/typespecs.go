@@ -0,0 +1,64 @@
+// cmd/codegen/typespecs.go
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func generateTypeSpecs(spec *Spec, outDir string) error {
+	f, err := os.Create(filepath.Join(outDir, "typespecs.go"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fmt.Fprintln(f, "// Code generated by codegen. DO NOT EDIT.")
+	fmt.Fprintln(f, "package gen")
+	fmt.Fprintln(f)
+
+	// Generate TypeSpec type
+	fmt.Fprintln(f, "// TypeSpec describes a Bot API type. Union types such as ChatMember")
+	fmt.Fprintln(f, "// have no fields and list the types they can be instead.")
+	fmt.Fprintln(f, "type TypeSpec struct {")
+	fmt.Fprintln(f, "\tName     string")
+	fmt.Fprintln(f, "\tFields   []FieldSpec")
+	fmt.Fprintln(f, "\tSubtypes []string")
+	fmt.Fprintln(f, "}")
+	fmt.Fprintln(f)
+
+	// Generate type registry
+	fmt.Fprintln(f, "// Types is the registry of all Bot API types")
+	fmt.Fprintln(f, "var Types = map[string]TypeSpec{")
+
+	names := make([]string, 0, len(spec.Types))
+	for name := range spec.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		t := spec.Types[name]
+		fmt.Fprintf(f, "\t%q: {\n", name)
+		fmt.Fprintf(f, "\t\tName: %q,\n", t.Name)
+
+		if len(t.Fields) > 0 {
+			fmt.Fprintln(f, "\t\tFields: []FieldSpec{")
+			for _, field := range t.Fields {
+				fmt.Fprintf(f, "\t\t\t%s,\n", fieldSpecLiteral(field))
+			}
+			fmt.Fprintln(f, "\t\t},")
+		}
+		if len(t.Subtypes) > 0 {
+			fmt.Fprintf(f, "\t\tSubtypes: %#v,\n", t.Subtypes)
+		}
+
+		fmt.Fprintln(f, "\t},")
+	}
+
+	fmt.Fprintln(f, "}")
+
+	return nil
+}
@@ -0,0 +1,94 @@
+// cmd/tg-mock/conformance.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/watzon/tg-mock/internal/conformance"
+	"github.com/watzon/tg-mock/internal/server"
+)
+
+// runConformance implements the conformance subcommand. It starts a mock
+// of its own, runs the suite, and returns the process exit code: 0 if
+// every case passed, 1 if any failed or wasn't called, and 2 if the run
+// could not be carried out.
+func runConformance(args []string) int {
+	fs := flag.NewFlagSet("conformance", flag.ExitOnError)
+	execCmd := fs.String("exec", "", "Shell command that runs the driver of the client library (default: check the mock's answers only)")
+	methods := fs.String("methods", "", "Comma-separated methods to include (default all)")
+	token := fs.String("token", conformance.DefaultToken, "Bot token of the suite")
+	seed := fs.Int64("seed", 1, "Faker seed of the mock, which makes its responses")
+	port := fs.Int("port", 0, "Port of the mock on 127.0.0.1 (default a free port)")
+	timeout := fs.Duration("timeout", conformance.DefaultTimeout, "How long the driver may run")
+	dir := fs.String("dir", "", "Directory to write suite.json and results.json to (default a temporary one)")
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	fs.Parse(args)
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", *port))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "conformance: %v\n", err)
+		return 2
+	}
+	srv := server.New(server.Config{Deterministic: true, FakerSeed: *seed})
+	httpServer := &http.Server{Handler: srv.Router()}
+	go httpServer.Serve(listener)
+	defer httpServer.Close()
+
+	cfg := conformance.Config{
+		BaseURL: "http://" + listener.Addr().String(),
+		Token:   *token,
+		Exec:    *execCmd,
+		Dir:     *dir,
+		Timeout: *timeout,
+		// The report goes to stdout, so the driver's output goes elsewhere
+		Output: os.Stderr,
+	}
+	if *methods != "" {
+		cfg.Methods = strings.Split(*methods, ",")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := conformance.Run(ctx, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "conformance: %v\n", err)
+		return 2
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		for _, c := range report.Cases {
+			if c.Status == conformance.Passed {
+				continue
+			}
+			fmt.Printf("%s: %s\n", c.ID, c.Status)
+			for _, problem := range c.Problems {
+				fmt.Printf("  %s\n", problem)
+			}
+		}
+		if report.ClientError != "" {
+			fmt.Printf("driver: %s\n", report.ClientError)
+		}
+		fmt.Printf("Bot API %s: %d passed, %d failed, %d not called, %d skipped in %.1fs\n",
+			report.SpecVersion, report.Summary[conformance.Passed], report.Summary[conformance.Failed],
+			report.Summary[conformance.NotCalled], report.Summary[conformance.Skipped], report.Duration)
+	}
+
+	if !report.Passed {
+		return 1
+	}
+	return 0
+}
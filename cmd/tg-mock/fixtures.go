@@ -0,0 +1,62 @@
+// cmd/tg-mock/fixtures.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/fixtures"
+	"github.com/watzon/tg-mock/internal/server"
+)
+
+// runFixtures implements the fixtures subcommand. It returns the process
+// exit code: 0 if the fixtures were written, and 2 otherwise.
+func runFixtures(args []string) int {
+	fs := flag.NewFlagSet("fixtures", flag.ExitOnError)
+	out := fs.String("out", "fixtures", "Directory to write the fixtures to")
+	seed := fs.Int64("seed", 1, "Seed for the faker (0 = random)")
+	optionalFields := fs.String("optional-fields", "", "Optional fields in generated objects: never, sparse, default, or always")
+	locales := fs.String("locales", "", "Comma-separated languages of generated names, titles, and texts")
+	types := fs.String("types", "", "Comma-separated types to export (default all, unless -methods is set)")
+	methods := fs.String("methods", "", "Comma-separated methods to export (default all, unless -types is set)")
+	fs.Parse(args)
+
+	density, err := faker.ParseDensity(*optionalFields)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fixtures: %v\n", err)
+		return 2
+	}
+	epoch := server.DeterministicEpoch(*seed)
+	cfg := fixtures.Config{
+		Faker: faker.Config{
+			Seed:           *seed,
+			OptionalFields: density,
+			Now:            func() time.Time { return epoch },
+		},
+	}
+	if *locales != "" {
+		cfg.Faker.Locales = strings.Split(*locales, ",")
+		if err := faker.CheckLocales(cfg.Faker.Locales); err != nil {
+			fmt.Fprintf(os.Stderr, "fixtures: %v\n", err)
+			return 2
+		}
+	}
+	if *types != "" {
+		cfg.Types = strings.Split(*types, ",")
+	}
+	if *methods != "" {
+		cfg.Methods = strings.Split(*methods, ",")
+	}
+
+	summary, err := fixtures.Export(*out, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "fixtures: %v\n", err)
+		return 2
+	}
+	fmt.Printf("wrote %d type and %d method fixtures to %s\n", summary.Types, summary.Methods, *out)
+	return 0
+}
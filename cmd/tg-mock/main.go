@@ -2,22 +2,64 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
+	"github.com/watzon/tg-mock/internal/adversarial"
+	"github.com/watzon/tg-mock/internal/apiversion"
+	"github.com/watzon/tg-mock/internal/chaos"
+	"github.com/watzon/tg-mock/internal/chats"
+	"github.com/watzon/tg-mock/internal/compat"
 	"github.com/watzon/tg-mock/internal/config"
+	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/floodlimit"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/hooks"
+	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/latency"
+	"github.com/watzon/tg-mock/internal/persona"
+	"github.com/watzon/tg-mock/internal/script"
 	"github.com/watzon/tg-mock/internal/server"
+	"github.com/watzon/tg-mock/internal/users"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "self-fuzz" {
+		os.Exit(runSelfFuzz(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "fixtures" {
+		os.Exit(runFixtures(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "conformance" {
+		os.Exit(runConformance(os.Args[2:]))
+	}
+
 	port := flag.Int("port", 0, "HTTP server port (overrides config)")
 	verbose := flag.Bool("verbose", false, "Enable verbose logging (overrides config)")
 	configPath := flag.String("config", "", "Path to config file")
 	storageDir := flag.String("storage-dir", "", "Directory for file storage")
+	filePathTTL := flag.Duration("file-path-ttl", 0, "How long file paths returned by getFile stay valid (default 1h)")
 	fakerSeed := flag.Int64("faker-seed", 0, "Seed for faker (0 = random, >0 = deterministic)")
+	deterministic := flag.Bool("deterministic", false, "Freeze the clock at a time derived from the faker seed, for byte-stable responses (overrides config)")
+	fakerLocales := flag.String("faker-locales", "", "Comma-separated languages of generated names, titles, and texts: en, ru, de, ja, ar (default en)")
+	fakerOptionalFields := flag.String("faker-optional-fields", "", "How many optional fields generated objects have: never, sparse, default, or always (overrides config)")
+	otlpEndpoint := flag.String("otlp-endpoint", "", "OTLP/HTTP collector to export traces to (default $OTEL_EXPORTER_OTLP_ENDPOINT)")
+	memoryLimits := flag.String("memory-limits", "", "Memory limits per store, e.g. recorder=64MB,queue=8MB,files=256MB")
+	memoryPolicy := flag.String("memory-policy", "", "What to do when a memory limit is reached: evict, reject, or log (default evict)")
+	corsOrigins := flag.String("cors-origins", "", "Comma-separated browser origins allowed to call the control API (* = any)")
+	controlToken := flag.String("control-token", "", "Token required for control API requests (enables shutdown/restart)")
+	recordFile := flag.String("record-file", "", "Append recorded requests to this JSONL file")
+	apiVersion := flag.String("api-version", "", "Simulate an older Bot API version, e.g. 7.0 (default latest)")
+	enforceRetryAfter := flag.Bool("enforce-retry-after", false, "Reject calls made before the retry_after of a 429 elapsed (overrides config)")
+	validateResults := flag.String("validate-results", "", "Check generated results against the spec: off, log, or fail (overrides config)")
 	flag.Parse()
 
 	// Load config
@@ -43,9 +85,263 @@ func main() {
 	if *storageDir != "" {
 		cfg.Storage.Dir = *storageDir
 	}
+	if *filePathTTL != 0 {
+		cfg.Storage.FilePathTTL = *filePathTTL
+	}
 	if *fakerSeed != 0 {
 		cfg.Server.FakerSeed = *fakerSeed
 	}
+	if *fakerOptionalFields != "" {
+		cfg.Server.FakerOptionalFields = *fakerOptionalFields
+	}
+	if *deterministic {
+		cfg.Server.Deterministic = true
+	}
+	if *fakerLocales != "" {
+		cfg.Server.FakerLocales = strings.Split(*fakerLocales, ",")
+	}
+	if *otlpEndpoint != "" {
+		cfg.Server.OTLPEndpoint = *otlpEndpoint
+	}
+	if cfg.Server.OTLPEndpoint == "" {
+		cfg.Server.OTLPEndpoint = otlpEndpointFromEnv()
+	}
+	if *controlToken != "" {
+		cfg.Server.ControlToken = *controlToken
+	}
+	if *corsOrigins != "" {
+		cfg.Server.CORSOrigins = strings.Split(*corsOrigins, ",")
+	}
+	if *recordFile != "" {
+		cfg.Server.RecordFile = *recordFile
+	}
+	if *apiVersion != "" {
+		cfg.Server.APIVersion = *apiVersion
+	}
+	if *enforceRetryAfter {
+		cfg.Server.EnforceRetryAfter = true
+	}
+	if *memoryPolicy != "" {
+		cfg.Memory.Policy = *memoryPolicy
+	}
+	if *validateResults != "" {
+		cfg.Server.ValidateResults = *validateResults
+	}
+	resultValidation, err := server.ParseResultValidation(cfg.Server.ValidateResults)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	optionalFields, err := faker.ParseDensity(cfg.Server.FakerOptionalFields)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	if err := faker.CheckLocales(cfg.Server.FakerLocales); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+	datasets := make(map[string]faker.Dataset, len(cfg.Server.FakerDatasets))
+	for name, d := range cfg.Server.FakerDatasets {
+		datasets[name] = faker.Dataset{Values: d.Values, Extend: d.Extend}
+	}
+	if err := faker.CheckDatasets(datasets); err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	limits, err := guard.ParseLimits(cfg.Memory.Limits)
+	if err == nil && *memoryLimits != "" {
+		var overrides map[guard.Component]int64
+		overrides, err = guard.ParseLimitList(*memoryLimits)
+		for c, n := range overrides {
+			limits[c] = n
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid memory limits: %v\n", err)
+		os.Exit(1)
+	}
+	policy, err := guard.ParsePolicy(cfg.Memory.Policy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+
+	personas := make([]persona.Persona, 0, len(cfg.Personas))
+	for _, pc := range cfg.Personas {
+		p := persona.Persona{ChatID: pc.ChatID, User: pc.User}
+		for _, rc := range pc.Rules {
+			p.Rules = append(p.Rules, persona.Rule{
+				Action: rc.Action,
+				Text:   rc.Text,
+				Button: rc.Button,
+				Every:  rc.Every,
+			})
+		}
+		if err := p.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid persona for chat %q: %v\n", pc.ChatID, err)
+			os.Exit(1)
+		}
+		personas = append(personas, p)
+	}
+
+	for _, sc := range cfg.Scenarios {
+		if sc.Script == "" {
+			continue
+		}
+		if sc.Response.ErrorCode > 0 {
+			fmt.Fprintf(os.Stderr, "invalid scenario for %s: script can't be combined with response\n", sc.Method)
+			os.Exit(1)
+		}
+		if err := script.Compile(sc.Script); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid script for %s: %v\n", sc.Method, err)
+			os.Exit(1)
+		}
+	}
+
+	var compatCfg *compat.Config
+	if c := cfg.Compat; c != nil {
+		compatCfg = &compat.Config{
+			OmitProbability:  c.OmitProbability,
+			ExtraProbability: c.ExtraProbability,
+			Omit:             c.Omit,
+			Methods:          c.Methods,
+			Seed:             c.Seed,
+		}
+		if err := compatCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid compat config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var adversarialCfg *adversarial.Config
+	if c := cfg.Adversarial; c != nil {
+		adversarialCfg = &adversarial.Config{
+			Probability: c.Probability,
+			Kinds:       c.Kinds,
+			Depth:       c.Depth,
+			Methods:     c.Methods,
+			Seed:        c.Seed,
+		}
+		if err := adversarialCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid adversarial config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var chaosCfg *chaos.Config
+	if c := cfg.Chaos; c != nil {
+		chaosCfg = &chaos.Config{Seed: c.Seed}
+		for _, fc := range c.Failures {
+			chaosCfg.Failures = append(chaosCfg.Failures, chaos.Failure{
+				Probability:   fc.Probability,
+				ErrorCode:     fc.ErrorCode,
+				Description:   fc.Description,
+				RetryAfterMin: fc.RetryAfterMin,
+				RetryAfterMax: fc.RetryAfterMax,
+				Methods:       fc.Methods,
+			})
+		}
+		if err := chaosCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid chaos config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var floodCfg *floodlimit.Config
+	if c := cfg.FloodLimits; c != nil {
+		floodCfg = &floodlimit.Config{
+			PerSecond:         c.PerSecond,
+			PerChatPerSecond:  c.PerChatPerSecond,
+			PerGroupPerMinute: c.PerGroupPerMinute,
+		}
+		if err := floodCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid flood_limits config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	var latencyCfg *latency.Config
+	if c := cfg.Latency; c != nil {
+		ms := func(d time.Duration) int { return int(d / time.Millisecond) }
+		percentiles := func(p config.LatencyProfileConfig) latency.Percentiles {
+			return latency.Percentiles{P50: ms(p.P50), P90: ms(p.P90), P99: ms(p.P99)}
+		}
+		latencyCfg = &latency.Config{
+			FixedMs:     ms(c.Fixed),
+			JitterMinMs: ms(c.JitterMin),
+			JitterMaxMs: ms(c.JitterMax),
+			Seed:        c.Seed,
+		}
+		if c.Profile != nil {
+			p := percentiles(*c.Profile)
+			latencyCfg.Profile = &p
+		}
+		if len(c.Methods) > 0 {
+			latencyCfg.Methods = make(map[string]latency.Percentiles, len(c.Methods))
+			for method, p := range c.Methods {
+				latencyCfg.Methods[method] = percentiles(p)
+			}
+		}
+		if err := latencyCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid latency config: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	callHooks := make([]hooks.Hook, 0, len(cfg.Hooks))
+	for _, hc := range cfg.Hooks {
+		hookCfg := hooks.HTTPConfig{
+			Name:    hc.Name,
+			URL:     hc.URL,
+			Methods: hc.Methods,
+			Phases:  hc.Phases,
+			Timeout: hc.Timeout,
+		}
+		if err := hookCfg.Validate(); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid hook %q: %v\n", hc.URL, err)
+			os.Exit(1)
+		}
+		callHooks = append(callHooks, hooks.NewHTTP(hookCfg))
+	}
+
+	for i, chat := range cfg.Chats {
+		if _, err := chats.NewStore().Seed(chat); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid chat %d: %v\n", i, err)
+			os.Exit(1)
+		}
+	}
+
+	for i, user := range cfg.Users {
+		if _, err := users.NewStore().Seed(user); err != nil {
+			fmt.Fprintf(os.Stderr, "invalid user %d: %v\n", i, err)
+			os.Exit(1)
+		}
+	}
+
+	for i, tc := range cfg.Traffic {
+		if tc.Every <= 0 || len(tc.Update) == 0 {
+			fmt.Fprintf(os.Stderr, "invalid traffic %d: every and update are required\n", i)
+			os.Exit(1)
+		}
+	}
+
+	version, err := apiversion.ParseSupported(cfg.Server.APIVersion)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid api_version: %v\n", err)
+		os.Exit(1)
+	}
+
+	var journal *inspector.Journal
+	if cfg.Server.RecordFile != "" {
+		journal, err = inspector.OpenJournal(cfg.Server.RecordFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to open record file: %v\n", err)
+			os.Exit(1)
+		}
+		defer journal.Close()
+	}
 
 	srv := server.New(server.Config{
 		Port:       cfg.Server.Port,
@@ -54,6 +350,36 @@ func main() {
 		Tokens:     cfg.Tokens,
 		Scenarios:  cfg.Scenarios,
 		StorageDir: cfg.Storage.Dir,
+
+		FilePathTTL:  cfg.Storage.FilePathTTL,
+		ControlToken: cfg.Server.ControlToken,
+		OTLPEndpoint: cfg.Server.OTLPEndpoint,
+		MemoryLimits: limits,
+		MemoryPolicy: policy,
+		CORSOrigins:  cfg.Server.CORSOrigins,
+		Journal:      journal,
+		Personas:     personas,
+		BotGroups:    cfg.BotGroups,
+		Compat:       compatCfg,
+		Adversarial:  adversarialCfg,
+		Chaos:        chaosCfg,
+		FloodLimits:  floodCfg,
+		Latency:      latencyCfg,
+		APIVersion:   version,
+		Hooks:        callHooks,
+		Chats:        cfg.Chats,
+		Users:        cfg.Users,
+		Updates:      cfg.Updates,
+		Traffic:      cfg.Traffic,
+
+		EnforceRetryAfter:    cfg.Server.EnforceRetryAfter,
+		CallbackQueryTimeout: cfg.Server.CallbackQueryTimeout,
+		MessageDeleteWindow:  cfg.Server.MessageDeleteWindow,
+		ValidateResults:      resultValidation,
+		FakerOptionalFields:  optionalFields,
+		FakerLocales:         cfg.Server.FakerLocales,
+		FakerDatasets:        datasets,
+		Deterministic:        cfg.Server.Deterministic,
 	})
 
 	// Handle graceful shutdown
@@ -62,11 +388,25 @@ func main() {
 		signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 		<-sigCh
 		fmt.Println("\nShutting down...")
-		os.Exit(0)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		srv.Shutdown(ctx)
 	}()
 
-	if err := srv.Start(); err != nil {
+	if err := srv.Start(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		fmt.Fprintf(os.Stderr, "server error: %v\n", err)
 		os.Exit(1)
 	}
+
+	// Serve returns as soon as shutdown begins; wait for in-flight requests
+	<-srv.Done()
+}
+
+// otlpEndpointFromEnv returns the trace collector configured through the
+// standard OpenTelemetry environment variables.
+func otlpEndpointFromEnv() string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
 }
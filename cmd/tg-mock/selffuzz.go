@@ -0,0 +1,78 @@
+// cmd/tg-mock/selffuzz.go
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/watzon/tg-mock/internal/selffuzz"
+)
+
+// runSelfFuzz implements the self-fuzz subcommand. It returns the process
+// exit code: 0 if no problems were found, 1 if there were findings, and 2
+// if the run could not be carried out.
+func runSelfFuzz(args []string) int {
+	fs := flag.NewFlagSet("self-fuzz", flag.ExitOnError)
+	target := fs.String("target", "http://localhost:8081", "Base URL of the tg-mock instance to fuzz")
+	token := fs.String("token", selffuzz.DefaultToken, "Bot token to send requests with")
+	sessionName := fs.String("session", selffuzz.DefaultSession, "Session to send requests in (deleted afterwards)")
+	methods := fs.String("methods", "", "Comma-separated methods to fuzz (default all)")
+	iterations := fs.Int("iterations", 10, "Random permutations per method")
+	seed := fs.Int64("seed", 1, "Seed for the random permutations")
+	controlToken := fs.String("control-token", "", "Control API token of the target, if it requires one")
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	fs.Parse(args)
+
+	cfg := selffuzz.Config{
+		BaseURL:      *target,
+		Token:        *token,
+		Session:      *sessionName,
+		Iterations:   *iterations,
+		Seed:         *seed,
+		ControlToken: *controlToken,
+	}
+	if *methods != "" {
+		cfg.Methods = strings.Split(*methods, ",")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := selffuzz.Run(ctx, cfg)
+	if err != nil && report == nil {
+		fmt.Fprintf(os.Stderr, "self-fuzz: %v\n", err)
+		return 2
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		for _, f := range report.Findings {
+			params, _ := json.Marshal(f.Params)
+			fmt.Printf("%s [%s, %s]: %s\n", f.Method, f.Case, f.Encoding, f.Problem)
+			fmt.Printf("  params: %s\n", params)
+			if f.StatusCode != 0 {
+				fmt.Printf("  response (%d): %s\n", f.StatusCode, f.Body)
+			}
+		}
+		fmt.Printf("%d methods, %d requests, %d findings in %.1fs\n",
+			report.Methods, report.Requests, len(report.Findings), report.Duration)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "self-fuzz: %v\n", err)
+		return 2
+	}
+	if len(report.Findings) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -26,8 +26,8 @@ func NewAffiliateInfo() *AffiliateInfo {
 // NewAnimation creates a fixture Animation
 func NewAnimation() *Animation {
 	return &Animation{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Width: 1,
 		Height: 1,
 		Duration: 1,
@@ -37,16 +37,15 @@ func NewAnimation() *Animation {
 // NewAudio creates a fixture Audio
 func NewAudio() *Audio {
 	return &Audio{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Duration: 1,
 	}
 }
 
 // NewBackgroundFill creates a fixture BackgroundFill
-func NewBackgroundFill() *BackgroundFill {
-	return &BackgroundFill{
-	}
+func NewBackgroundFill() BackgroundFill {
+	return NewBackgroundFillSolid()
 }
 
 // NewBackgroundFillFreeformGradient creates a fixture BackgroundFillFreeformGradient
@@ -76,9 +75,8 @@ func NewBackgroundFillSolid() *BackgroundFillSolid {
 }
 
 // NewBackgroundType creates a fixture BackgroundType
-func NewBackgroundType() *BackgroundType {
-	return &BackgroundType{
-	}
+func NewBackgroundType() BackgroundType {
+	return NewBackgroundTypeFill()
 }
 
 // NewBackgroundTypeChatTheme creates a fixture BackgroundTypeChatTheme
@@ -93,7 +91,7 @@ func NewBackgroundTypeChatTheme() *BackgroundTypeChatTheme {
 func NewBackgroundTypeFill() *BackgroundTypeFill {
 	return &BackgroundTypeFill{
 		Type: "private",
-		Fill: *NewBackgroundFill(),
+		Fill: NewBackgroundFill(),
 		DarkThemeDimming: 1,
 	}
 }
@@ -103,7 +101,7 @@ func NewBackgroundTypePattern() *BackgroundTypePattern {
 	return &BackgroundTypePattern{
 		Type: "private",
 		Document: *NewDocument(),
-		Fill: *NewBackgroundFill(),
+		Fill: NewBackgroundFill(),
 		Intensity: 1,
 	}
 }
@@ -134,9 +132,8 @@ func NewBotCommand() *BotCommand {
 }
 
 // NewBotCommandScope creates a fixture BotCommandScope
-func NewBotCommandScope() *BotCommandScope {
-	return &BotCommandScope{
-	}
+func NewBotCommandScope() BotCommandScope {
+	return NewBotCommandScopeDefault()
 }
 
 // NewBotCommandScopeAllChatAdministrators creates a fixture BotCommandScopeAllChatAdministrators
@@ -164,7 +161,7 @@ func NewBotCommandScopeAllPrivateChats() *BotCommandScopeAllPrivateChats {
 func NewBotCommandScopeChat() *BotCommandScopeChat {
 	return &BotCommandScopeChat{
 		Type: "private",
-		ChatId: 1,
+		ChatID: 1,
 	}
 }
 
@@ -172,7 +169,7 @@ func NewBotCommandScopeChat() *BotCommandScopeChat {
 func NewBotCommandScopeChatAdministrators() *BotCommandScopeChatAdministrators {
 	return &BotCommandScopeChatAdministrators{
 		Type: "private",
-		ChatId: 1,
+		ChatID: 1,
 	}
 }
 
@@ -180,8 +177,8 @@ func NewBotCommandScopeChatAdministrators() *BotCommandScopeChatAdministrators {
 func NewBotCommandScopeChatMember() *BotCommandScopeChatMember {
 	return &BotCommandScopeChatMember{
 		Type: "private",
-		ChatId: 1,
-		UserId: 1,
+		ChatID: 1,
+		UserID: 1,
 	}
 }
 
@@ -222,9 +219,9 @@ func NewBusinessBotRights() *BusinessBotRights {
 // NewBusinessConnection creates a fixture BusinessConnection
 func NewBusinessConnection() *BusinessConnection {
 	return &BusinessConnection{
-		Id: "id",
+		ID: "id",
 		User: *NewUser(),
-		UserChatId: 1,
+		UserChatID: 1,
 		Date: int64(time.Now().Unix()),
 		IsEnabled: true,
 	}
@@ -246,7 +243,7 @@ func NewBusinessLocation() *BusinessLocation {
 // NewBusinessMessagesDeleted creates a fixture BusinessMessagesDeleted
 func NewBusinessMessagesDeleted() *BusinessMessagesDeleted {
 	return &BusinessMessagesDeleted{
-		BusinessConnectionId: "business_connection_id",
+		BusinessConnectionID: "business_connection_id",
 		Chat: *NewChat(),
 		MessageIds: nil,
 	}
@@ -277,7 +274,7 @@ func NewCallbackGame() *CallbackGame {
 // NewCallbackQuery creates a fixture CallbackQuery
 func NewCallbackQuery() *CallbackQuery {
 	return &CallbackQuery{
-		Id: "id",
+		ID: "id",
 		From: *NewUser(),
 		ChatInstance: "chat_instance",
 	}
@@ -286,7 +283,7 @@ func NewCallbackQuery() *CallbackQuery {
 // NewChat creates a fixture Chat
 func NewChat() *Chat {
 	return &Chat{
-		Id: 1,
+		ID: 1,
 		Type: "private",
 	}
 }
@@ -311,17 +308,17 @@ func NewChatAdministratorRights() *ChatAdministratorRights {
 // NewChatBackground creates a fixture ChatBackground
 func NewChatBackground() *ChatBackground {
 	return &ChatBackground{
-		Type: *NewBackgroundType(),
+		Type: NewBackgroundType(),
 	}
 }
 
 // NewChatBoost creates a fixture ChatBoost
 func NewChatBoost() *ChatBoost {
 	return &ChatBoost{
-		BoostId: "boost_id",
+		BoostID: "boost_id",
 		AddDate: 1,
 		ExpirationDate: 1,
-		Source: *NewChatBoostSource(),
+		Source: NewChatBoostSource(),
 	}
 }
 
@@ -336,16 +333,15 @@ func NewChatBoostAdded() *ChatBoostAdded {
 func NewChatBoostRemoved() *ChatBoostRemoved {
 	return &ChatBoostRemoved{
 		Chat: *NewChat(),
-		BoostId: "boost_id",
+		BoostID: "boost_id",
 		RemoveDate: 1,
-		Source: *NewChatBoostSource(),
+		Source: NewChatBoostSource(),
 	}
 }
 
 // NewChatBoostSource creates a fixture ChatBoostSource
-func NewChatBoostSource() *ChatBoostSource {
-	return &ChatBoostSource{
-	}
+func NewChatBoostSource() ChatBoostSource {
+	return NewChatBoostSourcePremium()
 }
 
 // NewChatBoostSourceGiftCode creates a fixture ChatBoostSourceGiftCode
@@ -360,7 +356,7 @@ func NewChatBoostSourceGiftCode() *ChatBoostSourceGiftCode {
 func NewChatBoostSourceGiveaway() *ChatBoostSourceGiveaway {
 	return &ChatBoostSourceGiveaway{
 		Source: "source",
-		GiveawayMessageId: 1,
+		GiveawayMessageID: 1,
 	}
 }
 
@@ -383,9 +379,9 @@ func NewChatBoostUpdated() *ChatBoostUpdated {
 // NewChatFullInfo creates a fixture ChatFullInfo
 func NewChatFullInfo() *ChatFullInfo {
 	return &ChatFullInfo{
-		Id: 1,
+		ID: 1,
 		Type: "private",
-		AccentColorId: 1,
+		AccentColorID: 1,
 		MaxReactionCount: 1,
 		AcceptedGiftTypes: *NewAcceptedGiftTypes(),
 	}
@@ -407,7 +403,7 @@ func NewChatJoinRequest() *ChatJoinRequest {
 	return &ChatJoinRequest{
 		Chat: *NewChat(),
 		From: *NewUser(),
-		UserChatId: 1,
+		UserChatID: 1,
 		Date: int64(time.Now().Unix()),
 	}
 }
@@ -421,9 +417,8 @@ func NewChatLocation() *ChatLocation {
 }
 
 // NewChatMember creates a fixture ChatMember
-func NewChatMember() *ChatMember {
-	return &ChatMember{
-	}
+func NewChatMember() ChatMember {
+	return NewChatMemberOwner()
 }
 
 // NewChatMemberAdministrator creates a fixture ChatMemberAdministrator
@@ -510,8 +505,8 @@ func NewChatMemberUpdated() *ChatMemberUpdated {
 		Chat: *NewChat(),
 		From: *NewUser(),
 		Date: int64(time.Now().Unix()),
-		OldChatMember: *NewChatMember(),
-		NewChatMember: *NewChatMember(),
+		OldChatMember: NewChatMember(),
+		NewChatMember: NewChatMember(),
 	}
 }
 
@@ -524,18 +519,18 @@ func NewChatPermissions() *ChatPermissions {
 // NewChatPhoto creates a fixture ChatPhoto
 func NewChatPhoto() *ChatPhoto {
 	return &ChatPhoto{
-		SmallFileId: "small_file_id",
-		SmallFileUniqueId: "small_file_unique_id",
-		BigFileId: "big_file_id",
-		BigFileUniqueId: "big_file_unique_id",
+		SmallFileID: "small_file_id",
+		SmallFileUniqueID: "small_file_unique_id",
+		BigFileID: "big_file_id",
+		BigFileUniqueID: "big_file_unique_id",
 	}
 }
 
 // NewChatShared creates a fixture ChatShared
 func NewChatShared() *ChatShared {
 	return &ChatShared{
-		RequestId: 1,
-		ChatId: 1,
+		RequestID: 1,
+		ChatID: 1,
 	}
 }
 
@@ -550,7 +545,7 @@ func NewChecklist() *Checklist {
 // NewChecklistTask creates a fixture ChecklistTask
 func NewChecklistTask() *ChecklistTask {
 	return &ChecklistTask{
-		Id: 1,
+		ID: 1,
 		Text: "Hello",
 	}
 }
@@ -571,7 +566,7 @@ func NewChecklistTasksDone() *ChecklistTasksDone {
 // NewChosenInlineResult creates a fixture ChosenInlineResult
 func NewChosenInlineResult() *ChosenInlineResult {
 	return &ChosenInlineResult{
-		ResultId: "result_id",
+		ResultID: "result_id",
 		From: *NewUser(),
 		Query: "query",
 	}
@@ -610,15 +605,15 @@ func NewDirectMessagePriceChanged() *DirectMessagePriceChanged {
 // NewDirectMessagesTopic creates a fixture DirectMessagesTopic
 func NewDirectMessagesTopic() *DirectMessagesTopic {
 	return &DirectMessagesTopic{
-		TopicId: 1,
+		TopicID: 1,
 	}
 }
 
 // NewDocument creates a fixture Document
 func NewDocument() *Document {
 	return &Document{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 	}
 }
 
@@ -642,15 +637,15 @@ func NewEncryptedPassportElement() *EncryptedPassportElement {
 // NewExternalReplyInfo creates a fixture ExternalReplyInfo
 func NewExternalReplyInfo() *ExternalReplyInfo {
 	return &ExternalReplyInfo{
-		Origin: *NewMessageOrigin(),
+		Origin: NewMessageOrigin(),
 	}
 }
 
 // NewFile creates a fixture File
 func NewFile() *File {
 	return &File{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 	}
 }
 
@@ -664,7 +659,7 @@ func NewForceReply() *ForceReply {
 // NewForumTopic creates a fixture ForumTopic
 func NewForumTopic() *ForumTopic {
 	return &ForumTopic{
-		MessageThreadId: 1,
+		MessageThreadID: 1,
 		Name: "name",
 		IconColor: 1,
 	}
@@ -729,7 +724,7 @@ func NewGeneralForumTopicUnhidden() *GeneralForumTopicUnhidden {
 // NewGift creates a fixture Gift
 func NewGift() *Gift {
 	return &Gift{
-		Id: "id",
+		ID: "id",
 		Sticker: *NewSticker(),
 		StarCount: 1,
 	}
@@ -775,7 +770,7 @@ func NewGiveawayCreated() *GiveawayCreated {
 func NewGiveawayWinners() *GiveawayWinners {
 	return &GiveawayWinners{
 		Chat: *NewChat(),
-		GiveawayMessageId: 1,
+		GiveawayMessageID: 1,
 		WinnersSelectionDate: 1,
 		WinnerCount: 1,
 		Winners: nil,
@@ -786,7 +781,7 @@ func NewGiveawayWinners() *GiveawayWinners {
 func NewInaccessibleMessage() *InaccessibleMessage {
 	return &InaccessibleMessage{
 		Chat: *NewChat(),
-		MessageId: 1,
+		MessageID: 1,
 		Date: int64(time.Now().Unix()),
 	}
 }
@@ -808,7 +803,7 @@ func NewInlineKeyboardMarkup() *InlineKeyboardMarkup {
 // NewInlineQuery creates a fixture InlineQuery
 func NewInlineQuery() *InlineQuery {
 	return &InlineQuery{
-		Id: "id",
+		ID: "id",
 		From: *NewUser(),
 		Query: "query",
 		Offset: "offset",
@@ -816,18 +811,17 @@ func NewInlineQuery() *InlineQuery {
 }
 
 // NewInlineQueryResult creates a fixture InlineQueryResult
-func NewInlineQueryResult() *InlineQueryResult {
-	return &InlineQueryResult{
-	}
+func NewInlineQueryResult() InlineQueryResult {
+	return NewInlineQueryResultCachedAudio()
 }
 
 // NewInlineQueryResultArticle creates a fixture InlineQueryResultArticle
 func NewInlineQueryResultArticle() *InlineQueryResultArticle {
 	return &InlineQueryResultArticle{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		Title: "Test Chat",
-		InputMessageContent: *NewInputMessageContent(),
+		InputMessageContent: NewInputMessageContent(),
 	}
 }
 
@@ -835,8 +829,8 @@ func NewInlineQueryResultArticle() *InlineQueryResultArticle {
 func NewInlineQueryResultAudio() *InlineQueryResultAudio {
 	return &InlineQueryResultAudio{
 		Type: "private",
-		Id: "id",
-		AudioUrl: "audio_url",
+		ID: "id",
+		AudioURL: "audio_url",
 		Title: "Test Chat",
 	}
 }
@@ -845,8 +839,8 @@ func NewInlineQueryResultAudio() *InlineQueryResultAudio {
 func NewInlineQueryResultCachedAudio() *InlineQueryResultCachedAudio {
 	return &InlineQueryResultCachedAudio{
 		Type: "private",
-		Id: "id",
-		AudioFileId: "audio_file_id",
+		ID: "id",
+		AudioFileID: "audio_file_id",
 	}
 }
 
@@ -854,9 +848,9 @@ func NewInlineQueryResultCachedAudio() *InlineQueryResultCachedAudio {
 func NewInlineQueryResultCachedDocument() *InlineQueryResultCachedDocument {
 	return &InlineQueryResultCachedDocument{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		Title: "Test Chat",
-		DocumentFileId: "document_file_id",
+		DocumentFileID: "document_file_id",
 	}
 }
 
@@ -864,8 +858,8 @@ func NewInlineQueryResultCachedDocument() *InlineQueryResultCachedDocument {
 func NewInlineQueryResultCachedGif() *InlineQueryResultCachedGif {
 	return &InlineQueryResultCachedGif{
 		Type: "private",
-		Id: "id",
-		GifFileId: "gif_file_id",
+		ID: "id",
+		GifFileID: "gif_file_id",
 	}
 }
 
@@ -873,8 +867,8 @@ func NewInlineQueryResultCachedGif() *InlineQueryResultCachedGif {
 func NewInlineQueryResultCachedMpeg4Gif() *InlineQueryResultCachedMpeg4Gif {
 	return &InlineQueryResultCachedMpeg4Gif{
 		Type: "private",
-		Id: "id",
-		Mpeg4FileId: "mpeg4_file_id",
+		ID: "id",
+		Mpeg4FileID: "mpeg4_file_id",
 	}
 }
 
@@ -882,8 +876,8 @@ func NewInlineQueryResultCachedMpeg4Gif() *InlineQueryResultCachedMpeg4Gif {
 func NewInlineQueryResultCachedPhoto() *InlineQueryResultCachedPhoto {
 	return &InlineQueryResultCachedPhoto{
 		Type: "private",
-		Id: "id",
-		PhotoFileId: "photo_file_id",
+		ID: "id",
+		PhotoFileID: "photo_file_id",
 	}
 }
 
@@ -891,8 +885,8 @@ func NewInlineQueryResultCachedPhoto() *InlineQueryResultCachedPhoto {
 func NewInlineQueryResultCachedSticker() *InlineQueryResultCachedSticker {
 	return &InlineQueryResultCachedSticker{
 		Type: "private",
-		Id: "id",
-		StickerFileId: "sticker_file_id",
+		ID: "id",
+		StickerFileID: "sticker_file_id",
 	}
 }
 
@@ -900,8 +894,8 @@ func NewInlineQueryResultCachedSticker() *InlineQueryResultCachedSticker {
 func NewInlineQueryResultCachedVideo() *InlineQueryResultCachedVideo {
 	return &InlineQueryResultCachedVideo{
 		Type: "private",
-		Id: "id",
-		VideoFileId: "video_file_id",
+		ID: "id",
+		VideoFileID: "video_file_id",
 		Title: "Test Chat",
 	}
 }
@@ -910,8 +904,8 @@ func NewInlineQueryResultCachedVideo() *InlineQueryResultCachedVideo {
 func NewInlineQueryResultCachedVoice() *InlineQueryResultCachedVoice {
 	return &InlineQueryResultCachedVoice{
 		Type: "private",
-		Id: "id",
-		VoiceFileId: "voice_file_id",
+		ID: "id",
+		VoiceFileID: "voice_file_id",
 		Title: "Test Chat",
 	}
 }
@@ -920,7 +914,7 @@ func NewInlineQueryResultCachedVoice() *InlineQueryResultCachedVoice {
 func NewInlineQueryResultContact() *InlineQueryResultContact {
 	return &InlineQueryResultContact{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		PhoneNumber: "phone_number",
 		FirstName: "Test",
 	}
@@ -930,9 +924,9 @@ func NewInlineQueryResultContact() *InlineQueryResultContact {
 func NewInlineQueryResultDocument() *InlineQueryResultDocument {
 	return &InlineQueryResultDocument{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		Title: "Test Chat",
-		DocumentUrl: "document_url",
+		DocumentURL: "document_url",
 		MimeType: "mime_type",
 	}
 }
@@ -941,7 +935,7 @@ func NewInlineQueryResultDocument() *InlineQueryResultDocument {
 func NewInlineQueryResultGame() *InlineQueryResultGame {
 	return &InlineQueryResultGame{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		GameShortName: "game_short_name",
 	}
 }
@@ -950,9 +944,9 @@ func NewInlineQueryResultGame() *InlineQueryResultGame {
 func NewInlineQueryResultGif() *InlineQueryResultGif {
 	return &InlineQueryResultGif{
 		Type: "private",
-		Id: "id",
-		GifUrl: "gif_url",
-		ThumbnailUrl: "thumbnail_url",
+		ID: "id",
+		GifURL: "gif_url",
+		ThumbnailURL: "thumbnail_url",
 	}
 }
 
@@ -960,7 +954,7 @@ func NewInlineQueryResultGif() *InlineQueryResultGif {
 func NewInlineQueryResultLocation() *InlineQueryResultLocation {
 	return &InlineQueryResultLocation{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		Latitude: 1.0,
 		Longitude: 1.0,
 		Title: "Test Chat",
@@ -971,9 +965,9 @@ func NewInlineQueryResultLocation() *InlineQueryResultLocation {
 func NewInlineQueryResultMpeg4Gif() *InlineQueryResultMpeg4Gif {
 	return &InlineQueryResultMpeg4Gif{
 		Type: "private",
-		Id: "id",
-		Mpeg4Url: "mpeg4_url",
-		ThumbnailUrl: "thumbnail_url",
+		ID: "id",
+		Mpeg4URL: "mpeg4_url",
+		ThumbnailURL: "thumbnail_url",
 	}
 }
 
@@ -981,9 +975,9 @@ func NewInlineQueryResultMpeg4Gif() *InlineQueryResultMpeg4Gif {
 func NewInlineQueryResultPhoto() *InlineQueryResultPhoto {
 	return &InlineQueryResultPhoto{
 		Type: "private",
-		Id: "id",
-		PhotoUrl: "photo_url",
-		ThumbnailUrl: "thumbnail_url",
+		ID: "id",
+		PhotoURL: "photo_url",
+		ThumbnailURL: "thumbnail_url",
 	}
 }
 
@@ -991,7 +985,7 @@ func NewInlineQueryResultPhoto() *InlineQueryResultPhoto {
 func NewInlineQueryResultVenue() *InlineQueryResultVenue {
 	return &InlineQueryResultVenue{
 		Type: "private",
-		Id: "id",
+		ID: "id",
 		Latitude: 1.0,
 		Longitude: 1.0,
 		Title: "Test Chat",
@@ -1003,10 +997,10 @@ func NewInlineQueryResultVenue() *InlineQueryResultVenue {
 func NewInlineQueryResultVideo() *InlineQueryResultVideo {
 	return &InlineQueryResultVideo{
 		Type: "private",
-		Id: "id",
-		VideoUrl: "video_url",
+		ID: "id",
+		VideoURL: "video_url",
 		MimeType: "mime_type",
-		ThumbnailUrl: "thumbnail_url",
+		ThumbnailURL: "thumbnail_url",
 		Title: "Test Chat",
 	}
 }
@@ -1015,8 +1009,8 @@ func NewInlineQueryResultVideo() *InlineQueryResultVideo {
 func NewInlineQueryResultVoice() *InlineQueryResultVoice {
 	return &InlineQueryResultVoice{
 		Type: "private",
-		Id: "id",
-		VoiceUrl: "voice_url",
+		ID: "id",
+		VoiceURL: "voice_url",
 		Title: "Test Chat",
 	}
 }
@@ -1039,7 +1033,7 @@ func NewInputChecklist() *InputChecklist {
 // NewInputChecklistTask creates a fixture InputChecklistTask
 func NewInputChecklistTask() *InputChecklistTask {
 	return &InputChecklistTask{
-		Id: 1,
+		ID: 1,
 		Text: "Hello",
 	}
 }
@@ -1078,9 +1072,8 @@ func NewInputLocationMessageContent() *InputLocationMessageContent {
 }
 
 // NewInputMedia creates a fixture InputMedia
-func NewInputMedia() *InputMedia {
-	return &InputMedia{
-	}
+func NewInputMedia() InputMedia {
+	return NewInputMediaAnimation()
 }
 
 // NewInputMediaAnimation creates a fixture InputMediaAnimation
@@ -1124,15 +1117,13 @@ func NewInputMediaVideo() *InputMediaVideo {
 }
 
 // NewInputMessageContent creates a fixture InputMessageContent
-func NewInputMessageContent() *InputMessageContent {
-	return &InputMessageContent{
-	}
+func NewInputMessageContent() InputMessageContent {
+	return NewInputTextMessageContent()
 }
 
 // NewInputPaidMedia creates a fixture InputPaidMedia
-func NewInputPaidMedia() *InputPaidMedia {
-	return &InputPaidMedia{
-	}
+func NewInputPaidMedia() InputPaidMedia {
+	return NewInputPaidMediaPhoto()
 }
 
 // NewInputPaidMediaPhoto creates a fixture InputPaidMediaPhoto
@@ -1159,9 +1150,8 @@ func NewInputPollOption() *InputPollOption {
 }
 
 // NewInputProfilePhoto creates a fixture InputProfilePhoto
-func NewInputProfilePhoto() *InputProfilePhoto {
-	return &InputProfilePhoto{
-	}
+func NewInputProfilePhoto() InputProfilePhoto {
+	return NewInputProfilePhotoStatic()
 }
 
 // NewInputProfilePhotoAnimated creates a fixture InputProfilePhotoAnimated
@@ -1190,9 +1180,8 @@ func NewInputSticker() *InputSticker {
 }
 
 // NewInputStoryContent creates a fixture InputStoryContent
-func NewInputStoryContent() *InputStoryContent {
-	return &InputStoryContent{
-	}
+func NewInputStoryContent() InputStoryContent {
+	return NewInputStoryContentPhoto()
 }
 
 // NewInputStoryContentPhoto creates a fixture InputStoryContentPhoto
@@ -1255,7 +1244,7 @@ func NewKeyboardButtonPollType() *KeyboardButtonPollType {
 // NewKeyboardButtonRequestChat creates a fixture KeyboardButtonRequestChat
 func NewKeyboardButtonRequestChat() *KeyboardButtonRequestChat {
 	return &KeyboardButtonRequestChat{
-		RequestId: 1,
+		RequestID: 1,
 		ChatIsChannel: true,
 	}
 }
@@ -1263,7 +1252,7 @@ func NewKeyboardButtonRequestChat() *KeyboardButtonRequestChat {
 // NewKeyboardButtonRequestUsers creates a fixture KeyboardButtonRequestUsers
 func NewKeyboardButtonRequestUsers() *KeyboardButtonRequestUsers {
 	return &KeyboardButtonRequestUsers{
-		RequestId: 1,
+		RequestID: 1,
 	}
 }
 
@@ -1299,7 +1288,7 @@ func NewLocationAddress() *LocationAddress {
 // NewLoginUrl creates a fixture LoginUrl
 func NewLoginUrl() *LoginUrl {
 	return &LoginUrl{
-		Url: "url",
+		URL: "url",
 	}
 }
 
@@ -1314,15 +1303,13 @@ func NewMaskPosition() *MaskPosition {
 }
 
 // NewMaybeInaccessibleMessage creates a fixture MaybeInaccessibleMessage
-func NewMaybeInaccessibleMessage() *MaybeInaccessibleMessage {
-	return &MaybeInaccessibleMessage{
-	}
+func NewMaybeInaccessibleMessage() MaybeInaccessibleMessage {
+	return NewMessage()
 }
 
 // NewMenuButton creates a fixture MenuButton
-func NewMenuButton() *MenuButton {
-	return &MenuButton{
-	}
+func NewMenuButton() MenuButton {
+	return NewMenuButtonCommands()
 }
 
 // NewMenuButtonCommands creates a fixture MenuButtonCommands
@@ -1351,7 +1338,7 @@ func NewMenuButtonWebApp() *MenuButtonWebApp {
 // NewMessage creates a fixture Message
 func NewMessage() *Message {
 	return &Message{
-		MessageId: 1,
+		MessageID: 1,
 		Date: int64(time.Now().Unix()),
 		Chat: *NewChat(),
 	}
@@ -1376,14 +1363,13 @@ func NewMessageEntity() *MessageEntity {
 // NewMessageId creates a fixture MessageId
 func NewMessageId() *MessageId {
 	return &MessageId{
-		MessageId: 1,
+		MessageID: 1,
 	}
 }
 
 // NewMessageOrigin creates a fixture MessageOrigin
-func NewMessageOrigin() *MessageOrigin {
-	return &MessageOrigin{
-	}
+func NewMessageOrigin() MessageOrigin {
+	return NewMessageOriginUser()
 }
 
 // NewMessageOriginChannel creates a fixture MessageOriginChannel
@@ -1392,7 +1378,7 @@ func NewMessageOriginChannel() *MessageOriginChannel {
 		Type: "private",
 		Date: int64(time.Now().Unix()),
 		Chat: *NewChat(),
-		MessageId: 1,
+		MessageID: 1,
 	}
 }
 
@@ -1427,7 +1413,7 @@ func NewMessageOriginUser() *MessageOriginUser {
 func NewMessageReactionCountUpdated() *MessageReactionCountUpdated {
 	return &MessageReactionCountUpdated{
 		Chat: *NewChat(),
-		MessageId: 1,
+		MessageID: 1,
 		Date: int64(time.Now().Unix()),
 		Reactions: nil,
 	}
@@ -1437,7 +1423,7 @@ func NewMessageReactionCountUpdated() *MessageReactionCountUpdated {
 func NewMessageReactionUpdated() *MessageReactionUpdated {
 	return &MessageReactionUpdated{
 		Chat: *NewChat(),
-		MessageId: 1,
+		MessageID: 1,
 		Date: int64(time.Now().Unix()),
 		OldReaction: nil,
 		NewReaction: nil,
@@ -1451,9 +1437,8 @@ func NewOrderInfo() *OrderInfo {
 }
 
 // NewOwnedGift creates a fixture OwnedGift
-func NewOwnedGift() *OwnedGift {
-	return &OwnedGift{
-	}
+func NewOwnedGift() OwnedGift {
+	return NewOwnedGiftRegular()
 }
 
 // NewOwnedGiftRegular creates a fixture OwnedGiftRegular
@@ -1483,9 +1468,8 @@ func NewOwnedGifts() *OwnedGifts {
 }
 
 // NewPaidMedia creates a fixture PaidMedia
-func NewPaidMedia() *PaidMedia {
-	return &PaidMedia{
-	}
+func NewPaidMedia() PaidMedia {
+	return NewPaidMediaPreview()
 }
 
 // NewPaidMediaInfo creates a fixture PaidMediaInfo
@@ -1543,9 +1527,8 @@ func NewPassportData() *PassportData {
 }
 
 // NewPassportElementError creates a fixture PassportElementError
-func NewPassportElementError() *PassportElementError {
-	return &PassportElementError{
-	}
+func NewPassportElementError() PassportElementError {
+	return NewPassportElementErrorDataField()
 }
 
 // NewPassportElementErrorDataField creates a fixture PassportElementErrorDataField
@@ -1642,8 +1625,8 @@ func NewPassportElementErrorUnspecified() *PassportElementErrorUnspecified {
 // NewPassportFile creates a fixture PassportFile
 func NewPassportFile() *PassportFile {
 	return &PassportFile{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		FileSize: 1,
 		FileDate: 1,
 	}
@@ -1652,8 +1635,8 @@ func NewPassportFile() *PassportFile {
 // NewPhotoSize creates a fixture PhotoSize
 func NewPhotoSize() *PhotoSize {
 	return &PhotoSize{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Width: 1,
 		Height: 1,
 	}
@@ -1662,7 +1645,7 @@ func NewPhotoSize() *PhotoSize {
 // NewPoll creates a fixture Poll
 func NewPoll() *Poll {
 	return &Poll{
-		Id: "id",
+		ID: "id",
 		Question: "question",
 		Options: nil,
 		TotalVoterCount: 1,
@@ -1676,7 +1659,7 @@ func NewPoll() *Poll {
 // NewPollAnswer creates a fixture PollAnswer
 func NewPollAnswer() *PollAnswer {
 	return &PollAnswer{
-		PollId: "poll_id",
+		PollID: "poll_id",
 		OptionIds: nil,
 	}
 }
@@ -1692,7 +1675,7 @@ func NewPollOption() *PollOption {
 // NewPreCheckoutQuery creates a fixture PreCheckoutQuery
 func NewPreCheckoutQuery() *PreCheckoutQuery {
 	return &PreCheckoutQuery{
-		Id: "id",
+		ID: "id",
 		From: *NewUser(),
 		Currency: "currency",
 		TotalAmount: 1,
@@ -1703,7 +1686,7 @@ func NewPreCheckoutQuery() *PreCheckoutQuery {
 // NewPreparedInlineMessage creates a fixture PreparedInlineMessage
 func NewPreparedInlineMessage() *PreparedInlineMessage {
 	return &PreparedInlineMessage{
-		Id: "id",
+		ID: "id",
 		ExpirationDate: 1,
 	}
 }
@@ -1720,22 +1703,21 @@ func NewProximityAlertTriggered() *ProximityAlertTriggered {
 // NewReactionCount creates a fixture ReactionCount
 func NewReactionCount() *ReactionCount {
 	return &ReactionCount{
-		Type: *NewReactionType(),
+		Type: NewReactionType(),
 		TotalCount: 1,
 	}
 }
 
 // NewReactionType creates a fixture ReactionType
-func NewReactionType() *ReactionType {
-	return &ReactionType{
-	}
+func NewReactionType() ReactionType {
+	return NewReactionTypeEmoji()
 }
 
 // NewReactionTypeCustomEmoji creates a fixture ReactionTypeCustomEmoji
 func NewReactionTypeCustomEmoji() *ReactionTypeCustomEmoji {
 	return &ReactionTypeCustomEmoji{
 		Type: "private",
-		CustomEmojiId: "custom_emoji_id",
+		CustomEmojiID: "custom_emoji_id",
 	}
 }
 
@@ -1760,7 +1742,7 @@ func NewRefundedPayment() *RefundedPayment {
 		Currency: "currency",
 		TotalAmount: 1,
 		InvoicePayload: "invoice_payload",
-		TelegramPaymentChargeId: "telegram_payment_charge_id",
+		TelegramPaymentChargeID: "telegram_payment_charge_id",
 	}
 }
 
@@ -1781,7 +1763,7 @@ func NewReplyKeyboardRemove() *ReplyKeyboardRemove {
 // NewReplyParameters creates a fixture ReplyParameters
 func NewReplyParameters() *ReplyParameters {
 	return &ReplyParameters{
-		MessageId: 1,
+		MessageID: 1,
 	}
 }
 
@@ -1792,9 +1774,8 @@ func NewResponseParameters() *ResponseParameters {
 }
 
 // NewRevenueWithdrawalState creates a fixture RevenueWithdrawalState
-func NewRevenueWithdrawalState() *RevenueWithdrawalState {
-	return &RevenueWithdrawalState{
-	}
+func NewRevenueWithdrawalState() RevenueWithdrawalState {
+	return NewRevenueWithdrawalStatePending()
 }
 
 // NewRevenueWithdrawalStateFailed creates a fixture RevenueWithdrawalStateFailed
@@ -1816,7 +1797,7 @@ func NewRevenueWithdrawalStateSucceeded() *RevenueWithdrawalStateSucceeded {
 	return &RevenueWithdrawalStateSucceeded{
 		Type: "private",
 		Date: int64(time.Now().Unix()),
-		Url: "url",
+		URL: "url",
 	}
 }
 
@@ -1829,7 +1810,7 @@ func NewSentWebAppMessage() *SentWebAppMessage {
 // NewSharedUser creates a fixture SharedUser
 func NewSharedUser() *SharedUser {
 	return &SharedUser{
-		UserId: 1,
+		UserID: 1,
 	}
 }
 
@@ -1848,7 +1829,7 @@ func NewShippingAddress() *ShippingAddress {
 // NewShippingOption creates a fixture ShippingOption
 func NewShippingOption() *ShippingOption {
 	return &ShippingOption{
-		Id: "id",
+		ID: "id",
 		Title: "Test Chat",
 		Prices: nil,
 	}
@@ -1857,7 +1838,7 @@ func NewShippingOption() *ShippingOption {
 // NewShippingQuery creates a fixture ShippingQuery
 func NewShippingQuery() *ShippingQuery {
 	return &ShippingQuery{
-		Id: "id",
+		ID: "id",
 		From: *NewUser(),
 		InvoicePayload: "invoice_payload",
 		ShippingAddress: *NewShippingAddress(),
@@ -1874,7 +1855,7 @@ func NewStarAmount() *StarAmount {
 // NewStarTransaction creates a fixture StarTransaction
 func NewStarTransaction() *StarTransaction {
 	return &StarTransaction{
-		Id: "id",
+		ID: "id",
 		Amount: 1,
 		Date: int64(time.Now().Unix()),
 	}
@@ -1890,8 +1871,8 @@ func NewStarTransactions() *StarTransactions {
 // NewSticker creates a fixture Sticker
 func NewSticker() *Sticker {
 	return &Sticker{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Type: "private",
 		Width: 1,
 		Height: 1,
@@ -1914,7 +1895,7 @@ func NewStickerSet() *StickerSet {
 func NewStory() *Story {
 	return &Story{
 		Chat: *NewChat(),
-		Id: 1,
+		ID: 1,
 	}
 }
 
@@ -1922,7 +1903,7 @@ func NewStory() *Story {
 func NewStoryArea() *StoryArea {
 	return &StoryArea{
 		Position: *NewStoryAreaPosition(),
-		Type: *NewStoryAreaType(),
+		Type: NewStoryAreaType(),
 	}
 }
 
@@ -1939,16 +1920,15 @@ func NewStoryAreaPosition() *StoryAreaPosition {
 }
 
 // NewStoryAreaType creates a fixture StoryAreaType
-func NewStoryAreaType() *StoryAreaType {
-	return &StoryAreaType{
-	}
+func NewStoryAreaType() StoryAreaType {
+	return NewStoryAreaTypeLocation()
 }
 
 // NewStoryAreaTypeLink creates a fixture StoryAreaTypeLink
 func NewStoryAreaTypeLink() *StoryAreaTypeLink {
 	return &StoryAreaTypeLink{
 		Type: "private",
-		Url: "url",
+		URL: "url",
 	}
 }
 
@@ -1965,7 +1945,7 @@ func NewStoryAreaTypeLocation() *StoryAreaTypeLocation {
 func NewStoryAreaTypeSuggestedReaction() *StoryAreaTypeSuggestedReaction {
 	return &StoryAreaTypeSuggestedReaction{
 		Type: "private",
-		ReactionType: *NewReactionType(),
+		ReactionType: NewReactionType(),
 	}
 }
 
@@ -1993,8 +1973,8 @@ func NewSuccessfulPayment() *SuccessfulPayment {
 		Currency: "currency",
 		TotalAmount: 1,
 		InvoicePayload: "invoice_payload",
-		TelegramPaymentChargeId: "telegram_payment_charge_id",
-		ProviderPaymentChargeId: "provider_payment_charge_id",
+		TelegramPaymentChargeID: "telegram_payment_charge_id",
+		ProviderPaymentChargeID: "provider_payment_charge_id",
 	}
 }
 
@@ -2068,9 +2048,8 @@ func NewTextQuote() *TextQuote {
 }
 
 // NewTransactionPartner creates a fixture TransactionPartner
-func NewTransactionPartner() *TransactionPartner {
-	return &TransactionPartner{
-	}
+func NewTransactionPartner() TransactionPartner {
+	return NewTransactionPartnerUser()
 }
 
 // NewTransactionPartnerAffiliateProgram creates a fixture TransactionPartnerAffiliateProgram
@@ -2187,14 +2166,14 @@ func NewUniqueGiftSymbol() *UniqueGiftSymbol {
 // NewUpdate creates a fixture Update
 func NewUpdate() *Update {
 	return &Update{
-		UpdateId: 1,
+		UpdateID: 1,
 	}
 }
 
 // NewUser creates a fixture User
 func NewUser() *User {
 	return &User{
-		Id: 1,
+		ID: 1,
 		IsBot: true,
 		FirstName: "Test",
 	}
@@ -2218,7 +2197,7 @@ func NewUserProfilePhotos() *UserProfilePhotos {
 // NewUsersShared creates a fixture UsersShared
 func NewUsersShared() *UsersShared {
 	return &UsersShared{
-		RequestId: 1,
+		RequestID: 1,
 		Users: nil,
 	}
 }
@@ -2235,8 +2214,8 @@ func NewVenue() *Venue {
 // NewVideo creates a fixture Video
 func NewVideo() *Video {
 	return &Video{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Width: 1,
 		Height: 1,
 		Duration: 1,
@@ -2273,8 +2252,8 @@ func NewVideoChatStarted() *VideoChatStarted {
 // NewVideoNote creates a fixture VideoNote
 func NewVideoNote() *VideoNote {
 	return &VideoNote{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Length: 1,
 		Duration: 1,
 	}
@@ -2283,8 +2262,8 @@ func NewVideoNote() *VideoNote {
 // NewVoice creates a fixture Voice
 func NewVoice() *Voice {
 	return &Voice{
-		FileId: "file_id",
-		FileUniqueId: "file_unique_id",
+		FileID: "file_id",
+		FileUniqueID: "file_unique_id",
 		Duration: 1,
 	}
 }
@@ -2300,14 +2279,14 @@ func NewWebAppData() *WebAppData {
 // NewWebAppInfo creates a fixture WebAppInfo
 func NewWebAppInfo() *WebAppInfo {
 	return &WebAppInfo{
-		Url: "url",
+		URL: "url",
 	}
 }
 
 // NewWebhookInfo creates a fixture WebhookInfo
 func NewWebhookInfo() *WebhookInfo {
 	return &WebhookInfo{
-		Url: "url",
+		URL: "url",
 		HasCustomCertificate: true,
 		PendingUpdateCount: 1,
 	}
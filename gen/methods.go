@@ -6,20 +6,119 @@ type FieldSpec struct {
 	Name     string
 	Types    []string
 	Required bool
+	// Constraint is the limit the description states, if any
+	Constraint *Constraint
+}
+
+// Constraint is a limit on a field that the spec only states in the
+// field's description, such as "1-4096 characters" or "must be one of".
+type Constraint struct {
+	// Min and Max bound the length of a string or an array, or the value
+	// of a number. Max is 0 when the description gives no bounds.
+	Min, Max int64
+	// Unit is what a length counts: "characters" or "bytes" of a string,
+	// or "items" of an array. It is empty for the value of a number.
+	Unit string
+	// Parsed is set when a text's length counts after entities parsing.
+	Parsed bool
+	// Enum lists the values the field can take, if the spec lists them.
+	Enum []string
 }
 
 // MethodSpec describes a Bot API method
 type MethodSpec struct {
 	Name    string
 	Returns []string
-	Fields  []FieldSpec
+	// Result is the parsed form of Returns
+	Result TypeRef
+	Fields []FieldSpec
+}
+
+// TypeRef is a parsed Bot API type expression. Exactly one of Name, Elem,
+// and Union is set: "Message" is a named type, "Array of Message" an array
+// of Message, and "Message or Boolean" a union.
+type TypeRef struct {
+	Name  string
+	Elem  *TypeRef
+	Union []TypeRef
+}
+
+// ParseType parses a type expression as written in the spec. Several
+// expressions make a union.
+func ParseType(types ...string) TypeRef {
+	if len(types) == 1 {
+		return parseSingleType(types[0])
+	}
+	union := make([]TypeRef, 0, len(types))
+	for _, t := range types {
+		union = append(union, parseSingleType(t))
+	}
+	return TypeRef{Union: union}
+}
+
+func parseSingleType(t string) TypeRef {
+	if len(t) > len(arrayPrefix) && t[:len(arrayPrefix)] == arrayPrefix {
+		elem := parseSingleType(t[len(arrayPrefix):])
+		return TypeRef{Elem: &elem}
+	}
+	return TypeRef{Name: t}
 }
 
+const arrayPrefix = "Array of "
+
+// IsArray reports whether t is an array.
+func (t TypeRef) IsArray() bool { return t.Elem != nil }
+
+// IsUnion reports whether t is a union.
+func (t TypeRef) IsUnion() bool { return len(t.Union) > 0 }
+
+// Primary returns the first alternative of a union, or t itself. Responses
+// are generated as the primary type.
+func (t TypeRef) Primary() TypeRef {
+	if t.IsUnion() {
+		return t.Union[0]
+	}
+	return t
+}
+
+// Base returns the named type at the bottom of t's arrays, using the
+// primary alternative of unions.
+func (t TypeRef) Base() string {
+	t = t.Primary()
+	for t.IsArray() {
+		t = t.Elem.Primary()
+	}
+	return t.Name
+}
+
+// String formats t the way the spec writes it.
+func (t TypeRef) String() string {
+	switch {
+	case t.IsArray():
+		return arrayPrefix + t.Elem.String()
+	case t.IsUnion():
+		s := ""
+		for i, alt := range t.Union {
+			if i > 0 {
+				s += " or "
+			}
+			s += alt.String()
+		}
+		return s
+	default:
+		return t.Name
+	}
+}
+
+// Version is the Bot API version the spec describes
+const Version = "9.2"
+
 // Methods is the registry of all Bot API methods
 var Methods = map[string]MethodSpec{
 	"addStickerToSet": {
 		Name:    "addStickerToSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "name", Types: []string{"String"}, Required: true},
@@ -29,9 +128,10 @@ var Methods = map[string]MethodSpec{
 	"answerCallbackQuery": {
 		Name:    "answerCallbackQuery",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "callback_query_id", Types: []string{"String"}, Required: true},
-			{Name: "text", Types: []string{"String"}, Required: false},
+			{Name: "text", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 200, Unit: "characters"}},
 			{Name: "show_alert", Types: []string{"Boolean"}, Required: false},
 			{Name: "url", Types: []string{"String"}, Required: false},
 			{Name: "cache_time", Types: []string{"Integer"}, Required: false},
@@ -40,6 +140,7 @@ var Methods = map[string]MethodSpec{
 	"answerInlineQuery": {
 		Name:    "answerInlineQuery",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "inline_query_id", Types: []string{"String"}, Required: true},
 			{Name: "results", Types: []string{"Array of InlineQueryResult"}, Required: true},
@@ -52,6 +153,7 @@ var Methods = map[string]MethodSpec{
 	"answerPreCheckoutQuery": {
 		Name:    "answerPreCheckoutQuery",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "pre_checkout_query_id", Types: []string{"String"}, Required: true},
 			{Name: "ok", Types: []string{"Boolean"}, Required: true},
@@ -61,6 +163,7 @@ var Methods = map[string]MethodSpec{
 	"answerShippingQuery": {
 		Name:    "answerShippingQuery",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "shipping_query_id", Types: []string{"String"}, Required: true},
 			{Name: "ok", Types: []string{"Boolean"}, Required: true},
@@ -71,6 +174,7 @@ var Methods = map[string]MethodSpec{
 	"answerWebAppQuery": {
 		Name:    "answerWebAppQuery",
 		Returns: []string{"SentWebAppMessage"},
+		Result:  TypeRef{Name: "SentWebAppMessage"},
 		Fields: []FieldSpec{
 			{Name: "web_app_query_id", Types: []string{"String"}, Required: true},
 			{Name: "result", Types: []string{"InlineQueryResult"}, Required: true},
@@ -79,6 +183,7 @@ var Methods = map[string]MethodSpec{
 	"approveChatJoinRequest": {
 		Name:    "approveChatJoinRequest",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -87,6 +192,7 @@ var Methods = map[string]MethodSpec{
 	"approveSuggestedPost": {
 		Name:    "approveSuggestedPost",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
 			{Name: "message_id", Types: []string{"Integer"}, Required: true},
@@ -96,6 +202,7 @@ var Methods = map[string]MethodSpec{
 	"banChatMember": {
 		Name:    "banChatMember",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -106,6 +213,7 @@ var Methods = map[string]MethodSpec{
 	"banChatSenderChat": {
 		Name:    "banChatSenderChat",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "sender_chat_id", Types: []string{"Integer"}, Required: true},
@@ -114,10 +222,12 @@ var Methods = map[string]MethodSpec{
 	"close": {
 		Name:    "close",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 	},
 	"closeForumTopic": {
 		Name:    "closeForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: true},
@@ -126,6 +236,7 @@ var Methods = map[string]MethodSpec{
 	"closeGeneralForumTopic": {
 		Name:    "closeGeneralForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -133,6 +244,7 @@ var Methods = map[string]MethodSpec{
 	"convertGiftToStars": {
 		Name:    "convertGiftToStars",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "owned_gift_id", Types: []string{"String"}, Required: true},
@@ -141,6 +253,7 @@ var Methods = map[string]MethodSpec{
 	"copyMessage": {
 		Name:    "copyMessage",
 		Returns: []string{"MessageId"},
+		Result:  TypeRef{Name: "MessageId"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
@@ -148,7 +261,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "from_chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_id", Types: []string{"Integer"}, Required: true},
 			{Name: "video_start_timestamp", Types: []string{"Integer"}, Required: false},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
@@ -163,12 +276,13 @@ var Methods = map[string]MethodSpec{
 	"copyMessages": {
 		Name:    "copyMessages",
 		Returns: []string{"Array of MessageId"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "MessageId"}},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "from_chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true},
+			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "items"}},
 			{Name: "disable_notification", Types: []string{"Boolean"}, Required: false},
 			{Name: "protect_content", Types: []string{"Boolean"}, Required: false},
 			{Name: "remove_caption", Types: []string{"Boolean"}, Required: false},
@@ -177,42 +291,46 @@ var Methods = map[string]MethodSpec{
 	"createChatInviteLink": {
 		Name:    "createChatInviteLink",
 		Returns: []string{"ChatInviteLink"},
+		Result:  TypeRef{Name: "ChatInviteLink"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 32, Unit: "characters"}},
 			{Name: "expire_date", Types: []string{"Integer"}, Required: false},
-			{Name: "member_limit", Types: []string{"Integer"}, Required: false},
+			{Name: "member_limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 99999}},
 			{Name: "creates_join_request", Types: []string{"Boolean"}, Required: false},
 		},
 	},
 	"createChatSubscriptionInviteLink": {
 		Name:    "createChatSubscriptionInviteLink",
 		Returns: []string{"ChatInviteLink"},
+		Result:  TypeRef{Name: "ChatInviteLink"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 32, Unit: "characters"}},
 			{Name: "subscription_period", Types: []string{"Integer"}, Required: true},
-			{Name: "subscription_price", Types: []string{"Integer"}, Required: true},
+			{Name: "subscription_price", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 10000}},
 		},
 	},
 	"createForumTopic": {
 		Name:    "createForumTopic",
 		Returns: []string{"ForumTopic"},
+		Result:  TypeRef{Name: "ForumTopic"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: true},
-			{Name: "icon_color", Types: []string{"Integer"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 128, Unit: "characters"}},
+			{Name: "icon_color", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Enum: []string{"7322096", "16766590", "13338331", "9367192", "16749490", "16478047"}}},
 			{Name: "icon_custom_emoji_id", Types: []string{"String"}, Required: false},
 		},
 	},
 	"createInvoiceLink": {
 		Name:    "createInvoiceLink",
 		Returns: []string{"String"},
+		Result:  TypeRef{Name: "String"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
-			{Name: "title", Types: []string{"String"}, Required: true},
-			{Name: "description", Types: []string{"String"}, Required: true},
-			{Name: "payload", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 32, Unit: "characters"}},
+			{Name: "description", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 255, Unit: "characters"}},
+			{Name: "payload", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 128, Unit: "bytes"}},
 			{Name: "provider_token", Types: []string{"String"}, Required: false},
 			{Name: "currency", Types: []string{"String"}, Required: true},
 			{Name: "prices", Types: []string{"Array of LabeledPrice"}, Required: true},
@@ -236,11 +354,12 @@ var Methods = map[string]MethodSpec{
 	"createNewStickerSet": {
 		Name:    "createNewStickerSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: true},
-			{Name: "title", Types: []string{"String"}, Required: true},
-			{Name: "stickers", Types: []string{"Array of InputSticker"}, Required: true},
+			{Name: "name", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+			{Name: "stickers", Types: []string{"Array of InputSticker"}, Required: true, Constraint: &Constraint{Min: 1, Max: 50, Unit: "items"}},
 			{Name: "sticker_type", Types: []string{"String"}, Required: false},
 			{Name: "needs_repainting", Types: []string{"Boolean"}, Required: false},
 		},
@@ -248,6 +367,7 @@ var Methods = map[string]MethodSpec{
 	"declineChatJoinRequest": {
 		Name:    "declineChatJoinRequest",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -256,23 +376,26 @@ var Methods = map[string]MethodSpec{
 	"declineSuggestedPost": {
 		Name:    "declineSuggestedPost",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
 			{Name: "message_id", Types: []string{"Integer"}, Required: true},
-			{Name: "comment", Types: []string{"String"}, Required: false},
+			{Name: "comment", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 128, Unit: "characters"}},
 		},
 	},
 	"deleteBusinessMessages": {
 		Name:    "deleteBusinessMessages",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
-			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true},
+			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "items"}},
 		},
 	},
 	"deleteChatPhoto": {
 		Name:    "deleteChatPhoto",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -280,6 +403,7 @@ var Methods = map[string]MethodSpec{
 	"deleteChatStickerSet": {
 		Name:    "deleteChatStickerSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -287,6 +411,7 @@ var Methods = map[string]MethodSpec{
 	"deleteForumTopic": {
 		Name:    "deleteForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: true},
@@ -295,6 +420,7 @@ var Methods = map[string]MethodSpec{
 	"deleteMessage": {
 		Name:    "deleteMessage",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_id", Types: []string{"Integer"}, Required: true},
@@ -303,14 +429,16 @@ var Methods = map[string]MethodSpec{
 	"deleteMessages": {
 		Name:    "deleteMessages",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true},
+			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "items"}},
 		},
 	},
 	"deleteMyCommands": {
 		Name:    "deleteMyCommands",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "scope", Types: []string{"BotCommandScope"}, Required: false},
 			{Name: "language_code", Types: []string{"String"}, Required: false},
@@ -319,6 +447,7 @@ var Methods = map[string]MethodSpec{
 	"deleteStickerFromSet": {
 		Name:    "deleteStickerFromSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "sticker", Types: []string{"String"}, Required: true},
 		},
@@ -326,6 +455,7 @@ var Methods = map[string]MethodSpec{
 	"deleteStickerSet": {
 		Name:    "deleteStickerSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "name", Types: []string{"String"}, Required: true},
 		},
@@ -333,6 +463,7 @@ var Methods = map[string]MethodSpec{
 	"deleteStory": {
 		Name:    "deleteStory",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "story_id", Types: []string{"Integer"}, Required: true},
@@ -341,6 +472,7 @@ var Methods = map[string]MethodSpec{
 	"deleteWebhook": {
 		Name:    "deleteWebhook",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "drop_pending_updates", Types: []string{"Boolean"}, Required: false},
 		},
@@ -348,51 +480,56 @@ var Methods = map[string]MethodSpec{
 	"editChatInviteLink": {
 		Name:    "editChatInviteLink",
 		Returns: []string{"ChatInviteLink"},
+		Result:  TypeRef{Name: "ChatInviteLink"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "invite_link", Types: []string{"String"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 32, Unit: "characters"}},
 			{Name: "expire_date", Types: []string{"Integer"}, Required: false},
-			{Name: "member_limit", Types: []string{"Integer"}, Required: false},
+			{Name: "member_limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 99999}},
 			{Name: "creates_join_request", Types: []string{"Boolean"}, Required: false},
 		},
 	},
 	"editChatSubscriptionInviteLink": {
 		Name:    "editChatSubscriptionInviteLink",
 		Returns: []string{"ChatInviteLink"},
+		Result:  TypeRef{Name: "ChatInviteLink"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "invite_link", Types: []string{"String"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 32, Unit: "characters"}},
 		},
 	},
 	"editForumTopic": {
 		Name:    "editForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 128, Unit: "characters"}},
 			{Name: "icon_custom_emoji_id", Types: []string{"String"}, Required: false},
 		},
 	},
 	"editGeneralForumTopic": {
 		Name:    "editGeneralForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "name", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 128, Unit: "characters"}},
 		},
 	},
 	"editMessageCaption": {
 		Name:    "editMessageCaption",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
 			{Name: "message_id", Types: []string{"Integer"}, Required: false},
 			{Name: "inline_message_id", Types: []string{"String"}, Required: false},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
@@ -402,6 +539,7 @@ var Methods = map[string]MethodSpec{
 	"editMessageChecklist": {
 		Name:    "editMessageChecklist",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
@@ -413,6 +551,7 @@ var Methods = map[string]MethodSpec{
 	"editMessageLiveLocation": {
 		Name:    "editMessageLiveLocation",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
@@ -421,7 +560,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "latitude", Types: []string{"Float"}, Required: true},
 			{Name: "longitude", Types: []string{"Float"}, Required: true},
 			{Name: "live_period", Types: []string{"Integer"}, Required: false},
-			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false},
+			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1500}},
 			{Name: "heading", Types: []string{"Integer"}, Required: false},
 			{Name: "proximity_alert_radius", Types: []string{"Integer"}, Required: false},
 			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
@@ -430,6 +569,7 @@ var Methods = map[string]MethodSpec{
 	"editMessageMedia": {
 		Name:    "editMessageMedia",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
@@ -442,6 +582,7 @@ var Methods = map[string]MethodSpec{
 	"editMessageReplyMarkup": {
 		Name:    "editMessageReplyMarkup",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
@@ -453,12 +594,13 @@ var Methods = map[string]MethodSpec{
 	"editMessageText": {
 		Name:    "editMessageText",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
 			{Name: "message_id", Types: []string{"Integer"}, Required: false},
 			{Name: "inline_message_id", Types: []string{"String"}, Required: false},
-			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 4096, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "link_preview_options", Types: []string{"LinkPreviewOptions"}, Required: false},
@@ -468,11 +610,12 @@ var Methods = map[string]MethodSpec{
 	"editStory": {
 		Name:    "editStory",
 		Returns: []string{"Story"},
+		Result:  TypeRef{Name: "Story"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "story_id", Types: []string{"Integer"}, Required: true},
 			{Name: "content", Types: []string{"InputStoryContent"}, Required: true},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 2048, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "areas", Types: []string{"Array of StoryArea"}, Required: false},
@@ -481,6 +624,7 @@ var Methods = map[string]MethodSpec{
 	"editUserStarSubscription": {
 		Name:    "editUserStarSubscription",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "telegram_payment_charge_id", Types: []string{"String"}, Required: true},
@@ -490,6 +634,7 @@ var Methods = map[string]MethodSpec{
 	"exportChatInviteLink": {
 		Name:    "exportChatInviteLink",
 		Returns: []string{"String"},
+		Result:  TypeRef{Name: "String"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -497,6 +642,7 @@ var Methods = map[string]MethodSpec{
 	"forwardMessage": {
 		Name:    "forwardMessage",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
@@ -512,12 +658,13 @@ var Methods = map[string]MethodSpec{
 	"forwardMessages": {
 		Name:    "forwardMessages",
 		Returns: []string{"Array of MessageId"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "MessageId"}},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "from_chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true},
+			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "items"}},
 			{Name: "disable_notification", Types: []string{"Boolean"}, Required: false},
 			{Name: "protect_content", Types: []string{"Boolean"}, Required: false},
 		},
@@ -525,10 +672,12 @@ var Methods = map[string]MethodSpec{
 	"getAvailableGifts": {
 		Name:    "getAvailableGifts",
 		Returns: []string{"Gifts"},
+		Result:  TypeRef{Name: "Gifts"},
 	},
 	"getBusinessAccountGifts": {
 		Name:    "getBusinessAccountGifts",
 		Returns: []string{"OwnedGifts"},
+		Result:  TypeRef{Name: "OwnedGifts"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "exclude_unsaved", Types: []string{"Boolean"}, Required: false},
@@ -538,12 +687,13 @@ var Methods = map[string]MethodSpec{
 			{Name: "exclude_unique", Types: []string{"Boolean"}, Required: false},
 			{Name: "sort_by_price", Types: []string{"Boolean"}, Required: false},
 			{Name: "offset", Types: []string{"String"}, Required: false},
-			{Name: "limit", Types: []string{"Integer"}, Required: false},
+			{Name: "limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 100}},
 		},
 	},
 	"getBusinessAccountStarBalance": {
 		Name:    "getBusinessAccountStarBalance",
 		Returns: []string{"StarAmount"},
+		Result:  TypeRef{Name: "StarAmount"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 		},
@@ -551,6 +701,7 @@ var Methods = map[string]MethodSpec{
 	"getBusinessConnection": {
 		Name:    "getBusinessConnection",
 		Returns: []string{"BusinessConnection"},
+		Result:  TypeRef{Name: "BusinessConnection"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 		},
@@ -558,6 +709,7 @@ var Methods = map[string]MethodSpec{
 	"getChat": {
 		Name:    "getChat",
 		Returns: []string{"ChatFullInfo"},
+		Result:  TypeRef{Name: "ChatFullInfo"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -565,6 +717,7 @@ var Methods = map[string]MethodSpec{
 	"getChatAdministrators": {
 		Name:    "getChatAdministrators",
 		Returns: []string{"Array of ChatMember"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "ChatMember"}},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -572,6 +725,7 @@ var Methods = map[string]MethodSpec{
 	"getChatMember": {
 		Name:    "getChatMember",
 		Returns: []string{"ChatMember"},
+		Result:  TypeRef{Name: "ChatMember"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -580,6 +734,7 @@ var Methods = map[string]MethodSpec{
 	"getChatMemberCount": {
 		Name:    "getChatMemberCount",
 		Returns: []string{"Integer"},
+		Result:  TypeRef{Name: "Integer"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -587,6 +742,7 @@ var Methods = map[string]MethodSpec{
 	"getChatMenuButton": {
 		Name:    "getChatMenuButton",
 		Returns: []string{"MenuButton"},
+		Result:  TypeRef{Name: "MenuButton"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer"}, Required: false},
 		},
@@ -594,6 +750,7 @@ var Methods = map[string]MethodSpec{
 	"getCustomEmojiStickers": {
 		Name:    "getCustomEmojiStickers",
 		Returns: []string{"Array of Sticker"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "Sticker"}},
 		Fields: []FieldSpec{
 			{Name: "custom_emoji_ids", Types: []string{"Array of String"}, Required: true},
 		},
@@ -601,6 +758,7 @@ var Methods = map[string]MethodSpec{
 	"getFile": {
 		Name:    "getFile",
 		Returns: []string{"File"},
+		Result:  TypeRef{Name: "File"},
 		Fields: []FieldSpec{
 			{Name: "file_id", Types: []string{"String"}, Required: true},
 		},
@@ -608,10 +766,12 @@ var Methods = map[string]MethodSpec{
 	"getForumTopicIconStickers": {
 		Name:    "getForumTopicIconStickers",
 		Returns: []string{"Array of Sticker"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "Sticker"}},
 	},
 	"getGameHighScores": {
 		Name:    "getGameHighScores",
 		Returns: []string{"Array of GameHighScore"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "GameHighScore"}},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "chat_id", Types: []string{"Integer"}, Required: false},
@@ -622,10 +782,12 @@ var Methods = map[string]MethodSpec{
 	"getMe": {
 		Name:    "getMe",
 		Returns: []string{"User"},
+		Result:  TypeRef{Name: "User"},
 	},
 	"getMyCommands": {
 		Name:    "getMyCommands",
 		Returns: []string{"Array of BotCommand"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "BotCommand"}},
 		Fields: []FieldSpec{
 			{Name: "scope", Types: []string{"BotCommandScope"}, Required: false},
 			{Name: "language_code", Types: []string{"String"}, Required: false},
@@ -634,6 +796,7 @@ var Methods = map[string]MethodSpec{
 	"getMyDefaultAdministratorRights": {
 		Name:    "getMyDefaultAdministratorRights",
 		Returns: []string{"ChatAdministratorRights"},
+		Result:  TypeRef{Name: "ChatAdministratorRights"},
 		Fields: []FieldSpec{
 			{Name: "for_channels", Types: []string{"Boolean"}, Required: false},
 		},
@@ -641,6 +804,7 @@ var Methods = map[string]MethodSpec{
 	"getMyDescription": {
 		Name:    "getMyDescription",
 		Returns: []string{"BotDescription"},
+		Result:  TypeRef{Name: "BotDescription"},
 		Fields: []FieldSpec{
 			{Name: "language_code", Types: []string{"String"}, Required: false},
 		},
@@ -648,6 +812,7 @@ var Methods = map[string]MethodSpec{
 	"getMyName": {
 		Name:    "getMyName",
 		Returns: []string{"BotName"},
+		Result:  TypeRef{Name: "BotName"},
 		Fields: []FieldSpec{
 			{Name: "language_code", Types: []string{"String"}, Required: false},
 		},
@@ -655,6 +820,7 @@ var Methods = map[string]MethodSpec{
 	"getMyShortDescription": {
 		Name:    "getMyShortDescription",
 		Returns: []string{"BotShortDescription"},
+		Result:  TypeRef{Name: "BotShortDescription"},
 		Fields: []FieldSpec{
 			{Name: "language_code", Types: []string{"String"}, Required: false},
 		},
@@ -662,18 +828,21 @@ var Methods = map[string]MethodSpec{
 	"getMyStarBalance": {
 		Name:    "getMyStarBalance",
 		Returns: []string{"StarAmount"},
+		Result:  TypeRef{Name: "StarAmount"},
 	},
 	"getStarTransactions": {
 		Name:    "getStarTransactions",
 		Returns: []string{"StarTransactions"},
+		Result:  TypeRef{Name: "StarTransactions"},
 		Fields: []FieldSpec{
 			{Name: "offset", Types: []string{"Integer"}, Required: false},
-			{Name: "limit", Types: []string{"Integer"}, Required: false},
+			{Name: "limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 100}},
 		},
 	},
 	"getStickerSet": {
 		Name:    "getStickerSet",
 		Returns: []string{"StickerSet"},
+		Result:  TypeRef{Name: "StickerSet"},
 		Fields: []FieldSpec{
 			{Name: "name", Types: []string{"String"}, Required: true},
 		},
@@ -681,9 +850,10 @@ var Methods = map[string]MethodSpec{
 	"getUpdates": {
 		Name:    "getUpdates",
 		Returns: []string{"Array of Update"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "Update"}},
 		Fields: []FieldSpec{
 			{Name: "offset", Types: []string{"Integer"}, Required: false},
-			{Name: "limit", Types: []string{"Integer"}, Required: false},
+			{Name: "limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 100}},
 			{Name: "timeout", Types: []string{"Integer"}, Required: false},
 			{Name: "allowed_updates", Types: []string{"Array of String"}, Required: false},
 		},
@@ -691,6 +861,7 @@ var Methods = map[string]MethodSpec{
 	"getUserChatBoosts": {
 		Name:    "getUserChatBoosts",
 		Returns: []string{"UserChatBoosts"},
+		Result:  TypeRef{Name: "UserChatBoosts"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -699,24 +870,27 @@ var Methods = map[string]MethodSpec{
 	"getUserProfilePhotos": {
 		Name:    "getUserProfilePhotos",
 		Returns: []string{"UserProfilePhotos"},
+		Result:  TypeRef{Name: "UserProfilePhotos"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "offset", Types: []string{"Integer"}, Required: false},
-			{Name: "limit", Types: []string{"Integer"}, Required: false},
+			{Name: "limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 100}},
 		},
 	},
 	"getWebhookInfo": {
 		Name:    "getWebhookInfo",
 		Returns: []string{"WebhookInfo"},
+		Result:  TypeRef{Name: "WebhookInfo"},
 	},
 	"giftPremiumSubscription": {
 		Name:    "giftPremiumSubscription",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
-			{Name: "month_count", Types: []string{"Integer"}, Required: true},
+			{Name: "month_count", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Enum: []string{"3", "6", "12"}}},
 			{Name: "star_count", Types: []string{"Integer"}, Required: true},
-			{Name: "text", Types: []string{"String"}, Required: false},
+			{Name: "text", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 128, Unit: "characters"}},
 			{Name: "text_parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 		},
@@ -724,6 +898,7 @@ var Methods = map[string]MethodSpec{
 	"hideGeneralForumTopic": {
 		Name:    "hideGeneralForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -731,6 +906,7 @@ var Methods = map[string]MethodSpec{
 	"leaveChat": {
 		Name:    "leaveChat",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -738,10 +914,12 @@ var Methods = map[string]MethodSpec{
 	"logOut": {
 		Name:    "logOut",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 	},
 	"pinChatMessage": {
 		Name:    "pinChatMessage",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -752,11 +930,12 @@ var Methods = map[string]MethodSpec{
 	"postStory": {
 		Name:    "postStory",
 		Returns: []string{"Story"},
+		Result:  TypeRef{Name: "Story"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "content", Types: []string{"InputStoryContent"}, Required: true},
-			{Name: "active_period", Types: []string{"Integer"}, Required: true},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "active_period", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Enum: []string{"21600", "43200", "86400", "172800"}}},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 2048, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "areas", Types: []string{"Array of StoryArea"}, Required: false},
@@ -767,6 +946,7 @@ var Methods = map[string]MethodSpec{
 	"promoteChatMember": {
 		Name:    "promoteChatMember",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -791,6 +971,7 @@ var Methods = map[string]MethodSpec{
 	"readBusinessMessage": {
 		Name:    "readBusinessMessage",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
@@ -800,6 +981,7 @@ var Methods = map[string]MethodSpec{
 	"refundStarPayment": {
 		Name:    "refundStarPayment",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "telegram_payment_charge_id", Types: []string{"String"}, Required: true},
@@ -808,6 +990,7 @@ var Methods = map[string]MethodSpec{
 	"removeBusinessAccountProfilePhoto": {
 		Name:    "removeBusinessAccountProfilePhoto",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "is_public", Types: []string{"Boolean"}, Required: false},
@@ -816,6 +999,7 @@ var Methods = map[string]MethodSpec{
 	"removeChatVerification": {
 		Name:    "removeChatVerification",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -823,6 +1007,7 @@ var Methods = map[string]MethodSpec{
 	"removeUserVerification": {
 		Name:    "removeUserVerification",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 		},
@@ -830,6 +1015,7 @@ var Methods = map[string]MethodSpec{
 	"reopenForumTopic": {
 		Name:    "reopenForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: true},
@@ -838,6 +1024,7 @@ var Methods = map[string]MethodSpec{
 	"reopenGeneralForumTopic": {
 		Name:    "reopenGeneralForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -845,6 +1032,7 @@ var Methods = map[string]MethodSpec{
 	"replaceStickerInSet": {
 		Name:    "replaceStickerInSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "name", Types: []string{"String"}, Required: true},
@@ -855,6 +1043,7 @@ var Methods = map[string]MethodSpec{
 	"restrictChatMember": {
 		Name:    "restrictChatMember",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -866,6 +1055,7 @@ var Methods = map[string]MethodSpec{
 	"revokeChatInviteLink": {
 		Name:    "revokeChatInviteLink",
 		Returns: []string{"ChatInviteLink"},
+		Result:  TypeRef{Name: "ChatInviteLink"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "invite_link", Types: []string{"String"}, Required: true},
@@ -874,6 +1064,7 @@ var Methods = map[string]MethodSpec{
 	"savePreparedInlineMessage": {
 		Name:    "savePreparedInlineMessage",
 		Returns: []string{"PreparedInlineMessage"},
+		Result:  TypeRef{Name: "PreparedInlineMessage"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "result", Types: []string{"InlineQueryResult"}, Required: true},
@@ -886,6 +1077,7 @@ var Methods = map[string]MethodSpec{
 	"sendAnimation": {
 		Name:    "sendAnimation",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -896,7 +1088,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "width", Types: []string{"Integer"}, Required: false},
 			{Name: "height", Types: []string{"Integer"}, Required: false},
 			{Name: "thumbnail", Types: []string{"InputFile", "String"}, Required: false},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
@@ -913,13 +1105,14 @@ var Methods = map[string]MethodSpec{
 	"sendAudio": {
 		Name:    "sendAudio",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "audio", Types: []string{"InputFile", "String"}, Required: true},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "duration", Types: []string{"Integer"}, Required: false},
@@ -938,16 +1131,18 @@ var Methods = map[string]MethodSpec{
 	"sendChatAction": {
 		Name:    "sendChatAction",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
-			{Name: "action", Types: []string{"String"}, Required: true},
+			{Name: "action", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"typing", "upload_photo", "record_video", "upload_video", "record_voice", "upload_voice", "upload_document", "choose_sticker", "find_location", "record_video_note", "upload_video_note"}}},
 		},
 	},
 	"sendChecklist": {
 		Name:    "sendChecklist",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
@@ -962,6 +1157,7 @@ var Methods = map[string]MethodSpec{
 	"sendContact": {
 		Name:    "sendContact",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -970,7 +1166,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "phone_number", Types: []string{"String"}, Required: true},
 			{Name: "first_name", Types: []string{"String"}, Required: true},
 			{Name: "last_name", Types: []string{"String"}, Required: false},
-			{Name: "vcard", Types: []string{"String"}, Required: false},
+			{Name: "vcard", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 2048, Unit: "bytes"}},
 			{Name: "disable_notification", Types: []string{"Boolean"}, Required: false},
 			{Name: "protect_content", Types: []string{"Boolean"}, Required: false},
 			{Name: "allow_paid_broadcast", Types: []string{"Boolean"}, Required: false},
@@ -983,12 +1179,13 @@ var Methods = map[string]MethodSpec{
 	"sendDice": {
 		Name:    "sendDice",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
-			{Name: "emoji", Types: []string{"String"}, Required: false},
+			{Name: "emoji", Types: []string{"String"}, Required: false, Constraint: &Constraint{Enum: []string{"🎲", "🎯", "🏀", "⚽", "🎳", "🎰"}}},
 			{Name: "disable_notification", Types: []string{"Boolean"}, Required: false},
 			{Name: "protect_content", Types: []string{"Boolean"}, Required: false},
 			{Name: "allow_paid_broadcast", Types: []string{"Boolean"}, Required: false},
@@ -1001,6 +1198,7 @@ var Methods = map[string]MethodSpec{
 	"sendDocument": {
 		Name:    "sendDocument",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1008,7 +1206,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "document", Types: []string{"InputFile", "String"}, Required: true},
 			{Name: "thumbnail", Types: []string{"InputFile", "String"}, Required: false},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "disable_content_type_detection", Types: []string{"Boolean"}, Required: false},
@@ -1024,6 +1222,7 @@ var Methods = map[string]MethodSpec{
 	"sendGame": {
 		Name:    "sendGame",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
@@ -1040,12 +1239,13 @@ var Methods = map[string]MethodSpec{
 	"sendGift": {
 		Name:    "sendGift",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
 			{Name: "gift_id", Types: []string{"String"}, Required: true},
 			{Name: "pay_for_upgrade", Types: []string{"Boolean"}, Required: false},
-			{Name: "text", Types: []string{"String"}, Required: false},
+			{Name: "text", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 128, Unit: "characters"}},
 			{Name: "text_parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 		},
@@ -1053,13 +1253,14 @@ var Methods = map[string]MethodSpec{
 	"sendInvoice": {
 		Name:    "sendInvoice",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
-			{Name: "title", Types: []string{"String"}, Required: true},
-			{Name: "description", Types: []string{"String"}, Required: true},
-			{Name: "payload", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 32, Unit: "characters"}},
+			{Name: "description", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 255, Unit: "characters"}},
+			{Name: "payload", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 128, Unit: "bytes"}},
 			{Name: "provider_token", Types: []string{"String"}, Required: false},
 			{Name: "currency", Types: []string{"String"}, Required: true},
 			{Name: "prices", Types: []string{"Array of LabeledPrice"}, Required: true},
@@ -1090,6 +1291,7 @@ var Methods = map[string]MethodSpec{
 	"sendLocation": {
 		Name:    "sendLocation",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1097,7 +1299,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "latitude", Types: []string{"Float"}, Required: true},
 			{Name: "longitude", Types: []string{"Float"}, Required: true},
-			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false},
+			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1500}},
 			{Name: "live_period", Types: []string{"Integer"}, Required: false},
 			{Name: "heading", Types: []string{"Integer"}, Required: false},
 			{Name: "proximity_alert_radius", Types: []string{"Integer"}, Required: false},
@@ -1113,12 +1315,13 @@ var Methods = map[string]MethodSpec{
 	"sendMediaGroup": {
 		Name:    "sendMediaGroup",
 		Returns: []string{"Array of Message"},
+		Result:  TypeRef{Elem: &TypeRef{Name: "Message"}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
-			{Name: "media", Types: []string{"Array of InputMediaAudio", "Array of InputMediaDocument", "Array of InputMediaPhoto", "Array of InputMediaVideo"}, Required: true},
+			{Name: "media", Types: []string{"Array of InputMediaAudio", "Array of InputMediaDocument", "Array of InputMediaPhoto", "Array of InputMediaVideo"}, Required: true, Constraint: &Constraint{Min: 2, Max: 10, Unit: "items"}},
 			{Name: "disable_notification", Types: []string{"Boolean"}, Required: false},
 			{Name: "protect_content", Types: []string{"Boolean"}, Required: false},
 			{Name: "allow_paid_broadcast", Types: []string{"Boolean"}, Required: false},
@@ -1129,12 +1332,13 @@ var Methods = map[string]MethodSpec{
 	"sendMessage": {
 		Name:    "sendMessage",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
-			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 4096, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "link_preview_options", Types: []string{"LinkPreviewOptions"}, Required: false},
@@ -1150,15 +1354,16 @@ var Methods = map[string]MethodSpec{
 	"sendPaidMedia": {
 		Name:    "sendPaidMedia",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
-			{Name: "star_count", Types: []string{"Integer"}, Required: true},
+			{Name: "star_count", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 10000}},
 			{Name: "media", Types: []string{"Array of InputPaidMedia"}, Required: true},
-			{Name: "payload", Types: []string{"String"}, Required: false},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "payload", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 128, Unit: "bytes"}},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
@@ -1173,13 +1378,14 @@ var Methods = map[string]MethodSpec{
 	"sendPhoto": {
 		Name:    "sendPhoto",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "photo", Types: []string{"InputFile", "String"}, Required: true},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
@@ -1196,22 +1402,23 @@ var Methods = map[string]MethodSpec{
 	"sendPoll": {
 		Name:    "sendPoll",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
-			{Name: "question", Types: []string{"String"}, Required: true},
+			{Name: "question", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 300, Unit: "characters"}},
 			{Name: "question_parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "question_entities", Types: []string{"Array of MessageEntity"}, Required: false},
-			{Name: "options", Types: []string{"Array of InputPollOption"}, Required: true},
+			{Name: "options", Types: []string{"Array of InputPollOption"}, Required: true, Constraint: &Constraint{Min: 2, Max: 12, Unit: "items"}},
 			{Name: "is_anonymous", Types: []string{"Boolean"}, Required: false},
-			{Name: "type", Types: []string{"String"}, Required: false},
+			{Name: "type", Types: []string{"String"}, Required: false, Constraint: &Constraint{Enum: []string{"quiz", "regular"}}},
 			{Name: "allows_multiple_answers", Types: []string{"Boolean"}, Required: false},
 			{Name: "correct_option_id", Types: []string{"Integer"}, Required: false},
-			{Name: "explanation", Types: []string{"String"}, Required: false},
+			{Name: "explanation", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 200, Unit: "characters", Parsed: true}},
 			{Name: "explanation_parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "explanation_entities", Types: []string{"Array of MessageEntity"}, Required: false},
-			{Name: "open_period", Types: []string{"Integer"}, Required: false},
+			{Name: "open_period", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 5, Max: 600}},
 			{Name: "close_date", Types: []string{"Integer"}, Required: false},
 			{Name: "is_closed", Types: []string{"Boolean"}, Required: false},
 			{Name: "disable_notification", Types: []string{"Boolean"}, Required: false},
@@ -1225,6 +1432,7 @@ var Methods = map[string]MethodSpec{
 	"sendSticker": {
 		Name:    "sendSticker",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1244,6 +1452,7 @@ var Methods = map[string]MethodSpec{
 	"sendVenue": {
 		Name:    "sendVenue",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1269,6 +1478,7 @@ var Methods = map[string]MethodSpec{
 	"sendVideo": {
 		Name:    "sendVideo",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1281,7 +1491,7 @@ var Methods = map[string]MethodSpec{
 			{Name: "thumbnail", Types: []string{"InputFile", "String"}, Required: false},
 			{Name: "cover", Types: []string{"InputFile", "String"}, Required: false},
 			{Name: "start_timestamp", Types: []string{"Integer"}, Required: false},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
@@ -1299,6 +1509,7 @@ var Methods = map[string]MethodSpec{
 	"sendVideoNote": {
 		Name:    "sendVideoNote",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1320,13 +1531,14 @@ var Methods = map[string]MethodSpec{
 	"sendVoice": {
 		Name:    "sendVoice",
 		Returns: []string{"Message"},
+		Result:  TypeRef{Name: "Message"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
 			{Name: "direct_messages_topic_id", Types: []string{"Integer"}, Required: false},
 			{Name: "voice", Types: []string{"InputFile", "String"}, Required: true},
-			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
 			{Name: "parse_mode", Types: []string{"String"}, Required: false},
 			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
 			{Name: "duration", Types: []string{"Integer"}, Required: false},
@@ -1342,14 +1554,16 @@ var Methods = map[string]MethodSpec{
 	"setBusinessAccountBio": {
 		Name:    "setBusinessAccountBio",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
-			{Name: "bio", Types: []string{"String"}, Required: false},
+			{Name: "bio", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 140, Unit: "characters"}},
 		},
 	},
 	"setBusinessAccountGiftSettings": {
 		Name:    "setBusinessAccountGiftSettings",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "show_gift_button", Types: []string{"Boolean"}, Required: true},
@@ -1359,15 +1573,17 @@ var Methods = map[string]MethodSpec{
 	"setBusinessAccountName": {
 		Name:    "setBusinessAccountName",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
-			{Name: "first_name", Types: []string{"String"}, Required: true},
-			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "first_name", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+			{Name: "last_name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 64, Unit: "characters"}},
 		},
 	},
 	"setBusinessAccountProfilePhoto": {
 		Name:    "setBusinessAccountProfilePhoto",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "photo", Types: []string{"InputProfilePhoto"}, Required: true},
@@ -1377,31 +1593,35 @@ var Methods = map[string]MethodSpec{
 	"setBusinessAccountUsername": {
 		Name:    "setBusinessAccountUsername",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
-			{Name: "username", Types: []string{"String"}, Required: false},
+			{Name: "username", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 32, Unit: "characters"}},
 		},
 	},
 	"setChatAdministratorCustomTitle": {
 		Name:    "setChatAdministratorCustomTitle",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
-			{Name: "custom_title", Types: []string{"String"}, Required: true},
+			{Name: "custom_title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 0, Max: 16, Unit: "characters"}},
 		},
 	},
 	"setChatDescription": {
 		Name:    "setChatDescription",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 255, Unit: "characters"}},
 		},
 	},
 	"setChatMenuButton": {
 		Name:    "setChatMenuButton",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer"}, Required: false},
 			{Name: "menu_button", Types: []string{"MenuButton"}, Required: false},
@@ -1410,6 +1630,7 @@ var Methods = map[string]MethodSpec{
 	"setChatPermissions": {
 		Name:    "setChatPermissions",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "permissions", Types: []string{"ChatPermissions"}, Required: true},
@@ -1419,6 +1640,7 @@ var Methods = map[string]MethodSpec{
 	"setChatPhoto": {
 		Name:    "setChatPhoto",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "photo", Types: []string{"InputFile"}, Required: true},
@@ -1427,6 +1649,7 @@ var Methods = map[string]MethodSpec{
 	"setChatStickerSet": {
 		Name:    "setChatStickerSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "sticker_set_name", Types: []string{"String"}, Required: true},
@@ -1435,14 +1658,16 @@ var Methods = map[string]MethodSpec{
 	"setChatTitle": {
 		Name:    "setChatTitle",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 128, Unit: "characters"}},
 		},
 	},
 	"setCustomEmojiStickerSetThumbnail": {
 		Name:    "setCustomEmojiStickerSetThumbnail",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "name", Types: []string{"String"}, Required: true},
 			{Name: "custom_emoji_id", Types: []string{"String"}, Required: false},
@@ -1451,6 +1676,7 @@ var Methods = map[string]MethodSpec{
 	"setGameScore": {
 		Name:    "setGameScore",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "score", Types: []string{"Integer"}, Required: true},
@@ -1464,6 +1690,7 @@ var Methods = map[string]MethodSpec{
 	"setMessageReaction": {
 		Name:    "setMessageReaction",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_id", Types: []string{"Integer"}, Required: true},
@@ -1474,6 +1701,7 @@ var Methods = map[string]MethodSpec{
 	"setMyCommands": {
 		Name:    "setMyCommands",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "commands", Types: []string{"Array of BotCommand"}, Required: true},
 			{Name: "scope", Types: []string{"BotCommandScope"}, Required: false},
@@ -1483,6 +1711,7 @@ var Methods = map[string]MethodSpec{
 	"setMyDefaultAdministratorRights": {
 		Name:    "setMyDefaultAdministratorRights",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "rights", Types: []string{"ChatAdministratorRights"}, Required: false},
 			{Name: "for_channels", Types: []string{"Boolean"}, Required: false},
@@ -1491,30 +1720,34 @@ var Methods = map[string]MethodSpec{
 	"setMyDescription": {
 		Name:    "setMyDescription",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
-			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 512, Unit: "characters"}},
 			{Name: "language_code", Types: []string{"String"}, Required: false},
 		},
 	},
 	"setMyName": {
 		Name:    "setMyName",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
-			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "name", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 64, Unit: "characters"}},
 			{Name: "language_code", Types: []string{"String"}, Required: false},
 		},
 	},
 	"setMyShortDescription": {
 		Name:    "setMyShortDescription",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
-			{Name: "short_description", Types: []string{"String"}, Required: false},
+			{Name: "short_description", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 120, Unit: "characters"}},
 			{Name: "language_code", Types: []string{"String"}, Required: false},
 		},
 	},
 	"setPassportDataErrors": {
 		Name:    "setPassportDataErrors",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "errors", Types: []string{"Array of PassportElementError"}, Required: true},
@@ -1523,22 +1756,25 @@ var Methods = map[string]MethodSpec{
 	"setStickerEmojiList": {
 		Name:    "setStickerEmojiList",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "sticker", Types: []string{"String"}, Required: true},
-			{Name: "emoji_list", Types: []string{"Array of String"}, Required: true},
+			{Name: "emoji_list", Types: []string{"Array of String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 20, Unit: "items"}},
 		},
 	},
 	"setStickerKeywords": {
 		Name:    "setStickerKeywords",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "sticker", Types: []string{"String"}, Required: true},
-			{Name: "keywords", Types: []string{"Array of String"}, Required: false},
+			{Name: "keywords", Types: []string{"Array of String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 20, Unit: "items"}},
 		},
 	},
 	"setStickerMaskPosition": {
 		Name:    "setStickerMaskPosition",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "sticker", Types: []string{"String"}, Required: true},
 			{Name: "mask_position", Types: []string{"MaskPosition"}, Required: false},
@@ -1547,6 +1783,7 @@ var Methods = map[string]MethodSpec{
 	"setStickerPositionInSet": {
 		Name:    "setStickerPositionInSet",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "sticker", Types: []string{"String"}, Required: true},
 			{Name: "position", Types: []string{"Integer"}, Required: true},
@@ -1555,24 +1792,27 @@ var Methods = map[string]MethodSpec{
 	"setStickerSetThumbnail": {
 		Name:    "setStickerSetThumbnail",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "name", Types: []string{"String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "thumbnail", Types: []string{"InputFile", "String"}, Required: false},
-			{Name: "format", Types: []string{"String"}, Required: true},
+			{Name: "format", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"static", "animated", "video"}}},
 		},
 	},
 	"setStickerSetTitle": {
 		Name:    "setStickerSetTitle",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "name", Types: []string{"String"}, Required: true},
-			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
 		},
 	},
 	"setUserEmojiStatus": {
 		Name:    "setUserEmojiStatus",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "emoji_status_custom_emoji_id", Types: []string{"String"}, Required: false},
@@ -1582,19 +1822,21 @@ var Methods = map[string]MethodSpec{
 	"setWebhook": {
 		Name:    "setWebhook",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "url", Types: []string{"String"}, Required: true},
 			{Name: "certificate", Types: []string{"InputFile"}, Required: false},
 			{Name: "ip_address", Types: []string{"String"}, Required: false},
-			{Name: "max_connections", Types: []string{"Integer"}, Required: false},
+			{Name: "max_connections", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 100}},
 			{Name: "allowed_updates", Types: []string{"Array of String"}, Required: false},
 			{Name: "drop_pending_updates", Types: []string{"Boolean"}, Required: false},
-			{Name: "secret_token", Types: []string{"String"}, Required: false},
+			{Name: "secret_token", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 1, Max: 256, Unit: "characters"}},
 		},
 	},
 	"stopMessageLiveLocation": {
 		Name:    "stopMessageLiveLocation",
 		Returns: []string{"Message", "Boolean"},
+		Result:  TypeRef{Union: []TypeRef{TypeRef{Name: "Message"}, TypeRef{Name: "Boolean"}}},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
@@ -1606,6 +1848,7 @@ var Methods = map[string]MethodSpec{
 	"stopPoll": {
 		Name:    "stopPoll",
 		Returns: []string{"Poll"},
+		Result:  TypeRef{Name: "Poll"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1616,14 +1859,16 @@ var Methods = map[string]MethodSpec{
 	"transferBusinessAccountStars": {
 		Name:    "transferBusinessAccountStars",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
-			{Name: "star_count", Types: []string{"Integer"}, Required: true},
+			{Name: "star_count", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 10000}},
 		},
 	},
 	"transferGift": {
 		Name:    "transferGift",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "owned_gift_id", Types: []string{"String"}, Required: true},
@@ -1634,6 +1879,7 @@ var Methods = map[string]MethodSpec{
 	"unbanChatMember": {
 		Name:    "unbanChatMember",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
@@ -1643,6 +1889,7 @@ var Methods = map[string]MethodSpec{
 	"unbanChatSenderChat": {
 		Name:    "unbanChatSenderChat",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "sender_chat_id", Types: []string{"Integer"}, Required: true},
@@ -1651,6 +1898,7 @@ var Methods = map[string]MethodSpec{
 	"unhideGeneralForumTopic": {
 		Name:    "unhideGeneralForumTopic",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -1658,6 +1906,7 @@ var Methods = map[string]MethodSpec{
 	"unpinAllChatMessages": {
 		Name:    "unpinAllChatMessages",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -1665,6 +1914,7 @@ var Methods = map[string]MethodSpec{
 	"unpinAllForumTopicMessages": {
 		Name:    "unpinAllForumTopicMessages",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 			{Name: "message_thread_id", Types: []string{"Integer"}, Required: true},
@@ -1673,6 +1923,7 @@ var Methods = map[string]MethodSpec{
 	"unpinAllGeneralForumTopicMessages": {
 		Name:    "unpinAllGeneralForumTopicMessages",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
 		},
@@ -1680,6 +1931,7 @@ var Methods = map[string]MethodSpec{
 	"unpinChatMessage": {
 		Name:    "unpinChatMessage",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
@@ -1689,6 +1941,7 @@ var Methods = map[string]MethodSpec{
 	"upgradeGift": {
 		Name:    "upgradeGift",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
 			{Name: "owned_gift_id", Types: []string{"String"}, Required: true},
@@ -1699,26 +1952,29 @@ var Methods = map[string]MethodSpec{
 	"uploadStickerFile": {
 		Name:    "uploadStickerFile",
 		Returns: []string{"File"},
+		Result:  TypeRef{Name: "File"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
 			{Name: "sticker", Types: []string{"InputFile"}, Required: true},
-			{Name: "sticker_format", Types: []string{"String"}, Required: true},
+			{Name: "sticker_format", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"static", "animated", "video"}}},
 		},
 	},
 	"verifyChat": {
 		Name:    "verifyChat",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
-			{Name: "custom_description", Types: []string{"String"}, Required: false},
+			{Name: "custom_description", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 70, Unit: "characters"}},
 		},
 	},
 	"verifyUser": {
 		Name:    "verifyUser",
 		Returns: []string{"Boolean"},
+		Result:  TypeRef{Name: "Boolean"},
 		Fields: []FieldSpec{
 			{Name: "user_id", Types: []string{"Integer"}, Required: true},
-			{Name: "custom_description", Types: []string{"String"}, Required: false},
+			{Name: "custom_description", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 70, Unit: "characters"}},
 		},
 	},
 }
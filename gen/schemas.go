@@ -0,0 +1,20 @@
+// Code generated by codegen. DO NOT EDIT.
+package gen
+
+import "embed"
+
+// schemas holds the JSON Schema document of each Bot API type. Schemas
+// refer to each other by file name, as in "Message.json".
+//
+//go:embed schema/*.json
+var schemas embed.FS
+
+// Schema returns the JSON Schema document of the Bot API type name, and
+// whether there is such a type.
+func Schema(name string) ([]byte, bool) {
+	if _, ok := Types[name]; !ok {
+		return nil, false
+	}
+	data, err := schemas.ReadFile("schema/" + name + ".json")
+	return data, err == nil
+}
@@ -20,8 +20,8 @@ type AffiliateInfo struct {
 
 // Animation This object represents an animation file (GIF or H.264/MPEG-4 AVC video without sound).
 type Animation struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Width int64 `json:"width"`
 	Height int64 `json:"height"`
 	Duration int64 `json:"duration"`
@@ -33,8 +33,8 @@ type Animation struct {
 
 // Audio This object represents an audio file to be treated as music by the Telegram clients.
 type Audio struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Duration int64 `json:"duration"`
 	Performer string `json:"performer,omitempty"`
 	Title string `json:"title,omitempty"`
@@ -45,7 +45,8 @@ type Audio struct {
 }
 
 // BackgroundFill This object describes the way a background is filled based on the selected colors. Currently, it can be one of
-type BackgroundFill struct {
+type BackgroundFill interface {
+	isBackgroundFill()
 }
 
 // BackgroundFillFreeformGradient The background is a freeform gradient that rotates after every message in the chat.
@@ -54,6 +55,8 @@ type BackgroundFillFreeformGradient struct {
 	Colors []int64 `json:"colors"`
 }
 
+func (*BackgroundFillFreeformGradient) isBackgroundFill() {}
+
 // BackgroundFillGradient The background is a gradient fill.
 type BackgroundFillGradient struct {
 	Type string `json:"type"`
@@ -62,14 +65,19 @@ type BackgroundFillGradient struct {
 	RotationAngle int64 `json:"rotation_angle"`
 }
 
+func (*BackgroundFillGradient) isBackgroundFill() {}
+
 // BackgroundFillSolid The background is filled using the selected color.
 type BackgroundFillSolid struct {
 	Type string `json:"type"`
 	Color int64 `json:"color"`
 }
 
+func (*BackgroundFillSolid) isBackgroundFill() {}
+
 // BackgroundType This object describes the type of a background. Currently, it can be one of
-type BackgroundType struct {
+type BackgroundType interface {
+	isBackgroundType()
 }
 
 // BackgroundTypeChatTheme The background is taken directly from a built-in chat theme.
@@ -78,6 +86,8 @@ type BackgroundTypeChatTheme struct {
 	ThemeName string `json:"theme_name"`
 }
 
+func (*BackgroundTypeChatTheme) isBackgroundType() {}
+
 // BackgroundTypeFill The background is automatically filled based on the selected colors.
 type BackgroundTypeFill struct {
 	Type string `json:"type"`
@@ -85,6 +95,8 @@ type BackgroundTypeFill struct {
 	DarkThemeDimming int64 `json:"dark_theme_dimming"`
 }
 
+func (*BackgroundTypeFill) isBackgroundType() {}
+
 // BackgroundTypePattern The background is a .PNG or .TGV (gzipped subset of SVG with MIME type "application/x-tgwallpattern") pattern to be combined with the background fill chosen by the user.
 type BackgroundTypePattern struct {
 	Type string `json:"type"`
@@ -95,6 +107,8 @@ type BackgroundTypePattern struct {
 	IsMoving *bool `json:"is_moving,omitempty"`
 }
 
+func (*BackgroundTypePattern) isBackgroundType() {}
+
 // BackgroundTypeWallpaper The background is a wallpaper in the JPEG format.
 type BackgroundTypeWallpaper struct {
 	Type string `json:"type"`
@@ -104,6 +118,8 @@ type BackgroundTypeWallpaper struct {
 	IsMoving *bool `json:"is_moving,omitempty"`
 }
 
+func (*BackgroundTypeWallpaper) isBackgroundType() {}
+
 // Birthdate Describes the birthdate of a user.
 type Birthdate struct {
 	Day int64 `json:"day"`
@@ -118,7 +134,8 @@ type BotCommand struct {
 }
 
 // BotCommandScope This object represents the scope to which bot commands are applied. Currently, the following 7 scopes are supported:
-type BotCommandScope struct {
+type BotCommandScope interface {
+	isBotCommandScope()
 }
 
 // BotCommandScopeAllChatAdministrators Represents the scope of bot commands, covering all group and supergroup chat administrators.
@@ -126,40 +143,54 @@ type BotCommandScopeAllChatAdministrators struct {
 	Type string `json:"type"`
 }
 
+func (*BotCommandScopeAllChatAdministrators) isBotCommandScope() {}
+
 // BotCommandScopeAllGroupChats Represents the scope of bot commands, covering all group and supergroup chats.
 type BotCommandScopeAllGroupChats struct {
 	Type string `json:"type"`
 }
 
+func (*BotCommandScopeAllGroupChats) isBotCommandScope() {}
+
 // BotCommandScopeAllPrivateChats Represents the scope of bot commands, covering all private chats.
 type BotCommandScopeAllPrivateChats struct {
 	Type string `json:"type"`
 }
 
+func (*BotCommandScopeAllPrivateChats) isBotCommandScope() {}
+
 // BotCommandScopeChat Represents the scope of bot commands, covering a specific chat.
 type BotCommandScopeChat struct {
 	Type string `json:"type"`
-	ChatId int64 `json:"chat_id"`
+	ChatID int64 `json:"chat_id"`
 }
 
+func (*BotCommandScopeChat) isBotCommandScope() {}
+
 // BotCommandScopeChatAdministrators Represents the scope of bot commands, covering all administrators of a specific group or supergroup chat.
 type BotCommandScopeChatAdministrators struct {
 	Type string `json:"type"`
-	ChatId int64 `json:"chat_id"`
+	ChatID int64 `json:"chat_id"`
 }
 
+func (*BotCommandScopeChatAdministrators) isBotCommandScope() {}
+
 // BotCommandScopeChatMember Represents the scope of bot commands, covering a specific member of a group or supergroup chat.
 type BotCommandScopeChatMember struct {
 	Type string `json:"type"`
-	ChatId int64 `json:"chat_id"`
-	UserId int64 `json:"user_id"`
+	ChatID int64 `json:"chat_id"`
+	UserID int64 `json:"user_id"`
 }
 
+func (*BotCommandScopeChatMember) isBotCommandScope() {}
+
 // BotCommandScopeDefault Represents the default scope of bot commands. Default commands are used if no commands with a narrower scope are specified for the user.
 type BotCommandScopeDefault struct {
 	Type string `json:"type"`
 }
 
+func (*BotCommandScopeDefault) isBotCommandScope() {}
+
 // BotDescription This object represents the bot's description.
 type BotDescription struct {
 	Description string `json:"description"`
@@ -195,9 +226,9 @@ type BusinessBotRights struct {
 
 // BusinessConnection Describes the connection of the bot with a business account.
 type BusinessConnection struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	User User `json:"user"`
-	UserChatId int64 `json:"user_chat_id"`
+	UserChatID int64 `json:"user_chat_id"`
 	Date int64 `json:"date"`
 	Rights *BusinessBotRights `json:"rights,omitempty"`
 	IsEnabled bool `json:"is_enabled"`
@@ -218,7 +249,7 @@ type BusinessLocation struct {
 
 // BusinessMessagesDeleted This object is received when messages are deleted from a connected business account.
 type BusinessMessagesDeleted struct {
-	BusinessConnectionId string `json:"business_connection_id"`
+	BusinessConnectionID string `json:"business_connection_id"`
 	Chat Chat `json:"chat"`
 	MessageIds []int64 `json:"message_ids"`
 }
@@ -241,10 +272,10 @@ type CallbackGame struct {
 
 // CallbackQuery This object represents an incoming callback query from a callback button in an inline keyboard. If the button that originated the query was attached to a message sent by the bot, the field message will be present. If the button was attached to a message sent via the bot (in inline mode), the field inline_message_id will be present. Exactly one of the fields data or game_short_name will be present.
 type CallbackQuery struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	From User `json:"from"`
-	Message *MaybeInaccessibleMessage `json:"message,omitempty"`
-	InlineMessageId string `json:"inline_message_id,omitempty"`
+	Message MaybeInaccessibleMessage `json:"message,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
 	ChatInstance string `json:"chat_instance"`
 	Data string `json:"data,omitempty"`
 	GameShortName string `json:"game_short_name,omitempty"`
@@ -252,7 +283,7 @@ type CallbackQuery struct {
 
 // Chat This object represents a chat.
 type Chat struct {
-	Id int64 `json:"id"`
+	ID int64 `json:"id"`
 	Type string `json:"type"`
 	Title string `json:"title,omitempty"`
 	Username string `json:"username,omitempty"`
@@ -289,7 +320,7 @@ type ChatBackground struct {
 
 // ChatBoost This object contains information about a chat boost.
 type ChatBoost struct {
-	BoostId string `json:"boost_id"`
+	BoostID string `json:"boost_id"`
 	AddDate int64 `json:"add_date"`
 	ExpirationDate int64 `json:"expiration_date"`
 	Source ChatBoostSource `json:"source"`
@@ -303,13 +334,14 @@ type ChatBoostAdded struct {
 // ChatBoostRemoved This object represents a boost removed from a chat.
 type ChatBoostRemoved struct {
 	Chat Chat `json:"chat"`
-	BoostId string `json:"boost_id"`
+	BoostID string `json:"boost_id"`
 	RemoveDate int64 `json:"remove_date"`
 	Source ChatBoostSource `json:"source"`
 }
 
 // ChatBoostSource This object describes the source of a chat boost. It can be one of
-type ChatBoostSource struct {
+type ChatBoostSource interface {
+	isChatBoostSource()
 }
 
 // ChatBoostSourceGiftCode The boost was obtained by the creation of Telegram Premium gift codes to boost a chat. Each such code boosts the chat 4 times for the duration of the corresponding Telegram Premium subscription.
@@ -318,21 +350,27 @@ type ChatBoostSourceGiftCode struct {
 	User User `json:"user"`
 }
 
+func (*ChatBoostSourceGiftCode) isChatBoostSource() {}
+
 // ChatBoostSourceGiveaway The boost was obtained by the creation of a Telegram Premium or a Telegram Star giveaway. This boosts the chat 4 times for the duration of the corresponding Telegram Premium subscription for Telegram Premium giveaways and prize_star_count / 500 times for one year for Telegram Star giveaways.
 type ChatBoostSourceGiveaway struct {
 	Source string `json:"source"`
-	GiveawayMessageId int64 `json:"giveaway_message_id"`
+	GiveawayMessageID int64 `json:"giveaway_message_id"`
 	User *User `json:"user,omitempty"`
 	PrizeStarCount *int64 `json:"prize_star_count,omitempty"`
 	IsUnclaimed *bool `json:"is_unclaimed,omitempty"`
 }
 
+func (*ChatBoostSourceGiveaway) isChatBoostSource() {}
+
 // ChatBoostSourcePremium The boost was obtained by subscribing to Telegram Premium or by gifting a Telegram Premium subscription to another user.
 type ChatBoostSourcePremium struct {
 	Source string `json:"source"`
 	User User `json:"user"`
 }
 
+func (*ChatBoostSourcePremium) isChatBoostSource() {}
+
 // ChatBoostUpdated This object represents a boost added to a chat or changed.
 type ChatBoostUpdated struct {
 	Chat Chat `json:"chat"`
@@ -341,7 +379,7 @@ type ChatBoostUpdated struct {
 
 // ChatFullInfo This object contains full information about a chat.
 type ChatFullInfo struct {
-	Id int64 `json:"id"`
+	ID int64 `json:"id"`
 	Type string `json:"type"`
 	Title string `json:"title,omitempty"`
 	Username string `json:"username,omitempty"`
@@ -349,7 +387,7 @@ type ChatFullInfo struct {
 	LastName string `json:"last_name,omitempty"`
 	IsForum *bool `json:"is_forum,omitempty"`
 	IsDirectMessages *bool `json:"is_direct_messages,omitempty"`
-	AccentColorId int64 `json:"accent_color_id"`
+	AccentColorID int64 `json:"accent_color_id"`
 	MaxReactionCount int64 `json:"max_reaction_count"`
 	Photo *ChatPhoto `json:"photo,omitempty"`
 	ActiveUsernames []string `json:"active_usernames,omitempty"`
@@ -360,10 +398,10 @@ type ChatFullInfo struct {
 	PersonalChat *Chat `json:"personal_chat,omitempty"`
 	ParentChat *Chat `json:"parent_chat,omitempty"`
 	AvailableReactions []ReactionType `json:"available_reactions,omitempty"`
-	BackgroundCustomEmojiId string `json:"background_custom_emoji_id,omitempty"`
-	ProfileAccentColorId *int64 `json:"profile_accent_color_id,omitempty"`
-	ProfileBackgroundCustomEmojiId string `json:"profile_background_custom_emoji_id,omitempty"`
-	EmojiStatusCustomEmojiId string `json:"emoji_status_custom_emoji_id,omitempty"`
+	BackgroundCustomEmojiID string `json:"background_custom_emoji_id,omitempty"`
+	ProfileAccentColorID *int64 `json:"profile_accent_color_id,omitempty"`
+	ProfileBackgroundCustomEmojiID string `json:"profile_background_custom_emoji_id,omitempty"`
+	EmojiStatusCustomEmojiID string `json:"emoji_status_custom_emoji_id,omitempty"`
 	EmojiStatusExpirationDate *int64 `json:"emoji_status_expiration_date,omitempty"`
 	Bio string `json:"bio,omitempty"`
 	HasPrivateForwards *bool `json:"has_private_forwards,omitempty"`
@@ -386,7 +424,7 @@ type ChatFullInfo struct {
 	StickerSetName string `json:"sticker_set_name,omitempty"`
 	CanSetStickerSet *bool `json:"can_set_sticker_set,omitempty"`
 	CustomEmojiStickerSetName string `json:"custom_emoji_sticker_set_name,omitempty"`
-	LinkedChatId *int64 `json:"linked_chat_id,omitempty"`
+	LinkedChatID *int64 `json:"linked_chat_id,omitempty"`
 	Location *ChatLocation `json:"location,omitempty"`
 }
 
@@ -409,7 +447,7 @@ type ChatInviteLink struct {
 type ChatJoinRequest struct {
 	Chat Chat `json:"chat"`
 	From User `json:"from"`
-	UserChatId int64 `json:"user_chat_id"`
+	UserChatID int64 `json:"user_chat_id"`
 	Date int64 `json:"date"`
 	Bio string `json:"bio,omitempty"`
 	InviteLink *ChatInviteLink `json:"invite_link,omitempty"`
@@ -422,7 +460,8 @@ type ChatLocation struct {
 }
 
 // ChatMember This object contains information about one member of a chat. Currently, the following 6 types of chat members are supported:
-type ChatMember struct {
+type ChatMember interface {
+	isChatMember()
 }
 
 // ChatMemberAdministrator Represents a chat member that has some additional privileges.
@@ -449,6 +488,8 @@ type ChatMemberAdministrator struct {
 	CustomTitle string `json:"custom_title,omitempty"`
 }
 
+func (*ChatMemberAdministrator) isChatMember() {}
+
 // ChatMemberBanned Represents a chat member that was banned in the chat and can't return to the chat or view chat messages.
 type ChatMemberBanned struct {
 	Status string `json:"status"`
@@ -456,12 +497,16 @@ type ChatMemberBanned struct {
 	UntilDate int64 `json:"until_date"`
 }
 
+func (*ChatMemberBanned) isChatMember() {}
+
 // ChatMemberLeft Represents a chat member that isn't currently a member of the chat, but may join it themselves.
 type ChatMemberLeft struct {
 	Status string `json:"status"`
 	User User `json:"user"`
 }
 
+func (*ChatMemberLeft) isChatMember() {}
+
 // ChatMemberMember Represents a chat member that has no additional privileges or restrictions.
 type ChatMemberMember struct {
 	Status string `json:"status"`
@@ -469,6 +514,8 @@ type ChatMemberMember struct {
 	UntilDate *int64 `json:"until_date,omitempty"`
 }
 
+func (*ChatMemberMember) isChatMember() {}
+
 // ChatMemberOwner Represents a chat member that owns the chat and has all administrator privileges.
 type ChatMemberOwner struct {
 	Status string `json:"status"`
@@ -477,6 +524,8 @@ type ChatMemberOwner struct {
 	CustomTitle string `json:"custom_title,omitempty"`
 }
 
+func (*ChatMemberOwner) isChatMember() {}
+
 // ChatMemberRestricted Represents a chat member that is under certain restrictions in the chat. Supergroups only.
 type ChatMemberRestricted struct {
 	Status string `json:"status"`
@@ -499,6 +548,8 @@ type ChatMemberRestricted struct {
 	UntilDate int64 `json:"until_date"`
 }
 
+func (*ChatMemberRestricted) isChatMember() {}
+
 // ChatMemberUpdated This object represents changes in the status of a chat member.
 type ChatMemberUpdated struct {
 	Chat Chat `json:"chat"`
@@ -531,16 +582,16 @@ type ChatPermissions struct {
 
 // ChatPhoto This object represents a chat photo.
 type ChatPhoto struct {
-	SmallFileId string `json:"small_file_id"`
-	SmallFileUniqueId string `json:"small_file_unique_id"`
-	BigFileId string `json:"big_file_id"`
-	BigFileUniqueId string `json:"big_file_unique_id"`
+	SmallFileID string `json:"small_file_id"`
+	SmallFileUniqueID string `json:"small_file_unique_id"`
+	BigFileID string `json:"big_file_id"`
+	BigFileUniqueID string `json:"big_file_unique_id"`
 }
 
 // ChatShared This object contains information about a chat that was shared with the bot using a KeyboardButtonRequestChat button.
 type ChatShared struct {
-	RequestId int64 `json:"request_id"`
-	ChatId int64 `json:"chat_id"`
+	RequestID int64 `json:"request_id"`
+	ChatID int64 `json:"chat_id"`
 	Title string `json:"title,omitempty"`
 	Username string `json:"username,omitempty"`
 	Photo []PhotoSize `json:"photo,omitempty"`
@@ -557,7 +608,7 @@ type Checklist struct {
 
 // ChecklistTask Describes a task in a checklist.
 type ChecklistTask struct {
-	Id int64 `json:"id"`
+	ID int64 `json:"id"`
 	Text string `json:"text"`
 	TextEntities []MessageEntity `json:"text_entities,omitempty"`
 	CompletedByUser *User `json:"completed_by_user,omitempty"`
@@ -579,10 +630,10 @@ type ChecklistTasksDone struct {
 
 // ChosenInlineResult Represents a result of an inline query that was chosen by the user and sent to their chat partner.
 type ChosenInlineResult struct {
-	ResultId string `json:"result_id"`
+	ResultID string `json:"result_id"`
 	From User `json:"from"`
 	Location *Location `json:"location,omitempty"`
-	InlineMessageId string `json:"inline_message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
 	Query string `json:"query"`
 }
 
@@ -591,7 +642,7 @@ type Contact struct {
 	PhoneNumber string `json:"phone_number"`
 	FirstName string `json:"first_name"`
 	LastName string `json:"last_name,omitempty"`
-	UserId *int64 `json:"user_id,omitempty"`
+	UserID *int64 `json:"user_id,omitempty"`
 	Vcard string `json:"vcard,omitempty"`
 }
 
@@ -614,14 +665,14 @@ type DirectMessagePriceChanged struct {
 
 // DirectMessagesTopic Describes a topic of a direct messages chat.
 type DirectMessagesTopic struct {
-	TopicId int64 `json:"topic_id"`
+	TopicID int64 `json:"topic_id"`
 	User *User `json:"user,omitempty"`
 }
 
 // Document This object represents a general file (as opposed to photos, voice messages and audio files).
 type Document struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Thumbnail *PhotoSize `json:"thumbnail,omitempty"`
 	FileName string `json:"file_name,omitempty"`
 	MimeType string `json:"mime_type,omitempty"`
@@ -653,7 +704,7 @@ type EncryptedPassportElement struct {
 type ExternalReplyInfo struct {
 	Origin MessageOrigin `json:"origin"`
 	Chat *Chat `json:"chat,omitempty"`
-	MessageId *int64 `json:"message_id,omitempty"`
+	MessageID *int64 `json:"message_id,omitempty"`
 	LinkPreviewOptions *LinkPreviewOptions `json:"link_preview_options,omitempty"`
 	Animation *Animation `json:"animation,omitempty"`
 	Audio *Audio `json:"audio,omitempty"`
@@ -680,8 +731,8 @@ type ExternalReplyInfo struct {
 
 // File This object represents a file ready to be downloaded. The file can be downloaded via the link https://api.telegram.org/file/bot<token>/<file_path>. It is guaranteed that the link will be valid for at least 1 hour. When the link expires, a new one can be requested by calling getFile.
 type File struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	FileSize *int64 `json:"file_size,omitempty"`
 	FilePath string `json:"file_path,omitempty"`
 }
@@ -695,10 +746,10 @@ type ForceReply struct {
 
 // ForumTopic This object represents a forum topic.
 type ForumTopic struct {
-	MessageThreadId int64 `json:"message_thread_id"`
+	MessageThreadID int64 `json:"message_thread_id"`
 	Name string `json:"name"`
 	IconColor int64 `json:"icon_color"`
-	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
 }
 
 // ForumTopicClosed This object represents a service message about a forum topic closed in the chat. Currently holds no information.
@@ -709,13 +760,13 @@ type ForumTopicClosed struct {
 type ForumTopicCreated struct {
 	Name string `json:"name"`
 	IconColor int64 `json:"icon_color"`
-	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
 }
 
 // ForumTopicEdited This object represents a service message about an edited forum topic.
 type ForumTopicEdited struct {
 	Name string `json:"name,omitempty"`
-	IconCustomEmojiId string `json:"icon_custom_emoji_id,omitempty"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
 }
 
 // ForumTopicReopened This object represents a service message about a forum topic reopened in the chat. Currently holds no information.
@@ -749,7 +800,7 @@ type GeneralForumTopicUnhidden struct {
 
 // Gift This object represents a gift that can be sent by the bot.
 type Gift struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Sticker Sticker `json:"sticker"`
 	StarCount int64 `json:"star_count"`
 	UpgradeStarCount *int64 `json:"upgrade_star_count,omitempty"`
@@ -761,7 +812,7 @@ type Gift struct {
 // GiftInfo Describes a service message about a regular gift that was sent or received.
 type GiftInfo struct {
 	Gift Gift `json:"gift"`
-	OwnedGiftId string `json:"owned_gift_id,omitempty"`
+	OwnedGiftID string `json:"owned_gift_id,omitempty"`
 	ConvertStarCount *int64 `json:"convert_star_count,omitempty"`
 	PrepaidUpgradeStarCount *int64 `json:"prepaid_upgrade_star_count,omitempty"`
 	CanBeUpgraded *bool `json:"can_be_upgraded,omitempty"`
@@ -804,7 +855,7 @@ type GiveawayCreated struct {
 // GiveawayWinners This object represents a message about the completion of a giveaway with public winners.
 type GiveawayWinners struct {
 	Chat Chat `json:"chat"`
-	GiveawayMessageId int64 `json:"giveaway_message_id"`
+	GiveawayMessageID int64 `json:"giveaway_message_id"`
 	WinnersSelectionDate int64 `json:"winners_selection_date"`
 	WinnerCount int64 `json:"winner_count"`
 	Winners []User `json:"winners"`
@@ -820,17 +871,19 @@ type GiveawayWinners struct {
 // InaccessibleMessage This object describes a message that was deleted or is otherwise inaccessible to the bot.
 type InaccessibleMessage struct {
 	Chat Chat `json:"chat"`
-	MessageId int64 `json:"message_id"`
+	MessageID int64 `json:"message_id"`
 	Date int64 `json:"date"`
 }
 
+func (*InaccessibleMessage) isMaybeInaccessibleMessage() {}
+
 // InlineKeyboardButton This object represents one button of an inline keyboard. Exactly one of the optional fields must be used to specify type of the button.
 type InlineKeyboardButton struct {
 	Text string `json:"text"`
-	Url string `json:"url,omitempty"`
+	URL string `json:"url,omitempty"`
 	CallbackData string `json:"callback_data,omitempty"`
 	WebApp *WebAppInfo `json:"web_app,omitempty"`
-	LoginUrl *LoginUrl `json:"login_url,omitempty"`
+	LoginURL *LoginUrl `json:"login_url,omitempty"`
 	SwitchInlineQuery string `json:"switch_inline_query,omitempty"`
 	SwitchInlineQueryCurrentChat string `json:"switch_inline_query_current_chat,omitempty"`
 	SwitchInlineQueryChosenChat *SwitchInlineQueryChosenChat `json:"switch_inline_query_chosen_chat,omitempty"`
@@ -846,7 +899,7 @@ type InlineKeyboardMarkup struct {
 
 // InlineQuery This object represents an incoming inline query. When the user sends an empty query, your bot could return some default or trending results.
 type InlineQuery struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	From User `json:"from"`
 	Query string `json:"query"`
 	Offset string `json:"offset"`
@@ -855,28 +908,31 @@ type InlineQuery struct {
 }
 
 // InlineQueryResult This object represents one result of an inline query. Telegram clients currently support results of the following 20 types:
-type InlineQueryResult struct {
+type InlineQueryResult interface {
+	isInlineQueryResult()
 }
 
 // InlineQueryResultArticle Represents a link to an article or web page.
 type InlineQueryResultArticle struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Title string `json:"title"`
 	InputMessageContent InputMessageContent `json:"input_message_content"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	Url string `json:"url,omitempty"`
+	URL string `json:"url,omitempty"`
 	Description string `json:"description,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 	ThumbnailWidth *int64 `json:"thumbnail_width,omitempty"`
 	ThumbnailHeight *int64 `json:"thumbnail_height,omitempty"`
 }
 
+func (*InlineQueryResultArticle) isInlineQueryResult() {}
+
 // InlineQueryResultAudio Represents a link to an MP3 audio file. By default, this audio file will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the audio.
 type InlineQueryResultAudio struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	AudioUrl string `json:"audio_url"`
+	ID string `json:"id"`
+	AudioURL string `json:"audio_url"`
 	Title string `json:"title"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
@@ -884,68 +940,78 @@ type InlineQueryResultAudio struct {
 	Performer string `json:"performer,omitempty"`
 	AudioDuration *int64 `json:"audio_duration,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultAudio) isInlineQueryResult() {}
+
 // InlineQueryResultCachedAudio Represents a link to an MP3 audio file stored on the Telegram servers. By default, this audio file will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the audio.
 type InlineQueryResultCachedAudio struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	AudioFileId string `json:"audio_file_id"`
+	ID string `json:"id"`
+	AudioFileID string `json:"audio_file_id"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedAudio) isInlineQueryResult() {}
+
 // InlineQueryResultCachedDocument Represents a link to a file stored on the Telegram servers. By default, this file will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the file.
 type InlineQueryResultCachedDocument struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Title string `json:"title"`
-	DocumentFileId string `json:"document_file_id"`
+	DocumentFileID string `json:"document_file_id"`
 	Description string `json:"description,omitempty"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedDocument) isInlineQueryResult() {}
+
 // InlineQueryResultCachedGif Represents a link to an animated GIF file stored on the Telegram servers. By default, this animated GIF file will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with specified content instead of the animation.
 type InlineQueryResultCachedGif struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	GifFileId string `json:"gif_file_id"`
+	ID string `json:"id"`
+	GifFileID string `json:"gif_file_id"`
 	Title string `json:"title,omitempty"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedGif) isInlineQueryResult() {}
+
 // InlineQueryResultCachedMpeg4Gif Represents a link to a video animation (H.264/MPEG-4 AVC video without sound) stored on the Telegram servers. By default, this animated MPEG-4 file will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the animation.
 type InlineQueryResultCachedMpeg4Gif struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	Mpeg4FileId string `json:"mpeg4_file_id"`
+	ID string `json:"id"`
+	Mpeg4FileID string `json:"mpeg4_file_id"`
 	Title string `json:"title,omitempty"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedMpeg4Gif) isInlineQueryResult() {}
+
 // InlineQueryResultCachedPhoto Represents a link to a photo stored on the Telegram servers. By default, this photo will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the photo.
 type InlineQueryResultCachedPhoto struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	PhotoFileId string `json:"photo_file_id"`
+	ID string `json:"id"`
+	PhotoFileID string `json:"photo_file_id"`
 	Title string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
 	Caption string `json:"caption,omitempty"`
@@ -953,23 +1019,27 @@ type InlineQueryResultCachedPhoto struct {
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedPhoto) isInlineQueryResult() {}
+
 // InlineQueryResultCachedSticker Represents a link to a sticker stored on the Telegram servers. By default, this sticker will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the sticker.
 type InlineQueryResultCachedSticker struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	StickerFileId string `json:"sticker_file_id"`
+	ID string `json:"id"`
+	StickerFileID string `json:"sticker_file_id"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedSticker) isInlineQueryResult() {}
+
 // InlineQueryResultCachedVideo Represents a link to a video file stored on the Telegram servers. By default, this video file will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the video.
 type InlineQueryResultCachedVideo struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	VideoFileId string `json:"video_file_id"`
+	ID string `json:"id"`
+	VideoFileID string `json:"video_file_id"`
 	Title string `json:"title"`
 	Description string `json:"description,omitempty"`
 	Caption string `json:"caption,omitempty"`
@@ -977,72 +1047,82 @@ type InlineQueryResultCachedVideo struct {
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedVideo) isInlineQueryResult() {}
+
 // InlineQueryResultCachedVoice Represents a link to a voice message stored on the Telegram servers. By default, this voice message will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the voice message.
 type InlineQueryResultCachedVoice struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	VoiceFileId string `json:"voice_file_id"`
+	ID string `json:"id"`
+	VoiceFileID string `json:"voice_file_id"`
 	Title string `json:"title"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultCachedVoice) isInlineQueryResult() {}
+
 // InlineQueryResultContact Represents a contact with a phone number. By default, this contact will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the contact.
 type InlineQueryResultContact struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	PhoneNumber string `json:"phone_number"`
 	FirstName string `json:"first_name"`
 	LastName string `json:"last_name,omitempty"`
 	Vcard string `json:"vcard,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 	ThumbnailWidth *int64 `json:"thumbnail_width,omitempty"`
 	ThumbnailHeight *int64 `json:"thumbnail_height,omitempty"`
 }
 
+func (*InlineQueryResultContact) isInlineQueryResult() {}
+
 // InlineQueryResultDocument Represents a link to a file. By default, this file will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the file. Currently, only .PDF and .ZIP files can be sent using this method.
 type InlineQueryResultDocument struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Title string `json:"title"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
-	DocumentUrl string `json:"document_url"`
+	DocumentURL string `json:"document_url"`
 	MimeType string `json:"mime_type"`
 	Description string `json:"description,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 	ThumbnailWidth *int64 `json:"thumbnail_width,omitempty"`
 	ThumbnailHeight *int64 `json:"thumbnail_height,omitempty"`
 }
 
+func (*InlineQueryResultDocument) isInlineQueryResult() {}
+
 // InlineQueryResultGame Represents a Game.
 type InlineQueryResultGame struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	GameShortName string `json:"game_short_name"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
+func (*InlineQueryResultGame) isInlineQueryResult() {}
+
 // InlineQueryResultGif Represents a link to an animated GIF file. By default, this animated GIF file will be sent by the user with optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the animation.
 type InlineQueryResultGif struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	GifUrl string `json:"gif_url"`
+	ID string `json:"id"`
+	GifURL string `json:"gif_url"`
 	GifWidth *int64 `json:"gif_width,omitempty"`
 	GifHeight *int64 `json:"gif_height,omitempty"`
 	GifDuration *int64 `json:"gif_duration,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
 	ThumbnailMimeType string `json:"thumbnail_mime_type,omitempty"`
 	Title string `json:"title,omitempty"`
 	Caption string `json:"caption,omitempty"`
@@ -1050,13 +1130,15 @@ type InlineQueryResultGif struct {
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultGif) isInlineQueryResult() {}
+
 // InlineQueryResultLocation Represents a location on a map. By default, the location will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the location.
 type InlineQueryResultLocation struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Latitude float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 	Title string `json:"title"`
@@ -1065,21 +1147,23 @@ type InlineQueryResultLocation struct {
 	Heading *int64 `json:"heading,omitempty"`
 	ProximityAlertRadius *int64 `json:"proximity_alert_radius,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 	ThumbnailWidth *int64 `json:"thumbnail_width,omitempty"`
 	ThumbnailHeight *int64 `json:"thumbnail_height,omitempty"`
 }
 
+func (*InlineQueryResultLocation) isInlineQueryResult() {}
+
 // InlineQueryResultMpeg4Gif Represents a link to a video animation (H.264/MPEG-4 AVC video without sound). By default, this animated MPEG-4 file will be sent by the user with optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the animation.
 type InlineQueryResultMpeg4Gif struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	Mpeg4Url string `json:"mpeg4_url"`
+	ID string `json:"id"`
+	Mpeg4URL string `json:"mpeg4_url"`
 	Mpeg4Width *int64 `json:"mpeg4_width,omitempty"`
 	Mpeg4Height *int64 `json:"mpeg4_height,omitempty"`
 	Mpeg4Duration *int64 `json:"mpeg4_duration,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
 	ThumbnailMimeType string `json:"thumbnail_mime_type,omitempty"`
 	Title string `json:"title,omitempty"`
 	Caption string `json:"caption,omitempty"`
@@ -1087,15 +1171,17 @@ type InlineQueryResultMpeg4Gif struct {
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultMpeg4Gif) isInlineQueryResult() {}
+
 // InlineQueryResultPhoto Represents a link to a photo. By default, this photo will be sent by the user with optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the photo.
 type InlineQueryResultPhoto struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	PhotoUrl string `json:"photo_url"`
-	ThumbnailUrl string `json:"thumbnail_url"`
+	ID string `json:"id"`
+	PhotoURL string `json:"photo_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
 	PhotoWidth *int64 `json:"photo_width,omitempty"`
 	PhotoHeight *int64 `json:"photo_height,omitempty"`
 	Title string `json:"title,omitempty"`
@@ -1105,35 +1191,39 @@ type InlineQueryResultPhoto struct {
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	ShowCaptionAboveMedia *bool `json:"show_caption_above_media,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultPhoto) isInlineQueryResult() {}
+
 // InlineQueryResultVenue Represents a venue. By default, the venue will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the venue.
 type InlineQueryResultVenue struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Latitude float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 	Title string `json:"title"`
 	Address string `json:"address"`
-	FoursquareId string `json:"foursquare_id,omitempty"`
+	FoursquareID string `json:"foursquare_id,omitempty"`
 	FoursquareType string `json:"foursquare_type,omitempty"`
-	GooglePlaceId string `json:"google_place_id,omitempty"`
+	GooglePlaceID string `json:"google_place_id,omitempty"`
 	GooglePlaceType string `json:"google_place_type,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
-	ThumbnailUrl string `json:"thumbnail_url,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
+	ThumbnailURL string `json:"thumbnail_url,omitempty"`
 	ThumbnailWidth *int64 `json:"thumbnail_width,omitempty"`
 	ThumbnailHeight *int64 `json:"thumbnail_height,omitempty"`
 }
 
+func (*InlineQueryResultVenue) isInlineQueryResult() {}
+
 // InlineQueryResultVideo Represents a link to a page containing an embedded video player or a video file. By default, this video file will be sent by the user with an optional caption. Alternatively, you can use input_message_content to send a message with the specified content instead of the video.
 type InlineQueryResultVideo struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	VideoUrl string `json:"video_url"`
+	ID string `json:"id"`
+	VideoURL string `json:"video_url"`
 	MimeType string `json:"mime_type"`
-	ThumbnailUrl string `json:"thumbnail_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
 	Title string `json:"title"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
@@ -1144,23 +1234,27 @@ type InlineQueryResultVideo struct {
 	VideoDuration *int64 `json:"video_duration,omitempty"`
 	Description string `json:"description,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultVideo) isInlineQueryResult() {}
+
 // InlineQueryResultVoice Represents a link to a voice recording in an .OGG container encoded with OPUS. By default, this voice recording will be sent by the user. Alternatively, you can use input_message_content to send a message with the specified content instead of the the voice message.
 type InlineQueryResultVoice struct {
 	Type string `json:"type"`
-	Id string `json:"id"`
-	VoiceUrl string `json:"voice_url"`
+	ID string `json:"id"`
+	VoiceURL string `json:"voice_url"`
 	Title string `json:"title"`
 	Caption string `json:"caption,omitempty"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	CaptionEntities []MessageEntity `json:"caption_entities,omitempty"`
 	VoiceDuration *int64 `json:"voice_duration,omitempty"`
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
-	InputMessageContent *InputMessageContent `json:"input_message_content,omitempty"`
+	InputMessageContent InputMessageContent `json:"input_message_content,omitempty"`
 }
 
+func (*InlineQueryResultVoice) isInlineQueryResult() {}
+
 // InlineQueryResultsButton This object represents a button to be shown above inline query results. You must use exactly one of the optional fields.
 type InlineQueryResultsButton struct {
 	Text string `json:"text"`
@@ -1180,7 +1274,7 @@ type InputChecklist struct {
 
 // InputChecklistTask Describes a task to add to a checklist.
 type InputChecklistTask struct {
-	Id int64 `json:"id"`
+	ID int64 `json:"id"`
 	Text string `json:"text"`
 	ParseMode string `json:"parse_mode,omitempty"`
 	TextEntities []MessageEntity `json:"text_entities,omitempty"`
@@ -1194,6 +1288,8 @@ type InputContactMessageContent struct {
 	Vcard string `json:"vcard,omitempty"`
 }
 
+func (*InputContactMessageContent) isInputMessageContent() {}
+
 // InputFile This object represents the contents of a file to be uploaded. Must be posted using multipart/form-data in the usual way that files are uploaded via the browser.
 type InputFile struct {
 }
@@ -1209,7 +1305,7 @@ type InputInvoiceMessageContent struct {
 	MaxTipAmount *int64 `json:"max_tip_amount,omitempty"`
 	SuggestedTipAmounts []int64 `json:"suggested_tip_amounts,omitempty"`
 	ProviderData string `json:"provider_data,omitempty"`
-	PhotoUrl string `json:"photo_url,omitempty"`
+	PhotoURL string `json:"photo_url,omitempty"`
 	PhotoSize *int64 `json:"photo_size,omitempty"`
 	PhotoWidth *int64 `json:"photo_width,omitempty"`
 	PhotoHeight *int64 `json:"photo_height,omitempty"`
@@ -1222,6 +1318,8 @@ type InputInvoiceMessageContent struct {
 	IsFlexible *bool `json:"is_flexible,omitempty"`
 }
 
+func (*InputInvoiceMessageContent) isInputMessageContent() {}
+
 // InputLocationMessageContent Represents the content of a location message to be sent as the result of an inline query.
 type InputLocationMessageContent struct {
 	Latitude float64 `json:"latitude"`
@@ -1232,8 +1330,11 @@ type InputLocationMessageContent struct {
 	ProximityAlertRadius *int64 `json:"proximity_alert_radius,omitempty"`
 }
 
+func (*InputLocationMessageContent) isInputMessageContent() {}
+
 // InputMedia This object represents the content of a media message to be sent. It should be one of
-type InputMedia struct {
+type InputMedia interface {
+	isInputMedia()
 }
 
 // InputMediaAnimation Represents an animation file (GIF or H.264/MPEG-4 AVC video without sound) to be sent.
@@ -1251,6 +1352,8 @@ type InputMediaAnimation struct {
 	HasSpoiler *bool `json:"has_spoiler,omitempty"`
 }
 
+func (*InputMediaAnimation) isInputMedia() {}
+
 // InputMediaAudio Represents an audio file to be treated as music to be sent.
 type InputMediaAudio struct {
 	Type string `json:"type"`
@@ -1264,6 +1367,8 @@ type InputMediaAudio struct {
 	Title string `json:"title,omitempty"`
 }
 
+func (*InputMediaAudio) isInputMedia() {}
+
 // InputMediaDocument Represents a general file to be sent.
 type InputMediaDocument struct {
 	Type string `json:"type"`
@@ -1275,6 +1380,8 @@ type InputMediaDocument struct {
 	DisableContentTypeDetection *bool `json:"disable_content_type_detection,omitempty"`
 }
 
+func (*InputMediaDocument) isInputMedia() {}
+
 // InputMediaPhoto Represents a photo to be sent.
 type InputMediaPhoto struct {
 	Type string `json:"type"`
@@ -1286,6 +1393,8 @@ type InputMediaPhoto struct {
 	HasSpoiler *bool `json:"has_spoiler,omitempty"`
 }
 
+func (*InputMediaPhoto) isInputMedia() {}
+
 // InputMediaVideo Represents a video to be sent.
 type InputMediaVideo struct {
 	Type string `json:"type"`
@@ -1304,12 +1413,16 @@ type InputMediaVideo struct {
 	HasSpoiler *bool `json:"has_spoiler,omitempty"`
 }
 
+func (*InputMediaVideo) isInputMedia() {}
+
 // InputMessageContent This object represents the content of a message to be sent as a result of an inline query. Telegram clients currently support the following 5 types:
-type InputMessageContent struct {
+type InputMessageContent interface {
+	isInputMessageContent()
 }
 
 // InputPaidMedia This object describes the paid media to be sent. Currently, it can be one of
-type InputPaidMedia struct {
+type InputPaidMedia interface {
+	isInputPaidMedia()
 }
 
 // InputPaidMediaPhoto The paid media to send is a photo.
@@ -1318,6 +1431,8 @@ type InputPaidMediaPhoto struct {
 	Media string `json:"media"`
 }
 
+func (*InputPaidMediaPhoto) isInputPaidMedia() {}
+
 // InputPaidMediaVideo The paid media to send is a video.
 type InputPaidMediaVideo struct {
 	Type string `json:"type"`
@@ -1331,6 +1446,8 @@ type InputPaidMediaVideo struct {
 	SupportsStreaming *bool `json:"supports_streaming,omitempty"`
 }
 
+func (*InputPaidMediaVideo) isInputPaidMedia() {}
+
 // InputPollOption This object contains information about one answer option in a poll to be sent.
 type InputPollOption struct {
 	Text string `json:"text"`
@@ -1339,7 +1456,8 @@ type InputPollOption struct {
 }
 
 // InputProfilePhoto This object describes a profile photo to set. Currently, it can be one of
-type InputProfilePhoto struct {
+type InputProfilePhoto interface {
+	isInputProfilePhoto()
 }
 
 // InputProfilePhotoAnimated An animated profile photo in the MPEG4 format.
@@ -1349,12 +1467,16 @@ type InputProfilePhotoAnimated struct {
 	MainFrameTimestamp *float64 `json:"main_frame_timestamp,omitempty"`
 }
 
+func (*InputProfilePhotoAnimated) isInputProfilePhoto() {}
+
 // InputProfilePhotoStatic A static profile photo in the .JPG format.
 type InputProfilePhotoStatic struct {
 	Type string `json:"type"`
 	Photo string `json:"photo"`
 }
 
+func (*InputProfilePhotoStatic) isInputProfilePhoto() {}
+
 // InputSticker This object describes a sticker to be added to a sticker set.
 type InputSticker struct {
 	Sticker string `json:"sticker"`
@@ -1365,7 +1487,8 @@ type InputSticker struct {
 }
 
 // InputStoryContent This object describes the content of a story to post. Currently, it can be one of
-type InputStoryContent struct {
+type InputStoryContent interface {
+	isInputStoryContent()
 }
 
 // InputStoryContentPhoto Describes a photo to post as a story.
@@ -1374,6 +1497,8 @@ type InputStoryContentPhoto struct {
 	Photo string `json:"photo"`
 }
 
+func (*InputStoryContentPhoto) isInputStoryContent() {}
+
 // InputStoryContentVideo Describes a video to post as a story.
 type InputStoryContentVideo struct {
 	Type string `json:"type"`
@@ -1383,6 +1508,8 @@ type InputStoryContentVideo struct {
 	IsAnimation *bool `json:"is_animation,omitempty"`
 }
 
+func (*InputStoryContentVideo) isInputStoryContent() {}
+
 // InputTextMessageContent Represents the content of a text message to be sent as the result of an inline query.
 type InputTextMessageContent struct {
 	MessageText string `json:"message_text"`
@@ -1391,18 +1518,22 @@ type InputTextMessageContent struct {
 	LinkPreviewOptions *LinkPreviewOptions `json:"link_preview_options,omitempty"`
 }
 
+func (*InputTextMessageContent) isInputMessageContent() {}
+
 // InputVenueMessageContent Represents the content of a venue message to be sent as the result of an inline query.
 type InputVenueMessageContent struct {
 	Latitude float64 `json:"latitude"`
 	Longitude float64 `json:"longitude"`
 	Title string `json:"title"`
 	Address string `json:"address"`
-	FoursquareId string `json:"foursquare_id,omitempty"`
+	FoursquareID string `json:"foursquare_id,omitempty"`
 	FoursquareType string `json:"foursquare_type,omitempty"`
-	GooglePlaceId string `json:"google_place_id,omitempty"`
+	GooglePlaceID string `json:"google_place_id,omitempty"`
 	GooglePlaceType string `json:"google_place_type,omitempty"`
 }
 
+func (*InputVenueMessageContent) isInputMessageContent() {}
+
 // Invoice This object contains basic information about an invoice.
 type Invoice struct {
 	Title string `json:"title"`
@@ -1430,7 +1561,7 @@ type KeyboardButtonPollType struct {
 
 // KeyboardButtonRequestChat This object defines the criteria used to request a suitable chat. Information about the selected chat will be shared with the bot when the corresponding button is pressed. The bot will be granted requested rights in the chat if appropriate. More about requesting chats: https://core.telegram.org/bots/features#chat-and-user-selection.
 type KeyboardButtonRequestChat struct {
-	RequestId int64 `json:"request_id"`
+	RequestID int64 `json:"request_id"`
 	ChatIsChannel bool `json:"chat_is_channel"`
 	ChatIsForum *bool `json:"chat_is_forum,omitempty"`
 	ChatHasUsername *bool `json:"chat_has_username,omitempty"`
@@ -1445,7 +1576,7 @@ type KeyboardButtonRequestChat struct {
 
 // KeyboardButtonRequestUsers This object defines the criteria used to request suitable users. Information about the selected users will be shared with the bot when the corresponding button is pressed. More about requesting users: https://core.telegram.org/bots/features#chat-and-user-selection
 type KeyboardButtonRequestUsers struct {
-	RequestId int64 `json:"request_id"`
+	RequestID int64 `json:"request_id"`
 	UserIsBot *bool `json:"user_is_bot,omitempty"`
 	UserIsPremium *bool `json:"user_is_premium,omitempty"`
 	MaxQuantity *int64 `json:"max_quantity,omitempty"`
@@ -1463,7 +1594,7 @@ type LabeledPrice struct {
 // LinkPreviewOptions Describes the options used for link preview generation.
 type LinkPreviewOptions struct {
 	IsDisabled *bool `json:"is_disabled,omitempty"`
-	Url string `json:"url,omitempty"`
+	URL string `json:"url,omitempty"`
 	PreferSmallMedia *bool `json:"prefer_small_media,omitempty"`
 	PreferLargeMedia *bool `json:"prefer_large_media,omitempty"`
 	ShowAboveText *bool `json:"show_above_text,omitempty"`
@@ -1489,7 +1620,7 @@ type LocationAddress struct {
 
 // LoginUrl This object represents a parameter of the inline keyboard button used to automatically authorize a user. Serves as a great replacement for the Telegram Login Widget when the user is coming from Telegram. All the user needs to do is tap/click a button and confirm that they want to log in:
 type LoginUrl struct {
-	Url string `json:"url"`
+	URL string `json:"url"`
 	ForwardText string `json:"forward_text,omitempty"`
 	BotUsername string `json:"bot_username,omitempty"`
 	RequestWriteAccess *bool `json:"request_write_access,omitempty"`
@@ -1504,11 +1635,13 @@ type MaskPosition struct {
 }
 
 // MaybeInaccessibleMessage This object describes a message that can be inaccessible to the bot. It can be one of
-type MaybeInaccessibleMessage struct {
+type MaybeInaccessibleMessage interface {
+	isMaybeInaccessibleMessage()
 }
 
 // MenuButton This object describes the bot's menu button in a private chat. It should be one of
-type MenuButton struct {
+type MenuButton interface {
+	isMenuButton()
 }
 
 // MenuButtonCommands Represents a menu button, which opens the bot's list of commands.
@@ -1516,11 +1649,15 @@ type MenuButtonCommands struct {
 	Type string `json:"type"`
 }
 
+func (*MenuButtonCommands) isMenuButton() {}
+
 // MenuButtonDefault Describes that no specific value for the menu button was set.
 type MenuButtonDefault struct {
 	Type string `json:"type"`
 }
 
+func (*MenuButtonDefault) isMenuButton() {}
+
 // MenuButtonWebApp Represents a menu button, which launches a Web App.
 type MenuButtonWebApp struct {
 	Type string `json:"type"`
@@ -1528,39 +1665,41 @@ type MenuButtonWebApp struct {
 	WebApp WebAppInfo `json:"web_app"`
 }
 
+func (*MenuButtonWebApp) isMenuButton() {}
+
 // Message This object represents a message.
 type Message struct {
-	MessageId int64 `json:"message_id"`
-	MessageThreadId *int64 `json:"message_thread_id,omitempty"`
+	MessageID int64 `json:"message_id"`
+	MessageThreadID *int64 `json:"message_thread_id,omitempty"`
 	DirectMessagesTopic *DirectMessagesTopic `json:"direct_messages_topic,omitempty"`
 	From *User `json:"from,omitempty"`
 	SenderChat *Chat `json:"sender_chat,omitempty"`
 	SenderBoostCount *int64 `json:"sender_boost_count,omitempty"`
 	SenderBusinessBot *User `json:"sender_business_bot,omitempty"`
 	Date int64 `json:"date"`
-	BusinessConnectionId string `json:"business_connection_id,omitempty"`
+	BusinessConnectionID string `json:"business_connection_id,omitempty"`
 	Chat Chat `json:"chat"`
-	ForwardOrigin *MessageOrigin `json:"forward_origin,omitempty"`
+	ForwardOrigin MessageOrigin `json:"forward_origin,omitempty"`
 	IsTopicMessage *bool `json:"is_topic_message,omitempty"`
 	IsAutomaticForward *bool `json:"is_automatic_forward,omitempty"`
 	ReplyToMessage *Message `json:"reply_to_message,omitempty"`
 	ExternalReply *ExternalReplyInfo `json:"external_reply,omitempty"`
 	Quote *TextQuote `json:"quote,omitempty"`
 	ReplyToStory *Story `json:"reply_to_story,omitempty"`
-	ReplyToChecklistTaskId *int64 `json:"reply_to_checklist_task_id,omitempty"`
+	ReplyToChecklistTaskID *int64 `json:"reply_to_checklist_task_id,omitempty"`
 	ViaBot *User `json:"via_bot,omitempty"`
 	EditDate *int64 `json:"edit_date,omitempty"`
 	HasProtectedContent *bool `json:"has_protected_content,omitempty"`
 	IsFromOffline *bool `json:"is_from_offline,omitempty"`
 	IsPaidPost *bool `json:"is_paid_post,omitempty"`
-	MediaGroupId string `json:"media_group_id,omitempty"`
+	MediaGroupID string `json:"media_group_id,omitempty"`
 	AuthorSignature string `json:"author_signature,omitempty"`
 	PaidStarCount *int64 `json:"paid_star_count,omitempty"`
 	Text string `json:"text,omitempty"`
 	Entities []MessageEntity `json:"entities,omitempty"`
 	LinkPreviewOptions *LinkPreviewOptions `json:"link_preview_options,omitempty"`
 	SuggestedPostInfo *SuggestedPostInfo `json:"suggested_post_info,omitempty"`
-	EffectId string `json:"effect_id,omitempty"`
+	EffectID string `json:"effect_id,omitempty"`
 	Animation *Animation `json:"animation,omitempty"`
 	Audio *Audio `json:"audio,omitempty"`
 	Document *Document `json:"document,omitempty"`
@@ -1591,9 +1730,9 @@ type Message struct {
 	SupergroupChatCreated *bool `json:"supergroup_chat_created,omitempty"`
 	ChannelChatCreated *bool `json:"channel_chat_created,omitempty"`
 	MessageAutoDeleteTimerChanged *MessageAutoDeleteTimerChanged `json:"message_auto_delete_timer_changed,omitempty"`
-	MigrateToChatId *int64 `json:"migrate_to_chat_id,omitempty"`
-	MigrateFromChatId *int64 `json:"migrate_from_chat_id,omitempty"`
-	PinnedMessage *MaybeInaccessibleMessage `json:"pinned_message,omitempty"`
+	MigrateToChatID *int64 `json:"migrate_to_chat_id,omitempty"`
+	MigrateFromChatID *int64 `json:"migrate_from_chat_id,omitempty"`
+	PinnedMessage MaybeInaccessibleMessage `json:"pinned_message,omitempty"`
 	Invoice *Invoice `json:"invoice,omitempty"`
 	SuccessfulPayment *SuccessfulPayment `json:"successful_payment,omitempty"`
 	RefundedPayment *RefundedPayment `json:"refunded_payment,omitempty"`
@@ -1634,6 +1773,8 @@ type Message struct {
 	ReplyMarkup *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
 }
 
+func (*Message) isMaybeInaccessibleMessage() {}
+
 // MessageAutoDeleteTimerChanged This object represents a service message about a change in auto-delete timer settings.
 type MessageAutoDeleteTimerChanged struct {
 	MessageAutoDeleteTime int64 `json:"message_auto_delete_time"`
@@ -1644,19 +1785,20 @@ type MessageEntity struct {
 	Type string `json:"type"`
 	Offset int64 `json:"offset"`
 	Length int64 `json:"length"`
-	Url string `json:"url,omitempty"`
+	URL string `json:"url,omitempty"`
 	User *User `json:"user,omitempty"`
 	Language string `json:"language,omitempty"`
-	CustomEmojiId string `json:"custom_emoji_id,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
 }
 
 // MessageId This object represents a unique message identifier.
 type MessageId struct {
-	MessageId int64 `json:"message_id"`
+	MessageID int64 `json:"message_id"`
 }
 
 // MessageOrigin This object describes the origin of a message. It can be one of
-type MessageOrigin struct {
+type MessageOrigin interface {
+	isMessageOrigin()
 }
 
 // MessageOriginChannel The message was originally sent to a channel chat.
@@ -1664,10 +1806,12 @@ type MessageOriginChannel struct {
 	Type string `json:"type"`
 	Date int64 `json:"date"`
 	Chat Chat `json:"chat"`
-	MessageId int64 `json:"message_id"`
+	MessageID int64 `json:"message_id"`
 	AuthorSignature string `json:"author_signature,omitempty"`
 }
 
+func (*MessageOriginChannel) isMessageOrigin() {}
+
 // MessageOriginChat The message was originally sent on behalf of a chat to a group chat.
 type MessageOriginChat struct {
 	Type string `json:"type"`
@@ -1676,6 +1820,8 @@ type MessageOriginChat struct {
 	AuthorSignature string `json:"author_signature,omitempty"`
 }
 
+func (*MessageOriginChat) isMessageOrigin() {}
+
 // MessageOriginHiddenUser The message was originally sent by an unknown user.
 type MessageOriginHiddenUser struct {
 	Type string `json:"type"`
@@ -1683,6 +1829,8 @@ type MessageOriginHiddenUser struct {
 	SenderUserName string `json:"sender_user_name"`
 }
 
+func (*MessageOriginHiddenUser) isMessageOrigin() {}
+
 // MessageOriginUser The message was originally sent by a known user.
 type MessageOriginUser struct {
 	Type string `json:"type"`
@@ -1690,10 +1838,12 @@ type MessageOriginUser struct {
 	SenderUser User `json:"sender_user"`
 }
 
+func (*MessageOriginUser) isMessageOrigin() {}
+
 // MessageReactionCountUpdated This object represents reaction changes on a message with anonymous reactions.
 type MessageReactionCountUpdated struct {
 	Chat Chat `json:"chat"`
-	MessageId int64 `json:"message_id"`
+	MessageID int64 `json:"message_id"`
 	Date int64 `json:"date"`
 	Reactions []ReactionCount `json:"reactions"`
 }
@@ -1701,7 +1851,7 @@ type MessageReactionCountUpdated struct {
 // MessageReactionUpdated This object represents a change of a reaction on a message performed by a user.
 type MessageReactionUpdated struct {
 	Chat Chat `json:"chat"`
-	MessageId int64 `json:"message_id"`
+	MessageID int64 `json:"message_id"`
 	User *User `json:"user,omitempty"`
 	ActorChat *Chat `json:"actor_chat,omitempty"`
 	Date int64 `json:"date"`
@@ -1718,14 +1868,15 @@ type OrderInfo struct {
 }
 
 // OwnedGift This object describes a gift received and owned by a user or a chat. Currently, it can be one of
-type OwnedGift struct {
+type OwnedGift interface {
+	isOwnedGift()
 }
 
 // OwnedGiftRegular Describes a regular gift owned by a user or a chat.
 type OwnedGiftRegular struct {
 	Type string `json:"type"`
 	Gift Gift `json:"gift"`
-	OwnedGiftId string `json:"owned_gift_id,omitempty"`
+	OwnedGiftID string `json:"owned_gift_id,omitempty"`
 	SenderUser *User `json:"sender_user,omitempty"`
 	SendDate int64 `json:"send_date"`
 	Text string `json:"text,omitempty"`
@@ -1738,11 +1889,13 @@ type OwnedGiftRegular struct {
 	PrepaidUpgradeStarCount *int64 `json:"prepaid_upgrade_star_count,omitempty"`
 }
 
+func (*OwnedGiftRegular) isOwnedGift() {}
+
 // OwnedGiftUnique Describes a unique gift received and owned by a user or a chat.
 type OwnedGiftUnique struct {
 	Type string `json:"type"`
 	Gift UniqueGift `json:"gift"`
-	OwnedGiftId string `json:"owned_gift_id,omitempty"`
+	OwnedGiftID string `json:"owned_gift_id,omitempty"`
 	SenderUser *User `json:"sender_user,omitempty"`
 	SendDate int64 `json:"send_date"`
 	IsSaved *bool `json:"is_saved,omitempty"`
@@ -1751,6 +1904,8 @@ type OwnedGiftUnique struct {
 	NextTransferDate *int64 `json:"next_transfer_date,omitempty"`
 }
 
+func (*OwnedGiftUnique) isOwnedGift() {}
+
 // OwnedGifts Contains the list of gifts received and owned by a user or a chat.
 type OwnedGifts struct {
 	TotalCount int64 `json:"total_count"`
@@ -1759,7 +1914,8 @@ type OwnedGifts struct {
 }
 
 // PaidMedia This object describes paid media. Currently, it can be one of
-type PaidMedia struct {
+type PaidMedia interface {
+	isPaidMedia()
 }
 
 // PaidMediaInfo Describes the paid media added to a message.
@@ -1774,6 +1930,8 @@ type PaidMediaPhoto struct {
 	Photo []PhotoSize `json:"photo"`
 }
 
+func (*PaidMediaPhoto) isPaidMedia() {}
+
 // PaidMediaPreview The paid media isn't available before the payment.
 type PaidMediaPreview struct {
 	Type string `json:"type"`
@@ -1782,6 +1940,8 @@ type PaidMediaPreview struct {
 	Duration *int64 `json:"duration,omitempty"`
 }
 
+func (*PaidMediaPreview) isPaidMedia() {}
+
 // PaidMediaPurchased This object contains information about a paid media purchase.
 type PaidMediaPurchased struct {
 	From User `json:"from"`
@@ -1794,6 +1954,8 @@ type PaidMediaVideo struct {
 	Video Video `json:"video"`
 }
 
+func (*PaidMediaVideo) isPaidMedia() {}
+
 // PaidMessagePriceChanged Describes a service message about a change in the price of paid messages within a chat.
 type PaidMessagePriceChanged struct {
 	PaidMessageStarCount int64 `json:"paid_message_star_count"`
@@ -1806,7 +1968,8 @@ type PassportData struct {
 }
 
 // PassportElementError This object represents an error in the Telegram Passport element which was submitted that should be resolved by the user. It should be one of:
-type PassportElementError struct {
+type PassportElementError interface {
+	isPassportElementError()
 }
 
 // PassportElementErrorDataField Represents an issue in one of the data fields that was provided by the user. The error is considered resolved when the field's value changes.
@@ -1818,6 +1981,8 @@ type PassportElementErrorDataField struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorDataField) isPassportElementError() {}
+
 // PassportElementErrorFile Represents an issue with a document scan. The error is considered resolved when the file with the document scan changes.
 type PassportElementErrorFile struct {
 	Source string `json:"source"`
@@ -1826,6 +1991,8 @@ type PassportElementErrorFile struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorFile) isPassportElementError() {}
+
 // PassportElementErrorFiles Represents an issue with a list of scans. The error is considered resolved when the list of files containing the scans changes.
 type PassportElementErrorFiles struct {
 	Source string `json:"source"`
@@ -1834,6 +2001,8 @@ type PassportElementErrorFiles struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorFiles) isPassportElementError() {}
+
 // PassportElementErrorFrontSide Represents an issue with the front side of a document. The error is considered resolved when the file with the front side of the document changes.
 type PassportElementErrorFrontSide struct {
 	Source string `json:"source"`
@@ -1842,6 +2011,8 @@ type PassportElementErrorFrontSide struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorFrontSide) isPassportElementError() {}
+
 // PassportElementErrorReverseSide Represents an issue with the reverse side of a document. The error is considered resolved when the file with reverse side of the document changes.
 type PassportElementErrorReverseSide struct {
 	Source string `json:"source"`
@@ -1850,6 +2021,8 @@ type PassportElementErrorReverseSide struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorReverseSide) isPassportElementError() {}
+
 // PassportElementErrorSelfie Represents an issue with the selfie with a document. The error is considered resolved when the file with the selfie changes.
 type PassportElementErrorSelfie struct {
 	Source string `json:"source"`
@@ -1858,6 +2031,8 @@ type PassportElementErrorSelfie struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorSelfie) isPassportElementError() {}
+
 // PassportElementErrorTranslationFile Represents an issue with one of the files that constitute the translation of a document. The error is considered resolved when the file changes.
 type PassportElementErrorTranslationFile struct {
 	Source string `json:"source"`
@@ -1866,6 +2041,8 @@ type PassportElementErrorTranslationFile struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorTranslationFile) isPassportElementError() {}
+
 // PassportElementErrorTranslationFiles Represents an issue with the translated version of a document. The error is considered resolved when a file with the document translation change.
 type PassportElementErrorTranslationFiles struct {
 	Source string `json:"source"`
@@ -1874,6 +2051,8 @@ type PassportElementErrorTranslationFiles struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorTranslationFiles) isPassportElementError() {}
+
 // PassportElementErrorUnspecified Represents an issue in an unspecified place. The error is considered resolved when new data is added.
 type PassportElementErrorUnspecified struct {
 	Source string `json:"source"`
@@ -1882,18 +2061,20 @@ type PassportElementErrorUnspecified struct {
 	Message string `json:"message"`
 }
 
+func (*PassportElementErrorUnspecified) isPassportElementError() {}
+
 // PassportFile This object represents a file uploaded to Telegram Passport. Currently all Telegram Passport files are in JPEG format when decrypted and don't exceed 10MB.
 type PassportFile struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	FileSize int64 `json:"file_size"`
 	FileDate int64 `json:"file_date"`
 }
 
 // PhotoSize This object represents one size of a photo or a file / sticker thumbnail.
 type PhotoSize struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Width int64 `json:"width"`
 	Height int64 `json:"height"`
 	FileSize *int64 `json:"file_size,omitempty"`
@@ -1901,7 +2082,7 @@ type PhotoSize struct {
 
 // Poll This object contains information about a poll.
 type Poll struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Question string `json:"question"`
 	QuestionEntities []MessageEntity `json:"question_entities,omitempty"`
 	Options []PollOption `json:"options"`
@@ -1910,7 +2091,7 @@ type Poll struct {
 	IsAnonymous bool `json:"is_anonymous"`
 	Type string `json:"type"`
 	AllowsMultipleAnswers bool `json:"allows_multiple_answers"`
-	CorrectOptionId *int64 `json:"correct_option_id,omitempty"`
+	CorrectOptionID *int64 `json:"correct_option_id,omitempty"`
 	Explanation string `json:"explanation,omitempty"`
 	ExplanationEntities []MessageEntity `json:"explanation_entities,omitempty"`
 	OpenPeriod *int64 `json:"open_period,omitempty"`
@@ -1919,7 +2100,7 @@ type Poll struct {
 
 // PollAnswer This object represents an answer of a user in a non-anonymous poll.
 type PollAnswer struct {
-	PollId string `json:"poll_id"`
+	PollID string `json:"poll_id"`
 	VoterChat *Chat `json:"voter_chat,omitempty"`
 	User *User `json:"user,omitempty"`
 	OptionIds []int64 `json:"option_ids"`
@@ -1934,18 +2115,18 @@ type PollOption struct {
 
 // PreCheckoutQuery This object contains information about an incoming pre-checkout query.
 type PreCheckoutQuery struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	From User `json:"from"`
 	Currency string `json:"currency"`
 	TotalAmount int64 `json:"total_amount"`
 	InvoicePayload string `json:"invoice_payload"`
-	ShippingOptionId string `json:"shipping_option_id,omitempty"`
+	ShippingOptionID string `json:"shipping_option_id,omitempty"`
 	OrderInfo *OrderInfo `json:"order_info,omitempty"`
 }
 
 // PreparedInlineMessage Describes an inline message to be sent by a user of a Mini App.
 type PreparedInlineMessage struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	ExpirationDate int64 `json:"expiration_date"`
 }
 
@@ -1963,33 +2144,40 @@ type ReactionCount struct {
 }
 
 // ReactionType This object describes the type of a reaction. Currently, it can be one of
-type ReactionType struct {
+type ReactionType interface {
+	isReactionType()
 }
 
 // ReactionTypeCustomEmoji The reaction is based on a custom emoji.
 type ReactionTypeCustomEmoji struct {
 	Type string `json:"type"`
-	CustomEmojiId string `json:"custom_emoji_id"`
+	CustomEmojiID string `json:"custom_emoji_id"`
 }
 
+func (*ReactionTypeCustomEmoji) isReactionType() {}
+
 // ReactionTypeEmoji The reaction is based on an emoji.
 type ReactionTypeEmoji struct {
 	Type string `json:"type"`
 	Emoji string `json:"emoji"`
 }
 
+func (*ReactionTypeEmoji) isReactionType() {}
+
 // ReactionTypePaid The reaction is paid.
 type ReactionTypePaid struct {
 	Type string `json:"type"`
 }
 
+func (*ReactionTypePaid) isReactionType() {}
+
 // RefundedPayment This object contains basic information about a refunded payment.
 type RefundedPayment struct {
 	Currency string `json:"currency"`
 	TotalAmount int64 `json:"total_amount"`
 	InvoicePayload string `json:"invoice_payload"`
-	TelegramPaymentChargeId string `json:"telegram_payment_charge_id"`
-	ProviderPaymentChargeId string `json:"provider_payment_charge_id,omitempty"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string `json:"provider_payment_charge_id,omitempty"`
 }
 
 // ReplyKeyboardMarkup This object represents a custom keyboard with reply options (see Introduction to bots for details and examples). Not supported in channels and for messages sent on behalf of a Telegram Business account.
@@ -2010,24 +2198,25 @@ type ReplyKeyboardRemove struct {
 
 // ReplyParameters Describes reply parameters for the message that is being sent.
 type ReplyParameters struct {
-	MessageId int64 `json:"message_id"`
-	ChatId *int64 `json:"chat_id,omitempty"`
+	MessageID int64 `json:"message_id"`
+	ChatID *int64 `json:"chat_id,omitempty"`
 	AllowSendingWithoutReply *bool `json:"allow_sending_without_reply,omitempty"`
 	Quote string `json:"quote,omitempty"`
 	QuoteParseMode string `json:"quote_parse_mode,omitempty"`
 	QuoteEntities []MessageEntity `json:"quote_entities,omitempty"`
 	QuotePosition *int64 `json:"quote_position,omitempty"`
-	ChecklistTaskId *int64 `json:"checklist_task_id,omitempty"`
+	ChecklistTaskID *int64 `json:"checklist_task_id,omitempty"`
 }
 
 // ResponseParameters Describes why a request was unsuccessful.
 type ResponseParameters struct {
-	MigrateToChatId *int64 `json:"migrate_to_chat_id,omitempty"`
+	MigrateToChatID *int64 `json:"migrate_to_chat_id,omitempty"`
 	RetryAfter *int64 `json:"retry_after,omitempty"`
 }
 
 // RevenueWithdrawalState This object describes the state of a revenue withdrawal operation. Currently, it can be one of
-type RevenueWithdrawalState struct {
+type RevenueWithdrawalState interface {
+	isRevenueWithdrawalState()
 }
 
 // RevenueWithdrawalStateFailed The withdrawal failed and the transaction was refunded.
@@ -2035,26 +2224,32 @@ type RevenueWithdrawalStateFailed struct {
 	Type string `json:"type"`
 }
 
+func (*RevenueWithdrawalStateFailed) isRevenueWithdrawalState() {}
+
 // RevenueWithdrawalStatePending The withdrawal is in progress.
 type RevenueWithdrawalStatePending struct {
 	Type string `json:"type"`
 }
 
+func (*RevenueWithdrawalStatePending) isRevenueWithdrawalState() {}
+
 // RevenueWithdrawalStateSucceeded The withdrawal succeeded.
 type RevenueWithdrawalStateSucceeded struct {
 	Type string `json:"type"`
 	Date int64 `json:"date"`
-	Url string `json:"url"`
+	URL string `json:"url"`
 }
 
+func (*RevenueWithdrawalStateSucceeded) isRevenueWithdrawalState() {}
+
 // SentWebAppMessage Describes an inline message sent by a Web App on behalf of a user.
 type SentWebAppMessage struct {
-	InlineMessageId string `json:"inline_message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
 }
 
 // SharedUser This object contains information about a user that was shared with the bot using a KeyboardButtonRequestUsers button.
 type SharedUser struct {
-	UserId int64 `json:"user_id"`
+	UserID int64 `json:"user_id"`
 	FirstName string `json:"first_name,omitempty"`
 	LastName string `json:"last_name,omitempty"`
 	Username string `json:"username,omitempty"`
@@ -2073,14 +2268,14 @@ type ShippingAddress struct {
 
 // ShippingOption This object represents one shipping option.
 type ShippingOption struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Title string `json:"title"`
 	Prices []LabeledPrice `json:"prices"`
 }
 
 // ShippingQuery This object contains information about an incoming shipping query.
 type ShippingQuery struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	From User `json:"from"`
 	InvoicePayload string `json:"invoice_payload"`
 	ShippingAddress ShippingAddress `json:"shipping_address"`
@@ -2094,12 +2289,12 @@ type StarAmount struct {
 
 // StarTransaction Describes a Telegram Star transaction. Note that if the buyer initiates a chargeback with the payment provider from whom they acquired Stars (e.g., Apple, Google) following this transaction, the refunded Stars will be deducted from the bot's balance. This is outside of Telegram's control.
 type StarTransaction struct {
-	Id string `json:"id"`
+	ID string `json:"id"`
 	Amount int64 `json:"amount"`
 	NanostarAmount *int64 `json:"nanostar_amount,omitempty"`
 	Date int64 `json:"date"`
-	Source *TransactionPartner `json:"source,omitempty"`
-	Receiver *TransactionPartner `json:"receiver,omitempty"`
+	Source TransactionPartner `json:"source,omitempty"`
+	Receiver TransactionPartner `json:"receiver,omitempty"`
 }
 
 // StarTransactions Contains a list of Telegram Star transactions.
@@ -2109,8 +2304,8 @@ type StarTransactions struct {
 
 // Sticker This object represents a sticker.
 type Sticker struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Type string `json:"type"`
 	Width int64 `json:"width"`
 	Height int64 `json:"height"`
@@ -2121,7 +2316,7 @@ type Sticker struct {
 	SetName string `json:"set_name,omitempty"`
 	PremiumAnimation *File `json:"premium_animation,omitempty"`
 	MaskPosition *MaskPosition `json:"mask_position,omitempty"`
-	CustomEmojiId string `json:"custom_emoji_id,omitempty"`
+	CustomEmojiID string `json:"custom_emoji_id,omitempty"`
 	NeedsRepainting *bool `json:"needs_repainting,omitempty"`
 	FileSize *int64 `json:"file_size,omitempty"`
 }
@@ -2138,7 +2333,7 @@ type StickerSet struct {
 // Story This object represents a story.
 type Story struct {
 	Chat Chat `json:"chat"`
-	Id int64 `json:"id"`
+	ID int64 `json:"id"`
 }
 
 // StoryArea Describes a clickable area on a story media.
@@ -2158,15 +2353,18 @@ type StoryAreaPosition struct {
 }
 
 // StoryAreaType Describes the type of a clickable area on a story. Currently, it can be one of
-type StoryAreaType struct {
+type StoryAreaType interface {
+	isStoryAreaType()
 }
 
 // StoryAreaTypeLink Describes a story area pointing to an HTTP or tg:// link. Currently, a story can have up to 3 link areas.
 type StoryAreaTypeLink struct {
 	Type string `json:"type"`
-	Url string `json:"url"`
+	URL string `json:"url"`
 }
 
+func (*StoryAreaTypeLink) isStoryAreaType() {}
+
 // StoryAreaTypeLocation Describes a story area pointing to a location. Currently, a story can have up to 10 location areas.
 type StoryAreaTypeLocation struct {
 	Type string `json:"type"`
@@ -2175,6 +2373,8 @@ type StoryAreaTypeLocation struct {
 	Address *LocationAddress `json:"address,omitempty"`
 }
 
+func (*StoryAreaTypeLocation) isStoryAreaType() {}
+
 // StoryAreaTypeSuggestedReaction Describes a story area pointing to a suggested reaction. Currently, a story can have up to 5 suggested reaction areas.
 type StoryAreaTypeSuggestedReaction struct {
 	Type string `json:"type"`
@@ -2183,12 +2383,16 @@ type StoryAreaTypeSuggestedReaction struct {
 	IsFlipped *bool `json:"is_flipped,omitempty"`
 }
 
+func (*StoryAreaTypeSuggestedReaction) isStoryAreaType() {}
+
 // StoryAreaTypeUniqueGift Describes a story area pointing to a unique gift. Currently, a story can have at most 1 unique gift area.
 type StoryAreaTypeUniqueGift struct {
 	Type string `json:"type"`
 	Name string `json:"name"`
 }
 
+func (*StoryAreaTypeUniqueGift) isStoryAreaType() {}
+
 // StoryAreaTypeWeather Describes a story area containing weather information. Currently, a story can have up to 3 weather areas.
 type StoryAreaTypeWeather struct {
 	Type string `json:"type"`
@@ -2197,6 +2401,8 @@ type StoryAreaTypeWeather struct {
 	BackgroundColor int64 `json:"background_color"`
 }
 
+func (*StoryAreaTypeWeather) isStoryAreaType() {}
+
 // SuccessfulPayment This object contains basic information about a successful payment. Note that if the buyer initiates a chargeback with the relevant payment provider following this transaction, the funds may be debited from your balance. This is outside of Telegram's control.
 type SuccessfulPayment struct {
 	Currency string `json:"currency"`
@@ -2205,10 +2411,10 @@ type SuccessfulPayment struct {
 	SubscriptionExpirationDate *int64 `json:"subscription_expiration_date,omitempty"`
 	IsRecurring *bool `json:"is_recurring,omitempty"`
 	IsFirstRecurring *bool `json:"is_first_recurring,omitempty"`
-	ShippingOptionId string `json:"shipping_option_id,omitempty"`
+	ShippingOptionID string `json:"shipping_option_id,omitempty"`
 	OrderInfo *OrderInfo `json:"order_info,omitempty"`
-	TelegramPaymentChargeId string `json:"telegram_payment_charge_id"`
-	ProviderPaymentChargeId string `json:"provider_payment_charge_id"`
+	TelegramPaymentChargeID string `json:"telegram_payment_charge_id"`
+	ProviderPaymentChargeID string `json:"provider_payment_charge_id"`
 }
 
 // SuggestedPostApprovalFailed Describes a service message about the failed approval of a suggested post. Currently, only caused by insufficient user funds at the time of approval.
@@ -2281,7 +2487,8 @@ type TextQuote struct {
 }
 
 // TransactionPartner This object describes the source of a transaction, or its recipient for outgoing transactions. Currently, it can be one of
-type TransactionPartner struct {
+type TransactionPartner interface {
+	isTransactionPartner()
 }
 
 // TransactionPartnerAffiliateProgram Describes the affiliate program that issued the affiliate commission received via this transaction.
@@ -2291,6 +2498,8 @@ type TransactionPartnerAffiliateProgram struct {
 	CommissionPerMille int64 `json:"commission_per_mille"`
 }
 
+func (*TransactionPartnerAffiliateProgram) isTransactionPartner() {}
+
 // TransactionPartnerChat Describes a transaction with a chat.
 type TransactionPartnerChat struct {
 	Type string `json:"type"`
@@ -2298,28 +2507,38 @@ type TransactionPartnerChat struct {
 	Gift *Gift `json:"gift,omitempty"`
 }
 
+func (*TransactionPartnerChat) isTransactionPartner() {}
+
 // TransactionPartnerFragment Describes a withdrawal transaction with Fragment.
 type TransactionPartnerFragment struct {
 	Type string `json:"type"`
-	WithdrawalState *RevenueWithdrawalState `json:"withdrawal_state,omitempty"`
+	WithdrawalState RevenueWithdrawalState `json:"withdrawal_state,omitempty"`
 }
 
+func (*TransactionPartnerFragment) isTransactionPartner() {}
+
 // TransactionPartnerOther Describes a transaction with an unknown source or recipient.
 type TransactionPartnerOther struct {
 	Type string `json:"type"`
 }
 
+func (*TransactionPartnerOther) isTransactionPartner() {}
+
 // TransactionPartnerTelegramAds Describes a withdrawal transaction to the Telegram Ads platform.
 type TransactionPartnerTelegramAds struct {
 	Type string `json:"type"`
 }
 
+func (*TransactionPartnerTelegramAds) isTransactionPartner() {}
+
 // TransactionPartnerTelegramApi Describes a transaction with payment for paid broadcasting.
 type TransactionPartnerTelegramApi struct {
 	Type string `json:"type"`
 	RequestCount int64 `json:"request_count"`
 }
 
+func (*TransactionPartnerTelegramApi) isTransactionPartner() {}
+
 // TransactionPartnerUser Describes a transaction with a user.
 type TransactionPartnerUser struct {
 	Type string `json:"type"`
@@ -2334,6 +2553,8 @@ type TransactionPartnerUser struct {
 	PremiumSubscriptionDuration *int64 `json:"premium_subscription_duration,omitempty"`
 }
 
+func (*TransactionPartnerUser) isTransactionPartner() {}
+
 // UniqueGift This object describes a unique gift that was upgraded from a regular gift.
 type UniqueGift struct {
 	BaseName string `json:"base_name"`
@@ -2365,7 +2586,7 @@ type UniqueGiftInfo struct {
 	Gift UniqueGift `json:"gift"`
 	Origin string `json:"origin"`
 	LastResaleStarCount *int64 `json:"last_resale_star_count,omitempty"`
-	OwnedGiftId string `json:"owned_gift_id,omitempty"`
+	OwnedGiftID string `json:"owned_gift_id,omitempty"`
 	TransferStarCount *int64 `json:"transfer_star_count,omitempty"`
 	NextTransferDate *int64 `json:"next_transfer_date,omitempty"`
 }
@@ -2386,7 +2607,7 @@ type UniqueGiftSymbol struct {
 
 // Update This object represents an incoming update.
 type Update struct {
-	UpdateId int64 `json:"update_id"`
+	UpdateID int64 `json:"update_id"`
 	Message *Message `json:"message,omitempty"`
 	EditedMessage *Message `json:"edited_message,omitempty"`
 	ChannelPost *Message `json:"channel_post,omitempty"`
@@ -2414,7 +2635,7 @@ type Update struct {
 
 // User This object represents a Telegram user or bot.
 type User struct {
-	Id int64 `json:"id"`
+	ID int64 `json:"id"`
 	IsBot bool `json:"is_bot"`
 	FirstName string `json:"first_name"`
 	LastName string `json:"last_name,omitempty"`
@@ -2442,7 +2663,7 @@ type UserProfilePhotos struct {
 
 // UsersShared This object contains information about the users whose identifiers were shared with the bot using a KeyboardButtonRequestUsers button.
 type UsersShared struct {
-	RequestId int64 `json:"request_id"`
+	RequestID int64 `json:"request_id"`
 	Users []SharedUser `json:"users"`
 }
 
@@ -2451,16 +2672,16 @@ type Venue struct {
 	Location Location `json:"location"`
 	Title string `json:"title"`
 	Address string `json:"address"`
-	FoursquareId string `json:"foursquare_id,omitempty"`
+	FoursquareID string `json:"foursquare_id,omitempty"`
 	FoursquareType string `json:"foursquare_type,omitempty"`
-	GooglePlaceId string `json:"google_place_id,omitempty"`
+	GooglePlaceID string `json:"google_place_id,omitempty"`
 	GooglePlaceType string `json:"google_place_type,omitempty"`
 }
 
 // Video This object represents a video file.
 type Video struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Width int64 `json:"width"`
 	Height int64 `json:"height"`
 	Duration int64 `json:"duration"`
@@ -2493,8 +2714,8 @@ type VideoChatStarted struct {
 
 // VideoNote This object represents a video message (available in Telegram apps as of v.4.0).
 type VideoNote struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Length int64 `json:"length"`
 	Duration int64 `json:"duration"`
 	Thumbnail *PhotoSize `json:"thumbnail,omitempty"`
@@ -2503,8 +2724,8 @@ type VideoNote struct {
 
 // Voice This object represents a voice note.
 type Voice struct {
-	FileId string `json:"file_id"`
-	FileUniqueId string `json:"file_unique_id"`
+	FileID string `json:"file_id"`
+	FileUniqueID string `json:"file_unique_id"`
 	Duration int64 `json:"duration"`
 	MimeType string `json:"mime_type,omitempty"`
 	FileSize *int64 `json:"file_size,omitempty"`
@@ -2518,12 +2739,12 @@ type WebAppData struct {
 
 // WebAppInfo Describes a Web App.
 type WebAppInfo struct {
-	Url string `json:"url"`
+	URL string `json:"url"`
 }
 
 // WebhookInfo Describes the current status of a webhook.
 type WebhookInfo struct {
-	Url string `json:"url"`
+	URL string `json:"url"`
 	HasCustomCertificate bool `json:"has_custom_certificate"`
 	PendingUpdateCount int64 `json:"pending_update_count"`
 	IpAddress string `json:"ip_address,omitempty"`
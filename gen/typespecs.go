@@ -0,0 +1,2797 @@
+// Code generated by codegen. DO NOT EDIT.
+package gen
+
+// TypeSpec describes a Bot API type. Union types such as ChatMember
+// have no fields and list the types they can be instead.
+type TypeSpec struct {
+	Name     string
+	Fields   []FieldSpec
+	Subtypes []string
+}
+
+// Types is the registry of all Bot API types
+var Types = map[string]TypeSpec{
+	"AcceptedGiftTypes": {
+		Name: "AcceptedGiftTypes",
+		Fields: []FieldSpec{
+			{Name: "unlimited_gifts", Types: []string{"Boolean"}, Required: true},
+			{Name: "limited_gifts", Types: []string{"Boolean"}, Required: true},
+			{Name: "unique_gifts", Types: []string{"Boolean"}, Required: true},
+			{Name: "premium_subscription", Types: []string{"Boolean"}, Required: true},
+		},
+	},
+	"AffiliateInfo": {
+		Name: "AffiliateInfo",
+		Fields: []FieldSpec{
+			{Name: "affiliate_user", Types: []string{"User"}, Required: false},
+			{Name: "affiliate_chat", Types: []string{"Chat"}, Required: false},
+			{Name: "commission_per_mille", Types: []string{"Integer"}, Required: true},
+			{Name: "amount", Types: []string{"Integer"}, Required: true},
+			{Name: "nanostar_amount", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"Animation": {
+		Name: "Animation",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "width", Types: []string{"Integer"}, Required: true},
+			{Name: "height", Types: []string{"Integer"}, Required: true},
+			{Name: "duration", Types: []string{"Integer"}, Required: true},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+			{Name: "file_name", Types: []string{"String"}, Required: false},
+			{Name: "mime_type", Types: []string{"String"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"Audio": {
+		Name: "Audio",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "duration", Types: []string{"Integer"}, Required: true},
+			{Name: "performer", Types: []string{"String"}, Required: false},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "file_name", Types: []string{"String"}, Required: false},
+			{Name: "mime_type", Types: []string{"String"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+		},
+	},
+	"BackgroundFill": {
+		Name: "BackgroundFill",
+		Subtypes: []string{"BackgroundFillSolid", "BackgroundFillGradient", "BackgroundFillFreeformGradient"},
+	},
+	"BackgroundFillFreeformGradient": {
+		Name: "BackgroundFillFreeformGradient",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"freeform_gradient"}}},
+			{Name: "colors", Types: []string{"Array of Integer"}, Required: true},
+		},
+	},
+	"BackgroundFillGradient": {
+		Name: "BackgroundFillGradient",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"gradient"}}},
+			{Name: "top_color", Types: []string{"Integer"}, Required: true},
+			{Name: "bottom_color", Types: []string{"Integer"}, Required: true},
+			{Name: "rotation_angle", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 0, Max: 359}},
+		},
+	},
+	"BackgroundFillSolid": {
+		Name: "BackgroundFillSolid",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"solid"}}},
+			{Name: "color", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"BackgroundType": {
+		Name: "BackgroundType",
+		Subtypes: []string{"BackgroundTypeFill", "BackgroundTypeWallpaper", "BackgroundTypePattern", "BackgroundTypeChatTheme"},
+	},
+	"BackgroundTypeChatTheme": {
+		Name: "BackgroundTypeChatTheme",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"chat_theme"}}},
+			{Name: "theme_name", Types: []string{"String"}, Required: true},
+		},
+	},
+	"BackgroundTypeFill": {
+		Name: "BackgroundTypeFill",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"fill"}}},
+			{Name: "fill", Types: []string{"BackgroundFill"}, Required: true},
+			{Name: "dark_theme_dimming", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 0, Max: 100}},
+		},
+	},
+	"BackgroundTypePattern": {
+		Name: "BackgroundTypePattern",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"pattern"}}},
+			{Name: "document", Types: []string{"Document"}, Required: true},
+			{Name: "fill", Types: []string{"BackgroundFill"}, Required: true},
+			{Name: "intensity", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 0, Max: 100}},
+			{Name: "is_inverted", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_moving", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"BackgroundTypeWallpaper": {
+		Name: "BackgroundTypeWallpaper",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"wallpaper"}}},
+			{Name: "document", Types: []string{"Document"}, Required: true},
+			{Name: "dark_theme_dimming", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 0, Max: 100}},
+			{Name: "is_blurred", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_moving", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"Birthdate": {
+		Name: "Birthdate",
+		Fields: []FieldSpec{
+			{Name: "day", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 31}},
+			{Name: "month", Types: []string{"Integer"}, Required: true, Constraint: &Constraint{Min: 1, Max: 12}},
+			{Name: "year", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"BotCommand": {
+		Name: "BotCommand",
+		Fields: []FieldSpec{
+			{Name: "command", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 32, Unit: "characters"}},
+			{Name: "description", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 256, Unit: "characters"}},
+		},
+	},
+	"BotCommandScope": {
+		Name: "BotCommandScope",
+		Subtypes: []string{"BotCommandScopeDefault", "BotCommandScopeAllPrivateChats", "BotCommandScopeAllGroupChats", "BotCommandScopeAllChatAdministrators", "BotCommandScopeChat", "BotCommandScopeChatAdministrators", "BotCommandScopeChatMember"},
+	},
+	"BotCommandScopeAllChatAdministrators": {
+		Name: "BotCommandScopeAllChatAdministrators",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"all_chat_administrators"}}},
+		},
+	},
+	"BotCommandScopeAllGroupChats": {
+		Name: "BotCommandScopeAllGroupChats",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"all_group_chats"}}},
+		},
+	},
+	"BotCommandScopeAllPrivateChats": {
+		Name: "BotCommandScopeAllPrivateChats",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"all_private_chats"}}},
+		},
+	},
+	"BotCommandScopeChat": {
+		Name: "BotCommandScopeChat",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"chat"}}},
+			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
+		},
+	},
+	"BotCommandScopeChatAdministrators": {
+		Name: "BotCommandScopeChatAdministrators",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"chat_administrators"}}},
+			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
+		},
+	},
+	"BotCommandScopeChatMember": {
+		Name: "BotCommandScopeChatMember",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"chat_member"}}},
+			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: true},
+			{Name: "user_id", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"BotCommandScopeDefault": {
+		Name: "BotCommandScopeDefault",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"default"}}},
+		},
+	},
+	"BotDescription": {
+		Name: "BotDescription",
+		Fields: []FieldSpec{
+			{Name: "description", Types: []string{"String"}, Required: true},
+		},
+	},
+	"BotName": {
+		Name: "BotName",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: true},
+		},
+	},
+	"BotShortDescription": {
+		Name: "BotShortDescription",
+		Fields: []FieldSpec{
+			{Name: "short_description", Types: []string{"String"}, Required: true},
+		},
+	},
+	"BusinessBotRights": {
+		Name: "BusinessBotRights",
+		Fields: []FieldSpec{
+			{Name: "can_reply", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_read_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_delete_sent_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_delete_all_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_edit_name", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_edit_bio", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_edit_profile_photo", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_edit_username", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_change_gift_settings", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_view_gifts_and_stars", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_convert_gifts_to_stars", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_transfer_and_upgrade_gifts", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_transfer_stars", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_manage_stories", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"BusinessConnection": {
+		Name: "BusinessConnection",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "user_chat_id", Types: []string{"Integer"}, Required: true},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "rights", Types: []string{"BusinessBotRights"}, Required: false},
+			{Name: "is_enabled", Types: []string{"Boolean"}, Required: true},
+		},
+	},
+	"BusinessIntro": {
+		Name: "BusinessIntro",
+		Fields: []FieldSpec{
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "message", Types: []string{"String"}, Required: false},
+			{Name: "sticker", Types: []string{"Sticker"}, Required: false},
+		},
+	},
+	"BusinessLocation": {
+		Name: "BusinessLocation",
+		Fields: []FieldSpec{
+			{Name: "address", Types: []string{"String"}, Required: true},
+			{Name: "location", Types: []string{"Location"}, Required: false},
+		},
+	},
+	"BusinessMessagesDeleted": {
+		Name: "BusinessMessagesDeleted",
+		Fields: []FieldSpec{
+			{Name: "business_connection_id", Types: []string{"String"}, Required: true},
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "message_ids", Types: []string{"Array of Integer"}, Required: true},
+		},
+	},
+	"BusinessOpeningHours": {
+		Name: "BusinessOpeningHours",
+		Fields: []FieldSpec{
+			{Name: "time_zone_name", Types: []string{"String"}, Required: true},
+			{Name: "opening_hours", Types: []string{"Array of BusinessOpeningHoursInterval"}, Required: true},
+		},
+	},
+	"BusinessOpeningHoursInterval": {
+		Name: "BusinessOpeningHoursInterval",
+		Fields: []FieldSpec{
+			{Name: "opening_minute", Types: []string{"Integer"}, Required: true},
+			{Name: "closing_minute", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"CallbackGame": {
+		Name: "CallbackGame",
+	},
+	"CallbackQuery": {
+		Name: "CallbackQuery",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "message", Types: []string{"MaybeInaccessibleMessage"}, Required: false},
+			{Name: "inline_message_id", Types: []string{"String"}, Required: false},
+			{Name: "chat_instance", Types: []string{"String"}, Required: true},
+			{Name: "data", Types: []string{"String"}, Required: false},
+			{Name: "game_short_name", Types: []string{"String"}, Required: false},
+		},
+	},
+	"Chat": {
+		Name: "Chat",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"Integer"}, Required: true},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "username", Types: []string{"String"}, Required: false},
+			{Name: "first_name", Types: []string{"String"}, Required: false},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "is_forum", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_direct_messages", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ChatAdministratorRights": {
+		Name: "ChatAdministratorRights",
+		Fields: []FieldSpec{
+			{Name: "is_anonymous", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_manage_chat", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_delete_messages", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_manage_video_chats", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_restrict_members", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_promote_members", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_change_info", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_invite_users", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_post_stories", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_edit_stories", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_delete_stories", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_post_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_edit_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_pin_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_manage_topics", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_manage_direct_messages", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ChatBackground": {
+		Name: "ChatBackground",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"BackgroundType"}, Required: true},
+		},
+	},
+	"ChatBoost": {
+		Name: "ChatBoost",
+		Fields: []FieldSpec{
+			{Name: "boost_id", Types: []string{"String"}, Required: true},
+			{Name: "add_date", Types: []string{"Integer"}, Required: true},
+			{Name: "expiration_date", Types: []string{"Integer"}, Required: true},
+			{Name: "source", Types: []string{"ChatBoostSource"}, Required: true},
+		},
+	},
+	"ChatBoostAdded": {
+		Name: "ChatBoostAdded",
+		Fields: []FieldSpec{
+			{Name: "boost_count", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"ChatBoostRemoved": {
+		Name: "ChatBoostRemoved",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "boost_id", Types: []string{"String"}, Required: true},
+			{Name: "remove_date", Types: []string{"Integer"}, Required: true},
+			{Name: "source", Types: []string{"ChatBoostSource"}, Required: true},
+		},
+	},
+	"ChatBoostSource": {
+		Name: "ChatBoostSource",
+		Subtypes: []string{"ChatBoostSourcePremium", "ChatBoostSourceGiftCode", "ChatBoostSourceGiveaway"},
+	},
+	"ChatBoostSourceGiftCode": {
+		Name: "ChatBoostSourceGiftCode",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"gift_code"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+		},
+	},
+	"ChatBoostSourceGiveaway": {
+		Name: "ChatBoostSourceGiveaway",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"giveaway"}}},
+			{Name: "giveaway_message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "user", Types: []string{"User"}, Required: false},
+			{Name: "prize_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "is_unclaimed", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ChatBoostSourcePremium": {
+		Name: "ChatBoostSourcePremium",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"premium"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+		},
+	},
+	"ChatBoostUpdated": {
+		Name: "ChatBoostUpdated",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "boost", Types: []string{"ChatBoost"}, Required: true},
+		},
+	},
+	"ChatFullInfo": {
+		Name: "ChatFullInfo",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"Integer"}, Required: true},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "username", Types: []string{"String"}, Required: false},
+			{Name: "first_name", Types: []string{"String"}, Required: false},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "is_forum", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_direct_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "accent_color_id", Types: []string{"Integer"}, Required: true},
+			{Name: "max_reaction_count", Types: []string{"Integer"}, Required: true},
+			{Name: "photo", Types: []string{"ChatPhoto"}, Required: false},
+			{Name: "active_usernames", Types: []string{"Array of String"}, Required: false},
+			{Name: "birthdate", Types: []string{"Birthdate"}, Required: false},
+			{Name: "business_intro", Types: []string{"BusinessIntro"}, Required: false},
+			{Name: "business_location", Types: []string{"BusinessLocation"}, Required: false},
+			{Name: "business_opening_hours", Types: []string{"BusinessOpeningHours"}, Required: false},
+			{Name: "personal_chat", Types: []string{"Chat"}, Required: false},
+			{Name: "parent_chat", Types: []string{"Chat"}, Required: false},
+			{Name: "available_reactions", Types: []string{"Array of ReactionType"}, Required: false},
+			{Name: "background_custom_emoji_id", Types: []string{"String"}, Required: false},
+			{Name: "profile_accent_color_id", Types: []string{"Integer"}, Required: false},
+			{Name: "profile_background_custom_emoji_id", Types: []string{"String"}, Required: false},
+			{Name: "emoji_status_custom_emoji_id", Types: []string{"String"}, Required: false},
+			{Name: "emoji_status_expiration_date", Types: []string{"Integer"}, Required: false},
+			{Name: "bio", Types: []string{"String"}, Required: false},
+			{Name: "has_private_forwards", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_restricted_voice_and_video_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "join_to_send_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "join_by_request", Types: []string{"Boolean"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "invite_link", Types: []string{"String"}, Required: false},
+			{Name: "pinned_message", Types: []string{"Message"}, Required: false},
+			{Name: "permissions", Types: []string{"ChatPermissions"}, Required: false},
+			{Name: "accepted_gift_types", Types: []string{"AcceptedGiftTypes"}, Required: true},
+			{Name: "can_send_paid_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "slow_mode_delay", Types: []string{"Integer"}, Required: false},
+			{Name: "unrestrict_boost_count", Types: []string{"Integer"}, Required: false},
+			{Name: "message_auto_delete_time", Types: []string{"Integer"}, Required: false},
+			{Name: "has_aggressive_anti_spam_enabled", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_hidden_members", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_protected_content", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_visible_history", Types: []string{"Boolean"}, Required: false},
+			{Name: "sticker_set_name", Types: []string{"String"}, Required: false},
+			{Name: "can_set_sticker_set", Types: []string{"Boolean"}, Required: false},
+			{Name: "custom_emoji_sticker_set_name", Types: []string{"String"}, Required: false},
+			{Name: "linked_chat_id", Types: []string{"Integer"}, Required: false},
+			{Name: "location", Types: []string{"ChatLocation"}, Required: false},
+		},
+	},
+	"ChatInviteLink": {
+		Name: "ChatInviteLink",
+		Fields: []FieldSpec{
+			{Name: "invite_link", Types: []string{"String"}, Required: true},
+			{Name: "creator", Types: []string{"User"}, Required: true},
+			{Name: "creates_join_request", Types: []string{"Boolean"}, Required: true},
+			{Name: "is_primary", Types: []string{"Boolean"}, Required: true},
+			{Name: "is_revoked", Types: []string{"Boolean"}, Required: true},
+			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "expire_date", Types: []string{"Integer"}, Required: false},
+			{Name: "member_limit", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 99999}},
+			{Name: "pending_join_request_count", Types: []string{"Integer"}, Required: false},
+			{Name: "subscription_period", Types: []string{"Integer"}, Required: false},
+			{Name: "subscription_price", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"ChatJoinRequest": {
+		Name: "ChatJoinRequest",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "user_chat_id", Types: []string{"Integer"}, Required: true},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "bio", Types: []string{"String"}, Required: false},
+			{Name: "invite_link", Types: []string{"ChatInviteLink"}, Required: false},
+		},
+	},
+	"ChatLocation": {
+		Name: "ChatLocation",
+		Fields: []FieldSpec{
+			{Name: "location", Types: []string{"Location"}, Required: true},
+			{Name: "address", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+		},
+	},
+	"ChatMember": {
+		Name: "ChatMember",
+		Subtypes: []string{"ChatMemberOwner", "ChatMemberAdministrator", "ChatMemberMember", "ChatMemberRestricted", "ChatMemberLeft", "ChatMemberBanned"},
+	},
+	"ChatMemberAdministrator": {
+		Name: "ChatMemberAdministrator",
+		Fields: []FieldSpec{
+			{Name: "status", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"administrator"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "can_be_edited", Types: []string{"Boolean"}, Required: true},
+			{Name: "is_anonymous", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_manage_chat", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_delete_messages", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_manage_video_chats", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_restrict_members", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_promote_members", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_change_info", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_invite_users", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_post_stories", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_edit_stories", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_delete_stories", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_post_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_edit_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_pin_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_manage_topics", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_manage_direct_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "custom_title", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ChatMemberBanned": {
+		Name: "ChatMemberBanned",
+		Fields: []FieldSpec{
+			{Name: "status", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"kicked"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "until_date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"ChatMemberLeft": {
+		Name: "ChatMemberLeft",
+		Fields: []FieldSpec{
+			{Name: "status", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"left"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+		},
+	},
+	"ChatMemberMember": {
+		Name: "ChatMemberMember",
+		Fields: []FieldSpec{
+			{Name: "status", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"member"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "until_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"ChatMemberOwner": {
+		Name: "ChatMemberOwner",
+		Fields: []FieldSpec{
+			{Name: "status", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"creator"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "is_anonymous", Types: []string{"Boolean"}, Required: true},
+			{Name: "custom_title", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ChatMemberRestricted": {
+		Name: "ChatMemberRestricted",
+		Fields: []FieldSpec{
+			{Name: "status", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"restricted"}}},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "is_member", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_messages", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_audios", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_documents", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_photos", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_videos", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_video_notes", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_voice_notes", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_polls", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_send_other_messages", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_add_web_page_previews", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_change_info", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_invite_users", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_pin_messages", Types: []string{"Boolean"}, Required: true},
+			{Name: "can_manage_topics", Types: []string{"Boolean"}, Required: true},
+			{Name: "until_date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"ChatMemberUpdated": {
+		Name: "ChatMemberUpdated",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "old_chat_member", Types: []string{"ChatMember"}, Required: true},
+			{Name: "new_chat_member", Types: []string{"ChatMember"}, Required: true},
+			{Name: "invite_link", Types: []string{"ChatInviteLink"}, Required: false},
+			{Name: "via_join_request", Types: []string{"Boolean"}, Required: false},
+			{Name: "via_chat_folder_invite_link", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ChatPermissions": {
+		Name: "ChatPermissions",
+		Fields: []FieldSpec{
+			{Name: "can_send_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_audios", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_documents", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_photos", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_videos", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_video_notes", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_voice_notes", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_polls", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_send_other_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_add_web_page_previews", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_change_info", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_invite_users", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_pin_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_manage_topics", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ChatPhoto": {
+		Name: "ChatPhoto",
+		Fields: []FieldSpec{
+			{Name: "small_file_id", Types: []string{"String"}, Required: true},
+			{Name: "small_file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "big_file_id", Types: []string{"String"}, Required: true},
+			{Name: "big_file_unique_id", Types: []string{"String"}, Required: true},
+		},
+	},
+	"ChatShared": {
+		Name: "ChatShared",
+		Fields: []FieldSpec{
+			{Name: "request_id", Types: []string{"Integer"}, Required: true},
+			{Name: "chat_id", Types: []string{"Integer"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "username", Types: []string{"String"}, Required: false},
+			{Name: "photo", Types: []string{"Array of PhotoSize"}, Required: false},
+		},
+	},
+	"Checklist": {
+		Name: "Checklist",
+		Fields: []FieldSpec{
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "title_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "tasks", Types: []string{"Array of ChecklistTask"}, Required: true},
+			{Name: "others_can_add_tasks", Types: []string{"Boolean"}, Required: false},
+			{Name: "others_can_mark_tasks_as_done", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ChecklistTask": {
+		Name: "ChecklistTask",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"Integer"}, Required: true},
+			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "completed_by_user", Types: []string{"User"}, Required: false},
+			{Name: "completion_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"ChecklistTasksAdded": {
+		Name: "ChecklistTasksAdded",
+		Fields: []FieldSpec{
+			{Name: "checklist_message", Types: []string{"Message"}, Required: false},
+			{Name: "tasks", Types: []string{"Array of ChecklistTask"}, Required: true},
+		},
+	},
+	"ChecklistTasksDone": {
+		Name: "ChecklistTasksDone",
+		Fields: []FieldSpec{
+			{Name: "checklist_message", Types: []string{"Message"}, Required: false},
+			{Name: "marked_as_done_task_ids", Types: []string{"Array of Integer"}, Required: false},
+			{Name: "marked_as_not_done_task_ids", Types: []string{"Array of Integer"}, Required: false},
+		},
+	},
+	"ChosenInlineResult": {
+		Name: "ChosenInlineResult",
+		Fields: []FieldSpec{
+			{Name: "result_id", Types: []string{"String"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "location", Types: []string{"Location"}, Required: false},
+			{Name: "inline_message_id", Types: []string{"String"}, Required: false},
+			{Name: "query", Types: []string{"String"}, Required: true},
+		},
+	},
+	"Contact": {
+		Name: "Contact",
+		Fields: []FieldSpec{
+			{Name: "phone_number", Types: []string{"String"}, Required: true},
+			{Name: "first_name", Types: []string{"String"}, Required: true},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "user_id", Types: []string{"Integer"}, Required: false},
+			{Name: "vcard", Types: []string{"String"}, Required: false},
+		},
+	},
+	"CopyTextButton": {
+		Name: "CopyTextButton",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 256, Unit: "characters"}},
+		},
+	},
+	"Dice": {
+		Name: "Dice",
+		Fields: []FieldSpec{
+			{Name: "emoji", Types: []string{"String"}, Required: true},
+			{Name: "value", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"DirectMessagePriceChanged": {
+		Name: "DirectMessagePriceChanged",
+		Fields: []FieldSpec{
+			{Name: "are_direct_messages_enabled", Types: []string{"Boolean"}, Required: true},
+			{Name: "direct_message_star_count", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"DirectMessagesTopic": {
+		Name: "DirectMessagesTopic",
+		Fields: []FieldSpec{
+			{Name: "topic_id", Types: []string{"Integer"}, Required: true},
+			{Name: "user", Types: []string{"User"}, Required: false},
+		},
+	},
+	"Document": {
+		Name: "Document",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+			{Name: "file_name", Types: []string{"String"}, Required: false},
+			{Name: "mime_type", Types: []string{"String"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"EncryptedCredentials": {
+		Name: "EncryptedCredentials",
+		Fields: []FieldSpec{
+			{Name: "data", Types: []string{"String"}, Required: true},
+			{Name: "hash", Types: []string{"String"}, Required: true},
+			{Name: "secret", Types: []string{"String"}, Required: true},
+		},
+	},
+	"EncryptedPassportElement": {
+		Name: "EncryptedPassportElement",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "data", Types: []string{"String"}, Required: false},
+			{Name: "phone_number", Types: []string{"String"}, Required: false},
+			{Name: "email", Types: []string{"String"}, Required: false},
+			{Name: "files", Types: []string{"Array of PassportFile"}, Required: false},
+			{Name: "front_side", Types: []string{"PassportFile"}, Required: false},
+			{Name: "reverse_side", Types: []string{"PassportFile"}, Required: false},
+			{Name: "selfie", Types: []string{"PassportFile"}, Required: false},
+			{Name: "translation", Types: []string{"Array of PassportFile"}, Required: false},
+			{Name: "hash", Types: []string{"String"}, Required: true},
+		},
+	},
+	"ExternalReplyInfo": {
+		Name: "ExternalReplyInfo",
+		Fields: []FieldSpec{
+			{Name: "origin", Types: []string{"MessageOrigin"}, Required: true},
+			{Name: "chat", Types: []string{"Chat"}, Required: false},
+			{Name: "message_id", Types: []string{"Integer"}, Required: false},
+			{Name: "link_preview_options", Types: []string{"LinkPreviewOptions"}, Required: false},
+			{Name: "animation", Types: []string{"Animation"}, Required: false},
+			{Name: "audio", Types: []string{"Audio"}, Required: false},
+			{Name: "document", Types: []string{"Document"}, Required: false},
+			{Name: "paid_media", Types: []string{"PaidMediaInfo"}, Required: false},
+			{Name: "photo", Types: []string{"Array of PhotoSize"}, Required: false},
+			{Name: "sticker", Types: []string{"Sticker"}, Required: false},
+			{Name: "story", Types: []string{"Story"}, Required: false},
+			{Name: "video", Types: []string{"Video"}, Required: false},
+			{Name: "video_note", Types: []string{"VideoNote"}, Required: false},
+			{Name: "voice", Types: []string{"Voice"}, Required: false},
+			{Name: "has_media_spoiler", Types: []string{"Boolean"}, Required: false},
+			{Name: "checklist", Types: []string{"Checklist"}, Required: false},
+			{Name: "contact", Types: []string{"Contact"}, Required: false},
+			{Name: "dice", Types: []string{"Dice"}, Required: false},
+			{Name: "game", Types: []string{"Game"}, Required: false},
+			{Name: "giveaway", Types: []string{"Giveaway"}, Required: false},
+			{Name: "giveaway_winners", Types: []string{"GiveawayWinners"}, Required: false},
+			{Name: "invoice", Types: []string{"Invoice"}, Required: false},
+			{Name: "location", Types: []string{"Location"}, Required: false},
+			{Name: "poll", Types: []string{"Poll"}, Required: false},
+			{Name: "venue", Types: []string{"Venue"}, Required: false},
+		},
+	},
+	"File": {
+		Name: "File",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+			{Name: "file_path", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ForceReply": {
+		Name: "ForceReply",
+		Fields: []FieldSpec{
+			{Name: "force_reply", Types: []string{"Boolean"}, Required: true},
+			{Name: "input_field_placeholder", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+			{Name: "selective", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ForumTopic": {
+		Name: "ForumTopic",
+		Fields: []FieldSpec{
+			{Name: "message_thread_id", Types: []string{"Integer"}, Required: true},
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "icon_color", Types: []string{"Integer"}, Required: true},
+			{Name: "icon_custom_emoji_id", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ForumTopicClosed": {
+		Name: "ForumTopicClosed",
+	},
+	"ForumTopicCreated": {
+		Name: "ForumTopicCreated",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "icon_color", Types: []string{"Integer"}, Required: true},
+			{Name: "icon_custom_emoji_id", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ForumTopicEdited": {
+		Name: "ForumTopicEdited",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "icon_custom_emoji_id", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ForumTopicReopened": {
+		Name: "ForumTopicReopened",
+	},
+	"Game": {
+		Name: "Game",
+		Fields: []FieldSpec{
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "description", Types: []string{"String"}, Required: true},
+			{Name: "photo", Types: []string{"Array of PhotoSize"}, Required: true},
+			{Name: "text", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 4096, Unit: "characters"}},
+			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "animation", Types: []string{"Animation"}, Required: false},
+		},
+	},
+	"GameHighScore": {
+		Name: "GameHighScore",
+		Fields: []FieldSpec{
+			{Name: "position", Types: []string{"Integer"}, Required: true},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "score", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"GeneralForumTopicHidden": {
+		Name: "GeneralForumTopicHidden",
+	},
+	"GeneralForumTopicUnhidden": {
+		Name: "GeneralForumTopicUnhidden",
+	},
+	"Gift": {
+		Name: "Gift",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "sticker", Types: []string{"Sticker"}, Required: true},
+			{Name: "star_count", Types: []string{"Integer"}, Required: true},
+			{Name: "upgrade_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "total_count", Types: []string{"Integer"}, Required: false},
+			{Name: "remaining_count", Types: []string{"Integer"}, Required: false},
+			{Name: "publisher_chat", Types: []string{"Chat"}, Required: false},
+		},
+	},
+	"GiftInfo": {
+		Name: "GiftInfo",
+		Fields: []FieldSpec{
+			{Name: "gift", Types: []string{"Gift"}, Required: true},
+			{Name: "owned_gift_id", Types: []string{"String"}, Required: false},
+			{Name: "convert_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "prepaid_upgrade_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "can_be_upgraded", Types: []string{"Boolean"}, Required: false},
+			{Name: "text", Types: []string{"String"}, Required: false},
+			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "is_private", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"Gifts": {
+		Name: "Gifts",
+		Fields: []FieldSpec{
+			{Name: "gifts", Types: []string{"Array of Gift"}, Required: true},
+		},
+	},
+	"Giveaway": {
+		Name: "Giveaway",
+		Fields: []FieldSpec{
+			{Name: "chats", Types: []string{"Array of Chat"}, Required: true},
+			{Name: "winners_selection_date", Types: []string{"Integer"}, Required: true},
+			{Name: "winner_count", Types: []string{"Integer"}, Required: true},
+			{Name: "only_new_members", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_public_winners", Types: []string{"Boolean"}, Required: false},
+			{Name: "prize_description", Types: []string{"String"}, Required: false},
+			{Name: "country_codes", Types: []string{"Array of String"}, Required: false},
+			{Name: "prize_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "premium_subscription_month_count", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"GiveawayCompleted": {
+		Name: "GiveawayCompleted",
+		Fields: []FieldSpec{
+			{Name: "winner_count", Types: []string{"Integer"}, Required: true},
+			{Name: "unclaimed_prize_count", Types: []string{"Integer"}, Required: false},
+			{Name: "giveaway_message", Types: []string{"Message"}, Required: false},
+			{Name: "is_star_giveaway", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"GiveawayCreated": {
+		Name: "GiveawayCreated",
+		Fields: []FieldSpec{
+			{Name: "prize_star_count", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"GiveawayWinners": {
+		Name: "GiveawayWinners",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "giveaway_message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "winners_selection_date", Types: []string{"Integer"}, Required: true},
+			{Name: "winner_count", Types: []string{"Integer"}, Required: true},
+			{Name: "winners", Types: []string{"Array of User"}, Required: true},
+			{Name: "additional_chat_count", Types: []string{"Integer"}, Required: false},
+			{Name: "prize_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "premium_subscription_month_count", Types: []string{"Integer"}, Required: false},
+			{Name: "unclaimed_prize_count", Types: []string{"Integer"}, Required: false},
+			{Name: "only_new_members", Types: []string{"Boolean"}, Required: false},
+			{Name: "was_refunded", Types: []string{"Boolean"}, Required: false},
+			{Name: "prize_description", Types: []string{"String"}, Required: false},
+		},
+	},
+	"InaccessibleMessage": {
+		Name: "InaccessibleMessage",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"InlineKeyboardButton": {
+		Name: "InlineKeyboardButton",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "url", Types: []string{"String"}, Required: false},
+			{Name: "callback_data", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "web_app", Types: []string{"WebAppInfo"}, Required: false},
+			{Name: "login_url", Types: []string{"LoginUrl"}, Required: false},
+			{Name: "switch_inline_query", Types: []string{"String"}, Required: false},
+			{Name: "switch_inline_query_current_chat", Types: []string{"String"}, Required: false},
+			{Name: "switch_inline_query_chosen_chat", Types: []string{"SwitchInlineQueryChosenChat"}, Required: false},
+			{Name: "copy_text", Types: []string{"CopyTextButton"}, Required: false},
+			{Name: "callback_game", Types: []string{"CallbackGame"}, Required: false},
+			{Name: "pay", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InlineKeyboardMarkup": {
+		Name: "InlineKeyboardMarkup",
+		Fields: []FieldSpec{
+			{Name: "inline_keyboard", Types: []string{"Array of Array of InlineKeyboardButton"}, Required: true},
+		},
+	},
+	"InlineQuery": {
+		Name: "InlineQuery",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "query", Types: []string{"String"}, Required: true},
+			{Name: "offset", Types: []string{"String"}, Required: true},
+			{Name: "chat_type", Types: []string{"String"}, Required: false},
+			{Name: "location", Types: []string{"Location"}, Required: false},
+		},
+	},
+	"InlineQueryResult": {
+		Name: "InlineQueryResult",
+		Subtypes: []string{"InlineQueryResultCachedAudio", "InlineQueryResultCachedDocument", "InlineQueryResultCachedGif", "InlineQueryResultCachedMpeg4Gif", "InlineQueryResultCachedPhoto", "InlineQueryResultCachedSticker", "InlineQueryResultCachedVideo", "InlineQueryResultCachedVoice", "InlineQueryResultArticle", "InlineQueryResultAudio", "InlineQueryResultContact", "InlineQueryResultGame", "InlineQueryResultDocument", "InlineQueryResultGif", "InlineQueryResultLocation", "InlineQueryResultMpeg4Gif", "InlineQueryResultPhoto", "InlineQueryResultVenue", "InlineQueryResultVideo", "InlineQueryResultVoice"},
+	},
+	"InlineQueryResultArticle": {
+		Name: "InlineQueryResultArticle",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"article"}}},
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: true},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "url", Types: []string{"String"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: false},
+			{Name: "thumbnail_width", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_height", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"InlineQueryResultAudio": {
+		Name: "InlineQueryResultAudio",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"audio"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "audio_url", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "performer", Types: []string{"String"}, Required: false},
+			{Name: "audio_duration", Types: []string{"Integer"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedAudio": {
+		Name: "InlineQueryResultCachedAudio",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"audio"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "audio_file_id", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedDocument": {
+		Name: "InlineQueryResultCachedDocument",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"document"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "document_file_id", Types: []string{"String"}, Required: true},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedGif": {
+		Name: "InlineQueryResultCachedGif",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"gif"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "gif_file_id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedMpeg4Gif": {
+		Name: "InlineQueryResultCachedMpeg4Gif",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"mpeg4_gif"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "mpeg4_file_id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedPhoto": {
+		Name: "InlineQueryResultCachedPhoto",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"photo"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "photo_file_id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedSticker": {
+		Name: "InlineQueryResultCachedSticker",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"sticker"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "sticker_file_id", Types: []string{"String"}, Required: true},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedVideo": {
+		Name: "InlineQueryResultCachedVideo",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"video"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "video_file_id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultCachedVoice": {
+		Name: "InlineQueryResultCachedVoice",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"voice"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "voice_file_id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultContact": {
+		Name: "InlineQueryResultContact",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"contact"}}},
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "phone_number", Types: []string{"String"}, Required: true},
+			{Name: "first_name", Types: []string{"String"}, Required: true},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "vcard", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 2048, Unit: "bytes"}},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: false},
+			{Name: "thumbnail_width", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_height", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"InlineQueryResultDocument": {
+		Name: "InlineQueryResultDocument",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"document"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "document_url", Types: []string{"String"}, Required: true},
+			{Name: "mime_type", Types: []string{"String"}, Required: true},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: false},
+			{Name: "thumbnail_width", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_height", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"InlineQueryResultGame": {
+		Name: "InlineQueryResultGame",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"game"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "game_short_name", Types: []string{"String"}, Required: true},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+		},
+	},
+	"InlineQueryResultGif": {
+		Name: "InlineQueryResultGif",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"gif"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "gif_url", Types: []string{"String"}, Required: true},
+			{Name: "gif_width", Types: []string{"Integer"}, Required: false},
+			{Name: "gif_height", Types: []string{"Integer"}, Required: false},
+			{Name: "gif_duration", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail_mime_type", Types: []string{"String"}, Required: false, Constraint: &Constraint{Enum: []string{"image/jpeg", "image/gif", "video/mp4"}}},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultLocation": {
+		Name: "InlineQueryResultLocation",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"location"}}},
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "latitude", Types: []string{"Float"}, Required: true},
+			{Name: "longitude", Types: []string{"Float"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1500}},
+			{Name: "live_period", Types: []string{"Integer"}, Required: false},
+			{Name: "heading", Types: []string{"Integer"}, Required: false},
+			{Name: "proximity_alert_radius", Types: []string{"Integer"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: false},
+			{Name: "thumbnail_width", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_height", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"InlineQueryResultMpeg4Gif": {
+		Name: "InlineQueryResultMpeg4Gif",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"mpeg4_gif"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "mpeg4_url", Types: []string{"String"}, Required: true},
+			{Name: "mpeg4_width", Types: []string{"Integer"}, Required: false},
+			{Name: "mpeg4_height", Types: []string{"Integer"}, Required: false},
+			{Name: "mpeg4_duration", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail_mime_type", Types: []string{"String"}, Required: false, Constraint: &Constraint{Enum: []string{"image/jpeg", "image/gif", "video/mp4"}}},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultPhoto": {
+		Name: "InlineQueryResultPhoto",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"photo"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "photo_url", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: true},
+			{Name: "photo_width", Types: []string{"Integer"}, Required: false},
+			{Name: "photo_height", Types: []string{"Integer"}, Required: false},
+			{Name: "title", Types: []string{"String"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultVenue": {
+		Name: "InlineQueryResultVenue",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"venue"}}},
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "latitude", Types: []string{"Float"}, Required: true},
+			{Name: "longitude", Types: []string{"Float"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "address", Types: []string{"String"}, Required: true},
+			{Name: "foursquare_id", Types: []string{"String"}, Required: false},
+			{Name: "foursquare_type", Types: []string{"String"}, Required: false},
+			{Name: "google_place_id", Types: []string{"String"}, Required: false},
+			{Name: "google_place_type", Types: []string{"String"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: false},
+			{Name: "thumbnail_width", Types: []string{"Integer"}, Required: false},
+			{Name: "thumbnail_height", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"InlineQueryResultVideo": {
+		Name: "InlineQueryResultVideo",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"video"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "video_url", Types: []string{"String"}, Required: true},
+			{Name: "mime_type", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail_url", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "video_width", Types: []string{"Integer"}, Required: false},
+			{Name: "video_height", Types: []string{"Integer"}, Required: false},
+			{Name: "video_duration", Types: []string{"Integer"}, Required: false},
+			{Name: "description", Types: []string{"String"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultVoice": {
+		Name: "InlineQueryResultVoice",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"voice"}}},
+			{Name: "id", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 64, Unit: "bytes"}},
+			{Name: "voice_url", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "voice_duration", Types: []string{"Integer"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+			{Name: "input_message_content", Types: []string{"InputMessageContent"}, Required: false},
+		},
+	},
+	"InlineQueryResultsButton": {
+		Name: "InlineQueryResultsButton",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "web_app", Types: []string{"WebAppInfo"}, Required: false},
+			{Name: "start_parameter", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+		},
+	},
+	"InputChecklist": {
+		Name: "InputChecklist",
+		Fields: []FieldSpec{
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 255, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "title_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "tasks", Types: []string{"Array of InputChecklistTask"}, Required: true, Constraint: &Constraint{Min: 1, Max: 30, Unit: "items"}},
+			{Name: "others_can_add_tasks", Types: []string{"Boolean"}, Required: false},
+			{Name: "others_can_mark_tasks_as_done", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputChecklistTask": {
+		Name: "InputChecklistTask",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"Integer"}, Required: true},
+			{Name: "text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+		},
+	},
+	"InputContactMessageContent": {
+		Name: "InputContactMessageContent",
+		Fields: []FieldSpec{
+			{Name: "phone_number", Types: []string{"String"}, Required: true},
+			{Name: "first_name", Types: []string{"String"}, Required: true},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "vcard", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 2048, Unit: "bytes"}},
+		},
+	},
+	"InputFile": {
+		Name: "InputFile",
+	},
+	"InputInvoiceMessageContent": {
+		Name: "InputInvoiceMessageContent",
+		Fields: []FieldSpec{
+			{Name: "title", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 32, Unit: "characters"}},
+			{Name: "description", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 255, Unit: "characters"}},
+			{Name: "payload", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 128, Unit: "bytes"}},
+			{Name: "provider_token", Types: []string{"String"}, Required: false},
+			{Name: "currency", Types: []string{"String"}, Required: true},
+			{Name: "prices", Types: []string{"Array of LabeledPrice"}, Required: true},
+			{Name: "max_tip_amount", Types: []string{"Integer"}, Required: false},
+			{Name: "suggested_tip_amounts", Types: []string{"Array of Integer"}, Required: false},
+			{Name: "provider_data", Types: []string{"String"}, Required: false},
+			{Name: "photo_url", Types: []string{"String"}, Required: false},
+			{Name: "photo_size", Types: []string{"Integer"}, Required: false},
+			{Name: "photo_width", Types: []string{"Integer"}, Required: false},
+			{Name: "photo_height", Types: []string{"Integer"}, Required: false},
+			{Name: "need_name", Types: []string{"Boolean"}, Required: false},
+			{Name: "need_phone_number", Types: []string{"Boolean"}, Required: false},
+			{Name: "need_email", Types: []string{"Boolean"}, Required: false},
+			{Name: "need_shipping_address", Types: []string{"Boolean"}, Required: false},
+			{Name: "send_phone_number_to_provider", Types: []string{"Boolean"}, Required: false},
+			{Name: "send_email_to_provider", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_flexible", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputLocationMessageContent": {
+		Name: "InputLocationMessageContent",
+		Fields: []FieldSpec{
+			{Name: "latitude", Types: []string{"Float"}, Required: true},
+			{Name: "longitude", Types: []string{"Float"}, Required: true},
+			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1500}},
+			{Name: "live_period", Types: []string{"Integer"}, Required: false},
+			{Name: "heading", Types: []string{"Integer"}, Required: false},
+			{Name: "proximity_alert_radius", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"InputMedia": {
+		Name: "InputMedia",
+		Subtypes: []string{"InputMediaAnimation", "InputMediaDocument", "InputMediaAudio", "InputMediaPhoto", "InputMediaVideo"},
+	},
+	"InputMediaAnimation": {
+		Name: "InputMediaAnimation",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"animation"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "width", Types: []string{"Integer"}, Required: false},
+			{Name: "height", Types: []string{"Integer"}, Required: false},
+			{Name: "duration", Types: []string{"Integer"}, Required: false},
+			{Name: "has_spoiler", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputMediaAudio": {
+		Name: "InputMediaAudio",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"audio"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "duration", Types: []string{"Integer"}, Required: false},
+			{Name: "performer", Types: []string{"String"}, Required: false},
+			{Name: "title", Types: []string{"String"}, Required: false},
+		},
+	},
+	"InputMediaDocument": {
+		Name: "InputMediaDocument",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"document"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail", Types: []string{"String"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "disable_content_type_detection", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputMediaPhoto": {
+		Name: "InputMediaPhoto",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"photo"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_spoiler", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputMediaVideo": {
+		Name: "InputMediaVideo",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"video"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail", Types: []string{"String"}, Required: false},
+			{Name: "cover", Types: []string{"String"}, Required: false},
+			{Name: "start_timestamp", Types: []string{"Integer"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "width", Types: []string{"Integer"}, Required: false},
+			{Name: "height", Types: []string{"Integer"}, Required: false},
+			{Name: "duration", Types: []string{"Integer"}, Required: false},
+			{Name: "supports_streaming", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_spoiler", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputMessageContent": {
+		Name: "InputMessageContent",
+		Subtypes: []string{"InputTextMessageContent", "InputLocationMessageContent", "InputVenueMessageContent", "InputContactMessageContent", "InputInvoiceMessageContent"},
+	},
+	"InputPaidMedia": {
+		Name: "InputPaidMedia",
+		Subtypes: []string{"InputPaidMediaPhoto", "InputPaidMediaVideo"},
+	},
+	"InputPaidMediaPhoto": {
+		Name: "InputPaidMediaPhoto",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"photo"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+		},
+	},
+	"InputPaidMediaVideo": {
+		Name: "InputPaidMediaVideo",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"video"}}},
+			{Name: "media", Types: []string{"String"}, Required: true},
+			{Name: "thumbnail", Types: []string{"String"}, Required: false},
+			{Name: "cover", Types: []string{"String"}, Required: false},
+			{Name: "start_timestamp", Types: []string{"Integer"}, Required: false},
+			{Name: "width", Types: []string{"Integer"}, Required: false},
+			{Name: "height", Types: []string{"Integer"}, Required: false},
+			{Name: "duration", Types: []string{"Integer"}, Required: false},
+			{Name: "supports_streaming", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputPollOption": {
+		Name: "InputPollOption",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "characters"}},
+			{Name: "text_parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+		},
+	},
+	"InputProfilePhoto": {
+		Name: "InputProfilePhoto",
+		Subtypes: []string{"InputProfilePhotoStatic", "InputProfilePhotoAnimated"},
+	},
+	"InputProfilePhotoAnimated": {
+		Name: "InputProfilePhotoAnimated",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"animated"}}},
+			{Name: "animation", Types: []string{"String"}, Required: true},
+			{Name: "main_frame_timestamp", Types: []string{"Float"}, Required: false},
+		},
+	},
+	"InputProfilePhotoStatic": {
+		Name: "InputProfilePhotoStatic",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"static"}}},
+			{Name: "photo", Types: []string{"String"}, Required: true},
+		},
+	},
+	"InputSticker": {
+		Name: "InputSticker",
+		Fields: []FieldSpec{
+			{Name: "sticker", Types: []string{"String"}, Required: true},
+			{Name: "format", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"static", "animated", "video"}}},
+			{Name: "emoji_list", Types: []string{"Array of String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 20, Unit: "items"}},
+			{Name: "mask_position", Types: []string{"MaskPosition"}, Required: false},
+			{Name: "keywords", Types: []string{"Array of String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 20, Unit: "items"}},
+		},
+	},
+	"InputStoryContent": {
+		Name: "InputStoryContent",
+		Subtypes: []string{"InputStoryContentPhoto", "InputStoryContentVideo"},
+	},
+	"InputStoryContentPhoto": {
+		Name: "InputStoryContentPhoto",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"photo"}}},
+			{Name: "photo", Types: []string{"String"}, Required: true},
+		},
+	},
+	"InputStoryContentVideo": {
+		Name: "InputStoryContentVideo",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"video"}}},
+			{Name: "video", Types: []string{"String"}, Required: true},
+			{Name: "duration", Types: []string{"Float"}, Required: false, Constraint: &Constraint{Min: 0, Max: 60}},
+			{Name: "cover_frame_timestamp", Types: []string{"Float"}, Required: false},
+			{Name: "is_animation", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"InputTextMessageContent": {
+		Name: "InputTextMessageContent",
+		Fields: []FieldSpec{
+			{Name: "message_text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 4096, Unit: "characters"}},
+			{Name: "parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "link_preview_options", Types: []string{"LinkPreviewOptions"}, Required: false},
+		},
+	},
+	"InputVenueMessageContent": {
+		Name: "InputVenueMessageContent",
+		Fields: []FieldSpec{
+			{Name: "latitude", Types: []string{"Float"}, Required: true},
+			{Name: "longitude", Types: []string{"Float"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "address", Types: []string{"String"}, Required: true},
+			{Name: "foursquare_id", Types: []string{"String"}, Required: false},
+			{Name: "foursquare_type", Types: []string{"String"}, Required: false},
+			{Name: "google_place_id", Types: []string{"String"}, Required: false},
+			{Name: "google_place_type", Types: []string{"String"}, Required: false},
+		},
+	},
+	"Invoice": {
+		Name: "Invoice",
+		Fields: []FieldSpec{
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "description", Types: []string{"String"}, Required: true},
+			{Name: "start_parameter", Types: []string{"String"}, Required: true},
+			{Name: "currency", Types: []string{"String"}, Required: true},
+			{Name: "total_amount", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"KeyboardButton": {
+		Name: "KeyboardButton",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "request_users", Types: []string{"KeyboardButtonRequestUsers"}, Required: false},
+			{Name: "request_chat", Types: []string{"KeyboardButtonRequestChat"}, Required: false},
+			{Name: "request_contact", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_location", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_poll", Types: []string{"KeyboardButtonPollType"}, Required: false},
+			{Name: "web_app", Types: []string{"WebAppInfo"}, Required: false},
+		},
+	},
+	"KeyboardButtonPollType": {
+		Name: "KeyboardButtonPollType",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: false},
+		},
+	},
+	"KeyboardButtonRequestChat": {
+		Name: "KeyboardButtonRequestChat",
+		Fields: []FieldSpec{
+			{Name: "request_id", Types: []string{"Integer"}, Required: true},
+			{Name: "chat_is_channel", Types: []string{"Boolean"}, Required: true},
+			{Name: "chat_is_forum", Types: []string{"Boolean"}, Required: false},
+			{Name: "chat_has_username", Types: []string{"Boolean"}, Required: false},
+			{Name: "chat_is_created", Types: []string{"Boolean"}, Required: false},
+			{Name: "user_administrator_rights", Types: []string{"ChatAdministratorRights"}, Required: false},
+			{Name: "bot_administrator_rights", Types: []string{"ChatAdministratorRights"}, Required: false},
+			{Name: "bot_is_member", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_title", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_username", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_photo", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"KeyboardButtonRequestUsers": {
+		Name: "KeyboardButtonRequestUsers",
+		Fields: []FieldSpec{
+			{Name: "request_id", Types: []string{"Integer"}, Required: true},
+			{Name: "user_is_bot", Types: []string{"Boolean"}, Required: false},
+			{Name: "user_is_premium", Types: []string{"Boolean"}, Required: false},
+			{Name: "max_quantity", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 10}},
+			{Name: "request_name", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_username", Types: []string{"Boolean"}, Required: false},
+			{Name: "request_photo", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"LabeledPrice": {
+		Name: "LabeledPrice",
+		Fields: []FieldSpec{
+			{Name: "label", Types: []string{"String"}, Required: true},
+			{Name: "amount", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"LinkPreviewOptions": {
+		Name: "LinkPreviewOptions",
+		Fields: []FieldSpec{
+			{Name: "is_disabled", Types: []string{"Boolean"}, Required: false},
+			{Name: "url", Types: []string{"String"}, Required: false},
+			{Name: "prefer_small_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "prefer_large_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "show_above_text", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"Location": {
+		Name: "Location",
+		Fields: []FieldSpec{
+			{Name: "latitude", Types: []string{"Float"}, Required: true},
+			{Name: "longitude", Types: []string{"Float"}, Required: true},
+			{Name: "horizontal_accuracy", Types: []string{"Float"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1500}},
+			{Name: "live_period", Types: []string{"Integer"}, Required: false},
+			{Name: "heading", Types: []string{"Integer"}, Required: false, Constraint: &Constraint{Min: 1, Max: 360}},
+			{Name: "proximity_alert_radius", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"LocationAddress": {
+		Name: "LocationAddress",
+		Fields: []FieldSpec{
+			{Name: "country_code", Types: []string{"String"}, Required: true},
+			{Name: "state", Types: []string{"String"}, Required: false},
+			{Name: "city", Types: []string{"String"}, Required: false},
+			{Name: "street", Types: []string{"String"}, Required: false},
+		},
+	},
+	"LoginUrl": {
+		Name: "LoginUrl",
+		Fields: []FieldSpec{
+			{Name: "url", Types: []string{"String"}, Required: true},
+			{Name: "forward_text", Types: []string{"String"}, Required: false},
+			{Name: "bot_username", Types: []string{"String"}, Required: false},
+			{Name: "request_write_access", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"MaskPosition": {
+		Name: "MaskPosition",
+		Fields: []FieldSpec{
+			{Name: "point", Types: []string{"String"}, Required: true},
+			{Name: "x_shift", Types: []string{"Float"}, Required: true},
+			{Name: "y_shift", Types: []string{"Float"}, Required: true},
+			{Name: "scale", Types: []string{"Float"}, Required: true},
+		},
+	},
+	"MaybeInaccessibleMessage": {
+		Name: "MaybeInaccessibleMessage",
+		Subtypes: []string{"Message", "InaccessibleMessage"},
+	},
+	"MenuButton": {
+		Name: "MenuButton",
+		Subtypes: []string{"MenuButtonCommands", "MenuButtonWebApp", "MenuButtonDefault"},
+	},
+	"MenuButtonCommands": {
+		Name: "MenuButtonCommands",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"commands"}}},
+		},
+	},
+	"MenuButtonDefault": {
+		Name: "MenuButtonDefault",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"default"}}},
+		},
+	},
+	"MenuButtonWebApp": {
+		Name: "MenuButtonWebApp",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"web_app"}}},
+			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "web_app", Types: []string{"WebAppInfo"}, Required: true},
+		},
+	},
+	"Message": {
+		Name: "Message",
+		Fields: []FieldSpec{
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "message_thread_id", Types: []string{"Integer"}, Required: false},
+			{Name: "direct_messages_topic", Types: []string{"DirectMessagesTopic"}, Required: false},
+			{Name: "from", Types: []string{"User"}, Required: false},
+			{Name: "sender_chat", Types: []string{"Chat"}, Required: false},
+			{Name: "sender_boost_count", Types: []string{"Integer"}, Required: false},
+			{Name: "sender_business_bot", Types: []string{"User"}, Required: false},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "business_connection_id", Types: []string{"String"}, Required: false},
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "forward_origin", Types: []string{"MessageOrigin"}, Required: false},
+			{Name: "is_topic_message", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_automatic_forward", Types: []string{"Boolean"}, Required: false},
+			{Name: "reply_to_message", Types: []string{"Message"}, Required: false},
+			{Name: "external_reply", Types: []string{"ExternalReplyInfo"}, Required: false},
+			{Name: "quote", Types: []string{"TextQuote"}, Required: false},
+			{Name: "reply_to_story", Types: []string{"Story"}, Required: false},
+			{Name: "reply_to_checklist_task_id", Types: []string{"Integer"}, Required: false},
+			{Name: "via_bot", Types: []string{"User"}, Required: false},
+			{Name: "edit_date", Types: []string{"Integer"}, Required: false},
+			{Name: "has_protected_content", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_from_offline", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_paid_post", Types: []string{"Boolean"}, Required: false},
+			{Name: "media_group_id", Types: []string{"String"}, Required: false},
+			{Name: "author_signature", Types: []string{"String"}, Required: false},
+			{Name: "paid_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "text", Types: []string{"String"}, Required: false},
+			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "link_preview_options", Types: []string{"LinkPreviewOptions"}, Required: false},
+			{Name: "suggested_post_info", Types: []string{"SuggestedPostInfo"}, Required: false},
+			{Name: "effect_id", Types: []string{"String"}, Required: false},
+			{Name: "animation", Types: []string{"Animation"}, Required: false},
+			{Name: "audio", Types: []string{"Audio"}, Required: false},
+			{Name: "document", Types: []string{"Document"}, Required: false},
+			{Name: "paid_media", Types: []string{"PaidMediaInfo"}, Required: false},
+			{Name: "photo", Types: []string{"Array of PhotoSize"}, Required: false},
+			{Name: "sticker", Types: []string{"Sticker"}, Required: false},
+			{Name: "story", Types: []string{"Story"}, Required: false},
+			{Name: "video", Types: []string{"Video"}, Required: false},
+			{Name: "video_note", Types: []string{"VideoNote"}, Required: false},
+			{Name: "voice", Types: []string{"Voice"}, Required: false},
+			{Name: "caption", Types: []string{"String"}, Required: false},
+			{Name: "caption_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "show_caption_above_media", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_media_spoiler", Types: []string{"Boolean"}, Required: false},
+			{Name: "checklist", Types: []string{"Checklist"}, Required: false},
+			{Name: "contact", Types: []string{"Contact"}, Required: false},
+			{Name: "dice", Types: []string{"Dice"}, Required: false},
+			{Name: "game", Types: []string{"Game"}, Required: false},
+			{Name: "poll", Types: []string{"Poll"}, Required: false},
+			{Name: "venue", Types: []string{"Venue"}, Required: false},
+			{Name: "location", Types: []string{"Location"}, Required: false},
+			{Name: "new_chat_members", Types: []string{"Array of User"}, Required: false},
+			{Name: "left_chat_member", Types: []string{"User"}, Required: false},
+			{Name: "new_chat_title", Types: []string{"String"}, Required: false},
+			{Name: "new_chat_photo", Types: []string{"Array of PhotoSize"}, Required: false},
+			{Name: "delete_chat_photo", Types: []string{"Boolean"}, Required: false},
+			{Name: "group_chat_created", Types: []string{"Boolean"}, Required: false},
+			{Name: "supergroup_chat_created", Types: []string{"Boolean"}, Required: false},
+			{Name: "channel_chat_created", Types: []string{"Boolean"}, Required: false},
+			{Name: "message_auto_delete_timer_changed", Types: []string{"MessageAutoDeleteTimerChanged"}, Required: false},
+			{Name: "migrate_to_chat_id", Types: []string{"Integer"}, Required: false},
+			{Name: "migrate_from_chat_id", Types: []string{"Integer"}, Required: false},
+			{Name: "pinned_message", Types: []string{"MaybeInaccessibleMessage"}, Required: false},
+			{Name: "invoice", Types: []string{"Invoice"}, Required: false},
+			{Name: "successful_payment", Types: []string{"SuccessfulPayment"}, Required: false},
+			{Name: "refunded_payment", Types: []string{"RefundedPayment"}, Required: false},
+			{Name: "users_shared", Types: []string{"UsersShared"}, Required: false},
+			{Name: "chat_shared", Types: []string{"ChatShared"}, Required: false},
+			{Name: "gift", Types: []string{"GiftInfo"}, Required: false},
+			{Name: "unique_gift", Types: []string{"UniqueGiftInfo"}, Required: false},
+			{Name: "connected_website", Types: []string{"String"}, Required: false},
+			{Name: "write_access_allowed", Types: []string{"WriteAccessAllowed"}, Required: false},
+			{Name: "passport_data", Types: []string{"PassportData"}, Required: false},
+			{Name: "proximity_alert_triggered", Types: []string{"ProximityAlertTriggered"}, Required: false},
+			{Name: "boost_added", Types: []string{"ChatBoostAdded"}, Required: false},
+			{Name: "chat_background_set", Types: []string{"ChatBackground"}, Required: false},
+			{Name: "checklist_tasks_done", Types: []string{"ChecklistTasksDone"}, Required: false},
+			{Name: "checklist_tasks_added", Types: []string{"ChecklistTasksAdded"}, Required: false},
+			{Name: "direct_message_price_changed", Types: []string{"DirectMessagePriceChanged"}, Required: false},
+			{Name: "forum_topic_created", Types: []string{"ForumTopicCreated"}, Required: false},
+			{Name: "forum_topic_edited", Types: []string{"ForumTopicEdited"}, Required: false},
+			{Name: "forum_topic_closed", Types: []string{"ForumTopicClosed"}, Required: false},
+			{Name: "forum_topic_reopened", Types: []string{"ForumTopicReopened"}, Required: false},
+			{Name: "general_forum_topic_hidden", Types: []string{"GeneralForumTopicHidden"}, Required: false},
+			{Name: "general_forum_topic_unhidden", Types: []string{"GeneralForumTopicUnhidden"}, Required: false},
+			{Name: "giveaway_created", Types: []string{"GiveawayCreated"}, Required: false},
+			{Name: "giveaway", Types: []string{"Giveaway"}, Required: false},
+			{Name: "giveaway_winners", Types: []string{"GiveawayWinners"}, Required: false},
+			{Name: "giveaway_completed", Types: []string{"GiveawayCompleted"}, Required: false},
+			{Name: "paid_message_price_changed", Types: []string{"PaidMessagePriceChanged"}, Required: false},
+			{Name: "suggested_post_approved", Types: []string{"SuggestedPostApproved"}, Required: false},
+			{Name: "suggested_post_approval_failed", Types: []string{"SuggestedPostApprovalFailed"}, Required: false},
+			{Name: "suggested_post_declined", Types: []string{"SuggestedPostDeclined"}, Required: false},
+			{Name: "suggested_post_paid", Types: []string{"SuggestedPostPaid"}, Required: false},
+			{Name: "suggested_post_refunded", Types: []string{"SuggestedPostRefunded"}, Required: false},
+			{Name: "video_chat_scheduled", Types: []string{"VideoChatScheduled"}, Required: false},
+			{Name: "video_chat_started", Types: []string{"VideoChatStarted"}, Required: false},
+			{Name: "video_chat_ended", Types: []string{"VideoChatEnded"}, Required: false},
+			{Name: "video_chat_participants_invited", Types: []string{"VideoChatParticipantsInvited"}, Required: false},
+			{Name: "web_app_data", Types: []string{"WebAppData"}, Required: false},
+			{Name: "reply_markup", Types: []string{"InlineKeyboardMarkup"}, Required: false},
+		},
+	},
+	"MessageAutoDeleteTimerChanged": {
+		Name: "MessageAutoDeleteTimerChanged",
+		Fields: []FieldSpec{
+			{Name: "message_auto_delete_time", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"MessageEntity": {
+		Name: "MessageEntity",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "offset", Types: []string{"Integer"}, Required: true},
+			{Name: "length", Types: []string{"Integer"}, Required: true},
+			{Name: "url", Types: []string{"String"}, Required: false},
+			{Name: "user", Types: []string{"User"}, Required: false},
+			{Name: "language", Types: []string{"String"}, Required: false},
+			{Name: "custom_emoji_id", Types: []string{"String"}, Required: false},
+		},
+	},
+	"MessageId": {
+		Name: "MessageId",
+		Fields: []FieldSpec{
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"MessageOrigin": {
+		Name: "MessageOrigin",
+		Subtypes: []string{"MessageOriginUser", "MessageOriginHiddenUser", "MessageOriginChat", "MessageOriginChannel"},
+	},
+	"MessageOriginChannel": {
+		Name: "MessageOriginChannel",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"channel"}}},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "author_signature", Types: []string{"String"}, Required: false},
+		},
+	},
+	"MessageOriginChat": {
+		Name: "MessageOriginChat",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"chat"}}},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "sender_chat", Types: []string{"Chat"}, Required: true},
+			{Name: "author_signature", Types: []string{"String"}, Required: false},
+		},
+	},
+	"MessageOriginHiddenUser": {
+		Name: "MessageOriginHiddenUser",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"hidden_user"}}},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "sender_user_name", Types: []string{"String"}, Required: true},
+		},
+	},
+	"MessageOriginUser": {
+		Name: "MessageOriginUser",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"user"}}},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "sender_user", Types: []string{"User"}, Required: true},
+		},
+	},
+	"MessageReactionCountUpdated": {
+		Name: "MessageReactionCountUpdated",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "reactions", Types: []string{"Array of ReactionCount"}, Required: true},
+		},
+	},
+	"MessageReactionUpdated": {
+		Name: "MessageReactionUpdated",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "user", Types: []string{"User"}, Required: false},
+			{Name: "actor_chat", Types: []string{"Chat"}, Required: false},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "old_reaction", Types: []string{"Array of ReactionType"}, Required: true},
+			{Name: "new_reaction", Types: []string{"Array of ReactionType"}, Required: true},
+		},
+	},
+	"OrderInfo": {
+		Name: "OrderInfo",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: false},
+			{Name: "phone_number", Types: []string{"String"}, Required: false},
+			{Name: "email", Types: []string{"String"}, Required: false},
+			{Name: "shipping_address", Types: []string{"ShippingAddress"}, Required: false},
+		},
+	},
+	"OwnedGift": {
+		Name: "OwnedGift",
+		Subtypes: []string{"OwnedGiftRegular", "OwnedGiftUnique"},
+	},
+	"OwnedGiftRegular": {
+		Name: "OwnedGiftRegular",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"regular"}}},
+			{Name: "gift", Types: []string{"Gift"}, Required: true},
+			{Name: "owned_gift_id", Types: []string{"String"}, Required: false},
+			{Name: "sender_user", Types: []string{"User"}, Required: false},
+			{Name: "send_date", Types: []string{"Integer"}, Required: true},
+			{Name: "text", Types: []string{"String"}, Required: false},
+			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "is_private", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_saved", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_be_upgraded", Types: []string{"Boolean"}, Required: false},
+			{Name: "was_refunded", Types: []string{"Boolean"}, Required: false},
+			{Name: "convert_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "prepaid_upgrade_star_count", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"OwnedGiftUnique": {
+		Name: "OwnedGiftUnique",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"unique"}}},
+			{Name: "gift", Types: []string{"UniqueGift"}, Required: true},
+			{Name: "owned_gift_id", Types: []string{"String"}, Required: false},
+			{Name: "sender_user", Types: []string{"User"}, Required: false},
+			{Name: "send_date", Types: []string{"Integer"}, Required: true},
+			{Name: "is_saved", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_be_transferred", Types: []string{"Boolean"}, Required: false},
+			{Name: "transfer_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "next_transfer_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"OwnedGifts": {
+		Name: "OwnedGifts",
+		Fields: []FieldSpec{
+			{Name: "total_count", Types: []string{"Integer"}, Required: true},
+			{Name: "gifts", Types: []string{"Array of OwnedGift"}, Required: true},
+			{Name: "next_offset", Types: []string{"String"}, Required: false},
+		},
+	},
+	"PaidMedia": {
+		Name: "PaidMedia",
+		Subtypes: []string{"PaidMediaPreview", "PaidMediaPhoto", "PaidMediaVideo"},
+	},
+	"PaidMediaInfo": {
+		Name: "PaidMediaInfo",
+		Fields: []FieldSpec{
+			{Name: "star_count", Types: []string{"Integer"}, Required: true},
+			{Name: "paid_media", Types: []string{"Array of PaidMedia"}, Required: true},
+		},
+	},
+	"PaidMediaPhoto": {
+		Name: "PaidMediaPhoto",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"photo"}}},
+			{Name: "photo", Types: []string{"Array of PhotoSize"}, Required: true},
+		},
+	},
+	"PaidMediaPreview": {
+		Name: "PaidMediaPreview",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"preview"}}},
+			{Name: "width", Types: []string{"Integer"}, Required: false},
+			{Name: "height", Types: []string{"Integer"}, Required: false},
+			{Name: "duration", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"PaidMediaPurchased": {
+		Name: "PaidMediaPurchased",
+		Fields: []FieldSpec{
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "paid_media_payload", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PaidMediaVideo": {
+		Name: "PaidMediaVideo",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"video"}}},
+			{Name: "video", Types: []string{"Video"}, Required: true},
+		},
+	},
+	"PaidMessagePriceChanged": {
+		Name: "PaidMessagePriceChanged",
+		Fields: []FieldSpec{
+			{Name: "paid_message_star_count", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"PassportData": {
+		Name: "PassportData",
+		Fields: []FieldSpec{
+			{Name: "data", Types: []string{"Array of EncryptedPassportElement"}, Required: true},
+			{Name: "credentials", Types: []string{"EncryptedCredentials"}, Required: true},
+		},
+	},
+	"PassportElementError": {
+		Name: "PassportElementError",
+		Subtypes: []string{"PassportElementErrorDataField", "PassportElementErrorFrontSide", "PassportElementErrorReverseSide", "PassportElementErrorSelfie", "PassportElementErrorFile", "PassportElementErrorFiles", "PassportElementErrorTranslationFile", "PassportElementErrorTranslationFiles", "PassportElementErrorUnspecified"},
+	},
+	"PassportElementErrorDataField": {
+		Name: "PassportElementErrorDataField",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"data"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "field_name", Types: []string{"String"}, Required: true},
+			{Name: "data_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorFile": {
+		Name: "PassportElementErrorFile",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"file"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorFiles": {
+		Name: "PassportElementErrorFiles",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"files"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hashes", Types: []string{"Array of String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorFrontSide": {
+		Name: "PassportElementErrorFrontSide",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"front_side"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorReverseSide": {
+		Name: "PassportElementErrorReverseSide",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"reverse_side"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorSelfie": {
+		Name: "PassportElementErrorSelfie",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"selfie"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorTranslationFile": {
+		Name: "PassportElementErrorTranslationFile",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"translation_file"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorTranslationFiles": {
+		Name: "PassportElementErrorTranslationFiles",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"translation_files"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "file_hashes", Types: []string{"Array of String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportElementErrorUnspecified": {
+		Name: "PassportElementErrorUnspecified",
+		Fields: []FieldSpec{
+			{Name: "source", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"unspecified"}}},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "element_hash", Types: []string{"String"}, Required: true},
+			{Name: "message", Types: []string{"String"}, Required: true},
+		},
+	},
+	"PassportFile": {
+		Name: "PassportFile",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "file_size", Types: []string{"Integer"}, Required: true},
+			{Name: "file_date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"PhotoSize": {
+		Name: "PhotoSize",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "width", Types: []string{"Integer"}, Required: true},
+			{Name: "height", Types: []string{"Integer"}, Required: true},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"Poll": {
+		Name: "Poll",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "question", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 300, Unit: "characters"}},
+			{Name: "question_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "options", Types: []string{"Array of PollOption"}, Required: true},
+			{Name: "total_voter_count", Types: []string{"Integer"}, Required: true},
+			{Name: "is_closed", Types: []string{"Boolean"}, Required: true},
+			{Name: "is_anonymous", Types: []string{"Boolean"}, Required: true},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "allows_multiple_answers", Types: []string{"Boolean"}, Required: true},
+			{Name: "correct_option_id", Types: []string{"Integer"}, Required: false},
+			{Name: "explanation", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 200, Unit: "characters"}},
+			{Name: "explanation_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "open_period", Types: []string{"Integer"}, Required: false},
+			{Name: "close_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"PollAnswer": {
+		Name: "PollAnswer",
+		Fields: []FieldSpec{
+			{Name: "poll_id", Types: []string{"String"}, Required: true},
+			{Name: "voter_chat", Types: []string{"Chat"}, Required: false},
+			{Name: "user", Types: []string{"User"}, Required: false},
+			{Name: "option_ids", Types: []string{"Array of Integer"}, Required: true},
+		},
+	},
+	"PollOption": {
+		Name: "PollOption",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true, Constraint: &Constraint{Min: 1, Max: 100, Unit: "characters"}},
+			{Name: "text_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "voter_count", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"PreCheckoutQuery": {
+		Name: "PreCheckoutQuery",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "currency", Types: []string{"String"}, Required: true},
+			{Name: "total_amount", Types: []string{"Integer"}, Required: true},
+			{Name: "invoice_payload", Types: []string{"String"}, Required: true},
+			{Name: "shipping_option_id", Types: []string{"String"}, Required: false},
+			{Name: "order_info", Types: []string{"OrderInfo"}, Required: false},
+		},
+	},
+	"PreparedInlineMessage": {
+		Name: "PreparedInlineMessage",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "expiration_date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"ProximityAlertTriggered": {
+		Name: "ProximityAlertTriggered",
+		Fields: []FieldSpec{
+			{Name: "traveler", Types: []string{"User"}, Required: true},
+			{Name: "watcher", Types: []string{"User"}, Required: true},
+			{Name: "distance", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"ReactionCount": {
+		Name: "ReactionCount",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"ReactionType"}, Required: true},
+			{Name: "total_count", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"ReactionType": {
+		Name: "ReactionType",
+		Subtypes: []string{"ReactionTypeEmoji", "ReactionTypeCustomEmoji", "ReactionTypePaid"},
+	},
+	"ReactionTypeCustomEmoji": {
+		Name: "ReactionTypeCustomEmoji",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"custom_emoji"}}},
+			{Name: "custom_emoji_id", Types: []string{"String"}, Required: true},
+		},
+	},
+	"ReactionTypeEmoji": {
+		Name: "ReactionTypeEmoji",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"emoji"}}},
+			{Name: "emoji", Types: []string{"String"}, Required: true},
+		},
+	},
+	"ReactionTypePaid": {
+		Name: "ReactionTypePaid",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"paid"}}},
+		},
+	},
+	"RefundedPayment": {
+		Name: "RefundedPayment",
+		Fields: []FieldSpec{
+			{Name: "currency", Types: []string{"String"}, Required: true},
+			{Name: "total_amount", Types: []string{"Integer"}, Required: true},
+			{Name: "invoice_payload", Types: []string{"String"}, Required: true},
+			{Name: "telegram_payment_charge_id", Types: []string{"String"}, Required: true},
+			{Name: "provider_payment_charge_id", Types: []string{"String"}, Required: false},
+		},
+	},
+	"ReplyKeyboardMarkup": {
+		Name: "ReplyKeyboardMarkup",
+		Fields: []FieldSpec{
+			{Name: "keyboard", Types: []string{"Array of Array of KeyboardButton"}, Required: true},
+			{Name: "is_persistent", Types: []string{"Boolean"}, Required: false},
+			{Name: "resize_keyboard", Types: []string{"Boolean"}, Required: false},
+			{Name: "one_time_keyboard", Types: []string{"Boolean"}, Required: false},
+			{Name: "input_field_placeholder", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 1, Max: 64, Unit: "characters"}},
+			{Name: "selective", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ReplyKeyboardRemove": {
+		Name: "ReplyKeyboardRemove",
+		Fields: []FieldSpec{
+			{Name: "remove_keyboard", Types: []string{"Boolean"}, Required: true},
+			{Name: "selective", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"ReplyParameters": {
+		Name: "ReplyParameters",
+		Fields: []FieldSpec{
+			{Name: "message_id", Types: []string{"Integer"}, Required: true},
+			{Name: "chat_id", Types: []string{"Integer", "String"}, Required: false},
+			{Name: "allow_sending_without_reply", Types: []string{"Boolean"}, Required: false},
+			{Name: "quote", Types: []string{"String"}, Required: false, Constraint: &Constraint{Min: 0, Max: 1024, Unit: "characters", Parsed: true}},
+			{Name: "quote_parse_mode", Types: []string{"String"}, Required: false},
+			{Name: "quote_entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "quote_position", Types: []string{"Integer"}, Required: false},
+			{Name: "checklist_task_id", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"ResponseParameters": {
+		Name: "ResponseParameters",
+		Fields: []FieldSpec{
+			{Name: "migrate_to_chat_id", Types: []string{"Integer"}, Required: false},
+			{Name: "retry_after", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"RevenueWithdrawalState": {
+		Name: "RevenueWithdrawalState",
+		Subtypes: []string{"RevenueWithdrawalStatePending", "RevenueWithdrawalStateSucceeded", "RevenueWithdrawalStateFailed"},
+	},
+	"RevenueWithdrawalStateFailed": {
+		Name: "RevenueWithdrawalStateFailed",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"failed"}}},
+		},
+	},
+	"RevenueWithdrawalStatePending": {
+		Name: "RevenueWithdrawalStatePending",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"pending"}}},
+		},
+	},
+	"RevenueWithdrawalStateSucceeded": {
+		Name: "RevenueWithdrawalStateSucceeded",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"succeeded"}}},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "url", Types: []string{"String"}, Required: true},
+		},
+	},
+	"SentWebAppMessage": {
+		Name: "SentWebAppMessage",
+		Fields: []FieldSpec{
+			{Name: "inline_message_id", Types: []string{"String"}, Required: false},
+		},
+	},
+	"SharedUser": {
+		Name: "SharedUser",
+		Fields: []FieldSpec{
+			{Name: "user_id", Types: []string{"Integer"}, Required: true},
+			{Name: "first_name", Types: []string{"String"}, Required: false},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "username", Types: []string{"String"}, Required: false},
+			{Name: "photo", Types: []string{"Array of PhotoSize"}, Required: false},
+		},
+	},
+	"ShippingAddress": {
+		Name: "ShippingAddress",
+		Fields: []FieldSpec{
+			{Name: "country_code", Types: []string{"String"}, Required: true},
+			{Name: "state", Types: []string{"String"}, Required: true},
+			{Name: "city", Types: []string{"String"}, Required: true},
+			{Name: "street_line1", Types: []string{"String"}, Required: true},
+			{Name: "street_line2", Types: []string{"String"}, Required: true},
+			{Name: "post_code", Types: []string{"String"}, Required: true},
+		},
+	},
+	"ShippingOption": {
+		Name: "ShippingOption",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "prices", Types: []string{"Array of LabeledPrice"}, Required: true},
+		},
+	},
+	"ShippingQuery": {
+		Name: "ShippingQuery",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "from", Types: []string{"User"}, Required: true},
+			{Name: "invoice_payload", Types: []string{"String"}, Required: true},
+			{Name: "shipping_address", Types: []string{"ShippingAddress"}, Required: true},
+		},
+	},
+	"StarAmount": {
+		Name: "StarAmount",
+		Fields: []FieldSpec{
+			{Name: "amount", Types: []string{"Integer"}, Required: true},
+			{Name: "nanostar_amount", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"StarTransaction": {
+		Name: "StarTransaction",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"String"}, Required: true},
+			{Name: "amount", Types: []string{"Integer"}, Required: true},
+			{Name: "nanostar_amount", Types: []string{"Integer"}, Required: false},
+			{Name: "date", Types: []string{"Integer"}, Required: true},
+			{Name: "source", Types: []string{"TransactionPartner"}, Required: false},
+			{Name: "receiver", Types: []string{"TransactionPartner"}, Required: false},
+		},
+	},
+	"StarTransactions": {
+		Name: "StarTransactions",
+		Fields: []FieldSpec{
+			{Name: "transactions", Types: []string{"Array of StarTransaction"}, Required: true},
+		},
+	},
+	"Sticker": {
+		Name: "Sticker",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "type", Types: []string{"String"}, Required: true},
+			{Name: "width", Types: []string{"Integer"}, Required: true},
+			{Name: "height", Types: []string{"Integer"}, Required: true},
+			{Name: "is_animated", Types: []string{"Boolean"}, Required: true},
+			{Name: "is_video", Types: []string{"Boolean"}, Required: true},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+			{Name: "emoji", Types: []string{"String"}, Required: false},
+			{Name: "set_name", Types: []string{"String"}, Required: false},
+			{Name: "premium_animation", Types: []string{"File"}, Required: false},
+			{Name: "mask_position", Types: []string{"MaskPosition"}, Required: false},
+			{Name: "custom_emoji_id", Types: []string{"String"}, Required: false},
+			{Name: "needs_repainting", Types: []string{"Boolean"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"StickerSet": {
+		Name: "StickerSet",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "sticker_type", Types: []string{"String"}, Required: true},
+			{Name: "stickers", Types: []string{"Array of Sticker"}, Required: true},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+		},
+	},
+	"Story": {
+		Name: "Story",
+		Fields: []FieldSpec{
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "id", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"StoryArea": {
+		Name: "StoryArea",
+		Fields: []FieldSpec{
+			{Name: "position", Types: []string{"StoryAreaPosition"}, Required: true},
+			{Name: "type", Types: []string{"StoryAreaType"}, Required: true},
+		},
+	},
+	"StoryAreaPosition": {
+		Name: "StoryAreaPosition",
+		Fields: []FieldSpec{
+			{Name: "x_percentage", Types: []string{"Float"}, Required: true},
+			{Name: "y_percentage", Types: []string{"Float"}, Required: true},
+			{Name: "width_percentage", Types: []string{"Float"}, Required: true},
+			{Name: "height_percentage", Types: []string{"Float"}, Required: true},
+			{Name: "rotation_angle", Types: []string{"Float"}, Required: true, Constraint: &Constraint{Min: 0, Max: 360}},
+			{Name: "corner_radius_percentage", Types: []string{"Float"}, Required: true},
+		},
+	},
+	"StoryAreaType": {
+		Name: "StoryAreaType",
+		Subtypes: []string{"StoryAreaTypeLocation", "StoryAreaTypeSuggestedReaction", "StoryAreaTypeLink", "StoryAreaTypeWeather", "StoryAreaTypeUniqueGift"},
+	},
+	"StoryAreaTypeLink": {
+		Name: "StoryAreaTypeLink",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"link"}}},
+			{Name: "url", Types: []string{"String"}, Required: true},
+		},
+	},
+	"StoryAreaTypeLocation": {
+		Name: "StoryAreaTypeLocation",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"location"}}},
+			{Name: "latitude", Types: []string{"Float"}, Required: true},
+			{Name: "longitude", Types: []string{"Float"}, Required: true},
+			{Name: "address", Types: []string{"LocationAddress"}, Required: false},
+		},
+	},
+	"StoryAreaTypeSuggestedReaction": {
+		Name: "StoryAreaTypeSuggestedReaction",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"suggested_reaction"}}},
+			{Name: "reaction_type", Types: []string{"ReactionType"}, Required: true},
+			{Name: "is_dark", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_flipped", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"StoryAreaTypeUniqueGift": {
+		Name: "StoryAreaTypeUniqueGift",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"unique_gift"}}},
+			{Name: "name", Types: []string{"String"}, Required: true},
+		},
+	},
+	"StoryAreaTypeWeather": {
+		Name: "StoryAreaTypeWeather",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"weather"}}},
+			{Name: "temperature", Types: []string{"Float"}, Required: true},
+			{Name: "emoji", Types: []string{"String"}, Required: true},
+			{Name: "background_color", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"SuccessfulPayment": {
+		Name: "SuccessfulPayment",
+		Fields: []FieldSpec{
+			{Name: "currency", Types: []string{"String"}, Required: true},
+			{Name: "total_amount", Types: []string{"Integer"}, Required: true},
+			{Name: "invoice_payload", Types: []string{"String"}, Required: true},
+			{Name: "subscription_expiration_date", Types: []string{"Integer"}, Required: false},
+			{Name: "is_recurring", Types: []string{"Boolean"}, Required: false},
+			{Name: "is_first_recurring", Types: []string{"Boolean"}, Required: false},
+			{Name: "shipping_option_id", Types: []string{"String"}, Required: false},
+			{Name: "order_info", Types: []string{"OrderInfo"}, Required: false},
+			{Name: "telegram_payment_charge_id", Types: []string{"String"}, Required: true},
+			{Name: "provider_payment_charge_id", Types: []string{"String"}, Required: true},
+		},
+	},
+	"SuggestedPostApprovalFailed": {
+		Name: "SuggestedPostApprovalFailed",
+		Fields: []FieldSpec{
+			{Name: "suggested_post_message", Types: []string{"Message"}, Required: false},
+			{Name: "price", Types: []string{"SuggestedPostPrice"}, Required: true},
+		},
+	},
+	"SuggestedPostApproved": {
+		Name: "SuggestedPostApproved",
+		Fields: []FieldSpec{
+			{Name: "suggested_post_message", Types: []string{"Message"}, Required: false},
+			{Name: "price", Types: []string{"SuggestedPostPrice"}, Required: false},
+			{Name: "send_date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"SuggestedPostDeclined": {
+		Name: "SuggestedPostDeclined",
+		Fields: []FieldSpec{
+			{Name: "suggested_post_message", Types: []string{"Message"}, Required: false},
+			{Name: "comment", Types: []string{"String"}, Required: false},
+		},
+	},
+	"SuggestedPostInfo": {
+		Name: "SuggestedPostInfo",
+		Fields: []FieldSpec{
+			{Name: "state", Types: []string{"String"}, Required: true},
+			{Name: "price", Types: []string{"SuggestedPostPrice"}, Required: false},
+			{Name: "send_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"SuggestedPostPaid": {
+		Name: "SuggestedPostPaid",
+		Fields: []FieldSpec{
+			{Name: "suggested_post_message", Types: []string{"Message"}, Required: false},
+			{Name: "currency", Types: []string{"String"}, Required: true},
+			{Name: "amount", Types: []string{"Integer"}, Required: false},
+			{Name: "star_amount", Types: []string{"StarAmount"}, Required: false},
+		},
+	},
+	"SuggestedPostParameters": {
+		Name: "SuggestedPostParameters",
+		Fields: []FieldSpec{
+			{Name: "price", Types: []string{"SuggestedPostPrice"}, Required: false},
+			{Name: "send_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"SuggestedPostPrice": {
+		Name: "SuggestedPostPrice",
+		Fields: []FieldSpec{
+			{Name: "currency", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"XTR", "TON"}}},
+			{Name: "amount", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"SuggestedPostRefunded": {
+		Name: "SuggestedPostRefunded",
+		Fields: []FieldSpec{
+			{Name: "suggested_post_message", Types: []string{"Message"}, Required: false},
+			{Name: "reason", Types: []string{"String"}, Required: true},
+		},
+	},
+	"SwitchInlineQueryChosenChat": {
+		Name: "SwitchInlineQueryChosenChat",
+		Fields: []FieldSpec{
+			{Name: "query", Types: []string{"String"}, Required: false},
+			{Name: "allow_user_chats", Types: []string{"Boolean"}, Required: false},
+			{Name: "allow_bot_chats", Types: []string{"Boolean"}, Required: false},
+			{Name: "allow_group_chats", Types: []string{"Boolean"}, Required: false},
+			{Name: "allow_channel_chats", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"TextQuote": {
+		Name: "TextQuote",
+		Fields: []FieldSpec{
+			{Name: "text", Types: []string{"String"}, Required: true},
+			{Name: "entities", Types: []string{"Array of MessageEntity"}, Required: false},
+			{Name: "position", Types: []string{"Integer"}, Required: true},
+			{Name: "is_manual", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"TransactionPartner": {
+		Name: "TransactionPartner",
+		Subtypes: []string{"TransactionPartnerUser", "TransactionPartnerChat", "TransactionPartnerAffiliateProgram", "TransactionPartnerFragment", "TransactionPartnerTelegramAds", "TransactionPartnerTelegramApi", "TransactionPartnerOther"},
+	},
+	"TransactionPartnerAffiliateProgram": {
+		Name: "TransactionPartnerAffiliateProgram",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"affiliate_program"}}},
+			{Name: "sponsor_user", Types: []string{"User"}, Required: false},
+			{Name: "commission_per_mille", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"TransactionPartnerChat": {
+		Name: "TransactionPartnerChat",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"chat"}}},
+			{Name: "chat", Types: []string{"Chat"}, Required: true},
+			{Name: "gift", Types: []string{"Gift"}, Required: false},
+		},
+	},
+	"TransactionPartnerFragment": {
+		Name: "TransactionPartnerFragment",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"fragment"}}},
+			{Name: "withdrawal_state", Types: []string{"RevenueWithdrawalState"}, Required: false},
+		},
+	},
+	"TransactionPartnerOther": {
+		Name: "TransactionPartnerOther",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"other"}}},
+		},
+	},
+	"TransactionPartnerTelegramAds": {
+		Name: "TransactionPartnerTelegramAds",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"telegram_ads"}}},
+		},
+	},
+	"TransactionPartnerTelegramApi": {
+		Name: "TransactionPartnerTelegramApi",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"telegram_api"}}},
+			{Name: "request_count", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"TransactionPartnerUser": {
+		Name: "TransactionPartnerUser",
+		Fields: []FieldSpec{
+			{Name: "type", Types: []string{"String"}, Required: true, Constraint: &Constraint{Enum: []string{"user"}}},
+			{Name: "transaction_type", Types: []string{"String"}, Required: true},
+			{Name: "user", Types: []string{"User"}, Required: true},
+			{Name: "affiliate", Types: []string{"AffiliateInfo"}, Required: false},
+			{Name: "invoice_payload", Types: []string{"String"}, Required: false},
+			{Name: "subscription_period", Types: []string{"Integer"}, Required: false},
+			{Name: "paid_media", Types: []string{"Array of PaidMedia"}, Required: false},
+			{Name: "paid_media_payload", Types: []string{"String"}, Required: false},
+			{Name: "gift", Types: []string{"Gift"}, Required: false},
+			{Name: "premium_subscription_duration", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"UniqueGift": {
+		Name: "UniqueGift",
+		Fields: []FieldSpec{
+			{Name: "base_name", Types: []string{"String"}, Required: true},
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "number", Types: []string{"Integer"}, Required: true},
+			{Name: "model", Types: []string{"UniqueGiftModel"}, Required: true},
+			{Name: "symbol", Types: []string{"UniqueGiftSymbol"}, Required: true},
+			{Name: "backdrop", Types: []string{"UniqueGiftBackdrop"}, Required: true},
+			{Name: "publisher_chat", Types: []string{"Chat"}, Required: false},
+		},
+	},
+	"UniqueGiftBackdrop": {
+		Name: "UniqueGiftBackdrop",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "colors", Types: []string{"UniqueGiftBackdropColors"}, Required: true},
+			{Name: "rarity_per_mille", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"UniqueGiftBackdropColors": {
+		Name: "UniqueGiftBackdropColors",
+		Fields: []FieldSpec{
+			{Name: "center_color", Types: []string{"Integer"}, Required: true},
+			{Name: "edge_color", Types: []string{"Integer"}, Required: true},
+			{Name: "symbol_color", Types: []string{"Integer"}, Required: true},
+			{Name: "text_color", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"UniqueGiftInfo": {
+		Name: "UniqueGiftInfo",
+		Fields: []FieldSpec{
+			{Name: "gift", Types: []string{"UniqueGift"}, Required: true},
+			{Name: "origin", Types: []string{"String"}, Required: true},
+			{Name: "last_resale_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "owned_gift_id", Types: []string{"String"}, Required: false},
+			{Name: "transfer_star_count", Types: []string{"Integer"}, Required: false},
+			{Name: "next_transfer_date", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"UniqueGiftModel": {
+		Name: "UniqueGiftModel",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "sticker", Types: []string{"Sticker"}, Required: true},
+			{Name: "rarity_per_mille", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"UniqueGiftSymbol": {
+		Name: "UniqueGiftSymbol",
+		Fields: []FieldSpec{
+			{Name: "name", Types: []string{"String"}, Required: true},
+			{Name: "sticker", Types: []string{"Sticker"}, Required: true},
+			{Name: "rarity_per_mille", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"Update": {
+		Name: "Update",
+		Fields: []FieldSpec{
+			{Name: "update_id", Types: []string{"Integer"}, Required: true},
+			{Name: "message", Types: []string{"Message"}, Required: false},
+			{Name: "edited_message", Types: []string{"Message"}, Required: false},
+			{Name: "channel_post", Types: []string{"Message"}, Required: false},
+			{Name: "edited_channel_post", Types: []string{"Message"}, Required: false},
+			{Name: "business_connection", Types: []string{"BusinessConnection"}, Required: false},
+			{Name: "business_message", Types: []string{"Message"}, Required: false},
+			{Name: "edited_business_message", Types: []string{"Message"}, Required: false},
+			{Name: "deleted_business_messages", Types: []string{"BusinessMessagesDeleted"}, Required: false},
+			{Name: "message_reaction", Types: []string{"MessageReactionUpdated"}, Required: false},
+			{Name: "message_reaction_count", Types: []string{"MessageReactionCountUpdated"}, Required: false},
+			{Name: "inline_query", Types: []string{"InlineQuery"}, Required: false},
+			{Name: "chosen_inline_result", Types: []string{"ChosenInlineResult"}, Required: false},
+			{Name: "callback_query", Types: []string{"CallbackQuery"}, Required: false},
+			{Name: "shipping_query", Types: []string{"ShippingQuery"}, Required: false},
+			{Name: "pre_checkout_query", Types: []string{"PreCheckoutQuery"}, Required: false},
+			{Name: "purchased_paid_media", Types: []string{"PaidMediaPurchased"}, Required: false},
+			{Name: "poll", Types: []string{"Poll"}, Required: false},
+			{Name: "poll_answer", Types: []string{"PollAnswer"}, Required: false},
+			{Name: "my_chat_member", Types: []string{"ChatMemberUpdated"}, Required: false},
+			{Name: "chat_member", Types: []string{"ChatMemberUpdated"}, Required: false},
+			{Name: "chat_join_request", Types: []string{"ChatJoinRequest"}, Required: false},
+			{Name: "chat_boost", Types: []string{"ChatBoostUpdated"}, Required: false},
+			{Name: "removed_chat_boost", Types: []string{"ChatBoostRemoved"}, Required: false},
+		},
+	},
+	"User": {
+		Name: "User",
+		Fields: []FieldSpec{
+			{Name: "id", Types: []string{"Integer"}, Required: true},
+			{Name: "is_bot", Types: []string{"Boolean"}, Required: true},
+			{Name: "first_name", Types: []string{"String"}, Required: true},
+			{Name: "last_name", Types: []string{"String"}, Required: false},
+			{Name: "username", Types: []string{"String"}, Required: false},
+			{Name: "language_code", Types: []string{"String"}, Required: false},
+			{Name: "is_premium", Types: []string{"Boolean"}, Required: false},
+			{Name: "added_to_attachment_menu", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_join_groups", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_read_all_group_messages", Types: []string{"Boolean"}, Required: false},
+			{Name: "supports_inline_queries", Types: []string{"Boolean"}, Required: false},
+			{Name: "can_connect_to_business", Types: []string{"Boolean"}, Required: false},
+			{Name: "has_main_web_app", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+	"UserChatBoosts": {
+		Name: "UserChatBoosts",
+		Fields: []FieldSpec{
+			{Name: "boosts", Types: []string{"Array of ChatBoost"}, Required: true},
+		},
+	},
+	"UserProfilePhotos": {
+		Name: "UserProfilePhotos",
+		Fields: []FieldSpec{
+			{Name: "total_count", Types: []string{"Integer"}, Required: true},
+			{Name: "photos", Types: []string{"Array of Array of PhotoSize"}, Required: true},
+		},
+	},
+	"UsersShared": {
+		Name: "UsersShared",
+		Fields: []FieldSpec{
+			{Name: "request_id", Types: []string{"Integer"}, Required: true},
+			{Name: "users", Types: []string{"Array of SharedUser"}, Required: true},
+		},
+	},
+	"Venue": {
+		Name: "Venue",
+		Fields: []FieldSpec{
+			{Name: "location", Types: []string{"Location"}, Required: true},
+			{Name: "title", Types: []string{"String"}, Required: true},
+			{Name: "address", Types: []string{"String"}, Required: true},
+			{Name: "foursquare_id", Types: []string{"String"}, Required: false},
+			{Name: "foursquare_type", Types: []string{"String"}, Required: false},
+			{Name: "google_place_id", Types: []string{"String"}, Required: false},
+			{Name: "google_place_type", Types: []string{"String"}, Required: false},
+		},
+	},
+	"Video": {
+		Name: "Video",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "width", Types: []string{"Integer"}, Required: true},
+			{Name: "height", Types: []string{"Integer"}, Required: true},
+			{Name: "duration", Types: []string{"Integer"}, Required: true},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+			{Name: "cover", Types: []string{"Array of PhotoSize"}, Required: false},
+			{Name: "start_timestamp", Types: []string{"Integer"}, Required: false},
+			{Name: "file_name", Types: []string{"String"}, Required: false},
+			{Name: "mime_type", Types: []string{"String"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"VideoChatEnded": {
+		Name: "VideoChatEnded",
+		Fields: []FieldSpec{
+			{Name: "duration", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"VideoChatParticipantsInvited": {
+		Name: "VideoChatParticipantsInvited",
+		Fields: []FieldSpec{
+			{Name: "users", Types: []string{"Array of User"}, Required: true},
+		},
+	},
+	"VideoChatScheduled": {
+		Name: "VideoChatScheduled",
+		Fields: []FieldSpec{
+			{Name: "start_date", Types: []string{"Integer"}, Required: true},
+		},
+	},
+	"VideoChatStarted": {
+		Name: "VideoChatStarted",
+	},
+	"VideoNote": {
+		Name: "VideoNote",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "length", Types: []string{"Integer"}, Required: true},
+			{Name: "duration", Types: []string{"Integer"}, Required: true},
+			{Name: "thumbnail", Types: []string{"PhotoSize"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"Voice": {
+		Name: "Voice",
+		Fields: []FieldSpec{
+			{Name: "file_id", Types: []string{"String"}, Required: true},
+			{Name: "file_unique_id", Types: []string{"String"}, Required: true},
+			{Name: "duration", Types: []string{"Integer"}, Required: true},
+			{Name: "mime_type", Types: []string{"String"}, Required: false},
+			{Name: "file_size", Types: []string{"Integer"}, Required: false},
+		},
+	},
+	"WebAppData": {
+		Name: "WebAppData",
+		Fields: []FieldSpec{
+			{Name: "data", Types: []string{"String"}, Required: true},
+			{Name: "button_text", Types: []string{"String"}, Required: true},
+		},
+	},
+	"WebAppInfo": {
+		Name: "WebAppInfo",
+		Fields: []FieldSpec{
+			{Name: "url", Types: []string{"String"}, Required: true},
+		},
+	},
+	"WebhookInfo": {
+		Name: "WebhookInfo",
+		Fields: []FieldSpec{
+			{Name: "url", Types: []string{"String"}, Required: true},
+			{Name: "has_custom_certificate", Types: []string{"Boolean"}, Required: true},
+			{Name: "pending_update_count", Types: []string{"Integer"}, Required: true},
+			{Name: "ip_address", Types: []string{"String"}, Required: false},
+			{Name: "last_error_date", Types: []string{"Integer"}, Required: false},
+			{Name: "last_error_message", Types: []string{"String"}, Required: false},
+			{Name: "last_synchronization_error_date", Types: []string{"Integer"}, Required: false},
+			{Name: "max_connections", Types: []string{"Integer"}, Required: false},
+			{Name: "allowed_updates", Types: []string{"Array of String"}, Required: false},
+		},
+	},
+	"WriteAccessAllowed": {
+		Name: "WriteAccessAllowed",
+		Fields: []FieldSpec{
+			{Name: "from_request", Types: []string{"Boolean"}, Required: false},
+			{Name: "web_app_name", Types: []string{"String"}, Required: false},
+			{Name: "from_attachment_menu", Types: []string{"Boolean"}, Required: false},
+		},
+	},
+}
@@ -4,5 +4,6 @@ go 1.21
 
 require (
 	github.com/go-chi/chi/v5 v5.1.0
+	github.com/yuin/gopher-lua v1.1.1
 	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,506 @@
+// Package adversarial makes generated responses as hard to deserialize as
+// the Bot API allows: strings of the maximum length made of multi-byte and
+// invisible characters, integers at the edges of their range, optional
+// fields that rarely appear, and deeply nested entities and replies. Every
+// value still fits the spec, so a client that fails on one has a bug.
+package adversarial
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/faker"
+)
+
+// Kinds of adversarial values.
+const (
+	// Strings are made as long as the spec allows, or as short, out of
+	// characters that take several bytes or UTF-16 code units.
+	Strings = "strings"
+	// Integers are set to the bounds the spec gives, or to the edges of
+	// 32-bit integers and, for IDs and sizes, of 52-bit ones.
+	Integers = "integers"
+	// OptionalFields fills in optional fields that were left out.
+	OptionalFields = "optional_fields"
+	// Nesting gives texts nested entities and messages a chain of
+	// replies.
+	Nesting = "nesting"
+)
+
+// Kinds lists every kind of adversarial value.
+var Kinds = []string{Strings, Integers, OptionalFields, Nesting}
+
+// DefaultDepth is how deep entities and replies nest by default.
+const DefaultDepth = 10
+
+// Config selects which values of responses are made adversarial.
+type Config struct {
+	// Probability is the chance that each string, integer, and missing
+	// optional field is replaced, and that each text and message is
+	// nested (0 = 1, every one).
+	Probability float64 `json:"probability,omitempty"`
+	// Kinds restricts the values to these kinds (empty = all).
+	Kinds []string `json:"kinds,omitempty"`
+	// Depth is how deep entities and replies nest (0 = DefaultDepth).
+	Depth int `json:"depth,omitempty"`
+	// Methods restricts the changes to these methods (empty = all).
+	Methods []string `json:"methods,omitempty"`
+	// Seed makes the choices reproducible. With 0 a random seed is
+	// picked, and reported by Get, so a failing run can be repeated.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate checks the probability, kinds, depth, and methods.
+func (c *Config) Validate() error {
+	if c.Probability < 0 || c.Probability > 1 {
+		return fmt.Errorf("probability must be between 0 and 1")
+	}
+	for _, kind := range c.Kinds {
+		known := false
+		for _, k := range Kinds {
+			known = known || k == kind
+		}
+		if !known {
+			return fmt.Errorf("unknown kind %q; want one of %s", kind, strings.Join(Kinds, ", "))
+		}
+	}
+	if c.Depth < 0 {
+		return fmt.Errorf("depth must not be negative")
+	}
+	for _, method := range c.Methods {
+		if _, ok := gen.Methods[method]; !ok {
+			return fmt.Errorf("unknown method %q", method)
+		}
+	}
+	return nil
+}
+
+// Stats counts the changes made to responses.
+type Stats struct {
+	Responses int `json:"responses"`
+	Strings   int `json:"strings"`
+	Integers  int `json:"integers"`
+	Fields    int `json:"fields"`
+	Nested    int `json:"nested"`
+}
+
+// Mutator makes responses adversarial according to its configuration. It
+// is disabled until configured.
+type Mutator struct {
+	mu      sync.Mutex
+	base    *faker.Faker
+	cfg     *Config
+	rng     *rand.Rand
+	faker   *faker.Faker
+	kinds   map[string]bool
+	methods map[string]bool
+	stats   Stats
+}
+
+// NewMutator creates a disabled mutator. Optional fields are filled in
+// by a faker seeded like the mutator, made from f.
+func NewMutator(f *faker.Faker) *Mutator {
+	return &Mutator{base: f}
+}
+
+// Set enables the mutator with cfg and resets its statistics.
+func (m *Mutator) Set(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.Seed == 0 {
+		cfg.Seed = time.Now().UnixNano()
+	}
+	if cfg.Probability == 0 {
+		cfg.Probability = 1
+	}
+	if cfg.Depth == 0 {
+		cfg.Depth = DefaultDepth
+	}
+	kinds := cfg.Kinds
+	if len(kinds) == 0 {
+		kinds = Kinds
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = &cfg
+	m.rng = rand.New(rand.NewSource(cfg.Seed))
+	m.faker = m.base.WithSeed(cfg.Seed)
+	m.kinds = make(map[string]bool, len(kinds))
+	for _, kind := range kinds {
+		m.kinds[kind] = true
+	}
+	m.methods = make(map[string]bool, len(cfg.Methods))
+	for _, method := range cfg.Methods {
+		m.methods[method] = true
+	}
+	m.stats = Stats{}
+	return nil
+}
+
+// Get returns the configuration, if the mutator is enabled, and its
+// statistics.
+func (m *Mutator) Get() (*Config, Stats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg == nil {
+		return nil, m.stats
+	}
+	cfg := *m.cfg
+	return &cfg, m.stats
+}
+
+// Disable sends responses as they are again and resets the statistics.
+func (m *Mutator) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = nil
+	m.stats = Stats{}
+}
+
+// Apply returns the result of a method with adversarial values. The
+// result itself is not modified. returns are the method's possible
+// return types.
+func (m *Mutator) Apply(method string, returns []string, result interface{}) interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg == nil || (len(m.methods) > 0 && !m.methods[method]) {
+		return result
+	}
+	m.stats.Responses++
+	return m.walk(matchType(returns, result), result)
+}
+
+// walk changes a value of the named type, copying every object it passes
+// through.
+func (m *Mutator) walk(typeName string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case []interface{}:
+		elem, _ := strings.CutPrefix(typeName, "Array of ")
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = m.walk(elem, item)
+		}
+		return items
+	case []map[string]interface{}:
+		elem, _ := strings.CutPrefix(typeName, "Array of ")
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = m.walkObject(elem, item)
+		}
+		return items
+	case map[string]interface{}:
+		return m.walkObject(typeName, value)
+	}
+	return v
+}
+
+func (m *Mutator) walkObject(typeName string, obj map[string]interface{}) map[string]interface{} {
+	spec, known := resolve(typeName, obj)
+	if !known {
+		return obj
+	}
+	nest := m.kinds[Nesting] && m.chance()
+	replies := nest && spec.Name == "Message"
+
+	result := make(map[string]interface{}, len(obj))
+	// Texts and entities that were replaced or filled in
+	changed := map[string]bool{}
+	// Visit fields in spec order so that seeded runs make the same choices
+	for _, field := range spec.Fields {
+		value, present := obj[field.Name]
+		switch {
+		case !present:
+			if !field.Required && m.kinds[OptionalFields] && m.chance() {
+				result[field.Name] = m.faker.GenerateType(gen.ParseType(field.Types...), nil, nil)
+				changed[field.Name] = true
+				m.stats.Fields++
+			}
+		case replies && field.Name == "reply_to_message":
+			// Replaced by the chain of replies below
+		default:
+			result[field.Name], changed[field.Name] = m.value(spec.Name, field, value)
+		}
+	}
+	// Fields the spec doesn't know pass through
+	for key, value := range obj {
+		if _, ok := findField(spec, key); !ok {
+			result[key] = value
+		}
+	}
+
+	nested := m.fixEntities(spec, result, changed, nest)
+	if replies {
+		result["reply_to_message"] = replyChain(result, m.cfg.Depth)
+	}
+	if nested || replies {
+		m.stats.Nested++
+	}
+	return result
+}
+
+// value returns the value of a field, adversarial or walked into, and
+// whether it is a string that was replaced.
+func (m *Mutator) value(typeName string, field gen.FieldSpec, v interface{}) (interface{}, bool) {
+	if s, ok := v.(string); ok && m.kinds[Strings] && mutableString(field) && m.chance() {
+		m.stats.Strings++
+		next := m.boundaryString(field)
+		return next, next != s
+	}
+	if n, ok := integer(v); ok && m.kinds[Integers] && hasType(field, "Integer") && m.chance() {
+		m.stats.Integers++
+		return m.boundaryInteger(typeName, field, n), false
+	}
+	return m.walk(matchType(field.Types, v), v), false
+}
+
+func (m *Mutator) chance() bool {
+	return m.cfg.Probability >= 1 || m.rng.Float64() < m.cfg.Probability
+}
+
+// fixEntities rebuilds entities that were filled in or whose texts
+// changed, so that their offsets stay within the text, and nests the
+// entities of every text if nest is set, reporting whether it did.
+func (m *Mutator) fixEntities(spec gen.TypeSpec, obj map[string]interface{}, changed map[string]bool, nest bool) bool {
+	nested := false
+	for _, field := range spec.Fields {
+		if !hasType(field, "Array of MessageEntity") {
+			continue
+		}
+		source := strings.TrimSuffix(strings.TrimSuffix(field.Name, "entities"), "_")
+		if source == "" {
+			source = "text"
+		}
+		_, present := obj[field.Name]
+		text, ok := obj[source].(string)
+		switch {
+		case !ok || text == "":
+			if changed[field.Name] {
+				delete(obj, field.Name)
+			}
+		case nest:
+			obj[field.Name] = nestedEntities(text, m.cfg.Depth)
+			nested = true
+		case present && (changed[source] || changed[field.Name]):
+			obj[field.Name] = nestedEntities(text, 1)
+		}
+	}
+	return nested
+}
+
+// entityTypes are the entity types that can nest in one another.
+var entityTypes = []string{"bold", "italic", "underline", "strikethrough", "spoiler"}
+
+// nestedEntities returns up to depth entities over text, each inside the
+// one before it. Offsets and lengths count UTF-16 code units, as
+// Telegram's do.
+func nestedEntities(text string, depth int) []interface{} {
+	n := len(utf16.Encode([]rune(text)))
+	entities := []interface{}{}
+	for i := 0; i < depth && n-2*i > 0; i++ {
+		entities = append(entities, map[string]interface{}{
+			"type":   entityTypes[i%len(entityTypes)],
+			"offset": i,
+			"length": n - 2*i,
+		})
+	}
+	return entities
+}
+
+// replyChain returns depth copies of msg, each a reply to the next.
+func replyChain(msg map[string]interface{}, depth int) map[string]interface{} {
+	var chain map[string]interface{}
+	for i := 0; i < depth; i++ {
+		reply := make(map[string]interface{}, len(msg))
+		for k, v := range msg {
+			reply[k] = v
+		}
+		delete(reply, "reply_to_message")
+		if chain != nil {
+			reply["reply_to_message"] = chain
+		}
+		chain = reply
+	}
+	return chain
+}
+
+// fixedStrings are fields whose values clients commonly map to enums or
+// parse, though the spec doesn't list their values.
+var fixedStrings = map[string]bool{
+	"type":     true,
+	"status":   true,
+	"source":   true,
+	"currency": true,
+	"emoji":    true,
+}
+
+func mutableString(field gen.FieldSpec) bool {
+	if fixedStrings[field.Name] || !hasType(field, "String") {
+		return false
+	}
+	return field.Constraint == nil || len(field.Constraint.Enum) == 0
+}
+
+// defaultLengths are the lengths of strings the spec gives no bounds
+// for: Telegram's limits for message texts, and a long string otherwise.
+var defaultLengths = map[string]int{
+	"text":    4096,
+	"caption": 1024,
+}
+
+const defaultLength = 256
+
+// boundaryString returns a string for the field as long as its bounds
+// allow, or as short if they have a minimum.
+func (m *Mutator) boundaryString(field gen.FieldSpec) string {
+	length, unit := defaultLength, "characters"
+	if l, ok := defaultLengths[field.Name]; ok {
+		length = l
+	}
+	if c := field.Constraint; c != nil && c.Max > 0 && c.Unit != "items" {
+		length = int(c.Max)
+		if c.Min > 0 && m.rng.Intn(2) == 0 {
+			length = int(c.Min)
+		}
+		if c.Unit != "" {
+			unit = c.Unit
+		}
+	}
+	return m.hardString(length, unit)
+}
+
+// pieces make up adversarial strings: multi-byte letters, a character
+// outside the Basic Multilingual Plane, joiners, direction overrides,
+// combining marks, and characters that JSON and markup escape.
+var pieces = []string{"a", "й", "中", "😀", "‍", "‮", "́", `"`, `\`, "<", "&", "\n"}
+
+// hardString returns a string of exactly length UTF-16 code units
+// ("characters") or at most length bytes ("bytes").
+func (m *Mutator) hardString(length int, unit string) string {
+	var b strings.Builder
+	for left := length; left > 0; {
+		piece := pieces[m.rng.Intn(len(pieces))]
+		size := len(utf16.Encode([]rune(piece)))
+		if unit == "bytes" {
+			size = len(piece)
+		}
+		if size > left {
+			piece, size = "a", 1
+		}
+		b.WriteString(piece)
+		left -= size
+	}
+	return b.String()
+}
+
+// boundaryInteger returns the bound of the field's value, or the edge of
+// the range its values take.
+func (m *Mutator) boundaryInteger(typeName string, field gen.FieldSpec, n int64) int64 {
+	if c := field.Constraint; c != nil && c.Unit == "" && c.Max > 0 {
+		if m.rng.Intn(2) == 0 {
+			return c.Min
+		}
+		return c.Max
+	}
+	name := field.Name
+	if name == "id" || strings.HasSuffix(name, "_id") || strings.HasSuffix(name, "_size") {
+		// Identifiers and sizes have up to 52 significant bits
+		edges := []int64{1<<31 - 1, 1 << 31, 1<<52 - 1}
+		v := edges[m.rng.Intn(len(edges))]
+		// Chats of groups and channels have negative IDs
+		if n < 0 || (typeName == "Chat" || typeName == "ChatFullInfo") && name == "id" && m.rng.Intn(2) == 0 {
+			v = -v
+		}
+		return v
+	}
+	edges := []int64{0, 1<<31 - 1}
+	return edges[m.rng.Intn(len(edges))]
+}
+
+func integer(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), n == float64(int64(n))
+	}
+	return 0, false
+}
+
+func hasType(field gen.FieldSpec, t string) bool {
+	for _, ft := range field.Types {
+		if ft == t {
+			return true
+		}
+	}
+	return false
+}
+
+// matchType picks the type among types that fits the shape of v.
+func matchType(types []string, v interface{}) string {
+	for _, t := range types {
+		switch v.(type) {
+		case []interface{}, []map[string]interface{}:
+			if strings.HasPrefix(t, "Array of ") {
+				return t
+			}
+		case map[string]interface{}:
+			if _, ok := gen.Types[t]; ok {
+				return t
+			}
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+// resolve returns the spec of an object of the named type. Union types
+// resolve to the subtype whose fields fit the object best.
+func resolve(typeName string, obj map[string]interface{}) (gen.TypeSpec, bool) {
+	spec, ok := gen.Types[typeName]
+	if !ok || len(spec.Subtypes) == 0 {
+		return spec, ok
+	}
+
+	best, bestScore := gen.TypeSpec{}, -1
+	for _, name := range spec.Subtypes {
+		sub, ok := gen.Types[name]
+		if !ok {
+			continue
+		}
+		score := 0
+		for _, f := range sub.Fields {
+			if _, present := obj[f.Name]; present {
+				score++
+			} else if f.Required {
+				score = -1
+				break
+			}
+		}
+		if score > bestScore {
+			best, bestScore = sub, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+func findField(spec gen.TypeSpec, name string) (gen.FieldSpec, bool) {
+	for _, f := range spec.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return gen.FieldSpec{}, false
+}
@@ -0,0 +1,170 @@
+// internal/adversarial/adversarial_test.go
+package adversarial
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/faker"
+)
+
+func message() map[string]interface{} {
+	return map[string]interface{}{
+		"message_id": int64(1),
+		"date":       int64(1700000000),
+		"text":       "hello",
+		"entities":   []interface{}{map[string]interface{}{"type": "bold", "offset": 0, "length": 5}},
+		"chat":       map[string]interface{}{"id": int64(-1001), "type": "supergroup", "title": "Club"},
+		"from":       map[string]interface{}{"id": int64(7), "is_bot": false, "first_name": "Ann"},
+	}
+}
+
+func newMutator(cfg Config) *Mutator {
+	m := NewMutator(faker.New(faker.Config{Seed: 1}))
+	if err := m.Set(cfg); err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{"empty", Config{}, true},
+		{"everything", Config{Probability: 0.5, Kinds: []string{Strings, Nesting}, Depth: 3, Methods: []string{"getMe"}}, true},
+		{"probability too high", Config{Probability: 1.5}, false},
+		{"unknown kind", Config{Kinds: []string{"floats"}}, false},
+		{"negative depth", Config{Depth: -1}, false},
+		{"unknown method", Config{Methods: []string{"nope"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMutator_DisabledByDefault(t *testing.T) {
+	m := NewMutator(faker.New(faker.Config{}))
+	msg := message()
+	if got := m.Apply("sendMessage", []string{"Message"}, msg); !reflect.DeepEqual(got, msg) {
+		t.Errorf("expected result to be unchanged, got %v", got)
+	}
+}
+
+func TestMutator_Strings(t *testing.T) {
+	m := newMutator(Config{Kinds: []string{Strings}, Seed: 1})
+	msg := message()
+	got := m.Apply("sendMessage", []string{"Message"}, msg).(map[string]interface{})
+
+	text := got["text"].(string)
+	if utf16Len(text) != 4096 {
+		t.Errorf("expected a text of 4096 UTF-16 code units, got %d", utf16Len(text))
+	}
+	chat := got["chat"].(map[string]interface{})
+	if chat["type"] != "supergroup" {
+		t.Errorf("expected the chat type kept, got %v", chat["type"])
+	}
+	entities := got["entities"].([]interface{})
+	if entity := entities[0].(map[string]interface{}); len(entities) != 1 || entity["length"] != utf16Len(text) {
+		t.Errorf("expected the entity to cover the new text, got %v", entities)
+	}
+	if msg["text"] != "hello" {
+		t.Error("expected the result not to be modified")
+	}
+
+	// Bounded strings are as long as their bounds allow
+	field := gen.FieldSpec{Name: "id", Types: []string{"String"}, Constraint: &gen.Constraint{Min: 1, Max: 64, Unit: "bytes"}}
+	for i := 0; i < 20; i++ {
+		if s := m.boundaryString(field); len(s) > 64 || len(s) < 1 {
+			t.Fatalf("expected 1 to 64 bytes, got %d", len(s))
+		}
+	}
+}
+
+func TestMutator_Integers(t *testing.T) {
+	m := newMutator(Config{Kinds: []string{Integers}, Seed: 2})
+	got := m.Apply("sendMessage", []string{"Message"}, message()).(map[string]interface{})
+
+	edges := map[int64]bool{0: true, 1<<31 - 1: true, 1 << 31: true, 1<<52 - 1: true}
+	for _, value := range []interface{}{got["message_id"], got["date"], got["from"].(map[string]interface{})["id"]} {
+		if !edges[value.(int64)] {
+			t.Errorf("expected an edge value, got %v", value)
+		}
+	}
+	if id := got["chat"].(map[string]interface{})["id"].(int64); id >= 0 {
+		t.Errorf("expected the supergroup ID to stay negative, got %d", id)
+	}
+}
+
+func TestMutator_OptionalFields(t *testing.T) {
+	m := newMutator(Config{Kinds: []string{OptionalFields}, Seed: 3})
+	got := m.Apply("getMe", []string{"User"}, map[string]interface{}{"id": int64(1), "is_bot": true, "first_name": "Bot"}).(map[string]interface{})
+	for _, field := range gen.Types["User"].Fields {
+		if _, ok := got[field.Name]; !ok {
+			t.Errorf("expected %s to be filled in", field.Name)
+		}
+	}
+	if got["id"] != int64(1) {
+		t.Errorf("expected present fields kept, got %v", got["id"])
+	}
+}
+
+func TestMutator_Nesting(t *testing.T) {
+	m := newMutator(Config{Kinds: []string{Nesting}, Depth: 4, Seed: 4})
+	got := m.Apply("sendMessage", []string{"Message"}, message()).(map[string]interface{})
+
+	entities := got["entities"].([]interface{})
+	if len(entities) != 3 {
+		t.Errorf("expected 3 nested entities over 5 characters, got %v", entities)
+	}
+	depth := 0
+	for reply := got["reply_to_message"]; reply != nil; depth++ {
+		reply = reply.(map[string]interface{})["reply_to_message"]
+	}
+	if depth != 4 {
+		t.Errorf("expected a chain of 4 replies, got %d", depth)
+	}
+	if _, stats := m.Get(); stats.Nested != 1 || stats.Responses != 1 {
+		t.Errorf("expected one nested message, got %+v", stats)
+	}
+}
+
+func TestMutator_Seeded(t *testing.T) {
+	run := func(seed int64) string {
+		m := newMutator(Config{Probability: 0.5, Seed: seed})
+		data, _ := json.Marshal(m.Apply("sendMessage", []string{"Message"}, message()))
+		return string(data)
+	}
+	if run(5) != run(5) {
+		t.Error("expected the same seed to give the same response")
+	}
+	if run(5) == run(6) {
+		t.Error("expected different seeds to give different responses")
+	}
+
+	m := newMutator(Config{})
+	if cfg, _ := m.Get(); cfg.Seed == 0 || cfg.Probability != 1 || cfg.Depth != DefaultDepth {
+		t.Errorf("expected the seed picked and defaults reported, got %+v", cfg)
+	}
+}
+
+func TestMutator_Methods(t *testing.T) {
+	m := newMutator(Config{Methods: []string{"getMe"}, Seed: 1})
+	msg := message()
+	if got := m.Apply("sendMessage", []string{"Message"}, msg); !reflect.DeepEqual(got, msg) {
+		t.Errorf("expected other methods unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,182 @@
+// Package apiversion simulates older Bot API versions. Methods introduced
+// after the chosen version don't exist there, and calls to them are
+// reported so that accidental use of too-new API surface stands out.
+package apiversion
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// Version is a Bot API version such as 7.10. The zero Version means the
+// latest version the spec describes.
+type Version struct {
+	Major int
+	Minor int
+}
+
+// Latest is the version described by the spec.
+var Latest = MustParse(gen.Version)
+
+// Parse parses a version such as "7.10" or "Bot API 7.10".
+func Parse(s string) (Version, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "Bot API ")
+	major, minor, ok := strings.Cut(s, ".")
+	if !ok {
+		minor = "0"
+	}
+	maj, err1 := strconv.Atoi(major)
+	min, err2 := strconv.Atoi(minor)
+	if err1 != nil || err2 != nil || maj < 0 || min < 0 {
+		return Version{}, fmt.Errorf("invalid Bot API version %q", s)
+	}
+	return Version{Major: maj, Minor: min}, nil
+}
+
+// ParseSupported parses a version and checks that the spec describes it.
+// The empty string means the latest version and returns the zero Version.
+func ParseSupported(s string) (Version, error) {
+	if strings.TrimSpace(s) == "" {
+		return Version{}, nil
+	}
+	v, err := Parse(s)
+	if err != nil {
+		return Version{}, err
+	}
+	if Latest.Before(v) {
+		return Version{}, fmt.Errorf("Bot API %s is newer than the spec (%s)", v, Latest)
+	}
+	return v, nil
+}
+
+// MustParse is like Parse but panics on error.
+func MustParse(s string) Version {
+	v, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// IsZero reports whether v is the zero Version.
+func (v Version) IsZero() bool {
+	return v == Version{}
+}
+
+// Before reports whether v is older than o.
+func (v Version) Before(o Version) bool {
+	if v.Major != o.Major {
+		return v.Major < o.Major
+	}
+	return v.Minor < o.Minor
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// Introduced returns the version that added a method. Methods that predate
+// Bot API 5.0 are not listed and report false.
+func Introduced(method string) (Version, bool) {
+	s, ok := introduced[method]
+	if !ok {
+		return Version{}, false
+	}
+	return MustParse(s), true
+}
+
+// Available reports whether a method exists in version v.
+func Available(method string, v Version) bool {
+	if v.IsZero() {
+		return true
+	}
+	since, ok := Introduced(method)
+	return !ok || !v.Before(since)
+}
+
+// Call summarizes the calls to a method that the simulated version
+// doesn't have.
+type Call struct {
+	Method       string    `json:"method"`
+	IntroducedIn string    `json:"introduced_in"`
+	Calls        int       `json:"calls"`
+	LastCalledAt time.Time `json:"last_called_at"`
+}
+
+// Gate holds the simulated version of a session and the calls it refused.
+type Gate struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	version Version
+	calls   map[string]*Call
+}
+
+// NewGate creates a gate for version v (zero = latest) reading the time
+// from now. If now is nil, the system clock is used.
+func NewGate(v Version, now func() time.Time) *Gate {
+	if now == nil {
+		now = time.Now
+	}
+	return &Gate{now: now, version: v, calls: make(map[string]*Call)}
+}
+
+// Version returns the simulated version.
+func (g *Gate) Version() Version {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.version.IsZero() {
+		return Latest
+	}
+	return g.version
+}
+
+// SetVersion changes the simulated version (zero = latest).
+func (g *Gate) SetVersion(v Version) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.version = v
+}
+
+// Allow reports whether method exists in the simulated version. Refused
+// calls are counted.
+func (g *Gate) Allow(method string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if Available(method, g.version) {
+		return true
+	}
+	c, ok := g.calls[method]
+	if !ok {
+		since, _ := Introduced(method)
+		c = &Call{Method: method, IntroducedIn: since.String()}
+		g.calls[method] = c
+	}
+	c.Calls++
+	c.LastCalledAt = g.now()
+	return false
+}
+
+// Calls returns the refused calls ordered by method.
+func (g *Gate) Calls() []Call {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	result := make([]Call, 0, len(g.calls))
+	for _, c := range g.calls {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Method < result[j].Method })
+	return result
+}
+
+// Reset forgets the refused calls. The version is kept.
+func (g *Gate) Reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.calls = make(map[string]*Call)
+}
@@ -0,0 +1,91 @@
+// internal/apiversion/apiversion_test.go
+package apiversion
+
+import (
+	"testing"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+func TestIntroducedMethodsExist(t *testing.T) {
+	for method, version := range introduced {
+		if _, ok := gen.Methods[method]; !ok {
+			t.Errorf("%s is not in the spec", method)
+		}
+		v, err := Parse(version)
+		if err != nil {
+			t.Errorf("%s: %v", method, err)
+		}
+		if Latest.Before(v) {
+			t.Errorf("%s was introduced in %s, after the spec's %s", method, v, Latest)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Version
+		ok   bool
+	}{
+		{"7.0", Version{7, 0}, true},
+		{"7.10", Version{7, 10}, true},
+		{"Bot API 6.9", Version{6, 9}, true},
+		{"8", Version{8, 0}, true},
+		{"seven", Version{}, false},
+		{"7.x", Version{}, false},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if (err == nil) != tt.ok || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v (ok=%v)", tt.in, got, err, tt.want, tt.ok)
+		}
+	}
+
+	if !MustParse("7.9").Before(MustParse("7.10")) {
+		t.Error("expected 7.9 to be before 7.10")
+	}
+	if _, err := ParseSupported("99.0"); err == nil {
+		t.Error("expected a version newer than the spec to be rejected")
+	}
+	if v, err := ParseSupported(""); err != nil || !v.IsZero() {
+		t.Errorf("expected empty version to mean latest, got %v, %v", v, err)
+	}
+}
+
+func TestGate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := NewGate(MustParse("6.9"), func() time.Time { return now })
+
+	if !g.Allow("sendMessage") {
+		t.Error("expected methods older than 5.0 to be allowed")
+	}
+	if !g.Allow("createForumTopic") {
+		t.Error("expected 6.3 method to be allowed in 6.9")
+	}
+	if g.Allow("setMessageReaction") || g.Allow("setMessageReaction") {
+		t.Error("expected 7.0 method to be refused in 6.9")
+	}
+
+	calls := g.Calls()
+	if len(calls) != 1 {
+		t.Fatalf("expected 1 refused method, got %d", len(calls))
+	}
+	if c := calls[0]; c.Method != "setMessageReaction" || c.IntroducedIn != "7.0" || c.Calls != 2 || !c.LastCalledAt.Equal(now) {
+		t.Errorf("unexpected call summary: %+v", c)
+	}
+
+	g.SetVersion(Version{})
+	if !g.Allow("setMessageReaction") {
+		t.Error("expected the latest version to allow every method")
+	}
+	if g.Version() != Latest {
+		t.Errorf("expected zero version to report %s, got %s", Latest, g.Version())
+	}
+
+	g.Reset()
+	if len(g.Calls()) != 0 {
+		t.Error("expected Reset to forget refused calls")
+	}
+}
@@ -0,0 +1,123 @@
+// internal/apiversion/methods.go
+package apiversion
+
+// introduced maps methods added since Bot API 5.0 to the version that
+// added them, following the Bot API changelog. Renamed methods are listed
+// under the version that introduced their current name.
+var introduced = map[string]string{
+	// 5.0
+	"logOut":               "5.0",
+	"close":                "5.0",
+	"copyMessage":          "5.0",
+	"unpinAllChatMessages": "5.0",
+	// 5.1
+	"createChatInviteLink": "5.1",
+	"editChatInviteLink":   "5.1",
+	"revokeChatInviteLink": "5.1",
+	// 5.3
+	"banChatMember":      "5.3",
+	"getChatMemberCount": "5.3",
+	"deleteMyCommands":   "5.3",
+	// 5.4
+	"approveChatJoinRequest": "5.4",
+	"declineChatJoinRequest": "5.4",
+	// 5.5
+	"banChatSenderChat":   "5.5",
+	"unbanChatSenderChat": "5.5",
+	// 6.0
+	"answerWebAppQuery":               "6.0",
+	"setChatMenuButton":               "6.0",
+	"getChatMenuButton":               "6.0",
+	"setMyDefaultAdministratorRights": "6.0",
+	"getMyDefaultAdministratorRights": "6.0",
+	// 6.1
+	"createInvoiceLink": "6.1",
+	// 6.2
+	"getCustomEmojiStickers": "6.2",
+	// 6.3
+	"createForumTopic":           "6.3",
+	"editForumTopic":             "6.3",
+	"closeForumTopic":            "6.3",
+	"reopenForumTopic":           "6.3",
+	"deleteForumTopic":           "6.3",
+	"unpinAllForumTopicMessages": "6.3",
+	"getForumTopicIconStickers":  "6.3",
+	// 6.4
+	"editGeneralForumTopic":   "6.4",
+	"closeGeneralForumTopic":  "6.4",
+	"reopenGeneralForumTopic": "6.4",
+	"hideGeneralForumTopic":   "6.4",
+	"unhideGeneralForumTopic": "6.4",
+	// 6.6
+	"setMyDescription":                  "6.6",
+	"getMyDescription":                  "6.6",
+	"setMyShortDescription":             "6.6",
+	"getMyShortDescription":             "6.6",
+	"setStickerSetThumbnail":            "6.6",
+	"setCustomEmojiStickerSetThumbnail": "6.6",
+	"setStickerSetTitle":                "6.6",
+	"deleteStickerSet":                  "6.6",
+	"setStickerEmojiList":               "6.6",
+	"setStickerKeywords":                "6.6",
+	"setStickerMaskPosition":            "6.6",
+	// 6.7
+	"setMyName": "6.7",
+	"getMyName": "6.7",
+	// 6.8
+	"unpinAllGeneralForumTopicMessages": "6.8",
+	// 7.0
+	"setMessageReaction": "7.0",
+	"deleteMessages":     "7.0",
+	"forwardMessages":    "7.0",
+	"copyMessages":       "7.0",
+	"getUserChatBoosts":  "7.0",
+	// 7.2
+	"getBusinessConnection": "7.2",
+	"replaceStickerInSet":   "7.2",
+	// 7.4
+	"refundStarPayment": "7.4",
+	// 7.5
+	"getStarTransactions": "7.5",
+	// 7.6
+	"sendPaidMedia": "7.6",
+	// 7.9
+	"createChatSubscriptionInviteLink": "7.9",
+	"editChatSubscriptionInviteLink":   "7.9",
+	// 8.0
+	"savePreparedInlineMessage": "8.0",
+	"getAvailableGifts":         "8.0",
+	"sendGift":                  "8.0",
+	"setUserEmojiStatus":        "8.0",
+	"editUserStarSubscription":  "8.0",
+	// 8.2
+	"verifyUser":             "8.2",
+	"verifyChat":             "8.2",
+	"removeUserVerification": "8.2",
+	"removeChatVerification": "8.2",
+	// 9.0
+	"readBusinessMessage":               "9.0",
+	"deleteBusinessMessages":            "9.0",
+	"setBusinessAccountName":            "9.0",
+	"setBusinessAccountUsername":        "9.0",
+	"setBusinessAccountBio":             "9.0",
+	"setBusinessAccountProfilePhoto":    "9.0",
+	"removeBusinessAccountProfilePhoto": "9.0",
+	"setBusinessAccountGiftSettings":    "9.0",
+	"getBusinessAccountStarBalance":     "9.0",
+	"transferBusinessAccountStars":      "9.0",
+	"getBusinessAccountGifts":           "9.0",
+	"convertGiftToStars":                "9.0",
+	"upgradeGift":                       "9.0",
+	"transferGift":                      "9.0",
+	"postStory":                         "9.0",
+	"editStory":                         "9.0",
+	"deleteStory":                       "9.0",
+	"giftPremiumSubscription":           "9.0",
+	// 9.1
+	"sendChecklist":        "9.1",
+	"editMessageChecklist": "9.1",
+	"getMyStarBalance":     "9.1",
+	// 9.2
+	"approveSuggestedPost": "9.2",
+	"declineSuggestedPost": "9.2",
+}
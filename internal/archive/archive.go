@@ -0,0 +1,259 @@
+// Package archive keeps the history of simulated activity so that the
+// complete event log of a chat can be exported for archival and offline
+// analysis. User activity comes from injected updates, which the archive
+// keeps after they are delivered; bot activity comes from the recorded
+// requests.
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/inspector"
+)
+
+// Sources of events.
+const (
+	SourceUser = "user" // An injected update
+	SourceBot  = "bot"  // A Bot API call
+)
+
+// Types of Bot API calls. Updates use their update type instead, such as
+// message or message_reaction.
+const (
+	TypeSend     = "send"
+	TypeEdit     = "edit"
+	TypeDelete   = "delete"
+	TypeReaction = "reaction"
+	TypeCall     = "call"
+)
+
+// Event is a single entry in the history of a chat.
+type Event struct {
+	Timestamp time.Time `json:"timestamp"`
+	ChatID    string    `json:"chat_id"`
+	Source    string    `json:"source"`
+	Type      string    `json:"type"`
+
+	// Set for updates
+	UpdateID int64                  `json:"update_id,omitempty"`
+	Update   map[string]interface{} `json:"update,omitempty"`
+
+	// Set for Bot API calls
+	RequestID  int64                  `json:"request_id,omitempty"`
+	Token      string                 `json:"token,omitempty"`
+	Method     string                 `json:"method,omitempty"`
+	Params     map[string]interface{} `json:"params,omitempty"`
+	Response   interface{}            `json:"response,omitempty"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	IsError    bool                   `json:"is_error,omitempty"`
+}
+
+type loggedUpdate struct {
+	timestamp time.Time
+	chatID    string
+	update    map[string]interface{}
+}
+
+// Archive logs the updates injected into a session.
+type Archive struct {
+	mu      sync.RWMutex
+	updates []loggedUpdate
+}
+
+// New creates an empty archive.
+func New() *Archive {
+	return &Archive{}
+}
+
+// AddUpdate logs an injected update. Updates that don't belong to a chat,
+// such as inline queries, are ignored. The update is copied, so later
+// changes to it are not archived.
+func (a *Archive) AddUpdate(update map[string]interface{}) {
+	chat := updateChat(update)
+	if chat == "" {
+		return
+	}
+	data, err := json.Marshal(update)
+	if err != nil {
+		return
+	}
+	var copied map[string]interface{}
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	// The recorder stamps requests with the system clock, so updates are
+	// stamped the same way to keep the two in order.
+	a.updates = append(a.updates, loggedUpdate{timestamp: time.Now(), chatID: chat, update: copied})
+}
+
+// Clear forgets all logged updates.
+func (a *Archive) Clear() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.updates = nil
+}
+
+// Chat returns the history of a chat, oldest first, merging the logged
+// updates with the requests that targeted the chat.
+func (a *Archive) Chat(chatID string, requests []inspector.RequestRecord) []Event {
+	events := make([]Event, 0)
+
+	a.mu.RLock()
+	for _, u := range a.updates {
+		if u.chatID != chatID {
+			continue
+		}
+		id, _ := u.update["update_id"].(float64)
+		events = append(events, Event{
+			Timestamp: u.timestamp,
+			ChatID:    chatID,
+			Source:    SourceUser,
+			Type:      updateType(u.update),
+			UpdateID:  int64(id),
+			Update:    u.update,
+		})
+	}
+	a.mu.RUnlock()
+
+	for _, req := range requests {
+		if chatKey(req.Params["chat_id"]) != chatID {
+			continue
+		}
+		events = append(events, Event{
+			Timestamp:  req.Timestamp,
+			ChatID:     chatID,
+			Source:     SourceBot,
+			Type:       callType(req.Method),
+			RequestID:  req.ID,
+			Token:      req.Token,
+			Method:     req.Method,
+			Params:     req.Params,
+			Response:   req.Response,
+			StatusCode: req.StatusCode,
+			IsError:    req.IsError,
+		})
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Timestamp.Equal(events[j].Timestamp) {
+			return events[i].Timestamp.Before(events[j].Timestamp)
+		}
+		// Keep requests stamped at the same time in recording order
+		return events[i].RequestID != 0 && events[i].RequestID < events[j].RequestID
+	})
+	return events
+}
+
+// messageTypes are the update types carrying a message.
+var messageTypes = []string{"message", "edited_message", "channel_post", "edited_channel_post", "business_message", "edited_business_message"}
+
+// Message returns the latest version of a message injected into chatID,
+// so that bots can reply to messages they never sent. The message is
+// copied.
+func (a *Archive) Message(chatID string, messageID int64) (map[string]interface{}, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	for i := len(a.updates) - 1; i >= 0; i-- {
+		u := a.updates[i]
+		if u.chatID != chatID {
+			continue
+		}
+		for _, typ := range messageTypes {
+			msg, ok := u.update[typ].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if id, ok := msg["message_id"].(float64); ok && int64(id) == messageID {
+				copied := make(map[string]interface{}, len(msg))
+				for k, v := range msg {
+					copied[k] = v
+				}
+				return copied, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// WriteJSONL writes events as JSON Lines, one event per line.
+func WriteJSONL(w io.Writer, events []Event) error {
+	enc := json.NewEncoder(w)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateType returns the type of an update, i.e. its only field besides
+// update_id.
+func updateType(update map[string]interface{}) string {
+	for key := range update {
+		if key != "update_id" {
+			return key
+		}
+	}
+	return ""
+}
+
+// updateChat returns the chat an update belongs to, or "" if it has none.
+// Most updates carry the chat directly; callback queries carry it in
+// their message.
+func updateChat(update map[string]interface{}) string {
+	payload, ok := update[updateType(update)].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if chat, ok := payload["chat"].(map[string]interface{}); ok {
+		return chatKey(chat["id"])
+	}
+	if msg, ok := payload["message"].(map[string]interface{}); ok {
+		if chat, ok := msg["chat"].(map[string]interface{}); ok {
+			return chatKey(chat["id"])
+		}
+	}
+	return ""
+}
+
+// callType classifies a Bot API call by its effect on the chat.
+func callType(method string) string {
+	switch {
+	case method == "setMessageReaction":
+		return TypeReaction
+	case strings.HasPrefix(method, "edit"), method == "stopPoll":
+		return TypeEdit
+	case method == "deleteMessage" || method == "deleteMessages":
+		return TypeDelete
+	case method == "sendChatAction":
+		return TypeCall
+	case strings.HasPrefix(method, "send"), strings.HasPrefix(method, "copyMessage"), strings.HasPrefix(method, "forwardMessage"):
+		return TypeSend
+	default:
+		return TypeCall
+	}
+}
+
+// chatKey formats a chat ID as a string, or returns "" if there is none.
+func chatKey(v interface{}) string {
+	switch id := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return fmt.Sprint(id)
+	}
+}
@@ -0,0 +1,98 @@
+// internal/archive/archive_test.go
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/inspector"
+)
+
+func TestArchive_Chat(t *testing.T) {
+	a := New()
+	a.AddUpdate(map[string]interface{}{
+		"update_id": int64(1),
+		"message":   map[string]interface{}{"message_id": 1, "chat": map[string]interface{}{"id": 42}, "text": "hi"},
+	})
+	a.AddUpdate(map[string]interface{}{
+		"update_id":      int64(2),
+		"callback_query": map[string]interface{}{"id": "cb", "message": map[string]interface{}{"chat": map[string]interface{}{"id": 42}}},
+	})
+	a.AddUpdate(map[string]interface{}{
+		"update_id":    int64(3),
+		"inline_query": map[string]interface{}{"id": "iq", "query": "cats"},
+	})
+	a.AddUpdate(map[string]interface{}{
+		"update_id": int64(4),
+		"message":   map[string]interface{}{"message_id": 2, "chat": map[string]interface{}{"id": 7}},
+	})
+
+	later := time.Now().Add(time.Second)
+	requests := []inspector.RequestRecord{
+		{ID: 3, Timestamp: later, Method: "deleteMessage", Params: map[string]interface{}{"chat_id": float64(42), "message_id": float64(10)}},
+		{ID: 2, Timestamp: later, Method: "setMessageReaction", Params: map[string]interface{}{"chat_id": "42"}},
+		{ID: 1, Timestamp: later, Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(42), "text": "hello"}, StatusCode: 200},
+		{ID: 4, Timestamp: later, Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(7)}},
+		{ID: 5, Timestamp: later, Method: "getMe", Params: map[string]interface{}{}},
+	}
+
+	events := a.Chat("42", requests)
+	want := []struct{ source, typ string }{
+		{SourceUser, "message"},
+		{SourceUser, "callback_query"},
+		{SourceBot, TypeSend},
+		{SourceBot, TypeReaction},
+		{SourceBot, TypeDelete},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		if events[i].Source != w.source || events[i].Type != w.typ {
+			t.Errorf("event %d: got %s/%s, want %s/%s", i, events[i].Source, events[i].Type, w.source, w.typ)
+		}
+	}
+	if events[0].UpdateID != 1 || events[2].RequestID != 1 {
+		t.Errorf("unexpected IDs: update %d, request %d", events[0].UpdateID, events[2].RequestID)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteJSONL(&buf, events); err != nil {
+		t.Fatal(err)
+	}
+	lines := 0
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("line %d is not JSON: %v", lines, err)
+		}
+		lines++
+	}
+	if lines != len(events) {
+		t.Errorf("got %d lines, want %d", lines, len(events))
+	}
+
+	a.Clear()
+	if events := a.Chat("42", nil); len(events) != 0 {
+		t.Errorf("expected no events after Clear, got %d", len(events))
+	}
+}
+
+func TestArchive_CopiesUpdates(t *testing.T) {
+	a := New()
+	msg := map[string]interface{}{"chat": map[string]interface{}{"id": 1}, "text": "before"}
+	a.AddUpdate(map[string]interface{}{"update_id": int64(1), "message": msg})
+	msg["text"] = "after"
+
+	events := a.Chat("1", nil)
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	if text := events[0].Update["message"].(map[string]interface{})["text"]; text != "before" {
+		t.Errorf("archived update changed to %v", text)
+	}
+}
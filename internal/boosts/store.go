@@ -0,0 +1,162 @@
+// Package boosts keeps the boosts users give chats, so that
+// getUserChatBoosts returns the boosts that were seeded or simulated.
+package boosts
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// Lifetime is how long a boost lasts when its expiration_date isn't
+// given.
+const Lifetime = 30 * 24 * time.Hour
+
+// Entry is a ChatBoost given to a chat.
+type Entry struct {
+	ChatID string                 `json:"chat_id"`
+	Boost  map[string]interface{} `json:"boost"`
+}
+
+// Store holds the boosts of chats, keyed by chat and boost ID. Values are
+// copied on the way in and out.
+type Store struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	chats map[string]map[string]map[string]interface{}
+}
+
+// NewStore creates an empty boost store reading the time from now, which
+// decides when boosts expire.
+func NewStore(now func() time.Time) *Store {
+	return &Store{now: now, chats: make(map[string]map[string]map[string]interface{})}
+}
+
+// Add stores a ChatBoost given to a chat, replacing any boost with the
+// same ID, and returns it. The boost needs a boost_id and a source;
+// add_date defaults to now and expiration_date to Lifetime later.
+func (s *Store) Add(chatID string, boost map[string]interface{}) (map[string]interface{}, error) {
+	boost = jsoncopy.Map(boost)
+	id, _ := boost["boost_id"].(string)
+	if id == "" {
+		return nil, errors.New("boost needs a boost_id")
+	}
+	if _, ok := boost["source"].(map[string]interface{}); !ok {
+		return nil, errors.New("boost needs a source")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := boost["add_date"]; !ok {
+		boost["add_date"] = float64(s.now().Unix())
+	}
+	if _, ok := boost["expiration_date"]; !ok {
+		added, _ := boost["add_date"].(float64)
+		boost["expiration_date"] = added + Lifetime.Seconds()
+	}
+	if s.chats[chatID] == nil {
+		s.chats[chatID] = make(map[string]map[string]interface{})
+	}
+	s.chats[chatID][id] = boost
+	return jsoncopy.Map(boost), nil
+}
+
+// Get returns a boost of a chat.
+func (s *Store) Get(chatID, boostID string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	boost, ok := s.chats[chatID][boostID]
+	if !ok {
+		return nil, false
+	}
+	return jsoncopy.Map(boost), true
+}
+
+// Remove forgets a boost and returns it. It returns false if the boost
+// wasn't known.
+func (s *Store) Remove(chatID, boostID string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	boost, ok := s.chats[chatID][boostID]
+	if !ok {
+		return nil, false
+	}
+	delete(s.chats[chatID], boostID)
+	if len(s.chats[chatID]) == 0 {
+		delete(s.chats, chatID)
+	}
+	return boost, true
+}
+
+// Known reports whether a chat has any boosts.
+func (s *Store) Known(chatID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.chats[chatID]) > 0
+}
+
+// User returns the boosts a user gave a chat that haven't expired, oldest
+// first.
+func (s *Store) User(chatID string, userID int64) []map[string]interface{} {
+	now := float64(s.now().Unix())
+	list := []map[string]interface{}{}
+	for _, e := range s.List(chatID) {
+		source, _ := e.Boost["source"].(map[string]interface{})
+		user, _ := source["user"].(map[string]interface{})
+		id, _ := user["id"].(float64)
+		expires, _ := e.Boost["expiration_date"].(float64)
+		if int64(id) == userID && (expires == 0 || expires > now) {
+			list = append(list, e.Boost)
+		}
+	}
+	return list
+}
+
+// List returns the boosts of a chat, or of every chat if chatID is empty,
+// ordered by chat and by the date they were added.
+func (s *Store) List(chatID string) []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []Entry
+	for id, boosts := range s.chats {
+		if chatID != "" && id != chatID {
+			continue
+		}
+		for _, boost := range boosts {
+			list = append(list, Entry{ChatID: id, Boost: jsoncopy.Map(boost)})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ChatID != list[j].ChatID {
+			return list[i].ChatID < list[j].ChatID
+		}
+		di, _ := list[i].Boost["add_date"].(float64)
+		dj, _ := list[j].Boost["add_date"].(float64)
+		if di != dj {
+			return di < dj
+		}
+		bi, _ := list[i].Boost["boost_id"].(string)
+		bj, _ := list[j].Boost["boost_id"].(string)
+		return bi < bj
+	})
+	return list
+}
+
+// Restore replaces the store contents with the given boosts.
+func (s *Store) Restore(list []Entry) {
+	s.mu.Lock()
+	s.chats = make(map[string]map[string]map[string]interface{})
+	s.mu.Unlock()
+	for _, e := range list {
+		s.Add(e.ChatID, e.Boost)
+	}
+}
+
+// Clear forgets all boosts.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[string]map[string]interface{})
+}
@@ -0,0 +1,69 @@
+// internal/boosts/store_test.go
+package boosts
+
+import (
+	"testing"
+	"time"
+)
+
+func boost(id string, userID int64, added, expires int64) map[string]interface{} {
+	return map[string]interface{}{
+		"boost_id":        id,
+		"add_date":        added,
+		"expiration_date": expires,
+		"source": map[string]interface{}{
+			"source": "premium",
+			"user":   map[string]interface{}{"id": userID, "is_bot": false, "first_name": "Ann"},
+		},
+	}
+}
+
+func TestStore_User(t *testing.T) {
+	s := NewStore(func() time.Time { return time.Unix(1700000500, 0) })
+
+	if _, err := s.Add("-100", map[string]interface{}{"boost_id": "x"}); err == nil {
+		t.Error("expected a boost without a source to be rejected")
+	}
+	added, _ := s.Add("-300", map[string]interface{}{"boost_id": "new", "source": map[string]interface{}{"source": "premium"}})
+	if added["add_date"] != float64(1700000500) || added["expiration_date"] != float64(1700000500)+Lifetime.Seconds() {
+		t.Errorf("expected default dates, got %v", added)
+	}
+	s.Clear()
+	s.Add("-100", boost("b", 7, 1700000200, 1800000000))
+	s.Add("-100", boost("a", 7, 1700000100, 1800000000))
+	s.Add("-100", boost("old", 7, 1600000000, 1700000000))
+	s.Add("-100", boost("c", 8, 1700000100, 1800000000))
+	s.Add("-200", boost("d", 7, 1700000100, 1800000000))
+
+	list := s.User("-100", 7)
+	if len(list) != 2 || list[0]["boost_id"] != "a" || list[1]["boost_id"] != "b" {
+		t.Errorf("expected the user's active boosts, oldest first, got %v", list)
+	}
+	if list := s.User("-400", 7); list == nil || len(list) != 0 || s.Known("-400") {
+		t.Errorf("expected no boosts in other chats, got %v", list)
+	}
+}
+
+func TestStore_Remove(t *testing.T) {
+	s := NewStore(time.Now)
+	s.Add("-100", boost("a", 7, 1700000100, 0))
+
+	if _, ok := s.Remove("-100", "missing"); ok {
+		t.Error("expected unknown boosts not to be removed")
+	}
+	removed, ok := s.Remove("-100", "a")
+	if !ok || removed["boost_id"] != "a" {
+		t.Fatalf("expected the boost to be removed, got %v %v", removed, ok)
+	}
+	if s.Known("-100") {
+		t.Error("expected the chat to have no boosts left")
+	}
+
+	s.Add("-100", boost("b", 7, 1700000100, 0))
+	snapshot := s.List("")
+	s.Clear()
+	s.Restore(snapshot)
+	if list := s.User("-100", 7); len(list) != 1 {
+		t.Errorf("expected restored boosts, got %v", list)
+	}
+}
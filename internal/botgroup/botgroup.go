@@ -0,0 +1,150 @@
+// Package botgroup tracks group chats shared by several mock bots, so that
+// a message sent by one bot can be delivered to the others as an update.
+package botgroup
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Member is a bot taking part in a group. Session is the session whose
+// update queue the bot polls when it has no webhook.
+type Member struct {
+	Token   string `json:"token"`
+	Session string `json:"session,omitempty"`
+}
+
+// Group is a chat shared by several bots.
+type Group struct {
+	ChatID  string   `json:"chat_id"`
+	Members []Member `json:"members"`
+	// BotsSeeBots lifts Telegram's rule that bots never receive messages
+	// sent by other bots. While it is false, messages are not relayed.
+	BotsSeeBots bool `json:"bots_see_bots"`
+
+	// Relayed counts the updates delivered to other members.
+	Relayed int `json:"relayed"`
+	// Suppressed counts the updates withheld by the bots-can't-see-bots
+	// rule.
+	Suppressed int `json:"suppressed"`
+}
+
+// Validate checks the group's membership.
+func (g *Group) Validate() error {
+	if g.ChatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(g.Members) < 2 {
+		return fmt.Errorf("a group needs at least two bots")
+	}
+	seen := make(map[string]bool, len(g.Members))
+	for _, m := range g.Members {
+		if m.Token == "" {
+			return fmt.Errorf("member token is required")
+		}
+		if seen[m.Token] {
+			return fmt.Errorf("bot %s is listed twice", m.Token)
+		}
+		seen[m.Token] = true
+	}
+	return nil
+}
+
+// Registry holds the bot groups, keyed by chat ID.
+type Registry struct {
+	mu     sync.Mutex
+	groups map[string]*Group
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{groups: make(map[string]*Group)}
+}
+
+// Set creates or replaces a group. The counters start from zero.
+func (r *Registry) Set(g Group) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	g.Members = append([]Member(nil), g.Members...)
+	g.Relayed = 0
+	g.Suppressed = 0
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.groups[g.ChatID] = &g
+	return nil
+}
+
+// Get returns the group of a chat.
+func (r *Registry) Get(chatID string) (Group, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	g, ok := r.groups[chatID]
+	if !ok {
+		return Group{}, false
+	}
+	return *g, true
+}
+
+// List returns all groups ordered by chat ID.
+func (r *Registry) List() []Group {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]Group, 0, len(r.groups))
+	for _, g := range r.groups {
+		result = append(result, *g)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChatID < result[j].ChatID })
+	return result
+}
+
+// Delete removes the group of a chat. Returns true if one was removed.
+func (r *Registry) Delete(chatID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.groups[chatID]; ok {
+		delete(r.groups, chatID)
+		return true
+	}
+	return false
+}
+
+// Clear removes all groups.
+func (r *Registry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.groups = make(map[string]*Group)
+}
+
+// Route returns the members that should receive a message sent by token
+// to a chat. It returns nil if the chat isn't a group, token isn't one of
+// its members, or bots can't see each other there.
+func (r *Registry) Route(chatID, token string) []Member {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	g, ok := r.groups[chatID]
+	if !ok {
+		return nil
+	}
+	var targets []Member
+	member := false
+	for _, m := range g.Members {
+		if m.Token == token {
+			member = true
+		} else {
+			targets = append(targets, m)
+		}
+	}
+	if !member {
+		return nil
+	}
+	if !g.BotsSeeBots {
+		g.Suppressed += len(targets)
+		return nil
+	}
+	g.Relayed += len(targets)
+	return targets
+}
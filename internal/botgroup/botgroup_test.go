@@ -0,0 +1,68 @@
+// internal/botgroup/botgroup_test.go
+package botgroup
+
+import (
+	"testing"
+)
+
+func TestRegistry_SetValidates(t *testing.T) {
+	r := NewRegistry()
+
+	tests := []struct {
+		name  string
+		group Group
+	}{
+		{"no chat", Group{Members: []Member{{Token: "1:a"}, {Token: "2:b"}}}},
+		{"single bot", Group{ChatID: "-100", Members: []Member{{Token: "1:a"}}}},
+		{"empty token", Group{ChatID: "-100", Members: []Member{{Token: "1:a"}, {}}}},
+		{"duplicate bot", Group{ChatID: "-100", Members: []Member{{Token: "1:a"}, {Token: "1:a"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Set(tt.group); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+	if len(r.List()) != 0 {
+		t.Error("invalid groups should not be stored")
+	}
+}
+
+func TestRegistry_Route(t *testing.T) {
+	r := NewRegistry()
+	r.Set(Group{
+		ChatID:      "-100",
+		Members:     []Member{{Token: "1:a"}, {Token: "2:b", Session: "b"}, {Token: "3:c"}},
+		BotsSeeBots: true,
+	})
+
+	targets := r.Route("-100", "1:a")
+	if len(targets) != 2 || targets[0].Token != "2:b" || targets[0].Session != "b" || targets[1].Token != "3:c" {
+		t.Errorf("expected the other two bots, got %+v", targets)
+	}
+	if targets := r.Route("-100", "9:z"); targets != nil {
+		t.Errorf("expected no targets for a bot outside the group, got %+v", targets)
+	}
+	if targets := r.Route("42", "1:a"); targets != nil {
+		t.Errorf("expected no targets for a chat without group, got %+v", targets)
+	}
+
+	g, _ := r.Get("-100")
+	if g.Relayed != 2 || g.Suppressed != 0 {
+		t.Errorf("expected 2 relayed, got %+v", g)
+	}
+}
+
+func TestRegistry_RouteBotsCantSeeBots(t *testing.T) {
+	r := NewRegistry()
+	r.Set(Group{ChatID: "-100", Members: []Member{{Token: "1:a"}, {Token: "2:b"}}})
+
+	if targets := r.Route("-100", "1:a"); targets != nil {
+		t.Errorf("expected messages between bots to be withheld, got %+v", targets)
+	}
+	g, _ := r.Get("-100")
+	if g.Relayed != 0 || g.Suppressed != 1 {
+		t.Errorf("expected 1 suppressed, got %+v", g)
+	}
+}
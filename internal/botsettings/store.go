@@ -0,0 +1,270 @@
+// Package botsettings keeps what bots set about themselves, such as their
+// commands and name, so that the matching getters return it instead of generated
+// values.
+package botsettings
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/messages"
+)
+
+// Names of the texts a bot sets about itself, for each language.
+const (
+	TextName             = "name"
+	TextDescription      = "description"
+	TextShortDescription = "short_description"
+)
+
+// Bot is what a bot has set about itself, keyed by its token.
+type Bot struct {
+	Token    string     `json:"token"`
+	Commands []Commands `json:"commands,omitempty"`
+	// Texts holds the name and descriptions, by language code. The empty
+	// language code is the default.
+	Texts map[string]map[string]string `json:"texts,omitempty"`
+}
+
+// Commands are the commands of a bot for a scope and language.
+type Commands struct {
+	Scope        map[string]interface{} `json:"scope"`
+	LanguageCode string                 `json:"language_code,omitempty"`
+	Commands     []interface{}          `json:"commands"`
+}
+
+// bot is the stored settings of a bot.
+type bot struct {
+	commands map[string]Commands
+	texts    map[string]map[string]string
+}
+
+func newBot() *bot {
+	return &bot{commands: make(map[string]Commands), texts: make(map[string]map[string]string)}
+}
+
+func (b *bot) empty() bool {
+	return len(b.commands) == 0 && len(b.texts) == 0
+}
+
+// Store holds the settings of bots. Values are copied on the way in and
+// out.
+type Store struct {
+	mu   sync.RWMutex
+	bots map[string]*bot
+}
+
+// NewStore creates an empty store.
+func NewStore() *Store {
+	return &Store{bots: make(map[string]*bot)}
+}
+
+// entry returns the settings of the bot with token, creating them if
+// needed. Callers must hold s.mu for writing.
+func (s *Store) entry(token string) *bot {
+	b, ok := s.bots[token]
+	if !ok {
+		b = newBot()
+		s.bots[token] = b
+	}
+	return b
+}
+
+// forget drops the settings of the bot with token if nothing is set
+// anymore. Callers must hold s.mu for writing.
+func (s *Store) forget(token string) {
+	if b, ok := s.bots[token]; ok && b.empty() {
+		delete(s.bots, token)
+	}
+}
+
+// DefaultScope is the scope of commands set without one.
+func DefaultScope() map[string]interface{} {
+	return map[string]interface{}{"type": "default"}
+}
+
+// scopeKey identifies a scope and language. Chat IDs match however they
+// were passed.
+func scopeKey(scope map[string]interface{}, languageCode string) string {
+	if len(scope) == 0 {
+		scope = DefaultScope()
+	}
+	return messages.ChatKey(scope["type"]) + "|" + messages.ChatKey(scope["chat_id"]) + "|" +
+		messages.ChatKey(scope["user_id"]) + "|" + languageCode
+}
+
+// SetCommands replaces the commands of the bot with token for a scope and
+// language. A nil scope is the default scope.
+func (s *Store) SetCommands(token string, scope map[string]interface{}, languageCode string, commands []interface{}) {
+	if len(scope) == 0 {
+		scope = DefaultScope()
+	}
+	entry := Commands{Scope: scope, LanguageCode: languageCode, Commands: commands}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entry(token).commands[scopeKey(scope, languageCode)] = copyCommands(entry)
+}
+
+// Commands returns the commands of the bot with token for a scope and
+// language, or an empty list if none were set, as getMyCommands does.
+func (s *Store) Commands(token string, scope map[string]interface{}, languageCode string) []interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.bots[token]
+	if !ok {
+		return []interface{}{}
+	}
+	entry, ok := b.commands[scopeKey(scope, languageCode)]
+	if !ok {
+		return []interface{}{}
+	}
+	return copyCommands(entry).Commands
+}
+
+// DeleteCommands removes the commands of the bot with token for a scope and
+// language.
+func (s *Store) DeleteCommands(token string, scope map[string]interface{}, languageCode string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.bots[token]; ok {
+		delete(b.commands, scopeKey(scope, languageCode))
+		s.forget(token)
+	}
+}
+
+// SetText sets a text of the bot with token for a language, such as its
+// name. An empty value removes it. It returns false if that changes
+// nothing.
+func (s *Store) SetText(token, text, languageCode, value string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b := s.entry(token)
+	defer s.forget(token)
+	old, ok := b.texts[text][languageCode]
+	if value == "" {
+		if !ok {
+			return false
+		}
+		delete(b.texts[text], languageCode)
+		if len(b.texts[text]) == 0 {
+			delete(b.texts, text)
+		}
+		return true
+	}
+	if ok && old == value {
+		return false
+	}
+	if b.texts[text] == nil {
+		b.texts[text] = make(map[string]string)
+	}
+	b.texts[text][languageCode] = value
+	return true
+}
+
+// Text returns a text of the bot with token for a language. Languages
+// without their own text get the default one.
+func (s *Store) Text(token, text, languageCode string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.bots[token]
+	if !ok {
+		return "", false
+	}
+	if value, ok := b.texts[text][languageCode]; ok {
+		return value, true
+	}
+	value, ok := b.texts[text][""]
+	return value, ok
+}
+
+// Get returns the settings of the bot with token.
+func (s *Store) Get(token string) (Bot, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b, ok := s.bots[token]
+	if !ok {
+		return Bot{}, false
+	}
+	return b.export(token), true
+}
+
+// List returns the settings of all bots, ordered by token.
+func (s *Store) List() []Bot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	bots := make([]Bot, 0, len(s.bots))
+	for token, b := range s.bots {
+		bots = append(bots, b.export(token))
+	}
+	sort.Slice(bots, func(i, j int) bool { return bots[i].Token < bots[j].Token })
+	return bots
+}
+
+// Restore replaces the store contents with the given bots.
+func (s *Store) Restore(bots []Bot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bots = make(map[string]*bot, len(bots))
+	for _, restored := range bots {
+		b := s.entry(restored.Token)
+		for _, entry := range restored.Commands {
+			b.commands[scopeKey(entry.Scope, entry.LanguageCode)] = copyCommands(entry)
+		}
+		if texts := copyTexts(restored.Texts); texts != nil {
+			b.texts = texts
+		}
+		s.forget(restored.Token)
+	}
+}
+
+// Clear forgets all bots.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bots = make(map[string]*bot)
+}
+
+// export copies the settings of a bot, with its commands ordered by scope
+// and language. Callers must hold s.mu.
+func (b *bot) export(token string) Bot {
+	keys := make([]string, 0, len(b.commands))
+	for key := range b.commands {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	exported := Bot{Token: token, Texts: copyTexts(b.texts)}
+	for _, key := range keys {
+		exported.Commands = append(exported.Commands, copyCommands(b.commands[key]))
+	}
+	return exported
+}
+
+func copyTexts(texts map[string]map[string]string) map[string]map[string]string {
+	if len(texts) == 0 {
+		return nil
+	}
+	copied := make(map[string]map[string]string, len(texts))
+	for text, byLanguage := range texts {
+		copied[text] = make(map[string]string, len(byLanguage))
+		for languageCode, value := range byLanguage {
+			copied[text][languageCode] = value
+		}
+	}
+	return copied
+}
+
+// copyCommands deep-copies commands by converting them to what decoding
+// them from JSON gives.
+func copyCommands(c Commands) Commands {
+	var copied Commands
+	data, err := json.Marshal(c)
+	if err != nil || json.Unmarshal(data, &copied) != nil {
+		return c
+	}
+	if copied.Commands == nil {
+		copied.Commands = []interface{}{}
+	}
+	return copied
+}
@@ -0,0 +1,76 @@
+// internal/botsettings/store_test.go
+package botsettings
+
+import (
+	"testing"
+)
+
+func TestStore_Commands(t *testing.T) {
+	s := NewStore()
+	start := []interface{}{map[string]interface{}{"command": "start", "description": "Start"}}
+	s.SetCommands("bot", nil, "", start)
+	s.SetCommands("bot", map[string]interface{}{"type": "chat", "chat_id": float64(-100)}, "de", []interface{}{
+		map[string]interface{}{"command": "hilfe", "description": "Hilfe"},
+	})
+
+	if got := s.Commands("bot", map[string]interface{}{"type": "default"}, ""); len(got) != 1 {
+		t.Errorf("expected the default scope to match a missing one, got %v", got)
+	}
+	// Chat IDs match however they were passed
+	if got := s.Commands("bot", map[string]interface{}{"type": "chat", "chat_id": "-100"}, "de"); len(got) != 1 {
+		t.Errorf("expected the chat's commands, got %v", got)
+	}
+	if got := s.Commands("bot", nil, "de"); got == nil || len(got) != 0 {
+		t.Errorf("expected an empty list for another language, got %v", got)
+	}
+	if got := s.Commands("other", nil, ""); len(got) != 0 {
+		t.Errorf("expected other bots to have no commands, got %v", got)
+	}
+
+	s.DeleteCommands("bot", nil, "")
+	if got := s.Commands("bot", nil, ""); len(got) != 0 {
+		t.Errorf("expected deleted commands to be gone, got %v", got)
+	}
+}
+
+func TestStore_RestoreAndClear(t *testing.T) {
+	s := NewStore()
+	s.SetCommands("bot", nil, "en", []interface{}{map[string]interface{}{"command": "start", "description": "Start"}})
+
+	restored := NewStore()
+	restored.Restore(s.List())
+	if got := restored.Commands("bot", nil, "en"); len(got) != 1 {
+		t.Errorf("expected restored commands, got %v", got)
+	}
+
+	restored.Clear()
+	if _, ok := restored.Get("bot"); ok {
+		t.Error("expected clear to forget every bot")
+	}
+}
+
+func TestStore_Texts(t *testing.T) {
+	s := NewStore()
+	if !s.SetText("bot", TextName, "", "Weather") || !s.SetText("bot", TextName, "de", "Wetter") {
+		t.Fatal("expected new names to change the bot")
+	}
+	if s.SetText("bot", TextName, "", "Weather") {
+		t.Error("expected the same name not to change the bot")
+	}
+	if name, _ := s.Text("bot", TextName, "de"); name != "Wetter" {
+		t.Errorf("expected the German name, got %q", name)
+	}
+	if name, _ := s.Text("bot", TextName, "fr"); name != "Weather" {
+		t.Errorf("expected other languages to get the default name, got %q", name)
+	}
+	if _, ok := s.Text("bot", TextDescription, ""); ok {
+		t.Error("expected no description")
+	}
+
+	restored := NewStore()
+	restored.Restore(s.List())
+	restored.SetText("bot", TextName, "de", "")
+	if name, _ := restored.Text("bot", TextName, "de"); name != "Weather" {
+		t.Errorf("expected the removed name to fall back to the default, got %q", name)
+	}
+}
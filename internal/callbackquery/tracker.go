@@ -0,0 +1,119 @@
+// Package callbackquery tracks the callback queries sent to bots when
+// users press inline buttons, so that answerCallbackQuery calls arriving
+// after the answer deadline fail the way they do in production.
+package callbackquery
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultValidity is how long Telegram accepts an answer to a callback
+// query after the button was pressed.
+const DefaultValidity = 15 * time.Second
+
+// Status describes a single tracked callback query.
+type Status struct {
+	ID         string    `json:"id"`
+	Data       string    `json:"data,omitempty"`
+	ReceivedAt time.Time `json:"received_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// AnsweredAt is the time of the first answer that arrived in time.
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	// Pending is true while the query can still be answered.
+	Pending bool `json:"pending"`
+	// LateAnswers counts answers rejected because the query had expired.
+	LateAnswers int `json:"late_answers"`
+}
+
+// Tracker records callback queries by ID, measured against a clock.
+type Tracker struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	validity time.Duration
+	queries  map[string]*Status
+}
+
+// NewTracker creates a tracker reading the time from now, whose queries
+// can be answered for validity. A zero validity uses DefaultValidity.
+func NewTracker(now func() time.Time, validity time.Duration) *Tracker {
+	if validity <= 0 {
+		validity = DefaultValidity
+	}
+	return &Tracker{
+		now:      now,
+		validity: validity,
+		queries:  make(map[string]*Status),
+	}
+}
+
+// Track notes that a callback query was sent to the bot. Queries that are
+// already tracked keep their original timestamp.
+func (t *Tracker) Track(id, data string) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.queries[id]; ok {
+		return
+	}
+	t.queries[id] = &Status{
+		ID:         id,
+		Data:       data,
+		ReceivedAt: now,
+		ExpiresAt:  now.Add(t.validity),
+	}
+}
+
+// Answer records an answer to the query and reports whether it arrived
+// before the deadline. Unknown queries are always accepted.
+func (t *Tracker) Answer(id string) bool {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.queries[id]
+	if s == nil {
+		return true
+	}
+	if !now.Before(s.ExpiresAt) {
+		s.LateAnswers++
+		return false
+	}
+	if s.AnsweredAt == nil {
+		s.AnsweredAt = &now
+	}
+	return true
+}
+
+// List returns the status of every tracked query, oldest first.
+func (t *Tracker) List() []Status {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]Status, 0, len(t.queries))
+	for _, s := range t.queries {
+		c := *s
+		c.Pending = s.AnsweredAt == nil && now.Before(s.ExpiresAt)
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].ReceivedAt.Equal(result[j].ReceivedAt) {
+			return result[i].ReceivedAt.Before(result[j].ReceivedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Reset removes all tracked queries.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queries = make(map[string]*Status)
+}
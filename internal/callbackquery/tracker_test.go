@@ -0,0 +1,32 @@
+// internal/callbackquery/tracker_test.go
+package callbackquery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker_Deadline(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := NewTracker(func() time.Time { return now }, 5*time.Second)
+
+	tr.Track("q1", "yes")
+	now = now.Add(4 * time.Second)
+	if !tr.Answer("q1") {
+		t.Error("expected an answer within the deadline to be accepted")
+	}
+
+	tr.Track("q2", "no")
+	now = now.Add(5 * time.Second)
+	if tr.Answer("q2") {
+		t.Error("expected a late answer to be rejected")
+	}
+	if !tr.Answer("unknown") {
+		t.Error("expected unknown queries to be accepted")
+	}
+
+	list := tr.List()
+	if len(list) != 2 || list[0].AnsweredAt == nil || list[1].LateAnswers != 1 || list[1].Pending {
+		t.Errorf("unexpected statuses %+v", list)
+	}
+}
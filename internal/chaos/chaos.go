@@ -0,0 +1,217 @@
+// Package chaos fails random calls, so that bots can harden their retry
+// logic against the errors Telegram returns now and then without writing
+// a scenario for each one.
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// DefaultRetryAfterMax bounds the random retry_after of a 429 failure that
+// doesn't set its own range.
+const DefaultRetryAfterMax = 30
+
+// Failure is an error returned to a share of calls.
+type Failure struct {
+	// Probability is the chance that a call fails with this error.
+	Probability float64 `json:"probability"`
+	ErrorCode   int     `json:"error_code"`
+	// Description defaults to the usual description of ErrorCode.
+	Description string `json:"description,omitempty"`
+	// RetryAfterMin and RetryAfterMax bound the random retry_after, in
+	// seconds. For 429 errors they default to 1 and DefaultRetryAfterMax.
+	RetryAfterMin int `json:"retry_after_min,omitempty"`
+	RetryAfterMax int `json:"retry_after_max,omitempty"`
+	// Methods restricts the failure to these methods (empty = all).
+	Methods []string `json:"methods,omitempty"`
+}
+
+// Config lists the failures to inject.
+type Config struct {
+	Failures []Failure `json:"failures"`
+	// Seed makes the random choices reproducible (0 = random).
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate checks the failures and fills in their defaults. The
+// probabilities of the failures that apply to a method must not add up to
+// more than 1.
+func (c *Config) Validate() error {
+	if len(c.Failures) == 0 {
+		return fmt.Errorf("at least one failure is required")
+	}
+	var global float64
+	perMethod := make(map[string]float64)
+	for i := range c.Failures {
+		f := &c.Failures[i]
+		if f.Probability < 0 || f.Probability > 1 {
+			return fmt.Errorf("failure %d: probability must be between 0 and 1", i)
+		}
+		if f.ErrorCode < 400 || f.ErrorCode > 599 {
+			return fmt.Errorf("failure %d: error_code must be between 400 and 599", i)
+		}
+		if f.RetryAfterMin < 0 || f.RetryAfterMax < 0 {
+			return fmt.Errorf("failure %d: retry_after_min and retry_after_max must not be negative", i)
+		}
+		if f.ErrorCode == http.StatusTooManyRequests && f.RetryAfterMax == 0 {
+			if f.RetryAfterMin == 0 {
+				f.RetryAfterMin = 1
+			}
+			f.RetryAfterMax = DefaultRetryAfterMax
+		}
+		if f.RetryAfterMax < f.RetryAfterMin {
+			f.RetryAfterMax = f.RetryAfterMin
+		}
+		if len(f.Methods) == 0 {
+			global += f.Probability
+			continue
+		}
+		for _, method := range f.Methods {
+			if _, ok := gen.Methods[method]; !ok {
+				return fmt.Errorf("failure %d: unknown method %q", i, method)
+			}
+			perMethod[method] += f.Probability
+		}
+	}
+	if global > 1 {
+		return fmt.Errorf("probabilities of failures for all methods add up to more than 1")
+	}
+	for method, p := range perMethod {
+		if global+p > 1 {
+			return fmt.Errorf("probabilities of failures for %s add up to more than 1", method)
+		}
+	}
+	return nil
+}
+
+// Stats counts the calls seen and the failures injected, by error code.
+type Stats struct {
+	Calls  int            `json:"calls"`
+	Failed int            `json:"failed"`
+	ByCode map[string]int `json:"by_code"`
+}
+
+// Injector fails calls according to its configuration. It is disabled
+// until configured.
+type Injector struct {
+	mu    sync.Mutex
+	cfg   *Config
+	rng   *rand.Rand
+	stats Stats
+}
+
+// NewInjector creates a disabled injector.
+func NewInjector() *Injector {
+	return &Injector{stats: Stats{ByCode: map[string]int{}}}
+}
+
+// Set enables the injector with cfg and resets its statistics.
+func (in *Injector) Set(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.cfg = &cfg
+	in.rng = rand.New(rand.NewSource(seed))
+	in.stats = Stats{ByCode: map[string]int{}}
+	return nil
+}
+
+// Get returns the configuration, if the injector is enabled, and its
+// statistics.
+func (in *Injector) Get() (*Config, Stats) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	stats := in.stats
+	stats.ByCode = make(map[string]int, len(in.stats.ByCode))
+	for code, n := range in.stats.ByCode {
+		stats.ByCode[code] = n
+	}
+	if in.cfg == nil {
+		return nil, stats
+	}
+	cfg := *in.cfg
+	return &cfg, stats
+}
+
+// Disable stops failing calls and resets the statistics.
+func (in *Injector) Disable() {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.cfg = nil
+	in.stats = Stats{ByCode: map[string]int{}}
+}
+
+// Fail reports whether a call of method should fail, returning the error
+// if so. A single roll picks at most one of the failures that apply to the
+// method, so each fails its share of calls.
+func (in *Injector) Fail(method string) (*tgerrors.Error, bool) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if in.cfg == nil {
+		return nil, false
+	}
+	in.stats.Calls++
+
+	roll := in.rng.Float64()
+	var cumulative float64
+	for _, f := range in.cfg.Failures {
+		if !appliesTo(f, method) {
+			continue
+		}
+		cumulative += f.Probability
+		if roll >= cumulative {
+			continue
+		}
+		e := in.newError(f)
+		in.stats.Failed++
+		in.stats.ByCode[strconv.Itoa(e.ErrorCode)]++
+		return e, true
+	}
+	return nil, false
+}
+
+// newError builds the error of a failure. Callers must hold in.mu.
+func (in *Injector) newError(f Failure) *tgerrors.Error {
+	retryAfter := f.RetryAfterMin
+	if f.RetryAfterMax > f.RetryAfterMin {
+		retryAfter += in.rng.Intn(f.RetryAfterMax - f.RetryAfterMin + 1)
+	}
+	if f.ErrorCode == http.StatusTooManyRequests && f.Description == "" {
+		return tgerrors.RateLimit(retryAfter)
+	}
+	desc := f.Description
+	if desc == "" {
+		desc = http.StatusText(f.ErrorCode)
+		if desc == "" {
+			desc = "Error"
+		}
+	}
+	return &tgerrors.Error{ErrorCode: f.ErrorCode, Description: desc, RetryAfter: retryAfter}
+}
+
+func appliesTo(f Failure, method string) bool {
+	if len(f.Methods) == 0 {
+		return true
+	}
+	for _, m := range f.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+// internal/chaos/chaos_test.go
+package chaos
+
+import (
+	"testing"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{"empty", Config{}, false},
+		{"valid", Config{Failures: []Failure{{Probability: 0.05, ErrorCode: 500}}}, true},
+		{"bad probability", Config{Failures: []Failure{{Probability: 1.5, ErrorCode: 500}}}, false},
+		{"bad code", Config{Failures: []Failure{{Probability: 0.1, ErrorCode: 200}}}, false},
+		{"unknown method", Config{Failures: []Failure{{Probability: 0.1, ErrorCode: 500, Methods: []string{"nope"}}}}, false},
+		{"sum over 1", Config{Failures: []Failure{
+			{Probability: 0.6, ErrorCode: 500},
+			{Probability: 0.6, ErrorCode: 429, Methods: []string{"sendMessage"}},
+		}}, false},
+		{"sums per method", Config{Failures: []Failure{
+			{Probability: 0.6, ErrorCode: 500, Methods: []string{"getMe"}},
+			{Probability: 0.6, ErrorCode: 429, Methods: []string{"sendMessage"}},
+		}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err == nil) != tt.ok {
+				t.Errorf("expected ok=%v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateRetryAfterDefaults(t *testing.T) {
+	cfg := Config{Failures: []Failure{{Probability: 0.1, ErrorCode: 429}}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if f := cfg.Failures[0]; f.RetryAfterMin != 1 || f.RetryAfterMax != DefaultRetryAfterMax {
+		t.Errorf("unexpected retry_after range %d-%d", f.RetryAfterMin, f.RetryAfterMax)
+	}
+}
+
+func TestInjector_DisabledByDefault(t *testing.T) {
+	in := NewInjector()
+	if _, ok := in.Fail("sendMessage"); ok {
+		t.Error("expected a disabled injector not to fail calls")
+	}
+	if cfg, _ := in.Get(); cfg != nil {
+		t.Error("expected no config")
+	}
+}
+
+func TestInjector_Shares(t *testing.T) {
+	in := NewInjector()
+	err := in.Set(Config{Seed: 1, Failures: []Failure{
+		{Probability: 0.05, ErrorCode: 500, Methods: []string{"sendMessage"}},
+		{Probability: 0.02, ErrorCode: 429, RetryAfterMin: 5, RetryAfterMax: 10},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const calls = 20000
+	for i := 0; i < calls; i++ {
+		e, ok := in.Fail("sendMessage")
+		if !ok {
+			continue
+		}
+		switch e.ErrorCode {
+		case 500:
+			if e.Description != "Internal Server Error" {
+				t.Fatalf("unexpected description %q", e.Description)
+			}
+		case 429:
+			if e.RetryAfter < 5 || e.RetryAfter > 10 {
+				t.Fatalf("retry_after %d out of range", e.RetryAfter)
+			}
+		default:
+			t.Fatalf("unexpected error %+v", e)
+		}
+	}
+	for i := 0; i < calls; i++ {
+		if e, ok := in.Fail("getMe"); ok && e.ErrorCode != 429 {
+			t.Fatalf("expected getMe to only fail with 429, got %d", e.ErrorCode)
+		}
+	}
+
+	_, stats := in.Get()
+	if stats.Calls != 2*calls {
+		t.Errorf("expected %d calls, got %d", 2*calls, stats.Calls)
+	}
+	// 5% of sendMessage calls and 2% of all calls, give or take
+	if n := stats.ByCode["500"]; n < 800 || n > 1200 {
+		t.Errorf("expected about 1000 500 errors, got %d", n)
+	}
+	if n := stats.ByCode["429"]; n < 600 || n > 1000 {
+		t.Errorf("expected about 800 429 errors, got %d", n)
+	}
+	if stats.Failed != stats.ByCode["500"]+stats.ByCode["429"] {
+		t.Errorf("inconsistent stats %+v", stats)
+	}
+
+	in.Disable()
+	if _, ok := in.Fail("sendMessage"); ok {
+		t.Error("expected no failures after Disable")
+	}
+}
+
+func TestInjector_Seeded(t *testing.T) {
+	run := func() []int {
+		in := NewInjector()
+		in.Set(Config{Seed: 42, Failures: []Failure{{Probability: 0.3, ErrorCode: 429}}})
+		var got []int
+		for i := 0; i < 50; i++ {
+			if e, ok := in.Fail("getMe"); ok {
+				got = append(got, i, e.RetryAfter)
+			}
+		}
+		return got
+	}
+	a, b := run(), run()
+	if len(a) == 0 || len(a) != len(b) {
+		t.Fatalf("expected the same failures, got %v and %v", a, b)
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			t.Fatalf("expected the same failures, got %v and %v", a, b)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package chataction emulates how long chat actions sent with
+// sendChatAction stay visible, so tests can check that a bot keeps its
+// "typing…" indicator up during long operations.
+package chataction
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Visibility is how long Telegram clients show a chat action, unless a
+// message from the bot arrives first.
+const Visibility = 5 * time.Second
+
+// Status describes the chat action state of a single chat.
+type Status struct {
+	ChatID    string    `json:"chat_id"`
+	Action    string    `json:"action"`
+	Visible   bool      `json:"visible"`
+	SentAt    time.Time `json:"sent_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ClearedAt is set when a message from the bot cleared the action
+	// before it expired.
+	ClearedAt *time.Time `json:"cleared_at,omitempty"`
+
+	// Count is the number of sendChatAction calls for the chat since the
+	// session was last reset; messages clearing the action don't reset it.
+	Count int `json:"count"`
+	// Lapses counts refreshes sent after the previous action had already
+	// expired, i.e. times the indicator disappeared mid-operation.
+	Lapses int `json:"lapses"`
+	// MaxIntervalMs is the longest time between consecutive refreshes of
+	// an uncleared action.
+	MaxIntervalMs int64 `json:"max_interval_ms"`
+}
+
+// Tracker records chat actions per chat, measured against a clock.
+type Tracker struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	chats map[string]*Status
+}
+
+// NewTracker creates a tracker reading the time from now. If now is nil,
+// the system clock is used.
+func NewTracker(now func() time.Time) *Tracker {
+	if now == nil {
+		now = time.Now
+	}
+	return &Tracker{
+		now:   now,
+		chats: make(map[string]*Status),
+	}
+}
+
+// Record notes that action was sent to a chat.
+func (t *Tracker) Record(chatID, action string) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.chats[chatID]
+	if s == nil {
+		s = &Status{ChatID: chatID}
+		t.chats[chatID] = s
+	} else if s.ClearedAt == nil {
+		// Refreshing an ongoing action
+		if !now.Before(s.ExpiresAt) {
+			s.Lapses++
+		}
+		if interval := now.Sub(s.SentAt).Milliseconds(); interval > s.MaxIntervalMs {
+			s.MaxIntervalMs = interval
+		}
+	}
+
+	s.Action = action
+	s.SentAt = now
+	s.ExpiresAt = now.Add(Visibility)
+	s.ClearedAt = nil
+	s.Count++
+}
+
+// Clear hides a chat's action because the bot sent it a message. Actions
+// that have already expired are left as they are.
+func (t *Tracker) Clear(chatID string) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.chats[chatID]
+	if s == nil || s.ClearedAt != nil || !now.Before(s.ExpiresAt) {
+		return
+	}
+	s.ClearedAt = &now
+}
+
+// Get returns the status of a chat.
+func (t *Tracker) Get(chatID string) (Status, bool) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.chats[chatID]
+	if !ok {
+		return Status{}, false
+	}
+	return s.at(now), true
+}
+
+// List returns the status of every chat that received an action, ordered
+// by chat ID.
+func (t *Tracker) List() []Status {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]Status, 0, len(t.chats))
+	for _, s := range t.chats {
+		result = append(result, s.at(now))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChatID < result[j].ChatID })
+	return result
+}
+
+// Reset removes all tracked chats.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.chats = make(map[string]*Status)
+}
+
+// at returns a copy of the status with visibility evaluated at now.
+func (s *Status) at(now time.Time) Status {
+	c := *s
+	c.Visible = s.ClearedAt == nil && now.Before(s.ExpiresAt)
+	return c
+}
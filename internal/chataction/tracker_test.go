@@ -0,0 +1,95 @@
+// internal/chataction/tracker_test.go
+package chataction
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker_Visibility(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := NewTracker(func() time.Time { return now })
+
+	if _, ok := tr.Get("42"); ok {
+		t.Error("expected unknown chat to have no status")
+	}
+
+	tr.Record("42", "typing")
+	s, _ := tr.Get("42")
+	if !s.Visible || s.Action != "typing" || s.Count != 1 {
+		t.Errorf("unexpected status %+v", s)
+	}
+
+	now = now.Add(4 * time.Second)
+	if s, _ := tr.Get("42"); !s.Visible {
+		t.Error("expected action to be visible within 5 seconds")
+	}
+
+	now = now.Add(time.Second)
+	if s, _ := tr.Get("42"); s.Visible {
+		t.Error("expected action to expire after 5 seconds")
+	}
+}
+
+func TestTracker_Cadence(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := NewTracker(func() time.Time { return now })
+
+	// Refreshing every 4 seconds keeps the indicator up
+	tr.Record("42", "typing")
+	now = now.Add(4 * time.Second)
+	tr.Record("42", "typing")
+	now = now.Add(4 * time.Second)
+	tr.Record("42", "typing")
+
+	s, _ := tr.Get("42")
+	if s.Count != 3 || s.Lapses != 0 || s.MaxIntervalMs != 4000 {
+		t.Errorf("unexpected status %+v", s)
+	}
+
+	// A 6 second gap lets it lapse
+	now = now.Add(6 * time.Second)
+	tr.Record("42", "upload_photo")
+	s, _ = tr.Get("42")
+	if s.Lapses != 1 || s.MaxIntervalMs != 6000 || s.Action != "upload_photo" {
+		t.Errorf("unexpected status after lapse %+v", s)
+	}
+}
+
+func TestTracker_ClearedByMessage(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := NewTracker(func() time.Time { return now })
+
+	tr.Record("42", "typing")
+	now = now.Add(2 * time.Second)
+	tr.Clear("42")
+
+	s, _ := tr.Get("42")
+	if s.Visible || s.ClearedAt == nil || !s.ClearedAt.Equal(now) {
+		t.Errorf("expected message to clear the action, got %+v", s)
+	}
+
+	// The next action starts a new operation rather than counting as a lapse
+	now = now.Add(time.Minute)
+	tr.Record("42", "typing")
+	s, _ = tr.Get("42")
+	if !s.Visible || s.Lapses != 0 || s.MaxIntervalMs != 0 || s.ClearedAt != nil {
+		t.Errorf("unexpected status %+v", s)
+	}
+
+	// Messages after expiry don't rewrite history
+	now = now.Add(10 * time.Second)
+	tr.Clear("42")
+	if s, _ := tr.Get("42"); s.ClearedAt != nil {
+		t.Error("expected expired action not to be cleared")
+	}
+
+	tr.Record("7", "typing")
+	if list := tr.List(); len(list) != 2 || list[0].ChatID != "42" {
+		t.Errorf("unexpected list %+v", list)
+	}
+	tr.Reset()
+	if len(tr.List()) != 0 {
+		t.Error("expected Reset to remove all chats")
+	}
+}
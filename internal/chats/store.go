@@ -0,0 +1,225 @@
+// Package chats keeps the state bots give to chats, such as their title
+// and description, so that getChat returns what was set instead of
+// generated values.
+package chats
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// Chat types, as in Chat.type.
+var chatTypes = map[string]bool{"private": true, "group": true, "supergroup": true, "channel": true}
+
+// Entry is the known state of a chat: the ChatFullInfo fields that have
+// been set, keyed by the chat_id bots use for it.
+type Entry struct {
+	ChatID string                 `json:"chat_id"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Store holds the known state of chats. Values are copied on the way in
+// and out.
+type Store struct {
+	mu    sync.RWMutex
+	chats map[string]map[string]interface{}
+}
+
+// NewStore creates an empty chat store.
+func NewStore() *Store {
+	return &Store{chats: make(map[string]map[string]interface{})}
+}
+
+// Get returns the known fields of a chat.
+func (s *Store) Get(chatID string) (map[string]interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fields, ok := s.chats[chatID]
+	if !ok {
+		return nil, false
+	}
+	return jsoncopy.Map(fields), true
+}
+
+// Field returns a known field of a chat.
+func (s *Store) Field(chatID, name string) (interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	v, ok := s.chats[chatID][name]
+	return jsoncopy.Value(v), ok
+}
+
+// Set changes a field of a chat. It returns false, changing nothing, if
+// the field already has that value.
+func (s *Store) Set(chatID, name string, value interface{}) bool {
+	value = jsoncopy.Value(value)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields, ok := s.chats[chatID]
+	if !ok {
+		fields = make(map[string]interface{})
+		s.chats[chatID] = fields
+	}
+	if old, ok := fields[name]; ok && reflect.DeepEqual(old, value) {
+		return false
+	}
+	fields[name] = value
+	return true
+}
+
+// Unset removes a field of a chat, as when a bot deletes the chat's
+// sticker set. It returns false if the field is known to be unset
+// already. As with Set, a chat the store doesn't know yet is recorded.
+func (s *Store) Unset(chatID, name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields, ok := s.chats[chatID]
+	if !ok {
+		fields = make(map[string]interface{})
+		s.chats[chatID] = fields
+	}
+	if v, ok := fields[name]; ok && v == nil {
+		return false
+	}
+	// A nil value records that the field is unset, so it is also removed
+	// from generated chats
+	fields[name] = nil
+	return true
+}
+
+// Apply overlays the known fields of a chat onto a generated chat object.
+// Unset fields are removed.
+func (s *Store) Apply(chatID string, chat map[string]interface{}) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for name, v := range s.chats[chatID] {
+		if v == nil {
+			delete(chat, name)
+			continue
+		}
+		chat[name] = jsoncopy.Value(v)
+	}
+}
+
+// Seed adds a chat with the given ChatFullInfo fields, replacing what was
+// known about it, and returns its chat ID. The chat needs an integer id; a
+// missing type is derived from it as Telegram assigns IDs.
+func (s *Store) Seed(chat map[string]interface{}) (string, error) {
+	fields, _ := jsoncopy.Value(chat).(map[string]interface{})
+	id, ok := fields["id"].(float64)
+	if !ok || id != float64(int64(id)) || id == 0 {
+		return "", fmt.Errorf("chat needs an integer id")
+	}
+	if fields["type"] == nil {
+		fields["type"] = typeOf(int64(id))
+	}
+	if t, _ := fields["type"].(string); !chatTypes[t] {
+		return "", fmt.Errorf("invalid chat type %v", fields["type"])
+	}
+	chatID := strconv.FormatInt(int64(id), 10)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats[chatID] = fields
+	return chatID, nil
+}
+
+// Seeded reports whether a chat was seeded, rather than only changed by
+// bots.
+func (s *Store) Seeded(chatID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.chats[chatID]["id"]
+	return ok
+}
+
+// Username returns the ID of the chat with a username, as bots address
+// public chats by "@username". Usernames are matched regardless of case.
+func (s *Store) Username(username string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for chatID, fields := range s.chats {
+		if name, ok := fields["username"].(string); ok && strings.EqualFold(name, username) {
+			if _, err := strconv.ParseInt(chatID, 10, 64); err == nil {
+				return chatID, true
+			}
+		}
+	}
+	return "", false
+}
+
+// Type returns the type of a chat: the known one, or else the one its ID
+// suggests. It returns "" for chats named by username that aren't known.
+func (s *Store) Type(chatID string) string {
+	if t, ok := s.Field(chatID, "type"); ok {
+		if t, ok := t.(string); ok && chatTypes[t] {
+			return t
+		}
+	}
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil || id == 0 {
+		return ""
+	}
+	return typeOf(id)
+}
+
+// typeOf derives the type of a chat from its ID: users have positive IDs,
+// supergroups and channels IDs below -10^12, and groups the other negative
+// IDs.
+func typeOf(id int64) string {
+	switch {
+	case id > 0:
+		return "private"
+	case id < -1000000000000:
+		return "supergroup"
+	default:
+		return "group"
+	}
+}
+
+// Delete forgets a chat. It returns false if the chat wasn't known.
+func (s *Store) Delete(chatID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.chats[chatID]; !ok {
+		return false
+	}
+	delete(s.chats, chatID)
+	return true
+}
+
+// List returns the known chats, ordered by chat ID.
+func (s *Store) List() []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entries := make([]Entry, 0, len(s.chats))
+	for chatID, fields := range s.chats {
+		entries = append(entries, Entry{ChatID: chatID, Fields: jsoncopy.Map(fields)})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ChatID < entries[j].ChatID })
+	return entries
+}
+
+// Restore replaces the store contents with the given entries.
+func (s *Store) Restore(entries []Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[string]interface{}, len(entries))
+	for _, e := range entries {
+		s.chats[e.ChatID] = jsoncopy.Map(e.Fields)
+	}
+}
+
+// Clear forgets all chats.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[string]interface{})
+}
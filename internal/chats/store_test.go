@@ -0,0 +1,104 @@
+// internal/chats/store_test.go
+package chats
+
+import (
+	"testing"
+)
+
+func TestStore_SetAndApply(t *testing.T) {
+	s := NewStore()
+	if !s.Set("42", "title", "Team") {
+		t.Fatal("expected the first title to change the chat")
+	}
+	if s.Set("42", "title", "Team") {
+		t.Error("expected the same title not to change the chat")
+	}
+	if !s.Set("42", "title", "Renamed") {
+		t.Error("expected a new title to change the chat")
+	}
+
+	chat := map[string]interface{}{"id": 42, "title": "generated", "sticker_set_name": "generated"}
+	s.Unset("42", "sticker_set_name")
+	s.Apply("42", chat)
+	if chat["title"] != "Renamed" {
+		t.Errorf("expected the stored title, got %v", chat["title"])
+	}
+	if _, ok := chat["sticker_set_name"]; ok {
+		t.Error("expected the unset sticker set to be removed")
+	}
+	if s.Unset("42", "sticker_set_name") {
+		t.Error("expected unsetting twice not to change the chat")
+	}
+}
+
+func TestStore_RestoreAndClear(t *testing.T) {
+	s := NewStore()
+	s.Set("@team", "description", "About")
+
+	restored := NewStore()
+	restored.Restore(s.List())
+	if v, ok := restored.Field("@team", "description"); !ok || v != "About" {
+		t.Errorf("expected the restored description, got %v", v)
+	}
+
+	restored.Clear()
+	if _, ok := restored.Get("@team"); ok {
+		t.Error("expected no chats after Clear")
+	}
+}
+
+func TestStore_Seed(t *testing.T) {
+	s := NewStore()
+	chatID, err := s.Seed(map[string]interface{}{"id": -1001234, "title": "Release Team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chatID != "-1001234" || !s.Seeded(chatID) {
+		t.Errorf("expected the chat to be seeded as -1001234, got %q", chatID)
+	}
+	if v, _ := s.Field(chatID, "type"); v != "group" {
+		t.Errorf("expected the type to be derived from the ID, got %v", v)
+	}
+	if _, err := s.Seed(map[string]interface{}{"id": 1000000000001, "type": "private"}); err != nil {
+		t.Errorf("expected an explicit type to be kept, got %v", err)
+	}
+
+	s.Set("42", "title", "Changed")
+	if s.Seeded("42") {
+		t.Error("expected chats changed by bots not to count as seeded")
+	}
+	for _, bad := range []map[string]interface{}{{"title": "no id"}, {"id": 1.5}, {"id": 1, "type": "room"}} {
+		if _, err := s.Seed(bad); err == nil {
+			t.Errorf("expected %v to be rejected", bad)
+		}
+	}
+
+	if !s.Delete(chatID) || s.Delete(chatID) {
+		t.Error("expected a seeded chat to be deleted once")
+	}
+}
+
+func TestStore_Type(t *testing.T) {
+	s := NewStore()
+	s.Seed(map[string]interface{}{"id": -1001234567890, "type": "channel"})
+	for chatID, want := range map[string]string{"-1001234567890": "channel", "-1009876543210": "supergroup", "-42": "group", "42": "private", "@news": ""} {
+		if got := s.Type(chatID); got != want {
+			t.Errorf("expected %s to be a %q chat, got %q", chatID, want, got)
+		}
+	}
+}
+
+func TestStore_Username(t *testing.T) {
+	s := NewStore()
+	s.Seed(map[string]interface{}{"id": -1001234567890, "type": "channel", "username": "News"})
+	s.Set("@other", "username", "other")
+
+	if chatID, ok := s.Username("news"); !ok || chatID != "-1001234567890" {
+		t.Errorf("expected the channel's ID, got %q %v", chatID, ok)
+	}
+	for _, username := range []string{"other", "unknown"} {
+		if chatID, ok := s.Username(username); ok {
+			t.Errorf("expected %s not to resolve, got %q", username, chatID)
+		}
+	}
+}
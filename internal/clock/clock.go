@@ -0,0 +1,105 @@
+// Package clock provides the mock server's notion of "now". Time-dependent
+// behavior reads the time from a Clock rather than the system clock, so it
+// can be shifted in tests without sleeping.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the system clock shifted by an adjustable offset, or a time
+// that stands still while the clock is frozen. A nil *Clock reports the
+// system time.
+type Clock struct {
+	mu     sync.RWMutex
+	offset time.Duration
+	frozen bool
+	at     time.Time        // The time while frozen
+	start  time.Time        // Where Reset returns a clock created stopped
+	now    func() time.Time // System time source, replaceable in tests
+}
+
+// New creates a clock that starts at the system time.
+func New() *Clock {
+	return &Clock{now: time.Now}
+}
+
+// NewAt creates a clock frozen at t: time only passes when the clock is
+// set, advanced, or unfrozen, and Reset returns it to t.
+func NewAt(t time.Time) *Clock {
+	return &Clock{now: time.Now, frozen: true, at: t, start: t}
+}
+
+// Now returns the current mock time.
+func (c *Clock) Now() time.Time {
+	if c == nil {
+		return time.Now()
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.frozen {
+		return c.at
+	}
+	return c.now().Add(c.offset)
+}
+
+// Frozen reports whether time stands still.
+func (c *Clock) Frozen() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.frozen
+}
+
+// Set moves the clock to t. Unless the clock is frozen, time keeps passing
+// from there.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		c.at = t
+		return
+	}
+	c.offset = t.Sub(c.now())
+}
+
+// Advance moves the clock forward by d (or backward, if d is negative).
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		c.at = c.at.Add(d)
+		return
+	}
+	c.offset += d
+}
+
+// Freeze stops the clock at the current mock time.
+func (c *Clock) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.frozen {
+		c.at = c.now().Add(c.offset)
+		c.frozen = true
+	}
+}
+
+// Unfreeze lets time pass again from where the clock stands.
+func (c *Clock) Unfreeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frozen {
+		c.offset = c.at.Sub(c.now())
+		c.frozen = false
+	}
+}
+
+// Reset returns the clock to the system time, or to its start, frozen, if
+// it was created stopped.
+func (c *Clock) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offset = 0
+	c.frozen = !c.start.IsZero()
+	c.at = c.start
+}
@@ -0,0 +1,94 @@
+// internal/clock/clock_test.go
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClock(t *testing.T) {
+	system := time.Unix(1700000000, 0)
+	c := New()
+	c.now = func() time.Time { return system }
+
+	if !c.Now().Equal(system) {
+		t.Errorf("got %v, want system time %v", c.Now(), system)
+	}
+
+	c.Advance(5 * time.Second)
+	if got := c.Now().Sub(system); got != 5*time.Second {
+		t.Errorf("got offset %v after Advance, want 5s", got)
+	}
+
+	// Time keeps passing after Set
+	target := time.Unix(1800000000, 0)
+	c.Set(target)
+	system = system.Add(time.Minute)
+	if got := c.Now(); !got.Equal(target.Add(time.Minute)) {
+		t.Errorf("got %v, want %v", got, target.Add(time.Minute))
+	}
+
+	c.Reset()
+	if !c.Now().Equal(system) {
+		t.Errorf("got %v after Reset, want %v", c.Now(), system)
+	}
+}
+
+func TestClock_Freeze(t *testing.T) {
+	system := time.Unix(1700000000, 0)
+	c := New()
+	c.now = func() time.Time { return system }
+	c.Advance(time.Hour)
+
+	c.Freeze()
+	frozen := system.Add(time.Hour)
+	system = system.Add(time.Minute)
+	if !c.Frozen() || !c.Now().Equal(frozen) {
+		t.Errorf("got %v (frozen %v), want %v while frozen", c.Now(), c.Frozen(), frozen)
+	}
+
+	c.Advance(time.Second)
+	target := time.Unix(1800000000, 0)
+	c.Set(target)
+	if !c.Now().Equal(target) {
+		t.Errorf("got %v after Set while frozen, want %v", c.Now(), target)
+	}
+
+	// Time passes again from where the clock stood
+	c.Unfreeze()
+	system = system.Add(time.Minute)
+	if got := c.Now(); c.Frozen() || !got.Equal(target.Add(time.Minute)) {
+		t.Errorf("got %v after Unfreeze, want %v", got, target.Add(time.Minute))
+	}
+
+	c.Reset()
+	if c.Frozen() || !c.Now().Equal(system) {
+		t.Errorf("got %v after Reset, want the running system time %v", c.Now(), system)
+	}
+}
+
+func TestClock_Stopped(t *testing.T) {
+	start := time.Unix(1704067200, 0)
+	c := NewAt(start)
+	time.Sleep(time.Millisecond)
+	if !c.Now().Equal(start) {
+		t.Errorf("got %v, want the stopped time %v", c.Now(), start)
+	}
+
+	c.Advance(time.Hour)
+	if got := c.Now(); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("got %v after Advance, want %v", got, start.Add(time.Hour))
+	}
+
+	c.Reset()
+	if !c.Now().Equal(start) {
+		t.Errorf("got %v after Reset, want %v", c.Now(), start)
+	}
+}
+
+func TestClock_Nil(t *testing.T) {
+	var c *Clock
+	if d := time.Since(c.Now()); d < 0 || d > time.Second {
+		t.Errorf("expected nil clock to report system time, off by %v", d)
+	}
+}
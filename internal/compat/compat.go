@@ -0,0 +1,287 @@
+// Package compat perturbs generated responses the way newer Bot API
+// versions can: optional fields go missing and unknown fields appear.
+// Telegram expects clients to tolerate both, and this package lets tests
+// check that their deserializers do.
+package compat
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// ExtraFieldPrefix starts the name of every unknown field added to a
+// response, so that it can't clash with a real field.
+const ExtraFieldPrefix = "tg_mock_future_"
+
+// Config selects how responses are perturbed.
+type Config struct {
+	// OmitProbability is the chance that each optional field is dropped.
+	OmitProbability float64 `json:"omit_probability"`
+	// ExtraProbability is the chance that each object gains an unknown
+	// field.
+	ExtraProbability float64 `json:"extra_probability"`
+	// Omit lists optional fields that are always dropped, either as
+	// "Type.field" or as a bare field name matching any type.
+	Omit []string `json:"omit,omitempty"`
+	// Methods restricts perturbation to these methods (empty = all).
+	Methods []string `json:"methods,omitempty"`
+	// Seed makes the random choices reproducible (0 = random).
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate checks the probabilities and the omitted fields.
+func (c *Config) Validate() error {
+	if c.OmitProbability < 0 || c.OmitProbability > 1 {
+		return fmt.Errorf("omit_probability must be between 0 and 1")
+	}
+	if c.ExtraProbability < 0 || c.ExtraProbability > 1 {
+		return fmt.Errorf("extra_probability must be between 0 and 1")
+	}
+	for _, name := range c.Omit {
+		typeName, field, qualified := strings.Cut(name, ".")
+		if !qualified {
+			continue
+		}
+		spec, ok := gen.Types[typeName]
+		if !ok {
+			return fmt.Errorf("unknown type %q", typeName)
+		}
+		f, ok := findField(spec, field)
+		if !ok {
+			return fmt.Errorf("type %s has no field %q", typeName, field)
+		}
+		if f.Required {
+			return fmt.Errorf("%s is required and can't be omitted", name)
+		}
+	}
+	for _, method := range c.Methods {
+		if _, ok := gen.Methods[method]; !ok {
+			return fmt.Errorf("unknown method %q", method)
+		}
+	}
+	return nil
+}
+
+// Stats counts the changes made to responses.
+type Stats struct {
+	Responses int `json:"responses"`
+	Omitted   int `json:"omitted"`
+	Added     int `json:"added"`
+}
+
+// Mutator perturbs responses according to its configuration. It is
+// disabled until configured.
+type Mutator struct {
+	mu      sync.Mutex
+	cfg     *Config
+	rng     *rand.Rand
+	omit    map[string]bool
+	methods map[string]bool
+	stats   Stats
+}
+
+// NewMutator creates a disabled mutator.
+func NewMutator() *Mutator {
+	return &Mutator{}
+}
+
+// Set enables the mutator with cfg and resets its statistics.
+func (m *Mutator) Set(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = &cfg
+	m.rng = rand.New(rand.NewSource(seed))
+	m.omit = make(map[string]bool, len(cfg.Omit))
+	for _, name := range cfg.Omit {
+		m.omit[name] = true
+	}
+	m.methods = make(map[string]bool, len(cfg.Methods))
+	for _, method := range cfg.Methods {
+		m.methods[method] = true
+	}
+	m.stats = Stats{}
+	return nil
+}
+
+// Get returns the configuration, if the mutator is enabled, and its
+// statistics.
+func (m *Mutator) Get() (*Config, Stats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg == nil {
+		return nil, m.stats
+	}
+	cfg := *m.cfg
+	return &cfg, m.stats
+}
+
+// Disable stops perturbing responses and resets the statistics.
+func (m *Mutator) Disable() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cfg = nil
+	m.stats = Stats{}
+}
+
+// Apply returns the result of a method with its objects perturbed. The
+// result itself is not modified. returns are the method's possible
+// return types.
+func (m *Mutator) Apply(method string, returns []string, result interface{}) interface{} {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cfg == nil || (len(m.methods) > 0 && !m.methods[method]) {
+		return result
+	}
+	m.stats.Responses++
+	return m.walk(matchType(returns, result), result)
+}
+
+// walk perturbs a value of the named type, copying every object it
+// passes through.
+func (m *Mutator) walk(typeName string, v interface{}) interface{} {
+	switch value := v.(type) {
+	case []interface{}:
+		elem, _ := strings.CutPrefix(typeName, "Array of ")
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = m.walk(elem, item)
+		}
+		return items
+	case []map[string]interface{}:
+		elem, _ := strings.CutPrefix(typeName, "Array of ")
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = m.walkObject(elem, item)
+		}
+		return items
+	case map[string]interface{}:
+		return m.walkObject(typeName, value)
+	}
+	return v
+}
+
+func (m *Mutator) walkObject(typeName string, obj map[string]interface{}) map[string]interface{} {
+	spec, known := resolve(typeName, obj)
+	// Visit keys in order so that seeded runs make the same choices
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make(map[string]interface{}, len(obj)+1)
+	for _, key := range keys {
+		value := obj[key]
+		field, ok := findField(spec, key)
+		if !known || !ok {
+			result[key] = value
+			continue
+		}
+		if !field.Required && m.shouldOmit(spec.Name, key) {
+			m.stats.Omitted++
+			continue
+		}
+		result[key] = m.walk(matchType(field.Types, value), value)
+	}
+
+	if m.cfg.ExtraProbability > 0 && m.rng.Float64() < m.cfg.ExtraProbability {
+		name, value := m.extraField()
+		result[name] = value
+		m.stats.Added++
+	}
+	return result
+}
+
+func (m *Mutator) shouldOmit(typeName, field string) bool {
+	if m.omit[typeName+"."+field] || m.omit[field] {
+		return true
+	}
+	return m.cfg.OmitProbability > 0 && m.rng.Float64() < m.cfg.OmitProbability
+}
+
+// extraField returns an unknown field of a random JSON type.
+func (m *Mutator) extraField() (string, interface{}) {
+	switch m.rng.Intn(4) {
+	case 0:
+		return ExtraFieldPrefix + "flag", true
+	case 1:
+		return ExtraFieldPrefix + "count", m.rng.Int63n(1 << 40)
+	case 2:
+		return ExtraFieldPrefix + "text", "added in a later Bot API version"
+	default:
+		return ExtraFieldPrefix + "object", map[string]interface{}{"id": m.rng.Int63n(1 << 40)}
+	}
+}
+
+// matchType picks the type among types that fits the shape of v.
+func matchType(types []string, v interface{}) string {
+	for _, t := range types {
+		switch v.(type) {
+		case []interface{}, []map[string]interface{}:
+			if strings.HasPrefix(t, "Array of ") {
+				return t
+			}
+		case map[string]interface{}:
+			if _, ok := gen.Types[t]; ok {
+				return t
+			}
+		}
+	}
+	if len(types) > 0 {
+		return types[0]
+	}
+	return ""
+}
+
+// resolve returns the spec of an object of the named type. Union types
+// resolve to the subtype whose fields fit the object best.
+func resolve(typeName string, obj map[string]interface{}) (gen.TypeSpec, bool) {
+	spec, ok := gen.Types[typeName]
+	if !ok || len(spec.Subtypes) == 0 {
+		return spec, ok
+	}
+
+	best, bestScore := gen.TypeSpec{}, -1
+	for _, name := range spec.Subtypes {
+		sub, ok := gen.Types[name]
+		if !ok {
+			continue
+		}
+		score := 0
+		for _, f := range sub.Fields {
+			if _, present := obj[f.Name]; present {
+				score++
+			} else if f.Required {
+				score = -1
+				break
+			}
+		}
+		if score > bestScore {
+			best, bestScore = sub, score
+		}
+	}
+	return best, bestScore >= 0
+}
+
+func findField(spec gen.TypeSpec, name string) (gen.FieldSpec, bool) {
+	for _, f := range spec.Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return gen.FieldSpec{}, false
+}
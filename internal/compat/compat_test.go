@@ -0,0 +1,154 @@
+// internal/compat/compat_test.go
+package compat
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func message() map[string]interface{} {
+	return map[string]interface{}{
+		"message_id": int64(1),
+		"date":       int64(1700000000),
+		"text":       "hello",
+		"chat":       map[string]interface{}{"id": int64(42), "type": "private", "username": "ann"},
+		"from":       map[string]interface{}{"id": int64(7), "is_bot": false, "first_name": "Ann", "last_name": "Lee"},
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{"empty", Config{}, true},
+		{"probabilities", Config{OmitProbability: 0.5, ExtraProbability: 1}, true},
+		{"qualified field", Config{Omit: []string{"User.last_name"}}, true},
+		{"bare field", Config{Omit: []string{"username"}}, true},
+		{"omit probability too high", Config{OmitProbability: 1.5}, false},
+		{"negative extra probability", Config{ExtraProbability: -0.1}, false},
+		{"unknown type", Config{Omit: []string{"Nope.field"}}, false},
+		{"unknown field", Config{Omit: []string{"User.nope"}}, false},
+		{"required field", Config{Omit: []string{"User.id"}}, false},
+		{"unknown method", Config{Methods: []string{"nope"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); (err == nil) != tt.ok {
+				t.Errorf("Validate() = %v, want ok=%v", err, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMutator_DisabledByDefault(t *testing.T) {
+	m := NewMutator()
+	msg := message()
+	if got := m.Apply("sendMessage", []string{"Message"}, msg); !reflect.DeepEqual(got, msg) {
+		t.Errorf("expected result to be unchanged, got %v", got)
+	}
+}
+
+func TestMutator_OmitsOnlyOptionalFields(t *testing.T) {
+	m := NewMutator()
+	m.Set(Config{OmitProbability: 1, Seed: 1})
+
+	msg := message()
+	got := m.Apply("sendMessage", []string{"Message"}, msg).(map[string]interface{})
+
+	for _, field := range []string{"message_id", "date", "chat"} {
+		if _, ok := got[field]; !ok {
+			t.Errorf("required field %s was omitted", field)
+		}
+	}
+	for _, field := range []string{"text", "from"} {
+		if _, ok := got[field]; ok {
+			t.Errorf("optional field %s was kept", field)
+		}
+	}
+	chat := got["chat"].(map[string]interface{})
+	if _, ok := chat["username"]; ok || chat["id"] != int64(42) {
+		t.Errorf("expected nested optional fields to be omitted, got %v", chat)
+	}
+
+	if _, ok := msg["text"]; !ok {
+		t.Error("the original result must not be modified")
+	}
+
+	_, stats := m.Get()
+	if stats.Responses != 1 || stats.Omitted != 3 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestMutator_OmitList(t *testing.T) {
+	m := NewMutator()
+	m.Set(Config{Omit: []string{"User.last_name", "username"}})
+
+	got := m.Apply("sendMessage", []string{"Message"}, message()).(map[string]interface{})
+	from := got["from"].(map[string]interface{})
+	chat := got["chat"].(map[string]interface{})
+	if _, ok := from["last_name"]; ok {
+		t.Error("expected User.last_name to be omitted")
+	}
+	if _, ok := chat["username"]; ok {
+		t.Error("expected username to be omitted from any type")
+	}
+	if got["text"] != "hello" {
+		t.Error("expected other fields to be kept")
+	}
+}
+
+func TestMutator_ExtraFields(t *testing.T) {
+	m := NewMutator()
+	m.Set(Config{ExtraProbability: 1, Seed: 1})
+
+	got := m.Apply("getChatAdministrators", []string{"Array of ChatMember"}, []interface{}{
+		map[string]interface{}{
+			"status":       "creator",
+			"user":         map[string]interface{}{"id": int64(7), "is_bot": false, "first_name": "Ann"},
+			"is_anonymous": false,
+		},
+	}).([]interface{})
+
+	member := got[0].(map[string]interface{})
+	if countExtra(member) != 1 || countExtra(member["user"].(map[string]interface{})) != 1 {
+		t.Errorf("expected an unknown field in every object, got %v", member)
+	}
+}
+
+func TestMutator_Methods(t *testing.T) {
+	m := NewMutator()
+	m.Set(Config{OmitProbability: 1, Methods: []string{"getMe"}})
+
+	msg := message()
+	if got := m.Apply("sendMessage", []string{"Message"}, msg); !reflect.DeepEqual(got, msg) {
+		t.Errorf("expected other methods to be unchanged, got %v", got)
+	}
+}
+
+func TestMutator_Seeded(t *testing.T) {
+	a, b := NewMutator(), NewMutator()
+	a.Set(Config{OmitProbability: 0.5, ExtraProbability: 0.5, Seed: 7})
+	b.Set(Config{OmitProbability: 0.5, ExtraProbability: 0.5, Seed: 7})
+
+	for i := 0; i < 5; i++ {
+		x := a.Apply("sendMessage", []string{"Message"}, message())
+		y := b.Apply("sendMessage", []string{"Message"}, message())
+		if !reflect.DeepEqual(x, y) {
+			t.Fatalf("expected equal seeds to perturb alike, got %v and %v", x, y)
+		}
+	}
+}
+
+func countExtra(obj map[string]interface{}) int {
+	n := 0
+	for key := range obj {
+		if strings.HasPrefix(key, ExtraFieldPrefix) {
+			n++
+		}
+	}
+	return n
+}
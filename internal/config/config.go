@@ -3,16 +3,30 @@ package config
 
 import (
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the main configuration structure for tg-mock
 type Config struct {
-	Server    ServerConfig            `yaml:"server"`
-	Storage   StorageConfig           `yaml:"storage"`
-	Tokens    map[string]TokenConfig  `yaml:"tokens"`
-	Scenarios []ScenarioConfig        `yaml:"scenarios"`
+	Server      ServerConfig             `yaml:"server"`
+	Storage     StorageConfig            `yaml:"storage"`
+	Tokens      map[string]TokenConfig   `yaml:"tokens"`
+	Scenarios   []ScenarioConfig         `yaml:"scenarios"`
+	Memory      MemoryConfig             `yaml:"memory"`
+	Personas    []PersonaConfig          `yaml:"personas"`
+	BotGroups   []BotGroupConfig         `yaml:"bot_groups"`
+	Compat      *CompatConfig            `yaml:"compat"`
+	Adversarial *AdversarialConfig       `yaml:"adversarial"`
+	Chaos       *ChaosConfig             `yaml:"chaos"`
+	Latency     *LatencyConfig           `yaml:"latency"`
+	FloodLimits *FloodLimitsConfig       `yaml:"flood_limits"`
+	Hooks       []HookConfig             `yaml:"hooks"`
+	Chats       []map[string]interface{} `yaml:"chats"`   // Seeded in every new session, as ChatFullInfo objects
+	Users       []map[string]interface{} `yaml:"users"`   // Seeded in every new session, as User objects
+	Updates     []map[string]interface{} `yaml:"updates"` // Queued in every new session at startup
+	Traffic     []TrafficConfig          `yaml:"traffic"`
 }
 
 // ServerConfig holds server-related configuration
@@ -21,11 +35,39 @@ type ServerConfig struct {
 	Verbose   bool  `yaml:"verbose"`
 	Strict    bool  `yaml:"strict"`
 	FakerSeed int64 `yaml:"faker_seed"` // Seed for faker (0 = random, >0 = fixed for determinism)
+
+	Deterministic bool `yaml:"deterministic"` // Freeze the clock at a time derived from the faker seed, for byte-stable responses
+
+	FakerOptionalFields string   `yaml:"faker_optional_fields"` // How many optional fields generated objects have: never, sparse, default, or always
+	FakerLocales        []string `yaml:"faker_locales"`         // Languages of generated names, titles, and texts, e.g. ["ru", "ja"] (empty = English)
+
+	FakerDatasets map[string]FakerDatasetConfig `yaml:"faker_datasets"` // Word lists replacing or extending the faker's, by name, e.g. first_names
+
+	ControlToken string `yaml:"control_token"` // Required on control API requests when set
+	OTLPEndpoint string `yaml:"otlp_endpoint"` // OTLP/HTTP collector for trace export
+
+	CORSOrigins []string `yaml:"cors_origins"` // Browser origins allowed to call the control API ("*" = any)
+	RecordFile  string   `yaml:"record_file"`  // JSONL file recorded requests are appended to
+	APIVersion  string   `yaml:"api_version"`  // Simulated Bot API version, e.g. "7.0" (empty = latest)
+
+	EnforceRetryAfter bool `yaml:"enforce_retry_after"` // Reject calls made before a 429's retry_after elapsed
+
+	CallbackQueryTimeout time.Duration `yaml:"callback_query_timeout"` // How long callback queries can be answered (0 = 15s)
+	MessageDeleteWindow  time.Duration `yaml:"message_delete_window"`  // How long after they were sent messages can be deleted (0 = 48h)
+
+	ValidateResults string `yaml:"validate_results"` // Check generated results against the spec: off, log, or fail
 }
 
 // StorageConfig holds file storage configuration
 type StorageConfig struct {
-	Dir string `yaml:"dir"`
+	Dir         string        `yaml:"dir"`
+	FilePathTTL time.Duration `yaml:"file_path_ttl"` // How long getFile paths stay downloadable (0 = 1h)
+}
+
+// MemoryConfig holds memory limits for the in-memory stores
+type MemoryConfig struct {
+	Policy string            `yaml:"policy"` // evict, reject, or log (default evict)
+	Limits map[string]string `yaml:"limits"` // Component name to size, e.g. recorder: 64MB
 }
 
 // WebhookConfig holds webhook configuration for a bot token
@@ -39,9 +81,40 @@ type WebhookConfig struct {
 
 // TokenConfig holds configuration for a bot token
 type TokenConfig struct {
-	Status  string         `yaml:"status"`
-	BotName string         `yaml:"bot_name"`
-	Webhook *WebhookConfig `yaml:"webhook,omitempty"`
+	Status      string             `yaml:"status"`
+	BotName     string             `yaml:"bot_name"`
+	Profile     *BotProfileConfig  `yaml:"profile,omitempty"`
+	Webhook     *WebhookConfig     `yaml:"webhook,omitempty"`
+	Budget      *BudgetConfig      `yaml:"budget,omitempty"`
+	Concurrency *ConcurrencyConfig `yaml:"concurrency,omitempty"`
+}
+
+// BotProfileConfig describes the bot getMe returns for a token
+type BotProfileConfig struct {
+	FirstName               string `yaml:"first_name,omitempty"`
+	LastName                string `yaml:"last_name,omitempty"`
+	Username                string `yaml:"username,omitempty"`
+	CanJoinGroups           *bool  `yaml:"can_join_groups,omitempty"` // Defaults to true
+	CanReadAllGroupMessages bool   `yaml:"can_read_all_group_messages,omitempty"`
+	SupportsInlineQueries   bool   `yaml:"supports_inline_queries,omitempty"`
+	CanConnectToBusiness    bool   `yaml:"can_connect_to_business,omitempty"`
+	HasMainWebApp           bool   `yaml:"has_main_web_app,omitempty"`
+}
+
+// BudgetConfig makes a token fail after a number of successful calls
+type BudgetConfig struct {
+	Calls       int    `yaml:"calls"`
+	ErrorCode   int    `yaml:"error_code,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	RetryAfter  int    `yaml:"retry_after,omitempty"`
+}
+
+// ConcurrencyConfig limits the number of requests a token may have in flight
+type ConcurrencyConfig struct {
+	Limit        int           `yaml:"limit"`
+	Mode         string        `yaml:"mode,omitempty"`          // queue (default) or reject
+	QueueTimeout time.Duration `yaml:"queue_timeout,omitempty"` // How long queued requests wait (0 = until the client gives up)
+	RetryAfter   int           `yaml:"retry_after,omitempty"`
 }
 
 // ScenarioConfig defines a test scenario for simulating specific responses
@@ -51,13 +124,127 @@ type ScenarioConfig struct {
 	Times        int                    `yaml:"times"`
 	Response     ResponseConfig         `yaml:"response"`                // For error responses
 	ResponseData map[string]interface{} `yaml:"response_data,omitempty"` // For success response overrides
+	Script       string                 `yaml:"script,omitempty"`        // Lua script computing the response
+}
+
+// PersonaConfig attaches an auto-responder to a chat
+type PersonaConfig struct {
+	ChatID string                 `yaml:"chat_id"`
+	User   map[string]interface{} `yaml:"user,omitempty"` // Sender of the persona's updates (generated if empty)
+	Rules  []PersonaRuleConfig    `yaml:"rules"`
+}
+
+// PersonaRuleConfig is one reaction of a persona to messages from the bot
+type PersonaRuleConfig struct {
+	Action string `yaml:"action"`           // echo, reply, press_button, or send_photo
+	Text   string `yaml:"text,omitempty"`   // Reply text or photo caption
+	Button int    `yaml:"button,omitempty"` // 1-based callback button to press
+	Every  int    `yaml:"every,omitempty"`  // Fire on every Nth bot message (default 1)
+}
+
+// BotGroupConfig defines a group chat shared by several bots
+type BotGroupConfig struct {
+	ChatID      string                 `yaml:"chat_id"`
+	Bots        []BotGroupMemberConfig `yaml:"bots"`
+	BotsSeeBots bool                   `yaml:"bots_see_bots"` // Relay messages between bots (Telegram never does)
+}
+
+// BotGroupMemberConfig is a bot taking part in a group
+type BotGroupMemberConfig struct {
+	Token   string `yaml:"token"`
+	Session string `yaml:"session,omitempty"` // Session whose update queue the bot polls
+}
+
+// FakerDatasetConfig replaces or extends one of the faker's word lists
+type FakerDatasetConfig struct {
+	Values []string `yaml:"values"`
+	Extend bool     `yaml:"extend"` // Add the values to the built-in list instead of replacing it
+}
+
+// CompatConfig perturbs responses to test client tolerance of API changes
+type CompatConfig struct {
+	OmitProbability  float64  `yaml:"omit_probability"`  // Chance each optional field is dropped
+	ExtraProbability float64  `yaml:"extra_probability"` // Chance each object gains an unknown field
+	Omit             []string `yaml:"omit"`              // Fields always dropped ("Type.field" or "field")
+	Methods          []string `yaml:"methods"`           // Only perturb these methods (empty = all)
+	Seed             int64    `yaml:"seed"`              // Seed for reproducible choices (0 = random)
+}
+
+// AdversarialConfig makes responses hard to deserialize to harden clients
+type AdversarialConfig struct {
+	Probability float64  `yaml:"probability"` // Chance each value is made adversarial (0 = every one)
+	Kinds       []string `yaml:"kinds"`       // strings, integers, optional_fields, nesting (empty = all)
+	Depth       int      `yaml:"depth"`       // How deep entities and replies nest (0 = 10)
+	Methods     []string `yaml:"methods"`     // Only change these methods (empty = all)
+	Seed        int64    `yaml:"seed"`        // Seed for reproducible choices (0 = random)
+}
+
+// ChaosConfig fails a random share of calls
+type ChaosConfig struct {
+	Failures []ChaosFailureConfig `yaml:"failures"`
+	Seed     int64                `yaml:"seed"` // Seed for reproducible choices (0 = random)
+}
+
+// ChaosFailureConfig is an error returned to a share of calls
+type ChaosFailureConfig struct {
+	Probability   float64  `yaml:"probability"` // Chance each call fails with this error
+	ErrorCode     int      `yaml:"error_code"`
+	Description   string   `yaml:"description,omitempty"`
+	RetryAfterMin int      `yaml:"retry_after_min,omitempty"` // Bounds of the random retry_after
+	RetryAfterMax int      `yaml:"retry_after_max,omitempty"`
+	Methods       []string `yaml:"methods,omitempty"` // Only fail these methods (empty = all)
+}
+
+// FloodLimitsConfig enforces Telegram's flood limits on sent messages
+type FloodLimitsConfig struct {
+	PerSecond         int `yaml:"per_second"`           // Messages per second overall (0 = 30)
+	PerChatPerSecond  int `yaml:"per_chat_per_second"`  // Messages per second to one chat (0 = 1)
+	PerGroupPerMinute int `yaml:"per_group_per_minute"` // Messages per minute to one group (0 = 20)
+}
+
+// LatencyConfig delays every Bot API response
+type LatencyConfig struct {
+	Fixed     time.Duration                   `yaml:"fixed"`      // Base delay of every response
+	JitterMin time.Duration                   `yaml:"jitter_min"` // Bounds of the random jitter added to fixed
+	JitterMax time.Duration                   `yaml:"jitter_max"`
+	Profile   *LatencyProfileConfig           `yaml:"profile,omitempty"` // Percentile profile of all methods
+	Methods   map[string]LatencyProfileConfig `yaml:"methods,omitempty"` // Percentile profiles of single methods
+	Seed      int64                           `yaml:"seed"`              // Seed for reproducible delays (0 = random)
+}
+
+// LatencyProfileConfig describes a latency distribution by its percentiles
+type LatencyProfileConfig struct {
+	P50 time.Duration `yaml:"p50"`
+	P90 time.Duration `yaml:"p90"`
+	P99 time.Duration `yaml:"p99"`
+}
+
+// HookConfig registers an HTTP endpoint that intercepts Bot API calls
+type HookConfig struct {
+	Name    string        `yaml:"name,omitempty"`
+	URL     string        `yaml:"url"`
+	Methods []string      `yaml:"methods,omitempty"` // Only intercept these methods (empty = all)
+	Phases  []string      `yaml:"phases,omitempty"`  // before, after, or both (default both)
+	Timeout time.Duration `yaml:"timeout,omitempty"` // How long to wait for the endpoint (0 = 5s)
+}
+
+// TrafficConfig sends an update at regular intervals
+type TrafficConfig struct {
+	Every   time.Duration          `yaml:"every"`
+	Count   int                    `yaml:"count,omitempty"`   // Stop after this many updates (0 = never)
+	Token   string                 `yaml:"token,omitempty"`   // Bot whose webhook receives the updates, if it has one
+	Session string                 `yaml:"session,omitempty"` // Session to deliver to (empty = default)
+	Update  map[string]interface{} `yaml:"update"`
 }
 
 // ResponseConfig defines the response to return for a scenario
 type ResponseConfig struct {
-	ErrorCode   int    `yaml:"error_code"`
-	Description string `yaml:"description"`
-	RetryAfter  int    `yaml:"retry_after"`
+	ErrorCode       int                    `yaml:"error_code"`
+	Description     string                 `yaml:"description"`
+	RetryAfter      int                    `yaml:"retry_after"`
+	MigrateToChatID int64                  `yaml:"migrate_to_chat_id,omitempty"` // Supergroup a group was upgraded to
+	Parameters      map[string]interface{} `yaml:"parameters,omitempty"`         // Further response parameters, custom ones included
+	HTML            bool                   `yaml:"html,omitempty"`               // Send an HTML error page instead of JSON
 }
 
 // DefaultConfig returns a Config with sensible defaults
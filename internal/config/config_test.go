@@ -4,6 +4,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -160,6 +161,113 @@ storage:
 	}
 }
 
+func TestLoadConfigWithTokenBudget(t *testing.T) {
+	yaml := `
+tokens:
+  "123:abc":
+    status: active
+    budget:
+      calls: 10
+      error_code: 429
+      description: "Too Many Requests: quota exceeded"
+`
+
+	f, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(yaml)
+	f.Close()
+
+	cfg, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	budget := cfg.Tokens["123:abc"].Budget
+	if budget == nil {
+		t.Fatal("expected budget to be loaded")
+	}
+	if budget.Calls != 10 {
+		t.Errorf("calls = %d, want 10", budget.Calls)
+	}
+	if budget.ErrorCode != 429 {
+		t.Errorf("error_code = %d, want 429", budget.ErrorCode)
+	}
+}
+
+func TestLoadConfigWithTokenConcurrency(t *testing.T) {
+	yaml := `
+tokens:
+  "123:abc":
+    concurrency:
+      limit: 4
+      mode: reject
+      queue_timeout: 2s
+`
+
+	f, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(yaml)
+	f.Close()
+
+	cfg, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	c := cfg.Tokens["123:abc"].Concurrency
+	if c == nil {
+		t.Fatal("expected concurrency limit to be loaded")
+	}
+	if c.Limit != 4 || c.Mode != "reject" {
+		t.Errorf("unexpected concurrency limit %+v", c)
+	}
+	if c.QueueTimeout != 2*time.Second {
+		t.Errorf("queue_timeout = %v, want 2s", c.QueueTimeout)
+	}
+}
+
+func TestLoadConfigWithMemoryLimits(t *testing.T) {
+	yaml := `
+memory:
+  policy: reject
+  limits:
+    recorder: 64MB
+    queue: 1048576
+`
+
+	f, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(yaml)
+	f.Close()
+
+	cfg, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if cfg.Memory.Policy != "reject" {
+		t.Errorf("policy = %q, want reject", cfg.Memory.Policy)
+	}
+	if cfg.Memory.Limits["recorder"] != "64MB" {
+		t.Errorf("recorder limit = %q, want 64MB", cfg.Memory.Limits["recorder"])
+	}
+	if cfg.Memory.Limits["queue"] != "1048576" {
+		t.Errorf("queue limit = %q, want 1048576", cfg.Memory.Limits["queue"])
+	}
+}
+
 func TestLoadConfigFileNotFound(t *testing.T) {
 	_, err := Load("/nonexistent/config.yaml")
 	if err == nil {
@@ -183,3 +291,125 @@ func TestLoadConfigInvalidYAML(t *testing.T) {
 		t.Error("expected error for invalid YAML")
 	}
 }
+
+func TestLoadConfigWithPersonas(t *testing.T) {
+	yaml := `
+personas:
+  - chat_id: 42
+    user:
+      id: 7
+      first_name: Ann
+    rules:
+      - action: echo
+      - action: send_photo
+        every: 3
+`
+
+	f, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(yaml)
+	f.Close()
+
+	cfg, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if len(cfg.Personas) != 1 {
+		t.Fatalf("expected 1 persona, got %d", len(cfg.Personas))
+	}
+	p := cfg.Personas[0]
+	if p.ChatID != "42" {
+		t.Errorf("chat_id = %q, want 42", p.ChatID)
+	}
+	if p.User["first_name"] != "Ann" {
+		t.Errorf("unexpected user %v", p.User)
+	}
+	if len(p.Rules) != 2 || p.Rules[0].Action != "echo" || p.Rules[1].Every != 3 {
+		t.Errorf("unexpected rules %+v", p.Rules)
+	}
+}
+
+func TestLoadConfigWithUpdatesAndTraffic(t *testing.T) {
+	yaml := `
+updates:
+  - message:
+      chat: {id: 42, type: private}
+      text: /start
+traffic:
+  - every: 30s
+    count: 5
+    update:
+      message:
+        chat: {id: 42, type: private}
+        text: ping
+`
+
+	f, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(yaml)
+	f.Close()
+
+	cfg, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if len(cfg.Updates) != 1 {
+		t.Fatalf("expected 1 startup update, got %d", len(cfg.Updates))
+	}
+	if msg, _ := cfg.Updates[0]["message"].(map[string]interface{}); msg["text"] != "/start" {
+		t.Errorf("unexpected update %v", cfg.Updates[0])
+	}
+	if len(cfg.Traffic) != 1 {
+		t.Fatalf("expected 1 traffic entry, got %d", len(cfg.Traffic))
+	}
+	if tc := cfg.Traffic[0]; tc.Every != 30*time.Second || tc.Count != 5 || tc.Update["message"] == nil {
+		t.Errorf("unexpected traffic %+v", tc)
+	}
+}
+
+func TestLoadConfigWithFakerDatasets(t *testing.T) {
+	yaml := `
+server:
+  faker_locales: [ru, ja]
+  faker_datasets:
+    first_names:
+      values: [Ada, Grace]
+    domains:
+      values: [corp.example]
+      extend: true
+`
+
+	f, err := os.CreateTemp("", "config-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	f.WriteString(yaml)
+	f.Close()
+
+	cfg, err := Load(f.Name())
+	if err != nil {
+		t.Fatalf("failed to load config: %v", err)
+	}
+
+	if len(cfg.Server.FakerLocales) != 2 {
+		t.Errorf("expected 2 locales, got %v", cfg.Server.FakerLocales)
+	}
+	if d := cfg.Server.FakerDatasets["first_names"]; len(d.Values) != 2 || d.Extend {
+		t.Errorf("unexpected first_names dataset %+v", d)
+	}
+	if d := cfg.Server.FakerDatasets["domains"]; len(d.Values) != 1 || !d.Extend {
+		t.Errorf("unexpected domains dataset %+v", d)
+	}
+}
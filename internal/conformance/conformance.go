@@ -0,0 +1,598 @@
+// Package conformance certifies Bot API client libraries against the
+// mock. A suite of canonical calls, one per method, is handed to a driver
+// program that makes each call through the library under test. The calls
+// are then checked against the canonical requests, and the results the
+// library deserialized, which the driver writes back, against the
+// responses the mock sent.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/selffuzz"
+)
+
+// DefaultToken is the bot token of the suite when none is configured.
+const DefaultToken = "1000000000:conformance"
+
+// DefaultTimeout is how long the driver may run when no timeout is
+// configured.
+const DefaultTimeout = 5 * time.Minute
+
+// Statuses of a case.
+const (
+	// Passed cases were called as canonical and their results survived
+	// deserialization.
+	Passed = "passed"
+	// Failed cases were called, but not as canonical, or their results
+	// came back changed.
+	Failed = "failed"
+	// NotCalled cases were never called by the driver.
+	NotCalled = "not_called"
+	// Skipped cases aren't in the suite because the mock itself didn't
+	// answer their canonical call as the spec says.
+	Skipped = "skipped"
+)
+
+// Config controls a conformance run.
+type Config struct {
+	// BaseURL is the address of the mock the calls are made to.
+	BaseURL string
+	// Token is the bot token of the suite.
+	Token string
+	// Methods limits the suite to these methods (empty = all).
+	Methods []string
+	// Exec is the shell command that runs the driver. Without one, the
+	// run checks the mock's answers to the canonical calls only.
+	Exec string
+	// Dir is where the suite and the driver's results are written. If it
+	// is empty, a temporary directory is used and removed afterwards.
+	Dir string
+	// Timeout limits how long the driver may run (0 = DefaultTimeout).
+	Timeout time.Duration
+	// Output receives what the driver prints (nil = discarded).
+	Output io.Writer
+	// ControlToken authenticates calls to the control API on instances
+	// started with --control-token.
+	ControlToken string
+	// Client sends the requests. http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+// Suite is what the driver is given: a canonical call per method.
+type Suite struct {
+	SpecVersion string `json:"spec_version"`
+	Token       string `json:"token"`
+	Cases       []Case `json:"cases"`
+}
+
+// Case is a canonical call of a method.
+type Case struct {
+	ID     string `json:"id"`
+	Method string `json:"method"`
+	// BaseURL replaces https://api.telegram.org for the call, so that each
+	// case runs in a session of its own.
+	BaseURL string                 `json:"base_url"`
+	Params  map[string]interface{} `json:"params"`
+	// Result is what the mock answers the call with.
+	Result interface{} `json:"result"`
+}
+
+// CaseResult tells how a case went.
+type CaseResult struct {
+	ID       string   `json:"id"`
+	Method   string   `json:"method"`
+	Status   string   `json:"status"`
+	Problems []string `json:"problems,omitempty"`
+	// ResponseChecked is set when the driver wrote back the result it
+	// deserialized, so that the response was checked too.
+	ResponseChecked bool `json:"response_checked"`
+}
+
+// Report is the machine-readable outcome of a run.
+type Report struct {
+	SpecVersion string `json:"spec_version"`
+	// Client is the driver command, if there was one.
+	Client string `json:"client,omitempty"`
+	Passed bool   `json:"passed"`
+	// ClientError tells why the driver failed, if it did.
+	ClientError string         `json:"client_error,omitempty"`
+	Summary     map[string]int `json:"summary"`
+	Cases       []CaseResult   `json:"cases"`
+	Duration    float64        `json:"duration_seconds"`
+}
+
+// Run builds the suite, runs the driver, and checks its calls. It returns
+// an error only if the run itself could not be carried out.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Token == "" {
+		cfg.Token = DefaultToken
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	if cfg.Output == nil {
+		cfg.Output = io.Discard
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	methods := cfg.Methods
+	if len(methods) == 0 {
+		for name := range gen.Methods {
+			methods = append(methods, name)
+		}
+		sort.Strings(methods)
+	}
+	for _, name := range methods {
+		if _, ok := gen.Methods[name]; !ok {
+			return nil, fmt.Errorf("unknown method: %s", name)
+		}
+	}
+	if cfg.Dir == "" {
+		dir, err := os.MkdirTemp("", "tg-mock-conformance-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		cfg.Dir = dir
+	} else if err := os.MkdirAll(cfg.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	r := &runner{cfg: cfg}
+	defer r.cleanup()
+	report := &Report{SpecVersion: gen.Version, Client: cfg.Exec, Summary: map[string]int{}, Cases: []CaseResult{}}
+
+	// Canonical calls the mock answers as the spec says make the suite
+	suite := Suite{SpecVersion: gen.Version, Token: cfg.Token, Cases: []Case{}}
+	results := map[string]*CaseResult{}
+	for _, name := range methods {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		c := Case{ID: name, Method: name, BaseURL: r.sessionURL(name), Params: selffuzz.ValidParams(gen.Methods[name])}
+		r.sessions = append(r.sessions, "conformance-"+name, "conformance-reference-"+name)
+		result := &CaseResult{ID: c.ID, Method: c.Method, Status: Passed}
+		results[c.ID] = result
+		if err := r.setup(ctx, c); err != nil {
+			result.Problems = []string{err.Error()}
+		} else {
+			c.Result, result.Problems = r.reference(ctx, c)
+		}
+		if len(result.Problems) > 0 {
+			result.Status = Skipped
+			if cfg.Exec == "" {
+				result.Status = Failed
+			}
+			continue
+		}
+		suite.Cases = append(suite.Cases, c)
+	}
+	suitePath := filepath.Join(cfg.Dir, "suite.json")
+	if err := writeJSON(suitePath, suite); err != nil {
+		return nil, err
+	}
+
+	if cfg.Exec != "" {
+		resultsPath := filepath.Join(cfg.Dir, "results.json")
+		os.Remove(resultsPath)
+		if err := r.runDriver(ctx, suitePath, resultsPath); err != nil {
+			report.ClientError = err.Error()
+		}
+		echoed, err := readResults(resultsPath)
+		if err != nil {
+			report.ClientError = strings.TrimPrefix(report.ClientError+"; ", "; ") + err.Error()
+		}
+		for _, c := range suite.Cases {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			r.check(ctx, c, echoed, results[c.ID])
+		}
+	}
+
+	for _, name := range methods {
+		result := results[name]
+		report.Cases = append(report.Cases, *result)
+		report.Summary[result.Status]++
+	}
+	report.Passed = report.ClientError == "" && report.Summary[Failed] == 0 && report.Summary[NotCalled] == 0
+	report.Duration = time.Since(started).Seconds()
+	return report, nil
+}
+
+// runner makes the calls of a run.
+type runner struct {
+	cfg      Config
+	sessions []string
+}
+
+// sessionURL returns the Bot API root of the driver's session of a case.
+func (r *runner) sessionURL(id string) string {
+	return r.cfg.BaseURL + "/session/" + url.PathEscape("conformance-"+id)
+}
+
+// storedMessages are the methods whose canonical calls name messages
+// that must exist, by the parameter that names them.
+var storedMessages = map[string]string{
+	"copyMessage":     "message_id",
+	"copyMessages":    "message_ids",
+	"forwardMessage":  "message_id",
+	"forwardMessages": "message_ids",
+	"deleteMessages":  "message_ids",
+}
+
+// setup sends the messages a case names to the chat of its canonical
+// call, in both of its sessions. Fresh sessions number messages alike, so
+// the canonical message_id names the message in both.
+func (r *runner) setup(ctx context.Context, c Case) error {
+	param, ok := storedMessages[c.Method]
+	if !ok {
+		return nil
+	}
+	chatID, ok := c.Params["from_chat_id"]
+	if !ok {
+		chatID = c.Params["chat_id"]
+	}
+	var messageID interface{}
+	for _, base := range []string{r.referenceURL(c.ID), c.BaseURL} {
+		body := fmt.Sprintf(`{"chat_id":%s,"text":"conformance"}`, jsonString(chatID))
+		resp, err := r.cfg.Client.Post(base+"/bot"+r.cfg.Token+"/sendMessage", "application/json", strings.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("sending the message to %s: %w", c.Method, err)
+		}
+		var env struct {
+			Result struct {
+				MessageID interface{} `json:"message_id"`
+			} `json:"result"`
+		}
+		json.NewDecoder(resp.Body).Decode(&env)
+		resp.Body.Close()
+		messageID = env.Result.MessageID
+	}
+	if param == "message_ids" {
+		c.Params[param] = []interface{}{messageID}
+	} else {
+		c.Params[param] = messageID
+	}
+	return nil
+}
+
+// referenceURL returns the Bot API root of the runner's own session of a
+// case.
+func (r *runner) referenceURL(id string) string {
+	return r.cfg.BaseURL + "/session/" + url.PathEscape("conformance-reference-"+id)
+}
+
+// reference makes the canonical call of c itself, in a session of its
+// own, and returns the result, or what is wrong with the answer.
+func (r *runner) reference(ctx context.Context, c Case) (interface{}, []string) {
+	data, err := json.Marshal(c.Params)
+	if err != nil {
+		return nil, []string{"encoding canonical request: " + err.Error()}
+	}
+	endpoint := fmt.Sprintf("%s/bot%s/%s", r.referenceURL(c.ID), r.cfg.Token, c.Method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return nil, []string{"canonical request failed: " + err.Error()}
+	}
+	defer resp.Body.Close()
+
+	var env struct {
+		OK          bool        `json:"ok"`
+		Result      interface{} `json:"result"`
+		Description string      `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, []string{"canonical response is not JSON: " + err.Error()}
+	}
+	if resp.StatusCode != http.StatusOK || !env.OK {
+		return nil, []string{fmt.Sprintf("mock rejected the canonical request with %d: %s", resp.StatusCode, env.Description)}
+	}
+	for _, typ := range gen.Methods[c.Method].Returns {
+		if selffuzz.Matches(typ, env.Result) {
+			return env.Result, nil
+		}
+	}
+	return nil, []string{"canonical result does not match return type " + strings.Join(gen.Methods[c.Method].Returns, " or ")}
+}
+
+// runDriver runs the driver command with the suite, telling it where the
+// mock is and where to write its results.
+func (r *runner) runDriver(ctx context.Context, suitePath, resultsPath string) error {
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Timeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", r.cfg.Exec)
+	cmd.Env = append(os.Environ(),
+		"TG_MOCK_URL="+r.cfg.BaseURL,
+		"TG_MOCK_TOKEN="+r.cfg.Token,
+		"TG_MOCK_SUITE="+suitePath,
+		"TG_MOCK_RESULTS="+resultsPath,
+	)
+	cmd.Stdout = r.cfg.Output
+	cmd.Stderr = r.cfg.Output
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("driver didn't finish within %s", r.cfg.Timeout)
+	}
+	if err != nil {
+		return fmt.Errorf("driver failed: %w", err)
+	}
+	return nil
+}
+
+// readResults reads what the driver wrote back: the result of each case
+// as the library deserialized it, serialized again, by case ID. A driver
+// that writes nothing leaves the responses unchecked.
+func readResults(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var results map[string]interface{}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("invalid results file: %w", err)
+	}
+	return results, nil
+}
+
+// check compares the driver's call of c with the canonical one.
+func (r *runner) check(ctx context.Context, c Case, echoed map[string]interface{}, result *CaseResult) {
+	call, err := r.lastCall(ctx, c)
+	if err != nil {
+		result.Status = Failed
+		result.Problems = append(result.Problems, err.Error())
+		return
+	}
+	if call == nil {
+		result.Status = NotCalled
+		return
+	}
+	if call.IsError {
+		result.Problems = append(result.Problems, fmt.Sprintf("call failed with %d: %s", call.StatusCode, call.Response.Description))
+	}
+	result.Problems = append(result.Problems, paramProblems(gen.Methods[c.Method], c.Params, call.Params)...)
+	if got, ok := echoed[c.ID]; ok && !call.IsError {
+		result.ResponseChecked = true
+		result.Problems = append(result.Problems, resultProblems("result", call.Response.Result, got)...)
+	}
+	if len(result.Problems) > 0 {
+		result.Status = Failed
+	}
+}
+
+// call is a request the driver made, as the mock recorded it.
+type call struct {
+	Params     map[string]interface{} `json:"params"`
+	IsError    bool                   `json:"is_error"`
+	StatusCode int                    `json:"status_code"`
+	Response   struct {
+		Result      interface{} `json:"result"`
+		Description string      `json:"description"`
+	} `json:"response"`
+}
+
+// lastCall returns the latest call of the case's method in its session,
+// or nil if there is none.
+func (r *runner) lastCall(ctx context.Context, c Case) (*call, error) {
+	endpoint := c.BaseURL + "/__control/requests?limit=1&method=" + url.QueryEscape(c.Method)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.authorize(req)
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("listing calls: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("listing calls: status %d", resp.StatusCode)
+	}
+	var listing struct {
+		Requests []call `json:"requests"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&listing); err != nil {
+		return nil, fmt.Errorf("listing calls: %w", err)
+	}
+	if len(listing.Requests) == 0 {
+		return nil, nil
+	}
+	return &listing.Requests[0], nil
+}
+
+// paramProblems compares the parameters of a call with the canonical
+// ones. Optional parameters of the spec may be added; others may not.
+func paramProblems(spec gen.MethodSpec, want, got map[string]interface{}) []string {
+	var problems []string
+	names := make([]string, 0, len(want))
+	for name := range want {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v, ok := got[name]
+		switch {
+		case !ok:
+			problems = append(problems, "missing parameter "+name)
+		case !sameValue(v, want[name]):
+			problems = append(problems, fmt.Sprintf("parameter %s is %s, want %s", name, jsonString(v), jsonString(want[name])))
+		}
+	}
+	known := map[string]bool{}
+	for _, f := range spec.Fields {
+		known[f.Name] = true
+	}
+	extra := []string{}
+	for name := range got {
+		if !known[name] {
+			extra = append(extra, name)
+		}
+	}
+	sort.Strings(extra)
+	for _, name := range extra {
+		problems = append(problems, "unknown parameter "+name)
+	}
+	return problems
+}
+
+// resultProblems compares the result a library deserialized with the
+// one the mock sent. Every field sent must come back with its value;
+// fields that weren't sent may come back only with zero values, as
+// libraries with fixed structures write them.
+func resultProblems(path string, sent, got interface{}) []string {
+	switch want := sent.(type) {
+	case map[string]interface{}:
+		obj, ok := got.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s is %s, want an object", path, jsonString(got))}
+		}
+		var problems []string
+		for _, key := range sortedKeys(want) {
+			v, ok := obj[key]
+			if !ok || v == nil && want[key] != nil {
+				problems = append(problems, path+"."+key+" was lost")
+				continue
+			}
+			problems = append(problems, resultProblems(path+"."+key, want[key], v)...)
+		}
+		for _, key := range sortedKeys(obj) {
+			if _, ok := want[key]; !ok && !isZero(obj[key]) {
+				problems = append(problems, fmt.Sprintf("%s.%s is %s, but wasn't sent", path, key, jsonString(obj[key])))
+			}
+		}
+		return problems
+	case []interface{}:
+		items, ok := got.([]interface{})
+		if !ok || len(items) != len(want) {
+			return []string{fmt.Sprintf("%s has %s, want %d items", path, jsonString(got), len(want))}
+		}
+		var problems []string
+		for i := range want {
+			problems = append(problems, resultProblems(fmt.Sprintf("%s[%d]", path, i), want[i], items[i])...)
+		}
+		return problems
+	}
+	if jsonString(got) != jsonString(sent) {
+		return []string{fmt.Sprintf("%s is %s, want %s", path, jsonString(got), jsonString(sent))}
+	}
+	return nil
+}
+
+func isZero(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case bool:
+		return !v
+	case float64:
+		return v == 0
+	case string:
+		return v == ""
+	case []interface{}:
+		return len(v) == 0
+	case map[string]interface{}:
+		for _, item := range v {
+			if !isZero(item) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sameValue reports whether a value has the canonical one. Form-encoded
+// calls carry numbers and objects as strings, so strings are compared
+// decoded too.
+func sameValue(got, want interface{}) bool {
+	if jsonString(got) == jsonString(want) {
+		return true
+	}
+	s, ok := got.(string)
+	if !ok {
+		return false
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+		return false
+	}
+	return jsonString(decoded) == jsonString(want)
+}
+
+// jsonString returns v as JSON, with map keys in order.
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func (r *runner) authorize(req *http.Request) {
+	if r.cfg.ControlToken != "" {
+		req.Header.Set("Authorization", "Bearer "+r.cfg.ControlToken)
+	}
+}
+
+// cleanup removes the sessions of the run. Failures are ignored; they
+// don't affect the report.
+func (r *runner) cleanup() {
+	for _, session := range r.sessions {
+		req, err := http.NewRequest(http.MethodDelete, r.cfg.BaseURL+"/__control/sessions/"+url.PathEscape(session), nil)
+		if err != nil {
+			continue
+		}
+		r.authorize(req)
+		if resp, err := r.cfg.Client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+// writeJSON writes v to path as indented JSON.
+func writeJSON(path string, v interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,175 @@
+// internal/conformance/conformance_test.go
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/server"
+)
+
+// driverEnv makes the test binary a driver: "good" makes every call as
+// canonical, and "sloppy" changes sendMessage, skips getMe, and loses a
+// field of getChat.
+const driverEnv = "TG_MOCK_TEST_DRIVER"
+
+func TestMain(m *testing.M) {
+	if mode := os.Getenv(driverEnv); mode != "" {
+		if err := drive(mode); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func drive(mode string) error {
+	data, err := os.ReadFile(os.Getenv("TG_MOCK_SUITE"))
+	if err != nil {
+		return err
+	}
+	var suite Suite
+	if err := json.Unmarshal(data, &suite); err != nil {
+		return err
+	}
+	results := map[string]interface{}{}
+	for _, c := range suite.Cases {
+		params := c.Params
+		switch {
+		case mode == "sloppy" && c.Method == "getMe":
+			continue
+		case mode == "sloppy" && c.Method == "sendMessage":
+			params["text"] = "changed"
+			params["parse_mod"] = "HTML"
+		}
+		body, _ := json.Marshal(params)
+		resp, err := http.Post(c.BaseURL+"/bot"+suite.Token+"/"+c.Method, "application/json", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		var env struct {
+			Result interface{} `json:"result"`
+		}
+		json.NewDecoder(resp.Body).Decode(&env)
+		resp.Body.Close()
+		if chat, ok := env.Result.(map[string]interface{}); ok && mode == "sloppy" && c.Method == "getChat" {
+			delete(chat, "type")
+			chat["is_forum"] = true
+		}
+		results[c.ID] = env.Result
+	}
+	data, _ = json.Marshal(results)
+	return os.WriteFile(os.Getenv("TG_MOCK_RESULTS"), data, 0644)
+}
+
+func newMock(t *testing.T) string {
+	t.Helper()
+	ts := httptest.NewServer(server.New(server.Config{Deterministic: true}).Router())
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+var methods = []string{"getMe", "sendMessage", "getChat", "forwardMessage", "sendMediaGroup"}
+
+func TestRun_Reference(t *testing.T) {
+	dir := t.TempDir()
+	report, err := Run(context.Background(), Config{BaseURL: newMock(t), Methods: methods, Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed || report.Summary[Passed] != len(methods) {
+		t.Fatalf("expected the mock to answer every canonical call, got %+v", report)
+	}
+
+	data, err := os.ReadFile(dir + "/suite.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var suite Suite
+	json.Unmarshal(data, &suite)
+	if len(suite.Cases) != len(methods) || suite.SpecVersion != gen.Version {
+		t.Fatalf("expected a case per method, got %+v", suite)
+	}
+	for _, c := range suite.Cases {
+		if c.Method == "sendMediaGroup" {
+			if media, _ := c.Params["media"].([]interface{}); len(media) != 2 {
+				t.Errorf("expected an album of 2, got %v", c.Params["media"])
+			}
+		}
+		if c.Result == nil || !strings.Contains(c.BaseURL, "/session/conformance-"+c.ID) {
+			t.Errorf("expected a result and a session of its own, got %+v", c)
+		}
+	}
+}
+
+func TestRun_Driver(t *testing.T) {
+	base := newMock(t)
+	report, err := Run(context.Background(), Config{BaseURL: base, Methods: methods, Exec: driverEnv + "=good " + os.Args[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Passed || report.ClientError != "" {
+		t.Fatalf("expected the canonical driver to pass, got %+v", report)
+	}
+	for _, c := range report.Cases {
+		if !c.ResponseChecked {
+			t.Errorf("expected the response of %s checked", c.ID)
+		}
+	}
+
+	report, err = Run(context.Background(), Config{BaseURL: base, Methods: methods, Exec: driverEnv + "=sloppy " + os.Args[0]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed || report.Summary[Failed] != 2 || report.Summary[NotCalled] != 1 {
+		t.Fatalf("expected 2 failures and a missing call, got %+v", report)
+	}
+	problems := map[string]string{}
+	for _, c := range report.Cases {
+		problems[c.ID] = strings.Join(c.Problems, "; ")
+	}
+	if p := problems["sendMessage"]; !strings.Contains(p, `parameter text is "changed", want "fuzz"`) || !strings.Contains(p, "unknown parameter parse_mod") {
+		t.Errorf("expected the changed and unknown parameters, got %q", p)
+	}
+	if p := problems["getChat"]; !strings.Contains(p, "result.type was lost") || !strings.Contains(p, "result.is_forum is true, but wasn't sent") {
+		t.Errorf("expected the lost and invented fields, got %q", p)
+	}
+
+	report, err = Run(context.Background(), Config{BaseURL: base, Methods: []string{"getMe"}, Exec: "exit 3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed || !strings.Contains(report.ClientError, "exit status 3") {
+		t.Errorf("expected the driver failure reported, got %+v", report)
+	}
+
+	if _, err := Run(context.Background(), Config{BaseURL: base, Methods: []string{"teleport"}}); err == nil {
+		t.Error("expected an error for an unknown method")
+	}
+}
+
+func TestResultProblems(t *testing.T) {
+	sent := map[string]interface{}{"id": float64(1), "photo": []interface{}{map[string]interface{}{"width": float64(90)}}}
+	got := map[string]interface{}{"id": float64(1), "photo": []interface{}{map[string]interface{}{"width": float64(90)}}, "is_bot": false, "username": ""}
+	if problems := resultProblems("result", sent, got); len(problems) != 0 {
+		t.Errorf("expected fields that weren't sent to pass with zero values, got %v", problems)
+	}
+	got["id"] = "1"
+	if problems := resultProblems("result", sent, got); len(problems) != 1 || problems[0] != `result.id is "1", want 1` {
+		t.Errorf("expected the number as a string to fail, got %v", problems)
+	}
+	got["id"] = float64(1)
+	got["photo"] = []interface{}{}
+	if problems := resultProblems("result", sent, got); len(problems) != 1 || !strings.Contains(problems[0], "result.photo") {
+		t.Errorf("expected the lost photo size, got %v", problems)
+	}
+}
@@ -0,0 +1,303 @@
+// Package conversation runs scripted conversations with a bot: a user
+// sends messages and presses buttons, and the bot is expected to answer
+// each with certain Bot API calls. Scripts are written in YAML, which
+// makes the mock an acceptance test runner for bots.
+package conversation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/watzon/tg-mock/internal/inspector"
+)
+
+// DefaultTimeout is how long expectations wait for the bot's call when the
+// script doesn't say.
+const DefaultTimeout = 5 * time.Second
+
+// DefaultUserID is the user a script talks to the bot as when it doesn't
+// name one.
+const DefaultUserID = 100000001
+
+// Script is a conversation between a user and the bot under test.
+type Script struct {
+	// Name identifies the script in reports.
+	Name string `yaml:"name"`
+	// Token is the bot under test: updates are delivered to its webhook if
+	// it has one, and only its calls meet expectations. Without a token,
+	// updates are queued and the calls of any bot count.
+	Token string `yaml:"token"`
+	// UserID is the user the bot talks to, and ChatID the chat, which is
+	// the user's private chat by default.
+	UserID int64 `yaml:"user_id"`
+	ChatID int64 `yaml:"chat_id"`
+	// Timeout is how long expectations wait by default (0 = DefaultTimeout).
+	Timeout time.Duration `yaml:"timeout"`
+	Steps   []Step        `yaml:"steps"`
+}
+
+// Step is what happens next in a conversation. Exactly one of Send,
+// Press, Update, and Expect is set.
+type Step struct {
+	// Send is a message the user sends, such as "/start" or "hello".
+	Send string `yaml:"send"`
+	// Press is the text or callback data of an inline button the user
+	// presses, on the latest message in the chat that has it.
+	Press string `yaml:"press"`
+	// Update is the kind of another update to send, such as
+	// "inline_query" or "chat_member".
+	Update string `yaml:"update"`
+	// Fields shape the update of Send or Update, like the fields of
+	// POST /__control/simulate/{kind}, such as "query" or "chat_id".
+	Fields map[string]interface{} `yaml:"fields"`
+	// Expect is a call the bot is expected to make after the previous
+	// step.
+	Expect *Expect `yaml:"expect"`
+}
+
+// Expect describes a call of the bot.
+type Expect struct {
+	Method string `yaml:"method"`
+	// Contains is text that the text or caption of the call contains.
+	Contains string `yaml:"contains"`
+	// Params are parameters the call has, with these values.
+	Params map[string]interface{} `yaml:"params"`
+	// Timeout overrides the timeout of the script.
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// Parse reads a script from YAML, or JSON, which is YAML too. Unknown
+// fields are errors, so that typos don't go unnoticed.
+func Parse(data []byte) (*Script, error) {
+	var s Script
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid script: %w", err)
+	}
+	if len(s.Steps) == 0 {
+		return nil, errors.New("invalid script: no steps")
+	}
+	for i, step := range s.Steps {
+		actions := 0
+		for _, set := range []bool{step.Send != "", step.Press != "", step.Update != "", step.Expect != nil} {
+			if set {
+				actions++
+			}
+		}
+		if actions != 1 {
+			return nil, fmt.Errorf("invalid script: step %d: want exactly one of send, press, update, and expect", i+1)
+		}
+		if step.Expect != nil && step.Expect.Method == "" {
+			return nil, fmt.Errorf("invalid script: step %d: expect has no method", i+1)
+		}
+	}
+	return &s, nil
+}
+
+// Driver plays the user's side of a conversation in the mock.
+type Driver interface {
+	// Simulate sends the bot an update of the kind, made up from fields.
+	Simulate(ctx context.Context, kind string, fields map[string]interface{}) (map[string]interface{}, error)
+	// Press presses the inline button with the text or callback data on
+	// the latest message in the chat that has it, as the user.
+	Press(ctx context.Context, chatID, userID int64, button string) (map[string]interface{}, error)
+	// Recorder records the calls of the bot.
+	Recorder() *inspector.Recorder
+}
+
+// Report tells how a run went.
+type Report struct {
+	Name   string       `json:"name,omitempty"`
+	Passed bool         `json:"passed"`
+	Steps  []StepResult `json:"steps"`
+	// Duration is how long the run took, in seconds.
+	Duration float64 `json:"duration_seconds"`
+}
+
+// StepResult tells how a step went. Update is the update a user step
+// sent, and Request the call an expectation was met by.
+type StepResult struct {
+	Step    int                      `json:"step"`
+	Action  string                   `json:"action"`
+	Passed  bool                     `json:"passed"`
+	Error   string                   `json:"error,omitempty"`
+	Update  map[string]interface{}   `json:"update,omitempty"`
+	Request *inspector.RequestRecord `json:"request,omitempty"`
+}
+
+// Run plays a script through d. The run stops at the first step that
+// fails, and the report leaves out the steps after it.
+func Run(ctx context.Context, s *Script, d Driver) *Report {
+	start := time.Now()
+	userID := s.UserID
+	if userID == 0 {
+		userID = DefaultUserID
+	}
+	chatID := s.ChatID
+	if chatID == 0 {
+		chatID = userID
+	}
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	report := &Report{Name: s.Name, Passed: true, Steps: []StepResult{}}
+	// Expectations are met by calls made after the previous step
+	after := d.Recorder().LastID()
+	for i, step := range s.Steps {
+		result := StepResult{Step: i + 1, Action: step.action()}
+		var err error
+		switch {
+		case step.Send != "":
+			fields := step.fields(map[string]interface{}{"user_id": userID, "chat_id": chatID})
+			fields["text"] = step.Send
+			after = d.Recorder().LastID()
+			result.Update, err = d.Simulate(ctx, "message", fields)
+		case step.Press != "":
+			after = d.Recorder().LastID()
+			result.Update, err = d.Press(ctx, chatID, userID, step.Press)
+		case step.Update != "":
+			defaults := map[string]interface{}{"user_id": userID}
+			if s.ChatID != 0 {
+				defaults["chat_id"] = s.ChatID
+			}
+			after = d.Recorder().LastID()
+			result.Update, err = d.Simulate(ctx, step.Update, step.fields(defaults))
+		case step.Expect != nil:
+			wait := timeout
+			if step.Expect.Timeout > 0 {
+				wait = step.Expect.Timeout
+			}
+			result.Request, err = step.Expect.wait(ctx, d.Recorder(), s.Token, after, wait)
+			if result.Request != nil {
+				after = result.Request.ID
+			}
+		}
+		if err != nil {
+			result.Error = err.Error()
+			report.Passed = false
+			report.Steps = append(report.Steps, result)
+			break
+		}
+		result.Passed = true
+		report.Steps = append(report.Steps, result)
+	}
+	report.Duration = time.Since(start).Seconds()
+	return report
+}
+
+// action describes the step for reports, such as "send /start".
+func (s Step) action() string {
+	switch {
+	case s.Send != "":
+		return "send " + s.Send
+	case s.Press != "":
+		return "press " + s.Press
+	case s.Update != "":
+		return "update " + s.Update
+	}
+	return "expect " + s.Expect.Method
+}
+
+// fields returns the fields of the step's update over the defaults.
+func (s Step) fields(defaults map[string]interface{}) map[string]interface{} {
+	for k, v := range s.Fields {
+		defaults[k] = v
+	}
+	return defaults
+}
+
+// wait waits for a call after the request with ID after that meets e.
+// Calls of the method that don't are skipped, and reported if none does.
+func (e *Expect) wait(ctx context.Context, rec *inspector.Recorder, token string, after int64, timeout time.Duration) (*inspector.RequestRecord, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var mismatches []string
+	for {
+		requests, err := rec.Wait(ctx, inspector.Filter{Method: e.Method, Token: token, After: after}, 1)
+		if err != nil {
+			msg := fmt.Sprintf("no matching %s call within %s", e.Method, timeout)
+			if len(mismatches) > 0 {
+				msg += ": " + strings.Join(mismatches, "; ")
+			}
+			return nil, errors.New(msg)
+		}
+		req := requests[0]
+		problem := e.mismatch(req)
+		if problem == "" {
+			return &req, nil
+		}
+		mismatches = append(mismatches, problem)
+		after = req.ID
+	}
+}
+
+// mismatch returns why req doesn't meet e, or "" if it does.
+func (e *Expect) mismatch(req inspector.RequestRecord) string {
+	if req.IsError {
+		return fmt.Sprintf("call %d failed with status %d", req.ID, req.StatusCode)
+	}
+	if e.Contains != "" {
+		text, _ := req.Params["text"].(string)
+		if caption, ok := req.Params["caption"].(string); ok && text == "" {
+			text = caption
+		}
+		if !strings.Contains(text, e.Contains) {
+			return fmt.Sprintf("call %d has text %q, which doesn't contain %q", req.ID, text, e.Contains)
+		}
+	}
+	// Params are checked by name so the same mismatch is always reported
+	names := make([]string, 0, len(e.Params))
+	for name := range e.Params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		want := e.Params[name]
+		got, ok := req.Params[name]
+		if !ok {
+			return fmt.Sprintf("call %d has no %s", req.ID, name)
+		}
+		if !sameValue(got, want) {
+			return fmt.Sprintf("call %d has %s %s, want %s", req.ID, name, jsonString(got), jsonString(want))
+		}
+	}
+	return ""
+}
+
+// sameValue reports whether a parameter of a call has the value a script
+// expects. Form-encoded calls carry numbers and objects as strings, so
+// strings are compared decoded too.
+func sameValue(got, want interface{}) bool {
+	if jsonString(got) == jsonString(want) {
+		return true
+	}
+	s, ok := got.(string)
+	if !ok {
+		return false
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(s), &decoded); err != nil {
+		return false
+	}
+	return jsonString(decoded) == jsonString(want)
+}
+
+// jsonString returns v as JSON, with map keys in order.
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
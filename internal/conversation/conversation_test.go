@@ -0,0 +1,146 @@
+// internal/conversation/conversation_test.go
+package conversation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/inspector"
+)
+
+// botDriver plays a bot that answers each update with the calls bot
+// returns for it.
+type botDriver struct {
+	rec     *inspector.Recorder
+	bot     func(kind string, fields map[string]interface{}) []inspector.RequestRecord
+	updates []string
+}
+
+func (d *botDriver) Simulate(ctx context.Context, kind string, fields map[string]interface{}) (map[string]interface{}, error) {
+	d.updates = append(d.updates, kind)
+	for _, req := range d.bot(kind, fields) {
+		d.rec.Record(req)
+	}
+	return map[string]interface{}{kind: fields}, nil
+}
+
+func (d *botDriver) Press(ctx context.Context, chatID, userID int64, button string) (map[string]interface{}, error) {
+	if button != "Yes" {
+		return nil, errors.New("no such button")
+	}
+	return d.Simulate(ctx, "callback_query", map[string]interface{}{"data": "yes", "user_id": userID})
+}
+
+func (d *botDriver) Recorder() *inspector.Recorder { return d.rec }
+
+const greeterScript = `
+name: greeter
+token: "123:abc"
+timeout: 100ms
+steps:
+  - send: /start
+  - expect:
+      method: sendMessage
+      contains: Welcome
+      params:
+        chat_id: 100000001
+        reply_markup: {inline_keyboard: [[{text: "Yes", callback_data: "yes"}]]}
+  - press: "Yes"
+  - expect: {method: editMessageText, contains: Great}
+`
+
+func greeter(kind string, fields map[string]interface{}) []inspector.RequestRecord {
+	switch kind {
+	case "message":
+		return []inspector.RequestRecord{
+			{Token: "123:abc", Method: "sendChatAction", Params: map[string]interface{}{"action": "typing"}},
+			{Token: "123:abc", Method: "sendMessage", Params: map[string]interface{}{"chat_id": "100000001", "text": "Hi"}},
+			{Token: "123:abc", Method: "sendMessage", Params: map[string]interface{}{
+				"chat_id":      fields["chat_id"],
+				"text":         "Welcome! Continue?",
+				"reply_markup": `{"inline_keyboard":[[{"text":"Yes","callback_data":"yes"}]]}`,
+			}},
+		}
+	case "callback_query":
+		return []inspector.RequestRecord{
+			{Token: "123:abc", Method: "editMessageText", Params: map[string]interface{}{"text": "Great!"}},
+		}
+	}
+	return nil
+}
+
+func TestRun(t *testing.T) {
+	s, err := Parse([]byte(greeterScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := &botDriver{rec: inspector.NewRecorder(), bot: greeter}
+	report := Run(context.Background(), s, d)
+	if !report.Passed || len(report.Steps) != 4 {
+		t.Fatalf("expected the script to pass, got %+v", report)
+	}
+	if got := report.Steps[1].Request; got == nil || got.ID != 3 {
+		t.Errorf("expected the keyboard message to meet the expectation, got %+v", got)
+	}
+	if report.Steps[0].Action != "send /start" || report.Steps[0].Update == nil {
+		t.Errorf("expected the sent update in the report, got %+v", report.Steps[0])
+	}
+	if strings.Join(d.updates, ",") != "message,callback_query" {
+		t.Errorf("expected a message and a callback query, got %v", d.updates)
+	}
+}
+
+func TestRun_Failure(t *testing.T) {
+	s, err := Parse([]byte(greeterScript))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A bot that never offers the keyboard
+	d := &botDriver{rec: inspector.NewRecorder(), bot: func(kind string, fields map[string]interface{}) []inspector.RequestRecord {
+		return []inspector.RequestRecord{{Token: "123:abc", Method: "sendMessage", Params: map[string]interface{}{"text": "Welcome!", "chat_id": 1}}}
+	}}
+	report := Run(context.Background(), s, d)
+	if report.Passed || len(report.Steps) != 2 {
+		t.Fatalf("expected the script to stop at the expectation, got %+v", report)
+	}
+	msg := report.Steps[1].Error
+	if !strings.Contains(msg, "no matching sendMessage call within 100ms") || !strings.Contains(msg, "has chat_id 1, want 100000001") {
+		t.Errorf("expected the mismatch in the error, got %q", msg)
+	}
+	if len(d.updates) != 1 {
+		t.Errorf("expected no updates after the failure, got %v", d.updates)
+	}
+
+	// Calls of other bots and calls before the step don't count
+	d.rec.Record(inspector.RequestRecord{Token: "456:def", Method: "ping"})
+	s = &Script{Token: "123:abc", Timeout: 50 * time.Millisecond, Steps: []Step{
+		{Send: "hello"},
+		{Expect: &Expect{Method: "ping"}},
+	}}
+	if report := Run(context.Background(), s, d); report.Passed {
+		t.Errorf("expected the call of another bot not to count, got %+v", report)
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, script := range []string{
+		"steps: []",
+		"steps: [{send: hi, press: Yes}]",
+		"steps: [{expect: {contains: hi}}]",
+		"steps: [{sned: hi}]",
+	} {
+		if _, err := Parse([]byte(script)); err == nil {
+			t.Errorf("expected %q to be invalid", script)
+		}
+	}
+	s, err := Parse([]byte(`{"timeout": "2s", "steps": [{"update": "inline_query", "fields": {"query": "cats"}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Timeout != 2*time.Second || s.Steps[0].Fields["query"] != "cats" {
+		t.Errorf("expected the JSON script, got %+v", s)
+	}
+}
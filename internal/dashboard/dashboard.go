@@ -0,0 +1,22 @@
+// Package dashboard serves the embedded web UI for inspecting and
+// controlling a running tg-mock instance.
+package dashboard
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Handler returns an http.Handler serving the dashboard page. The page
+// talks to the control API from the browser, so it needs no server-side
+// state of its own.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Write(indexHTML)
+	})
+}
@@ -0,0 +1,219 @@
+// Package events delivers mock lifecycle events to orchestration webhooks,
+// letting external test runners react to conditions inside the mock as
+// they happen.
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Event types published by the mock server.
+const (
+	// TypeScenarioExhausted is published when a scenario with a Times limit
+	// has been used up.
+	TypeScenarioExhausted = "scenario.exhausted"
+	// TypeVerificationFailed is published when a Bot API request fails
+	// parameter validation.
+	TypeVerificationFailed = "verification.failed"
+	// TypeRateLimitTripped is published whenever a bot receives a 429 response.
+	TypeRateLimitTripped = "rate_limit.tripped"
+	// TypeStateReset is published when state is reset or the server restarts.
+	TypeStateReset = "state.reset"
+	// TypeMemoryLimitReached is published when an in-memory store reaches
+	// its configured memory limit.
+	TypeMemoryLimitReached = "memory.limit_reached"
+)
+
+// queueSize is the number of events buffered per subscription before new
+// events are dropped.
+const queueSize = 256
+
+// Event is a single lifecycle event.
+type Event struct {
+	Type      string                 `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Session   string                 `json:"session,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
+
+// Subscription is an orchestration webhook registered to receive events.
+type Subscription struct {
+	ID     string   `json:"id"`
+	URL    string   `json:"url"`
+	Events []string `json:"events,omitempty"` // Event types to deliver (empty = all)
+	Secret string   `json:"secret,omitempty"` // Sent in the X-TG-Mock-Secret header
+
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// wants reports whether the subscription should receive events of type t.
+func (s *Subscription) wants(t string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == t {
+			return true
+		}
+	}
+	return false
+}
+
+type subscriber struct {
+	sub   Subscription
+	queue chan Event
+}
+
+// Bus fans events out to the registered subscriptions. Each subscription
+// has its own queue and delivery goroutine, so events arrive in order and a
+// slow receiver never blocks the mock server.
+type Bus struct {
+	mu        sync.Mutex
+	subs      map[string]*subscriber
+	idCounter int64
+	client    *http.Client
+}
+
+// NewBus creates an event bus with no subscriptions.
+func NewBus() *Bus {
+	return &Bus{
+		subs: make(map[string]*subscriber),
+		client: &http.Client{
+			Timeout: 5 * time.Second,
+		},
+	}
+}
+
+// Subscribe registers a webhook and returns its ID.
+func (b *Bus) Subscribe(sub Subscription) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.idCounter++
+	sub.ID = fmt.Sprintf("sub-%d", b.idCounter)
+	sub.Delivered, sub.Dropped, sub.LastError = 0, 0, ""
+
+	s := &subscriber{sub: sub, queue: make(chan Event, queueSize)}
+	b.subs[sub.ID] = s
+	go b.deliverLoop(s)
+
+	return sub.ID
+}
+
+// Unsubscribe removes a webhook. Events already queued for it are still
+// delivered. Returns false if no such subscription exists.
+func (b *Bus) Unsubscribe(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s, ok := b.subs[id]
+	if !ok {
+		return false
+	}
+	delete(b.subs, id)
+	close(s.queue)
+	return true
+}
+
+// Clear removes all webhooks.
+func (b *Bus) Clear() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for id, s := range b.subs {
+		delete(b.subs, id)
+		close(s.queue)
+	}
+}
+
+// List returns a copy of all subscriptions, ordered by ID.
+func (b *Bus) List() []Subscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	result := make([]Subscription, 0, len(b.subs))
+	for _, s := range b.subs {
+		result = append(result, s.sub)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].ID) != len(result[j].ID) {
+			return len(result[i].ID) < len(result[j].ID)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Publish queues an event for every subscription interested in its type.
+// It never blocks; if a subscription's queue is full the event is dropped
+// for that subscription.
+func (b *Bus) Publish(e Event) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now().UTC()
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, s := range b.subs {
+		if !s.sub.wants(e.Type) {
+			continue
+		}
+		select {
+		case s.queue <- e:
+		default:
+			s.sub.Dropped++
+		}
+	}
+}
+
+func (b *Bus) deliverLoop(s *subscriber) {
+	for e := range s.queue {
+		err := b.deliver(s.sub.URL, s.sub.Secret, e)
+
+		b.mu.Lock()
+		if err != nil {
+			s.sub.LastError = err.Error()
+		} else {
+			s.sub.Delivered++
+			s.sub.LastError = ""
+		}
+		b.mu.Unlock()
+	}
+}
+
+func (b *Bus) deliver(url, secret string, e Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-TG-Mock-Event", e.Type)
+	if secret != "" {
+		req.Header.Set("X-TG-Mock-Secret", secret)
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// internal/events/events_test.go
+package events
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// receiver starts a server that forwards received events to a channel.
+func receiver(t *testing.T) (*httptest.Server, <-chan *http.Request, <-chan Event) {
+	t.Helper()
+	reqs := make(chan *http.Request, 10)
+	events := make(chan Event, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e Event
+		json.NewDecoder(r.Body).Decode(&e)
+		reqs <- r
+		events <- e
+	}))
+	t.Cleanup(srv.Close)
+	return srv, reqs, events
+}
+
+func TestBus_Publish(t *testing.T) {
+	srv, reqs, events := receiver(t)
+
+	b := NewBus()
+	defer b.Clear()
+	b.Subscribe(Subscription{URL: srv.URL, Secret: "s3cret"})
+
+	b.Publish(Event{Type: TypeStateReset, Session: "job-1", Data: map[string]interface{}{"reason": "reset"}})
+
+	select {
+	case e := <-events:
+		r := <-reqs
+		if e.Type != TypeStateReset || e.Session != "job-1" {
+			t.Errorf("unexpected event %+v", e)
+		}
+		if e.Timestamp.IsZero() {
+			t.Error("expected timestamp to be set")
+		}
+		if got := r.Header.Get("X-TG-Mock-Event"); got != TypeStateReset {
+			t.Errorf("X-TG-Mock-Event = %q, want %q", got, TypeStateReset)
+		}
+		if got := r.Header.Get("X-TG-Mock-Secret"); got != "s3cret" {
+			t.Errorf("X-TG-Mock-Secret = %q, want s3cret", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+	}
+}
+
+func TestBus_EventFilter(t *testing.T) {
+	srv, _, events := receiver(t)
+
+	b := NewBus()
+	defer b.Clear()
+	b.Subscribe(Subscription{URL: srv.URL, Events: []string{TypeRateLimitTripped}})
+
+	b.Publish(Event{Type: TypeStateReset})
+	b.Publish(Event{Type: TypeRateLimitTripped})
+
+	select {
+	case e := <-events:
+		if e.Type != TypeRateLimitTripped {
+			t.Errorf("got %s, want only %s", e.Type, TypeRateLimitTripped)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("event was not delivered")
+	}
+}
+
+func TestBus_SubscribeAndUnsubscribe(t *testing.T) {
+	b := NewBus()
+	id1 := b.Subscribe(Subscription{URL: "http://127.0.0.1:1/a"})
+	id2 := b.Subscribe(Subscription{URL: "http://127.0.0.1:1/b"})
+
+	subs := b.List()
+	if len(subs) != 2 || subs[0].ID != id1 || subs[1].ID != id2 {
+		t.Fatalf("unexpected subscriptions %+v", subs)
+	}
+
+	if !b.Unsubscribe(id1) {
+		t.Error("expected Unsubscribe to succeed")
+	}
+	if b.Unsubscribe(id1) {
+		t.Error("expected second Unsubscribe to fail")
+	}
+
+	b.Clear()
+	if len(b.List()) != 0 {
+		t.Error("expected no subscriptions after Clear")
+	}
+}
+
+func TestBus_RecordsDeliveryErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	b := NewBus()
+	defer b.Clear()
+	b.Subscribe(Subscription{URL: srv.URL})
+	b.Publish(Event{Type: TypeStateReset})
+
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if subs := b.List(); subs[0].LastError != "" {
+			if subs[0].Delivered != 0 {
+				t.Errorf("delivered = %d, want 0", subs[0].Delivered)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("expected delivery error to be recorded")
+}
@@ -0,0 +1,62 @@
+package faker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Dataset is a word list that replaces or extends one of the built-in
+// lists of the faker, such as its first names, so that generated data
+// uses a project's vocabulary.
+type Dataset struct {
+	Values []string
+	// Extend adds Values to the built-in list instead of replacing it.
+	Extend bool
+}
+
+// datasetLists are the lists datasets can replace or extend, by name.
+var datasetLists = map[string]func(l *locale) *[]string{
+	"first_names":         func(l *locale) *[]string { return &l.firstNames },
+	"last_names":          func(l *locale) *[]string { return &l.lastNames },
+	"titles":              func(l *locale) *[]string { return &l.titles },
+	"title_adjectives":    func(l *locale) *[]string { return &l.titleAdjectives },
+	"title_nouns":         func(l *locale) *[]string { return &l.titleNouns },
+	"sentences":           func(l *locale) *[]string { return &l.sentences },
+	"queries":             func(l *locale) *[]string { return &l.queries },
+	"username_adjectives": func(l *locale) *[]string { return &l.usernameAdjectives },
+	"username_nouns":      func(l *locale) *[]string { return &l.usernameNouns },
+	"domains":             func(l *locale) *[]string { return &l.domains },
+	"email_domains":       func(l *locale) *[]string { return &l.emailDomains },
+	"commands":            func(l *locale) *[]string { return &l.commands },
+}
+
+// DatasetNames returns the names of the lists datasets can replace or
+// extend, such as "first_names" or "domains".
+func DatasetNames() []string {
+	names := make([]string, 0, len(datasetLists))
+	for name := range datasetLists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckDatasets returns an error for the first dataset that names no
+// list, or that would leave its list empty.
+func CheckDatasets(datasets map[string]Dataset) error {
+	names := make([]string, 0, len(datasets))
+	for name := range datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if _, ok := datasetLists[name]; !ok {
+			return fmt.Errorf("unknown faker dataset %q: want one of %s", name, strings.Join(DatasetNames(), ", "))
+		}
+		if len(datasets[name].Values) == 0 {
+			return fmt.Errorf("faker dataset %q has no values", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package faker
+
+import (
+	"fmt"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// Density says how many of the optional fields of the generated objects
+// are present.
+type Density string
+
+const (
+	// DensityDefault fills in optional fields half of the time.
+	DensityDefault Density = ""
+	// DensityMinimal leaves out every optional field, so objects only have
+	// the fields the spec requires.
+	DensityMinimal Density = "minimal"
+	// DensitySparse fills in a few optional fields.
+	DensitySparse Density = "sparse"
+	// DensityAlways fills in every optional field, down to objects nested
+	// maxOptionalDepth deep.
+	DensityAlways Density = "always"
+)
+
+// sparseFieldProbability is how often optional fields are kept or filled
+// in with DensitySparse.
+const sparseFieldProbability = 0.2
+
+// ParseDensity parses an optional field density: never (or minimal),
+// sparse, default, or always. The empty string is default.
+func ParseDensity(s string) (Density, error) {
+	switch s {
+	case "", "default":
+		return DensityDefault, nil
+	case "never", "minimal":
+		return DensityMinimal, nil
+	case "sparse":
+		return DensitySparse, nil
+	case "always":
+		return DensityAlways, nil
+	}
+	return DensityDefault, fmt.Errorf("invalid optional field density %q: want never, sparse, default, or always", s)
+}
+
+// optionalFieldProbability is how often the spec-driven generator fills in
+// an optional field.
+func (f *Faker) optionalFieldProbability() float64 {
+	switch f.density {
+	case DensityMinimal:
+		return 0
+	case DensitySparse:
+		return sparseFieldProbability
+	case DensityAlways:
+		return 1
+	}
+	return 0.5
+}
+
+// applyDensity adds or removes the optional fields of obj, an object of
+// the type spec at the given nesting level made by a dedicated generator,
+// so that it has as many as the density asks for. Fields named like a
+// request parameter in params, such as the text of a sent message, are
+// kept, since they echo what the bot sent. Nested objects are handled the
+// same way, without params.
+func (f *Faker) applyDensity(spec gen.TypeSpec, obj map[string]interface{}, level int, params map[string]interface{}) {
+	if f.density == DensityDefault {
+		return
+	}
+	for _, field := range spec.Fields {
+		v, present := obj[field.Name]
+		if !field.Required {
+			_, echoed := params[field.Name]
+			switch {
+			case present && !echoed && (f.density == DensityMinimal || f.density == DensitySparse && !f.RandomBool(sparseFieldProbability)):
+				delete(obj, field.Name)
+				continue
+			case !present && f.density == DensityAlways && level <= maxOptionalDepth:
+				// Filled in fields are generated at the density already
+				saved := f.specDepth
+				f.specDepth = level
+				obj[field.Name] = f.generateField(field, nil)
+				f.specDepth = saved
+				continue
+			}
+		}
+		if present {
+			f.applyDensityTo(gen.ParseType(field.Types...), v, level+1)
+		}
+	}
+}
+
+// applyDensityTo applies the density to v, a value of type t nested at the
+// given level. Objects of union types are matched to their type by the
+// fields that tell the types apart, and left alone if none matches.
+func (f *Faker) applyDensityTo(t gen.TypeRef, v interface{}, level int) {
+	t = t.Primary()
+	if t.IsArray() {
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			f.applyDensityTo(*t.Elem, item, level)
+		}
+		return
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	spec, ok := gen.Types[t.Name]
+	if ok && len(spec.Subtypes) > 0 {
+		spec, ok = subtypeOf(spec, obj)
+	}
+	if ok {
+		f.applyDensity(spec, obj, level, nil)
+	}
+}
+
+// subtypeOf returns the type of the union spec that obj is, going by the
+// fields whose value the spec fixes, such as the status of a ChatMember.
+func subtypeOf(spec gen.TypeSpec, obj map[string]interface{}) (gen.TypeSpec, bool) {
+	for _, name := range spec.Subtypes {
+		sub := gen.Types[name]
+		for _, field := range sub.Fields {
+			if c := field.Constraint; c != nil && len(c.Enum) == 1 && field.Required && obj[field.Name] == c.Enum[0] {
+				return sub, true
+			}
+		}
+	}
+	return gen.TypeSpec{}, false
+}
@@ -6,6 +6,8 @@ import (
 	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/watzon/tg-mock/gen"
 )
 
 // Faker generates realistic mock data for Telegram Bot API responses.
@@ -19,13 +21,29 @@ type Faker struct {
 	seed             int64
 	mu               sync.Mutex
 
-	// Type generators registry
+	// clock is the time dates are generated around
+	clock func() time.Time
+
+	// Type generators registry, and the generators and modifiers
+	// registered by users
 	generators map[string]GeneratorFunc
+	custom     map[string]GeneratorFunc
+	modifiers  map[string][]ModifierFunc
+
+	// specDepth is how deep generateFromSpec is nested
+	specDepth int
+	// density is how many optional fields objects have
+	density Density
+	// locales are the languages of names, titles, and texts, and english
+	// the language when none are configured, with the datasets applied
+	locales []*locale
+	english *locale
 }
 
-// GeneratorFunc is a function that generates mock data for a specific type.
-// params contains the request parameters that can be reflected in the response.
-type GeneratorFunc func(f *Faker, params map[string]interface{}) map[string]interface{}
+// GeneratorFunc is a function that generates mock data for a specific type,
+// as a typed value from gen such as *gen.User. params contains the request
+// parameters that can be reflected in the response.
+type GeneratorFunc func(f *Faker, params map[string]interface{}) interface{}
 
 // Config holds configuration for the Faker.
 type Config struct {
@@ -33,6 +51,19 @@ type Config struct {
 	// 0 = use current time (non-deterministic)
 	// >0 = use fixed seed (deterministic, reproducible)
 	Seed int64
+	// OptionalFields is how many optional fields generated objects have.
+	// The zero value fills in about half of them.
+	OptionalFields Density
+	// Locales are the languages names, titles, and texts are generated
+	// in, such as "ru" or "ja"; each value picks one of them. Unknown
+	// locales are ignored, and none means English.
+	Locales []string
+	// Now is the clock that generated dates are relative to. nil uses the
+	// system clock.
+	Now func() time.Time
+	// Datasets replace or extend the built-in word lists, by name, such
+	// as "first_names"; see DatasetNames. They apply to every locale.
+	Datasets map[string]Dataset
 }
 
 // New creates a new Faker with the given configuration.
@@ -45,7 +76,21 @@ func New(cfg Config) *Faker {
 	f := &Faker{
 		rng:        rand.New(rand.NewSource(seed)),
 		seed:       seed,
+		density:    cfg.OptionalFields,
 		generators: make(map[string]GeneratorFunc),
+		custom:     make(map[string]GeneratorFunc),
+		modifiers:  make(map[string][]ModifierFunc),
+		english:    english.with(cfg.Datasets),
+		clock:      cfg.Now,
+	}
+	if f.clock == nil {
+		f.clock = time.Now
+	}
+
+	for _, name := range cfg.Locales {
+		if loc, ok := locales[name]; ok {
+			f.locales = append(f.locales, loc.with(cfg.Datasets))
+		}
 	}
 
 	// Register all type generators
@@ -72,6 +117,36 @@ func (f *Faker) Reset(seed int64) {
 	atomic.StoreInt64(&f.chatIDCounter, 0)
 }
 
+// WithSeed returns a new faker configured like f, with its generators and
+// modifiers, that starts afresh from seed: its output depends on the seed
+// alone, not on what f generated before. f is left alone.
+func (f *Faker) WithSeed(seed int64) *Faker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	c := &Faker{
+		rng:        rand.New(rand.NewSource(seed)),
+		seed:       seed,
+		clock:      f.clock,
+		generators: f.generators,
+		custom:     make(map[string]GeneratorFunc, len(f.custom)),
+		modifiers:  make(map[string][]ModifierFunc, len(f.modifiers)),
+		density:    f.density,
+		locales:    f.locales,
+		english:    f.english,
+	}
+	for name, fn := range f.custom {
+		c.custom[name] = fn
+	}
+	for name, fns := range f.modifiers {
+		c.modifiers[name] = append([]ModifierFunc(nil), fns...)
+	}
+	return c
+}
+
 // Generate creates mock data for the given Telegram API type.
 // params are the request parameters that may be reflected in the response.
 func (f *Faker) Generate(typeName string, params map[string]interface{}) interface{} {
@@ -83,6 +158,29 @@ func (f *Faker) Generate(typeName string, params map[string]interface{}) interfa
 func (f *Faker) GenerateWithOverrides(typeName string, params map[string]interface{}, overrides map[string]interface{}) interface{} {
 	f.mu.Lock()
 	defer f.mu.Unlock()
+	return f.generate(typeName, params, overrides)
+}
+
+// GenerateType creates mock data for a parsed type, with optional
+// overrides. Unions are generated as their first alternative.
+func (f *Faker) GenerateType(t gen.TypeRef, params map[string]interface{}, overrides map[string]interface{}) interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.generateType(t, params, overrides)
+}
+
+// generate is GenerateWithOverrides without locking. f.mu must be held.
+func (f *Faker) generate(typeName string, params map[string]interface{}, overrides map[string]interface{}) interface{} {
+	return f.generateType(gen.ParseType(typeName), params, overrides)
+}
+
+// generateType is GenerateType without locking. f.mu must be held.
+func (f *Faker) generateType(t gen.TypeRef, params map[string]interface{}, overrides map[string]interface{}) interface{} {
+	t = t.Primary()
+	if t.IsArray() {
+		return f.generateArray(*t.Elem, params, overrides)
+	}
+	typeName := t.Name
 
 	// Handle primitive types
 	switch typeName {
@@ -109,21 +207,42 @@ func (f *Faker) GenerateWithOverrides(typeName string, params map[string]interfa
 		return f.generateString("value")
 	}
 
-	// Handle array types
-	if len(typeName) > 9 && typeName[:9] == "Array of " {
-		elementType := typeName[9:]
-		return f.generateArray(elementType, params, overrides)
-	}
-
-	// Look up type generator
+	// Look up type generator, custom ones first
+	custom, isCustom := f.custom[typeName]
 	generator, ok := f.generators[typeName]
-	if !ok {
-		// Fallback: generate based on type name heuristics
-		return f.generateUnknownType(typeName, params, overrides)
+	spec, inSpec := gen.Types[typeName]
+	if !isCustom && !ok && len(spec.Subtypes) > 0 {
+		// Unions without a generator are one of their types
+		sub := spec.Subtypes[f.rng.Intn(len(spec.Subtypes))]
+		return f.generateType(gen.ParseType(sub), params, overrides)
 	}
 
-	// Generate base data
-	result := generator(f, params)
+	var result map[string]interface{}
+	switch {
+	case isCustom:
+		// Custom generators get what they return
+		result = fields(custom(f, params))
+		if result == nil {
+			result = make(map[string]interface{})
+		}
+	case ok:
+		// Generate base data, as the fields of the typed value
+		result = fields(generator(f, params))
+		if len(spec.Subtypes) > 0 {
+			spec, _ = subtypeOf(spec, result)
+		}
+		var echoed map[string]interface{}
+		if f.specDepth == 0 {
+			echoed = params
+		}
+		f.applyDensity(spec, result, f.specDepth+1, echoed)
+	default:
+		// Fallback: generate from the type's fields in the spec
+		result = f.generateUnknownType(typeName, params)
+	}
+	if f.specDepth == 0 && inSpec {
+		f.modify(t, result)
+	}
 
 	// Apply overrides
 	if overrides != nil {
@@ -134,13 +253,13 @@ func (f *Faker) GenerateWithOverrides(typeName string, params map[string]interfa
 }
 
 // generateArray generates an array of the specified element type.
-func (f *Faker) generateArray(elementType string, params map[string]interface{}, overrides map[string]interface{}) []interface{} {
+func (f *Faker) generateArray(elem gen.TypeRef, params map[string]interface{}, overrides map[string]interface{}) []interface{} {
 	// Determine array size based on context
-	size := f.arraySize(elementType)
+	size := f.arraySize(elem.String())
 
 	result := make([]interface{}, size)
 	for i := 0; i < size; i++ {
-		result[i] = f.GenerateWithOverrides(elementType, params, nil)
+		result[i] = f.generateType(elem, params, nil)
 	}
 
 	// Apply array overrides if provided
@@ -170,18 +289,13 @@ func (f *Faker) arraySize(elementType string) int {
 	}
 }
 
-// generateUnknownType generates data for types without specific generators.
-func (f *Faker) generateUnknownType(typeName string, params map[string]interface{}, overrides map[string]interface{}) map[string]interface{} {
-	result := make(map[string]interface{})
-
-	// Apply overrides if provided
-	if overrides != nil {
-		for k, v := range overrides {
-			result[k] = v
-		}
+// generateUnknownType generates data for types without specific generators
+// from their spec. Types missing from the spec are empty objects.
+func (f *Faker) generateUnknownType(typeName string, params map[string]interface{}) map[string]interface{} {
+	if spec, ok := gen.Types[typeName]; ok {
+		return f.generateFromSpec(spec, params)
 	}
-
-	return result
+	return make(map[string]interface{})
 }
 
 // mergeOverrides deep merges overrides into the result.
@@ -200,6 +314,12 @@ func (f *Faker) mergeOverrides(result, overrides map[string]interface{}) map[str
 	return result
 }
 
+// Now returns the time of the faker's clock, which generated dates are
+// relative to.
+func (f *Faker) Now() time.Time {
+	return f.clock()
+}
+
 // NextMessageID returns the next auto-incrementing message ID.
 func (f *Faker) NextMessageID() int64 {
 	return atomic.AddInt64(&f.messageIDCounter, 1)
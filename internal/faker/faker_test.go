@@ -0,0 +1,311 @@
+// internal/faker/faker_test.go
+package faker
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// generated are the types with a dedicated generator.
+var generated = []string{
+	"User", "Chat", "ChatFullInfo", "Message", "MessageId", "File", "Update",
+	"PhotoSize", "Audio", "Document", "Video", "Animation", "Voice", "VideoNote",
+	"Sticker", "Contact", "Location", "Venue", "Poll", "PollAnswer", "Dice",
+	"ChatMember", "ChatMemberUpdated", "ChatJoinRequest", "ChatAdministratorRights",
+	"ChatInviteLink", "ChatPhoto", "ChatPermissions", "MessageOrigin",
+	"ExternalReplyInfo", "TextQuote", "LinkPreviewOptions", "InaccessibleMessage",
+	"ReactionType", "ReactionCount", "MessageReactionUpdated",
+	"MessageReactionCountUpdated", "Giveaway", "GiveawayWinners",
+	"GiveawayCreated", "GiveawayCompleted", "InlineQuery", "ChosenInlineResult",
+	"CallbackQuery", "InlineKeyboardMarkup", "InlineKeyboardButton",
+	"ReplyKeyboardMarkup", "KeyboardButton", "WebhookInfo", "BotCommand",
+}
+
+// at is the fixed time dates are generated around in tests.
+var at = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// decode converts a generated value to what clients decode it as.
+func decode(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("encoding %v: %v", v, err)
+	}
+	var decoded interface{}
+	json.Unmarshal(data, &decoded)
+	return decoded
+}
+
+// checkSpec returns where v lacks a required field of type t or has a
+// field of the wrong JSON type.
+func checkSpec(path string, t gen.TypeRef, v interface{}) []string {
+	switch {
+	case t.IsUnion():
+		var closest []string
+		for i, alt := range t.Union {
+			problems := checkSpec(path, alt, v)
+			if len(problems) == 0 {
+				return nil
+			}
+			if i == 0 || len(problems) < len(closest) {
+				closest = problems
+			}
+		}
+		return closest
+	case t.IsArray():
+		items, ok := v.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected %s, got %T", path, t, v)}
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, checkSpec(fmt.Sprintf("%s[%d]", path, i), *t.Elem, item)...)
+		}
+		return problems
+	}
+
+	var ok bool
+	switch t.Name {
+	case "Integer":
+		n, isNumber := v.(float64)
+		ok = isNumber && n == math.Trunc(n)
+	case "Float":
+		_, ok = v.(float64)
+	case "String":
+		_, ok = v.(string)
+	case "Boolean":
+		_, ok = v.(bool)
+	case "True":
+		ok = v == true
+	default:
+		spec, known := gen.Types[t.Name]
+		if !known {
+			return nil
+		}
+		obj, isObject := v.(map[string]interface{})
+		if !isObject {
+			break
+		}
+		if len(spec.Subtypes) > 0 {
+			union := gen.TypeRef{}
+			for _, name := range spec.Subtypes {
+				union.Union = append(union.Union, gen.TypeRef{Name: name})
+			}
+			return checkSpec(path, union, v)
+		}
+		var problems []string
+		for _, field := range spec.Fields {
+			value, present := obj[field.Name]
+			if !present {
+				if field.Required {
+					problems = append(problems, fmt.Sprintf("%s.%s: missing", path, field.Name))
+				}
+				continue
+			}
+			problems = append(problems, checkSpec(path+"."+field.Name, gen.ParseType(field.Types...), value)...)
+		}
+		return problems
+	}
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected %s, got %T", path, t, v)}
+	}
+	return nil
+}
+
+func TestGenerate_MatchesSpec(t *testing.T) {
+	// Several seeds, to go down the generators' random branches
+	for seed := int64(1); seed <= 10; seed++ {
+		f := New(Config{Seed: seed, Now: func() time.Time { return at }})
+		for _, name := range generated {
+			v := decode(t, f.Generate(name, map[string]interface{}{"chat_id": int64(-100)}))
+			if problems := checkSpec(name, gen.ParseType(name), v); len(problems) > 0 {
+				t.Errorf("seed %d: %s breaks the spec: %q", seed, name, problems)
+			}
+		}
+	}
+}
+
+func TestGenerate_Reproducible(t *testing.T) {
+	run := func(seed int64) []interface{} {
+		f := New(Config{Seed: seed, Now: func() time.Time { return at }})
+		var values []interface{}
+		for _, name := range generated {
+			values = append(values, decode(t, f.Generate(name, nil)))
+		}
+		return values
+	}
+
+	a, b := run(42), run(42)
+	for i, name := range generated {
+		if !reflect.DeepEqual(a[i], b[i]) {
+			t.Errorf("seed 42 gave two %ss: %v and %v", name, a[i], b[i])
+		}
+	}
+	if reflect.DeepEqual(a, run(43)) {
+		t.Error("expected another seed to give other values")
+	}
+
+	// Reset starts the sequence over
+	f := New(Config{Seed: 42, Now: func() time.Time { return at }})
+	first := decode(t, f.Generate("Message", nil))
+	f.Reset(42)
+	if again := decode(t, f.Generate("Message", nil)); !reflect.DeepEqual(first, again) {
+		t.Errorf("expected Reset to repeat the sequence, got %v and %v", first, again)
+	}
+}
+
+func TestGenerate_Params(t *testing.T) {
+	f := New(Config{Seed: 1, Now: func() time.Time { return at }})
+
+	msg := f.Generate("Message", map[string]interface{}{"chat_id": int64(-1001), "text": "hello"}).(map[string]interface{})
+	chat, _ := msg["chat"].(map[string]interface{})
+	if msg["text"] != "hello" || chat["id"] != int64(-1001) {
+		t.Errorf("expected the message to reflect the request, got %v", msg)
+	}
+	if msg["date"] != at.Unix() {
+		t.Errorf("expected the date from the clock, got %v", msg["date"])
+	}
+	if chat["type"] != "supergroup" {
+		t.Errorf("expected a supergroup for a -100 chat ID, got %v", chat["type"])
+	}
+
+	// Message IDs increase
+	next := f.Generate("Message", nil).(map[string]interface{})
+	if next["message_id"].(int64) <= msg["message_id"].(int64) {
+		t.Errorf("expected increasing message IDs, got %v then %v", msg["message_id"], next["message_id"])
+	}
+}
+
+func TestGenerate_Media(t *testing.T) {
+	f := New(Config{Seed: 1})
+	for i := 0; i < 50; i++ {
+		doc := f.Generate("Document", nil).(map[string]interface{})
+		name, _ := doc["file_name"].(string)
+		if ext := "." + mimeExtensions[doc["mime_type"].(string)]; len(name) <= len(ext) || name[len(name)-len(ext):] != ext {
+			t.Errorf("expected %s to end in the extension of %s", name, doc["mime_type"])
+		}
+
+		sticker := f.Generate("Sticker", nil).(map[string]interface{})
+		if sticker["is_animated"] == true && sticker["is_video"] == true {
+			t.Errorf("expected a sticker to be animated or a video, not both: %v", sticker)
+		}
+	}
+}
+
+func TestOptionalFieldDensity(t *testing.T) {
+	for _, density := range []Density{DensityMinimal, DensitySparse, DensityAlways} {
+		f := New(Config{Seed: 1, OptionalFields: density})
+		for name, spec := range gen.Types {
+			if name == "InputFile" {
+				continue
+			}
+			decoded := decode(t, f.Generate(name, nil))
+			if problems := checkSpec(name, gen.ParseType(name), decoded); len(problems) > 0 {
+				t.Errorf("%s: %s breaks the spec: %q", density, name, problems)
+			}
+			obj, _ := decoded.(map[string]interface{})
+			for _, field := range spec.Fields {
+				_, present := obj[field.Name]
+				if density == DensityMinimal && present && !field.Required {
+					t.Errorf("minimal: %s has optional field %s", name, field.Name)
+				}
+				if density == DensityAlways && !present {
+					t.Errorf("always: %s lacks field %s", name, field.Name)
+				}
+			}
+		}
+	}
+
+	// Fields echoing the request stay, so responses still show what was sent
+	f := New(Config{Seed: 1, OptionalFields: DensityMinimal})
+	msg := f.Generate("Message", map[string]interface{}{"chat_id": int64(5), "text": "hi"}).(map[string]interface{})
+	if msg["text"] != "hi" {
+		t.Errorf("expected the sent text to stay, got %v", msg)
+	}
+	update, _ := f.GenerateUpdate("message", map[string]interface{}{"text": "/start"})
+	if update["message"].(map[string]interface{})["text"] != "/start" {
+		t.Errorf("expected the update's text to stay, got %v", update)
+	}
+}
+
+func TestLocales(t *testing.T) {
+	for _, locale := range []string{"ru", "de", "ja", "ar"} {
+		f := New(Config{Seed: 1, Locales: []string{locale}})
+		for name := range gen.Types {
+			if name == "InputFile" {
+				continue
+			}
+			data, _ := json.Marshal(f.Generate(name, nil))
+			// Byte limits must not cut characters in half
+			if strings.ContainsRune(string(data), utf8.RuneError) {
+				t.Errorf("%s: %s has invalid UTF-8: %s", locale, name, data)
+			}
+			var decoded interface{}
+			json.Unmarshal(data, &decoded)
+			if problems := checkSpec(name, gen.ParseType(name), decoded); len(problems) > 0 {
+				t.Errorf("%s: %s breaks the spec: %q", locale, name, problems)
+			}
+		}
+
+		msg := f.Generate("Message", map[string]interface{}{"chat_id": int64(-100)}).(map[string]interface{})
+		user := msg["from"].(map[string]interface{})
+		chat := msg["chat"].(map[string]interface{})
+		update, _ := f.GenerateUpdate("message", nil)
+		text := update["message"].(map[string]interface{})["text"].(string)
+		for _, s := range []string{user["first_name"].(string), chat["title"].(string), text} {
+			if locale != "de" && isASCII(s) {
+				t.Errorf("%s: expected %q in the locale's script", locale, s)
+			}
+		}
+		if code, ok := user["language_code"]; ok && code != locale {
+			t.Errorf("%s: expected users to speak %s, got %v", locale, locale, code)
+		}
+	}
+
+	if err := CheckLocales([]string{"ja", "xx"}); err == nil {
+		t.Error("expected an unknown locale to be rejected")
+	}
+}
+
+func isASCII(s string) bool {
+	for _, r := range s {
+		if r > 127 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestDatasets(t *testing.T) {
+	f := New(Config{Seed: 1, Datasets: map[string]Dataset{
+		"first_names": {Values: []string{"Ada"}},
+		"titles":      {Values: []string{"Analytical Engine Fans"}},
+		"domains":     {Values: []string{"corp.example"}, Extend: true},
+	}})
+	for i := 0; i < 20; i++ {
+		user := f.Generate("User", nil).(map[string]interface{})
+		if user["first_name"] != "Ada" {
+			t.Fatalf("expected first names from the dataset, got %v", user["first_name"])
+		}
+		chat := f.Generate("Chat", map[string]interface{}{"chat_id": int64(-100 - i)}).(map[string]interface{})
+		if chat["title"] != "Analytical Engine Fans" {
+			t.Fatalf("expected titles from the dataset, got %v", chat["title"])
+		}
+	}
+
+	err := CheckDatasets(map[string]Dataset{"pets": {Values: []string{"cat"}}})
+	if err == nil || !strings.Contains(err.Error(), "first_names") {
+		t.Errorf("expected an unknown dataset to be rejected with the known ones, got %v", err)
+	}
+	if err := CheckDatasets(map[string]Dataset{"titles": {}}); err == nil {
+		t.Error("expected an empty dataset to be rejected")
+	}
+}
@@ -0,0 +1,91 @@
+package faker
+
+import (
+	"reflect"
+	"strings"
+)
+
+// typed adapts a generator of a typed value from gen, such as
+// (*Faker).generateUser, to a GeneratorFunc.
+func typed[T any](generate func(f *Faker, params map[string]interface{}) T) GeneratorFunc {
+	return func(f *Faker, params map[string]interface{}) interface{} {
+		return generate(f, params)
+	}
+}
+
+// ptr returns a pointer to v, for the optional fields of typed values.
+func ptr[T any](v T) *T {
+	return &v
+}
+
+// fields returns the JSON fields of a typed value from gen, such as a
+// *gen.Message, as the map the rest of the mock works with. Unlike a JSON
+// round trip, numbers keep their Go types, so IDs stay int64.
+func fields(v interface{}) map[string]interface{} {
+	m, _ := fieldValue(reflect.ValueOf(v)).(map[string]interface{})
+	return m
+}
+
+// fieldValue returns the JSON form of a value: structs become maps by
+// their json tags, leaving out empty omitempty fields, maps and slices
+// become map[string]interface{} and []interface{}, and pointers and
+// interfaces their values.
+func fieldValue(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return fieldValue(v.Elem())
+	case reflect.Struct:
+		m := map[string]interface{}{}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name, opts, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+			if opts == "omitempty" && isEmptyValue(v.Field(i)) {
+				continue
+			}
+			m[name] = fieldValue(v.Field(i))
+		}
+		return m
+	case reflect.Map:
+		// Maps returned by custom generators may hold typed values
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = fieldValue(iter.Value())
+		}
+		return m
+	case reflect.Slice, reflect.Array:
+		// Required arrays are sent empty rather than null
+		items := make([]interface{}, v.Len())
+		for i := range items {
+			items[i] = fieldValue(v.Index(i))
+		}
+		return items
+	default:
+		return v.Interface()
+	}
+}
+
+// isEmptyValue reports whether encoding/json leaves v out of an omitempty
+// field.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+package faker
+
+import (
+	"github.com/watzon/tg-mock/gen"
+)
+
+// ModifierFunc changes an object of a type after it was generated, such as
+// a User as the from of a Message, in place. obj holds its JSON fields.
+type ModifierFunc func(f *Faker, obj map[string]interface{})
+
+// RegisterGenerator makes fn generate the type typeName whenever it is
+// generated on its own: as the result of a method, as a field of a type
+// generated from the spec, or through the control API. fn returns a typed
+// value from gen, such as a *gen.User, or the object's fields as a map.
+// Objects that built-in generators make as part of others, such as the
+// from of a Message, are left alone; use RegisterModifier for those. A nil
+// fn restores the built-in generation.
+//
+// Generators run with the faker locked: they may use its Random methods
+// and the generator returned by Generator, but not Generate.
+func (f *Faker) RegisterGenerator(typeName string, fn GeneratorFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if fn == nil {
+		delete(f.custom, typeName)
+		return
+	}
+	f.custom[typeName] = fn
+}
+
+// Generator returns the built-in generator of typeName, for custom
+// generators that extend rather than replace it. Types without a dedicated
+// generator are generated from the spec.
+func (f *Faker) Generator(typeName string) GeneratorFunc {
+	if generator, ok := f.generators[typeName]; ok {
+		return generator
+	}
+	return func(f *Faker, params map[string]interface{}) interface{} {
+		return f.generateUnknownType(typeName, params)
+	}
+}
+
+// RegisterModifier makes fn change every generated object of the type
+// typeName, wherever in a result it is, after it was generated and before
+// response data overrides apply. Modifiers of a type run in the order they
+// were registered, with the faker locked like generators.
+func (f *Faker) RegisterModifier(typeName string, fn ModifierFunc) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.modifiers[typeName] = append(f.modifiers[typeName], fn)
+}
+
+// modify runs the modifiers on the objects of v, a generated value of
+// type t.
+func (f *Faker) modify(t gen.TypeRef, v interface{}) {
+	if len(f.modifiers) == 0 {
+		return
+	}
+	eachObject(t, v, func(name string, obj map[string]interface{}) {
+		for _, fn := range f.modifiers[name] {
+			fn(f, obj)
+		}
+	})
+}
+
+// eachObject calls fn on the objects of v, a value of type t, and on the
+// objects they hold, outermost first. Objects of union types are visited
+// as the union and, if the fields that tell its types apart match one, as
+// that type.
+func eachObject(t gen.TypeRef, v interface{}, fn func(name string, obj map[string]interface{})) {
+	t = t.Primary()
+	if t.IsArray() {
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			eachObject(*t.Elem, item, fn)
+		}
+		return
+	}
+	obj, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	spec, ok := gen.Types[t.Name]
+	if !ok {
+		return
+	}
+	fn(t.Name, obj)
+	if len(spec.Subtypes) > 0 {
+		if spec, ok = subtypeOf(spec, obj); !ok {
+			return
+		}
+		fn(spec.Name, obj)
+	}
+	for _, field := range spec.Fields {
+		if value, ok := obj[field.Name]; ok {
+			eachObject(gen.ParseType(field.Types...), value, fn)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package faker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// locale holds the words the names, titles, and texts of a language are
+// made of. Usernames, emails, file paths, and other identifiers stay ASCII
+// whatever the locale, as Telegram requires.
+type locale struct {
+	// code is the language_code of users speaking the language
+	code            string
+	firstNames      []string
+	lastNames       []string
+	titleAdjectives []string
+	titleNouns      []string
+	sentences       []string
+	queries         []string
+	// titles are whole titles, picked instead of adjective-noun titles
+	// always if titlesOnly is set and half of the time otherwise
+	titles     []string
+	titlesOnly bool
+	// The ASCII lists that identifiers are made of, shared by all locales
+	usernameAdjectives []string
+	usernameNouns      []string
+	domains            []string
+	emailDomains       []string
+	commands           []string
+	// title joins an adjective and a noun into a title
+	title func(adjective, noun string) string
+	// separator joins the sentences of a text
+	separator string
+}
+
+// adjectiveFirst makes titles like "Official Channel".
+func adjectiveFirst(adjective, noun string) string {
+	return adjective + " " + noun
+}
+
+var english = &locale{
+	code:            "en",
+	firstNames:      firstNames,
+	lastNames:       lastNames,
+	titleAdjectives: titleAdjectives,
+	titleNouns:      titleNouns,
+	sentences:       sampleSentences,
+	queries:         queries,
+	title:           adjectiveFirst,
+	separator:       " ",
+
+	usernameAdjectives: usernameAdjectives,
+	usernameNouns:      usernameNouns,
+	domains:            domains,
+	emailDomains:       emailDomains,
+	commands:           commands,
+}
+
+// locales are the locales the faker can generate text in, by name.
+var locales = map[string]*locale{
+	"en": english,
+	"ru": {
+		code: "ru",
+		firstNames: []string{
+			"Александр", "Анна", "Дмитрий", "Мария", "Сергей", "Елена", "Андрей", "Ольга",
+			"Алексей", "Татьяна", "Иван", "Наталья", "Михаил", "Екатерина", "Никита", "Юлия",
+		},
+		lastNames: []string{
+			"Иванов", "Смирнов", "Кузнецов", "Попов", "Васильев", "Петров", "Соколов", "Михайлов",
+			"Новиков", "Фёдоров", "Морозов", "Волков", "Алексеев", "Лебедев", "Семёнов", "Егоров",
+		},
+		titleAdjectives: []string{
+			"Официальный", "Новый", "Лучший", "Главный", "Большой", "Городской", "Семейный", "Рабочий",
+		},
+		titleNouns: []string{
+			"чат", "канал", "клуб", "форум", "проект", "блог", "совет", "кружок",
+		},
+		sentences: []string{
+			"Привет, это тестовое сообщение.",
+			"Добро пожаловать в группу!",
+			"Спасибо за ваше сообщение.",
+			"Отличный вопрос!",
+			"Я скоро вам отвечу.",
+			"Пожалуйста, загляните в документацию.",
+			"Хорошего дня!",
+			"Дайте знать, если нужна помощь.",
+		},
+		queries:   []string{"поиск", "пример", "тест", "привет мир", "погода в Москве"},
+		title:     adjectiveFirst,
+		separator: " ",
+	},
+	"de": {
+		code: "de",
+		firstNames: []string{
+			"Lukas", "Anna", "Jürgen", "Sophie", "Maximilian", "Lea", "Jonas", "Hannah",
+			"Felix", "Jörg", "Leon", "Marie", "Björn", "Käthe", "Paul", "Emilia",
+		},
+		lastNames: []string{
+			"Müller", "Schmidt", "Schneider", "Fischer", "Weber", "Meyer", "Wagner", "Becker",
+			"Schulz", "Hoffmann", "Schäfer", "Koch", "Bauer", "Richter", "Klein", "Krämer",
+		},
+		titleAdjectives: []string{
+			"Offizielle", "Neue", "Große", "Beste", "Bunte", "Freie", "Tolle", "Echte",
+		},
+		titleNouns: []string{
+			"Gruppe", "Runde", "Gemeinschaft", "Stammtischrunde", "Nachbarschaft", "Werkstatt", "Bühne", "Küche",
+		},
+		sentences: []string{
+			"Hallo, das ist eine Testnachricht.",
+			"Willkommen in der Gruppe!",
+			"Danke für deine Nachricht.",
+			"Das ist eine großartige Frage!",
+			"Ich melde mich bald bei dir.",
+			"Bitte schau in die Dokumentation.",
+			"Einen schönen Tag noch!",
+			"Sag Bescheid, wenn du Hilfe brauchst.",
+		},
+		queries:   []string{"Suche", "Beispiel", "Test", "Hallo Welt", "Straßenbahn München"},
+		title:     adjectiveFirst,
+		separator: " ",
+	},
+	"ja": {
+		code: "ja",
+		firstNames: []string{
+			"翔太", "さくら", "大輝", "陽菜", "蓮", "結衣", "悠真", "美咲",
+			"湊", "葵", "颯太", "凛", "陸", "芽依", "健太", "ゆい",
+		},
+		lastNames: []string{
+			"佐藤", "鈴木", "高橋", "田中", "伊藤", "渡辺", "山本", "中村",
+			"小林", "加藤", "吉田", "山田", "佐々木", "松本", "井上", "木村",
+		},
+		titleAdjectives: []string{
+			"公式", "新しい", "東京", "みんなの", "楽しい", "最高の", "日本", "秘密の",
+		},
+		titleNouns: []string{
+			"グループ", "チャンネル", "コミュニティ", "クラブ", "サークル", "広場", "部屋", "ネットワーク",
+		},
+		sentences: []string{
+			"こんにちは、これはテストメッセージです。",
+			"グループへようこそ！",
+			"メッセージをありがとうございます。",
+			"いい質問ですね！",
+			"すぐに返信します。",
+			"ドキュメントを確認してください。",
+			"素敵な一日を！",
+			"困ったことがあれば教えてください。",
+		},
+		queries: []string{"検索", "例", "テスト", "こんにちは世界", "東京の天気"},
+		title: func(adjective, noun string) string {
+			return adjective + noun
+		},
+		separator: "",
+	},
+	"ar": {
+		code: "ar",
+		firstNames: []string{
+			"محمد", "فاطمة", "أحمد", "مريم", "علي", "نور", "عمر", "سارة",
+			"يوسف", "ليلى", "خالد", "هدى", "حسن", "زينب", "إبراهيم", "رنا",
+		},
+		lastNames: []string{
+			"العلي", "الحسن", "المصري", "الخطيب", "حداد", "النجار", "الشامي", "منصور",
+			"القاسم", "سليمان", "عبدالله", "يوسف", "الراشد", "الأحمد", "صالح", "عثمان",
+		},
+		titleAdjectives: []string{
+			"الرسمية", "الجديدة", "الكبرى", "المفتوحة", "العربية", "الذهبية", "الأولى", "السعيدة",
+		},
+		titleNouns: []string{
+			"المجموعة", "القناة", "الرابطة", "الجمعية", "الشبكة", "الساحة", "المنصة", "الدائرة",
+		},
+		sentences: []string{
+			"مرحبا، هذه رسالة تجريبية.",
+			"أهلا بك في المجموعة!",
+			"شكرا على رسالتك.",
+			"سؤال رائع!",
+			"سأرد عليك قريبا.",
+			"يرجى مراجعة التوثيق.",
+			"أتمنى لك يوما سعيدا!",
+			"أخبرني إذا احتجت إلى مساعدة.",
+		},
+		queries: []string{"بحث", "مثال", "تجربة", "مرحبا بالعالم", "طقس القاهرة"},
+		// Adjectives follow their nouns
+		title: func(adjective, noun string) string {
+			return noun + " " + adjective
+		},
+		separator: " ",
+	},
+}
+
+// Locales returns the names of the locales the faker can generate text
+// in, such as "en" or "ja".
+func Locales() []string {
+	names := make([]string, 0, len(locales))
+	for name := range locales {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckLocales returns an error naming the first of names that isn't a
+// locale the faker knows.
+func CheckLocales(names []string) error {
+	for _, name := range names {
+		if _, ok := locales[name]; !ok {
+			return fmt.Errorf("unknown faker locale %q: want one of %s", name, strings.Join(Locales(), ", "))
+		}
+	}
+	return nil
+}
+
+// with returns a copy of l with the ASCII lists of English and the
+// datasets applied.
+func (l *locale) with(datasets map[string]Dataset) *locale {
+	c := *l
+	c.usernameAdjectives = english.usernameAdjectives
+	c.usernameNouns = english.usernameNouns
+	c.domains = english.domains
+	c.emailDomains = english.emailDomains
+	c.commands = english.commands
+	for name, dataset := range datasets {
+		list, ok := datasetLists[name]
+		if !ok || len(dataset.Values) == 0 {
+			continue
+		}
+		values := list(&c)
+		if dataset.Extend {
+			// Copy, so that the built-in list is left alone
+			*values = append(append([]string(nil), *values...), dataset.Values...)
+		} else {
+			*values = dataset.Values
+			c.titlesOnly = c.titlesOnly || name == "titles"
+		}
+	}
+	return &c
+}
+
+// locale returns the locale of a generated value: English by default, or
+// one of the configured locales.
+func (f *Faker) locale() *locale {
+	switch len(f.locales) {
+	case 0:
+		return f.english
+	case 1:
+		return f.locales[0]
+	}
+	return f.locales[f.rng.Intn(len(f.locales))]
+}
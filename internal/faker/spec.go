@@ -0,0 +1,123 @@
+package faker
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+const (
+	// maxOptionalDepth is how deep the spec-driven generator nests
+	// objects in optional fields; deeper objects only get the fields they
+	// require.
+	maxOptionalDepth = 2
+	// maxSpecDepth stops the spec-driven generator on types that require
+	// themselves.
+	maxSpecDepth = 8
+)
+
+// generateFromSpec generates a type without a dedicated generator by
+// walking its fields in the spec: required fields are always generated and
+// optional ones as often as the density says, with values chosen by the field name
+// heuristics. Request parameters named like fields of the outermost object
+// are reflected.
+func (f *Faker) generateFromSpec(spec gen.TypeSpec, params map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(spec.Fields))
+	if f.specDepth >= maxSpecDepth {
+		return result
+	}
+	f.specDepth++
+	defer func() { f.specDepth-- }()
+
+	for _, field := range spec.Fields {
+		if !field.Required && (f.specDepth > maxOptionalDepth || !f.RandomBool(f.optionalFieldProbability())) {
+			continue
+		}
+		if f.specDepth == 1 {
+			if v, ok := reflectParam(field, params); ok {
+				result[field.Name] = v
+				continue
+			}
+		}
+		result[field.Name] = f.generateField(field, params)
+	}
+	return result
+}
+
+// generateField generates the value of a field, within the constraint the
+// spec states for it.
+func (f *Faker) generateField(field gen.FieldSpec, params map[string]interface{}) interface{} {
+	t := gen.ParseType(field.Types...).Primary()
+	c := field.Constraint
+	if c != nil && len(c.Enum) > 0 {
+		value := c.Enum[f.rng.Intn(len(c.Enum))]
+		if t.Name == "Integer" {
+			n, _ := strconv.ParseInt(value, 10, 64)
+			return n
+		}
+		return value
+	}
+
+	switch t.Name {
+	case "String":
+		return truncate(f.generateString(field.Name), c)
+	case "Integer":
+		if c != nil && c.Unit == "" && c.Max > 0 {
+			return f.RandomInt64(c.Min, c.Max+1)
+		}
+		return f.generateInt64(field.Name)
+	case "Float":
+		return f.generateFloat64(field.Name)
+	case "Boolean":
+		return f.generateBool(field.Name)
+	case "True":
+		return true
+	}
+	return f.generateType(t, params, nil)
+}
+
+// reflectParam returns the request parameter named like a field, if it
+// has the field's type.
+func reflectParam(field gen.FieldSpec, params map[string]interface{}) (interface{}, bool) {
+	v, ok := params[field.Name]
+	if !ok {
+		return nil, false
+	}
+	switch gen.ParseType(field.Types...).Primary().Name {
+	case "String":
+		s, ok := v.(string)
+		return s, ok
+	case "Integer":
+		switch n := v.(type) {
+		case int64:
+			return n, true
+		case float64:
+			return int64(n), n == float64(int64(n))
+		}
+	case "Boolean":
+		b, ok := v.(bool)
+		return b, ok
+	}
+	return nil, false
+}
+
+// truncate shortens s to the maximum length of c, if it has one.
+func truncate(s string, c *gen.Constraint) string {
+	if c == nil || c.Max == 0 || c.Unit == "items" {
+		return s
+	}
+	if c.Unit == "characters" {
+		for utf8.RuneCountInString(s) > int(c.Max) {
+			_, size := utf8.DecodeLastRuneInString(s)
+			s = s[:len(s)-size]
+		}
+		return s
+	}
+	// Byte lengths cut whole characters, so the text stays valid UTF-8
+	for len(s) > int(c.Max) {
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+	}
+	return s
+}
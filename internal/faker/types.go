@@ -1,70 +1,153 @@
 package faker
 
 import (
-	"time"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+
+	"github.com/watzon/tg-mock/gen"
 )
 
 // registerGenerators registers all type-specific generators.
 func (f *Faker) registerGenerators() {
 	// Core types
-	f.generators["User"] = (*Faker).generateUser
-	f.generators["Chat"] = (*Faker).generateChat
-	f.generators["ChatFullInfo"] = (*Faker).generateChatFullInfo
-	f.generators["Message"] = (*Faker).generateMessage
-	f.generators["MessageId"] = (*Faker).generateMessageId
-	f.generators["File"] = (*Faker).generateFile
-	f.generators["Update"] = (*Faker).generateUpdate
+	f.generators["User"] = typed((*Faker).generateUser)
+	f.generators["Chat"] = typed((*Faker).generateChat)
+	f.generators["ChatFullInfo"] = typed((*Faker).generateChatFullInfo)
+	f.generators["Message"] = typed((*Faker).generateMessage)
+	f.generators["MessageId"] = typed((*Faker).generateMessageId)
+	f.generators["File"] = typed((*Faker).generateFile)
+	f.generators["Update"] = typed((*Faker).generateUpdate)
 
 	// Media types
-	f.generators["PhotoSize"] = (*Faker).generatePhotoSize
-	f.generators["Audio"] = (*Faker).generateAudio
-	f.generators["Document"] = (*Faker).generateDocument
-	f.generators["Video"] = (*Faker).generateVideo
-	f.generators["Animation"] = (*Faker).generateAnimation
-	f.generators["Voice"] = (*Faker).generateVoice
-	f.generators["VideoNote"] = (*Faker).generateVideoNote
-	f.generators["Sticker"] = (*Faker).generateSticker
-	f.generators["Contact"] = (*Faker).generateContact
-	f.generators["Location"] = (*Faker).generateLocation
-	f.generators["Venue"] = (*Faker).generateVenue
-	f.generators["Poll"] = (*Faker).generatePoll
-	f.generators["Dice"] = (*Faker).generateDice
+	f.generators["PhotoSize"] = typed((*Faker).generatePhotoSize)
+	f.generators["Audio"] = typed((*Faker).generateAudio)
+	f.generators["Document"] = typed((*Faker).generateDocument)
+	f.generators["Video"] = typed((*Faker).generateVideo)
+	f.generators["Animation"] = typed((*Faker).generateAnimation)
+	f.generators["Voice"] = typed((*Faker).generateVoice)
+	f.generators["VideoNote"] = typed((*Faker).generateVideoNote)
+	f.generators["Sticker"] = typed((*Faker).generateSticker)
+	f.generators["Contact"] = typed((*Faker).generateContact)
+	f.generators["Location"] = typed((*Faker).generateLocation)
+	f.generators["Venue"] = typed((*Faker).generateVenue)
+	f.generators["Poll"] = typed((*Faker).generatePoll)
+	f.generators["PollAnswer"] = typed((*Faker).generatePollAnswer)
+	f.generators["Dice"] = typed((*Faker).generateDice)
 
 	// Chat-related types
-	f.generators["ChatMember"] = (*Faker).generateChatMember
-	f.generators["ChatMemberOwner"] = (*Faker).generateChatMemberOwner
-	f.generators["ChatMemberAdministrator"] = (*Faker).generateChatMemberAdministrator
-	f.generators["ChatMemberMember"] = (*Faker).generateChatMemberMember
-	f.generators["ChatInviteLink"] = (*Faker).generateChatInviteLink
-	f.generators["ChatPhoto"] = (*Faker).generateChatPhoto
-	f.generators["ChatPermissions"] = (*Faker).generateChatPermissions
+	f.generators["ChatMember"] = typed((*Faker).generateChatMember)
+	f.generators["ChatMemberOwner"] = typed((*Faker).generateChatMemberOwner)
+	f.generators["ChatMemberAdministrator"] = typed((*Faker).generateChatMemberAdministrator)
+	f.generators["ChatMemberMember"] = typed((*Faker).generateChatMemberMember)
+	f.generators["ChatMemberRestricted"] = typed((*Faker).generateChatMemberRestricted)
+	f.generators["ChatMemberLeft"] = typed((*Faker).generateChatMemberLeft)
+	f.generators["ChatMemberBanned"] = typed((*Faker).generateChatMemberBanned)
+	f.generators["ChatMemberUpdated"] = typed((*Faker).generateChatMemberUpdated)
+	f.generators["ChatJoinRequest"] = typed((*Faker).generateChatJoinRequest)
+	f.generators["ChatAdministratorRights"] = typed((*Faker).generateChatAdministratorRights)
+	f.generators["ChatInviteLink"] = typed((*Faker).generateChatInviteLink)
+	f.generators["ChatPhoto"] = typed((*Faker).generateChatPhoto)
+	f.generators["ChatPermissions"] = typed((*Faker).generateChatPermissions)
+
+	// Message metadata types
+	f.generators["MessageOrigin"] = typed((*Faker).generateMessageOrigin)
+	f.generators["MessageOriginUser"] = typed((*Faker).generateMessageOriginUser)
+	f.generators["MessageOriginHiddenUser"] = typed((*Faker).generateMessageOriginHiddenUser)
+	f.generators["MessageOriginChat"] = typed((*Faker).generateMessageOriginChat)
+	f.generators["MessageOriginChannel"] = typed((*Faker).generateMessageOriginChannel)
+	f.generators["ExternalReplyInfo"] = typed((*Faker).generateExternalReplyInfo)
+	f.generators["TextQuote"] = typed((*Faker).generateTextQuote)
+	f.generators["LinkPreviewOptions"] = typed((*Faker).generateLinkPreviewOptions)
+	f.generators["InaccessibleMessage"] = typed((*Faker).generateInaccessibleMessage)
+
+	// Reaction types
+	f.generators["ReactionType"] = typed((*Faker).generateReactionType)
+	f.generators["ReactionTypeEmoji"] = typed((*Faker).generateReactionTypeEmoji)
+	f.generators["ReactionTypeCustomEmoji"] = typed((*Faker).generateReactionTypeCustomEmoji)
+	f.generators["ReactionTypePaid"] = typed((*Faker).generateReactionTypePaid)
+	f.generators["ReactionCount"] = typed((*Faker).generateReactionCount)
+	f.generators["MessageReactionUpdated"] = typed((*Faker).generateMessageReactionUpdated)
+	f.generators["MessageReactionCountUpdated"] = typed((*Faker).generateMessageReactionCountUpdated)
+
+	// Giveaway types
+	f.generators["Giveaway"] = typed((*Faker).generateGiveaway)
+	f.generators["GiveawayWinners"] = typed((*Faker).generateGiveawayWinners)
+	f.generators["GiveawayCreated"] = typed((*Faker).generateGiveawayCreated)
+	f.generators["GiveawayCompleted"] = typed((*Faker).generateGiveawayCompleted)
 
 	// Inline types
-	f.generators["InlineQuery"] = (*Faker).generateInlineQuery
-	f.generators["ChosenInlineResult"] = (*Faker).generateChosenInlineResult
-	f.generators["CallbackQuery"] = (*Faker).generateCallbackQuery
+	f.generators["InlineQuery"] = typed((*Faker).generateInlineQuery)
+	f.generators["ChosenInlineResult"] = typed((*Faker).generateChosenInlineResult)
+	f.generators["CallbackQuery"] = typed((*Faker).generateCallbackQuery)
 
 	// Keyboard types
-	f.generators["InlineKeyboardMarkup"] = (*Faker).generateInlineKeyboardMarkup
-	f.generators["InlineKeyboardButton"] = (*Faker).generateInlineKeyboardButton
-	f.generators["ReplyKeyboardMarkup"] = (*Faker).generateReplyKeyboardMarkup
-	f.generators["KeyboardButton"] = (*Faker).generateKeyboardButton
+	f.generators["InlineKeyboardMarkup"] = typed((*Faker).generateInlineKeyboardMarkup)
+	f.generators["InlineKeyboardButton"] = typed((*Faker).generateInlineKeyboardButton)
+	f.generators["ReplyKeyboardMarkup"] = typed((*Faker).generateReplyKeyboardMarkup)
+	f.generators["KeyboardButton"] = typed((*Faker).generateKeyboardButton)
 
 	// Other types
-	f.generators["WebhookInfo"] = (*Faker).generateWebhookInfo
-	f.generators["BotCommand"] = (*Faker).generateBotCommand
-	f.generators["BotDescription"] = (*Faker).generateBotDescription
-	f.generators["BotName"] = (*Faker).generateBotName
-	f.generators["BotShortDescription"] = (*Faker).generateBotShortDescription
-	f.generators["MessageEntity"] = (*Faker).generateMessageEntity
-	f.generators["UserProfilePhotos"] = (*Faker).generateUserProfilePhotos
-	f.generators["ForumTopic"] = (*Faker).generateForumTopic
-	f.generators["SentWebAppMessage"] = (*Faker).generateSentWebAppMessage
+	f.generators["WebhookInfo"] = typed((*Faker).generateWebhookInfo)
+	f.generators["BotCommand"] = typed((*Faker).generateBotCommand)
+	f.generators["BotDescription"] = typed((*Faker).generateBotDescription)
+	f.generators["BotName"] = typed((*Faker).generateBotName)
+	f.generators["BotShortDescription"] = typed((*Faker).generateBotShortDescription)
+	f.generators["MessageEntity"] = typed((*Faker).generateMessageEntity)
+	f.generators["UserProfilePhotos"] = typed((*Faker).generateUserProfilePhotos)
+	f.generators["ForumTopic"] = typed((*Faker).generateForumTopic)
+	f.generators["SentWebAppMessage"] = typed((*Faker).generateSentWebAppMessage)
+	f.generators["BusinessConnection"] = typed((*Faker).generateBusinessConnection)
+	f.generators["BusinessMessagesDeleted"] = typed((*Faker).generateBusinessMessagesDeleted)
+	f.generators["GameHighScore"] = typed((*Faker).generateGameHighScore)
+	f.generators["PreparedInlineMessage"] = typed((*Faker).generatePreparedInlineMessage)
+	f.generators["StickerSet"] = typed((*Faker).generateStickerSet)
+	f.generators["Story"] = typed((*Faker).generateStory)
+	f.generators["MenuButton"] = typed((*Faker).generateMenuButton)
+	f.generators["MenuButtonCommands"] = typed((*Faker).generateMenuButtonCommands)
+	f.generators["MenuButtonWebApp"] = typed((*Faker).generateMenuButtonWebApp)
+	f.generators["MenuButtonDefault"] = typed((*Faker).generateMenuButtonDefault)
+
+	// Gift types
+	f.generators["Gift"] = typed((*Faker).generateGift)
+	f.generators["Gifts"] = typed((*Faker).generateGifts)
+	f.generators["OwnedGift"] = typed((*Faker).generateOwnedGift)
+	f.generators["OwnedGiftRegular"] = typed((*Faker).generateOwnedGiftRegular)
+	f.generators["OwnedGifts"] = typed((*Faker).generateOwnedGifts)
+
+	// Payment types
+	f.generators["StarAmount"] = typed((*Faker).generateStarAmount)
+	f.generators["ShippingQuery"] = typed((*Faker).generateShippingQuery)
+	f.generators["ShippingAddress"] = typed((*Faker).generateShippingAddress)
+	f.generators["PreCheckoutQuery"] = typed((*Faker).generatePreCheckoutQuery)
+	f.generators["PaidMediaPurchased"] = typed((*Faker).generatePaidMediaPurchased)
+	f.generators["StarTransactions"] = typed((*Faker).generateStarTransactions)
+	f.generators["StarTransaction"] = typed((*Faker).generateStarTransaction)
+	f.generators["TransactionPartner"] = typed((*Faker).generateTransactionPartner)
+	f.generators["TransactionPartnerUser"] = typed((*Faker).generateTransactionPartnerUser)
+	f.generators["TransactionPartnerChat"] = typed((*Faker).generateTransactionPartnerChat)
+	f.generators["TransactionPartnerAffiliateProgram"] = typed((*Faker).generateTransactionPartnerAffiliateProgram)
+	f.generators["TransactionPartnerFragment"] = typed((*Faker).generateTransactionPartnerFragment)
+	f.generators["TransactionPartnerTelegramAds"] = typed((*Faker).generateTransactionPartnerTelegramAds)
+	f.generators["TransactionPartnerTelegramApi"] = typed((*Faker).generateTransactionPartnerTelegramApi)
+	f.generators["TransactionPartnerOther"] = typed((*Faker).generateTransactionPartnerOther)
+	f.generators["RevenueWithdrawalState"] = typed((*Faker).generateRevenueWithdrawalState)
+
+	// Boost types
+	f.generators["UserChatBoosts"] = typed((*Faker).generateUserChatBoosts)
+	f.generators["ChatBoost"] = typed((*Faker).generateChatBoost)
+	f.generators["ChatBoostSource"] = typed((*Faker).generateChatBoostSource)
+	f.generators["ChatBoostSourcePremium"] = typed((*Faker).generateChatBoostSourcePremium)
+	f.generators["ChatBoostSourceGiftCode"] = typed((*Faker).generateChatBoostSourceGiftCode)
+	f.generators["ChatBoostSourceGiveaway"] = typed((*Faker).generateChatBoostSourceGiveaway)
+	f.generators["ChatBoostUpdated"] = typed((*Faker).generateChatBoostUpdated)
+	f.generators["ChatBoostRemoved"] = typed((*Faker).generateChatBoostRemoved)
+	f.generators["ChatBoostAdded"] = typed((*Faker).generateChatBoostAdded)
 }
 
 // Core type generators
 
-func (f *Faker) generateUser(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateUser(params map[string]interface{}) *gen.User {
 	userID := f.NextUserID() + 100000000
 
 	// Check if user_id is provided in params
@@ -74,30 +157,47 @@ func (f *Faker) generateUser(params map[string]interface{}) map[string]interface
 		userID = id
 	}
 
-	user := map[string]interface{}{
-		"id":         userID,
-		"is_bot":     false,
-		"first_name": f.RandomChoice(firstNames),
-	}
+	user := f.userProfile(userID)
+	user.ID = userID
+	return user
+}
 
-	// Add optional fields with some probability
-	if f.RandomBool(0.7) {
-		user["last_name"] = f.RandomChoice(lastNames)
+// userProfile returns the names and settings of a user, derived from its
+// ID so that the same user looks the same in every response. With several
+// locales, the ID also picks the user's language.
+func (f *Faker) userProfile(userID int64) *gen.User {
+	rng := rand.New(rand.NewSource(userID))
+	loc := f.english
+	if len(f.locales) > 0 {
+		loc = f.locales[rng.Intn(len(f.locales))]
 	}
-	if f.RandomBool(0.8) {
-		user["username"] = f.generateUsername()
+	profile := &gen.User{
+		FirstName: loc.firstNames[rng.Intn(len(loc.firstNames))],
 	}
-	if f.RandomBool(0.5) {
-		user["language_code"] = f.RandomChoice(languageCodes)
+
+	// Add optional fields with some probability
+	if rng.Float64() < 0.7 {
+		profile.LastName = loc.lastNames[rng.Intn(len(loc.lastNames))]
+	}
+	if rng.Float64() < 0.8 {
+		profile.Username = fmt.Sprintf("%s_%s_%d",
+			loc.usernameAdjectives[rng.Intn(len(loc.usernameAdjectives))],
+			loc.usernameNouns[rng.Intn(len(loc.usernameNouns))],
+			rng.Intn(1000))
+	}
+	if rng.Float64() < 0.5 {
+		profile.LanguageCode = languageCodes[rng.Intn(len(languageCodes))]
+		if len(f.locales) > 0 {
+			profile.LanguageCode = loc.code
+		}
 	}
-	if f.RandomBool(0.3) {
-		user["is_premium"] = true
+	if rng.Float64() < 0.3 {
+		profile.IsPremium = ptr(true)
 	}
-
-	return user
+	return profile
 }
 
-func (f *Faker) generateChat(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateChat(params map[string]interface{}) *gen.Chat {
 	chatID := f.NextChatID()
 	chatType := "private"
 
@@ -117,58 +217,70 @@ func (f *Faker) generateChat(params map[string]interface{}) map[string]interface
 		}
 	}
 
-	chat := map[string]interface{}{
-		"id":   chatID,
-		"type": chatType,
+	chat := &gen.Chat{
+		ID:   chatID,
+		Type: chatType,
 	}
 
 	// Add type-specific fields
 	switch chatType {
 	case "private":
-		chat["first_name"] = f.RandomChoice(firstNames)
-		if f.RandomBool(0.7) {
-			chat["last_name"] = f.RandomChoice(lastNames)
-		}
-		if f.RandomBool(0.8) {
-			chat["username"] = f.generateUsername()
-		}
+		// A private chat has the names of its user
+		profile := f.userProfile(chatID)
+		chat.FirstName = profile.FirstName
+		chat.LastName = profile.LastName
+		chat.Username = profile.Username
 	case "group", "supergroup":
-		chat["title"] = f.generateTitle()
+		chat.Title = f.generateTitle()
 		if f.RandomBool(0.6) {
-			chat["username"] = f.generateUsername()
+			chat.Username = f.generateUsername()
 		}
 	case "channel":
-		chat["title"] = f.generateTitle()
+		chat.Title = f.generateTitle()
 		if f.RandomBool(0.8) {
-			chat["username"] = f.generateUsername()
+			chat.Username = f.generateUsername()
 		}
 	}
 
 	return chat
 }
 
-func (f *Faker) generateChatFullInfo(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateChatFullInfo(params map[string]interface{}) *gen.ChatFullInfo {
 	// Start with basic chat info
 	chat := f.generateChat(params)
 
 	// Add full info fields
-	chat["accent_color_id"] = f.RandomInt64(0, 20)
-	chat["max_reaction_count"] = int64(11)
+	info := &gen.ChatFullInfo{
+		ID:               chat.ID,
+		Type:             chat.Type,
+		Title:            chat.Title,
+		Username:         chat.Username,
+		FirstName:        chat.FirstName,
+		LastName:         chat.LastName,
+		AccentColorID:    f.RandomInt64(0, 20),
+		MaxReactionCount: 11,
+		AcceptedGiftTypes: gen.AcceptedGiftTypes{
+			UnlimitedGifts:      true,
+			LimitedGifts:        true,
+			UniqueGifts:         true,
+			PremiumSubscription: true,
+		},
+	}
 
 	if f.RandomBool(0.6) {
-		chat["photo"] = f.generateChatPhoto(params)
+		info.Photo = f.generateChatPhoto(params)
 	}
 	if f.RandomBool(0.5) {
-		chat["bio"] = f.generateText()
+		info.Bio = f.generateText()
 	}
 	if f.RandomBool(0.4) {
-		chat["description"] = f.generateText()
+		info.Description = f.generateText()
 	}
 
-	return chat
+	return info
 }
 
-func (f *Faker) generateMessage(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateMessage(params map[string]interface{}) *gen.Message {
 	messageID := f.NextMessageID()
 	chatID := int64(1)
 
@@ -179,81 +291,78 @@ func (f *Faker) generateMessage(params map[string]interface{}) map[string]interf
 		chatID = id
 	}
 
-	msg := map[string]interface{}{
-		"message_id": messageID,
-		"date":       time.Now().Unix(),
-		"chat":       f.generateChat(map[string]interface{}{"chat_id": chatID}),
+	msg := &gen.Message{
+		MessageID: messageID,
+		Date:      f.Now().Unix(),
+		Chat:      *f.generateChat(map[string]interface{}{"chat_id": chatID}),
 	}
 
 	// Add from user for non-channel messages
-	chatType := msg["chat"].(map[string]interface{})["type"].(string)
-	if chatType != "channel" {
-		msg["from"] = f.generateUser(params)
+	if msg.Chat.Type != "channel" {
+		msg.From = f.generateUser(params)
 	}
 
 	// Reflect text from params
 	if text, ok := params["text"].(string); ok {
-		msg["text"] = text
+		msg.Text = text
 	}
 
 	// Reflect caption from params
 	if caption, ok := params["caption"].(string); ok {
-		msg["caption"] = caption
+		msg.Caption = caption
 	}
 
 	// Handle media types based on method context
 	if _, ok := params["photo"]; ok {
-		msg["photo"] = f.generatePhotoSizes()
+		msg.Photo = f.generatePhotoSizes()
 	}
 	if _, ok := params["document"]; ok {
-		msg["document"] = f.generateDocument(params)
+		msg.Document = f.generateDocument(params)
 	}
 	if _, ok := params["audio"]; ok {
-		msg["audio"] = f.generateAudio(params)
+		msg.Audio = f.generateAudio(params)
 	}
 	if _, ok := params["video"]; ok {
-		msg["video"] = f.generateVideo(params)
+		msg.Video = f.generateVideo(params)
 	}
 	if _, ok := params["voice"]; ok {
-		msg["voice"] = f.generateVoice(params)
+		msg.Voice = f.generateVoice(params)
 	}
 	if _, ok := params["animation"]; ok {
-		msg["animation"] = f.generateAnimation(params)
+		msg.Animation = f.generateAnimation(params)
 	}
 	if _, ok := params["sticker"]; ok {
-		msg["sticker"] = f.generateSticker(params)
+		msg.Sticker = f.generateSticker(params)
 	}
 	if _, ok := params["location"]; ok || params["latitude"] != nil {
-		msg["location"] = f.generateLocation(params)
+		msg.Location = f.generateLocation(params)
 	}
 	if _, ok := params["venue"]; ok {
-		msg["venue"] = f.generateVenue(params)
+		msg.Venue = f.generateVenue(params)
 	}
 	if _, ok := params["contact"]; ok {
-		msg["contact"] = f.generateContact(params)
+		msg.Contact = f.generateContact(params)
 	}
 	if _, ok := params["poll"]; ok {
-		msg["poll"] = f.generatePoll(params)
+		msg.Poll = f.generatePoll(params)
 	}
 	if _, ok := params["dice"]; ok {
-		msg["dice"] = f.generateDice(params)
+		msg.Dice = f.generateDice(params)
 	}
 
-	// Handle reply markup
-	if _, ok := params["reply_markup"]; ok {
-		// Reply markup is passed through but not generated
-	}
+	// Echo inline keyboards; other reply markup isn't part of a Message
+	msg.ReplyMarkup = inlineKeyboardMarkup(params["reply_markup"])
 
 	return msg
 }
 
-func (f *Faker) generateMessageId(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"message_id": f.NextMessageID(),
+func (f *Faker) generateMessageId(params map[string]interface{}) *gen.MessageId {
+	return &gen.MessageId{
+		MessageID: f.NextMessageID(),
 	}
 }
 
-func (f *Faker) generateFile(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateFile(params map[string]interface{}) *gen.File {
 	fileID := f.generateFileID()
 	if id, ok := params["file_id"].(string); ok {
 		fileID = id
@@ -266,33 +375,33 @@ func (f *Faker) generateFile(params map[string]interface{}) map[string]interface
 		uniqueID += fileID
 	}
 
-	return map[string]interface{}{
-		"file_id":        fileID,
-		"file_unique_id": uniqueID,
-		"file_size":      f.RandomInt64(1024, 1024*1024*10),
-		"file_path":      f.generateFilePath(),
+	return &gen.File{
+		FileID:       fileID,
+		FileUniqueID: uniqueID,
+		FileSize:     ptr(f.RandomInt64(1024, 1024*1024*10)),
+		FilePath:     f.generateFilePath(),
 	}
 }
 
-func (f *Faker) generateUpdate(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"update_id": f.NextUpdateID(),
-	}
+func (f *Faker) generateUpdate(params map[string]interface{}) *gen.Update {
+	// Updates without a requested kind are messages
+	update, _ := f.update("message", params)
+	return update
 }
 
 // Media type generators
 
-func (f *Faker) generatePhotoSize(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"width":          f.RandomInt64(100, 1920),
-		"height":         f.RandomInt64(100, 1080),
-		"file_size":      f.RandomInt64(1024, 1024*500),
+func (f *Faker) generatePhotoSize(params map[string]interface{}) *gen.PhotoSize {
+	return &gen.PhotoSize{
+		FileID:       f.generateFileID(),
+		FileUniqueID: f.generateFileID()[:20],
+		Width:        f.RandomInt64(100, 1920),
+		Height:       f.RandomInt64(100, 1080),
+		FileSize:     ptr(f.RandomInt64(1024, 1024*500)),
 	}
 }
 
-func (f *Faker) generatePhotoSizes() []map[string]interface{} {
+func (f *Faker) generatePhotoSizes() []gen.PhotoSize {
 	// Generate 3 sizes: small, medium, large
 	sizes := []struct {
 		w, h int64
@@ -302,158 +411,180 @@ func (f *Faker) generatePhotoSizes() []map[string]interface{} {
 		{800, 800},
 	}
 
-	result := make([]map[string]interface{}, len(sizes))
+	result := make([]gen.PhotoSize, len(sizes))
 	for i, size := range sizes {
-		result[i] = map[string]interface{}{
-			"file_id":        f.generateFileID(),
-			"file_unique_id": f.generateFileID()[:20],
-			"width":          size.w,
-			"height":         size.h,
-			"file_size":      f.RandomInt64(1024, 1024*100*(int64(i)+1)),
+		result[i] = gen.PhotoSize{
+			FileID:       f.generateFileID(),
+			FileUniqueID: f.generateFileID()[:20],
+			Width:        size.w,
+			Height:       size.h,
+			FileSize:     ptr(f.RandomInt64(1024, 1024*100*(int64(i)+1))),
 		}
 	}
 	return result
 }
 
-func (f *Faker) generateAudio(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"duration":       f.RandomInt64(30, 300),
-		"performer":      f.generateAuthor(),
-		"title":          f.generateTitle(),
-		"mime_type":      "audio/mpeg",
-		"file_size":      f.RandomInt64(1024*100, 1024*1024*10),
+func (f *Faker) generateAudio(params map[string]interface{}) *gen.Audio {
+	return &gen.Audio{
+		FileID:       f.generateFileID(),
+		FileUniqueID: f.generateFileID()[:20],
+		Duration:     f.RandomInt64(30, 300),
+		Performer:    f.generateAuthor(),
+		Title:        f.generateTitle(),
+		MimeType:     "audio/mpeg",
+		FileSize:     ptr(f.RandomInt64(1024*100, 1024*1024*10)),
 	}
 }
 
-func (f *Faker) generateDocument(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"file_name":      f.generateFilePath(),
-		"mime_type":      f.RandomChoice(mimeTypes),
-		"file_size":      f.RandomInt64(1024, 1024*1024*50),
+func (f *Faker) generateDocument(params map[string]interface{}) *gen.Document {
+	fileID := f.generateFileID()
+	fileUniqueID := f.generateFileID()[:20]
+	mimeType := f.RandomChoice(mimeTypes)
+	return &gen.Document{
+		FileID:       fileID,
+		FileUniqueID: fileUniqueID,
+		FileName:     fmt.Sprintf("document_%d.%s", f.rng.Intn(10000), mimeExtensions[mimeType]),
+		MimeType:     mimeType,
+		FileSize:     ptr(f.RandomInt64(1024, 1024*1024*50)),
 	}
 }
 
-func (f *Faker) generateVideo(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"width":          f.RandomInt64(320, 1920),
-		"height":         f.RandomInt64(240, 1080),
-		"duration":       f.RandomInt64(5, 600),
-		"mime_type":      "video/mp4",
-		"file_size":      f.RandomInt64(1024*100, 1024*1024*100),
+func (f *Faker) generateVideo(params map[string]interface{}) *gen.Video {
+	return &gen.Video{
+		FileID:       f.generateFileID(),
+		FileUniqueID: f.generateFileID()[:20],
+		Width:        f.RandomInt64(320, 1920),
+		Height:       f.RandomInt64(240, 1080),
+		Duration:     f.RandomInt64(5, 600),
+		MimeType:     "video/mp4",
+		FileSize:     ptr(f.RandomInt64(1024*100, 1024*1024*100)),
 	}
 }
 
-func (f *Faker) generateAnimation(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"width":          f.RandomInt64(100, 500),
-		"height":         f.RandomInt64(100, 500),
-		"duration":       f.RandomInt64(1, 10),
-		"mime_type":      "video/mp4",
-		"file_size":      f.RandomInt64(1024*10, 1024*1024*5),
+func (f *Faker) generateAnimation(params map[string]interface{}) *gen.Animation {
+	return &gen.Animation{
+		FileID:       f.generateFileID(),
+		FileUniqueID: f.generateFileID()[:20],
+		Width:        f.RandomInt64(100, 500),
+		Height:       f.RandomInt64(100, 500),
+		Duration:     f.RandomInt64(1, 10),
+		MimeType:     "video/mp4",
+		FileSize:     ptr(f.RandomInt64(1024*10, 1024*1024*5)),
 	}
 }
 
-func (f *Faker) generateVoice(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"duration":       f.RandomInt64(1, 120),
-		"mime_type":      "audio/ogg",
-		"file_size":      f.RandomInt64(1024, 1024*1024),
+func (f *Faker) generateVoice(params map[string]interface{}) *gen.Voice {
+	return &gen.Voice{
+		FileID:       f.generateFileID(),
+		FileUniqueID: f.generateFileID()[:20],
+		Duration:     f.RandomInt64(1, 120),
+		MimeType:     "audio/ogg",
+		FileSize:     ptr(f.RandomInt64(1024, 1024*1024)),
 	}
 }
 
-func (f *Faker) generateVideoNote(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateVideoNote(params map[string]interface{}) *gen.VideoNote {
 	length := f.RandomInt64(200, 500)
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"length":         length,
-		"duration":       f.RandomInt64(1, 60),
-		"file_size":      f.RandomInt64(1024*100, 1024*1024*10),
+	return &gen.VideoNote{
+		FileID:       f.generateFileID(),
+		FileUniqueID: f.generateFileID()[:20],
+		Length:       length,
+		Duration:     f.RandomInt64(1, 60),
+		FileSize:     ptr(f.RandomInt64(1024*100, 1024*1024*10)),
 	}
 }
 
-func (f *Faker) generateSticker(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"file_id":        f.generateFileID(),
-		"file_unique_id": f.generateFileID()[:20],
-		"type":           f.RandomChoice([]string{"regular", "mask", "custom_emoji"}),
-		"width":          int64(512),
-		"height":         int64(512),
-		"is_animated":    f.RandomBool(0.3),
-		"is_video":       f.RandomBool(0.2),
-		"file_size":      f.RandomInt64(1024*10, 1024*100),
+func (f *Faker) generateSticker(params map[string]interface{}) *gen.Sticker {
+	fileID := f.generateFileID()
+	fileUniqueID := f.generateFileID()[:20]
+	stickerType := f.RandomChoice([]string{"regular", "mask", "custom_emoji"})
+	// A sticker is either animated (TGS), a video (WebM) or static (WebP)
+	animated := f.RandomBool(0.3)
+	video := f.RandomBool(0.2) && !animated
+	return &gen.Sticker{
+		FileID:       fileID,
+		FileUniqueID: fileUniqueID,
+		Type:         stickerType,
+		Width:        512,
+		Height:       512,
+		IsAnimated:   animated,
+		IsVideo:      video,
+		FileSize:     ptr(f.RandomInt64(1024*10, 1024*100)),
 	}
 }
 
-func (f *Faker) generateContact(params map[string]interface{}) map[string]interface{} {
-	contact := map[string]interface{}{
-		"phone_number": f.generatePhoneNumber(),
-		"first_name":   f.RandomChoice(firstNames),
+func (f *Faker) generateContact(params map[string]interface{}) *gen.Contact {
+	loc := f.locale()
+	contact := &gen.Contact{
+		PhoneNumber: f.generatePhoneNumber(),
+		FirstName:   f.RandomChoice(loc.firstNames),
 	}
 	if f.RandomBool(0.7) {
-		contact["last_name"] = f.RandomChoice(lastNames)
+		contact.LastName = f.RandomChoice(loc.lastNames)
 	}
 	if f.RandomBool(0.5) {
-		contact["user_id"] = f.RandomInt64(100000000, 999999999)
+		contact.UserID = ptr(f.RandomInt64(100000000, 999999999))
 	}
 	return contact
 }
 
-func (f *Faker) generateLocation(params map[string]interface{}) map[string]interface{} {
-	loc := map[string]interface{}{
-		"latitude":  f.RandomFloat64(-90, 90),
-		"longitude": f.RandomFloat64(-180, 180),
+func (f *Faker) generateLocation(params map[string]interface{}) *gen.Location {
+	loc := &gen.Location{
+		Latitude:  f.RandomFloat64(-90, 90),
+		Longitude: f.RandomFloat64(-180, 180),
 	}
 
 	// Use provided coordinates if available
 	if lat, ok := params["latitude"].(float64); ok {
-		loc["latitude"] = lat
+		loc.Latitude = lat
 	}
 	if lon, ok := params["longitude"].(float64); ok {
-		loc["longitude"] = lon
+		loc.Longitude = lon
 	}
 
 	if f.RandomBool(0.3) {
-		loc["horizontal_accuracy"] = f.RandomFloat64(0, 100)
+		loc.HorizontalAccuracy = ptr(f.RandomFloat64(0, 100))
 	}
 
 	return loc
 }
 
-func (f *Faker) generateVenue(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"location":        f.generateLocation(params),
-		"title":           f.generateTitle(),
-		"address":         f.generateText(),
-		"foursquare_id":   f.generateFileID()[:24],
-		"foursquare_type": "food/restaurant",
+func (f *Faker) generateVenue(params map[string]interface{}) *gen.Venue {
+	return &gen.Venue{
+		Location:       *f.generateLocation(params),
+		Title:          f.generateTitle(),
+		Address:        f.generateText(),
+		FoursquareID:   f.generateFileID()[:24],
+		FoursquareType: "food/restaurant",
 	}
 }
 
-func (f *Faker) generatePoll(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"id":                    f.generateFileID()[:17],
-		"question":              f.generateText(),
-		"options":               []map[string]interface{}{},
-		"total_voter_count":     f.RandomInt64(0, 100),
-		"is_closed":             false,
-		"is_anonymous":          true,
-		"type":                  "regular",
-		"allows_multiple_answers": false,
+func (f *Faker) generatePoll(params map[string]interface{}) *gen.Poll {
+	return &gen.Poll{
+		ID:                    f.generateFileID()[:17],
+		Question:              f.generateText(),
+		Options:               []gen.PollOption{},
+		TotalVoterCount:       f.RandomInt64(0, 100),
+		IsClosed:              false,
+		IsAnonymous:           true,
+		Type:                  "regular",
+		AllowsMultipleAnswers: false,
 	}
 }
 
-func (f *Faker) generateDice(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generatePollAnswer(params map[string]interface{}) *gen.PollAnswer {
+	answer := &gen.PollAnswer{
+		PollID:    f.generateFileID()[:17],
+		User:      f.generateUser(params),
+		OptionIds: []int64{f.RandomInt64(0, 4)},
+	}
+	if id, ok := params["poll_id"].(string); ok {
+		answer.PollID = id
+	}
+	return answer
+}
+
+func (f *Faker) generateDice(params map[string]interface{}) *gen.Dice {
 	emoji := "🎲"
 	if e, ok := params["emoji"].(string); ok {
 		emoji = e
@@ -473,207 +604,962 @@ func (f *Faker) generateDice(params map[string]interface{}) map[string]interface
 		maxValue = 6
 	}
 
-	return map[string]interface{}{
-		"emoji": emoji,
-		"value": f.RandomInt64(1, maxValue+1),
+	return &gen.Dice{
+		Emoji: emoji,
+		Value: f.RandomInt64(1, maxValue+1),
 	}
 }
 
 // Chat member generators
 
-func (f *Faker) generateChatMember(params map[string]interface{}) map[string]interface{} {
+func (f *Faker) generateChatMember(params map[string]interface{}) gen.ChatMember {
 	// Default to regular member
 	return f.generateChatMemberMember(params)
 }
 
-func (f *Faker) generateChatMemberOwner(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"status":       "creator",
-		"user":         f.generateUser(params),
-		"is_anonymous": f.RandomBool(0.2),
+func (f *Faker) generateChatMemberOwner(params map[string]interface{}) *gen.ChatMemberOwner {
+	return &gen.ChatMemberOwner{
+		Status:      "creator",
+		User:        *f.generateUser(params),
+		IsAnonymous: f.RandomBool(0.2),
+	}
+}
+
+func (f *Faker) generateChatMemberAdministrator(params map[string]interface{}) *gen.ChatMemberAdministrator {
+	return &gen.ChatMemberAdministrator{
+		Status:              "administrator",
+		User:                *f.generateUser(params),
+		CanBeEdited:         true,
+		IsAnonymous:         f.RandomBool(0.2),
+		CanManageChat:       true,
+		CanDeleteMessages:   true,
+		CanManageVideoChats: true,
+		CanRestrictMembers:  true,
+		CanPromoteMembers:   f.RandomBool(0.5),
+		CanChangeInfo:       true,
+		CanInviteUsers:      true,
+		CanPostMessages:     ptr(true),
+		CanEditMessages:     ptr(true),
+		CanPinMessages:      ptr(true),
 	}
 }
 
-func (f *Faker) generateChatMemberAdministrator(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"status":                "administrator",
-		"user":                  f.generateUser(params),
-		"can_be_edited":         true,
-		"is_anonymous":          f.RandomBool(0.2),
-		"can_manage_chat":       true,
-		"can_delete_messages":   true,
-		"can_manage_video_chats": true,
-		"can_restrict_members":  true,
-		"can_promote_members":   f.RandomBool(0.5),
-		"can_change_info":       true,
-		"can_invite_users":      true,
-		"can_post_messages":     true,
-		"can_edit_messages":     true,
-		"can_pin_messages":      true,
+func (f *Faker) generateChatMemberMember(params map[string]interface{}) *gen.ChatMemberMember {
+	return &gen.ChatMemberMember{
+		Status: "member",
+		User:   *f.generateUser(params),
 	}
 }
 
-func (f *Faker) generateChatMemberMember(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"status": "member",
-		"user":   f.generateUser(params),
+func (f *Faker) generateChatMemberRestricted(params map[string]interface{}) *gen.ChatMemberRestricted {
+	member := &gen.ChatMemberRestricted{
+		Status:                "restricted",
+		User:                  *f.generateUser(params),
+		IsMember:              true,
+		CanSendMessages:       f.RandomBool(0.5),
+		CanSendAudios:         f.RandomBool(0.5),
+		CanSendDocuments:      f.RandomBool(0.5),
+		CanSendPhotos:         f.RandomBool(0.5),
+		CanSendVideos:         f.RandomBool(0.5),
+		CanSendVideoNotes:     f.RandomBool(0.5),
+		CanSendVoiceNotes:     f.RandomBool(0.5),
+		CanSendPolls:          f.RandomBool(0.5),
+		CanSendOtherMessages:  f.RandomBool(0.5),
+		CanAddWebPagePreviews: f.RandomBool(0.5),
+		CanInviteUsers:        f.RandomBool(0.5),
+	}
+	// 0 restricts forever
+	if f.RandomBool(0.5) {
+		member.UntilDate = f.Now().Unix() + f.RandomInt64(3600, 30*86400)
 	}
+	return member
 }
 
-func (f *Faker) generateChatInviteLink(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"invite_link":          "https://t.me/+" + f.generateFileID()[:16],
-		"creator":              f.generateUser(params),
-		"creates_join_request": f.RandomBool(0.3),
-		"is_primary":           f.RandomBool(0.5),
-		"is_revoked":           false,
+func (f *Faker) generateChatMemberLeft(params map[string]interface{}) *gen.ChatMemberLeft {
+	return &gen.ChatMemberLeft{
+		Status: "left",
+		User:   *f.generateUser(params),
 	}
 }
 
-func (f *Faker) generateChatPhoto(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"small_file_id":        f.generateFileID(),
-		"small_file_unique_id": f.generateFileID()[:20],
-		"big_file_id":          f.generateFileID(),
-		"big_file_unique_id":   f.generateFileID()[:20],
+func (f *Faker) generateChatMemberBanned(params map[string]interface{}) *gen.ChatMemberBanned {
+	member := &gen.ChatMemberBanned{
+		Status: "kicked",
+		User:   *f.generateUser(params),
+	}
+	// 0 bans forever
+	if f.RandomBool(0.5) {
+		member.UntilDate = f.Now().Unix() + f.RandomInt64(3600, 30*86400)
 	}
+	return member
 }
 
-func (f *Faker) generateChatPermissions(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"can_send_messages":         true,
-		"can_send_audios":           true,
-		"can_send_documents":        true,
-		"can_send_photos":           true,
-		"can_send_videos":           true,
-		"can_send_video_notes":      true,
-		"can_send_voice_notes":      true,
-		"can_send_polls":            true,
-		"can_send_other_messages":   true,
-		"can_add_web_page_previews": true,
-		"can_change_info":           f.RandomBool(0.5),
-		"can_invite_users":          true,
-		"can_pin_messages":          f.RandomBool(0.5),
+func (f *Faker) generateChatMemberUpdated(params map[string]interface{}) *gen.ChatMemberUpdated {
+	// A user joining the chat
+	member := f.generateUser(params)
+	return &gen.ChatMemberUpdated{
+		Chat:          *f.generateChat(params),
+		From:          *member,
+		Date:          f.Now().Unix(),
+		OldChatMember: &gen.ChatMemberLeft{Status: "left", User: *member},
+		NewChatMember: &gen.ChatMemberMember{Status: "member", User: *member},
+	}
+}
+
+func (f *Faker) generateChatJoinRequest(params map[string]interface{}) *gen.ChatJoinRequest {
+	user := f.generateUser(params)
+	request := &gen.ChatJoinRequest{
+		Chat:       *f.generateChat(params),
+		From:       *user,
+		UserChatID: user.ID,
+		Date:       f.Now().Unix(),
+	}
+	if f.RandomBool(0.4) {
+		request.Bio = f.generateText()
+	}
+	return request
+}
+
+func (f *Faker) generateChatAdministratorRights(params map[string]interface{}) *gen.ChatAdministratorRights {
+	return &gen.ChatAdministratorRights{
+		CanManageChat:       true,
+		CanDeleteMessages:   true,
+		CanManageVideoChats: true,
+		CanRestrictMembers:  true,
+		CanPromoteMembers:   f.RandomBool(0.5),
+		CanChangeInfo:       true,
+		CanInviteUsers:      true,
+		CanPostStories:      f.RandomBool(0.5),
+		CanEditStories:      f.RandomBool(0.5),
+		CanDeleteStories:    f.RandomBool(0.5),
+		CanPinMessages:      ptr(true),
+	}
+}
+
+func (f *Faker) generateChatInviteLink(params map[string]interface{}) *gen.ChatInviteLink {
+	return &gen.ChatInviteLink{
+		InviteLink:         "https://t.me/+" + f.generateFileID()[:16],
+		Creator:            *f.generateUser(params),
+		CreatesJoinRequest: f.RandomBool(0.3),
+		IsPrimary:          f.RandomBool(0.5),
+		IsRevoked:          false,
+	}
+}
+
+func (f *Faker) generateChatPhoto(params map[string]interface{}) *gen.ChatPhoto {
+	return &gen.ChatPhoto{
+		SmallFileID:       f.generateFileID(),
+		SmallFileUniqueID: f.generateFileID()[:20],
+		BigFileID:         f.generateFileID(),
+		BigFileUniqueID:   f.generateFileID()[:20],
+	}
+}
+
+func (f *Faker) generateChatPermissions(params map[string]interface{}) *gen.ChatPermissions {
+	return &gen.ChatPermissions{
+		CanSendMessages:       ptr(true),
+		CanSendAudios:         ptr(true),
+		CanSendDocuments:      ptr(true),
+		CanSendPhotos:         ptr(true),
+		CanSendVideos:         ptr(true),
+		CanSendVideoNotes:     ptr(true),
+		CanSendVoiceNotes:     ptr(true),
+		CanSendPolls:          ptr(true),
+		CanSendOtherMessages:  ptr(true),
+		CanAddWebPagePreviews: ptr(true),
+		CanChangeInfo:         ptr(f.RandomBool(0.5)),
+		CanInviteUsers:        ptr(true),
+		CanPinMessages:        ptr(f.RandomBool(0.5)),
 	}
 }
 
 // Inline type generators
 
-func (f *Faker) generateInlineQuery(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"id":     f.generateFileID()[:20],
-		"from":   f.generateUser(params),
-		"query":  f.generateQuery(),
-		"offset": "",
+func (f *Faker) generateInlineQuery(params map[string]interface{}) *gen.InlineQuery {
+	return &gen.InlineQuery{
+		ID:     f.generateFileID()[:20],
+		From:   *f.generateUser(params),
+		Query:  f.generateQuery(),
+		Offset: "",
 	}
 }
 
-func (f *Faker) generateChosenInlineResult(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"result_id":         f.generateFileID()[:20],
-		"from":              f.generateUser(params),
-		"query":             f.generateQuery(),
-		"inline_message_id": f.generateFileID()[:30],
+func (f *Faker) generateChosenInlineResult(params map[string]interface{}) *gen.ChosenInlineResult {
+	return &gen.ChosenInlineResult{
+		ResultID:        f.generateFileID()[:20],
+		From:            *f.generateUser(params),
+		Query:           f.generateQuery(),
+		InlineMessageID: f.generateFileID()[:30],
 	}
 }
 
-func (f *Faker) generateCallbackQuery(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"id":            f.generateFileID()[:20],
-		"from":          f.generateUser(params),
-		"chat_instance": f.generateFileID()[:15],
-		"data":          f.generateString("callback_data"),
+func (f *Faker) generateCallbackQuery(params map[string]interface{}) *gen.CallbackQuery {
+	return &gen.CallbackQuery{
+		ID:           f.generateFileID()[:20],
+		From:         *f.generateUser(params),
+		ChatInstance: f.generateFileID()[:15],
+		Data:         f.generateString("callback_data"),
 	}
 }
 
 // Keyboard generators
 
-func (f *Faker) generateInlineKeyboardMarkup(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"inline_keyboard": [][]map[string]interface{}{},
+func (f *Faker) generateInlineKeyboardMarkup(params map[string]interface{}) *gen.InlineKeyboardMarkup {
+	return &gen.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gen.InlineKeyboardButton{},
+	}
+}
+
+// inlineKeyboardMarkup returns the reply_markup parameter if it is an
+// InlineKeyboardMarkup. Form and query parameters carry it as a JSON string.
+func inlineKeyboardMarkup(v interface{}) *gen.InlineKeyboardMarkup {
+	data, ok := v.(string)
+	if !ok {
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		data = string(encoded)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(data), &fields); err != nil {
+		return nil
 	}
+	if _, ok := fields["inline_keyboard"]; !ok {
+		return nil
+	}
+	var markup gen.InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(data), &markup); err != nil {
+		return nil
+	}
+	return &markup
 }
 
-func (f *Faker) generateInlineKeyboardButton(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"text":          "Button",
-		"callback_data": f.generateString("callback_data"),
+func (f *Faker) generateInlineKeyboardButton(params map[string]interface{}) *gen.InlineKeyboardButton {
+	return &gen.InlineKeyboardButton{
+		Text:         "Button",
+		CallbackData: f.generateString("callback_data"),
 	}
 }
 
-func (f *Faker) generateReplyKeyboardMarkup(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"keyboard":        [][]map[string]interface{}{},
-		"resize_keyboard": true,
+func (f *Faker) generateReplyKeyboardMarkup(params map[string]interface{}) *gen.ReplyKeyboardMarkup {
+	return &gen.ReplyKeyboardMarkup{
+		Keyboard:       [][]gen.KeyboardButton{},
+		ResizeKeyboard: ptr(true),
 	}
 }
 
-func (f *Faker) generateKeyboardButton(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"text": "Button",
+func (f *Faker) generateKeyboardButton(params map[string]interface{}) *gen.KeyboardButton {
+	return &gen.KeyboardButton{
+		Text: "Button",
 	}
 }
 
 // Other generators
 
-func (f *Faker) generateWebhookInfo(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"url":                    "",
-		"has_custom_certificate": false,
-		"pending_update_count":   int64(0),
+func (f *Faker) generateWebhookInfo(params map[string]interface{}) *gen.WebhookInfo {
+	return &gen.WebhookInfo{
+		URL:                  "",
+		HasCustomCertificate: false,
+		PendingUpdateCount:   0,
+	}
+}
+
+func (f *Faker) generateBotCommand(params map[string]interface{}) *gen.BotCommand {
+	return &gen.BotCommand{
+		Command:     f.RandomChoice(commands),
+		Description: f.generateText(),
+	}
+}
+
+func (f *Faker) generateBotDescription(params map[string]interface{}) *gen.BotDescription {
+	return &gen.BotDescription{
+		Description: f.generateText(),
+	}
+}
+
+func (f *Faker) generateBotName(params map[string]interface{}) *gen.BotName {
+	return &gen.BotName{
+		Name: f.generateTitle(),
+	}
+}
+
+func (f *Faker) generateBotShortDescription(params map[string]interface{}) *gen.BotShortDescription {
+	return &gen.BotShortDescription{
+		ShortDescription: f.generateText(),
+	}
+}
+
+func (f *Faker) generateMessageEntity(params map[string]interface{}) *gen.MessageEntity {
+	return &gen.MessageEntity{
+		Type:   f.RandomChoice([]string{"bold", "italic", "code", "mention", "hashtag", "url"}),
+		Offset: 0,
+		Length: f.RandomInt64(1, 20),
+	}
+}
+
+func (f *Faker) generateUserProfilePhotos(params map[string]interface{}) *gen.UserProfilePhotos {
+	return &gen.UserProfilePhotos{
+		TotalCount: 1,
+		Photos:     [][]gen.PhotoSize{f.generatePhotoSizes()},
+	}
+}
+
+func (f *Faker) generateForumTopic(params map[string]interface{}) *gen.ForumTopic {
+	return &gen.ForumTopic{
+		MessageThreadID: f.RandomInt64(1, 10000),
+		Name:            f.generateTitle(),
+		IconColor:       f.RandomInt64(0, 16777215),
+	}
+}
+
+func (f *Faker) generateSentWebAppMessage(params map[string]interface{}) *gen.SentWebAppMessage {
+	return &gen.SentWebAppMessage{
+		InlineMessageID: f.generateFileID()[:30],
+	}
+}
+
+// Payment type generators
+
+func (f *Faker) generateStarAmount(params map[string]interface{}) *gen.StarAmount {
+	return &gen.StarAmount{
+		Amount: f.RandomInt64(0, 100000),
+	}
+}
+
+// invoicePayload returns the invoice_payload of params, or a generated one.
+func (f *Faker) invoicePayload(params map[string]interface{}) string {
+	if payload, ok := params["invoice_payload"].(string); ok {
+		return payload
+	}
+	return "payload_" + f.generateFileID()[:12]
+}
+
+func (f *Faker) generateShippingQuery(params map[string]interface{}) *gen.ShippingQuery {
+	return &gen.ShippingQuery{
+		ID:              f.generateFileID()[:20],
+		From:            *f.generateUser(params),
+		InvoicePayload:  f.invoicePayload(params),
+		ShippingAddress: *f.generateShippingAddress(params),
+	}
+}
+
+func (f *Faker) generateShippingAddress(params map[string]interface{}) *gen.ShippingAddress {
+	return &gen.ShippingAddress{
+		CountryCode: f.RandomChoice(countryCodes),
+		City:        f.RandomChoice(titleNouns) + " City",
+		StreetLine1: fmt.Sprintf("%d %s Street", f.RandomInt64(1, 200), f.RandomChoice(lastNames)),
+		PostCode:    fmt.Sprintf("%05d", f.RandomInt64(10000, 99999)),
+	}
+}
+
+func (f *Faker) generatePreCheckoutQuery(params map[string]interface{}) *gen.PreCheckoutQuery {
+	query := &gen.PreCheckoutQuery{
+		ID:             f.generateFileID()[:20],
+		From:           *f.generateUser(params),
+		Currency:       f.RandomChoice(currencies),
+		TotalAmount:    f.RandomInt64(100, 100000),
+		InvoicePayload: f.invoicePayload(params),
+	}
+	if currency, ok := params["currency"].(string); ok {
+		query.Currency = currency
+	}
+	return query
+}
+
+func (f *Faker) generatePaidMediaPurchased(params map[string]interface{}) *gen.PaidMediaPurchased {
+	payload := "media_" + f.generateFileID()[:12]
+	if p, ok := params["paid_media_payload"].(string); ok {
+		payload = p
+	}
+	return &gen.PaidMediaPurchased{
+		From:             *f.generateUser(params),
+		PaidMediaPayload: payload,
+	}
+}
+
+func (f *Faker) generateStarTransactions(params map[string]interface{}) *gen.StarTransactions {
+	size := int(f.RandomInt64(1, 4))
+	transactions := make([]gen.StarTransaction, size)
+	date := f.Now().Unix() - f.RandomInt64(86400, 30*86400)
+	for i := range transactions {
+		tx := f.generateStarTransaction(params)
+		date += f.RandomInt64(60, 86400)
+		tx.Date = date
+		transactions[i] = *tx
+	}
+	return &gen.StarTransactions{
+		Transactions: transactions,
+	}
+}
+
+func (f *Faker) generateStarTransaction(params map[string]interface{}) *gen.StarTransaction {
+	tx := &gen.StarTransaction{
+		ID:     "stxn_" + f.generateFileID()[:24],
+		Amount: f.RandomInt64(1, 2500),
+		Date:   f.Now().Unix() - f.RandomInt64(0, 30*86400),
+	}
+	// Most transactions are payments from users; the rest leave the bot
+	if f.RandomBool(0.7) {
+		tx.Source = f.generateTransactionPartnerUser(params)
+	} else {
+		tx.Receiver = f.generateTransactionPartner(params)
+	}
+	return tx
+}
+
+func (f *Faker) generateTransactionPartner(params map[string]interface{}) gen.TransactionPartner {
+	switch f.RandomChoice([]string{"user", "user", "chat", "affiliate_program", "fragment", "telegram_ads", "telegram_api", "other"}) {
+	case "user":
+		return f.generateTransactionPartnerUser(params)
+	case "chat":
+		return f.generateTransactionPartnerChat(params)
+	case "affiliate_program":
+		return f.generateTransactionPartnerAffiliateProgram(params)
+	case "fragment":
+		return f.generateTransactionPartnerFragment(params)
+	case "telegram_ads":
+		return f.generateTransactionPartnerTelegramAds(params)
+	case "telegram_api":
+		return f.generateTransactionPartnerTelegramApi(params)
+	default:
+		return f.generateTransactionPartnerOther(params)
+	}
+}
+
+func (f *Faker) generateTransactionPartnerUser(params map[string]interface{}) *gen.TransactionPartnerUser {
+	partner := &gen.TransactionPartnerUser{
+		Type:            "user",
+		TransactionType: f.RandomChoice([]string{"invoice_payment", "invoice_payment", "paid_media_payment", "gift_purchase"}),
+		User:            *f.generateUser(params),
+	}
+	if partner.TransactionType == "invoice_payment" {
+		partner.InvoicePayload = "payload_" + f.generateFileID()[:12]
+		if f.RandomBool(0.2) {
+			partner.SubscriptionPeriod = ptr(int64(2592000))
+		}
+	}
+	return partner
+}
+
+func (f *Faker) generateTransactionPartnerChat(params map[string]interface{}) *gen.TransactionPartnerChat {
+	return &gen.TransactionPartnerChat{
+		Type: "chat",
+		Chat: *f.generateChat(map[string]interface{}{"chat_id": -1000000000000 - f.RandomInt64(1, 999999999)}),
+	}
+}
+
+func (f *Faker) generateTransactionPartnerAffiliateProgram(params map[string]interface{}) *gen.TransactionPartnerAffiliateProgram {
+	partner := &gen.TransactionPartnerAffiliateProgram{
+		Type:               "affiliate_program",
+		CommissionPerMille: f.RandomInt64(1, 1000),
+	}
+	if f.RandomBool(0.5) {
+		partner.SponsorUser = f.generateUser(nil)
+	}
+	return partner
+}
+
+func (f *Faker) generateTransactionPartnerFragment(params map[string]interface{}) *gen.TransactionPartnerFragment {
+	return &gen.TransactionPartnerFragment{
+		Type:            "fragment",
+		WithdrawalState: f.generateRevenueWithdrawalState(params),
+	}
+}
+
+func (f *Faker) generateTransactionPartnerTelegramAds(params map[string]interface{}) *gen.TransactionPartnerTelegramAds {
+	return &gen.TransactionPartnerTelegramAds{
+		Type: "telegram_ads",
+	}
+}
+
+func (f *Faker) generateTransactionPartnerTelegramApi(params map[string]interface{}) *gen.TransactionPartnerTelegramApi {
+	return &gen.TransactionPartnerTelegramApi{
+		Type:         "telegram_api",
+		RequestCount: f.RandomInt64(1, 10000),
+	}
+}
+
+func (f *Faker) generateTransactionPartnerOther(params map[string]interface{}) *gen.TransactionPartnerOther {
+	return &gen.TransactionPartnerOther{
+		Type: "other",
+	}
+}
+
+func (f *Faker) generateRevenueWithdrawalState(params map[string]interface{}) gen.RevenueWithdrawalState {
+	switch f.RandomChoice([]string{"pending", "succeeded", "failed"}) {
+	case "succeeded":
+		return &gen.RevenueWithdrawalStateSucceeded{
+			Type: "succeeded",
+			Date: f.Now().Unix() - f.RandomInt64(0, 30*86400),
+			URL:  "https://fragment.com/tx/" + f.generateFileID()[:16],
+		}
+	case "failed":
+		return &gen.RevenueWithdrawalStateFailed{Type: "failed"}
+	default:
+		return &gen.RevenueWithdrawalStatePending{Type: "pending"}
+	}
+}
+
+// Boost type generators
+
+func (f *Faker) generateUserChatBoosts(params map[string]interface{}) *gen.UserChatBoosts {
+	boosts := make([]gen.ChatBoost, f.RandomInt64(0, 3))
+	for i := range boosts {
+		boost := f.generateChatBoost(params)
+		boost.Source = f.generateChatBoostSourcePremium(params)
+		boosts[i] = *boost
+	}
+	return &gen.UserChatBoosts{
+		Boosts: boosts,
+	}
+}
+
+func (f *Faker) generateChatBoost(params map[string]interface{}) *gen.ChatBoost {
+	added := f.Now().Unix() - f.RandomInt64(0, 30*86400)
+	return &gen.ChatBoost{
+		BoostID:        f.generateFileID()[:16],
+		AddDate:        added,
+		ExpirationDate: added + 30*86400,
+		Source:         f.generateChatBoostSource(params),
 	}
 }
 
-func (f *Faker) generateBotCommand(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"command":     f.RandomChoice(commands),
-		"description": f.generateText(),
+func (f *Faker) generateChatBoostSource(params map[string]interface{}) gen.ChatBoostSource {
+	switch f.RandomChoice([]string{"premium", "premium", "gift_code", "giveaway"}) {
+	case "gift_code":
+		return f.generateChatBoostSourceGiftCode(params)
+	case "giveaway":
+		return f.generateChatBoostSourceGiveaway(params)
+	default:
+		return f.generateChatBoostSourcePremium(params)
 	}
 }
 
-func (f *Faker) generateBotDescription(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"description": f.generateText(),
+func (f *Faker) generateChatBoostSourcePremium(params map[string]interface{}) *gen.ChatBoostSourcePremium {
+	return &gen.ChatBoostSourcePremium{
+		Source: "premium",
+		User:   *f.generateUser(params),
 	}
 }
 
-func (f *Faker) generateBotName(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"name": f.generateTitle(),
+func (f *Faker) generateChatBoostSourceGiftCode(params map[string]interface{}) *gen.ChatBoostSourceGiftCode {
+	return &gen.ChatBoostSourceGiftCode{
+		Source: "gift_code",
+		User:   *f.generateUser(params),
 	}
 }
 
-func (f *Faker) generateBotShortDescription(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"short_description": f.generateText(),
+func (f *Faker) generateChatBoostSourceGiveaway(params map[string]interface{}) *gen.ChatBoostSourceGiveaway {
+	source := &gen.ChatBoostSourceGiveaway{
+		Source:            "giveaway",
+		GiveawayMessageID: f.RandomInt64(1, 100000),
+	}
+	// Unclaimed prizes have no user
+	if f.RandomBool(0.8) {
+		source.User = f.generateUser(params)
+	} else {
+		source.IsUnclaimed = ptr(true)
 	}
+	return source
 }
 
-func (f *Faker) generateMessageEntity(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"type":   f.RandomChoice([]string{"bold", "italic", "code", "mention", "hashtag", "url"}),
-		"offset": int64(0),
-		"length": f.RandomInt64(1, 20),
+func (f *Faker) generateChatBoostUpdated(params map[string]interface{}) *gen.ChatBoostUpdated {
+	return &gen.ChatBoostUpdated{
+		Chat:  *f.generateChat(params),
+		Boost: *f.generateChatBoost(params),
 	}
 }
 
-func (f *Faker) generateUserProfilePhotos(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"total_count": int64(1),
-		"photos":      [][]map[string]interface{}{f.generatePhotoSizes()},
+func (f *Faker) generateChatBoostRemoved(params map[string]interface{}) *gen.ChatBoostRemoved {
+	return &gen.ChatBoostRemoved{
+		Chat:       *f.generateChat(params),
+		BoostID:    f.generateFileID()[:16],
+		RemoveDate: f.Now().Unix(),
+		Source:     f.generateChatBoostSource(params),
 	}
 }
 
-func (f *Faker) generateForumTopic(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"message_thread_id": f.RandomInt64(1, 10000),
-		"name":              f.generateTitle(),
-		"icon_color":        f.RandomInt64(0, 16777215),
+func (f *Faker) generateChatBoostAdded(params map[string]interface{}) *gen.ChatBoostAdded {
+	return &gen.ChatBoostAdded{
+		BoostCount: f.RandomInt64(1, 10),
+	}
+}
+
+// channelChat generates a channel, for the types that only come from one.
+func (f *Faker) channelChat() *gen.Chat {
+	return f.generateChat(map[string]interface{}{"chat_id": -1000000000000 - f.RandomInt64(1, 999999999)})
+}
+
+// Message metadata generators
+
+func (f *Faker) generateMessageOrigin(params map[string]interface{}) gen.MessageOrigin {
+	switch f.RandomChoice([]string{"user", "user", "hidden_user", "chat", "channel"}) {
+	case "hidden_user":
+		return f.generateMessageOriginHiddenUser(params)
+	case "chat":
+		return f.generateMessageOriginChat(params)
+	case "channel":
+		return f.generateMessageOriginChannel(params)
+	default:
+		return f.generateMessageOriginUser(params)
+	}
+}
+
+func (f *Faker) generateMessageOriginUser(params map[string]interface{}) *gen.MessageOriginUser {
+	return &gen.MessageOriginUser{
+		Type:       "user",
+		Date:       f.Now().Unix() - f.RandomInt64(60, 30*86400),
+		SenderUser: *f.generateUser(nil),
+	}
+}
+
+func (f *Faker) generateMessageOriginHiddenUser(params map[string]interface{}) *gen.MessageOriginHiddenUser {
+	return &gen.MessageOriginHiddenUser{
+		Type:           "hidden_user",
+		Date:           f.Now().Unix() - f.RandomInt64(60, 30*86400),
+		SenderUserName: f.generateAuthor(),
+	}
+}
+
+func (f *Faker) generateMessageOriginChat(params map[string]interface{}) *gen.MessageOriginChat {
+	return &gen.MessageOriginChat{
+		Type:       "chat",
+		Date:       f.Now().Unix() - f.RandomInt64(60, 30*86400),
+		SenderChat: *f.generateChat(map[string]interface{}{"chat_id": -f.RandomInt64(1, 999999999)}),
+	}
+}
+
+func (f *Faker) generateMessageOriginChannel(params map[string]interface{}) *gen.MessageOriginChannel {
+	origin := &gen.MessageOriginChannel{
+		Type:      "channel",
+		Date:      f.Now().Unix() - f.RandomInt64(60, 30*86400),
+		Chat:      *f.channelChat(),
+		MessageID: f.RandomInt64(1, 100000),
+	}
+	if f.RandomBool(0.3) {
+		origin.AuthorSignature = f.generateAuthor()
+	}
+	return origin
+}
+
+func (f *Faker) generateExternalReplyInfo(params map[string]interface{}) *gen.ExternalReplyInfo {
+	info := &gen.ExternalReplyInfo{
+		Origin: f.generateMessageOrigin(params),
+	}
+	// Only messages from channels and supergroups name their chat and ID
+	if origin, ok := info.Origin.(*gen.MessageOriginChannel); ok {
+		info.Chat = &origin.Chat
+		info.MessageID = ptr(origin.MessageID)
+	}
+	if f.RandomBool(0.5) {
+		info.Photo = f.generatePhotoSizes()
+	}
+	return info
+}
+
+func (f *Faker) generateTextQuote(params map[string]interface{}) *gen.TextQuote {
+	quote := &gen.TextQuote{
+		Text:     f.generateText(),
+		Position: f.RandomInt64(0, 100),
+	}
+	if text, ok := params["quote"].(string); ok {
+		quote.Text = text
+		quote.IsManual = ptr(true)
+	}
+	return quote
+}
+
+func (f *Faker) generateLinkPreviewOptions(params map[string]interface{}) *gen.LinkPreviewOptions {
+	if f.RandomBool(0.2) {
+		return &gen.LinkPreviewOptions{IsDisabled: ptr(true)}
+	}
+	options := &gen.LinkPreviewOptions{
+		URL: f.generateURL(),
+	}
+	if f.RandomBool(0.3) {
+		options.PreferLargeMedia = ptr(true)
+	}
+	if f.RandomBool(0.2) {
+		options.ShowAboveText = ptr(true)
+	}
+	return options
+}
+
+func (f *Faker) generateInaccessibleMessage(params map[string]interface{}) *gen.InaccessibleMessage {
+	// Date is always 0, which tells it apart from a Message
+	return &gen.InaccessibleMessage{
+		Chat:      *f.generateChat(params),
+		MessageID: f.RandomInt64(1, 100000),
+	}
+}
+
+// Reaction type generators
+
+// reactionEmojis are emoji Telegram accepts as reactions.
+var reactionEmojis = []string{
+	"👍", "👎", "❤", "🔥", "🥰", "👏", "😁", "🤔", "🎉", "🤩", "🙏", "👌",
+}
+
+func (f *Faker) generateReactionType(params map[string]interface{}) gen.ReactionType {
+	switch f.RandomChoice([]string{"emoji", "emoji", "emoji", "custom_emoji", "paid"}) {
+	case "custom_emoji":
+		return f.generateReactionTypeCustomEmoji(params)
+	case "paid":
+		return f.generateReactionTypePaid(params)
+	default:
+		return f.generateReactionTypeEmoji(params)
+	}
+}
+
+func (f *Faker) generateReactionTypeEmoji(params map[string]interface{}) *gen.ReactionTypeEmoji {
+	return &gen.ReactionTypeEmoji{
+		Type:  "emoji",
+		Emoji: f.RandomChoice(reactionEmojis),
+	}
+}
+
+func (f *Faker) generateReactionTypeCustomEmoji(params map[string]interface{}) *gen.ReactionTypeCustomEmoji {
+	return &gen.ReactionTypeCustomEmoji{
+		Type:          "custom_emoji",
+		CustomEmojiID: fmt.Sprintf("%d", f.RandomInt64(5000000000000000000, 5999999999999999999)),
+	}
+}
+
+func (f *Faker) generateReactionTypePaid(params map[string]interface{}) *gen.ReactionTypePaid {
+	return &gen.ReactionTypePaid{
+		Type: "paid",
+	}
+}
+
+func (f *Faker) generateReactionCount(params map[string]interface{}) *gen.ReactionCount {
+	return &gen.ReactionCount{
+		Type:       f.generateReactionType(params),
+		TotalCount: f.RandomInt64(1, 100),
+	}
+}
+
+func (f *Faker) generateMessageReactionUpdated(params map[string]interface{}) *gen.MessageReactionUpdated {
+	return &gen.MessageReactionUpdated{
+		Chat:        *f.generateChat(params),
+		MessageID:   f.RandomInt64(1, 100000),
+		User:        f.generateUser(params),
+		Date:        f.Now().Unix(),
+		OldReaction: []gen.ReactionType{},
+		NewReaction: []gen.ReactionType{f.generateReactionTypeEmoji(params)},
+	}
+}
+
+func (f *Faker) generateMessageReactionCountUpdated(params map[string]interface{}) *gen.MessageReactionCountUpdated {
+	// Counts are sent for anonymous reactions, as in channels
+	update := &gen.MessageReactionCountUpdated{
+		Chat:      *f.channelChat(),
+		MessageID: f.RandomInt64(1, 100000),
+		Date:      f.Now().Unix(),
+	}
+	for i := f.RandomInt64(1, 4); i > 0; i-- {
+		update.Reactions = append(update.Reactions, gen.ReactionCount{
+			Type:       f.generateReactionTypeEmoji(params),
+			TotalCount: f.RandomInt64(1, 100),
+		})
+	}
+	return update
+}
+
+// Giveaway type generators
+
+func (f *Faker) generateGiveaway(params map[string]interface{}) *gen.Giveaway {
+	giveaway := &gen.Giveaway{
+		Chats:                []gen.Chat{*f.channelChat()},
+		WinnersSelectionDate: f.Now().Unix() + f.RandomInt64(86400, 7*86400),
+		WinnerCount:          f.RandomInt64(1, 10),
+	}
+	// Prizes are either Telegram Premium or Telegram Stars
+	if f.RandomBool(0.5) {
+		giveaway.PrizeStarCount = ptr(f.RandomInt64(1, 100) * 500)
+	} else {
+		giveaway.PremiumSubscriptionMonthCount = ptr(f.premiumMonths())
+	}
+	return giveaway
+}
+
+// premiumMonths picks a Telegram Premium subscription period.
+func (f *Faker) premiumMonths() int64 {
+	months := []int64{3, 6, 12}
+	return months[f.rng.Intn(len(months))]
+}
+
+func (f *Faker) generateGiveawayWinners(params map[string]interface{}) *gen.GiveawayWinners {
+	winners := &gen.GiveawayWinners{
+		Chat:                 *f.channelChat(),
+		GiveawayMessageID:    f.RandomInt64(1, 100000),
+		WinnersSelectionDate: f.Now().Unix() - f.RandomInt64(0, 86400),
+		WinnerCount:          f.RandomInt64(1, 4),
+	}
+	for i := int64(0); i < winners.WinnerCount; i++ {
+		winners.Winners = append(winners.Winners, *f.generateUser(nil))
+	}
+	if f.RandomBool(0.5) {
+		winners.PrizeStarCount = ptr(f.RandomInt64(1, 100) * 500)
+	} else {
+		winners.PremiumSubscriptionMonthCount = ptr(f.premiumMonths())
+	}
+	return winners
+}
+
+func (f *Faker) generateGiveawayCreated(params map[string]interface{}) *gen.GiveawayCreated {
+	created := &gen.GiveawayCreated{}
+	if f.RandomBool(0.5) {
+		created.PrizeStarCount = ptr(f.RandomInt64(1, 100) * 500)
+	}
+	return created
+}
+
+func (f *Faker) generateGiveawayCompleted(params map[string]interface{}) *gen.GiveawayCompleted {
+	completed := &gen.GiveawayCompleted{
+		WinnerCount: f.RandomInt64(1, 10),
+	}
+	if f.RandomBool(0.2) {
+		completed.UnclaimedPrizeCount = ptr(f.RandomInt64(1, completed.WinnerCount+1))
+	}
+	return completed
+}
+
+// Business, game, sticker, story and menu type generators
+
+func (f *Faker) generateBusinessConnection(params map[string]interface{}) *gen.BusinessConnection {
+	user := f.generateUser(params)
+	connection := &gen.BusinessConnection{
+		ID:         f.businessConnectionID(params),
+		User:       *user,
+		UserChatID: user.ID,
+		Date:       f.Now().Unix() - f.RandomInt64(0, 30*86400),
+		Rights: &gen.BusinessBotRights{
+			CanReply:        ptr(true),
+			CanReadMessages: ptr(true),
+		},
+		IsEnabled: true,
+	}
+	return connection
+}
+
+func (f *Faker) generateBusinessMessagesDeleted(params map[string]interface{}) *gen.BusinessMessagesDeleted {
+	deleted := &gen.BusinessMessagesDeleted{
+		BusinessConnectionID: f.businessConnectionID(params),
+		Chat:                 *f.generateChat(params),
+	}
+	for i := f.RandomInt64(1, 4); i > 0; i-- {
+		deleted.MessageIds = append(deleted.MessageIds, f.RandomInt64(1, 100000))
+	}
+	return deleted
+}
+
+func (f *Faker) generateGameHighScore(params map[string]interface{}) *gen.GameHighScore {
+	return &gen.GameHighScore{
+		Position: 1,
+		User:     *f.generateUser(params),
+		Score:    f.RandomInt64(1, 10000),
+	}
+}
+
+func (f *Faker) generatePreparedInlineMessage(params map[string]interface{}) *gen.PreparedInlineMessage {
+	return &gen.PreparedInlineMessage{
+		ID:             f.generateFileID()[:16],
+		ExpirationDate: f.Now().Unix() + 86400,
+	}
+}
+
+func (f *Faker) generateStickerSet(params map[string]interface{}) *gen.StickerSet {
+	set := &gen.StickerSet{
+		Name:        fmt.Sprintf("%s_by_bot", f.generateUsername()),
+		Title:       f.generateTitle(),
+		StickerType: "regular",
+	}
+	if name, ok := params["name"].(string); ok {
+		set.Name = name
+	}
+	for i := f.RandomInt64(1, 4); i > 0; i-- {
+		sticker := f.generateSticker(params)
+		sticker.Type = set.StickerType
+		sticker.SetName = set.Name
+		set.Stickers = append(set.Stickers, *sticker)
+	}
+	return set
+}
+
+func (f *Faker) generateStory(params map[string]interface{}) *gen.Story {
+	return &gen.Story{
+		Chat: *f.generateChat(params),
+		ID:   f.RandomInt64(1, 1000),
+	}
+}
+
+func (f *Faker) generateMenuButton(params map[string]interface{}) gen.MenuButton {
+	// The menu button of chats without their own
+	return f.generateMenuButtonCommands(params)
+}
+
+func (f *Faker) generateMenuButtonCommands(params map[string]interface{}) *gen.MenuButtonCommands {
+	return &gen.MenuButtonCommands{
+		Type: "commands",
+	}
+}
+
+func (f *Faker) generateMenuButtonWebApp(params map[string]interface{}) *gen.MenuButtonWebApp {
+	return &gen.MenuButtonWebApp{
+		Type:   "web_app",
+		Text:   f.RandomChoice([]string{"Open", "Launch", "Play", "Shop"}),
+		WebApp: gen.WebAppInfo{URL: f.generateURL()},
+	}
+}
+
+func (f *Faker) generateMenuButtonDefault(params map[string]interface{}) *gen.MenuButtonDefault {
+	return &gen.MenuButtonDefault{
+		Type: "default",
+	}
+}
+
+// Gift type generators
+
+func (f *Faker) generateGift(params map[string]interface{}) *gen.Gift {
+	sticker := f.generateSticker(params)
+	sticker.Type = "regular"
+	return &gen.Gift{
+		ID:        fmt.Sprintf("%d", f.RandomInt64(5000000000000000000, 5999999999999999999)),
+		Sticker:   *sticker,
+		StarCount: f.RandomInt64(1, 20) * 25,
+	}
+}
+
+func (f *Faker) generateGifts(params map[string]interface{}) *gen.Gifts {
+	gifts := &gen.Gifts{Gifts: []gen.Gift{}}
+	for i := f.RandomInt64(1, 4); i > 0; i-- {
+		gifts.Gifts = append(gifts.Gifts, *f.generateGift(params))
+	}
+	return gifts
+}
+
+func (f *Faker) generateOwnedGift(params map[string]interface{}) gen.OwnedGift {
+	return f.generateOwnedGiftRegular(params)
+}
+
+func (f *Faker) generateOwnedGiftRegular(params map[string]interface{}) *gen.OwnedGiftRegular {
+	gift := &gen.OwnedGiftRegular{
+		Type:     "regular",
+		Gift:     *f.generateGift(params),
+		SendDate: f.Now().Unix() - f.RandomInt64(0, 30*86400),
+	}
+	if f.RandomBool(0.8) {
+		gift.SenderUser = f.generateUser(nil)
 	}
+	return gift
 }
 
-func (f *Faker) generateSentWebAppMessage(params map[string]interface{}) map[string]interface{} {
-	return map[string]interface{}{
-		"inline_message_id": f.generateFileID()[:30],
+func (f *Faker) generateOwnedGifts(params map[string]interface{}) *gen.OwnedGifts {
+	gifts := &gen.OwnedGifts{Gifts: []gen.OwnedGift{}}
+	for i := f.RandomInt64(0, 4); i > 0; i-- {
+		gifts.Gifts = append(gifts.Gifts, f.generateOwnedGift(params))
 	}
+	gifts.TotalCount = int64(len(gifts.Gifts))
+	return gifts
 }
@@ -0,0 +1,260 @@
+package faker
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// UpdateKinds returns the kinds of update Telegram sends, such as
+// "message" or "callback_query": the fields of Update besides update_id.
+func UpdateKinds() []string {
+	var kinds []string
+	for _, field := range gen.Types["Update"].Fields {
+		if field.Name != "update_id" {
+			kinds = append(kinds, field.Name)
+		}
+	}
+	return kinds
+}
+
+// GenerateUpdate creates a complete Update of the given kind, such as
+// "message" or "chat_member". params shape it like request parameters:
+// chat_id and user_id pick the chat and the user behind the update, and
+// text, data, and the like fill in what the update carries. The object of
+// the kind has as many optional fields as the density says, except those
+// params fill in.
+func (f *Faker) GenerateUpdate(kind string, params map[string]interface{}) (map[string]interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	update, err := f.update(kind, params)
+	if err != nil {
+		return nil, err
+	}
+	result := fields(update)
+	for _, field := range gen.Types["Update"].Fields {
+		if obj, ok := result[kind].(map[string]interface{}); ok && field.Name == kind {
+			f.applyDensity(gen.Types[gen.ParseType(field.Types...).Base()], obj, 2, params)
+		}
+	}
+	f.modify(gen.ParseType("Update"), result)
+	return result, nil
+}
+
+// update generates an Update of a kind. Chats default to the user's
+// private chat for messages and queries, to a group for membership and
+// reaction updates, and to a channel for channel posts and boosts.
+func (f *Faker) update(kind string, params map[string]interface{}) (*gen.Update, error) {
+	u := &gen.Update{UpdateID: f.NextUpdateID()}
+	p := make(map[string]interface{}, len(params)+2)
+	for k, v := range params {
+		p[k] = v
+	}
+
+	switch kind {
+	case "message":
+		u.Message = f.incomingMessage(f.inPrivateChat(p))
+	case "edited_message":
+		u.EditedMessage = f.edited(f.incomingMessage(f.inPrivateChat(p)))
+	case "channel_post":
+		u.ChannelPost = f.channelPost(f.inChannel(p))
+	case "edited_channel_post":
+		u.EditedChannelPost = f.edited(f.channelPost(f.inChannel(p)))
+	case "business_connection":
+		u.BusinessConnection = f.generateBusinessConnection(p)
+	case "business_message":
+		u.BusinessMessage = f.businessMessage(f.inPrivateChat(p))
+	case "edited_business_message":
+		u.EditedBusinessMessage = f.edited(f.businessMessage(f.inPrivateChat(p)))
+	case "deleted_business_messages":
+		u.DeletedBusinessMessages = f.generateBusinessMessagesDeleted(f.inPrivateChat(p))
+	case "message_reaction":
+		u.MessageReaction = f.generateMessageReactionUpdated(f.inGroup(p))
+	case "message_reaction_count":
+		u.MessageReactionCount = f.generateMessageReactionCountUpdated(f.inChannel(p))
+	case "inline_query":
+		query := f.generateInlineQuery(p)
+		if text, ok := p["query"].(string); ok {
+			query.Query = text
+		}
+		u.InlineQuery = query
+	case "chosen_inline_result":
+		u.ChosenInlineResult = f.generateChosenInlineResult(p)
+	case "callback_query":
+		u.CallbackQuery = f.callbackQuery(f.inPrivateChat(p))
+	case "shipping_query":
+		u.ShippingQuery = f.generateShippingQuery(p)
+	case "pre_checkout_query":
+		u.PreCheckoutQuery = f.generatePreCheckoutQuery(p)
+	case "purchased_paid_media":
+		u.PurchasedPaidMedia = f.generatePaidMediaPurchased(p)
+	case "poll":
+		u.Poll = f.pollWithOptions(p)
+	case "poll_answer":
+		u.PollAnswer = f.generatePollAnswer(p)
+	case "my_chat_member":
+		u.MyChatMember = f.botMembership(f.inGroup(p))
+	case "chat_member":
+		u.ChatMember = f.generateChatMemberUpdated(f.inGroup(p))
+	case "chat_join_request":
+		u.ChatJoinRequest = f.generateChatJoinRequest(f.inGroup(p))
+	case "chat_boost":
+		u.ChatBoost = f.generateChatBoostUpdated(f.inChannel(p))
+	case "removed_chat_boost":
+		u.RemovedChatBoost = f.generateChatBoostRemoved(f.inChannel(p))
+	default:
+		return nil, fmt.Errorf("unknown update kind %q", kind)
+	}
+	return u, nil
+}
+
+// inPrivateChat defaults the chat of p to the private chat of its user.
+func (f *Faker) inPrivateChat(p map[string]interface{}) map[string]interface{} {
+	if _, ok := p["chat_id"]; ok {
+		return p
+	}
+	if _, ok := p["user_id"]; !ok {
+		p["user_id"] = f.generateUser(p).ID
+	}
+	p["chat_id"] = p["user_id"]
+	return p
+}
+
+// inGroup defaults the chat of p to a group.
+func (f *Faker) inGroup(p map[string]interface{}) map[string]interface{} {
+	if _, ok := p["chat_id"]; !ok {
+		p["chat_id"] = -f.RandomInt64(1000000000, 9999999999)
+	}
+	return p
+}
+
+// inChannel defaults the chat of p to a channel.
+func (f *Faker) inChannel(p map[string]interface{}) map[string]interface{} {
+	if _, ok := p["chat_id"]; !ok {
+		p["chat_id"] = f.channelChat().ID
+	}
+	return p
+}
+
+// mediaParams are the parameters that give a message content besides
+// text.
+var mediaParams = []string{
+	"photo", "document", "audio", "video", "voice", "animation", "sticker",
+	"location", "latitude", "venue", "contact", "poll", "dice",
+}
+
+// incomingMessage generates a message a user sends. Messages without
+// media get a text, and commands are marked with a bot_command entity as
+// Telegram does.
+func (f *Faker) incomingMessage(params map[string]interface{}) *gen.Message {
+	msg := f.generateMessage(params)
+	hasMedia := false
+	for _, name := range mediaParams {
+		if _, ok := params[name]; ok {
+			hasMedia = true
+		}
+	}
+	if msg.Text == "" && !hasMedia {
+		msg.Text = f.generateText()
+	}
+	if strings.HasPrefix(msg.Text, "/") {
+		command := strings.Fields(msg.Text)[0]
+		msg.Entities = append(msg.Entities, gen.MessageEntity{
+			Type:   "bot_command",
+			Length: int64(len(utf16.Encode([]rune(command)))),
+		})
+	}
+	return msg
+}
+
+// channelPost generates a post in a channel, which is sent on behalf of
+// the channel rather than a user.
+func (f *Faker) channelPost(params map[string]interface{}) *gen.Message {
+	msg := f.incomingMessage(params)
+	msg.From = nil
+	chat := msg.Chat
+	msg.SenderChat = &chat
+	return msg
+}
+
+// businessMessage generates a message sent to a business account the bot
+// is connected to.
+func (f *Faker) businessMessage(params map[string]interface{}) *gen.Message {
+	msg := f.incomingMessage(params)
+	msg.BusinessConnectionID = f.businessConnectionID(params)
+	return msg
+}
+
+// edited dates msg back and marks it edited now.
+func (f *Faker) edited(msg *gen.Message) *gen.Message {
+	msg.Date -= f.RandomInt64(60, 3600)
+	msg.EditDate = ptr(f.Now().Unix())
+	return msg
+}
+
+// callbackQuery generates the press of a button of a message the bot
+// sent, with the callback data of params, if any.
+func (f *Faker) callbackQuery(params map[string]interface{}) *gen.CallbackQuery {
+	query := f.generateCallbackQuery(params)
+	if data, ok := params["data"].(string); ok {
+		query.Data = data
+	}
+
+	msg := f.generateMessage(map[string]interface{}{"chat_id": params["chat_id"]})
+	msg.From = f.botUser()
+	msg.Text = f.generateText()
+	msg.ReplyMarkup = &gen.InlineKeyboardMarkup{
+		InlineKeyboard: [][]gen.InlineKeyboardButton{{{Text: "OK", CallbackData: query.Data}}},
+	}
+	query.Message = msg
+	return query
+}
+
+// botMembership generates a change of the bot's own membership: it was
+// added to the chat.
+func (f *Faker) botMembership(params map[string]interface{}) *gen.ChatMemberUpdated {
+	update := f.generateChatMemberUpdated(params)
+	bot := f.botUser()
+	update.OldChatMember = &gen.ChatMemberLeft{Status: "left", User: *bot}
+	update.NewChatMember = &gen.ChatMemberMember{Status: "member", User: *bot}
+	return update
+}
+
+// botUser generates the User of a bot.
+func (f *Faker) botUser() *gen.User {
+	bot := f.generateUser(nil)
+	bot.IsBot = true
+	bot.LastName = ""
+	bot.Username = f.generateUsername() + "_bot"
+	bot.LanguageCode = ""
+	bot.IsPremium = nil
+	return bot
+}
+
+// pollWithOptions generates a poll with its options, as in poll updates.
+func (f *Faker) pollWithOptions(params map[string]interface{}) *gen.Poll {
+	poll := f.generatePoll(params)
+	loc := f.locale()
+	for i := f.RandomInt64(2, 5); i > 0; i-- {
+		poll.Options = append(poll.Options, gen.PollOption{
+			Text:       f.RandomChoice(loc.titleNouns),
+			VoterCount: f.RandomInt64(0, 20),
+		})
+	}
+	poll.TotalVoterCount = 0
+	for _, option := range poll.Options {
+		poll.TotalVoterCount += option.VoterCount
+	}
+	return poll
+}
+
+// businessConnectionID returns the business_connection_id of params, or
+// a generated one.
+func (f *Faker) businessConnectionID(params map[string]interface{}) string {
+	if id, ok := params["business_connection_id"].(string); ok {
+		return id
+	}
+	return f.generateFileID()[:16]
+}
@@ -3,7 +3,6 @@ package faker
 import (
 	"fmt"
 	"strings"
-	"time"
 )
 
 // generateString generates a realistic string value based on the field name.
@@ -22,10 +21,10 @@ func (f *Faker) generateString(fieldName string) string {
 
 	// Name fields
 	if name == "first_name" {
-		return f.RandomChoice(firstNames)
+		return f.RandomChoice(f.locale().firstNames)
 	}
 	if name == "last_name" {
-		return f.RandomChoice(lastNames)
+		return f.RandomChoice(f.locale().lastNames)
 	}
 	if name == "name" || name == "title" {
 		return f.generateTitle()
@@ -111,7 +110,7 @@ func (f *Faker) generateInt64(fieldName string) int64 {
 
 	// Date/time fields - Unix timestamps
 	if name == "date" || strings.HasSuffix(name, "_date") || strings.HasSuffix(name, "_time") {
-		return time.Now().Unix()
+		return f.Now().Unix()
 	}
 
 	// Count fields - small numbers
@@ -232,29 +231,34 @@ func (f *Faker) generateFileID() string {
 }
 
 func (f *Faker) generateUsername() string {
-	adjective := f.RandomChoice(usernameAdjectives)
-	noun := f.RandomChoice(usernameNouns)
+	adjective := f.RandomChoice(f.english.usernameAdjectives)
+	noun := f.RandomChoice(f.english.usernameNouns)
 	num := f.rng.Intn(1000)
 	return fmt.Sprintf("%s_%s_%d", adjective, noun, num)
 }
 
 func (f *Faker) generateTitle() string {
-	adjective := f.RandomChoice(titleAdjectives)
-	noun := f.RandomChoice(titleNouns)
-	return fmt.Sprintf("%s %s", adjective, noun)
+	loc := f.locale()
+	if len(loc.titles) > 0 && (loc.titlesOnly || f.RandomBool(0.5)) {
+		return f.RandomChoice(loc.titles)
+	}
+	adjective := f.RandomChoice(loc.titleAdjectives)
+	noun := f.RandomChoice(loc.titleNouns)
+	return loc.title(adjective, noun)
 }
 
 func (f *Faker) generateText() string {
+	loc := f.locale()
 	sentences := 1 + f.rng.Intn(3)
 	var parts []string
 	for i := 0; i < sentences; i++ {
-		parts = append(parts, f.RandomChoice(sampleSentences))
+		parts = append(parts, f.RandomChoice(loc.sentences))
 	}
-	return strings.Join(parts, " ")
+	return strings.Join(parts, loc.separator)
 }
 
 func (f *Faker) generateURL() string {
-	domain := f.RandomChoice(domains)
+	domain := f.RandomChoice(f.english.domains)
 	path := f.RandomChoice(urlPaths)
 	return fmt.Sprintf("https://%s/%s", domain, path)
 }
@@ -268,7 +272,7 @@ func (f *Faker) generateFilePath() string {
 
 func (f *Faker) generateEmail() string {
 	name := strings.ToLower(f.RandomChoice(firstNames))
-	domain := f.RandomChoice(emailDomains)
+	domain := f.RandomChoice(f.english.emailDomains)
 	num := f.rng.Intn(100)
 	return fmt.Sprintf("%s%d@%s", name, num, domain)
 }
@@ -284,16 +288,17 @@ func (f *Faker) generatePhoneNumber() string {
 }
 
 func (f *Faker) generateCommand() string {
-	return "/" + f.RandomChoice(commands)
+	return "/" + f.RandomChoice(f.english.commands)
 }
 
 func (f *Faker) generateQuery() string {
-	return f.RandomChoice(queries)
+	return f.RandomChoice(f.locale().queries)
 }
 
 func (f *Faker) generateAuthor() string {
-	first := f.RandomChoice(firstNames)
-	last := f.RandomChoice(lastNames)
+	loc := f.locale()
+	first := f.RandomChoice(loc.firstNames)
+	last := f.RandomChoice(loc.lastNames)
 	return fmt.Sprintf("%s %s", first, last)
 }
 
@@ -385,6 +390,12 @@ var mimeTypes = []string{
 	"audio/ogg", "application/pdf", "text/plain", "application/json",
 }
 
+// mimeExtensions names the file extension of each of mimeTypes.
+var mimeExtensions = map[string]string{
+	"image/jpeg": "jpg", "image/png": "png", "image/gif": "gif", "video/mp4": "mp4", "audio/mpeg": "mp3",
+	"audio/ogg": "ogg", "application/pdf": "pdf", "text/plain": "txt", "application/json": "json",
+}
+
 var commands = []string{
 	"start", "help", "settings", "about", "cancel", "menu", "status", "info",
 }
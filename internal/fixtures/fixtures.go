@@ -0,0 +1,115 @@
+// Package fixtures writes generated examples of the Bot API's types and
+// method responses to files, for use as golden files and in documentation
+// outside a running mock.
+package fixtures
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/faker"
+)
+
+// Config controls an export.
+type Config struct {
+	// Faker configures the faker the fixtures are generated with. Each
+	// fixture is generated afresh from the seed, so it doesn't change when
+	// the spec gains other types or methods; a seed of 0 makes them
+	// random. Set Now for dates that don't change either.
+	Faker faker.Config
+	// Types and Methods limit the export to these types and methods. If
+	// both are empty, every type and method of the spec is exported.
+	Types   []string
+	Methods []string
+}
+
+// Summary tells what an export wrote.
+type Summary struct {
+	Types   int `json:"types"`
+	Methods int `json:"methods"`
+}
+
+// Export writes a fixture per type to dir/types/<Type>.json and a
+// response per method, as the Bot API sends it, to
+// dir/methods/<method>.json. Existing files are overwritten.
+func Export(dir string, cfg Config) (Summary, error) {
+	var summary Summary
+	types, methods := cfg.Types, cfg.Methods
+	if len(types) == 0 && len(methods) == 0 {
+		types, methods = allTypes(), allMethods()
+	}
+	for _, name := range types {
+		if _, ok := gen.Types[name]; !ok {
+			return summary, fmt.Errorf("unknown type %q", name)
+		}
+	}
+	for _, name := range methods {
+		if _, ok := gen.Methods[name]; !ok {
+			return summary, fmt.Errorf("unknown method %q", name)
+		}
+	}
+
+	base := faker.New(cfg.Faker)
+	for _, name := range types {
+		v := base.WithSeed(cfg.Faker.Seed).Generate(name, nil)
+		if err := write(filepath.Join(dir, "types", name+".json"), v); err != nil {
+			return summary, err
+		}
+		summary.Types++
+	}
+	for _, name := range methods {
+		spec := gen.Methods[name]
+		var result interface{} = true
+		if spec.Result.Base() != "" {
+			result = base.WithSeed(cfg.Faker.Seed).GenerateType(spec.Result, nil, nil)
+		}
+		resp := map[string]interface{}{"ok": true, "result": result}
+		if err := write(filepath.Join(dir, "methods", name+".json"), resp); err != nil {
+			return summary, err
+		}
+		summary.Methods++
+	}
+	return summary, nil
+}
+
+// allTypes returns the names of the spec's types, sorted.
+func allTypes() []string {
+	names := make([]string, 0, len(gen.Types))
+	for name := range gen.Types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// allMethods returns the names of the spec's methods, sorted.
+func allMethods() []string {
+	names := make([]string, 0, len(gen.Methods))
+	for name := range gen.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// write writes v to path as indented JSON, creating the directory if
+// needed. Markup characters are left as they are, since fixtures are read
+// by people as well as tests.
+func write(path string, v interface{}) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("encode %s: %w", filepath.Base(path), err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,71 @@
+// internal/fixtures/fixtures_test.go
+package fixtures
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/faker"
+)
+
+func TestExport(t *testing.T) {
+	now := time.Unix(1704067200, 0)
+	cfg := Config{
+		Faker:   faker.Config{Seed: 3, Now: func() time.Time { return now }},
+		Types:   []string{"User", "ChatMember"},
+		Methods: []string{"sendMessage", "close"},
+	}
+	dir := t.TempDir()
+	summary, err := Export(dir, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Types != 2 || summary.Methods != 2 {
+		t.Errorf("expected 2 types and 2 methods, got %+v", summary)
+	}
+
+	read := func(t *testing.T, dir, name string) map[string]interface{} {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var v map[string]interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	if user := read(t, dir, "types/User.json"); user["id"] == nil || user["first_name"] == nil {
+		t.Errorf("expected a user, got %v", user)
+	}
+	sent := read(t, dir, "methods/sendMessage.json")
+	msg, _ := sent["result"].(map[string]interface{})
+	if sent["ok"] != true || msg["date"] != float64(now.Unix()) {
+		t.Errorf("expected a message dated by the clock, got %v", sent)
+	}
+	if closed := read(t, dir, "methods/close.json"); closed["result"] != true {
+		t.Errorf("expected true, got %v", closed)
+	}
+
+	// Fixtures depend on the seed alone, not on what else is exported
+	other := t.TempDir()
+	if _, err := Export(other, Config{Faker: cfg.Faker, Methods: []string{"sendMessage"}}); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := os.ReadFile(filepath.Join(dir, "methods/sendMessage.json"))
+	b, _ := os.ReadFile(filepath.Join(other, "methods/sendMessage.json"))
+	if string(a) != string(b) {
+		t.Errorf("expected the same fixture, got\n%s\n%s", a, b)
+	}
+	if _, err := os.Stat(filepath.Join(other, "types")); !os.IsNotExist(err) {
+		t.Error("expected no types when only methods are exported")
+	}
+
+	if _, err := Export(other, Config{Types: []string{"Telepathy"}}); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
@@ -0,0 +1,160 @@
+// internal/floodlimit/cooldown.go
+package floodlimit
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Cooldown is a token, or one of its chats, that must wait before calling
+// again.
+type Cooldown struct {
+	Token string `json:"token"`
+	// ChatID is empty when the whole token waits.
+	ChatID string    `json:"chat_id,omitempty"`
+	Until  time.Time `json:"until"`
+	// RetryAfter is the remaining wait in seconds.
+	RetryAfter int `json:"retry_after"`
+}
+
+type cooldownKey struct {
+	token  string
+	chatID string
+}
+
+// Cooldowns holds the 429 errors given to bots until their retry_after
+// elapses, so that calls made too early fail again like in Telegram. It is
+// disabled until enabled.
+type Cooldowns struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	enabled  bool
+	until    map[cooldownKey]time.Time
+	rejected int
+}
+
+// NewCooldowns creates disabled cooldowns reading the time from now.
+func NewCooldowns(now func() time.Time) *Cooldowns {
+	return &Cooldowns{now: now, until: make(map[cooldownKey]time.Time)}
+}
+
+// SetEnabled turns enforcement on or off. Turning it off forgets the
+// active cooldowns.
+func (c *Cooldowns) SetEnabled(enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.enabled = enabled
+	if !enabled {
+		c.until = make(map[cooldownKey]time.Time)
+	}
+}
+
+// Enabled reports whether cooldowns are enforced.
+func (c *Cooldowns) Enabled() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enabled
+}
+
+// Start makes the bot with token wait seconds before calling again, for
+// chatID only or, if it is empty, for every call.
+func (c *Cooldowns) Start(token, chatID string, seconds int) {
+	if seconds <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return
+	}
+	key := cooldownKey{token, chatID}
+	until := c.now().Add(time.Duration(seconds) * time.Second)
+	if until.After(c.until[key]) {
+		c.until[key] = until
+	}
+}
+
+// Check reports whether the bot with token may call now for chatID. If
+// not, it returns the remaining wait in seconds.
+func (c *Cooldowns) Check(token, chatID string) (retryAfter int, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.enabled {
+		return 0, true
+	}
+	now := c.now()
+	var wait time.Duration
+	keys := []cooldownKey{{token, ""}}
+	if chatID != "" {
+		keys = append(keys, cooldownKey{token, chatID})
+	}
+	for _, key := range keys {
+		until, found := c.until[key]
+		if !found {
+			continue
+		}
+		if !until.After(now) {
+			delete(c.until, key)
+			continue
+		}
+		if d := until.Sub(now); d > wait {
+			wait = d
+		}
+	}
+	if wait == 0 {
+		return 0, true
+	}
+	c.rejected++
+	return seconds(wait), false
+}
+
+// List returns the active cooldowns, ordered by token and chat.
+func (c *Cooldowns) List() []Cooldown {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	list := make([]Cooldown, 0, len(c.until))
+	for key, until := range c.until {
+		if !until.After(now) {
+			continue
+		}
+		list = append(list, Cooldown{
+			Token:      key.token,
+			ChatID:     key.chatID,
+			Until:      until,
+			RetryAfter: seconds(until.Sub(now)),
+		})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Token != list[j].Token {
+			return list[i].Token < list[j].Token
+		}
+		return list[i].ChatID < list[j].ChatID
+	})
+	return list
+}
+
+// Rejected returns the number of calls rejected for being made too early.
+func (c *Cooldowns) Rejected() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rejected
+}
+
+// Clear forgets the active cooldowns and the rejected calls.
+func (c *Cooldowns) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.until = make(map[cooldownKey]time.Time)
+	c.rejected = 0
+}
+
+// seconds rounds a wait up to whole seconds, as retry_after is given.
+func seconds(d time.Duration) int {
+	s := int((d + time.Second - 1) / time.Second)
+	if s < 1 {
+		s = 1
+	}
+	return s
+}
@@ -0,0 +1,206 @@
+// Package floodlimit enforces Telegram's flood limits on sent messages, so
+// that bots meet 429 errors with the same shape as in production: a burst
+// to one chat is throttled long before the overall limit is reached.
+package floodlimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default limits, as documented by Telegram.
+const (
+	DefaultPerSecond         = 30
+	DefaultPerChatPerSecond  = 1
+	DefaultPerGroupPerMinute = 20
+)
+
+// Names of the limits, as counted in Stats.
+const (
+	LimitGlobal = "global"
+	LimitChat   = "chat"
+	LimitGroup  = "group"
+)
+
+// Config sets the limits on the messages a bot sends.
+type Config struct {
+	// PerSecond limits the messages a bot sends overall.
+	PerSecond int `json:"per_second,omitempty"`
+	// PerChatPerSecond limits the messages a bot sends to one chat.
+	PerChatPerSecond int `json:"per_chat_per_second,omitempty"`
+	// PerGroupPerMinute limits the messages a bot sends to one group or
+	// channel.
+	PerGroupPerMinute int `json:"per_group_per_minute,omitempty"`
+}
+
+// Validate checks the limits and fills in the defaults.
+func (c *Config) Validate() error {
+	if c.PerSecond < 0 || c.PerChatPerSecond < 0 || c.PerGroupPerMinute < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	if c.PerSecond == 0 {
+		c.PerSecond = DefaultPerSecond
+	}
+	if c.PerChatPerSecond == 0 {
+		c.PerChatPerSecond = DefaultPerChatPerSecond
+	}
+	if c.PerGroupPerMinute == 0 {
+		c.PerGroupPerMinute = DefaultPerGroupPerMinute
+	}
+	return nil
+}
+
+// Stats counts the messages allowed and throttled, by the limit that was
+// hit.
+type Stats struct {
+	Allowed int            `json:"allowed"`
+	Limited int            `json:"limited"`
+	ByLimit map[string]int `json:"by_limit"`
+}
+
+// sweepEvery is how many calls pass between removals of idle windows.
+const sweepEvery = 1024
+
+// Limiter throttles messages according to its configuration. It is
+// disabled until configured.
+type Limiter struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	cfg     *Config
+	windows map[string][]time.Time
+	calls   int
+	stats   Stats
+}
+
+// NewLimiter creates a disabled limiter reading the time from now.
+func NewLimiter(now func() time.Time) *Limiter {
+	return &Limiter{
+		now:     now,
+		windows: make(map[string][]time.Time),
+		stats:   Stats{ByLimit: map[string]int{}},
+	}
+}
+
+// Set enables the limiter with cfg and resets its windows and statistics.
+func (l *Limiter) Set(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = &cfg
+	l.windows = make(map[string][]time.Time)
+	l.stats = Stats{ByLimit: map[string]int{}}
+	return nil
+}
+
+// Get returns the configuration, if the limiter is enabled, and its
+// statistics.
+func (l *Limiter) Get() (*Config, Stats) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	stats := l.stats
+	stats.ByLimit = make(map[string]int, len(l.stats.ByLimit))
+	for limit, n := range l.stats.ByLimit {
+		stats.ByLimit[limit] = n
+	}
+	if l.cfg == nil {
+		return nil, stats
+	}
+	cfg := *l.cfg
+	return &cfg, stats
+}
+
+// Disable stops throttling and resets the statistics.
+func (l *Limiter) Disable() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = nil
+	l.windows = make(map[string][]time.Time)
+	l.stats = Stats{ByLimit: map[string]int{}}
+}
+
+// window is a limit on the messages sent within a period.
+type window struct {
+	name   string
+	key    string
+	limit  int
+	period time.Duration
+}
+
+// Allow reports whether the bot with token may send a message to chatID
+// now, and counts the message if so. Otherwise it returns the number of
+// seconds until it may, as retry_after. Throttled messages don't count.
+func (l *Limiter) Allow(token, chatID string, group bool) (retryAfter int, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.cfg == nil {
+		return 0, true
+	}
+	now := l.now()
+	l.sweep(now)
+
+	windows := []window{
+		{LimitGlobal, token, l.cfg.PerSecond, time.Second},
+		{LimitChat, token + "|" + chatID, l.cfg.PerChatPerSecond, time.Second},
+	}
+	if group {
+		windows = append(windows, window{LimitGroup, token + "|" + chatID + "|min", l.cfg.PerGroupPerMinute, time.Minute})
+	}
+
+	var wait time.Duration
+	hit := ""
+	for _, w := range windows {
+		sent := l.prune(w.key, now, w.period)
+		if len(sent) < w.limit {
+			continue
+		}
+		// The window opens again when the oldest message in it leaves
+		if d := sent[len(sent)-w.limit].Add(w.period).Sub(now); d > wait || hit == "" {
+			wait, hit = d, w.name
+		}
+	}
+	if hit != "" {
+		l.stats.Limited++
+		l.stats.ByLimit[hit]++
+		return seconds(wait), false
+	}
+
+	for _, w := range windows {
+		l.windows[w.key] = append(l.windows[w.key], now)
+	}
+	l.stats.Allowed++
+	return 0, true
+}
+
+// prune drops the messages that left a window and returns the rest.
+// Callers must hold l.mu.
+func (l *Limiter) prune(key string, now time.Time, period time.Duration) []time.Time {
+	sent := l.windows[key]
+	i := 0
+	for i < len(sent) && !sent[i].After(now.Add(-period)) {
+		i++
+	}
+	sent = sent[i:]
+	if len(sent) == 0 {
+		delete(l.windows, key)
+		return nil
+	}
+	l.windows[key] = sent
+	return sent
+}
+
+// sweep now and then removes the windows of chats no longer written to,
+// which Allow would otherwise keep forever. Callers must hold l.mu.
+func (l *Limiter) sweep(now time.Time) {
+	l.calls++
+	if l.calls%sweepEvery != 0 {
+		return
+	}
+	for key, sent := range l.windows {
+		if len(sent) > 0 && now.Sub(sent[len(sent)-1]) > time.Minute {
+			delete(l.windows, key)
+		}
+	}
+}
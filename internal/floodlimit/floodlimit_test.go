@@ -0,0 +1,137 @@
+// internal/floodlimit/floodlimit_test.go
+package floodlimit
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestLimiter(t *testing.T, cfg Config) (*Limiter, *time.Time) {
+	t.Helper()
+	now := time.Unix(1700000000, 0)
+	l := NewLimiter(func() time.Time { return now })
+	if err := l.Set(cfg); err != nil {
+		t.Fatal(err)
+	}
+	return l, &now
+}
+
+func TestConfig_ValidateDefaults(t *testing.T) {
+	cfg := Config{}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.PerSecond != DefaultPerSecond || cfg.PerChatPerSecond != DefaultPerChatPerSecond || cfg.PerGroupPerMinute != DefaultPerGroupPerMinute {
+		t.Errorf("unexpected defaults %+v", cfg)
+	}
+	if err := (&Config{PerSecond: -1}).Validate(); err == nil {
+		t.Error("expected negative limits to be rejected")
+	}
+}
+
+func TestLimiter_DisabledByDefault(t *testing.T) {
+	l := NewLimiter(time.Now)
+	for i := 0; i < 100; i++ {
+		if _, ok := l.Allow("bot", "42", false); !ok {
+			t.Fatal("expected a disabled limiter to allow everything")
+		}
+	}
+}
+
+func TestLimiter_PerChat(t *testing.T) {
+	l, now := newTestLimiter(t, Config{})
+
+	if _, ok := l.Allow("bot", "42", false); !ok {
+		t.Fatal("expected the first message to be allowed")
+	}
+	retryAfter, ok := l.Allow("bot", "42", false)
+	if ok || retryAfter != 1 {
+		t.Errorf("expected the second message within a second to wait 1s, got %d %v", retryAfter, ok)
+	}
+	if _, ok := l.Allow("bot", "43", false); !ok {
+		t.Error("expected other chats not to be throttled")
+	}
+	if _, ok := l.Allow("other", "42", false); !ok {
+		t.Error("expected other bots not to be throttled")
+	}
+
+	*now = now.Add(time.Second)
+	if _, ok := l.Allow("bot", "42", false); !ok {
+		t.Error("expected the chat to accept messages again after a second")
+	}
+
+	_, stats := l.Get()
+	if stats.Allowed != 4 || stats.Limited != 1 || stats.ByLimit[LimitChat] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLimiter_PerGroup(t *testing.T) {
+	l, now := newTestLimiter(t, Config{})
+
+	for i := 0; i < DefaultPerGroupPerMinute; i++ {
+		if _, ok := l.Allow("bot", "-100", true); !ok {
+			t.Fatalf("expected message %d to be allowed", i+1)
+		}
+		*now = now.Add(2 * time.Second)
+	}
+	// The first message was sent 40s ago
+	retryAfter, ok := l.Allow("bot", "-100", true)
+	if ok || retryAfter != 20 {
+		t.Errorf("expected to wait 20s for the group, got %d %v", retryAfter, ok)
+	}
+}
+
+func TestLimiter_Global(t *testing.T) {
+	l, _ := newTestLimiter(t, Config{PerSecond: 5})
+
+	for i := 0; i < 5; i++ {
+		if _, ok := l.Allow("bot", strconv.Itoa(i), false); !ok {
+			t.Fatalf("expected message %d to be allowed", i+1)
+		}
+	}
+	if _, ok := l.Allow("bot", "99", false); ok {
+		t.Error("expected the overall limit to throttle a new chat")
+	}
+	_, stats := l.Get()
+	if stats.ByLimit[LimitGlobal] != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestCooldowns(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := NewCooldowns(func() time.Time { return now })
+
+	c.Start("bot", "42", 5)
+	if _, ok := c.Check("bot", "42"); !ok {
+		t.Fatal("expected disabled cooldowns not to reject calls")
+	}
+
+	c.SetEnabled(true)
+	c.Start("bot", "42", 5)
+	now = now.Add(2 * time.Second)
+	if retryAfter, ok := c.Check("bot", "42"); ok || retryAfter != 3 {
+		t.Errorf("expected to wait 3 more seconds, got %d %v", retryAfter, ok)
+	}
+	if _, ok := c.Check("bot", "43"); !ok {
+		t.Error("expected other chats to be allowed")
+	}
+
+	c.Start("bot", "", 10)
+	if _, ok := c.Check("bot", "43"); ok {
+		t.Error("expected a cooldown without a chat to cover every chat")
+	}
+	if list := c.List(); len(list) != 2 || list[0].ChatID != "" || list[1].RetryAfter != 3 {
+		t.Errorf("unexpected cooldowns %+v", list)
+	}
+
+	now = now.Add(10 * time.Second)
+	if _, ok := c.Check("bot", "42"); !ok {
+		t.Error("expected calls to be allowed once the cooldown elapsed")
+	}
+	if c.Rejected() != 2 {
+		t.Errorf("expected 2 rejected calls, got %d", c.Rejected())
+	}
+}
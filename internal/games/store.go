@@ -0,0 +1,157 @@
+// Package games keeps the high scores bots set on game messages, so that
+// getGameHighScores returns the leaderboard setGameScore built.
+package games
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrNotModified is returned by Set when a score isn't higher than the
+// user's current one and the change isn't forced.
+var ErrNotModified = errors.New("score not modified")
+
+// Game identifies the message a game was sent in: a chat and message ID,
+// or an inline message ID.
+type Game struct {
+	ChatID          string `json:"chat_id,omitempty"`
+	MessageID       int64  `json:"message_id,omitempty"`
+	InlineMessageID string `json:"inline_message_id,omitempty"`
+}
+
+// Board is the scores of a game, by user ID.
+type Board struct {
+	Game
+	Scores map[int64]int64 `json:"scores"`
+}
+
+// HighScore is a user's place on a leaderboard.
+type HighScore struct {
+	Position int   `json:"position"`
+	UserID   int64 `json:"user_id"`
+	Score    int64 `json:"score"`
+}
+
+// Store holds the scores of games.
+type Store struct {
+	mu     sync.Mutex
+	boards map[Game]map[int64]int64
+}
+
+// NewStore creates an empty score store.
+func NewStore() *Store {
+	return &Store{boards: make(map[Game]map[int64]int64)}
+}
+
+// Set sets the score of a user in a game. Unless force is set, the score
+// must be higher than the user's current one.
+func (s *Store) Set(game Game, userID, score int64, force bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scores := s.boards[game]
+	if current, ok := scores[userID]; ok && !force && score <= current {
+		return ErrNotModified
+	}
+	if scores == nil {
+		scores = make(map[int64]int64)
+		s.boards[game] = scores
+	}
+	scores[userID] = score
+	return nil
+}
+
+// HighScores returns the top three scores of a game and the scores around
+// the user's, ordered by position. It returns false if the game has no
+// scores.
+func (s *Store) HighScores(game Game, userID int64) ([]HighScore, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scores, ok := s.boards[game]
+	if !ok {
+		return nil, false
+	}
+	board := leaderboard(scores)
+	user := -1
+	for i, hs := range board {
+		if hs.UserID == userID {
+			user = i
+		}
+	}
+	list := []HighScore{}
+	for i, hs := range board {
+		if i < 3 || (user >= 0 && i >= user-1 && i <= user+1) {
+			list = append(list, hs)
+		}
+	}
+	return list, true
+}
+
+// List returns the scores of every game.
+func (s *Store) List() []Board {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Board, 0, len(s.boards))
+	for game, scores := range s.boards {
+		list = append(list, Board{Game: game, Scores: copyScores(scores)})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].Game, list[j].Game
+		if a.ChatID != b.ChatID {
+			return a.ChatID < b.ChatID
+		}
+		if a.MessageID != b.MessageID {
+			return a.MessageID < b.MessageID
+		}
+		return a.InlineMessageID < b.InlineMessageID
+	})
+	return list
+}
+
+// Restore replaces the store contents with the given boards.
+func (s *Store) Restore(list []Board) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boards = make(map[Game]map[int64]int64, len(list))
+	for _, b := range list {
+		s.boards[b.Game] = copyScores(b.Scores)
+	}
+}
+
+// Clear forgets all scores.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.boards = make(map[Game]map[int64]int64)
+}
+
+// leaderboard orders scores from highest to lowest. Users with the same
+// score share a position.
+func leaderboard(scores map[int64]int64) []HighScore {
+	board := make([]HighScore, 0, len(scores))
+	for userID, score := range scores {
+		board = append(board, HighScore{UserID: userID, Score: score})
+	}
+	sort.Slice(board, func(i, j int) bool {
+		if board[i].Score != board[j].Score {
+			return board[i].Score > board[j].Score
+		}
+		return board[i].UserID < board[j].UserID
+	})
+	for i := range board {
+		if i > 0 && board[i].Score == board[i-1].Score {
+			board[i].Position = board[i-1].Position
+		} else {
+			board[i].Position = i + 1
+		}
+	}
+	return board
+}
+
+func copyScores(scores map[int64]int64) map[int64]int64 {
+	copied := make(map[int64]int64, len(scores))
+	for userID, score := range scores {
+		copied[userID] = score
+	}
+	return copied
+}
@@ -0,0 +1,58 @@
+// internal/games/store_test.go
+package games
+
+import "testing"
+
+func TestStore_Set(t *testing.T) {
+	s := NewStore()
+	game := Game{ChatID: "42", MessageID: 7}
+
+	if err := s.Set(game, 1, 100, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Set(game, 1, 100, false); err != ErrNotModified {
+		t.Errorf("expected an equal score to be rejected, got %v", err)
+	}
+	if err := s.Set(game, 1, 50, false); err != ErrNotModified {
+		t.Errorf("expected a lower score to be rejected, got %v", err)
+	}
+	if err := s.Set(game, 1, 50, true); err != nil {
+		t.Errorf("expected a forced score to be set, got %v", err)
+	}
+	if err := s.Set(Game{InlineMessageID: "abc"}, 1, 10, false); err != nil {
+		t.Errorf("expected other games to have their own scores, got %v", err)
+	}
+	list, _ := s.HighScores(game, 1)
+	if len(list) != 1 || list[0].Score != 50 {
+		t.Errorf("expected the forced score, got %+v", list)
+	}
+}
+
+func TestStore_HighScores(t *testing.T) {
+	s := NewStore()
+	game := Game{ChatID: "42", MessageID: 7}
+	if _, ok := s.HighScores(game, 1); ok {
+		t.Error("expected a game without scores to be unknown")
+	}
+
+	for userID, score := range map[int64]int64{1: 900, 2: 800, 3: 800, 4: 700, 5: 600, 6: 500, 7: 400, 8: 300} {
+		s.Set(game, userID, score, false)
+	}
+	list, ok := s.HighScores(game, 6)
+	if !ok || len(list) != 6 {
+		t.Fatalf("expected the top three and the user's neighbours, got %+v", list)
+	}
+	want := []HighScore{{1, 1, 900}, {2, 2, 800}, {2, 3, 800}, {5, 5, 600}, {6, 6, 500}, {7, 7, 400}}
+	for i, hs := range want {
+		if list[i] != hs {
+			t.Errorf("position %d: expected %+v, got %+v", i, hs, list[i])
+		}
+	}
+
+	snapshot := s.List()
+	s.Clear()
+	s.Restore(snapshot)
+	if list, _ := s.HighScores(game, 1); len(list) != 3 {
+		t.Errorf("expected restored scores, got %+v", list)
+	}
+}
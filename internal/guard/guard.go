@@ -0,0 +1,303 @@
+// Package guard accounts for the memory held by the mock's in-memory stores
+// and enforces configurable ceilings, so long-running soak tests shed old
+// data or fail loudly instead of growing without bound.
+package guard
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Component names a store whose memory use is accounted for.
+type Component string
+
+const (
+	Recorder Component = "recorder"
+	Queue    Component = "queue"
+	Messages Component = "messages"
+	Files    Component = "files"
+)
+
+// Components lists every accounted component.
+var Components = []Component{Recorder, Queue, Messages, Files}
+
+// Policy decides what happens when a store reaches its ceiling.
+type Policy string
+
+const (
+	// PolicyEvict drops the oldest entries until the store is below its ceiling.
+	PolicyEvict Policy = "evict"
+	// PolicyReject refuses to add anything to the store until space is freed.
+	PolicyReject Policy = "reject"
+	// PolicyLog keeps growing but logs that the ceiling was crossed.
+	PolicyLog Policy = "log"
+)
+
+// DefaultPolicy is used when no policy is configured.
+const DefaultPolicy = PolicyEvict
+
+// ErrLimitExceeded is returned by Admit when a store is full and the
+// policy is PolicyReject.
+var ErrLimitExceeded = errors.New("memory limit exceeded")
+
+// maxBreaches is the number of recent breaches kept for reporting.
+const maxBreaches = 50
+
+// Store is a component whose memory use can be measured and reduced.
+type Store interface {
+	// Size returns the approximate number of bytes held.
+	Size() int64
+	// EvictOldest drops the oldest entry. It returns false if the store is empty.
+	EvictOldest() bool
+}
+
+// Breach records a store reaching its ceiling and the action taken.
+type Breach struct {
+	Time      time.Time `json:"time"`
+	Component Component `json:"component"`
+	Session   string    `json:"session,omitempty"`
+	Limit     int64     `json:"limit"`
+	Used      int64     `json:"used"`
+	Action    string    `json:"action"` // "evicted", "rejected", or "logged"
+	Evicted   int       `json:"evicted,omitempty"`
+}
+
+// Guard enforces memory ceilings. Ceilings are soft: a store is full once
+// its usage reaches the ceiling, so the entry that crosses it is kept.
+// A nil *Guard admits everything.
+type Guard struct {
+	mu       sync.Mutex
+	limits   map[Component]int64
+	policy   Policy
+	breaches []Breach
+	total    int
+	// over tracks stores currently above their ceiling under PolicyLog,
+	// so the crossing is logged once rather than on every request.
+	over map[string]bool
+
+	// OnBreach, if set, is called after every recorded breach.
+	OnBreach func(Breach)
+}
+
+// New creates a guard with the given per-component ceilings in bytes.
+// Components without a ceiling, or with a ceiling of zero, are unlimited.
+func New(limits map[Component]int64, policy Policy) *Guard {
+	if policy == "" {
+		policy = DefaultPolicy
+	}
+	l := make(map[Component]int64, len(limits))
+	for c, n := range limits {
+		if n > 0 {
+			l[c] = n
+		}
+	}
+	return &Guard{
+		limits: l,
+		policy: policy,
+		over:   make(map[string]bool),
+	}
+}
+
+// Limit returns the ceiling for a component, or 0 if it is unlimited.
+func (g *Guard) Limit(c Component) int64 {
+	if g == nil {
+		return 0
+	}
+	return g.limits[c]
+}
+
+// Policy returns the policy applied when a ceiling is reached.
+func (g *Guard) Policy() Policy {
+	if g == nil {
+		return DefaultPolicy
+	}
+	return g.policy
+}
+
+// Admit is called before adding to a store. If the store has reached its
+// ceiling, the policy is applied: the oldest entries are evicted, the
+// addition is refused with ErrLimitExceeded, or the crossing is logged.
+// session identifies the store's session, if it belongs to one.
+func (g *Guard) Admit(c Component, session string, s Store) error {
+	limit := g.Limit(c)
+	if limit == 0 {
+		return nil
+	}
+
+	used := s.Size()
+	key := string(c) + "/" + session
+	if used < limit {
+		if g.policy == PolicyLog {
+			g.mu.Lock()
+			delete(g.over, key)
+			g.mu.Unlock()
+		}
+		return nil
+	}
+
+	b := Breach{
+		Time:      time.Now(),
+		Component: c,
+		Session:   session,
+		Limit:     limit,
+		Used:      used,
+	}
+
+	switch g.policy {
+	case PolicyReject:
+		b.Action = "rejected"
+		g.record(b)
+		return ErrLimitExceeded
+	case PolicyLog:
+		g.mu.Lock()
+		logged := g.over[key]
+		g.over[key] = true
+		g.mu.Unlock()
+		if logged {
+			return nil
+		}
+		b.Action = "logged"
+		log.Printf("tg-mock: %s memory limit reached (%d of %d bytes, session %q)", c, used, limit, session)
+		g.record(b)
+		return nil
+	default:
+		for s.Size() >= limit && s.EvictOldest() {
+			b.Evicted++
+		}
+		b.Action = "evicted"
+		g.record(b)
+		return nil
+	}
+}
+
+func (g *Guard) record(b Breach) {
+	g.mu.Lock()
+	g.total++
+	g.breaches = append(g.breaches, b)
+	if len(g.breaches) > maxBreaches {
+		g.breaches = g.breaches[len(g.breaches)-maxBreaches:]
+	}
+	onBreach := g.OnBreach
+	g.mu.Unlock()
+
+	if onBreach != nil {
+		onBreach(b)
+	}
+}
+
+// Breaches returns the most recent breaches, oldest first, and the total
+// number recorded.
+func (g *Guard) Breaches() ([]Breach, int) {
+	if g == nil {
+		return []Breach{}, 0
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	result := make([]Breach, len(g.breaches))
+	copy(result, g.breaches)
+	return result, g.total
+}
+
+// Clear forgets all recorded breaches.
+func (g *Guard) Clear() {
+	if g == nil {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.breaches = nil
+	g.total = 0
+	g.over = make(map[string]bool)
+}
+
+// ParsePolicy validates a policy name. An empty name selects DefaultPolicy.
+func ParsePolicy(s string) (Policy, error) {
+	switch p := Policy(strings.ToLower(strings.TrimSpace(s))); p {
+	case "":
+		return DefaultPolicy, nil
+	case PolicyEvict, PolicyReject, PolicyLog:
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown memory policy %q (want evict, reject, or log)", s)
+}
+
+// ParseSize parses a byte size such as "1048576", "512KB", "64MB", or "1GB".
+// Units are binary (1KB = 1024 bytes).
+func ParseSize(s string) (int64, error) {
+	str := strings.ToUpper(strings.TrimSpace(s))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix string
+		n      int64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	} {
+		if strings.HasSuffix(str, unit.suffix) {
+			str = strings.TrimSpace(strings.TrimSuffix(str, unit.suffix))
+			multiplier = unit.n
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * multiplier, nil
+}
+
+// ParseLimits parses ceilings from a map of component name to size, as
+// found in the config file.
+func ParseLimits(m map[string]string) (map[Component]int64, error) {
+	limits := make(map[Component]int64, len(m))
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := Component(strings.TrimSpace(name))
+		if !c.valid() {
+			return nil, fmt.Errorf("unknown memory component %q", name)
+		}
+		n, err := ParseSize(m[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		limits[c] = n
+	}
+	return limits, nil
+}
+
+// ParseLimitList parses ceilings from a comma-separated list such as
+// "recorder=64MB,queue=8MB", as given on the command line.
+func ParseLimitList(s string) (map[Component]int64, error) {
+	m := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		name, size, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid memory limit %q (want component=size)", part)
+		}
+		m[name] = size
+	}
+	return ParseLimits(m)
+}
+
+func (c Component) valid() bool {
+	for _, known := range Components {
+		if c == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,165 @@
+// internal/guard/guard_test.go
+package guard
+
+import (
+	"testing"
+)
+
+// sliceStore is a Store holding entries of the given sizes, oldest first.
+type sliceStore struct {
+	entries []int64
+}
+
+func (s *sliceStore) Size() int64 {
+	var n int64
+	for _, e := range s.entries {
+		n += e
+	}
+	return n
+}
+
+func (s *sliceStore) EvictOldest() bool {
+	if len(s.entries) == 0 {
+		return false
+	}
+	s.entries = s.entries[1:]
+	return true
+}
+
+func TestGuard_Unlimited(t *testing.T) {
+	g := New(nil, PolicyReject)
+	store := &sliceStore{entries: []int64{1 << 20}}
+	if err := g.Admit(Recorder, "", store); err != nil {
+		t.Errorf("expected unlimited component to admit, got %v", err)
+	}
+
+	var nilGuard *Guard
+	if err := nilGuard.Admit(Recorder, "", store); err != nil {
+		t.Errorf("expected nil guard to admit, got %v", err)
+	}
+}
+
+func TestGuard_Evict(t *testing.T) {
+	g := New(map[Component]int64{Recorder: 100}, PolicyEvict)
+	var breaches []Breach
+	g.OnBreach = func(b Breach) { breaches = append(breaches, b) }
+
+	store := &sliceStore{entries: []int64{40, 40}}
+	if err := g.Admit(Recorder, "", store); err != nil {
+		t.Fatalf("Admit below limit: %v", err)
+	}
+	if len(breaches) != 0 {
+		t.Errorf("expected no breach below the limit")
+	}
+
+	store.entries = append(store.entries, 40)
+	if err := g.Admit(Recorder, "s1", store); err != nil {
+		t.Fatalf("Admit with evict policy: %v", err)
+	}
+	if len(store.entries) != 2 {
+		t.Errorf("expected 1 entry to be evicted, %d left", len(store.entries))
+	}
+	if len(breaches) != 1 || breaches[0].Action != "evicted" || breaches[0].Evicted != 1 || breaches[0].Session != "s1" {
+		t.Errorf("unexpected breaches %+v", breaches)
+	}
+
+	recent, total := g.Breaches()
+	if total != 1 || len(recent) != 1 || recent[0].Used != 120 || recent[0].Limit != 100 {
+		t.Errorf("unexpected recorded breaches %+v (total %d)", recent, total)
+	}
+
+	g.Clear()
+	if _, total := g.Breaches(); total != 0 {
+		t.Errorf("expected Clear to forget breaches, got %d", total)
+	}
+}
+
+func TestGuard_Reject(t *testing.T) {
+	g := New(map[Component]int64{Queue: 100}, PolicyReject)
+	store := &sliceStore{entries: []int64{100}}
+
+	if err := g.Admit(Queue, "", store); err != ErrLimitExceeded {
+		t.Errorf("got %v, want ErrLimitExceeded", err)
+	}
+	if len(store.entries) != 1 {
+		t.Errorf("reject policy must not evict")
+	}
+	if _, total := g.Breaches(); total != 1 {
+		t.Errorf("expected rejection to be recorded, got %d breaches", total)
+	}
+}
+
+func TestGuard_LogOnce(t *testing.T) {
+	g := New(map[Component]int64{Files: 100}, PolicyLog)
+	store := &sliceStore{entries: []int64{150}}
+
+	for i := 0; i < 3; i++ {
+		if err := g.Admit(Files, "", store); err != nil {
+			t.Fatalf("log policy must admit, got %v", err)
+		}
+	}
+	if _, total := g.Breaches(); total != 1 {
+		t.Errorf("expected crossing to be logged once, got %d breaches", total)
+	}
+
+	// Dropping below the limit re-arms the log
+	store.entries = nil
+	g.Admit(Files, "", store)
+	store.entries = []int64{150}
+	g.Admit(Files, "", store)
+	if _, total := g.Breaches(); total != 2 {
+		t.Errorf("expected second crossing to be logged, got %d breaches", total)
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"1024":  1024,
+		"512KB": 512 << 10,
+		"64MB":  64 << 20,
+		"64m":   64 << 20,
+		"1 GB":  1 << 30,
+		"100B":  100,
+		" 2k ":  2048,
+	}
+	for in, want := range tests {
+		got, err := ParseSize(in)
+		if err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{"", "MB", "-1", "1.5MB", "ten"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) should fail", in)
+		}
+	}
+}
+
+func TestParseLimitList(t *testing.T) {
+	limits, err := ParseLimitList("recorder=64MB, queue=1KB")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits[Recorder] != 64<<20 || limits[Queue] != 1024 || limits[Files] != 0 {
+		t.Errorf("unexpected limits %v", limits)
+	}
+
+	for _, in := range []string{"recorder", "bogus=1MB", "queue=lots"} {
+		if _, err := ParseLimitList(in); err == nil {
+			t.Errorf("ParseLimitList(%q) should fail", in)
+		}
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if p, err := ParsePolicy(""); err != nil || p != DefaultPolicy {
+		t.Errorf("empty policy: got %q, %v", p, err)
+	}
+	if p, err := ParsePolicy("Reject"); err != nil || p != PolicyReject {
+		t.Errorf("got %q, %v", p, err)
+	}
+	if _, err := ParsePolicy("panic"); err == nil {
+		t.Error("expected unknown policy to fail")
+	}
+}
@@ -0,0 +1,154 @@
+// Package hooks lets users extend tg-mock with behaviors it doesn't model
+// natively. Hooks see every Bot API call before it is validated and every
+// generated response before it is sent, and can change either, answer a
+// call themselves, or veto it with an error.
+package hooks
+
+import (
+	"sync"
+)
+
+// Call is a Bot API call passing through the hooks.
+type Call struct {
+	Session string                 `json:"session"`
+	Token   string                 `json:"token"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params"`
+}
+
+// Response is the answer to a call, as sent to the bot.
+type Response struct {
+	OK          bool        `json:"ok"`
+	Result      interface{} `json:"result,omitempty"`
+	ErrorCode   int         `json:"error_code,omitempty"`
+	Description string      `json:"description,omitempty"`
+	RetryAfter  int         `json:"retry_after,omitempty"`
+}
+
+// Result returns a successful response with result.
+func Result(result interface{}) *Response {
+	return &Response{OK: true, Result: result}
+}
+
+// Error returns an error response, vetoing the call.
+func Error(code int, description string) *Response {
+	return &Response{ErrorCode: code, Description: description}
+}
+
+// Hook intercepts Bot API calls.
+type Hook struct {
+	// Name identifies the hook in recorded requests and the control API.
+	Name string
+	// Methods restricts the hook to these methods (empty = all).
+	Methods []string
+
+	// Before runs once the parameters of a call are parsed, before the
+	// call is validated or matched against scenarios. It may change
+	// call.Params. Returning a response answers the call with it, skipping
+	// everything else; return an error response to veto the call.
+	Before func(call *Call) *Response
+	// After runs once a response has been generated for a call, before
+	// it is sent. It may change the response, including turning it into
+	// an error. Errors from scenarios and validation don't pass through
+	// After hooks.
+	After func(call *Call, resp *Response)
+}
+
+func (h Hook) applies(method string) bool {
+	if len(h.Methods) == 0 {
+		return true
+	}
+	for _, m := range h.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// Chain runs hooks in the order they were added.
+type Chain struct {
+	mu    sync.RWMutex
+	hooks []Hook
+}
+
+// NewChain creates a chain of hooks.
+func NewChain(hooks ...Hook) *Chain {
+	return &Chain{hooks: append([]Hook(nil), hooks...)}
+}
+
+// Add appends a hook to the chain. A hook with the same name replaces
+// the earlier one, keeping its position.
+func (c *Chain) Add(h Hook) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, existing := range c.hooks {
+		if h.Name != "" && existing.Name == h.Name {
+			c.hooks[i] = h
+			return
+		}
+	}
+	c.hooks = append(c.hooks, h)
+}
+
+// Remove removes the named hook, reporting whether it existed.
+func (c *Chain) Remove(name string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, h := range c.hooks {
+		if h.Name == name {
+			c.hooks = append(c.hooks[:i], c.hooks[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Names returns the names of the hooks, in order.
+func (c *Chain) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, len(c.hooks))
+	for i, h := range c.hooks {
+		names[i] = h.Name
+	}
+	return names
+}
+
+// Len returns the number of hooks.
+func (c *Chain) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.hooks)
+}
+
+// Before runs the Before hooks of the call's method. It stops at the
+// first hook that answers the call and returns its name and response.
+func (c *Chain) Before(call *Call) (string, *Response) {
+	for _, h := range c.snapshot() {
+		if h.Before == nil || !h.applies(call.Method) {
+			continue
+		}
+		if resp := h.Before(call); resp != nil {
+			return h.Name, resp
+		}
+	}
+	return "", nil
+}
+
+// After runs the After hooks of the call's method on resp.
+func (c *Chain) After(call *Call, resp *Response) {
+	for _, h := range c.snapshot() {
+		if h.After != nil && h.applies(call.Method) {
+			h.After(call, resp)
+		}
+	}
+}
+
+// snapshot copies the hooks so they run without holding the lock, which
+// lets hooks add or remove hooks themselves.
+func (c *Chain) snapshot() []Hook {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Hook(nil), c.hooks...)
+}
@@ -0,0 +1,144 @@
+// internal/hooks/hooks_test.go
+package hooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestChain(t *testing.T) {
+	var order []string
+	c := NewChain(
+		Hook{Name: "upper", Before: func(call *Call) *Response {
+			order = append(order, "upper")
+			call.Params["text"] = "HI"
+			return nil
+		}},
+		Hook{Name: "photos-only", Methods: []string{"sendPhoto"}, Before: func(call *Call) *Response {
+			order = append(order, "photos-only")
+			return nil
+		}},
+		Hook{Name: "veto", Before: func(call *Call) *Response {
+			order = append(order, "veto")
+			if call.Params["chat_id"] == float64(13) {
+				return Error(403, "Forbidden: unlucky chat")
+			}
+			return nil
+		}},
+		Hook{Name: "late", Before: func(call *Call) *Response {
+			order = append(order, "late")
+			return nil
+		}},
+	)
+
+	call := &Call{Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(13), "text": "hi"}}
+	name, resp := c.Before(call)
+	if name != "veto" || resp == nil || resp.ErrorCode != 403 {
+		t.Fatalf("expected the veto hook to answer, got %q %+v", name, resp)
+	}
+	if call.Params["text"] != "HI" {
+		t.Errorf("expected params to be changed, got %v", call.Params)
+	}
+	if want := []string{"upper", "veto"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("hooks ran as %v, want %v", order, want)
+	}
+
+	c.Add(Hook{Name: "veto"})
+	if _, resp := c.Before(&Call{Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(13)}}); resp != nil {
+		t.Errorf("expected the replaced hook not to answer, got %+v", resp)
+	}
+	if want := []string{"upper", "photos-only", "veto", "late"}; !reflect.DeepEqual(c.Names(), want) {
+		t.Errorf("names = %v, want %v", c.Names(), want)
+	}
+	if !c.Remove("late") || c.Remove("late") || c.Len() != 3 {
+		t.Errorf("unexpected hooks after Remove: %v", c.Names())
+	}
+}
+
+func TestChainAfter(t *testing.T) {
+	c := NewChain(
+		Hook{Methods: []string{"sendMessage"}, After: func(call *Call, resp *Response) {
+			resp.Result.(map[string]interface{})["text"] = "changed"
+		}},
+		Hook{Methods: []string{"deleteMessage"}, After: func(call *Call, resp *Response) {
+			*resp = *Error(400, "Bad Request: message can't be deleted")
+		}},
+	)
+
+	resp := Result(map[string]interface{}{"text": "original"})
+	c.After(&Call{Method: "sendMessage"}, resp)
+	if !resp.OK || resp.Result.(map[string]interface{})["text"] != "changed" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	resp = Result(true)
+	c.After(&Call{Method: "deleteMessage"}, resp)
+	if resp.OK || resp.ErrorCode != 400 {
+		t.Errorf("expected an error response, got %+v", resp)
+	}
+}
+
+func TestHTTPHook(t *testing.T) {
+	var phases []string
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req httpRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		phases = append(phases, req.Phase)
+		switch {
+		case req.Call.Method == "getMe":
+			w.WriteHeader(http.StatusNoContent)
+		case req.Phase == PhaseBefore:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"params": map[string]interface{}{"chat_id": 1, "text": "rewritten"},
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"response": map[string]interface{}{"ok": false, "error_code": 429, "description": "Too Many Requests: retry after 3", "retry_after": 3},
+			})
+		}
+	}))
+	defer endpoint.Close()
+
+	cfg := HTTPConfig{URL: endpoint.URL}
+	if err := cfg.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	h := NewHTTP(cfg)
+	if h.Name != endpoint.URL {
+		t.Errorf("expected the URL as name, got %q", h.Name)
+	}
+
+	call := &Call{Method: "sendMessage", Params: map[string]interface{}{"text": "original"}}
+	if resp := h.Before(call); resp != nil {
+		t.Errorf("expected no response, got %+v", resp)
+	}
+	if call.Params["text"] != "rewritten" {
+		t.Errorf("expected params to be replaced, got %v", call.Params)
+	}
+	resp := Result(true)
+	h.After(call, resp)
+	if resp.OK || resp.ErrorCode != 429 || resp.RetryAfter != 3 {
+		t.Errorf("expected the response to be replaced, got %+v", resp)
+	}
+
+	call = &Call{Method: "getMe", Params: map[string]interface{}{}}
+	if resp := h.Before(call); resp != nil || len(call.Params) != 0 {
+		t.Errorf("expected 204 to leave the call alone, got %+v %v", resp, call.Params)
+	}
+	if want := []string{PhaseBefore, PhaseAfter, PhaseBefore}; !reflect.DeepEqual(phases, want) {
+		t.Errorf("phases = %v, want %v", phases, want)
+	}
+
+	if h := NewHTTP(HTTPConfig{URL: endpoint.URL, Phases: []string{PhaseAfter}}); h.Before != nil || h.After == nil {
+		t.Error("expected only an After hook")
+	}
+	if err := (HTTPConfig{URL: endpoint.URL, Phases: []string{"during"}}).Validate(); err == nil {
+		t.Error("expected an unknown phase to be rejected")
+	}
+	if err := (HTTPConfig{}).Validate(); err == nil {
+		t.Error("expected a missing URL to be rejected")
+	}
+}
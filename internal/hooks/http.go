@@ -0,0 +1,142 @@
+// internal/hooks/http.go
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+)
+
+// Phases of a call.
+const (
+	PhaseBefore = "before"
+	PhaseAfter  = "after"
+)
+
+// DefaultHTTPTimeout bounds how long a call waits for an HTTP hook.
+const DefaultHTTPTimeout = 5 * time.Second
+
+// HTTPConfig describes a hook implemented by an HTTP endpoint, so hooks
+// can be written in any language and set up from the config file.
+type HTTPConfig struct {
+	Name    string
+	URL     string
+	Methods []string
+	// Phases selects when the endpoint is called (empty = both).
+	Phases  []string
+	Timeout time.Duration
+}
+
+// Validate checks the config.
+func (c HTTPConfig) Validate() error {
+	if c.URL == "" {
+		return fmt.Errorf("url is required")
+	}
+	for _, p := range c.Phases {
+		if p != PhaseBefore && p != PhaseAfter {
+			return fmt.Errorf("unknown phase %q (want before or after)", p)
+		}
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout must not be negative")
+	}
+	return nil
+}
+
+// httpRequest is posted to an HTTP hook.
+type httpRequest struct {
+	Phase    string    `json:"phase"`
+	Call     *Call     `json:"call"`
+	Response *Response `json:"response,omitempty"`
+}
+
+// httpReply is what an HTTP hook may answer. Both fields are optional; an
+// empty body or 204 No Content leaves the call alone.
+type httpReply struct {
+	// Params replaces the parameters of the call (before only).
+	Params map[string]interface{} `json:"params"`
+	// Response answers the call (before) or replaces its response (after).
+	Response *Response `json:"response"`
+}
+
+// NewHTTP returns a hook that posts each call, as JSON with its phase and,
+// after the response is generated, the response, to the configured URL.
+// An endpoint that fails or times out is logged and leaves the call alone.
+func NewHTTP(cfg HTTPConfig) Hook {
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultHTTPTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	wants := func(phase string) bool {
+		if len(cfg.Phases) == 0 {
+			return true
+		}
+		for _, p := range cfg.Phases {
+			if p == phase {
+				return true
+			}
+		}
+		return false
+	}
+	name := cfg.Name
+	if name == "" {
+		name = cfg.URL
+	}
+
+	h := Hook{Name: name, Methods: cfg.Methods}
+	if wants(PhaseBefore) {
+		h.Before = func(call *Call) *Response {
+			reply := postHook(client, cfg.URL, httpRequest{Phase: PhaseBefore, Call: call})
+			if reply == nil {
+				return nil
+			}
+			if reply.Params != nil {
+				call.Params = reply.Params
+			}
+			return reply.Response
+		}
+	}
+	if wants(PhaseAfter) {
+		h.After = func(call *Call, resp *Response) {
+			reply := postHook(client, cfg.URL, httpRequest{Phase: PhaseAfter, Call: call, Response: resp})
+			if reply != nil && reply.Response != nil {
+				*resp = *reply.Response
+			}
+		}
+	}
+	return h
+}
+
+func postHook(client *http.Client, url string, req httpRequest) *httpReply {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Printf("tg-mock: hook %s: %v", url, err)
+		return nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if resp.StatusCode >= 300 {
+		log.Printf("tg-mock: hook %s: unexpected status %d", url, resp.StatusCode)
+		return nil
+	}
+	var reply httpReply
+	if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+		// An empty body leaves the call alone
+		if err != io.EOF {
+			log.Printf("tg-mock: hook %s: invalid reply: %v", url, err)
+		}
+		return nil
+	}
+	return &reply
+}
@@ -0,0 +1,139 @@
+// Package inlinequery tracks inline queries injected as updates, so that
+// answerInlineQuery calls arriving after Telegram's answer deadline fail
+// the way they do in production.
+package inlinequery
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Validity is how long Telegram accepts an answer to an inline query after
+// the query was sent to the bot.
+const Validity = 10 * time.Second
+
+// Status describes a single tracked inline query.
+type Status struct {
+	ID         string    `json:"id"`
+	ReceivedAt time.Time `json:"received_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// AnsweredAt is the time of the first answer that arrived in time.
+	AnsweredAt *time.Time `json:"answered_at,omitempty"`
+	// Pending is true while the query can still be answered.
+	Pending bool `json:"pending"`
+
+	// LatencyMs is the time the bot took to answer in time.
+	LatencyMs int64 `json:"latency_ms,omitempty"`
+	// LateAnswers counts answers rejected because the query had expired.
+	LateAnswers int `json:"late_answers"`
+}
+
+// Tracker records inline queries by ID, measured against a clock.
+type Tracker struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	queries map[string]*Status
+}
+
+// NewTracker creates a tracker reading the time from now. If now is nil,
+// the system clock is used.
+func NewTracker(now func() time.Time) *Tracker {
+	if now == nil {
+		now = time.Now
+	}
+	return &Tracker{
+		now:     now,
+		queries: make(map[string]*Status),
+	}
+}
+
+// Track notes that an inline query was sent to the bot. Queries that are
+// already tracked keep their original timestamp.
+func (t *Tracker) Track(id string) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if _, ok := t.queries[id]; ok {
+		return
+	}
+	t.queries[id] = &Status{
+		ID:         id,
+		ReceivedAt: now,
+		ExpiresAt:  now.Add(Validity),
+	}
+}
+
+// Answer records an answer to the query and reports whether it arrived
+// before the deadline. Unknown queries, e.g. IDs made up by a test without
+// injecting an update, are always accepted.
+func (t *Tracker) Answer(id string) bool {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := t.queries[id]
+	if s == nil {
+		return true
+	}
+	if !now.Before(s.ExpiresAt) {
+		s.LateAnswers++
+		return false
+	}
+	if s.AnsweredAt == nil {
+		s.AnsweredAt = &now
+		s.LatencyMs = now.Sub(s.ReceivedAt).Milliseconds()
+	}
+	return true
+}
+
+// Get returns the status of a query.
+func (t *Tracker) Get(id string) (Status, bool) {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.queries[id]
+	if !ok {
+		return Status{}, false
+	}
+	return s.at(now), true
+}
+
+// List returns the status of every tracked query, oldest first.
+func (t *Tracker) List() []Status {
+	now := t.now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make([]Status, 0, len(t.queries))
+	for _, s := range t.queries {
+		result = append(result, s.at(now))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].ReceivedAt.Equal(result[j].ReceivedAt) {
+			return result[i].ReceivedAt.Before(result[j].ReceivedAt)
+		}
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// Reset removes all tracked queries.
+func (t *Tracker) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.queries = make(map[string]*Status)
+}
+
+// at returns a copy of the status with the deadline evaluated at now.
+func (s *Status) at(now time.Time) Status {
+	c := *s
+	c.Pending = s.AnsweredAt == nil && now.Before(s.ExpiresAt)
+	return c
+}
@@ -0,0 +1,58 @@
+// internal/inlinequery/tracker_test.go
+package inlinequery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker_Deadline(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := NewTracker(func() time.Time { return now })
+
+	tr.Track("q1")
+	tr.Track("q2")
+	if s, _ := tr.Get("q1"); !s.Pending {
+		t.Errorf("expected new query to be pending, got %+v", s)
+	}
+
+	now = now.Add(3 * time.Second)
+	if !tr.Answer("q1") {
+		t.Error("expected answer within 10 seconds to be accepted")
+	}
+	s, _ := tr.Get("q1")
+	if s.Pending || s.AnsweredAt == nil || s.LatencyMs != 3000 {
+		t.Errorf("unexpected status after answer %+v", s)
+	}
+
+	now = now.Add(7 * time.Second)
+	if tr.Answer("q2") {
+		t.Error("expected answer after 10 seconds to be rejected")
+	}
+	s, _ = tr.Get("q2")
+	if s.Pending || s.AnsweredAt != nil || s.LateAnswers != 1 {
+		t.Errorf("unexpected status after late answer %+v", s)
+	}
+}
+
+func TestTracker_UnknownAndReset(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	tr := NewTracker(func() time.Time { return now })
+
+	if !tr.Answer("made-up") {
+		t.Error("expected answers to untracked queries to be accepted")
+	}
+
+	tr.Track("q1")
+	now = now.Add(5 * time.Second)
+	tr.Track("q1") // Re-tracking keeps the original deadline
+	now = now.Add(5 * time.Second)
+	if tr.Answer("q1") {
+		t.Error("expected deadline to be measured from the first Track")
+	}
+
+	tr.Reset()
+	if len(tr.List()) != 0 {
+		t.Error("expected no queries after reset")
+	}
+}
@@ -0,0 +1,141 @@
+// internal/inspector/duplicates.go
+package inspector
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// DefaultDuplicateWindow is how long after an error response repeated
+// sends are considered retries of the failed call.
+const DefaultDuplicateWindow = time.Minute
+
+// Reasons a group of sends is flagged as a duplicate.
+const (
+	// DuplicateDelivered means more than one of the calls succeeded, so
+	// the chat received the message several times.
+	DuplicateDelivered = "delivered_twice"
+	// DuplicateUnknownOutcome means a call was retried after a server
+	// error. Telegram may already have delivered the failed call, as it
+	// does while restarting, so the retry can double-message the user.
+	DuplicateUnknownOutcome = "unknown_outcome"
+)
+
+// DuplicateSend is a likely duplicate: the same message sent to the same
+// chat again within the window following an error response.
+type DuplicateSend struct {
+	Method string `json:"method"`
+	ChatID string `json:"chat_id"`
+	// Text is the text or caption of the message, if it has one.
+	Text   string `json:"text,omitempty"`
+	Reason string `json:"reason"`
+	// RequestIDs lists every call in the group, oldest first. The first
+	// one is the error that started it.
+	RequestIDs []int64   `json:"request_ids"`
+	Errors     int       `json:"errors"`
+	Delivered  int       `json:"delivered"`
+	FirstAt    time.Time `json:"first_at"`
+	LastAt     time.Time `json:"last_at"`
+}
+
+// duplicateGroup collects the calls following an error that sent the same
+// message to the same chat.
+type duplicateGroup struct {
+	DuplicateSend
+	serverError bool
+}
+
+// DuplicateSends looks for send calls that failed and were then sent again
+// with the same method, chat, and content within window. A group is
+// flagged when more than one of its calls succeeded, or when a call that
+// failed with a server error was retried successfully. Results are
+// ordered by their first request. A window of 0 uses
+// DefaultDuplicateWindow.
+func (r *Recorder) DuplicateSends(window time.Duration) []DuplicateSend {
+	if window <= 0 {
+		window = DefaultDuplicateWindow
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]DuplicateSend, 0)
+	open := make(map[string]*duplicateGroup)
+	finish := func(g *duplicateGroup) {
+		switch {
+		case g.Delivered > 1:
+			g.Reason = DuplicateDelivered
+		case g.serverError && g.Delivered > 0:
+			g.Reason = DuplicateUnknownOutcome
+		default:
+			return
+		}
+		result = append(result, g.DuplicateSend)
+	}
+
+	for _, req := range r.requests {
+		chat := chatKey(req.Params)
+		if chat == "" || !isSendMethod(req.Method) {
+			continue
+		}
+		key := req.Method + " " + chat + " " + contentKey(req.Params)
+
+		g := open[key]
+		if g != nil && req.Timestamp.Sub(g.FirstAt) > window {
+			finish(g)
+			delete(open, key)
+			g = nil
+		}
+		if g == nil {
+			// Only an error response starts a group
+			if !req.IsError {
+				continue
+			}
+			g = &duplicateGroup{DuplicateSend: DuplicateSend{
+				Method:  req.Method,
+				ChatID:  chat,
+				Text:    messageText(req.Params),
+				FirstAt: req.Timestamp,
+			}}
+			open[key] = g
+		}
+
+		g.RequestIDs = append(g.RequestIDs, req.ID)
+		g.LastAt = req.Timestamp
+		if req.IsError {
+			g.Errors++
+			if req.StatusCode >= 500 {
+				g.serverError = true
+			}
+		} else {
+			g.Delivered++
+		}
+	}
+	for _, g := range open {
+		finish(g)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].RequestIDs[0] < result[j].RequestIDs[0] })
+	return result
+}
+
+// messageText returns the text or caption of a send call.
+func messageText(params map[string]interface{}) string {
+	for _, key := range []string{"text", "caption"} {
+		if s, ok := params[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// contentKey identifies what a send call sends: its text or caption, or
+// all of its parameters for messages without either.
+func contentKey(params map[string]interface{}) string {
+	if text := messageText(params); text != "" {
+		return text
+	}
+	data, _ := json.Marshal(params)
+	return string(data)
+}
@@ -0,0 +1,62 @@
+// internal/inspector/duplicates_test.go
+package inspector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRecorder_DuplicateSends(t *testing.T) {
+	r := NewRecorder()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	send := func(offset time.Duration, chat float64, text string, status int) {
+		r.Record(RequestRecord{
+			Timestamp:  start.Add(offset),
+			Method:     "sendMessage",
+			Params:     map[string]interface{}{"chat_id": chat, "text": text},
+			IsError:    status != 200,
+			StatusCode: status,
+		})
+	}
+
+	// Rate limited, then retried twice: delivered twice
+	send(0, 1, "hello", 429)
+	send(time.Second, 1, "hello", 200)
+	send(2*time.Second, 1, "hello", 200)
+
+	// Server error, then retried once: may have been delivered already
+	send(0, 2, "hello", 500)
+	send(time.Second, 2, "hello", 200)
+
+	// Rate limited, then retried once: fine
+	send(0, 3, "hello", 429)
+	send(time.Second, 3, "hello", 200)
+
+	// Sent again long after the error: not a retry
+	send(0, 4, "hello", 500)
+	send(2*time.Minute, 4, "hello", 200)
+
+	// Repeated successful sends without an error aren't flagged
+	send(0, 5, "hello", 200)
+	send(time.Second, 5, "hello", 200)
+
+	// A different text is a different message
+	send(0, 6, "one", 429)
+	send(time.Second, 6, "one", 200)
+	send(2*time.Second, 6, "two", 200)
+
+	dups := r.DuplicateSends(time.Minute)
+	if len(dups) != 2 {
+		t.Fatalf("got %d duplicates, want 2: %+v", len(dups), dups)
+	}
+	if d := dups[0]; d.ChatID != "1" || d.Reason != DuplicateDelivered || d.Delivered != 2 || d.Errors != 1 || len(d.RequestIDs) != 3 || d.Text != "hello" {
+		t.Errorf("unexpected first duplicate %+v", d)
+	}
+	if d := dups[1]; d.ChatID != "2" || d.Reason != DuplicateUnknownOutcome || d.Delivered != 1 {
+		t.Errorf("unexpected second duplicate %+v", d)
+	}
+
+	if dups := r.DuplicateSends(5 * time.Minute); len(dups) != 3 {
+		t.Errorf("got %d duplicates with a longer window, want 3", len(dups))
+	}
+}
@@ -0,0 +1,86 @@
+// internal/inspector/journal.go
+package inspector
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"sync"
+)
+
+// JournalEntry is a single line of a request journal.
+type JournalEntry struct {
+	// Session is the session the request was made in, empty for the
+	// default session.
+	Session string `json:"session,omitempty"`
+	RequestRecord
+}
+
+// Journal appends recorded requests to a JSONL file as they are captured,
+// so request history outlives the process and can be analyzed after a run.
+type Journal struct {
+	mu   sync.Mutex
+	file *os.File
+	err  error
+}
+
+// OpenJournal opens path for appending, creating it if needed.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{file: f}, nil
+}
+
+// Append writes a record as one line. After the first write error the
+// journal is disabled and the error is returned for every later call.
+func (j *Journal) Append(session string, req RequestRecord) error {
+	data, err := json.Marshal(JournalEntry{Session: session, RequestRecord: req})
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err != nil {
+		return j.err
+	}
+	if _, err := j.file.Write(data); err != nil {
+		j.err = err
+		log.Printf("tg-mock: request journal disabled: %v", err)
+		return err
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err == nil {
+		j.err = os.ErrClosed
+	}
+	return j.file.Close()
+}
+
+// ReadJournal decodes the entries of a request journal.
+func ReadJournal(r io.Reader) ([]JournalEntry, error) {
+	var entries []JournalEntry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
@@ -0,0 +1,54 @@
+// internal/inspector/journal_test.go
+package inspector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestJournal_AppendsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "requests.jsonl")
+
+	j, err := OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewRecorder()
+	r.OnRecord = func(req RequestRecord) { j.Append("", req) }
+	r.Record(RequestRecord{Method: "sendMessage", Token: "123:abc", StatusCode: 200})
+	j.Close()
+
+	// A new process appends to the same file
+	j, err = OpenJournal(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.Append("job-1", RequestRecord{ID: 1, Method: "getMe", IsError: true, StatusCode: 401}); err != nil {
+		t.Fatal(err)
+	}
+	j.Close()
+
+	if err := j.Append("", RequestRecord{Method: "getMe"}); err == nil {
+		t.Error("expected append after close to fail")
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	entries, err := ReadJournal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Session != "" || entries[0].Method != "sendMessage" || entries[0].ID != 1 {
+		t.Errorf("unexpected first entry %+v", entries[0])
+	}
+	if entries[1].Session != "job-1" || entries[1].Method != "getMe" || !entries[1].IsError {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+}
@@ -2,6 +2,9 @@
 package inspector
 
 import (
+	"context"
+	"encoding/json"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -15,9 +18,14 @@ type RequestRecord struct {
 	Method     string                 `json:"method"`
 	Params     map[string]interface{} `json:"params"`
 	ScenarioID string                 `json:"scenario_id,omitempty"`
-	Response   interface{}            `json:"response"`
-	IsError    bool                   `json:"is_error"`
-	StatusCode int                    `json:"status_code"`
+	// ScenarioRevision is the revision of the scenario set when the
+	// request was handled.
+	ScenarioRevision int64       `json:"scenario_revision"`
+	Response         interface{} `json:"response"`
+	IsError          bool        `json:"is_error"`
+	StatusCode       int         `json:"status_code"`
+
+	size int64 // Approximate memory held, see recordSize
 }
 
 // Recorder stores and retrieves recorded Bot API requests.
@@ -25,12 +33,22 @@ type Recorder struct {
 	mu        sync.RWMutex
 	requests  []RequestRecord
 	idCounter int64
+	// changed is closed and replaced whenever a request is recorded,
+	// waking up any callers blocked in Wait.
+	changed chan struct{}
+	stats   map[string]*ChatStats
+	bytes   int64
+
+	// OnRecord, if set, is called with every recorded request, in order.
+	OnRecord func(RequestRecord)
 }
 
 // NewRecorder creates a new empty request recorder.
 func NewRecorder() *Recorder {
 	return &Recorder{
 		requests: make([]RequestRecord, 0),
+		changed:  make(chan struct{}),
+		stats:    make(map[string]*ChatStats),
 	}
 }
 
@@ -44,26 +62,75 @@ func (r *Recorder) Record(req RequestRecord) int64 {
 		req.Timestamp = time.Now()
 	}
 
+	req.size = recordSize(req)
 	r.requests = append(r.requests, req)
+	r.bytes += req.size
+	r.recordStats(req)
+	if r.OnRecord != nil {
+		r.OnRecord(req)
+	}
+	close(r.changed)
+	r.changed = make(chan struct{})
 	return req.ID
 }
 
+// Filter selects recorded requests. Zero-valued fields match everything.
+type Filter struct {
+	Method     string
+	Token      string
+	ScenarioID string
+	// Since and Until bound the request timestamp (inclusive).
+	Since time.Time
+	Until time.Time
+	// StatusCode matches the HTTP status code returned.
+	StatusCode int
+	// IsError, if set, matches failed or successful requests only.
+	IsError *bool
+	// After matches requests recorded after the one with this ID.
+	After int64
+}
+
+// Match reports whether req passes the filter.
+func (f Filter) Match(req RequestRecord) bool {
+	switch {
+	case f.Method != "" && req.Method != f.Method:
+		return false
+	case f.Token != "" && req.Token != f.Token:
+		return false
+	case f.ScenarioID != "" && req.ScenarioID != f.ScenarioID:
+		return false
+	case !f.Since.IsZero() && req.Timestamp.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && req.Timestamp.After(f.Until):
+		return false
+	case f.StatusCode != 0 && req.StatusCode != f.StatusCode:
+		return false
+	case f.IsError != nil && req.IsError != *f.IsError:
+		return false
+	case req.ID <= f.After:
+		return false
+	}
+	return true
+}
+
 // List returns recorded requests with optional filtering.
 // method: filter by method name (empty = all methods)
 // token: filter by token (empty = all tokens)
 // limit: maximum number of records to return (0 = all)
 func (r *Recorder) List(method, token string, limit int) []RequestRecord {
+	return r.Find(Filter{Method: method, Token: token}, limit)
+}
+
+// Find returns the recorded requests matching f, oldest first, up to
+// limit (0 = all).
+func (r *Recorder) Find(f Filter, limit int) []RequestRecord {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	result := make([]RequestRecord, 0)
 
 	for _, req := range r.requests {
-		// Apply filters
-		if method != "" && req.Method != method {
-			continue
-		}
-		if token != "" && req.Token != token {
+		if !f.Match(req) {
 			continue
 		}
 
@@ -77,6 +144,73 @@ func (r *Recorder) List(method, token string, limit int) []RequestRecord {
 	return result
 }
 
+// Get returns the recorded request with the given ID, if it is still held.
+func (r *Recorder) Get(id int64) (RequestRecord, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	i := sort.Search(len(r.requests), func(i int) bool { return r.requests[i].ID >= id })
+	if i < len(r.requests) && r.requests[i].ID == id {
+		return r.requests[i], true
+	}
+	return RequestRecord{}, false
+}
+
+// Page returns up to limit requests matching f, newest first, starting
+// below the record ID cursor (0 = from the newest request). next is the
+// cursor for the following page, or 0 if there are no older matches.
+func (r *Recorder) Page(f Filter, cursor int64, limit int) (page []RequestRecord, next int64) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	page = make([]RequestRecord, 0)
+	for i := len(r.requests) - 1; i >= 0; i-- {
+		req := r.requests[i]
+		if cursor > 0 && req.ID >= cursor {
+			continue
+		}
+		if !f.Match(req) {
+			continue
+		}
+		if limit > 0 && len(page) == limit {
+			// There is at least one more match
+			return page, page[len(page)-1].ID
+		}
+		page = append(page, req)
+	}
+	return page, 0
+}
+
+// Wait blocks until at least count requests matching f have been
+// recorded, or ctx is done. It returns the matching requests (at most
+// count) along with ctx.Err() if the wait was cut short.
+func (r *Recorder) Wait(ctx context.Context, f Filter, count int) ([]RequestRecord, error) {
+	if count < 1 {
+		count = 1
+	}
+	for {
+		r.mu.RLock()
+		changed := r.changed
+		r.mu.RUnlock()
+
+		requests := r.Find(f, count)
+		if len(requests) >= count {
+			return requests, nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return requests, ctx.Err()
+		}
+	}
+}
+
+// LastID returns the ID of the latest recorded request, or 0 if none was
+// recorded. Requests recorded later have higher IDs.
+func (r *Recorder) LastID() int64 {
+	return atomic.LoadInt64(&r.idCounter)
+}
+
 // Count returns the total number of recorded requests.
 func (r *Recorder) Count() int {
 	r.mu.RLock()
@@ -84,9 +218,50 @@ func (r *Recorder) Count() int {
 	return len(r.requests)
 }
 
+// Size returns the approximate number of bytes held by recorded requests.
+func (r *Recorder) Size() int64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.bytes
+}
+
+// EvictOldest drops the oldest recorded request. Statistics are kept, as
+// they are accumulated when requests are recorded. It returns false if
+// there is nothing to evict.
+func (r *Recorder) EvictOldest() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.requests) == 0 {
+		return false
+	}
+	r.bytes -= r.requests[0].size
+	r.requests[0] = RequestRecord{}
+	r.requests = r.requests[1:]
+	return true
+}
+
 // Clear removes all recorded requests.
 func (r *Recorder) Clear() {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	r.requests = make([]RequestRecord, 0)
+	r.stats = make(map[string]*ChatStats)
+	r.bytes = 0
+}
+
+// recordOverhead approximates the fixed cost of a record beyond its
+// encoded parameters and response.
+const recordOverhead = 256
+
+// recordSize estimates the memory held by a record from the size of its
+// JSON encoding.
+func recordSize(req RequestRecord) int64 {
+	size := int64(recordOverhead + len(req.Token) + len(req.Method) + len(req.ScenarioID))
+	if data, err := json.Marshal(req.Params); err == nil {
+		size += int64(len(data))
+	}
+	if data, err := json.Marshal(req.Response); err == nil {
+		size += int64(len(data))
+	}
+	return size
 }
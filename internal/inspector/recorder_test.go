@@ -2,6 +2,8 @@
 package inspector
 
 import (
+	"context"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
@@ -265,3 +267,191 @@ func TestRecorder_ThreadSafety(t *testing.T) {
 		t.Errorf("count after concurrent records = %d, want 100", r.Count())
 	}
 }
+
+func TestRecorder_Find(t *testing.T) {
+	r := NewRecorder()
+	base := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	r.Record(RequestRecord{Timestamp: base, Method: "sendMessage", StatusCode: 200})
+	r.Record(RequestRecord{Timestamp: base.Add(time.Minute), Method: "sendMessage", ScenarioID: "flood", IsError: true, StatusCode: 429})
+	r.Record(RequestRecord{Timestamp: base.Add(2 * time.Minute), Method: "getMe", IsError: true, StatusCode: 401})
+	r.Record(RequestRecord{Timestamp: base.Add(3 * time.Minute), Method: "getMe", StatusCode: 200})
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{"all", Filter{}, []int64{1, 2, 3, 4}},
+		{"since", Filter{Since: base.Add(time.Minute)}, []int64{2, 3, 4}},
+		{"until", Filter{Until: base.Add(time.Minute)}, []int64{1, 2}},
+		{"time range", Filter{Since: base.Add(time.Minute), Until: base.Add(2 * time.Minute)}, []int64{2, 3}},
+		{"status code", Filter{StatusCode: 200}, []int64{1, 4}},
+		{"errors only", Filter{IsError: &yes}, []int64{2, 3}},
+		{"successes only", Filter{IsError: &no}, []int64{1, 4}},
+		{"scenario", Filter{ScenarioID: "flood"}, []int64{2}},
+		{"after", Filter{After: 2}, []int64{3, 4}},
+		{"combined", Filter{Method: "getMe", IsError: &yes}, []int64{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, req := range r.Find(tt.filter, 0) {
+				got = append(got, req.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got IDs %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := r.Find(Filter{IsError: &no}, 1); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("expected limit to apply after filtering, got %+v", got)
+	}
+	if got := r.LastID(); got != 4 {
+		t.Errorf("expected last ID 4, got %d", got)
+	}
+}
+
+func TestRecorder_Wait(t *testing.T) {
+	r := NewRecorder()
+	r.Record(RequestRecord{Method: "getMe", Token: "123:abc"})
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		r.Record(RequestRecord{Method: "sendMessage", Token: "123:abc"})
+		r.Record(RequestRecord{Method: "sendMessage", Token: "123:abc"})
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	requests, err := r.Wait(ctx, Filter{Method: "sendMessage"}, 2)
+	if err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+	if len(requests) != 2 {
+		t.Errorf("got %d requests, want 2", len(requests))
+	}
+}
+
+func TestRecorder_WaitAlreadySatisfied(t *testing.T) {
+	r := NewRecorder()
+	r.Record(RequestRecord{Method: "sendMessage", Token: "123:abc"})
+
+	requests, err := r.Wait(context.Background(), Filter{Method: "sendMessage"}, 1)
+	if err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("got %d requests, want 1", len(requests))
+	}
+}
+
+func TestRecorder_WaitTimeout(t *testing.T) {
+	r := NewRecorder()
+	r.Record(RequestRecord{Method: "sendMessage", Token: "123:abc"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	requests, err := r.Wait(ctx, Filter{Method: "sendMessage"}, 3)
+	if err != context.DeadlineExceeded {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+	if len(requests) != 1 {
+		t.Errorf("got %d partial requests, want 1", len(requests))
+	}
+}
+
+func TestRecorder_SizeAndEvict(t *testing.T) {
+	r := NewRecorder()
+	if r.Size() != 0 {
+		t.Errorf("initial size = %d, want 0", r.Size())
+	}
+
+	r.Record(RequestRecord{Method: "sendMessage", Token: "123:abc", Params: map[string]interface{}{"chat_id": 1, "text": "hello"}})
+	r.Record(RequestRecord{Method: "getMe", Token: "123:abc"})
+	if r.Size() <= 0 {
+		t.Fatalf("size = %d, want > 0", r.Size())
+	}
+
+	if !r.EvictOldest() {
+		t.Fatal("expected EvictOldest to succeed")
+	}
+	requests := r.List("", "", 0)
+	if len(requests) != 1 || requests[0].Method != "getMe" {
+		t.Errorf("expected oldest request to be evicted, got %v", requests)
+	}
+
+	// Statistics outlive evicted requests
+	if stats := r.Stats(); len(stats) != 1 || stats[0].Sent != 1 {
+		t.Errorf("expected stats to survive eviction, got %+v", stats)
+	}
+
+	r.EvictOldest()
+	if r.Size() != 0 || r.EvictOldest() {
+		t.Errorf("expected empty recorder, size = %d", r.Size())
+	}
+}
+
+func TestRecorder_Page(t *testing.T) {
+	r := NewRecorder()
+	for i := 0; i < 5; i++ {
+		method := "getMe"
+		if i%2 == 1 {
+			method = "sendMessage"
+		}
+		r.Record(RequestRecord{Method: method})
+	}
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		cursor   int64
+		limit    int
+		wantIDs  []int64
+		wantNext int64
+	}{
+		{"first page", Filter{}, 0, 2, []int64{5, 4}, 4},
+		{"second page", Filter{}, 4, 2, []int64{3, 2}, 2},
+		{"last page", Filter{}, 2, 2, []int64{1}, 0},
+		{"exact fit", Filter{}, 3, 2, []int64{2, 1}, 0},
+		{"no limit", Filter{}, 0, 0, []int64{5, 4, 3, 2, 1}, 0},
+		{"filtered", Filter{Method: "getMe"}, 0, 2, []int64{5, 3}, 3},
+		{"filtered last page", Filter{Method: "getMe"}, 3, 2, []int64{1}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, next := r.Page(tt.filter, tt.cursor, tt.limit)
+			var got []int64
+			for _, req := range page {
+				got = append(got, req.ID)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.wantIDs) {
+				t.Errorf("got IDs %v, want %v", got, tt.wantIDs)
+			}
+			if next != tt.wantNext {
+				t.Errorf("next = %d, want %d", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestRecorder_Get(t *testing.T) {
+	r := NewRecorder()
+	for i := 0; i < 3; i++ {
+		r.Record(RequestRecord{Method: fmt.Sprintf("m%d", i)})
+	}
+	r.EvictOldest()
+
+	if req, ok := r.Get(2); !ok || req.Method != "m1" {
+		t.Errorf("expected request 2, got %+v, %v", req, ok)
+	}
+	if _, ok := r.Get(1); ok {
+		t.Error("expected evicted request to be gone")
+	}
+	if _, ok := r.Get(4); ok {
+		t.Error("expected unknown request to be missing")
+	}
+}
@@ -0,0 +1,113 @@
+// internal/inspector/stats.go
+package inspector
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChatStats aggregates the requests a bot made against a single chat.
+type ChatStats struct {
+	ChatID   string                  `json:"chat_id"`
+	Requests int                     `json:"requests"`
+	Sent     int                     `json:"sent"`    // Successful send*/copy*/forward* calls
+	Edits    int                     `json:"edits"`   // Successful edit* calls
+	Deletes  int                     `json:"deletes"` // Successful deleteMessage(s) calls
+	Errors   int                     `json:"errors"`
+	Methods  map[string]*MethodStats `json:"methods"`
+}
+
+// MethodStats counts calls to a single method within a chat.
+type MethodStats struct {
+	Requests int `json:"requests"`
+	Errors   int `json:"errors"`
+}
+
+// add counts a request against the chat.
+func (s *ChatStats) add(req RequestRecord) {
+	s.Requests++
+	m := s.Methods[req.Method]
+	if m == nil {
+		m = &MethodStats{}
+		s.Methods[req.Method] = m
+	}
+	m.Requests++
+
+	if req.IsError {
+		s.Errors++
+		m.Errors++
+		return
+	}
+
+	switch {
+	case isSendMethod(req.Method):
+		s.Sent++
+	case strings.HasPrefix(req.Method, "edit"):
+		s.Edits++
+	case req.Method == "deleteMessage" || req.Method == "deleteMessages":
+		s.Deletes++
+	}
+}
+
+// isSendMethod reports whether a method posts new messages to a chat.
+// sendChatAction only shows a status, so it doesn't count.
+func isSendMethod(method string) bool {
+	if method == "sendChatAction" {
+		return false
+	}
+	return strings.HasPrefix(method, "send") ||
+		strings.HasPrefix(method, "copyMessage") ||
+		strings.HasPrefix(method, "forwardMessage")
+}
+
+// chatKey returns the chat a request targets as a string, or "" if the
+// request has no chat_id.
+func chatKey(params map[string]interface{}) string {
+	switch v := params["chat_id"].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Stats returns per-chat statistics for every recorded request that
+// targeted a chat, ordered by chat ID. Statistics are accumulated as
+// requests are recorded and reset by Clear.
+func (r *Recorder) Stats() []ChatStats {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]ChatStats, 0, len(r.stats))
+	for _, s := range r.stats {
+		c := *s
+		c.Methods = make(map[string]*MethodStats, len(s.Methods))
+		for method, m := range s.Methods {
+			mc := *m
+			c.Methods[method] = &mc
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChatID < result[j].ChatID })
+	return result
+}
+
+// recordStats counts req towards its chat's statistics. Callers must hold r.mu.
+func (r *Recorder) recordStats(req RequestRecord) {
+	key := chatKey(req.Params)
+	if key == "" {
+		return
+	}
+	s := r.stats[key]
+	if s == nil {
+		s = &ChatStats{ChatID: key, Methods: make(map[string]*MethodStats)}
+		r.stats[key] = s
+	}
+	s.add(req)
+}
@@ -0,0 +1,60 @@
+// internal/inspector/stats_test.go
+package inspector
+
+import "testing"
+
+func TestRecorder_Stats(t *testing.T) {
+	r := NewRecorder()
+
+	r.Record(RequestRecord{Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(100)}})
+	r.Record(RequestRecord{Method: "sendPhoto", Params: map[string]interface{}{"chat_id": "100"}})
+	r.Record(RequestRecord{Method: "editMessageText", Params: map[string]interface{}{"chat_id": float64(100)}})
+	r.Record(RequestRecord{Method: "deleteMessage", Params: map[string]interface{}{"chat_id": float64(100)}})
+	r.Record(RequestRecord{Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(100)}, IsError: true})
+	r.Record(RequestRecord{Method: "sendChatAction", Params: map[string]interface{}{"chat_id": float64(100)}})
+	r.Record(RequestRecord{Method: "sendMessage", Params: map[string]interface{}{"chat_id": "@channel"}})
+	r.Record(RequestRecord{Method: "getMe"})
+
+	stats := r.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("got %d chats, want 2", len(stats))
+	}
+
+	chat := stats[0]
+	if chat.ChatID != "100" {
+		t.Fatalf("got chat %q, want 100", chat.ChatID)
+	}
+	if chat.Requests != 6 {
+		t.Errorf("requests = %d, want 6", chat.Requests)
+	}
+	if chat.Sent != 2 {
+		t.Errorf("sent = %d, want 2", chat.Sent)
+	}
+	if chat.Edits != 1 {
+		t.Errorf("edits = %d, want 1", chat.Edits)
+	}
+	if chat.Deletes != 1 {
+		t.Errorf("deletes = %d, want 1", chat.Deletes)
+	}
+	if chat.Errors != 1 {
+		t.Errorf("errors = %d, want 1", chat.Errors)
+	}
+	if m := chat.Methods["sendMessage"]; m == nil || m.Requests != 2 || m.Errors != 1 {
+		t.Errorf("unexpected sendMessage stats %+v", m)
+	}
+
+	if stats[1].ChatID != "@channel" || stats[1].Sent != 1 {
+		t.Errorf("unexpected stats for @channel: %+v", stats[1])
+	}
+}
+
+func TestRecorder_StatsClear(t *testing.T) {
+	r := NewRecorder()
+	r.Record(RequestRecord{Method: "sendMessage", Params: map[string]interface{}{"chat_id": float64(100)}})
+
+	r.Clear()
+
+	if stats := r.Stats(); len(stats) != 0 {
+		t.Errorf("got %d chats after Clear, want 0", len(stats))
+	}
+}
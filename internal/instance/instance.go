@@ -0,0 +1,202 @@
+// Package instance hands out throwaway sandboxes on a shared tg-mock
+// server. An instance is a session with its own base path, faker seed,
+// and expiry, so parallel CI jobs can each get a clean mock on demand and
+// leave nothing behind.
+package instance
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Limits of an instance's time to live.
+const (
+	DefaultTTL = 30 * time.Minute
+	MaxTTL     = 24 * time.Hour
+)
+
+// SessionPrefix starts the name of every instance's session.
+const SessionPrefix = "instance-"
+
+// Instance is an isolated sandbox.
+type Instance struct {
+	ID string `json:"id"`
+	// Session is the name of the session holding the instance's state.
+	Session   string    `json:"session"`
+	Seed      int64     `json:"seed"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type entry struct {
+	Instance
+	timer *time.Timer
+}
+
+// Registry tracks the live instances and removes them once they expire.
+// Expiry follows the registry's clock, so moving a mock clock ahead
+// expires instances early and freezing it keeps them alive.
+type Registry struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	instances map[string]*entry
+
+	// OnCreate, if set, is called with every new instance before it is
+	// returned, to set up its session.
+	OnCreate func(Instance)
+	// OnRemove, if set, is called with every instance that expires or is
+	// deleted, to discard its session.
+	OnRemove func(Instance)
+}
+
+// NewRegistry creates an empty registry reading the time from now
+// (time.Now if nil).
+func NewRegistry(now func() time.Time) *Registry {
+	if now == nil {
+		now = time.Now
+	}
+	return &Registry{now: now, instances: make(map[string]*entry)}
+}
+
+// Create starts an instance that lives for ttl (0 = DefaultTTL). A seed
+// of 0 picks a random one, which is reported so failures can be
+// reproduced.
+func (r *Registry) Create(seed int64, ttl time.Duration) (Instance, error) {
+	if ttl < 0 || ttl > MaxTTL {
+		return Instance{}, fmt.Errorf("ttl must be between 0 and %s", MaxTTL)
+	}
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	for seed == 0 {
+		seed = mathrand.Int63()
+	}
+
+	id := newID()
+	now := r.now()
+	inst := Instance{
+		ID:        id,
+		Session:   SessionPrefix + id,
+		Seed:      seed,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if r.OnCreate != nil {
+		r.OnCreate(inst)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.instances[id] = &entry{
+		Instance: inst,
+		timer:    time.AfterFunc(ttl, func() { r.expire(id) }),
+	}
+	return inst, nil
+}
+
+// expire deletes an instance whose timer fired if it has expired by the
+// registry's clock, and otherwise waits for the time it has left.
+func (r *Registry) expire(id string) {
+	r.mu.Lock()
+	e, ok := r.instances[id]
+	var left time.Duration
+	if ok {
+		if left = e.ExpiresAt.Sub(r.now()); left > 0 {
+			e.timer = time.AfterFunc(left, func() { r.expire(id) })
+		}
+	}
+	r.mu.Unlock()
+	if ok && left <= 0 {
+		r.Delete(id)
+	}
+}
+
+// expireAll deletes every instance that has expired by the registry's
+// clock, which may have been moved ahead of its timers.
+func (r *Registry) expireAll() {
+	now := r.now()
+	var expired []string
+	r.mu.Lock()
+	for id, e := range r.instances {
+		if !now.Before(e.ExpiresAt) {
+			expired = append(expired, id)
+		}
+	}
+	r.mu.Unlock()
+	for _, id := range expired {
+		r.Delete(id)
+	}
+}
+
+// Get returns a live instance.
+func (r *Registry) Get(id string) (Instance, bool) {
+	r.expireAll()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.instances[id]
+	if !ok {
+		return Instance{}, false
+	}
+	return e.Instance, true
+}
+
+// List returns the live instances, oldest first.
+func (r *Registry) List() []Instance {
+	r.expireAll()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]Instance, 0, len(r.instances))
+	for _, e := range r.instances {
+		result = append(result, e.Instance)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].CreatedAt.Before(result[j].CreatedAt) })
+	return result
+}
+
+// Delete removes an instance before it expires, reporting whether it
+// existed.
+func (r *Registry) Delete(id string) bool {
+	r.mu.Lock()
+	e, ok := r.instances[id]
+	if ok {
+		e.timer.Stop()
+		delete(r.instances, id)
+	}
+	r.mu.Unlock()
+
+	if ok && r.OnRemove != nil {
+		r.OnRemove(e.Instance)
+	}
+	return ok
+}
+
+// Clear removes every instance.
+func (r *Registry) Clear() {
+	r.mu.Lock()
+	removed := make([]Instance, 0, len(r.instances))
+	for _, e := range r.instances {
+		e.timer.Stop()
+		removed = append(removed, e.Instance)
+	}
+	r.instances = make(map[string]*entry)
+	r.mu.Unlock()
+
+	if r.OnRemove != nil {
+		for _, inst := range removed {
+			r.OnRemove(inst)
+		}
+	}
+}
+
+// newID returns a random instance ID. IDs are hard to guess, so one CI
+// job can't stumble into another's sandbox.
+func newID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
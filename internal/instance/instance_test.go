@@ -0,0 +1,101 @@
+// internal/instance/instance_test.go
+package instance
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry(time.Now)
+	var created, removed []string
+	r.OnCreate = func(inst Instance) { created = append(created, inst.Session) }
+	r.OnRemove = func(inst Instance) { removed = append(removed, inst.Session) }
+
+	a, err := r.Create(42, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Seed != 42 || !strings.HasPrefix(a.Session, SessionPrefix) || a.ExpiresAt.Sub(a.CreatedAt) != DefaultTTL {
+		t.Errorf("unexpected instance %+v", a)
+	}
+	b, err := r.Create(0, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Seed == 0 {
+		t.Error("expected a random seed")
+	}
+	if a.ID == b.ID {
+		t.Error("expected unique IDs")
+	}
+	if len(created) != 2 {
+		t.Errorf("expected OnCreate for both instances, got %v", created)
+	}
+
+	if list := r.List(); len(list) != 2 || list[0].ID != a.ID {
+		t.Errorf("unexpected list %+v", list)
+	}
+	if _, ok := r.Get(a.ID); !ok {
+		t.Error("expected to find the instance")
+	}
+	if !r.Delete(a.ID) || r.Delete(a.ID) {
+		t.Error("expected the instance to be deleted once")
+	}
+	if len(removed) != 1 || removed[0] != a.Session {
+		t.Errorf("expected OnRemove for the deleted instance, got %v", removed)
+	}
+
+	r.Clear()
+	if len(r.List()) != 0 || len(removed) != 2 {
+		t.Errorf("expected Clear to remove every instance, removed %v", removed)
+	}
+
+	if _, err := r.Create(1, MaxTTL+time.Second); err == nil {
+		t.Error("expected a TTL above the maximum to be rejected")
+	}
+}
+
+func TestRegistryExpiry(t *testing.T) {
+	r := NewRegistry(time.Now)
+	done := make(chan Instance, 1)
+	r.OnRemove = func(inst Instance) { done <- inst }
+
+	inst, err := r.Create(1, 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case removed := <-done:
+		if removed.ID != inst.ID {
+			t.Errorf("expected %s to expire, got %s", inst.ID, removed.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("instance did not expire")
+	}
+	if _, ok := r.Get(inst.ID); ok {
+		t.Error("expected the expired instance to be gone")
+	}
+}
+
+func TestRegistryClock(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewRegistry(func() time.Time { return now })
+
+	inst, err := r.Create(1, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !inst.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("expected expiry an hour from the clock, got %v", inst.ExpiresAt)
+	}
+	now = now.Add(59 * time.Minute)
+	if _, ok := r.Get(inst.ID); !ok {
+		t.Error("expected the instance to live until its expiry")
+	}
+	now = now.Add(time.Minute)
+	if _, ok := r.Get(inst.ID); ok {
+		t.Error("expected the instance to expire once the clock passes its expiry")
+	}
+}
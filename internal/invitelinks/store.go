@@ -0,0 +1,157 @@
+// Package invitelinks keeps the invite links bots create for chats, so that
+// editing, revoking, and exporting links work on the links bots were given
+// instead of random ones.
+package invitelinks
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// Link is a known invite link of a chat: its ChatInviteLink fields.
+type Link struct {
+	ChatID string                 `json:"chat_id"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Store holds the known invite links of chats, in the order they were
+// created. Values are copied on the way in and out.
+type Store struct {
+	mu    sync.Mutex
+	chats map[string][]map[string]interface{}
+}
+
+// NewStore creates an empty invite link store.
+func NewStore() *Store {
+	return &Store{chats: make(map[string][]map[string]interface{})}
+}
+
+// Add stores a link of a chat, replacing the link with the same
+// invite_link. A primary link revokes the previous primary link, as
+// exportChatInviteLink does.
+func (s *Store) Add(chatID string, link map[string]interface{}) {
+	link = jsoncopy.Map(link)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if link["is_primary"] == true {
+		for _, l := range s.chats[chatID] {
+			if l["is_primary"] == true {
+				l["is_revoked"] = true
+			}
+		}
+	}
+	if i := s.find(chatID, link["invite_link"]); i >= 0 {
+		s.chats[chatID][i] = link
+		return
+	}
+	s.chats[chatID] = append(s.chats[chatID], link)
+}
+
+// Get returns a known link of a chat.
+func (s *Store) Get(chatID, inviteLink string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.find(chatID, inviteLink)
+	if i < 0 {
+		return nil, false
+	}
+	return jsoncopy.Map(s.chats[chatID][i]), true
+}
+
+// Primary returns the primary link of a chat, if it has one that wasn't
+// revoked.
+func (s *Store) Primary(chatID string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, l := range s.chats[chatID] {
+		if l["is_primary"] == true && l["is_revoked"] != true {
+			return jsoncopy.Map(l), true
+		}
+	}
+	return nil, false
+}
+
+// Edit changes the fields of a known link and returns it. Revoked links
+// can't be edited. It returns false if the link isn't known.
+func (s *Store) Edit(chatID, inviteLink string, changes map[string]interface{}) (map[string]interface{}, bool, *tgerrors.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.find(chatID, inviteLink)
+	if i < 0 {
+		return nil, false, nil
+	}
+	link := s.chats[chatID][i]
+	if link["is_revoked"] == true {
+		return nil, true, tgerrors.InviteHashExpired()
+	}
+	for name, v := range jsoncopy.Map(changes) {
+		link[name] = v
+	}
+	return jsoncopy.Map(link), true, nil
+}
+
+// Revoke marks a known link as revoked and returns it. It returns false if
+// the link isn't known.
+func (s *Store) Revoke(chatID, inviteLink string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := s.find(chatID, inviteLink)
+	if i < 0 {
+		return nil, false
+	}
+	link := s.chats[chatID][i]
+	link["is_revoked"] = true
+	return jsoncopy.Map(link), true
+}
+
+// List returns the known links of a chat, or of every chat if chatID is
+// empty, ordered by chat and then as created.
+func (s *Store) List(chatID string) []Link {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.chats))
+	for id := range s.chats {
+		if chatID == "" || id == chatID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	var list []Link
+	for _, id := range ids {
+		for _, l := range s.chats[id] {
+			list = append(list, Link{ChatID: id, Fields: jsoncopy.Map(l)})
+		}
+	}
+	return list
+}
+
+// Restore replaces the store contents with the given links.
+func (s *Store) Restore(list []Link) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string][]map[string]interface{})
+	for _, l := range list {
+		s.chats[l.ChatID] = append(s.chats[l.ChatID], jsoncopy.Map(l.Fields))
+	}
+}
+
+// Clear forgets all links.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string][]map[string]interface{})
+}
+
+// find returns the index of a link of a chat, or -1. Callers must hold
+// s.mu.
+func (s *Store) find(chatID string, inviteLink interface{}) int {
+	for i, l := range s.chats[chatID] {
+		if l["invite_link"] == inviteLink {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,40 @@
+// internal/invitelinks/store_test.go
+package invitelinks
+
+import "testing"
+
+func TestStore_EditAndRevoke(t *testing.T) {
+	s := NewStore()
+	s.Add("-100", map[string]interface{}{"invite_link": "https://t.me/+a", "is_primary": false, "is_revoked": false})
+
+	link, known, err := s.Edit("-100", "https://t.me/+a", map[string]interface{}{"name": "Friends", "member_limit": 5})
+	if !known || err != nil || link["name"] != "Friends" || link["member_limit"] != float64(5) {
+		t.Fatalf("expected the link to be edited, got %v %v %v", link, known, err)
+	}
+	if _, known, _ := s.Edit("-200", "https://t.me/+a", nil); known {
+		t.Error("expected links to belong to their chat")
+	}
+
+	if link, _ := s.Revoke("-100", "https://t.me/+a"); link["is_revoked"] != true {
+		t.Errorf("expected the link to be revoked, got %v", link)
+	}
+	if _, _, err := s.Edit("-100", "https://t.me/+a", map[string]interface{}{"name": "Late"}); err == nil || err.Description != "Bad Request: INVITE_HASH_EXPIRED" {
+		t.Errorf("expected editing a revoked link to fail, got %v", err)
+	}
+}
+
+func TestStore_Primary(t *testing.T) {
+	s := NewStore()
+	s.Add("-100", map[string]interface{}{"invite_link": "https://t.me/+one", "is_primary": true})
+	s.Add("-100", map[string]interface{}{"invite_link": "https://t.me/+two", "is_primary": true})
+
+	if link, ok := s.Primary("-100"); !ok || link["invite_link"] != "https://t.me/+two" {
+		t.Errorf("expected the last exported link to be primary, got %v", link)
+	}
+	if link, _ := s.Get("-100", "https://t.me/+one"); link["is_revoked"] != true {
+		t.Errorf("expected the previous primary link to be revoked, got %v", link)
+	}
+	if list := s.List(""); len(list) != 2 || list[0].Fields["invite_link"] != "https://t.me/+one" {
+		t.Errorf("expected the links in the order they were created, got %+v", list)
+	}
+}
@@ -0,0 +1,34 @@
+// Package jsoncopy deep-copies the JSON-like values stores keep, by
+// converting them to what decoding them from JSON gives. Stored values
+// then don't share maps or slices with callers, and compare equal however
+// they were passed in.
+package jsoncopy
+
+import "encoding/json"
+
+// Map deep-copies fields. A nil map, or one that can't be encoded, gives
+// an empty map.
+func Map(fields map[string]interface{}) map[string]interface{} {
+	var copied map[string]interface{}
+	data, err := json.Marshal(fields)
+	if err != nil || json.Unmarshal(data, &copied) != nil || copied == nil {
+		return map[string]interface{}{}
+	}
+	return copied
+}
+
+// Value deep-copies v. Values that can't be encoded are returned as is.
+func Value(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
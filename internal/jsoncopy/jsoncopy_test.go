@@ -0,0 +1,49 @@
+// internal/jsoncopy/jsoncopy_test.go
+package jsoncopy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMap(t *testing.T) {
+	fields := map[string]interface{}{
+		"id":   int64(42),
+		"tags": []string{"a", "b"},
+		"user": map[string]interface{}{"first_name": "Ann"},
+	}
+	copied := Map(fields)
+
+	want := map[string]interface{}{
+		"id":   float64(42),
+		"tags": []interface{}{"a", "b"},
+		"user": map[string]interface{}{"first_name": "Ann"},
+	}
+	if !reflect.DeepEqual(copied, want) {
+		t.Errorf("got %v, want %v", copied, want)
+	}
+	copied["user"].(map[string]interface{})["first_name"] = "Bob"
+	if fields["user"].(map[string]interface{})["first_name"] != "Ann" {
+		t.Error("expected the copy not to share nested maps")
+	}
+
+	if got := Map(nil); got == nil || len(got) != 0 {
+		t.Errorf("got %v for nil, want an empty map", got)
+	}
+	if got := Map(map[string]interface{}{"f": func() {}}); len(got) != 0 {
+		t.Errorf("got %v for a map that can't be encoded, want an empty map", got)
+	}
+}
+
+func TestValue(t *testing.T) {
+	if got := Value(int64(7)); got != float64(7) {
+		t.Errorf("got %#v, want 7.0", got)
+	}
+	if got := Value(nil); got != nil {
+		t.Errorf("got %#v for nil", got)
+	}
+	ch := make(chan int)
+	if got := Value(ch); got != ch {
+		t.Errorf("expected a value that can't be encoded to be returned as is")
+	}
+}
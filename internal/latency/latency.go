@@ -0,0 +1,167 @@
+// Package latency delays Bot API responses, so that end-to-end tests see
+// round-trip times like the real Bot API's instead of sub-millisecond
+// replies.
+package latency
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// Percentiles describes a latency distribution by its 50th, 90th, and
+// 99th percentiles, in milliseconds.
+type Percentiles struct {
+	P50 int `json:"p50_ms"`
+	P90 int `json:"p90_ms"`
+	P99 int `json:"p99_ms"`
+}
+
+// Config sets the delay of responses. Methods with a percentile profile
+// are delayed by a sample of it; other methods by FixedMs plus a random
+// jitter.
+type Config struct {
+	FixedMs int `json:"fixed_ms,omitempty"`
+	// JitterMinMs and JitterMaxMs bound the random jitter added to FixedMs.
+	JitterMinMs int `json:"jitter_min_ms,omitempty"`
+	JitterMaxMs int `json:"jitter_max_ms,omitempty"`
+	// Profile is the percentile profile of all methods.
+	Profile *Percentiles `json:"profile,omitempty"`
+	// Methods are percentile profiles of single methods, taking precedence
+	// over Profile.
+	Methods map[string]Percentiles `json:"methods,omitempty"`
+	// Seed makes the random delays reproducible (0 = random).
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate checks the configuration. A jitter with only a minimum is a
+// fixed extra delay.
+func (c *Config) Validate() error {
+	if c.FixedMs < 0 || c.JitterMinMs < 0 || c.JitterMaxMs < 0 {
+		return fmt.Errorf("delays must not be negative")
+	}
+	if c.JitterMaxMs < c.JitterMinMs {
+		if c.JitterMaxMs != 0 {
+			return fmt.Errorf("jitter_max_ms must not be less than jitter_min_ms")
+		}
+		c.JitterMaxMs = c.JitterMinMs
+	}
+	if c.Profile != nil {
+		if err := c.Profile.validate(); err != nil {
+			return fmt.Errorf("profile: %w", err)
+		}
+	}
+	for method, p := range c.Methods {
+		if _, ok := gen.Methods[method]; !ok {
+			return fmt.Errorf("unknown method %q", method)
+		}
+		if err := p.validate(); err != nil {
+			return fmt.Errorf("%s: %w", method, err)
+		}
+	}
+	return nil
+}
+
+func (p Percentiles) validate() error {
+	if p.P50 < 0 {
+		return fmt.Errorf("percentiles must not be negative")
+	}
+	if p.P90 < p.P50 || p.P99 < p.P90 {
+		return fmt.Errorf("percentiles must not decrease")
+	}
+	return nil
+}
+
+// sample draws a latency from the distribution. Between the percentiles it
+// is linear; the fastest half of the calls take from half of p50 up to p50,
+// and the slowest 1% from p99 up to p99 plus the p90-p99 spread.
+func (p Percentiles) sample(q float64) float64 {
+	points := []struct{ q, ms float64 }{
+		{0, float64(p.P50) / 2},
+		{0.5, float64(p.P50)},
+		{0.9, float64(p.P90)},
+		{0.99, float64(p.P99)},
+		{1, float64(p.P99 + (p.P99 - p.P90))},
+	}
+	for i := 1; i < len(points); i++ {
+		lo, hi := points[i-1], points[i]
+		if q <= hi.q {
+			return lo.ms + (hi.ms-lo.ms)*(q-lo.q)/(hi.q-lo.q)
+		}
+	}
+	return points[len(points)-1].ms
+}
+
+// Profile delays responses according to its configuration. It adds no
+// delay until configured.
+type Profile struct {
+	mu  sync.Mutex
+	cfg *Config
+	rng *rand.Rand
+}
+
+// NewProfile creates a profile adding no delay.
+func NewProfile() *Profile {
+	return &Profile{}
+}
+
+// Set configures the profile.
+func (p *Profile) Set(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = &cfg
+	p.rng = rand.New(rand.NewSource(seed))
+	return nil
+}
+
+// Get returns the configuration, if one is set.
+func (p *Profile) Get() *Config {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg == nil {
+		return nil
+	}
+	cfg := *p.cfg
+	return &cfg
+}
+
+// Disable stops delaying responses.
+func (p *Profile) Disable() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = nil
+}
+
+// Delay returns how long to delay a response to method.
+func (p *Profile) Delay(method string) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cfg == nil {
+		return 0
+	}
+
+	profile, ok := p.cfg.Methods[method]
+	if !ok && p.cfg.Profile != nil {
+		profile, ok = *p.cfg.Profile, true
+	}
+	if ok {
+		return time.Duration(profile.sample(p.rng.Float64()) * float64(time.Millisecond))
+	}
+
+	ms := p.cfg.FixedMs + p.cfg.JitterMinMs
+	if p.cfg.JitterMaxMs > p.cfg.JitterMinMs {
+		ms += p.rng.Intn(p.cfg.JitterMaxMs - p.cfg.JitterMinMs + 1)
+	}
+	return time.Duration(ms) * time.Millisecond
+}
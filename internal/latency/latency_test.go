@@ -0,0 +1,79 @@
+// internal/latency/latency_test.go
+package latency
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		ok   bool
+	}{
+		{"empty", Config{}, true},
+		{"fixed", Config{FixedMs: 50}, true},
+		{"negative", Config{FixedMs: -1}, false},
+		{"bad jitter", Config{JitterMinMs: 100, JitterMaxMs: 50}, false},
+		{"profile", Config{Profile: &Percentiles{P50: 80, P90: 200, P99: 900}}, true},
+		{"decreasing profile", Config{Profile: &Percentiles{P50: 80, P90: 60, P99: 900}}, false},
+		{"unknown method", Config{Methods: map[string]Percentiles{"nope": {P50: 1, P90: 2, P99: 3}}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err == nil) != tt.ok {
+				t.Errorf("expected ok=%v, got %v", tt.ok, err)
+			}
+		})
+	}
+}
+
+func TestProfile_DisabledByDefault(t *testing.T) {
+	p := NewProfile()
+	if d := p.Delay("sendMessage"); d != 0 {
+		t.Errorf("expected no delay, got %v", d)
+	}
+}
+
+func TestProfile_FixedAndJitter(t *testing.T) {
+	p := NewProfile()
+	if err := p.Set(Config{FixedMs: 100, JitterMinMs: 10, JitterMaxMs: 20, Seed: 1}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		if d := p.Delay("getMe"); d < 110*time.Millisecond || d > 120*time.Millisecond {
+			t.Fatalf("delay %v outside 110-120ms", d)
+		}
+	}
+}
+
+func TestProfile_Percentiles(t *testing.T) {
+	p := NewProfile()
+	err := p.Set(Config{
+		FixedMs: 5,
+		Methods: map[string]Percentiles{"sendPhoto": {P50: 100, P90: 300, P99: 1000}},
+		Seed:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := p.Delay("getMe"); d != 5*time.Millisecond {
+		t.Errorf("expected methods without a profile to use the fixed delay, got %v", d)
+	}
+
+	delays := make([]time.Duration, 10000)
+	for i := range delays {
+		delays[i] = p.Delay("sendPhoto")
+	}
+	sort.Slice(delays, func(i, j int) bool { return delays[i] < delays[j] })
+	within := func(got time.Duration, want int) bool {
+		ms := float64(got) / float64(time.Millisecond)
+		return ms > float64(want)*0.9 && ms < float64(want)*1.1
+	}
+	if !within(delays[5000], 100) || !within(delays[9000], 300) || !within(delays[9900], 1000) {
+		t.Errorf("unexpected percentiles: p50=%v p90=%v p99=%v", delays[5000], delays[9000], delays[9900])
+	}
+}
@@ -0,0 +1,79 @@
+// internal/markup/detect.go
+package markup
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// detectors find the entities Telegram recognizes in plain text. Each
+// pattern's first group is the entity; what precedes it only makes sure
+// the entity starts a word.
+var detectors = []struct {
+	typ     string
+	pattern *regexp.Regexp
+}{
+	{"url", regexp.MustCompile(`(?i)(?:^|[\s(])((?:https?://|www\.)[^\s<>"]+|(?:[a-z0-9-]+\.)+(?:com|org|net|io|dev|app|me|co|info|ru|de|uk)(?:/[^\s<>"]*)?)`)},
+	{"email", regexp.MustCompile(`(?:^|[\s(])([A-Za-z0-9._%+-]+@(?:[A-Za-z0-9-]+\.)+[A-Za-z]{2,})`)},
+	{"mention", regexp.MustCompile(`(?:^|[^\w@])(@[A-Za-z0-9_]{5,32})\b`)},
+	{"hashtag", regexp.MustCompile(`(?:^|[^\w#])(#[\p{L}\p{N}_]*[\p{L}_][\p{L}\p{N}_]*)`)},
+	{"cashtag", regexp.MustCompile(`(?:^|[^\w$])(\$[A-Z]{1,8})\b`)},
+	{"bot_command", regexp.MustCompile(`(?:^|[\s(])(/[A-Za-z0-9_]{1,64}(?:@[A-Za-z0-9_]{3,32})?)\b`)},
+}
+
+// noDetection are the entities whose text Telegram doesn't look for
+// other entities in.
+var noDetection = map[string]bool{
+	"code":      true,
+	"pre":       true,
+	"text_link": true,
+	"url":       true,
+	"email":     true,
+}
+
+// Detect finds the mentions, hashtags, cashtags, bot commands, URLs, and
+// email addresses in text that the given entities don't already cover,
+// and returns all entities ordered by offset.
+func Detect(text string, entities []Entity) []Entity {
+	type span struct{ start, end int }
+	var taken []span
+	for _, e := range entities {
+		if noDetection[e.Type] {
+			taken = append(taken, span{e.Offset, e.Offset + e.Length})
+		}
+	}
+	overlaps := func(s span) bool {
+		for _, t := range taken {
+			if s.start < t.end && t.start < s.end {
+				return true
+			}
+		}
+		return false
+	}
+
+	result := append([]Entity{}, entities...)
+	for _, d := range detectors {
+		for _, m := range d.pattern.FindAllStringSubmatchIndex(text, -1) {
+			start, end := m[2], m[3]
+			match := text[start:end]
+			if d.typ == "url" {
+				match = strings.TrimRight(match, ".,;:!?)'")
+				end = start + len(match)
+			}
+			s := span{units(text[:start]), units(text[:end])}
+			if overlaps(s) {
+				continue
+			}
+			taken = append(taken, s)
+			result = append(result, Entity{Type: d.typ, Offset: s.start, Length: s.end - s.start})
+		}
+	}
+	sortEntities(result)
+	return result
+}
+
+// units returns the length of s in UTF-16 code units.
+func units(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
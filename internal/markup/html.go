@@ -0,0 +1,256 @@
+// internal/markup/html.go
+package markup
+
+import (
+	"strconv"
+	"strings"
+)
+
+// htmlTags maps the tags Telegram supports to the entities they create.
+var htmlTags = map[string]string{
+	"a":          "text_link",
+	"b":          "bold",
+	"strong":     "bold",
+	"i":          "italic",
+	"em":         "italic",
+	"u":          "underline",
+	"ins":        "underline",
+	"s":          "strikethrough",
+	"strike":     "strikethrough",
+	"del":        "strikethrough",
+	"span":       "spoiler",
+	"tg-spoiler": "spoiler",
+	"tg-emoji":   "custom_emoji",
+	"code":       "code",
+	"pre":        "pre",
+	"blockquote": "blockquote",
+}
+
+// htmlEntities are the named character references Telegram decodes.
+var htmlEntities = map[string]rune{
+	"lt":   '<',
+	"gt":   '>',
+	"amp":  '&',
+	"quot": '"',
+}
+
+// openTag is an HTML tag whose end tag hasn't been found yet.
+type openTag struct {
+	name       string
+	attrs      map[string]string
+	offset     int
+	byteOffset int
+	textStart  int
+}
+
+// parseHTML parses the HTML subset of the Bot API, following Telegram's
+// parser: only the supported tags and the &lt;, &gt;, &amp;, &quot;, and
+// numeric character references are understood, and tags must be closed
+// in order.
+func parseHTML(text string) (string, []Entity, error) {
+	var out builder
+	var entities []Entity
+	var stack []openTag
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '&' {
+			if r, n := htmlEntity(text[i:]); n > 0 {
+				out.writeRune(r)
+				i += n - 1
+				continue
+			}
+		}
+		if c != '<' {
+			out.writeByte(c)
+			continue
+		}
+
+		begin := i
+		i++
+		if at(text, i) != '/' {
+			name, attrs, end, err := htmlStartTag(text, i, begin)
+			if err != nil {
+				return "", nil, err
+			}
+			i = end
+			stack = append(stack, openTag{name: name, attrs: attrs, offset: out.units, byteOffset: begin, textStart: out.Len()})
+			continue
+		}
+
+		if len(stack) == 0 {
+			return "", nil, errorf("Unexpected end tag at byte offset %d", begin)
+		}
+		end := strings.IndexByte(text[i:], '>')
+		if end < 0 {
+			return "", nil, errorf("Unclosed end tag at byte offset %d", begin)
+		}
+		name := strings.ToLower(strings.TrimSpace(text[i+1 : i+end]))
+		i += end
+		open := stack[len(stack)-1]
+		if name != open.name {
+			return "", nil, errorf(`Unmatched end tag at byte offset %d, expected "</%s>", found "</%s>"`, begin, open.name, name)
+		}
+		stack = stack[:len(stack)-1]
+
+		entity := Entity{Type: htmlTags[name], Offset: open.offset, Length: out.units - open.offset}
+		if entity.Length == 0 {
+			continue
+		}
+		switch name {
+		case "a":
+			link := open.attrs["href"]
+			if link == "" {
+				link = out.String()[open.textStart:]
+			}
+			u, ok := linkURL(link)
+			if !ok {
+				continue
+			}
+			entity.URL = u
+		case "tg-emoji":
+			entity.CustomEmojiID = open.attrs["emoji-id"]
+		case "blockquote":
+			if _, ok := open.attrs["expandable"]; ok {
+				entity.Type = "expandable_blockquote"
+			}
+		case "code":
+			// <pre><code class="language-go"> is a pre block in Go
+			if language := strings.TrimPrefix(open.attrs["class"], "language-"); language != open.attrs["class"] && len(stack) > 0 && stack[len(stack)-1].name == "pre" {
+				stack[len(stack)-1].attrs["language"] = language
+			}
+		case "pre":
+			if language := open.attrs["language"]; language != "" {
+				entity.Language = language
+				// The code entity of the same text is replaced by the language
+				if n := len(entities); n > 0 && entities[n-1].Type == "code" && entities[n-1].Offset == entity.Offset && entities[n-1].Length == entity.Length {
+					entities = entities[:n-1]
+				}
+			}
+		}
+		entities = append(entities, entity)
+	}
+
+	if n := len(stack); n > 0 {
+		return "", nil, errorf(`Can't find end tag corresponding to start tag "%s"`, stack[n-1].name)
+	}
+	return out.String(), entities, nil
+}
+
+// htmlStartTag reads a start tag whose name starts at i and returns its
+// name, its attributes, and the position of its closing >.
+func htmlStartTag(text string, i, begin int) (string, map[string]string, int, error) {
+	start := i
+	for i < len(text) && !isSpace(text[i]) && text[i] != '>' {
+		i++
+	}
+	if i >= len(text) {
+		return "", nil, 0, errorf("Unclosed start tag at byte offset %d", begin)
+	}
+	name := strings.ToLower(text[start:i])
+	if _, ok := htmlTags[name]; !ok {
+		return "", nil, 0, errorf(`Unsupported start tag "%s" at byte offset %d`, name, begin)
+	}
+
+	attrs := map[string]string{}
+	for {
+		for i < len(text) && isSpace(text[i]) {
+			i++
+		}
+		if i >= len(text) {
+			return "", nil, 0, errorf("Unclosed start tag at byte offset %d", begin)
+		}
+		if text[i] == '>' {
+			break
+		}
+		attrStart := i
+		for i < len(text) && !isSpace(text[i]) && text[i] != '=' && text[i] != '>' {
+			i++
+		}
+		attr := strings.ToLower(text[attrStart:i])
+		if attr == "" {
+			return "", nil, 0, errorf(`Empty attribute name in the tag "%s" at byte offset %d`, name, begin)
+		}
+		if at(text, i) != '=' {
+			attrs[attr] = ""
+			continue
+		}
+		i++
+		value, end, ok := htmlAttrValue(text, i)
+		if !ok {
+			return "", nil, 0, errorf("Unclosed start tag at byte offset %d", begin)
+		}
+		attrs[attr] = value
+		i = end
+	}
+
+	switch name {
+	case "span":
+		if attrs["class"] != "tg-spoiler" {
+			return "", nil, 0, errorf(`Tag "span" must have class "tg-spoiler" at byte offset %d`, begin)
+		}
+	case "tg-emoji":
+		if _, err := strconv.ParseInt(attrs["emoji-id"], 10, 64); err != nil {
+			return "", nil, 0, errorf(`Tag "tg-emoji" must have attribute "emoji-id" at byte offset %d`, begin)
+		}
+	}
+	return name, attrs, i, nil
+}
+
+// htmlAttrValue reads a quoted or unquoted attribute value starting at i
+// and returns it decoded, with the position after it.
+func htmlAttrValue(text string, i int) (string, int, bool) {
+	var raw string
+	if q := at(text, i); q == '"' || q == '\'' {
+		end := strings.IndexByte(text[i+1:], q)
+		if end < 0 {
+			return "", 0, false
+		}
+		raw = text[i+1 : i+1+end]
+		i += end + 2
+	} else {
+		start := i
+		for i < len(text) && !isSpace(text[i]) && text[i] != '>' {
+			i++
+		}
+		raw = text[start:i]
+	}
+	var value strings.Builder
+	for j := 0; j < len(raw); j++ {
+		if raw[j] == '&' {
+			if r, n := htmlEntity(raw[j:]); n > 0 {
+				value.WriteRune(r)
+				j += n - 1
+				continue
+			}
+		}
+		value.WriteByte(raw[j])
+	}
+	return value.String(), i, true
+}
+
+// htmlEntity decodes the character reference text starts with and returns
+// the character with the length of the reference, or 0 if text doesn't
+// start with one Telegram understands.
+func htmlEntity(text string) (rune, int) {
+	end := strings.IndexByte(text, ';')
+	if end < 2 || end > 10 {
+		return 0, 0
+	}
+	ref := text[1:end]
+	if r, ok := htmlEntities[ref]; ok {
+		return r, end + 1
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return 0, 0
+	}
+	base, digits := 10, ref[1:]
+	if strings.HasPrefix(digits, "x") || strings.HasPrefix(digits, "X") {
+		base, digits = 16, digits[1:]
+	}
+	n, err := strconv.ParseInt(digits, base, 32)
+	if err != nil || n <= 0 || n > 0x10FFFF || (n >= 0xD800 && n <= 0xDFFF) {
+		return 0, 0
+	}
+	return rune(n), end + 1
+}
@@ -0,0 +1,334 @@
+// internal/markup/markdown.go
+package markup
+
+import "strings"
+
+// markdownV2Reserved are the characters MarkdownV2 text must escape.
+const markdownV2Reserved = "_*[]()~`>#+-=|{}.!"
+
+// Entity type names used in MarkdownV2 errors, as Telegram spells them.
+var markdownV2Names = map[string]string{
+	"bold":          "Bold",
+	"italic":        "Italic",
+	"underline":     "Underline",
+	"strikethrough": "Strikethrough",
+	"spoiler":       "Spoiler",
+	"code":          "Code",
+	"pre":           "Pre",
+	"text_link":     "TextUrl",
+	"custom_emoji":  "CustomEmoji",
+}
+
+// openEntity is an entity whose end hasn't been found yet.
+type openEntity struct {
+	typ        string
+	language   string
+	offset     int // In UTF-16 code units of the plain text
+	byteOffset int // In bytes of the markup
+	textStart  int // In bytes of the plain text
+}
+
+// parseMarkdownV2 parses MarkdownV2, following Telegram's parser: every
+// reserved character is markup unless escaped, and entities may nest.
+func parseMarkdownV2(text string) (string, []Entity, error) {
+	var out builder
+	var entities []Entity
+	var stack []openEntity
+	quote := -1 // Offset of the blockquote being built, if any
+	expandable := false
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		// Lines starting with > are quoted; consecutive lines form one
+		// blockquote
+		lineStart := i == 0 || text[i-1] == '\n'
+		if lineStart && len(stack) == 0 {
+			if c == '>' || strings.HasPrefix(text[i:], "**>") {
+				if quote < 0 {
+					quote = out.units
+					expandable = c == '*'
+				}
+				if c == '*' {
+					i += 2
+				}
+				continue
+			}
+			if quote >= 0 {
+				entities = appendQuote(entities, quote, out.units, expandable)
+				quote = -1
+			}
+		}
+
+		if c == '\\' && at(text, i+1) > 0 && at(text, i+1) <= 126 {
+			i++
+			out.writeByte(text[i])
+			continue
+		}
+
+		reserved := markdownV2Reserved
+		inCode := len(stack) > 0 && (stack[len(stack)-1].typ == "code" || stack[len(stack)-1].typ == "pre")
+		if inCode {
+			reserved = "`"
+		}
+		if quote >= 0 && expandable && c == '|' && at(text, i+1) == '|' && (i+2 == len(text) || text[i+2] == '\n') && len(stack) == 0 {
+			// || closes an expandable blockquote at the end of a line
+			i++
+			continue
+		}
+		if !strings.ContainsRune(reserved, rune(c)) {
+			out.writeByte(c)
+			continue
+		}
+
+		if n := len(stack); n > 0 && closesMarkdownV2(stack[n-1].typ, text, i) {
+			open := stack[n-1]
+			stack = stack[:n-1]
+			entity := Entity{Type: open.typ, Offset: open.offset, Length: out.units - open.offset, Language: open.language}
+			skip := entity.Length == 0
+			switch open.typ {
+			case "underline", "spoiler":
+				i++
+			case "pre":
+				i += 2
+			case "text_link", "custom_emoji":
+				link := out.String()[open.textStart:]
+				if at(text, i+1) == '(' {
+					var end int
+					var err error
+					link, end, err = markdownV2Link(text, i+2, open.typ)
+					if err != nil {
+						return "", nil, err
+					}
+					i = end
+				} else if open.typ == "custom_emoji" {
+					return "", nil, errorf("Custom emoji entity must contain a tg://emoji URL")
+				}
+				if open.typ == "custom_emoji" {
+					id, ok := customEmojiID(link)
+					if !ok {
+						return "", nil, errorf("Custom emoji URL must have an emoji identifier")
+					}
+					entity.CustomEmojiID = id
+				} else if u, ok := linkURL(link); ok {
+					entity.URL = u
+				} else {
+					skip = true
+				}
+			}
+			if !skip {
+				entities = append(entities, entity)
+			}
+			continue
+		}
+
+		open := openEntity{offset: out.units, byteOffset: i, textStart: out.Len()}
+		switch c {
+		case '_':
+			open.typ = "italic"
+			if at(text, i+1) == '_' {
+				open.typ = "underline"
+				i++
+			}
+		case '*':
+			open.typ = "bold"
+		case '~':
+			open.typ = "strikethrough"
+		case '|':
+			if at(text, i+1) != '|' {
+				return "", nil, reservedError(c)
+			}
+			open.typ = "spoiler"
+			i++
+		case '[':
+			open.typ = "text_link"
+		case '!':
+			if at(text, i+1) != '[' {
+				return "", nil, reservedError(c)
+			}
+			open.typ = "custom_emoji"
+			i++
+		case '`':
+			open.typ = "code"
+			if at(text, i+1) == '`' && at(text, i+2) == '`' {
+				open.typ = "pre"
+				i = preStart(text, i+3, &open.language) - 1
+			}
+		default:
+			return "", nil, reservedError(c)
+		}
+		stack = append(stack, open)
+	}
+
+	if n := len(stack); n > 0 {
+		open := stack[n-1]
+		return "", nil, errorf("Can't find end of %s entity at byte offset %d", markdownV2Names[open.typ], open.byteOffset)
+	}
+	if quote >= 0 {
+		entities = appendQuote(entities, quote, out.units, expandable)
+	}
+	return out.String(), entities, nil
+}
+
+// closesMarkdownV2 reports whether the markup at i ends an open entity of
+// type typ.
+func closesMarkdownV2(typ, text string, i int) bool {
+	c := text[i]
+	switch typ {
+	case "bold":
+		return c == '*'
+	case "italic":
+		return c == '_' && at(text, i+1) != '_'
+	case "underline":
+		return c == '_' && at(text, i+1) == '_'
+	case "strikethrough":
+		return c == '~'
+	case "spoiler":
+		return c == '|' && at(text, i+1) == '|'
+	case "code":
+		return c == '`'
+	case "pre":
+		return c == '`' && at(text, i+1) == '`' && at(text, i+2) == '`'
+	case "text_link", "custom_emoji":
+		return c == ']'
+	}
+	return false
+}
+
+// markdownV2Link reads the URL of a link starting at i, up to the closing
+// parenthesis, and returns it with the position of the parenthesis.
+func markdownV2Link(text string, i int, typ string) (string, int, error) {
+	start := i
+	var link strings.Builder
+	for i < len(text) && text[i] != ')' {
+		if text[i] == '\\' && at(text, i+1) > 0 && at(text, i+1) <= 126 {
+			i++
+		}
+		link.WriteByte(text[i])
+		i++
+	}
+	if i >= len(text) {
+		if typ == "custom_emoji" {
+			return "", 0, errorf("Can't find end of a custom emoji URL at byte offset %d", start)
+		}
+		return "", 0, errorf("Can't find end of a URL at byte offset %d", start)
+	}
+	return link.String(), i, nil
+}
+
+// preStart skips the language and the first line break of a pre block
+// starting at i, storing the language, and returns where its text starts.
+func preStart(text string, i int, language *string) int {
+	end := i
+	for end < len(text) && !isSpace(text[end]) && text[end] != '`' {
+		end++
+	}
+	if end != i && end < len(text) && text[end] != '`' {
+		*language = text[i:end]
+		i = end
+	}
+	if c := at(text, i); c == '\n' || c == '\r' {
+		if next := at(text, i+1); (next == '\n' || next == '\r') && next != c {
+			i += 2
+		} else {
+			i++
+		}
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func reservedError(c byte) *Error {
+	return errorf("Character '%c' is reserved and must be escaped with the preceding '\\'", c)
+}
+
+// appendQuote adds a blockquote from offset to end, leaving out the line
+// break that ends it.
+func appendQuote(entities []Entity, offset, end int, expandable bool) []Entity {
+	typ := "blockquote"
+	if expandable {
+		typ = "expandable_blockquote"
+	}
+	if end > offset {
+		entities = append(entities, Entity{Type: typ, Offset: offset, Length: end - offset})
+	}
+	return entities
+}
+
+// parseMarkdown parses the legacy Markdown mode, which has bold, italic,
+// code, pre, and links that can't nest, and only escapes markup
+// characters with a backslash.
+func parseMarkdown(text string) (string, []Entity, error) {
+	var out builder
+	var entities []Entity
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c == '\\' && strings.IndexByte("_*`[", at(text, i+1)) >= 0 && at(text, i+1) != 0 {
+			i++
+			out.writeByte(text[i])
+			continue
+		}
+		if strings.IndexByte("_*`[", c) < 0 {
+			out.writeByte(c)
+			continue
+		}
+
+		begin := i
+		end := c
+		if c == '[' {
+			end = ']'
+		}
+		i++
+		pre := false
+		language := ""
+		if c == '`' && at(text, i) == '`' && at(text, i+1) == '`' {
+			pre = true
+			i = preStart(text, i+2, &language)
+		}
+		offset, textStart := out.units, out.Len()
+		for i < len(text) && (text[i] != end || (pre && !(at(text, i+1) == '`' && at(text, i+2) == '`'))) {
+			out.writeByte(text[i])
+			i++
+		}
+		if i >= len(text) {
+			return "", nil, errorf("Can't find end of the entity starting at byte offset %d", begin)
+		}
+
+		entity := Entity{Offset: offset, Length: out.units - offset}
+		switch c {
+		case '_':
+			entity.Type = "italic"
+		case '*':
+			entity.Type = "bold"
+		case '`':
+			entity.Type = "code"
+			if pre {
+				entity.Type = "pre"
+				entity.Language = language
+				i += 2
+			}
+		case '[':
+			link := out.String()[textStart:]
+			if at(text, i+1) == '(' {
+				i += 2
+				start := i
+				for i < len(text) && text[i] != ')' {
+					i++
+				}
+				link = text[start:i]
+			}
+			if u, ok := linkURL(link); ok {
+				entity.Type = "text_link"
+				entity.URL = u
+			}
+		}
+		if entity.Type != "" && entity.Length > 0 {
+			entities = append(entities, entity)
+		}
+	}
+	return out.String(), entities, nil
+}
@@ -0,0 +1,163 @@
+// Package markup parses the formatting options of the Bot API, HTML,
+// MarkdownV2, and legacy Markdown, into plain text and message entities,
+// as Telegram does with parse_mode. It also finds the entities Telegram
+// detects in plain text, such as mentions and URLs.
+package markup
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Parse modes, as passed in parse_mode. Modes are matched regardless of
+// case.
+const (
+	ModeHTML       = "HTML"
+	ModeMarkdownV2 = "MarkdownV2"
+	ModeMarkdown   = "Markdown"
+)
+
+// Entity is a MessageEntity. Offsets and lengths are in UTF-16 code units.
+type Entity struct {
+	Type          string
+	Offset        int
+	Length        int
+	URL           string
+	Language      string
+	CustomEmojiID string
+}
+
+// Fields returns the entity as a MessageEntity object.
+func (e Entity) Fields() map[string]interface{} {
+	fields := map[string]interface{}{
+		"type":   e.Type,
+		"offset": e.Offset,
+		"length": e.Length,
+	}
+	if e.URL != "" {
+		fields["url"] = e.URL
+	}
+	if e.Language != "" {
+		fields["language"] = e.Language
+	}
+	if e.CustomEmojiID != "" {
+		fields["custom_emoji_id"] = e.CustomEmojiID
+	}
+	return fields
+}
+
+// Error is markup Telegram can't parse. Its message is the reason
+// Telegram gives after "can't parse entities: ".
+type Error struct {
+	Reason string
+}
+
+func (e *Error) Error() string { return e.Reason }
+
+func errorf(format string, args ...interface{}) *Error {
+	return &Error{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Parse removes the markup of a parse mode from text and returns the
+// plain text with the entities the markup described, ordered by offset.
+// Text in an unknown or empty mode is returned as is.
+func Parse(text, mode string) (string, []Entity, error) {
+	var (
+		plain    string
+		entities []Entity
+		err      error
+	)
+	switch strings.ToLower(mode) {
+	case "html":
+		plain, entities, err = parseHTML(text)
+	case "markdownv2":
+		plain, entities, err = parseMarkdownV2(text)
+	case "markdown":
+		plain, entities, err = parseMarkdown(text)
+	default:
+		return text, nil, nil
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	sortEntities(entities)
+	return plain, entities, nil
+}
+
+// sortEntities orders entities by offset, with enclosing entities before
+// the entities they contain.
+func sortEntities(entities []Entity) {
+	sort.SliceStable(entities, func(i, j int) bool {
+		if entities[i].Offset != entities[j].Offset {
+			return entities[i].Offset < entities[j].Offset
+		}
+		return entities[i].Length > entities[j].Length
+	})
+}
+
+// builder accumulates plain text and counts its length in UTF-16 code
+// units, which entity offsets are measured in.
+type builder struct {
+	strings.Builder
+	units int
+}
+
+func (b *builder) writeByte(c byte) {
+	b.WriteByte(c)
+	// Count each UTF-8 sequence once, when its first byte is written;
+	// 4-byte sequences are surrogate pairs in UTF-16
+	if c&0xC0 != 0x80 {
+		b.units++
+		if c >= 0xF0 {
+			b.units++
+		}
+	}
+}
+
+func (b *builder) writeRune(r rune) {
+	b.WriteRune(r)
+	b.units++
+	if r >= 0x10000 {
+		b.units++
+	}
+}
+
+// at returns the byte of text at i, or 0 past its end.
+func at(text string, i int) byte {
+	if i < 0 || i >= len(text) {
+		return 0
+	}
+	return text[i]
+}
+
+// linkURL returns the URL a link points to as Telegram normalizes it, or
+// false if it isn't a valid URL. Links without a scheme are HTTP links.
+func linkURL(link string) (string, bool) {
+	link = strings.TrimSpace(link)
+	if link == "" || strings.ContainsAny(link, " \n\t") {
+		return "", false
+	}
+	if !strings.Contains(link, "://") && !strings.HasPrefix(link, "mailto:") && !strings.HasPrefix(link, "tg:") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "" && u.Scheme != "tg") {
+		return "", false
+	}
+	return link, true
+}
+
+// customEmojiID returns the ID of a tg://emoji?id=... link.
+func customEmojiID(link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Scheme != "tg" || (u.Host != "emoji" && u.Opaque != "emoji") {
+		return "", false
+	}
+	id := u.Query().Get("id")
+	if id == "" || strings.Trim(id, "0123456789") != "" {
+		return "", false
+	}
+	return id, true
+}
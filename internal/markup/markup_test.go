@@ -0,0 +1,117 @@
+// internal/markup/markup_test.go
+package markup
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		mode     string
+		plain    string
+		entities []Entity
+	}{
+		{"html", `<b>bold <i>both</i></b> &amp; <a href="example.com">link</a>`, ModeHTML, "bold both & link", []Entity{
+			{Type: "bold", Offset: 0, Length: 9},
+			{Type: "italic", Offset: 5, Length: 4},
+			{Type: "text_link", Offset: 12, Length: 4, URL: "http://example.com"},
+		}},
+		{"html pre with language", `<pre><code class="language-go">x := 1</code></pre>`, ModeHTML, "x := 1", []Entity{
+			{Type: "pre", Offset: 0, Length: 6, Language: "go"},
+		}},
+		{"html spoiler and quote", `<span class="tg-spoiler">a</span><blockquote expandable>b</blockquote>`, ModeHTML, "ab", []Entity{
+			{Type: "spoiler", Offset: 0, Length: 1},
+			{Type: "expandable_blockquote", Offset: 1, Length: 1},
+		}},
+		{"utf-16 offsets", "😀 <b>hi</b>", "html", "😀 hi", []Entity{{Type: "bold", Offset: 3, Length: 2}}},
+		{"markdownv2", `*bold _both_* __under__ ~s~ ||sp|| \. [link](http://x/\)) ` + "`c`", ModeMarkdownV2, "bold both under s sp . link c", []Entity{
+			{Type: "bold", Offset: 0, Length: 9},
+			{Type: "italic", Offset: 5, Length: 4},
+			{Type: "underline", Offset: 10, Length: 5},
+			{Type: "strikethrough", Offset: 16, Length: 1},
+			{Type: "spoiler", Offset: 18, Length: 2},
+			{Type: "text_link", Offset: 23, Length: 4, URL: "http://x/)"},
+			{Type: "code", Offset: 28, Length: 1},
+		}},
+		{"markdownv2 pre", "```python\nprint(1)\n```", ModeMarkdownV2, "print(1)\n", []Entity{
+			{Type: "pre", Offset: 0, Length: 9, Language: "python"},
+		}},
+		{"markdownv2 custom emoji", "![👍](tg://emoji?id=5368324170671202286)", ModeMarkdownV2, "👍", []Entity{
+			{Type: "custom_emoji", Offset: 0, Length: 2, CustomEmojiID: "5368324170671202286"},
+		}},
+		{"markdownv2 blockquote", ">one\n>two\nthree", ModeMarkdownV2, "one\ntwo\nthree", []Entity{
+			{Type: "blockquote", Offset: 0, Length: 8},
+		}},
+		{"markdown", "*bold* _it_ `code` [link](http://x) \\*", ModeMarkdown, "bold it code link *", []Entity{
+			{Type: "bold", Offset: 0, Length: 4},
+			{Type: "italic", Offset: 5, Length: 2},
+			{Type: "code", Offset: 8, Length: 4},
+			{Type: "text_link", Offset: 13, Length: 4, URL: "http://x"},
+		}},
+		{"no mode", "*as is*", "", "*as is*", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plain, entities, err := Parse(tt.text, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plain != tt.plain {
+				t.Errorf("expected %q, got %q", tt.plain, plain)
+			}
+			if !reflect.DeepEqual(entities, tt.entities) {
+				t.Errorf("expected %+v, got %+v", tt.entities, entities)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		text string
+		mode string
+		want string
+	}{
+		{"1.5", ModeMarkdownV2, `Character '.' is reserved and must be escaped with the preceding '\'`},
+		{"*bold", ModeMarkdownV2, "Can't find end of Bold entity at byte offset 0"},
+		{"a ```code", ModeMarkdownV2, "Can't find end of Pre entity at byte offset 2"},
+		{"[x](http://", ModeMarkdownV2, "Can't find end of a URL at byte offset 4"},
+		{"_it", ModeMarkdown, "Can't find end of the entity starting at byte offset 0"},
+		{"<b>bold", ModeHTML, `Can't find end tag corresponding to start tag "b"`},
+		{"<b><i>x</b></i>", ModeHTML, `Unmatched end tag at byte offset 7, expected "</i>", found "</b>"`},
+		{"x</b>", ModeHTML, "Unexpected end tag at byte offset 1"},
+		{"<blink>x</blink>", ModeHTML, `Unsupported start tag "blink" at byte offset 0`},
+		{`<a href="x`, ModeHTML, "Unclosed start tag at byte offset 0"},
+	}
+	for _, tt := range tests {
+		_, _, err := Parse(tt.text, tt.mode)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("%s %q: expected %q, got %v", tt.mode, tt.text, tt.want, err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	text := "/start@my_bot hi @someone, see https://example.com/a. #tag $USD ann@example.org `x`"
+	entities := Detect(text, []Entity{{Type: "code", Offset: strings.Index(text, "`"), Length: 3}})
+	var got []string
+	for _, e := range entities {
+		got = append(got, e.Type+":"+text[e.Offset:e.Offset+e.Length])
+	}
+	want := []string{
+		"bot_command:/start@my_bot",
+		"mention:@someone",
+		"url:https://example.com/a",
+		"hashtag:#tag",
+		"cashtag:$USD",
+		"email:ann@example.org",
+		"code:`x`",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
@@ -0,0 +1,172 @@
+// internal/members/moderation.go
+package members
+
+import (
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// Permissions are the ChatPermissions fields of a restricted member.
+var Permissions = []string{
+	"can_send_messages", "can_send_audios", "can_send_documents", "can_send_photos",
+	"can_send_videos", "can_send_video_notes", "can_send_voice_notes", "can_send_polls",
+	"can_send_other_messages", "can_add_web_page_previews", "can_change_info",
+	"can_invite_users", "can_pin_messages", "can_manage_topics",
+}
+
+// mediaPermissions are implied by can_send_other_messages and
+// can_add_web_page_previews unless permissions are set independently.
+var mediaPermissions = []string{
+	"can_send_messages", "can_send_audios", "can_send_documents", "can_send_photos",
+	"can_send_videos", "can_send_video_notes", "can_send_voice_notes",
+}
+
+// AdminRights are the rights of an administrator, as passed to
+// promoteChatMember.
+var AdminRights = []string{
+	"is_anonymous", "can_manage_chat", "can_delete_messages", "can_manage_video_chats",
+	"can_restrict_members", "can_promote_members", "can_change_info", "can_invite_users",
+	"can_post_stories", "can_edit_stories", "can_delete_stories", "can_post_messages",
+	"can_edit_messages", "can_pin_messages", "can_manage_topics",
+}
+
+// current returns the known status of a member, after lifting expired bans
+// and restrictions. Callers must hold s.mu.
+func (s *Store) current(chatID string, userID int64) map[string]interface{} {
+	fields, ok := s.chats[chatID][userID]
+	if !ok {
+		return nil
+	}
+	s.expire(fields)
+	return fields
+}
+
+// set stores the status of a member. Callers must hold s.mu.
+func (s *Store) set(chatID string, userID int64, fields map[string]interface{}) {
+	members, ok := s.chats[chatID]
+	if !ok {
+		members = make(map[int64]map[string]interface{})
+		s.chats[chatID] = members
+	}
+	members[userID] = fields
+}
+
+// Ban makes a user kicked from a chat until a Unix time, 0 meaning
+// forever, as banChatMember does. The owner can't be banned.
+func (s *Store) Ban(chatID string, userID int64, until int64) *tgerrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cur := s.current(chatID, userID); cur["status"] == StatusCreator {
+		return tgerrors.CantRemoveOwner()
+	}
+	s.set(chatID, userID, map[string]interface{}{
+		"status":     StatusKicked,
+		"until_date": float64(untilDate(s.now(), until)),
+	})
+	return nil
+}
+
+// Unban lifts the ban of a user, as unbanChatMember does: the user has
+// left the chat and may join again. Unless onlyIfBanned, members are
+// removed from the chat too.
+func (s *Store) Unban(chatID string, userID int64, onlyIfBanned bool) *tgerrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.current(chatID, userID)
+	if cur["status"] == StatusCreator {
+		return tgerrors.CantRemoveOwner()
+	}
+	if onlyIfBanned && cur["status"] != StatusKicked {
+		return nil
+	}
+	s.set(chatID, userID, map[string]interface{}{"status": StatusLeft})
+	return nil
+}
+
+// Restrict sets the permissions of a user until a Unix time, 0 meaning
+// forever, as restrictChatMember does. Unless independent, sending other
+// messages or link previews implies sending media, and polls imply
+// messages. Granting every permission lifts the restriction.
+// Administrators can't be restricted.
+func (s *Store) Restrict(chatID string, userID int64, permissions map[string]interface{}, independent bool, until int64) *tgerrors.Error {
+	granted := make(map[string]bool, len(Permissions))
+	for _, p := range Permissions {
+		granted[p], _ = permissions[p].(bool)
+	}
+	if !independent {
+		if granted["can_send_other_messages"] || granted["can_add_web_page_previews"] {
+			for _, p := range mediaPermissions {
+				granted[p] = true
+			}
+		}
+		if granted["can_send_polls"] {
+			granted["can_send_messages"] = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.current(chatID, userID)
+	switch cur["status"] {
+	case StatusCreator, StatusAdministrator:
+		return tgerrors.UserIsAdmin()
+	}
+	isMember := true
+	switch cur["status"] {
+	case StatusLeft, StatusKicked:
+		isMember = false
+	case StatusRestricted:
+		isMember, _ = cur["is_member"].(bool)
+	}
+
+	all := true
+	fields := map[string]interface{}{
+		"status":     StatusRestricted,
+		"is_member":  isMember,
+		"until_date": float64(untilDate(s.now(), until)),
+	}
+	for _, p := range Permissions {
+		fields[p] = granted[p]
+		all = all && granted[p]
+	}
+	if all {
+		if isMember {
+			fields = map[string]interface{}{"status": StatusMember}
+		} else {
+			fields = map[string]interface{}{"status": StatusLeft}
+		}
+	}
+	s.set(chatID, userID, fields)
+	return nil
+}
+
+// Promote sets the administrator rights of a user, as promoteChatMember
+// does. Granting none demotes an administrator to a member. The owner's
+// status doesn't change.
+func (s *Store) Promote(chatID string, userID int64, rights map[string]interface{}) *tgerrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.current(chatID, userID)
+	if cur["status"] == StatusCreator {
+		return nil
+	}
+
+	fields := map[string]interface{}{
+		"status":        StatusAdministrator,
+		"can_be_edited": true,
+	}
+	promoted := false
+	for _, r := range AdminRights {
+		granted, _ := rights[r].(bool)
+		fields[r] = granted
+		promoted = promoted || granted
+	}
+	if !promoted {
+		s.set(chatID, userID, map[string]interface{}{"status": StatusMember})
+		return nil
+	}
+	if cur["status"] == StatusAdministrator && cur["custom_title"] != nil {
+		fields["custom_title"] = cur["custom_title"]
+	}
+	s.set(chatID, userID, fields)
+	return nil
+}
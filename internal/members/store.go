@@ -0,0 +1,210 @@
+// Package members tracks the status of users in chats, as changed by
+// moderation methods, so that getChatMember returns what bots did instead
+// of a random status.
+package members
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// Member statuses, as in ChatMember.status.
+const (
+	StatusCreator       = "creator"
+	StatusAdministrator = "administrator"
+	StatusMember        = "member"
+	StatusRestricted    = "restricted"
+	StatusLeft          = "left"
+	StatusKicked        = "kicked"
+)
+
+var statuses = map[string]bool{
+	StatusCreator: true, StatusAdministrator: true, StatusMember: true,
+	StatusRestricted: true, StatusLeft: true, StatusKicked: true,
+}
+
+// Member is the known status of a user in a chat: the ChatMember fields
+// other than user.
+type Member struct {
+	ChatID string                 `json:"chat_id"`
+	UserID int64                  `json:"user_id"`
+	Fields map[string]interface{} `json:"fields"`
+}
+
+// Store holds the known members of chats. Values are copied on the way in
+// and out.
+type Store struct {
+	mu    sync.Mutex
+	now   func() time.Time
+	chats map[string]map[int64]map[string]interface{}
+}
+
+// NewStore creates an empty member store reading the time from now, which
+// decides when bans and restrictions end.
+func NewStore(now func() time.Time) *Store {
+	return &Store{now: now, chats: make(map[string]map[int64]map[string]interface{})}
+}
+
+// Get returns the known status of a user in a chat. Bans and restrictions
+// whose until_date passed are lifted first.
+func (s *Store) Get(chatID string, userID int64) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields, ok := s.chats[chatID][userID]
+	if !ok {
+		return nil, false
+	}
+	s.expire(fields)
+	return jsoncopy.Map(fields), true
+}
+
+// Set replaces the known status of a user in a chat. Fields needs a valid
+// status.
+func (s *Store) Set(chatID string, userID int64, fields map[string]interface{}) error {
+	if status, _ := fields["status"].(string); !statuses[status] {
+		return fmt.Errorf("invalid member status %v", fields["status"])
+	}
+	fields = jsoncopy.Map(fields)
+	delete(fields, "user")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(chatID, userID, fields)
+	return nil
+}
+
+// Delete forgets the status of a user in a chat. It returns false if it
+// wasn't known.
+func (s *Store) Delete(chatID string, userID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.chats[chatID][userID]; !ok {
+		return false
+	}
+	delete(s.chats[chatID], userID)
+	if len(s.chats[chatID]) == 0 {
+		delete(s.chats, chatID)
+	}
+	return true
+}
+
+// List returns the known members of a chat, or of every chat if chatID is
+// empty, ordered by chat and user.
+func (s *Store) List(chatID string) []Member {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var list []Member
+	for id, members := range s.chats {
+		if chatID != "" && id != chatID {
+			continue
+		}
+		for userID, fields := range members {
+			s.expire(fields)
+			list = append(list, Member{ChatID: id, UserID: userID, Fields: jsoncopy.Map(fields)})
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ChatID != list[j].ChatID {
+			return list[i].ChatID < list[j].ChatID
+		}
+		return list[i].UserID < list[j].UserID
+	})
+	return list
+}
+
+// Administrators returns the owner and administrators of a chat, owner
+// first.
+func (s *Store) Administrators(chatID string) []Member {
+	var admins []Member
+	for _, m := range s.List(chatID) {
+		switch m.Fields["status"] {
+		case StatusCreator:
+			admins = append([]Member{m}, admins...)
+		case StatusAdministrator:
+			admins = append(admins, m)
+		}
+	}
+	return admins
+}
+
+// Count returns the number of known members in a chat: everyone but users
+// who left or were banned.
+func (s *Store) Count(chatID string) int {
+	n := 0
+	for _, m := range s.List(chatID) {
+		if Present(m.Fields) {
+			n++
+		}
+	}
+	return n
+}
+
+// Present reports whether a member with the given fields is in the chat.
+// Restricted users may have left it.
+func Present(fields map[string]interface{}) bool {
+	switch fields["status"] {
+	case StatusLeft, StatusKicked:
+		return false
+	case StatusRestricted:
+		return fields["is_member"] != false
+	}
+	return true
+}
+
+// Restore replaces the store contents with the given members.
+func (s *Store) Restore(list []Member) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[int64]map[string]interface{})
+	for _, m := range list {
+		s.set(m.ChatID, m.UserID, jsoncopy.Map(m.Fields))
+	}
+}
+
+// Clear forgets all members.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[int64]map[string]interface{})
+}
+
+// expire lifts a ban or restriction whose until_date passed. Callers must
+// hold s.mu.
+func (s *Store) expire(fields map[string]interface{}) {
+	until, _ := fields["until_date"].(float64)
+	if until == 0 || s.now().Unix() < int64(until) {
+		return
+	}
+	switch fields["status"] {
+	case StatusKicked:
+		replace(fields, map[string]interface{}{"status": StatusLeft})
+	case StatusRestricted:
+		if fields["is_member"] == false {
+			replace(fields, map[string]interface{}{"status": StatusLeft})
+		} else {
+			replace(fields, map[string]interface{}{"status": StatusMember})
+		}
+	}
+}
+
+// untilDate returns the until_date to store for a ban or restriction.
+// Like Telegram, periods under 30 seconds or over 366 days are forever.
+func untilDate(now time.Time, until int64) int64 {
+	if d := time.Unix(until, 0).Sub(now); d < 30*time.Second || d > 366*24*time.Hour {
+		return 0
+	}
+	return until
+}
+
+func replace(fields, with map[string]interface{}) {
+	for name := range fields {
+		delete(fields, name)
+	}
+	for name, v := range with {
+		fields[name] = v
+	}
+}
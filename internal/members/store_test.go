@@ -0,0 +1,118 @@
+// internal/members/store_test.go
+package members
+
+import (
+	"testing"
+	"time"
+)
+
+func newTestStore() (*Store, *time.Time) {
+	now := time.Unix(1700000000, 0)
+	return NewStore(func() time.Time { return now }), &now
+}
+
+func status(t *testing.T, s *Store, chatID string, userID int64) string {
+	t.Helper()
+	fields, _ := s.Get(chatID, userID)
+	st, _ := fields["status"].(string)
+	return st
+}
+
+func TestStore_BanAndUnban(t *testing.T) {
+	s, now := newTestStore()
+
+	if err := s.Ban("-100", 7, now.Add(time.Hour).Unix()); err != nil {
+		t.Fatal(err)
+	}
+	if got := status(t, s, "-100", 7); got != StatusKicked {
+		t.Fatalf("expected the user to be kicked, got %q", got)
+	}
+	*now = now.Add(2 * time.Hour)
+	if got := status(t, s, "-100", 7); got != StatusLeft {
+		t.Errorf("expected the ban to end, got %q", got)
+	}
+
+	s.Ban("-100", 7, 0)
+	s.Unban("-100", 7, true)
+	if got := status(t, s, "-100", 7); got != StatusLeft {
+		t.Errorf("expected unban to let the user join again, got %q", got)
+	}
+
+	s.Set("-100", 8, map[string]interface{}{"status": StatusMember})
+	s.Unban("-100", 8, true)
+	if got := status(t, s, "-100", 8); got != StatusMember {
+		t.Errorf("expected only_if_banned to keep members, got %q", got)
+	}
+	s.Unban("-100", 8, false)
+	if got := status(t, s, "-100", 8); got != StatusLeft {
+		t.Errorf("expected unban to remove members, got %q", got)
+	}
+
+	s.Set("-100", 1, map[string]interface{}{"status": StatusCreator})
+	if err := s.Ban("-100", 1, 0); err == nil {
+		t.Error("expected the owner not to be banned")
+	}
+}
+
+func TestStore_Restrict(t *testing.T) {
+	s, now := newTestStore()
+
+	if err := s.Restrict("-100", 7, map[string]interface{}{"can_send_polls": true}, false, now.Add(time.Hour).Unix()); err != nil {
+		t.Fatal(err)
+	}
+	fields, _ := s.Get("-100", 7)
+	if fields["status"] != StatusRestricted || fields["can_send_messages"] != true || fields["can_send_photos"] != false || fields["is_member"] != true {
+		t.Errorf("expected polls to imply messages only, got %v", fields)
+	}
+
+	all := map[string]interface{}{}
+	for _, p := range Permissions {
+		all[p] = true
+	}
+	s.Restrict("-100", 7, all, true, 0)
+	if got := status(t, s, "-100", 7); got != StatusMember {
+		t.Errorf("expected granting every permission to lift the restriction, got %q", got)
+	}
+
+	s.Promote("-100", 9, map[string]interface{}{"can_delete_messages": true})
+	if err := s.Restrict("-100", 9, nil, true, 0); err == nil {
+		t.Error("expected administrators not to be restricted")
+	}
+}
+
+func TestStore_Promote(t *testing.T) {
+	s, _ := newTestStore()
+
+	s.Promote("-100", 7, map[string]interface{}{"can_pin_messages": true})
+	fields, _ := s.Get("-100", 7)
+	if fields["status"] != StatusAdministrator || fields["can_pin_messages"] != true || fields["can_manage_chat"] != false || fields["can_be_edited"] != true {
+		t.Errorf("unexpected administrator %v", fields)
+	}
+
+	s.Promote("-100", 7, nil)
+	if got := status(t, s, "-100", 7); got != StatusMember {
+		t.Errorf("expected granting no rights to demote, got %q", got)
+	}
+
+	if err := s.Set("-100", 7, map[string]interface{}{"status": "owner"}); err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+}
+
+func TestStore_AdministratorsAndCount(t *testing.T) {
+	s := NewStore(time.Now)
+	s.Set("-100", 3, map[string]interface{}{"status": StatusAdministrator})
+	s.Set("-100", 1, map[string]interface{}{"status": StatusCreator})
+	s.Set("-100", 2, map[string]interface{}{"status": StatusMember})
+	s.Set("-100", 4, map[string]interface{}{"status": StatusRestricted, "is_member": false})
+	s.Ban("-100", 5, 0)
+	s.Set("-200", 6, map[string]interface{}{"status": StatusAdministrator})
+
+	admins := s.Administrators("-100")
+	if len(admins) != 2 || admins[0].UserID != 1 || admins[1].UserID != 3 {
+		t.Errorf("expected the owner and then the administrator, got %+v", admins)
+	}
+	if n := s.Count("-100"); n != 3 {
+		t.Errorf("expected 3 members in the chat, got %d", n)
+	}
+}
@@ -0,0 +1,259 @@
+// Package messages keeps the messages a bot has sent, so their current
+// state (such as the visible inline keyboard) can be edited by later Bot
+// API calls and inspected by tests.
+package messages
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// DefaultDeleteWindow is how long after they were sent Telegram lets bots
+// delete messages.
+const DefaultDeleteWindow = 48 * time.Hour
+
+// Errors returned by Remove.
+var (
+	ErrNotFound = errors.New("message not found")
+	ErrTooOld   = errors.New("message is too old to be deleted")
+)
+
+// Entry is a stored message together with the chat it was sent to.
+type Entry struct {
+	ChatID  string                 `json:"chat_id"`
+	Message map[string]interface{} `json:"message"`
+}
+
+type key struct {
+	chatID    string
+	messageID int64
+}
+
+type entry struct {
+	message map[string]interface{}
+	size    int64
+	seq     int64 // Insertion order, used to evict the oldest message
+}
+
+// Store holds messages keyed by chat and message ID. Messages are copied
+// on the way in and out, so callers may keep using the maps they pass or
+// receive.
+type Store struct {
+	mu           sync.RWMutex
+	now          func() time.Time
+	deleteWindow time.Duration
+	messages     map[key]*entry
+	seq          int64
+	bytes        int64
+}
+
+// NewStore creates an empty message store reading the time from now.
+// Messages can be removed for deleteWindow after they were sent; a zero
+// window uses DefaultDeleteWindow.
+func NewStore(now func() time.Time, deleteWindow time.Duration) *Store {
+	if deleteWindow <= 0 {
+		deleteWindow = DefaultDeleteWindow
+	}
+	return &Store{
+		now:          now,
+		deleteWindow: deleteWindow,
+		messages:     make(map[key]*entry),
+	}
+}
+
+// Put stores a message sent to chatID, replacing any message with the same
+// message_id. Messages without a message_id are ignored.
+func (s *Store) Put(chatID string, message map[string]interface{}) {
+	id, ok := MessageID(message["message_id"])
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.put(key{chatID, id}, jsoncopy.Map(message))
+}
+
+// put stores a message under k. s.mu must be held.
+func (s *Store) put(k key, message map[string]interface{}) {
+	if old, ok := s.messages[k]; ok {
+		s.bytes -= old.size
+	}
+	var size int64
+	if data, err := json.Marshal(message); err == nil {
+		size = int64(len(data))
+	}
+	s.seq++
+	s.messages[k] = &entry{message: message, size: size, seq: s.seq}
+	s.bytes += size
+}
+
+// Get returns a copy of the message with the given ID in chatID.
+func (s *Store) Get(chatID string, messageID int64) (map[string]interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	e, ok := s.messages[key{chatID, messageID}]
+	if !ok {
+		return nil, false
+	}
+	return jsoncopy.Map(e.message), true
+}
+
+// Delete removes a message. It returns true if the message existed.
+func (s *Store) Delete(chatID string, messageID int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := key{chatID, messageID}
+	e, ok := s.messages[k]
+	if !ok {
+		return false
+	}
+	s.bytes -= e.size
+	delete(s.messages, k)
+	return true
+}
+
+// Remove deletes a message as a bot would: it fails with ErrNotFound if
+// the message isn't stored and with ErrTooOld if it was sent longer ago
+// than the delete window. Messages without a date can always be removed.
+func (s *Store) Remove(chatID string, messageID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	k := key{chatID, messageID}
+	e, ok := s.messages[k]
+	if !ok {
+		return ErrNotFound
+	}
+	if date, ok := MessageID(e.message["date"]); ok && date > 0 {
+		if s.now().Sub(time.Unix(date, 0)) > s.deleteWindow {
+			return ErrTooOld
+		}
+	}
+	s.bytes -= e.size
+	delete(s.messages, k)
+	return nil
+}
+
+// List returns stored messages ordered by chat and message ID. If chatID
+// is not empty, only messages in that chat are returned.
+func (s *Store) List(chatID string) []Entry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]key, 0, len(s.messages))
+	for k := range s.messages {
+		if chatID == "" || k.chatID == chatID {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].chatID != keys[j].chatID {
+			return keys[i].chatID < keys[j].chatID
+		}
+		return keys[i].messageID < keys[j].messageID
+	})
+
+	result := make([]Entry, len(keys))
+	for i, k := range keys {
+		result[i] = Entry{ChatID: k.chatID, Message: jsoncopy.Map(s.messages[k].message)}
+	}
+	return result
+}
+
+// Restore replaces the store contents with the given entries.
+func (s *Store) Restore(entries []Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = make(map[key]*entry, len(entries))
+	s.bytes = 0
+	for _, e := range entries {
+		if id, ok := MessageID(e.Message["message_id"]); ok {
+			s.put(key{e.ChatID, id}, jsoncopy.Map(e.Message))
+		}
+	}
+}
+
+// Count returns the number of stored messages.
+func (s *Store) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.messages)
+}
+
+// Clear removes all stored messages.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages = make(map[key]*entry)
+	s.bytes = 0
+}
+
+// Size returns the approximate number of bytes held by stored messages.
+func (s *Store) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bytes
+}
+
+// EvictOldest removes the message that was stored (or last edited) first.
+// It returns false if the store is empty.
+func (s *Store) EvictOldest() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var oldestKey key
+	var oldest *entry
+	for k, e := range s.messages {
+		if oldest == nil || e.seq < oldest.seq {
+			oldestKey, oldest = k, e
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	s.bytes -= oldest.size
+	delete(s.messages, oldestKey)
+	return true
+}
+
+// ChatKey returns a chat_id parameter as a string key, so numeric IDs and
+// @usernames can be used interchangeably as given by the bot.
+func ChatKey(v interface{}) string {
+	switch id := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return strconv.FormatFloat(id, 'f', -1, 64)
+	default:
+		return fmt.Sprint(id)
+	}
+}
+
+// MessageID converts a message_id value from generated or decoded JSON
+// data to int64.
+func MessageID(v interface{}) (int64, bool) {
+	switch id := v.(type) {
+	case int64:
+		return id, true
+	case int:
+		return int64(id), true
+	case float64:
+		return int64(id), true
+	case json.Number:
+		n, err := id.Int64()
+		return n, err == nil
+	case string:
+		n, err := strconv.ParseInt(id, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,148 @@
+// internal/messages/store_test.go
+package messages
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_PutAndGet(t *testing.T) {
+	s := NewStore(time.Now, 0)
+
+	msg := map[string]interface{}{"message_id": int64(7), "text": "hello"}
+	s.Put("42", msg)
+
+	// The store keeps its own copy
+	msg["text"] = "changed"
+
+	got, ok := s.Get("42", 7)
+	if !ok {
+		t.Fatal("expected message to be stored")
+	}
+	if got["text"] != "hello" {
+		t.Errorf("got text %v, want hello", got["text"])
+	}
+
+	got["text"] = "mutated"
+	if again, _ := s.Get("42", 7); again["text"] != "hello" {
+		t.Error("expected Get to return a copy")
+	}
+
+	if _, ok := s.Get("43", 7); ok {
+		t.Error("expected messages to be scoped to their chat")
+	}
+
+	// Messages without an ID are ignored
+	s.Put("42", map[string]interface{}{"text": "no id"})
+	if s.Count() != 1 {
+		t.Errorf("count = %d, want 1", s.Count())
+	}
+}
+
+func TestStore_ListAndDelete(t *testing.T) {
+	s := NewStore(time.Now, 0)
+	s.Put("2", map[string]interface{}{"message_id": int64(1)})
+	s.Put("1", map[string]interface{}{"message_id": int64(5)})
+	s.Put("1", map[string]interface{}{"message_id": int64(3)})
+
+	all := s.List("")
+	if len(all) != 3 {
+		t.Fatalf("got %d messages, want 3", len(all))
+	}
+	if all[0].ChatID != "1" || all[0].Message["message_id"] != float64(3) || all[2].ChatID != "2" {
+		t.Errorf("unexpected order %+v", all)
+	}
+
+	if chat := s.List("1"); len(chat) != 2 {
+		t.Errorf("got %d messages in chat 1, want 2", len(chat))
+	}
+
+	if !s.Delete("1", 3) || s.Delete("1", 3) {
+		t.Error("expected Delete to succeed exactly once")
+	}
+	if s.Count() != 2 {
+		t.Errorf("count = %d, want 2", s.Count())
+	}
+}
+
+func TestStore_SizeAndEvict(t *testing.T) {
+	s := NewStore(time.Now, 0)
+	s.Put("1", map[string]interface{}{"message_id": int64(1), "text": "first"})
+	s.Put("1", map[string]interface{}{"message_id": int64(2), "text": "second"})
+	if s.Size() <= 0 {
+		t.Fatalf("size = %d, want > 0", s.Size())
+	}
+
+	// Re-storing a message (as edits do) makes it the newest
+	s.Put("1", map[string]interface{}{"message_id": int64(1), "text": "edited"})
+
+	if !s.EvictOldest() {
+		t.Fatal("expected EvictOldest to succeed")
+	}
+	if _, ok := s.Get("1", 2); ok {
+		t.Error("expected the least recently stored message to be evicted")
+	}
+
+	s.Clear()
+	if s.Size() != 0 || s.EvictOldest() {
+		t.Error("expected cleared store to be empty")
+	}
+}
+
+func TestStore_Restore(t *testing.T) {
+	s := NewStore(time.Now, 0)
+	s.Put("1", map[string]interface{}{"message_id": int64(1)})
+
+	// Decoded snapshots carry message IDs as float64
+	s.Restore([]Entry{
+		{ChatID: "@channel", Message: map[string]interface{}{"message_id": float64(9), "text": "restored"}},
+	})
+
+	if s.Count() != 1 {
+		t.Fatalf("count = %d, want 1", s.Count())
+	}
+	if msg, ok := s.Get("@channel", 9); !ok || msg["text"] != "restored" {
+		t.Errorf("unexpected restored message %v", msg)
+	}
+}
+
+func TestChatKey(t *testing.T) {
+	tests := []struct {
+		in   interface{}
+		want string
+	}{
+		{nil, ""},
+		{"@channel", "@channel"},
+		{float64(-1001234567890), "-1001234567890"},
+		{int64(42), "42"},
+	}
+	for _, tt := range tests {
+		if got := ChatKey(tt.in); got != tt.want {
+			t.Errorf("ChatKey(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStore_Remove(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s := NewStore(func() time.Time { return now }, time.Hour)
+	s.Put("42", map[string]interface{}{"message_id": int64(1), "date": now.Add(-30 * time.Minute).Unix()})
+	s.Put("42", map[string]interface{}{"message_id": int64(2), "date": float64(now.Add(-2 * time.Hour).Unix())})
+	s.Put("42", map[string]interface{}{"message_id": int64(3)})
+
+	if err := s.Remove("42", 1); err != nil {
+		t.Errorf("expected a recent message to be removed, got %v", err)
+	}
+	if err := s.Remove("42", 1); err != ErrNotFound {
+		t.Errorf("expected a removed message to be gone, got %v", err)
+	}
+	if err := s.Remove("42", 2); err != ErrTooOld {
+		t.Errorf("expected an old message to stay, got %v", err)
+	}
+	if err := s.Remove("42", 3); err != nil {
+		t.Errorf("expected a message without a date to be removed, got %v", err)
+	}
+	if s.Count() != 1 {
+		t.Errorf("count = %d, want 1", s.Count())
+	}
+}
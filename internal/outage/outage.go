@@ -0,0 +1,196 @@
+// Package outage simulates the short bursts of server errors Telegram
+// returns while its servers restart or a bot is migrated to another data
+// center. It also tracks whether clients retry the failed calls, and
+// whether those retries sent something twice.
+package outage
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Default error of a burst.
+const (
+	DefaultErrorCode   = 500
+	DefaultDescription = "Internal Server Error: restart"
+)
+
+// Config describes a burst. It ends after Requests failed calls or once
+// DurationMs has passed, whichever comes first; at least one must be set.
+type Config struct {
+	Requests    int    `json:"requests,omitempty"`
+	DurationMs  int64  `json:"duration_ms,omitempty"`
+	ErrorCode   int    `json:"error_code,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Methods restricts the burst to these methods (empty = all).
+	Methods []string `json:"methods,omitempty"`
+	// Applied makes failed calls take effect anyway, as when Telegram
+	// sends a message but the response is lost. Retrying such a call
+	// sends it twice.
+	Applied bool `json:"applied,omitempty"`
+}
+
+// Validate checks the config and fills in the default error.
+func (c *Config) Validate() error {
+	if c.Requests < 0 || c.DurationMs < 0 {
+		return fmt.Errorf("requests and duration_ms must not be negative")
+	}
+	if c.Requests == 0 && c.DurationMs == 0 {
+		return fmt.Errorf("requests or duration_ms is required")
+	}
+	if c.ErrorCode == 0 {
+		c.ErrorCode = DefaultErrorCode
+	}
+	if c.ErrorCode < 400 || c.ErrorCode > 599 {
+		return fmt.Errorf("error_code must be between 400 and 599")
+	}
+	if c.Description == "" {
+		c.Description = DefaultDescription
+	}
+	return nil
+}
+
+// Status reports the current or last burst.
+type Status struct {
+	Active    bool       `json:"active"`
+	Config    *Config    `json:"config"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	// Failed counts the calls the burst failed.
+	Failed int `json:"failed"`
+	// Retried counts failed calls that were later sent again with the
+	// same parameters and succeeded.
+	Retried int `json:"retried"`
+	// Duplicates counts retries of applied calls, i.e. calls that took
+	// effect twice.
+	Duplicates int `json:"duplicates"`
+}
+
+// Burst holds the burst of a session.
+type Burst struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	cfg     *Config
+	methods map[string]bool
+	started time.Time
+	ends    time.Time
+	status  Status
+	// pending holds the fingerprints of failed calls not retried yet.
+	pending map[string]int
+}
+
+// NewBurst creates an inactive burst reading the time from now. If now is
+// nil, the system clock is used.
+func NewBurst(now func() time.Time) *Burst {
+	if now == nil {
+		now = time.Now
+	}
+	return &Burst{now: now, pending: make(map[string]int)}
+}
+
+// Start begins a burst, replacing any previous one and its counters.
+func (b *Burst) Start(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = &cfg
+	b.methods = make(map[string]bool, len(cfg.Methods))
+	for _, m := range cfg.Methods {
+		b.methods[m] = true
+	}
+	b.started = b.now()
+	b.ends = time.Time{}
+	if cfg.DurationMs > 0 {
+		b.ends = b.started.Add(time.Duration(cfg.DurationMs) * time.Millisecond)
+	}
+	started := b.started
+	b.status = Status{Active: true, Config: &cfg, StartedAt: &started}
+	b.pending = make(map[string]int)
+	return nil
+}
+
+// Stop ends the burst early. Retries are still counted.
+func (b *Burst) Stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = nil
+	b.status.Active = false
+}
+
+// Reset ends the burst and forgets its counters.
+func (b *Burst) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cfg = nil
+	b.status = Status{}
+	b.pending = make(map[string]int)
+}
+
+// Status returns the state of the current or last burst.
+func (b *Burst) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire()
+	return b.status
+}
+
+// Fail reports whether a call should fail, returning the burst's config
+// if so. The call is counted against the burst.
+func (b *Burst) Fail(method string, params map[string]interface{}) (*Config, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.expire()
+	if b.cfg == nil || (len(b.methods) > 0 && !b.methods[method]) {
+		return nil, false
+	}
+
+	cfg := b.cfg
+	b.status.Failed++
+	b.pending[fingerprint(method, params)]++
+	if cfg.Requests > 0 && b.status.Failed >= cfg.Requests {
+		b.cfg = nil
+		b.status.Active = false
+	}
+	return cfg, true
+}
+
+// Succeeded notes a successful call, counting it as a retry if an earlier
+// call with the same parameters failed during the burst.
+func (b *Burst) Succeeded(method string, params map[string]interface{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.pending) == 0 {
+		return
+	}
+	key := fingerprint(method, params)
+	if b.pending[key] == 0 {
+		return
+	}
+	b.pending[key]--
+	if b.pending[key] == 0 {
+		delete(b.pending, key)
+	}
+	b.status.Retried++
+	if b.status.Config != nil && b.status.Config.Applied {
+		b.status.Duplicates++
+	}
+}
+
+// expire ends a burst whose duration has passed. Callers must hold b.mu.
+func (b *Burst) expire() {
+	if b.cfg != nil && !b.ends.IsZero() && !b.now().Before(b.ends) {
+		b.cfg = nil
+		b.status.Active = false
+	}
+}
+
+// fingerprint identifies a call by its method and parameters. Map keys
+// are marshaled in order, so equal parameters give equal fingerprints.
+func fingerprint(method string, params map[string]interface{}) string {
+	data, _ := json.Marshal(params)
+	return method + " " + string(data)
+}
@@ -0,0 +1,64 @@
+// internal/outage/outage_test.go
+package outage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBurstRequests(t *testing.T) {
+	b := NewBurst(nil)
+	if _, failing := b.Fail("sendMessage", nil); failing {
+		t.Fatal("expected no failures before a burst")
+	}
+	if err := b.Start(Config{}); err == nil {
+		t.Error("expected a burst without an end to be rejected")
+	}
+
+	if err := b.Start(Config{Requests: 2, Methods: []string{"sendMessage"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, failing := b.Fail("getMe", nil); failing {
+		t.Error("expected other methods to be unaffected")
+	}
+	params := map[string]interface{}{"chat_id": 1, "text": "hi"}
+	cfg, failing := b.Fail("sendMessage", params)
+	if !failing || cfg.ErrorCode != DefaultErrorCode || cfg.Description != DefaultDescription {
+		t.Fatalf("expected the default error, got %+v", cfg)
+	}
+	b.Fail("sendMessage", params)
+	if _, failing := b.Fail("sendMessage", params); failing {
+		t.Error("expected the burst to end after 2 failures")
+	}
+
+	b.Succeeded("sendMessage", map[string]interface{}{"chat_id": 2, "text": "hi"})
+	b.Succeeded("sendMessage", params)
+	st := b.Status()
+	if st.Active || st.Failed != 2 || st.Retried != 1 || st.Duplicates != 0 {
+		t.Errorf("unexpected status: %+v", st)
+	}
+}
+
+func TestBurstDuration(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := NewBurst(func() time.Time { return now })
+	b.Start(Config{DurationMs: 1000, ErrorCode: 502, Description: "Bad Gateway", Applied: true})
+
+	if cfg, failing := b.Fail("sendMessage", nil); !failing || cfg.ErrorCode != 502 || !cfg.Applied {
+		t.Fatalf("expected an applied 502, got %+v", cfg)
+	}
+	now = now.Add(time.Second)
+	if _, failing := b.Fail("sendMessage", nil); failing {
+		t.Error("expected the burst to end after its duration")
+	}
+
+	b.Succeeded("sendMessage", nil)
+	if st := b.Status(); st.Duplicates != 1 {
+		t.Errorf("expected the retry of an applied call to be a duplicate, got %+v", st)
+	}
+
+	b.Reset()
+	if st := b.Status(); st.Config != nil || st.Failed != 0 {
+		t.Errorf("expected reset to clear the status, got %+v", st)
+	}
+}
@@ -0,0 +1,272 @@
+// Package persona provides auto-responders that act as the user on the
+// other side of a chat. A persona watches the messages a bot sends to its
+// chat and answers with updates, turning the mock into a lightweight
+// conversation partner for demos and smoke tests.
+package persona
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/faker"
+)
+
+// Rule actions.
+const (
+	// ActionEcho replies with the text or caption of the bot's message.
+	ActionEcho = "echo"
+	// ActionReply replies with a fixed text.
+	ActionReply = "reply"
+	// ActionPressButton presses a callback button of the message's inline
+	// keyboard.
+	ActionPressButton = "press_button"
+	// ActionSendPhoto replies with a photo.
+	ActionSendPhoto = "send_photo"
+)
+
+// Rule is a single reaction of a persona to messages from the bot.
+type Rule struct {
+	Action string `json:"action"`
+	// Text is the reply sent by ActionReply, or the caption of the photo
+	// sent by ActionSendPhoto.
+	Text string `json:"text,omitempty"`
+	// Button is the 1-based index of the callback button pressed by
+	// ActionPressButton, counting row by row.
+	Button int `json:"button,omitempty"`
+	// Every makes the rule fire only on every Nth bot message (default 1).
+	Every int `json:"every,omitempty"`
+}
+
+// Persona is an auto-responder attached to a chat.
+type Persona struct {
+	ChatID string `json:"chat_id"`
+	// User is the sender of the persona's updates. A user is generated
+	// on first use if it is empty.
+	User  map[string]interface{} `json:"user,omitempty"`
+	Rules []Rule                 `json:"rules"`
+
+	// BotMessages counts the messages the bot sent to the chat.
+	BotMessages int `json:"bot_messages"`
+	// Responses counts the updates the persona produced.
+	Responses int `json:"responses"`
+}
+
+// Validate checks the persona's rules.
+func (p *Persona) Validate() error {
+	if p.ChatID == "" {
+		return fmt.Errorf("chat_id is required")
+	}
+	if len(p.Rules) == 0 {
+		return fmt.Errorf("at least one rule is required")
+	}
+	for i, rule := range p.Rules {
+		switch rule.Action {
+		case ActionEcho, ActionSendPhoto:
+		case ActionReply:
+			if rule.Text == "" {
+				return fmt.Errorf("rule %d: reply needs a text", i+1)
+			}
+		case ActionPressButton:
+			if rule.Button < 1 {
+				return fmt.Errorf("rule %d: press_button needs a button of at least 1", i+1)
+			}
+		default:
+			return fmt.Errorf("rule %d: unknown action %q", i+1, rule.Action)
+		}
+		if rule.Every < 0 {
+			return fmt.Errorf("rule %d: every must not be negative", i+1)
+		}
+	}
+	return nil
+}
+
+// Registry holds the personas of a session, keyed by chat ID.
+type Registry struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	personas map[string]*Persona
+}
+
+// NewRegistry creates an empty registry reading the time for generated
+// messages from now. If now is nil, the system clock is used.
+func NewRegistry(now func() time.Time) *Registry {
+	if now == nil {
+		now = time.Now
+	}
+	return &Registry{
+		now:      now,
+		personas: make(map[string]*Persona),
+	}
+}
+
+// Set attaches a persona to its chat, replacing any existing one. The
+// counters start from zero.
+func (r *Registry) Set(p Persona) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	p.BotMessages = 0
+	p.Responses = 0
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.personas[p.ChatID] = &p
+	return nil
+}
+
+// Get returns the persona attached to a chat.
+func (r *Registry) Get(chatID string) (Persona, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.personas[chatID]
+	if !ok {
+		return Persona{}, false
+	}
+	return *p, true
+}
+
+// List returns all personas ordered by chat ID.
+func (r *Registry) List() []Persona {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	result := make([]Persona, 0, len(r.personas))
+	for _, p := range r.personas {
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChatID < result[j].ChatID })
+	return result
+}
+
+// Delete detaches the persona from a chat. Returns true if one was removed.
+func (r *Registry) Delete(chatID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.personas[chatID]; ok {
+		delete(r.personas, chatID)
+		return true
+	}
+	return false
+}
+
+// Clear removes all personas.
+func (r *Registry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.personas = make(map[string]*Persona)
+}
+
+// Respond returns the updates the persona of a chat sends in reaction to
+// msg, a message the bot just sent there. Update IDs are left for the
+// update queue to assign.
+func (r *Registry) Respond(chatID string, msg map[string]interface{}, f *faker.Faker) []map[string]interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	p, ok := r.personas[chatID]
+	if !ok {
+		return nil
+	}
+	p.BotMessages++
+	if p.User == nil {
+		user, _ := f.Generate("User", nil).(map[string]interface{})
+		p.User = user
+	}
+
+	var result []map[string]interface{}
+	for _, rule := range p.Rules {
+		every := rule.Every
+		if every < 1 {
+			every = 1
+		}
+		if p.BotMessages%every != 0 {
+			continue
+		}
+		if update := r.apply(p, rule, msg, f); update != nil {
+			result = append(result, update)
+		}
+	}
+	p.Responses += len(result)
+	return result
+}
+
+// apply builds the update for one rule, or returns nil if the rule doesn't
+// apply to msg.
+func (r *Registry) apply(p *Persona, rule Rule, msg map[string]interface{}, f *faker.Faker) map[string]interface{} {
+	switch rule.Action {
+	case ActionEcho:
+		text, _ := msg["text"].(string)
+		if text == "" {
+			text, _ = msg["caption"].(string)
+		}
+		if text == "" {
+			return nil
+		}
+		reply := r.newMessage(p, msg, f)
+		reply["text"] = text
+		return map[string]interface{}{"message": reply}
+
+	case ActionReply:
+		reply := r.newMessage(p, msg, f)
+		reply["text"] = rule.Text
+		return map[string]interface{}{"message": reply}
+
+	case ActionSendPhoto:
+		reply := r.newMessage(p, msg, f)
+		reply["photo"] = f.Generate("Array of PhotoSize", nil)
+		if rule.Text != "" {
+			reply["caption"] = rule.Text
+		}
+		return map[string]interface{}{"message": reply}
+
+	case ActionPressButton:
+		data, ok := callbackButton(msg, rule.Button)
+		if !ok {
+			return nil
+		}
+		return map[string]interface{}{
+			"callback_query": map[string]interface{}{
+				"id":            strconv.FormatInt(f.RandomInt64(1e17, 1e18), 10),
+				"from":          p.User,
+				"message":       msg,
+				"chat_instance": strconv.FormatInt(f.RandomInt64(1e17, 1e18), 10),
+				"data":          data,
+			},
+		}
+	}
+	return nil
+}
+
+// newMessage creates a message from the persona's user in the chat of msg.
+func (r *Registry) newMessage(p *Persona, msg map[string]interface{}, f *faker.Faker) map[string]interface{} {
+	return map[string]interface{}{
+		"message_id": f.NextMessageID(),
+		"from":       p.User,
+		"chat":       msg["chat"],
+		"date":       r.now().Unix(),
+	}
+}
+
+// callbackButton returns the callback_data of the nth (1-based) callback
+// button in the message's inline keyboard. URL, login, and other buttons
+// that don't produce callback queries are skipped.
+func callbackButton(msg map[string]interface{}, n int) (string, bool) {
+	markup, _ := msg["reply_markup"].(map[string]interface{})
+	rows, _ := markup["inline_keyboard"].([]interface{})
+	for _, row := range rows {
+		buttons, _ := row.([]interface{})
+		for _, b := range buttons {
+			button, _ := b.(map[string]interface{})
+			data, ok := button["callback_data"].(string)
+			if !ok {
+				continue
+			}
+			if n--; n == 0 {
+				return data, true
+			}
+		}
+	}
+	return "", false
+}
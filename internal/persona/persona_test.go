@@ -0,0 +1,135 @@
+// internal/persona/persona_test.go
+package persona
+
+import (
+	"testing"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/faker"
+)
+
+func botMessage(text string) map[string]interface{} {
+	return map[string]interface{}{
+		"message_id": int64(1),
+		"chat":       map[string]interface{}{"id": int64(42), "type": "private"},
+		"text":       text,
+	}
+}
+
+func TestRegistry_SetValidates(t *testing.T) {
+	r := NewRegistry(nil)
+
+	tests := []struct {
+		name    string
+		persona Persona
+	}{
+		{"no chat", Persona{Rules: []Rule{{Action: ActionEcho}}}},
+		{"no rules", Persona{ChatID: "42"}},
+		{"unknown action", Persona{ChatID: "42", Rules: []Rule{{Action: "dance"}}}},
+		{"reply without text", Persona{ChatID: "42", Rules: []Rule{{Action: ActionReply}}}},
+		{"press without button", Persona{ChatID: "42", Rules: []Rule{{Action: ActionPressButton}}}},
+		{"negative every", Persona{ChatID: "42", Rules: []Rule{{Action: ActionEcho, Every: -1}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Set(tt.persona); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+	if len(r.List()) != 0 {
+		t.Error("invalid personas should not be stored")
+	}
+}
+
+func TestRegistry_Echo(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewRegistry(func() time.Time { return now })
+	user := map[string]interface{}{"id": int64(7), "is_bot": false, "first_name": "Ann"}
+	r.Set(Persona{ChatID: "42", User: user, Rules: []Rule{{Action: ActionEcho}}})
+
+	updates := r.Respond("42", botMessage("hello"), faker.New(faker.Config{Seed: 1}))
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(updates))
+	}
+	msg := updates[0]["message"].(map[string]interface{})
+	if msg["text"] != "hello" {
+		t.Errorf("expected echoed text, got %v", msg["text"])
+	}
+	if msg["from"].(map[string]interface{})["id"] != int64(7) {
+		t.Errorf("expected message from the persona's user, got %v", msg["from"])
+	}
+	if msg["chat"].(map[string]interface{})["id"] != int64(42) {
+		t.Errorf("expected message in the bot's chat, got %v", msg["chat"])
+	}
+	if msg["date"] != now.Unix() {
+		t.Errorf("expected date from the clock, got %v", msg["date"])
+	}
+
+	if updates := r.Respond("99", botMessage("hello"), faker.New(faker.Config{Seed: 1})); updates != nil {
+		t.Errorf("expected no updates for a chat without persona, got %v", updates)
+	}
+}
+
+func TestRegistry_Every(t *testing.T) {
+	r := NewRegistry(nil)
+	r.Set(Persona{ChatID: "42", Rules: []Rule{{Action: ActionSendPhoto, Text: "look", Every: 3}}})
+	f := faker.New(faker.Config{Seed: 1})
+
+	var got []int
+	for i := 1; i <= 6; i++ {
+		if updates := r.Respond("42", botMessage("hi"), f); len(updates) > 0 {
+			msg := updates[0]["message"].(map[string]interface{})
+			if _, ok := msg["photo"].([]interface{}); !ok || msg["caption"] != "look" {
+				t.Errorf("expected photo with caption, got %v", msg)
+			}
+			got = append(got, i)
+		}
+	}
+	if len(got) != 2 || got[0] != 3 || got[1] != 6 {
+		t.Errorf("expected photos after messages 3 and 6, got %v", got)
+	}
+
+	p, _ := r.Get("42")
+	if p.BotMessages != 6 || p.Responses != 2 {
+		t.Errorf("expected 6 bot messages and 2 responses, got %d and %d", p.BotMessages, p.Responses)
+	}
+	if p.User == nil {
+		t.Error("expected a user to be generated")
+	}
+}
+
+func TestRegistry_PressButton(t *testing.T) {
+	r := NewRegistry(nil)
+	r.Set(Persona{ChatID: "42", Rules: []Rule{{Action: ActionPressButton, Button: 2}}})
+	f := faker.New(faker.Config{Seed: 1})
+
+	msg := botMessage("pick one")
+	msg["reply_markup"] = map[string]interface{}{
+		"inline_keyboard": []interface{}{
+			[]interface{}{
+				map[string]interface{}{"text": "Site", "url": "https://example.com"},
+				map[string]interface{}{"text": "Yes", "callback_data": "yes"},
+			},
+			[]interface{}{
+				map[string]interface{}{"text": "No", "callback_data": "no"},
+			},
+		},
+	}
+
+	updates := r.Respond("42", msg, f)
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(updates))
+	}
+	query := updates[0]["callback_query"].(map[string]interface{})
+	if query["data"] != "no" {
+		t.Errorf("expected second callback button to be pressed, got %v", query["data"])
+	}
+	if query["message"].(map[string]interface{})["text"] != "pick one" {
+		t.Errorf("expected the bot's message in the callback query, got %v", query["message"])
+	}
+
+	if updates := r.Respond("42", botMessage("no keyboard"), f); len(updates) != 0 {
+		t.Errorf("expected no update without a keyboard, got %v", updates)
+	}
+}
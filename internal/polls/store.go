@@ -0,0 +1,182 @@
+// Package polls keeps the polls bots send, so that stopPoll returns the
+// poll that was sent with the votes it got, and votes from fake users can
+// be simulated.
+package polls
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// Errors returned by Vote.
+var (
+	ErrUnknownPoll    = errors.New("poll not found")
+	ErrClosed         = errors.New("poll is closed")
+	ErrInvalidOption  = errors.New("invalid option")
+	ErrSingleAnswer   = errors.New("poll allows only one answer")
+	ErrCantRetract    = errors.New("quiz answers can't be retracted")
+	ErrAlreadyStopped = errors.New("poll has already been closed")
+)
+
+// Entry is a poll a bot sent: its Poll object, the message it was sent
+// in, and the options each user voted for.
+type Entry struct {
+	ChatID    string                 `json:"chat_id"`
+	MessageID int64                  `json:"message_id"`
+	Poll      map[string]interface{} `json:"poll"`
+	Votes     map[int64][]int        `json:"votes,omitempty"`
+}
+
+// Store holds the polls bots sent, keyed by poll ID. Values are copied on
+// the way in and out.
+type Store struct {
+	mu    sync.Mutex
+	polls map[string]*Entry
+}
+
+// NewStore creates an empty poll store.
+func NewStore() *Store {
+	return &Store{polls: make(map[string]*Entry)}
+}
+
+// Add stores a poll sent in a message. The poll needs an id.
+func (s *Store) Add(chatID string, messageID int64, poll map[string]interface{}) {
+	id, _ := poll["id"].(string)
+	if id == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polls[id] = &Entry{ChatID: chatID, MessageID: messageID, Poll: jsoncopy.Map(poll), Votes: map[int64][]int{}}
+}
+
+// Get returns a poll by ID.
+func (s *Store) Get(id string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.polls[id]
+	if !ok {
+		return Entry{}, false
+	}
+	return copyEntry(e), true
+}
+
+// Stop closes the poll sent in a message and returns it. It returns false
+// if no poll is known for the message.
+func (s *Store) Stop(chatID string, messageID int64) (map[string]interface{}, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.polls {
+		if e.ChatID != chatID || e.MessageID != messageID {
+			continue
+		}
+		if e.Poll["is_closed"] == true {
+			return nil, true, ErrAlreadyStopped
+		}
+		e.Poll["is_closed"] = true
+		return jsoncopy.Map(e.Poll), true, nil
+	}
+	return nil, false, nil
+}
+
+// Vote replaces the options a user voted for in a poll, as a PollAnswer
+// does, and returns the poll with the new results. No options retract the
+// vote, which quizzes don't allow.
+func (s *Store) Vote(id string, userID int64, options []int) (map[string]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.polls[id]
+	if !ok {
+		return nil, ErrUnknownPoll
+	}
+	if e.Poll["is_closed"] == true {
+		return nil, ErrClosed
+	}
+	pollOptions, _ := e.Poll["options"].([]interface{})
+	for _, o := range options {
+		if o < 0 || o >= len(pollOptions) {
+			return nil, ErrInvalidOption
+		}
+	}
+	if len(options) > 1 && e.Poll["allows_multiple_answers"] != true {
+		return nil, ErrSingleAnswer
+	}
+	if e.Poll["type"] == "quiz" && len(e.Votes[userID]) > 0 {
+		return nil, ErrCantRetract
+	}
+
+	if len(options) == 0 {
+		delete(e.Votes, userID)
+	} else {
+		e.Votes[userID] = append([]int(nil), options...)
+	}
+	e.tally()
+	return jsoncopy.Map(e.Poll), nil
+}
+
+// tally counts the votes into the poll's results.
+func (e *Entry) tally() {
+	options, _ := e.Poll["options"].([]interface{})
+	counts := make([]int, len(options))
+	for _, voted := range e.Votes {
+		for _, o := range voted {
+			counts[o]++
+		}
+	}
+	for i, o := range options {
+		if option, ok := o.(map[string]interface{}); ok {
+			option["voter_count"] = counts[i]
+		}
+	}
+	e.Poll["total_voter_count"] = len(e.Votes)
+}
+
+// List returns the known polls, ordered by chat and message.
+func (s *Store) List() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Entry, 0, len(s.polls))
+	for _, e := range s.polls {
+		list = append(list, copyEntry(e))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ChatID != list[j].ChatID {
+			return list[i].ChatID < list[j].ChatID
+		}
+		return list[i].MessageID < list[j].MessageID
+	})
+	return list
+}
+
+// Restore replaces the store contents with the given polls.
+func (s *Store) Restore(list []Entry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polls = make(map[string]*Entry, len(list))
+	for _, e := range list {
+		id, _ := e.Poll["id"].(string)
+		if id == "" {
+			continue
+		}
+		e := copyEntry(&e)
+		s.polls[id] = &e
+	}
+}
+
+// Clear forgets all polls.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.polls = make(map[string]*Entry)
+}
+
+func copyEntry(e *Entry) Entry {
+	c := Entry{ChatID: e.ChatID, MessageID: e.MessageID, Poll: jsoncopy.Map(e.Poll), Votes: make(map[int64][]int, len(e.Votes))}
+	for user, options := range e.Votes {
+		c.Votes[user] = append([]int(nil), options...)
+	}
+	return c
+}
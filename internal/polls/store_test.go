@@ -0,0 +1,61 @@
+// internal/polls/store_test.go
+package polls
+
+import "testing"
+
+func newPoll(id string, extra map[string]interface{}) map[string]interface{} {
+	poll := map[string]interface{}{
+		"id":      id,
+		"options": []interface{}{map[string]interface{}{"text": "Yes", "voter_count": 0}, map[string]interface{}{"text": "No", "voter_count": 0}},
+		"type":    "regular",
+	}
+	for k, v := range extra {
+		poll[k] = v
+	}
+	return poll
+}
+
+func TestStore_VoteAndStop(t *testing.T) {
+	s := NewStore()
+	s.Add("-100", 10, newPoll("p1", nil))
+
+	s.Vote("p1", 1, []int{0})
+	s.Vote("p1", 2, []int{0})
+	poll, err := s.Vote("p1", 2, []int{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := poll["options"].([]interface{})
+	if poll["total_voter_count"] != float64(2) || options[0].(map[string]interface{})["voter_count"] != float64(1) || options[1].(map[string]interface{})["voter_count"] != float64(1) {
+		t.Errorf("expected a changed vote to move, got %v", poll)
+	}
+	if _, err := s.Vote("p1", 3, []int{0, 1}); err != ErrSingleAnswer {
+		t.Errorf("expected several answers to be refused, got %v", err)
+	}
+	if _, err := s.Vote("p1", 3, []int{5}); err != ErrInvalidOption {
+		t.Errorf("expected an unknown option to be refused, got %v", err)
+	}
+
+	stopped, known, err := s.Stop("-100", 10)
+	if !known || err != nil || stopped["is_closed"] != true || stopped["total_voter_count"] != float64(2) {
+		t.Errorf("expected the stopped poll with its votes, got %v %v %v", stopped, known, err)
+	}
+	if _, _, err := s.Stop("-100", 10); err != ErrAlreadyStopped {
+		t.Errorf("expected stopping twice to fail, got %v", err)
+	}
+	if _, err := s.Vote("p1", 4, []int{0}); err != ErrClosed {
+		t.Errorf("expected votes in a closed poll to be refused, got %v", err)
+	}
+}
+
+func TestStore_Quiz(t *testing.T) {
+	s := NewStore()
+	s.Add("-100", 10, newPoll("q1", map[string]interface{}{"type": "quiz"}))
+	s.Vote("q1", 1, []int{1})
+	if _, err := s.Vote("q1", 1, nil); err != ErrCantRetract {
+		t.Errorf("expected quiz answers to be final, got %v", err)
+	}
+	if _, err := s.Vote("missing", 1, []int{0}); err != ErrUnknownPoll {
+		t.Errorf("expected unknown polls to be reported, got %v", err)
+	}
+}
@@ -4,8 +4,13 @@ package scenario
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
+
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
 )
 
 // Scenario represents a single simulation scenario.
@@ -13,11 +18,12 @@ import (
 // Scenarios can return either error responses (Response) or success data overrides (ResponseData).
 type Scenario struct {
 	ID           string                 `json:"id"`
-	Method       string                 `json:"method"`                   // Method to match, or "*" for any method
-	Match        map[string]interface{} `json:"match,omitempty"`          // Parameters to match
-	Times        int                    `json:"times"`                    // Number of times to trigger (0 = unlimited)
-	Response     *ErrorResponse         `json:"response,omitempty"`       // Error response to return
-	ResponseData map[string]interface{} `json:"response_data,omitempty"`  // Success response data overrides
+	Method       string                 `json:"method"`                  // Method to match, or "*" for any method
+	Match        map[string]interface{} `json:"match,omitempty"`         // Parameters to match
+	Times        int                    `json:"times"`                   // Number of times to trigger (0 = unlimited)
+	Response     *ErrorResponse         `json:"response,omitempty"`      // Error response to return
+	ResponseData map[string]interface{} `json:"response_data,omitempty"` // Success response data overrides
+	Script       string                 `json:"script,omitempty"`        // Lua script computing the response
 
 	used int32 // atomic counter for number of times this scenario has been used
 }
@@ -27,17 +33,18 @@ func (s *Scenario) IsError() bool {
 	return s.Response != nil
 }
 
+// HasScript returns true if this scenario computes its response with a script.
+func (s *Scenario) HasScript() bool {
+	return s.Script != ""
+}
+
 // HasResponseData returns true if this scenario has success response data overrides.
 func (s *Scenario) HasResponseData() bool {
 	return s.ResponseData != nil && len(s.ResponseData) > 0
 }
 
 // ErrorResponse represents a Telegram API error response.
-type ErrorResponse struct {
-	ErrorCode   int    `json:"error_code"`
-	Description string `json:"description"`
-	RetryAfter  int    `json:"retry_after,omitempty"` // For rate limit errors
-}
+type ErrorResponse = tgerrors.Error
 
 // Matches checks if this scenario matches the given method and parameters.
 // A scenario matches if:
@@ -66,11 +73,10 @@ func (s *Scenario) Matches(method string, params map[string]interface{}) bool {
 // Use increments the usage counter and returns true if the scenario is still valid.
 // For unlimited scenarios (Times=0), it always returns true.
 func (s *Scenario) Use() bool {
+	used := atomic.AddInt32(&s.used, 1)
 	if s.Times == 0 {
 		return true // Unlimited
 	}
-
-	used := atomic.AddInt32(&s.used, 1)
 	return int(used) <= s.Times
 }
 
@@ -83,12 +89,51 @@ func (s *Scenario) Exhausted() bool {
 	return int(atomic.LoadInt32(&s.used)) >= s.Times
 }
 
+// Used returns the number of times this scenario has been used.
+func (s *Scenario) Used() int {
+	return int(atomic.LoadInt32(&s.used))
+}
+
+// SetUsed overwrites the usage counter, e.g. when restoring a snapshot.
+func (s *Scenario) SetUsed(n int) {
+	atomic.StoreInt32(&s.used, int32(n))
+}
+
 // Engine manages a collection of scenarios.
 // It provides thread-safe operations for adding, finding, listing, and removing scenarios.
+// Every change to the scenario set bumps its revision and is kept in a
+// history, so recorded requests can be traced back to the scenarios that
+// were active when they were handled.
 type Engine struct {
 	mu        sync.RWMutex
 	scenarios []*Scenario
 	idCounter int64
+	revision  int64
+	history   []Change
+}
+
+// MaxHistory is the number of changes an engine keeps. Older changes are
+// dropped first.
+const MaxHistory = 1000
+
+// Change actions.
+const (
+	ActionAdd     = "add"
+	ActionUpdate  = "update"
+	ActionRemove  = "remove"
+	ActionClear   = "clear"
+	ActionRestore = "restore"
+)
+
+// Change is one change to the scenario set. Revision is the revision the
+// change produced.
+type Change struct {
+	Revision   int64     `json:"revision"`
+	Timestamp  time.Time `json:"timestamp"`
+	Action     string    `json:"action"`
+	ScenarioID string    `json:"scenario_id,omitempty"`
+	// Scenario is the added or updated scenario as it was at the time.
+	Scenario *Scenario `json:"scenario,omitempty"`
 }
 
 // NewEngine creates a new scenario engine.
@@ -110,9 +155,74 @@ func (e *Engine) Add(s *Scenario) string {
 	}
 
 	e.scenarios = append(e.scenarios, s)
+	e.logChange(ActionAdd, s.ID, s)
 	return s.ID
 }
 
+// Update replaces the scenario with the given ID, keeping its position and
+// resetting its usage counter.
+// Returns false if no scenario has that ID.
+func (e *Engine) Update(id string, s *Scenario) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for i, old := range e.scenarios {
+		if old.ID == id {
+			s.ID = id
+			s.SetUsed(0)
+			e.scenarios[i] = s
+			e.logChange(ActionUpdate, id, s)
+			return true
+		}
+	}
+	return false
+}
+
+// logChange bumps the revision and appends a change to the history. The
+// scenario is copied so that later use doesn't alter the history. Callers
+// must hold e.mu.
+func (e *Engine) logChange(action, id string, s *Scenario) {
+	e.revision++
+	c := Change{Revision: e.revision, Timestamp: time.Now(), Action: action, ScenarioID: id}
+	if s != nil {
+		c.Scenario = &Scenario{
+			ID:           s.ID,
+			Method:       s.Method,
+			Match:        s.Match,
+			Times:        s.Times,
+			Response:     s.Response,
+			ResponseData: s.ResponseData,
+		}
+	}
+	e.history = append(e.history, c)
+	if len(e.history) > MaxHistory {
+		e.history = append([]Change(nil), e.history[len(e.history)-MaxHistory:]...)
+	}
+}
+
+// Revision returns the current revision of the scenario set. It starts at
+// zero and grows with every change.
+func (e *Engine) Revision() int64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.revision
+}
+
+// History returns the changes that produced revisions after from, up to
+// and including to (0 = latest), oldest first.
+func (e *Engine) History(from, to int64) []Change {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	result := make([]Change, 0)
+	for _, c := range e.history {
+		if c.Revision > from && (to == 0 || c.Revision <= to) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 // generateID creates a unique scenario ID.
 func (e *Engine) generateID() string {
 	id := atomic.AddInt64(&e.idCounter, 1)
@@ -152,6 +262,7 @@ func (e *Engine) Remove(id string) bool {
 	for i, s := range e.scenarios {
 		if s.ID == id {
 			e.scenarios = append(e.scenarios[:i], e.scenarios[i+1:]...)
+			e.logChange(ActionRemove, id, nil)
 			return true
 		}
 	}
@@ -163,4 +274,38 @@ func (e *Engine) Clear() {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.scenarios = make([]*Scenario, 0)
+	e.logChange(ActionClear, "", nil)
+}
+
+// Reset removes all scenarios and forgets the history, starting again from
+// revision zero.
+func (e *Engine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.scenarios = make([]*Scenario, 0)
+	e.revision = 0
+	e.history = nil
+}
+
+// Restore replaces all scenarios with the given set, preserving their IDs.
+// The ID counter is advanced past any restored generated IDs so that
+// scenarios added afterwards never collide with restored ones.
+func (e *Engine) Restore(scenarios []*Scenario) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	for _, s := range scenarios {
+		if n, err := strconv.ParseInt(strings.TrimPrefix(s.ID, "scenario-"), 10, 64); err == nil && n > e.idCounter {
+			e.idCounter = n
+		}
+	}
+
+	e.scenarios = make([]*Scenario, 0, len(scenarios))
+	for _, s := range scenarios {
+		if s.ID == "" {
+			s.ID = e.generateID()
+		}
+		e.scenarios = append(e.scenarios, s)
+	}
+	e.logChange(ActionRestore, "", nil)
 }
@@ -1,7 +1,10 @@
 // internal/scenario/scenario_test.go
 package scenario
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestScenarioMatch(t *testing.T) {
 	s := &Scenario{
@@ -211,3 +214,85 @@ func TestEngineClear(t *testing.T) {
 		t.Errorf("expected 0 scenarios after clear, got %d", len(list))
 	}
 }
+
+func TestEngineRestore(t *testing.T) {
+	e := NewEngine()
+	e.Add(&Scenario{Method: "getMe"})
+
+	restored := &Scenario{ID: "scenario-7", Method: "sendMessage", Times: 3}
+	restored.SetUsed(2)
+	e.Restore([]*Scenario{restored})
+
+	list := e.List()
+	if len(list) != 1 || list[0].ID != "scenario-7" {
+		t.Fatalf("expected only restored scenario, got %v", list)
+	}
+	if list[0].Used() != 2 {
+		t.Errorf("Used() = %d, want 2", list[0].Used())
+	}
+
+	// New IDs must not collide with restored ones
+	if id := e.Add(&Scenario{Method: "getMe"}); id != "scenario-8" {
+		t.Errorf("next ID = %q, want scenario-8", id)
+	}
+}
+
+func TestEngineHistory(t *testing.T) {
+	e := NewEngine()
+	if e.Revision() != 0 {
+		t.Fatalf("expected revision 0, got %d", e.Revision())
+	}
+
+	s := &Scenario{ID: "s1", Method: "sendMessage", Times: 1}
+	e.Add(s)
+	s.Use()
+	if !e.Update("s1", &Scenario{Method: "getMe"}) {
+		t.Fatal("expected update to find s1")
+	}
+	if e.Update("missing", &Scenario{Method: "getMe"}) {
+		t.Error("expected update of unknown scenario to fail")
+	}
+	e.Remove("s1")
+	e.Clear()
+
+	if e.Revision() != 4 {
+		t.Errorf("expected revision 4, got %d", e.Revision())
+	}
+	changes := e.History(0, 0)
+	actions := make([]string, len(changes))
+	for i, c := range changes {
+		actions[i] = c.Action
+	}
+	want := []string{ActionAdd, ActionUpdate, ActionRemove, ActionClear}
+	if strings.Join(actions, ",") != strings.Join(want, ",") {
+		t.Errorf("actions = %v, want %v", actions, want)
+	}
+	if c := changes[1]; c.Revision != 2 || c.Scenario == nil || c.Scenario.Method != "getMe" || c.ScenarioID != "s1" {
+		t.Errorf("unexpected update change: %+v", c)
+	}
+	if c := changes[0]; c.Scenario.Method != "sendMessage" {
+		t.Errorf("expected history to keep the original scenario, got %+v", c.Scenario)
+	}
+
+	if between := e.History(1, 3); len(between) != 2 || between[0].Revision != 2 {
+		t.Errorf("expected revisions 2 and 3, got %+v", between)
+	}
+
+	e.Reset()
+	if e.Revision() != 0 || len(e.History(0, 0)) != 0 {
+		t.Error("expected reset to forget the history")
+	}
+}
+
+func TestEngineUpdateResetsUsage(t *testing.T) {
+	e := NewEngine()
+	e.Add(&Scenario{ID: "s1", Method: "sendMessage", Times: 1})
+	e.Find("sendMessage", nil).Use()
+	if e.Find("sendMessage", nil) != nil {
+		t.Fatal("expected scenario to be exhausted")
+	}
+	e.Update("s1", &Scenario{Method: "sendMessage", Times: 1})
+	if e.Find("sendMessage", nil) == nil {
+		t.Error("expected updated scenario to be usable again")
+	}
+}
@@ -0,0 +1,230 @@
+// Package script runs the Lua scripts attached to scenarios, for responses
+// that static response data can't express, like echoing text reversed or
+// failing every third call.
+//
+// A script sees these globals:
+//
+//	method  the Bot API method called
+//	params  the call's parameters
+//	call    how many times the scenario has matched, including this call
+//	result  the response tg-mock generated for the call
+//
+// It may change result in place, return a new result, or return
+// fail(code, description[, retry_after]) to answer with an error:
+//
+//	if call % 3 == 0 then
+//	  return fail(500, "Internal Server Error")
+//	end
+//	result.text = string.reverse(params.text)
+//
+// Scripts run sandboxed: only the base, string, table, and math libraries
+// are available, and a script is stopped after Timeout.
+package script
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	lua "github.com/yuin/gopher-lua"
+	"github.com/yuin/gopher-lua/parse"
+
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// Timeout is how long a script may run.
+const Timeout = time.Second
+
+// Input is what a script sees.
+type Input struct {
+	Method string
+	Params map[string]interface{}
+	Call   int
+	Result interface{}
+}
+
+// Output is the outcome of a script: either a result or an error.
+type Output struct {
+	Result interface{}
+	Error  *tgerrors.Error
+}
+
+// compiled caches compiled scripts by source, since the same scenario
+// script runs on every matching call.
+var compiled sync.Map
+
+// Compile checks a script for syntax errors.
+func Compile(source string) error {
+	_, err := compile(source)
+	return err
+}
+
+func compile(source string) (*lua.FunctionProto, error) {
+	if proto, ok := compiled.Load(source); ok {
+		return proto.(*lua.FunctionProto), nil
+	}
+	chunk, err := parse.Parse(strings.NewReader(source), "script")
+	if err != nil {
+		return nil, err
+	}
+	proto, err := lua.Compile(chunk, "script")
+	if err != nil {
+		return nil, err
+	}
+	compiled.Store(source, proto)
+	return proto, nil
+}
+
+// Run runs a script.
+func Run(source string, in Input) (Output, error) {
+	proto, err := compile(source)
+	if err != nil {
+		return Output{}, err
+	}
+
+	L := newState()
+	defer L.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	L.SetContext(ctx)
+
+	L.SetGlobal("method", lua.LString(in.Method))
+	L.SetGlobal("params", toLua(L, normalize(in.Params)))
+	L.SetGlobal("call", lua.LNumber(in.Call))
+	L.SetGlobal("result", toLua(L, normalize(in.Result)))
+
+	L.Push(L.NewFunctionFromProto(proto))
+	if err := L.PCall(0, 1, nil); err != nil {
+		return Output{}, err
+	}
+	ret := L.Get(-1)
+	L.Pop(1)
+
+	if ud, ok := ret.(*lua.LUserData); ok {
+		if e, ok := ud.Value.(*tgerrors.Error); ok {
+			return Output{Error: e}, nil
+		}
+	}
+	if ret == lua.LNil {
+		// Nothing returned: keep result, with any changes made to it
+		ret = L.GetGlobal("result")
+	}
+	return Output{Result: fromLua(ret)}, nil
+}
+
+// newState creates a sandboxed Lua state with the fail helper.
+func newState() *lua.LState {
+	L := lua.NewState(lua.Options{SkipOpenLibs: true})
+	for _, lib := range []struct {
+		name string
+		open lua.LGFunction
+	}{
+		{lua.BaseLibName, lua.OpenBase},
+		{lua.TabLibName, lua.OpenTable},
+		{lua.StringLibName, lua.OpenString},
+		{lua.MathLibName, lua.OpenMath},
+	} {
+		L.Push(L.NewFunction(lib.open))
+		L.Push(lua.LString(lib.name))
+		L.Call(1, 0)
+	}
+	// No access to the file system
+	L.SetGlobal("dofile", lua.LNil)
+	L.SetGlobal("loadfile", lua.LNil)
+
+	L.SetGlobal("fail", L.NewFunction(func(L *lua.LState) int {
+		e := &tgerrors.Error{
+			ErrorCode:   L.CheckInt(1),
+			Description: L.CheckString(2),
+			RetryAfter:  L.OptInt(3, 0),
+		}
+		ud := L.NewUserData()
+		ud.Value = e
+		L.Push(ud)
+		return 1
+	}))
+	return L
+}
+
+// normalize converts a value to the types decoding JSON gives, which are
+// the ones toLua handles.
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil
+	}
+	return out
+}
+
+func toLua(L *lua.LState, v interface{}) lua.LValue {
+	switch v := v.(type) {
+	case nil:
+		return lua.LNil
+	case bool:
+		return lua.LBool(v)
+	case float64:
+		return lua.LNumber(v)
+	case string:
+		return lua.LString(v)
+	case []interface{}:
+		t := L.CreateTable(len(v), 0)
+		for _, item := range v {
+			t.Append(toLua(L, item))
+		}
+		return t
+	case map[string]interface{}:
+		t := L.CreateTable(0, len(v))
+		for key, item := range v {
+			t.RawSetString(key, toLua(L, item))
+		}
+		return t
+	default:
+		return lua.LString(fmt.Sprint(v))
+	}
+}
+
+// fromLua converts a Lua value for JSON. Tables with keys 1..n become
+// arrays, other tables objects.
+func fromLua(v lua.LValue) interface{} {
+	switch v := v.(type) {
+	case lua.LBool:
+		return bool(v)
+	case lua.LNumber:
+		return float64(v)
+	case lua.LString:
+		return string(v)
+	case *lua.LTable:
+		n := v.Len()
+		keys := 0
+		v.ForEach(func(lua.LValue, lua.LValue) { keys++ })
+		if n > 0 && keys == n {
+			arr := make([]interface{}, 0, n)
+			for i := 1; i <= n; i++ {
+				arr = append(arr, fromLua(v.RawGetInt(i)))
+			}
+			return arr
+		}
+		obj := make(map[string]interface{}, keys)
+		v.ForEach(func(key, value lua.LValue) {
+			obj[tableKey(key)] = fromLua(value)
+		})
+		return obj
+	default:
+		return nil
+	}
+}
+
+func tableKey(key lua.LValue) string {
+	if n, ok := key.(lua.LNumber); ok {
+		return strconv.FormatFloat(float64(n), 'f', -1, 64)
+	}
+	return key.String()
+}
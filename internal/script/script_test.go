@@ -0,0 +1,83 @@
+// internal/script/script_test.go
+package script
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunEchoReversed(t *testing.T) {
+	out, err := Run(`result.text = string.reverse(params.text)`, Input{
+		Method: "sendMessage",
+		Params: map[string]interface{}{"chat_id": 1, "text": "hello"},
+		Call:   1,
+		Result: map[string]interface{}{"message_id": 7, "text": "generated"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{"message_id": float64(7), "text": "olleh"}
+	if !reflect.DeepEqual(out.Result, want) {
+		t.Errorf("expected %v, got %v", want, out.Result)
+	}
+}
+
+func TestRunFailEveryThirdCall(t *testing.T) {
+	src := `
+if call % 3 == 0 then
+  return fail(500, "Internal Server Error", 2)
+end
+return { method = method, call = call, tags = { "a", "b" } }
+`
+	for call := 1; call <= 3; call++ {
+		out, err := Run(src, Input{Method: "getMe", Call: call})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if call < 3 {
+			want := map[string]interface{}{"method": "getMe", "call": float64(call), "tags": []interface{}{"a", "b"}}
+			if out.Error != nil || !reflect.DeepEqual(out.Result, want) {
+				t.Errorf("call %d: expected %v, got %+v", call, want, out)
+			}
+			continue
+		}
+		if out.Error == nil || out.Error.ErrorCode != 500 || out.Error.RetryAfter != 2 {
+			t.Errorf("call 3: expected a 500 error, got %+v", out)
+		}
+	}
+}
+
+func TestRunSandbox(t *testing.T) {
+	for _, src := range []string{
+		`os.exit(1)`,
+		`io.write("x")`,
+		`dofile("/etc/passwd")`,
+		`require("os")`,
+	} {
+		if _, err := Run(src, Input{}); err == nil {
+			t.Errorf("expected %q to fail", src)
+		}
+	}
+}
+
+func TestRunTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := Run(`while true do end`, Input{})
+	if err == nil {
+		t.Fatal("expected the script to be stopped")
+	}
+	if elapsed := time.Since(start); elapsed > 3*Timeout {
+		t.Errorf("script ran for %v", elapsed)
+	}
+}
+
+func TestCompile(t *testing.T) {
+	if err := Compile(`return result`); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := Compile(`return (`); err == nil || !strings.Contains(err.Error(), "script") {
+		t.Errorf("expected a syntax error, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+// internal/selffuzz/cases.go
+package selffuzz
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// fuzzCase is one set of parameters to send to a method.
+type fuzzCase struct {
+	name   string
+	params map[string]interface{}
+	// valid is set when the parameters satisfy the spec, so the mock
+	// must answer successfully.
+	valid bool
+	// missing is the required field left out, which the mock must reject
+	// with 400 Bad Request.
+	missing string
+}
+
+// skipFields are never sent because they would stall the run.
+var skipFields = map[string]map[string]bool{
+	"getUpdates": {"timeout": true},
+}
+
+// optionalInPractice are required by the spec but accepted when missing,
+// as they are by Telegram.
+var optionalInPractice = map[string]map[string]bool{
+	// Calling setWebhook without a URL removes the webhook
+	"setWebhook": {"url": true},
+}
+
+// cases builds the requests sent to a method: the required fields only,
+// every field, each required field left out, each field with a value of
+// the wrong type, and iterations random permutations.
+func cases(spec gen.MethodSpec, rng *rand.Rand, iterations int) []fuzzCase {
+	fields := make([]gen.FieldSpec, 0, len(spec.Fields))
+	for _, f := range spec.Fields {
+		if !skipFields[spec.Name][f.Name] {
+			fields = append(fields, f)
+		}
+	}
+
+	required := map[string]interface{}{}
+	all := map[string]interface{}{}
+	for _, f := range fields {
+		v := validField(f, 0)
+		all[f.Name] = v
+		if f.Required {
+			required[f.Name] = v
+		}
+	}
+
+	result := []fuzzCase{
+		{name: "required", params: required, valid: true},
+		{name: "all", params: all, valid: true},
+	}
+
+	for _, f := range fields {
+		if f.Required && !optionalInPractice[spec.Name][f.Name] {
+			result = append(result, fuzzCase{
+				name:    "missing:" + f.Name,
+				params:  without(required, f.Name),
+				missing: f.Name,
+			})
+		}
+	}
+
+	for _, f := range fields {
+		params := copyParams(required)
+		params[f.Name] = wrongValue(f.Types[0])
+		result = append(result, fuzzCase{name: "wrong-type:" + f.Name, params: params})
+	}
+
+	for i := 0; i < iterations; i++ {
+		params := map[string]interface{}{}
+		for _, f := range fields {
+			if f.Required || rng.Intn(2) == 0 {
+				params[f.Name] = randomValue(rng, f.Types[rng.Intn(len(f.Types))])
+			}
+		}
+		result = append(result, fuzzCase{name: "random", params: params})
+	}
+
+	return result
+}
+
+// ValidParams returns parameters of a method that satisfy the spec: its
+// required fields, set to plausible values. Arrays have as many items as
+// the spec requires.
+func ValidParams(spec gen.MethodSpec) map[string]interface{} {
+	params := map[string]interface{}{}
+	for _, f := range spec.Fields {
+		if !f.Required || skipFields[spec.Name][f.Name] {
+			continue
+		}
+		v := validField(f, 0)
+		if items, ok := v.([]interface{}); ok && f.Constraint != nil && f.Constraint.Unit == "items" {
+			for int64(len(items)) < f.Constraint.Min {
+				items = append(items, items[0])
+			}
+			v = items
+		}
+		params[f.Name] = v
+	}
+	return params
+}
+
+// maxObjectDepth is how deeply validValue fills in the required fields of
+// nested objects.
+const maxObjectDepth = 3
+
+// validValue returns a plausible value of a spec type.
+func validValue(typ string) interface{} {
+	return validValueAt(typ, 0)
+}
+
+// validField returns a valid value of a field. Enumerated fields take the
+// first value the spec lists, and parse modes, which it doesn't list, are
+// set to HTML.
+func validField(f gen.FieldSpec, depth int) interface{} {
+	typ := f.Types[0]
+	if c := f.Constraint; c != nil && len(c.Enum) > 0 {
+		switch typ {
+		case "String":
+			return c.Enum[0]
+		case "Integer":
+			n, _ := strconv.Atoi(c.Enum[0])
+			return n
+		}
+	}
+	if typ == "String" && (f.Name == "parse_mode" || strings.HasSuffix(f.Name, "_parse_mode")) {
+		return "HTML"
+	}
+	return validValueAt(typ, depth)
+}
+
+func validValueAt(typ string, depth int) interface{} {
+	if elem, ok := strings.CutPrefix(typ, "Array of "); ok {
+		return []interface{}{validValueAt(elem, depth)}
+	}
+	switch typ {
+	case "String", "InputFile":
+		return "fuzz"
+	case "Integer":
+		return 42
+	case "Float":
+		return 1.5
+	case "Boolean":
+		return true
+	default:
+		return objectValue(typ, depth)
+	}
+}
+
+// objectValue returns an object of a spec type with its required fields.
+// Union types are built as their first subtype, whose type field is named
+// after it, as in InputMediaAnimation's "animation".
+func objectValue(typ string, depth int) map[string]interface{} {
+	obj := map[string]interface{}{}
+	spec, ok := gen.Types[typ]
+	if !ok || depth >= maxObjectDepth {
+		return obj
+	}
+	if len(spec.Subtypes) > 0 {
+		sub := objectValue(spec.Subtypes[0], depth)
+		if _, ok := sub["type"]; ok {
+			sub["type"] = strings.ToLower(strings.TrimPrefix(spec.Subtypes[0], typ))
+		}
+		return sub
+	}
+	for _, f := range spec.Fields {
+		if f.Required {
+			obj[f.Name] = validField(f, depth+1)
+		}
+	}
+	return obj
+}
+
+// wrongValue returns a value whose JSON type differs from typ.
+func wrongValue(typ string) interface{} {
+	if strings.HasPrefix(typ, "Array of ") {
+		return "not-an-array"
+	}
+	switch typ {
+	case "String", "InputFile":
+		return map[string]interface{}{"unexpected": true}
+	case "Integer", "Float":
+		return "not-a-number"
+	case "Boolean":
+		return []interface{}{1, 2}
+	default:
+		return 12345
+	}
+}
+
+// oddValues are sent in place of valid values by random permutations.
+var oddValues = []interface{}{
+	nil,
+	"",
+	-1,
+	0,
+	int64(1) << 62,
+	1e308,
+	"🤖\u0000‮",
+	strings.Repeat("x", 10000),
+	[]interface{}{},
+	[]interface{}{nil, "x", 1},
+	map[string]interface{}{"type": "unknown", "nested": map[string]interface{}{"a": []interface{}{map[string]interface{}{}}}},
+}
+
+// randomValue returns either a valid value of typ, a value of the wrong
+// type, or one of the oddValues.
+func randomValue(rng *rand.Rand, typ string) interface{} {
+	switch rng.Intn(3) {
+	case 0:
+		return validValue(typ)
+	case 1:
+		return wrongValue(typ)
+	default:
+		return oddValues[rng.Intn(len(oddValues))]
+	}
+}
+
+func copyParams(params map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		c[k] = v
+	}
+	return c
+}
+
+func without(params map[string]interface{}, name string) map[string]interface{} {
+	c := copyParams(params)
+	delete(c, name)
+	return c
+}
@@ -0,0 +1,132 @@
+// internal/selffuzz/check.go
+package selffuzz
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// envelope is the Bot API response shape.
+type envelope struct {
+	OK          *bool           `json:"ok"`
+	Result      json.RawMessage `json:"result"`
+	ErrorCode   int             `json:"error_code"`
+	Description string          `json:"description"`
+}
+
+// stateErrors are 400 errors that valid requests get because of what
+// earlier requests did, such as repeating a change to a chat.
+var stateErrors = map[string]bool{
+	"Bad Request: CHAT_NOT_MODIFIED":                    true,
+	"Bad Request: TOPIC_NOT_MODIFIED":                   true,
+	"Bad Request: TOPIC_CLOSED":                         true,
+	"Bad Request: message thread not found":             true,
+	"Bad Request: BOT_SCORE_NOT_MODIFIED":               true,
+	"Bad Request: sticker set name is already occupied": true,
+	"Bad Request: message to forward not found":         true,
+	"Bad Request: message to delete not found":          true,
+	"Bad Request: message to be replied not found":      true,
+}
+
+// constraintErrors are 400 errors for constraints the spec only states in
+// its descriptions, such as the size of an album or the one action of an
+// inline keyboard button, which the values built from field types don't
+// satisfy.
+var constraintErrors = map[string]bool{
+	"Bad Request: media group must include 2-10 items":                                                   true,
+	"Bad Request: can't parse inline keyboard button: Text buttons are unallowed in the inline keyboard": true,
+}
+
+// check returns a description of what is wrong with a response, or ""
+// if it is acceptable for the case that was sent.
+func check(spec gen.MethodSpec, c fuzzCase, status int, body []byte) string {
+	if status >= 500 {
+		return fmt.Sprintf("server error %d", status)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return "response is not JSON: " + err.Error()
+	}
+	if env.OK == nil {
+		return `response has no "ok" field`
+	}
+
+	if !*env.OK {
+		if status == http.StatusOK {
+			return "error response sent with status 200"
+		}
+		if env.ErrorCode != status {
+			return fmt.Sprintf("error_code %d does not match status %d", env.ErrorCode, status)
+		}
+		if env.Description == "" {
+			return "error response has no description"
+		}
+		// The remaining errors depend on state, such as an active webhook
+		// or an unregistered token
+		if c.valid && status == http.StatusBadRequest && !stateErrors[env.Description] && !constraintErrors[env.Description] {
+			return "request that satisfies the spec was rejected: " + env.Description
+		}
+		return ""
+	}
+
+	if status != http.StatusOK {
+		return fmt.Sprintf("successful response sent with status %d", status)
+	}
+	if c.missing != "" {
+		return "request without required field " + c.missing + " succeeded"
+	}
+	if len(env.Result) == 0 {
+		return `successful response has no "result" field`
+	}
+
+	var result interface{}
+	if err := json.Unmarshal(env.Result, &result); err != nil {
+		return "result is not valid JSON: " + err.Error()
+	}
+	for _, typ := range spec.Returns {
+		if Matches(typ, result) {
+			return ""
+		}
+	}
+	return fmt.Sprintf("result does not match return type %s", strings.Join(spec.Returns, " or "))
+}
+
+// Matches reports whether a decoded JSON value has the shape of a spec
+// type. Objects are only checked to be objects.
+func Matches(typ string, v interface{}) bool {
+	if elem, ok := strings.CutPrefix(typ, "Array of "); ok {
+		items, ok := v.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, item := range items {
+			if !Matches(elem, item) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch typ {
+	case "Boolean", "True":
+		_, ok := v.(bool)
+		return ok
+	case "Integer":
+		n, ok := v.(float64)
+		return ok && n == float64(int64(n))
+	case "Float":
+		_, ok := v.(float64)
+		return ok
+	case "String":
+		_, ok := v.(string)
+		return ok
+	default:
+		_, ok := v.(map[string]interface{})
+		return ok
+	}
+}
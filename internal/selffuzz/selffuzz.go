@@ -0,0 +1,247 @@
+// Package selffuzz sends generated requests for every Bot API method to a
+// running tg-mock instance and reports responses that indicate a bug in
+// the mock itself: server errors, dropped connections, malformed
+// envelopes, and results that don't match the method's return type.
+package selffuzz
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// DefaultToken is the bot token used when none is configured.
+const DefaultToken = "1000000000:self-fuzz"
+
+// DefaultSession is the session fuzz requests are sent to, keeping them
+// out of the default session's recorder and update queue.
+const DefaultSession = "self-fuzz"
+
+// Config controls a fuzz run.
+type Config struct {
+	// BaseURL is the address of the instance under test.
+	BaseURL string
+	// Token is the bot token used in request paths.
+	Token string
+	// Session is the session requests are made in. The session is deleted
+	// when the run is over.
+	Session string
+	// Methods limits the run to these methods (empty = all).
+	Methods []string
+	// Iterations is the number of random permutations sent per method.
+	Iterations int
+	// Seed makes the random permutations reproducible.
+	Seed int64
+	// ControlToken authenticates the cleanup calls to the control API on
+	// instances started with --control-token.
+	ControlToken string
+	// Client sends the requests. http.DefaultClient is used if nil.
+	Client *http.Client
+}
+
+// Finding is a response that points to a bug in the mock.
+type Finding struct {
+	Method     string                 `json:"method"`
+	Case       string                 `json:"case"`
+	Encoding   string                 `json:"encoding"`
+	Params     map[string]interface{} `json:"params"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	Problem    string                 `json:"problem"`
+	Body       string                 `json:"body,omitempty"`
+}
+
+// Report summarizes a fuzz run.
+type Report struct {
+	Methods  int       `json:"methods"`
+	Requests int       `json:"requests"`
+	Findings []Finding `json:"findings"`
+	Duration float64   `json:"duration_seconds"`
+}
+
+// maxBody limits how much of a response body is kept in a finding.
+const maxBody = 512
+
+// Run fuzzes every selected method. It returns an error only if the run
+// itself could not be carried out, e.g. because a method is unknown.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if cfg.Token == "" {
+		cfg.Token = DefaultToken
+	}
+	if cfg.Session == "" {
+		cfg.Session = DefaultSession
+	}
+	if cfg.Client == nil {
+		cfg.Client = http.DefaultClient
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	methods := cfg.Methods
+	if len(methods) == 0 {
+		for name := range gen.Methods {
+			methods = append(methods, name)
+		}
+		sort.Strings(methods)
+	}
+	for _, name := range methods {
+		if _, ok := gen.Methods[name]; !ok {
+			return nil, fmt.Errorf("unknown method: %s", name)
+		}
+	}
+
+	started := time.Now()
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	report := &Report{Methods: len(methods), Findings: []Finding{}}
+
+	for _, name := range methods {
+		spec := gen.Methods[name]
+		for i, c := range cases(spec, rng, cfg.Iterations) {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			// Rotate encodings so every case shape is eventually sent
+			// each way a client library might send it
+			enc := encodings[i%len(encodings)]
+			report.Requests++
+			if f := send(ctx, cfg, spec, c, enc); f != nil {
+				report.Findings = append(report.Findings, *f)
+			}
+		}
+	}
+
+	cleanup(ctx, cfg)
+	report.Duration = time.Since(started).Seconds()
+	return report, nil
+}
+
+// send makes a single request and checks the response.
+func send(ctx context.Context, cfg Config, spec gen.MethodSpec, c fuzzCase, enc encoding) *Finding {
+	finding := &Finding{
+		Method:   spec.Name,
+		Case:     c.name,
+		Encoding: string(enc),
+		Params:   c.params,
+	}
+
+	req, err := newRequest(ctx, cfg, spec.Name, c.params, enc)
+	if err != nil {
+		// Parameters that can't be encoded are a bug in the fuzzer
+		finding.Problem = "encoding request: " + err.Error()
+		return finding
+	}
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		finding.Problem = "request failed (crashed or dropped connection?): " + err.Error()
+		return finding
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	finding.StatusCode = resp.StatusCode
+	finding.Body = truncate(body)
+	if err != nil {
+		finding.Problem = "reading response: " + err.Error()
+		return finding
+	}
+
+	if problem := check(spec, c, resp.StatusCode, body); problem != "" {
+		finding.Problem = problem
+		return finding
+	}
+	return nil
+}
+
+// encoding is how parameters are sent.
+type encoding string
+
+const (
+	encodingJSON  encoding = "json"
+	encodingForm  encoding = "form"
+	encodingQuery encoding = "query"
+)
+
+var encodings = []encoding{encodingJSON, encodingForm, encodingQuery}
+
+func newRequest(ctx context.Context, cfg Config, method string, params map[string]interface{}, enc encoding) (*http.Request, error) {
+	endpoint := fmt.Sprintf("%s/session/%s/bot%s/%s", cfg.BaseURL, url.PathEscape(cfg.Session), cfg.Token, method)
+
+	switch enc {
+	case encodingForm, encodingQuery:
+		values, err := formValues(params)
+		if err != nil {
+			return nil, err
+		}
+		if enc == encodingQuery {
+			return http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+values.Encode(), nil)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(values.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req, nil
+	default:
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return req, nil
+	}
+}
+
+// formValues encodes params the way Bot API clients do in form and query
+// requests: strings as they are, everything else as JSON.
+func formValues(params map[string]interface{}) (url.Values, error) {
+	values := url.Values{}
+	for key, v := range params {
+		if s, ok := v.(string); ok {
+			values.Set(key, s)
+			continue
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		values.Set(key, string(data))
+	}
+	return values, nil
+}
+
+// cleanup removes state the run left behind on the instance. Failures are
+// ignored; they don't affect the report.
+func cleanup(ctx context.Context, cfg Config) {
+	base := fmt.Sprintf("%s/session/%s", cfg.BaseURL, url.PathEscape(cfg.Session))
+	if req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+"/bot"+cfg.Token+"/deleteWebhook", nil); err == nil {
+		if resp, err := cfg.Client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+	if req, err := http.NewRequestWithContext(ctx, http.MethodDelete, cfg.BaseURL+"/__control/sessions/"+url.PathEscape(cfg.Session), nil); err == nil {
+		if cfg.ControlToken != "" {
+			req.Header.Set("Authorization", "Bearer "+cfg.ControlToken)
+		}
+		if resp, err := cfg.Client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+func truncate(body []byte) string {
+	if len(body) > maxBody {
+		return string(body[:maxBody]) + "..."
+	}
+	return string(body)
+}
@@ -0,0 +1,75 @@
+// internal/selffuzz/selffuzz_test.go
+package selffuzz
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/watzon/tg-mock/internal/server"
+)
+
+func TestRun_MockHasNoFindings(t *testing.T) {
+	ts := httptest.NewServer(server.New(server.Config{}).Router())
+	defer ts.Close()
+
+	report, err := Run(context.Background(), Config{BaseURL: ts.URL, Iterations: 3, Seed: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Requests == 0 {
+		t.Fatal("expected requests to be sent")
+	}
+	for _, f := range report.Findings {
+		t.Errorf("%s [%s, %s]: %s (status %d) %s", f.Method, f.Case, f.Encoding, f.Problem, f.StatusCode, f.Body)
+	}
+}
+
+func TestRun_ReportsBrokenResponses(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			w.WriteHeader(http.StatusInternalServerError)
+		case strings.HasSuffix(r.URL.Path, "/getChatMemberCount"):
+			// Integer expected
+			w.Write([]byte(`{"ok":true,"result":"many"}`))
+		case strings.HasSuffix(r.URL.Path, "/close"):
+			w.Write([]byte(`{"ok":false,"error_code":400,"description":"Bad Request"}`))
+		default:
+			w.Write([]byte(`{"ok":true,"result":true}`))
+		}
+	}))
+	defer ts.Close()
+
+	report, err := Run(context.Background(), Config{
+		BaseURL: ts.URL,
+		Methods: []string{"getMe", "getChatMemberCount", "close"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	problems := map[string]string{}
+	for _, f := range report.Findings {
+		if _, ok := problems[f.Method]; !ok {
+			problems[f.Method] = f.Problem
+		}
+	}
+	if !strings.Contains(problems["getMe"], "server error 500") {
+		t.Errorf("expected 500 to be reported for getMe, got %q", problems["getMe"])
+	}
+	if !strings.Contains(problems["getChatMemberCount"], "does not match return type Integer") {
+		t.Errorf("expected type mismatch for getChatMemberCount, got %q", problems["getChatMemberCount"])
+	}
+	if !strings.Contains(problems["close"], "status 200") {
+		t.Errorf("expected error sent with status 200 for close, got %q", problems["close"])
+	}
+}
+
+func TestRun_UnknownMethod(t *testing.T) {
+	if _, err := Run(context.Background(), Config{Methods: []string{"noSuchMethod"}}); err == nil {
+		t.Error("expected error for unknown method")
+	}
+}
@@ -0,0 +1,21 @@
+// internal/server/boosts.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+)
+
+// applyBoosts answers getUserChatBoosts from the boosts seeded or
+// simulated for the chat. Chats without boosts get generated ones.
+func applyBoosts(st *session.State, method string, params map[string]interface{}, result interface{}) interface{} {
+	if method != "getUserChatBoosts" {
+		return result
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	userID, _ := int64Value(params["user_id"])
+	if !st.Boosts.Known(chatID) {
+		return result
+	}
+	return map[string]interface{}{"boosts": st.Boosts.User(chatID, userID)}
+}
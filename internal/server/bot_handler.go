@@ -2,46 +2,84 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"html"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/botgroup"
+	"github.com/watzon/tg-mock/internal/events"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/hooks"
 	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/latency"
+	"github.com/watzon/tg-mock/internal/messages"
 	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/script"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/storage"
 	"github.com/watzon/tg-mock/internal/tokens"
-	"github.com/watzon/tg-mock/internal/updates"
+	"github.com/watzon/tg-mock/internal/tracing"
 	"github.com/watzon/tg-mock/internal/webhook"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
 )
 
 // BotHandler handles Bot API requests with token validation
 type BotHandler struct {
 	registry        *tokens.Registry
 	registryEnabled bool
-	scenarios       *scenario.Engine
-	updates         *updates.Queue
+	sessions        *session.Manager
 	validator       *Validator
-	responder       *Responder
-	recorder        *inspector.Recorder
 	webhooks        *webhook.Registry
+	filePaths       *storage.PathRegistry
+	media           *storage.MediaRegistry
+	events          *events.Bus
+	tracer          *tracing.Tracer
+	guard           *guard.Guard
+	groups          *botgroup.Registry
+	hooks           *hooks.Chain
+	latency         *latency.Profile
+	// validateResults says what happens to generated results that break
+	// the spec.
+	validateResults ResultValidation
 }
 
 // NewBotHandler creates a new BotHandler
-func NewBotHandler(registry *tokens.Registry, scenarios *scenario.Engine, updates *updates.Queue, responder *Responder, recorder *inspector.Recorder, webhooks *webhook.Registry, registryEnabled bool) *BotHandler {
+func NewBotHandler(registry *tokens.Registry, sessions *session.Manager, webhooks *webhook.Registry, filePaths *storage.PathRegistry, media *storage.MediaRegistry, events *events.Bus, tracer *tracing.Tracer, guard *guard.Guard, groups *botgroup.Registry, chain *hooks.Chain, profile *latency.Profile, validateResults ResultValidation, registryEnabled bool) *BotHandler {
 	return &BotHandler{
 		registry:        registry,
 		registryEnabled: registryEnabled,
-		scenarios:       scenarios,
-		updates:         updates,
+		sessions:        sessions,
 		validator:       NewValidator(),
-		responder:       responder,
-		recorder:        recorder,
 		webhooks:        webhooks,
+		filePaths:       filePaths,
+		media:           media,
+		events:          events,
+		tracer:          tracer,
+		guard:           guard,
+		groups:          groups,
+		hooks:           chain,
+		latency:         profile,
+		validateResults: validateResults,
 	}
 }
 
+// session returns the session selected for the request, falling back
+// to the default session.
+func (h *BotHandler) session(r *http.Request) *session.State {
+	if st := session.FromContext(r.Context()); st != nil {
+		return st
+	}
+	return h.sessions.Default()
+}
+
 // APIResponse represents the standard Telegram Bot API response format
 type APIResponse struct {
 	OK          bool        `json:"ok"`
@@ -50,17 +88,81 @@ type APIResponse struct {
 	Description string      `json:"description,omitempty"`
 }
 
-// Handle processes Bot API method requests
+// Handle processes Bot API method requests. Each request is traced as a
+// server span, joining the caller's trace when a traceparent header is sent.
 func (h *BotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	token := chi.URLParam(r, "token")
 	method := chi.URLParam(r, "method")
 
+	ctx := tracing.Extract(r.Context(), r.Header)
+	ctx, span := h.tracer.Start(ctx, method, tracing.KindServer)
+	defer span.End()
+	span.SetAttribute("telegram.method", method)
+	span.SetAttribute("telegram.bot_id", botID(token))
+	span.SetAttribute("tg_mock.session", h.session(r).Name)
+
+	// Simulate the round-trip time of the real Bot API
+	if d := h.latency.Delay(method); d > 0 {
+		timer := time.NewTimer(d)
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			timer.Stop()
+			return
+		}
+	}
+
+	sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+	h.handle(sw, r.WithContext(ctx))
+
+	span.SetAttribute("http.response.status_code", sw.status)
+	if sw.status >= 400 {
+		span.SetError(http.StatusText(sw.status))
+	}
+}
+
+// statusWriter remembers the status code written to the response.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// botID returns the bot ID part of a token, which unlike the full token
+// is safe to export in traces.
+func botID(token string) string {
+	if i := strings.IndexByte(token, ':'); i > 0 {
+		return token[:i]
+	}
+	return ""
+}
+
+func (h *BotHandler) handle(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	method := chi.URLParam(r, "method")
+	st := h.session(r)
+
 	w.Header().Set("Content-Type", "application/json")
 
+	// Refuse requests that can't be recorded once the recorder is full
+	if err := h.guard.Admit(guard.Recorder, st.Name, st.Recorder); err != nil {
+		h.writeError(w, http.StatusInsufficientStorage, "Insufficient Storage: request recorder memory limit reached")
+		return
+	}
+
 	// Validate token format
 	if !tokens.ValidateFormat(token) {
 		h.writeError(w, 401, "Unauthorized: invalid token format")
-		h.recordRequest(token, method, nil, "", APIResponse{OK: false, ErrorCode: 401, Description: "Unauthorized: invalid token format"}, true, 401)
+		h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 401, Description: "Unauthorized: invalid token format"}, true, 401)
 		return
 	}
 
@@ -69,31 +171,69 @@ func (h *BotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		info, ok := h.registry.Get(token)
 		if !ok {
 			h.writeError(w, 401, "Unauthorized: token not registered")
-			h.recordRequest(token, method, nil, "", APIResponse{OK: false, ErrorCode: 401, Description: "Unauthorized: token not registered"}, true, 401)
+			h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 401, Description: "Unauthorized: token not registered"}, true, 401)
 			return
 		}
 		switch info.Status {
 		case tokens.StatusBanned:
 			h.writeError(w, 403, "Forbidden: bot was banned")
-			h.recordRequest(token, method, nil, "", APIResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was banned"}, true, 403)
+			h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 403, Description: "Forbidden: bot was banned"}, true, 403)
 			return
 		case tokens.StatusDeactivated:
 			h.writeError(w, 401, "Unauthorized: bot was deactivated")
-			h.recordRequest(token, method, nil, "", APIResponse{OK: false, ErrorCode: 401, Description: "Unauthorized: bot was deactivated"}, true, 401)
+			h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 401, Description: "Unauthorized: bot was deactivated"}, true, 401)
 			return
 		}
 	}
 
+	// Fail once the token has used up its call budget. Only successful
+	// calls count, so failed ones get their charge back.
+	budget, allowed := h.registry.TryCharge(token)
+	if !allowed {
+		resp := &scenario.ErrorResponse{
+			ErrorCode:   budget.ErrorCode,
+			Description: budget.Description,
+			RetryAfter:  budget.RetryAfter,
+		}
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, nil, "budget", errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+	if budget.Calls > 0 {
+		bw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		w = bw
+		defer func() {
+			if bw.status >= 400 {
+				h.registry.RefundBudget(token)
+			}
+		}()
+	}
+
+	// Hold one of the token's in-flight slots until the response is written
+	release, err := h.registry.AcquireConcurrency(r.Context(), token)
+	if err != nil {
+		limit, _ := h.registry.GetConcurrency(token)
+		resp := &scenario.ErrorResponse{
+			ErrorCode:   http.StatusTooManyRequests,
+			Description: fmt.Sprintf("Too Many Requests: retry after %d", limit.RetryAfter),
+			RetryAfter:  limit.RetryAfter,
+		}
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, nil, "concurrency", errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+	defer release()
+
 	// Handle webhook methods before method lookup
 	switch method {
 	case "setWebhook":
-		h.handleSetWebhook(w, token, h.parseParamsOrEmpty(r))
+		h.handleSetWebhook(w, st, token, h.parseParamsOrEmpty(r))
 		return
 	case "deleteWebhook":
-		h.handleDeleteWebhook(w, token, h.parseParamsOrEmpty(r))
+		h.handleDeleteWebhook(w, st, token, h.parseParamsOrEmpty(r))
 		return
 	case "getWebhookInfo":
-		h.handleGetWebhookInfo(w, token)
+		h.handleGetWebhookInfo(w, st, token)
 		return
 	}
 
@@ -101,7 +241,14 @@ func (h *BotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	spec, ok := gen.Methods[method]
 	if !ok {
 		h.writeError(w, 404, "Not Found: method not found")
-		h.recordRequest(token, method, nil, "", APIResponse{OK: false, ErrorCode: 404, Description: "Not Found: method not found"}, true, 404)
+		h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 404, Description: "Not Found: method not found"}, true, 404)
+		return
+	}
+
+	// Methods newer than the simulated Bot API version don't exist yet
+	if !st.APIVersion.Allow(method) {
+		h.writeError(w, 404, "Not Found: method not found")
+		h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 404, Description: "Not Found: method not found"}, true, 404)
 		return
 	}
 
@@ -110,13 +257,55 @@ func (h *BotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		desc := "Bad Request: " + err.Error()
 		h.writeError(w, 400, desc)
-		h.recordRequest(token, method, nil, "", APIResponse{OK: false, ErrorCode: 400, Description: desc}, true, 400)
+		h.recordRequest(st, token, method, nil, "", APIResponse{OK: false, ErrorCode: 400, Description: desc}, true, 400)
+		return
+	}
+
+	// Hooks may change the call, answer it, or veto it before anything else
+	call := &hooks.Call{Session: st.Name, Token: token, Method: method, Params: params}
+	if name, resp := h.hooks.Before(call); resp != nil {
+		h.writeHookResponse(w, st, call, "hook:"+name, resp)
+		return
+	}
+	if call.Params == nil {
+		call.Params = make(map[string]interface{})
+	}
+	params = call.Params
+
+	// Calls made before the retry_after of a 429 elapsed fail again
+	if retryAfter, ok := st.Cooldowns.Check(token, messages.ChatKey(params["chat_id"])); !ok {
+		resp := tgerrors.RateLimit(retryAfter)
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, "retry_after", errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Groups upgraded to supergroups can't be used anymore
+	if resp := migratedChat(st, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, "migrated", errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// During an outage burst calls fail, unless they are applied anyway and
+	// only the response is lost
+	failure, failing := st.Outage.Fail(method, params)
+	if failing && (!failure.Applied || method == "getUpdates") {
+		h.writeError(w, failure.ErrorCode, failure.Description)
+		h.recordRequest(st, token, method, params, "outage", APIResponse{OK: false, ErrorCode: failure.ErrorCode, Description: failure.Description}, true, failure.ErrorCode)
+		return
+	}
+
+	// Chaos mode fails a random share of calls
+	if resp, ok := st.Chaos.Fail(method); ok {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, "chaos", errorBody(resp), true, resp.ErrorCode)
 		return
 	}
 
 	// Check for header-based scenario
 	if scenarioName := r.Header.Get("X-TG-Mock-Scenario"); scenarioName != "" {
-		if resp := h.handleHeaderScenarioWithRecording(w, r, token, method, params, scenarioName); resp {
+		if resp := h.handleHeaderScenarioWithRecording(w, r, st, token, method, params, scenarioName); resp {
 			return
 		}
 	}
@@ -124,22 +313,43 @@ func (h *BotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 	// Check for queued scenarios
 	var scenarioOverrides map[string]interface{}
 	var matchedScenarioID string
-	if s := h.scenarios.Find(method, params); s != nil {
+	var scripted *scenario.Scenario
+	var scriptCall int
+	_, matchSpan := h.tracer.Start(r.Context(), "scenario.match", tracing.KindInternal)
+	s := st.Scenarios.Find(method, params)
+	matchSpan.SetAttribute("tg_mock.scenario_matched", s != nil)
+	if s != nil {
+		matchSpan.SetAttribute("tg_mock.scenario_id", s.ID)
+	}
+	matchSpan.End()
+	if s != nil {
 		s.Use()
 		matchedScenarioID = s.ID
+		if s.Exhausted() {
+			h.events.Publish(events.Event{
+				Type:    events.TypeScenarioExhausted,
+				Session: st.Name,
+				Data: map[string]interface{}{
+					"scenario_id": s.ID,
+					"method":      method,
+					"token":       token,
+				},
+			})
+		}
 		if s.IsError() {
-			h.writeErrorResponse(w, s.Response)
-			h.recordRequest(token, method, params, matchedScenarioID, map[string]interface{}{
-				"ok":          false,
-				"error_code":  s.Response.ErrorCode,
-				"description": s.Response.Description,
-			}, true, s.Response.ErrorCode)
+			resp := migrateChat(st, params, s.Response)
+			h.writeErrorResponse(w, resp)
+			h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
 			return
 		}
 		// Store response data overrides for later use
 		if s.HasResponseData() {
 			scenarioOverrides = s.ResponseData
 		}
+		if s.HasScript() {
+			scripted = s
+			scriptCall = s.Used()
+		}
 	}
 
 	// Handle getUpdates specially
@@ -148,33 +358,358 @@ func (h *BotHandler) Handle(w http.ResponseWriter, r *http.Request) {
 		if h.webhooks.IsActive(token) {
 			desc := "Conflict: can't use getUpdates method while webhook is active"
 			h.writeError(w, 409, desc)
-			h.recordRequest(token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 409, Description: desc}, true, 409)
+			h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 409, Description: desc}, true, 409)
+			return
+		}
+		// A bot polls once at a time, and a new call ends the one waiting
+		terminated, done := st.LongPolls.Start(token)
+		result, ok := h.handleGetUpdates(r.Context(), w, st, params, terminated)
+		done()
+		if !ok {
+			desc := tgerrors.TerminatedByLongPoll().Description
+			h.writeError(w, 409, desc)
+			h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 409, Description: desc}, true, 409)
 			return
 		}
-		result := h.handleGetUpdates(params)
-		h.writeSuccess(w, result)
-		h.recordRequest(token, method, params, matchedScenarioID, APIResponse{OK: true, Result: result}, false, 200)
+		resp := hooks.Result(result)
+		h.hooks.After(call, resp)
+		if resp.OK {
+			resp.Result = st.Compat.Apply(method, spec.Returns, resp.Result)
+			resp.Result = st.Adversarial.Apply(method, spec.Returns, resp.Result)
+		}
+		h.writeHookResponse(w, st, call, matchedScenarioID, resp)
 		return
 	}
 
 	// Validate request
 	if err := h.validator.Validate(spec, params); err != nil {
 		desc := "Bad Request: " + err.Error()
+		h.events.Publish(events.Event{
+			Type:    events.TypeVerificationFailed,
+			Session: st.Name,
+			Data: map[string]interface{}{
+				"method": method,
+				"token":  token,
+				"error":  err.Error(),
+			},
+		})
 		h.writeError(w, 400, desc)
-		h.recordRequest(token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 400, Description: desc}, true, 400)
+		h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 400, Description: desc}, true, 400)
+		return
+	}
+
+	// Public chats named by username are resolved to the known chat
+	if resp := resolveUsernames(st, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Answers to inline and callback queries are only accepted for a short
+	// time
+	if method == "answerInlineQuery" || method == "answerCallbackQuery" {
+		var answered bool
+		if id, _ := params["inline_query_id"].(string); method == "answerInlineQuery" {
+			answered = st.InlineQueries.Answer(id)
+		} else {
+			id, _ = params["callback_query_id"].(string)
+			answered = st.Callbacks.Answer(id)
+		}
+		if !answered {
+			desc := "Bad Request: query is too old and response timeout expired or query ID is invalid"
+			h.writeError(w, 400, desc)
+			h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 400, Description: desc}, true, 400)
+			return
+		}
+	}
+
+	// Refuse to send new messages once the message store is full
+	if returnsMessages(spec) {
+		if err := h.guard.Admit(guard.Messages, st.Name, st.Messages); err != nil {
+			desc := "Insufficient Storage: message store memory limit reached"
+			h.writeError(w, http.StatusInsufficientStorage, desc)
+			h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: http.StatusInsufficientStorage, Description: desc}, true, http.StatusInsufficientStorage)
+			return
+		}
+	}
+
+	// Bots can't use chats they were removed from, nor send without rights
+	if resp := botMembership(st, token, spec, method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Sent messages are subject to Telegram's flood limits
+	if returnsMessages(spec) && !editMethods[method] {
+		if chatID := messages.ChatKey(params["chat_id"]); chatID != "" {
+			if retryAfter, ok := st.FloodLimit.Allow(token, chatID, groupChat(chatID)); !ok {
+				resp := tgerrors.RateLimit(retryAfter)
+				h.writeErrorResponse(w, resp)
+				h.recordRequest(st, token, method, params, "flood_limit", errorBody(resp), true, resp.ErrorCode)
+				return
+			}
+		}
+	}
+
+	// Enumerated parameters, such as chat actions and parse modes, must
+	// have one of their values
+	if resp := checkEnums(spec, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Text and captions must parse in their parse_mode and fit the length
+	// limits
+	if resp := checkFormatting(spec.Fields, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Inline keyboards must be well-formed and within Telegram's limits
+	if resp := checkReplyMarkup(params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Media must be well-formed, and albums have 2 to 10 media of types
+	// that can be grouped
+	if resp := checkMedia(method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Replies may only quote text the replied-to message contains
+	var replyTo *reply
+	if returnsMessages(spec) && !editMethods[method] {
+		var resp *scenario.ErrorResponse
+		if replyTo, resp = resolveReply(st, params); resp != nil {
+			h.writeErrorResponse(w, resp)
+			h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+			return
+		}
+	}
+
+	// Forwards and copies need a message to forward
+	sources, resp := resolveForward(st, method, params)
+	if resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Chat changes are stored, and fail if they change nothing
+	if resp := updateChat(st, method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Bot settings are stored, and commands follow Telegram's rules
+	if resp := updateBotSettings(st, token, method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Forum topics change, and messages go to topics that exist
+	if resp := updateTopic(st, spec, method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Moderation changes the members of chats, and fails for the owner
+	if resp := updateMember(st, method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
+		return
+	}
+
+	// Deleted messages leave the message store, unless they are too old
+	if resp := deleteMessages(st, method, params); resp != nil {
+		h.writeErrorResponse(w, resp)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(resp), true, resp.ErrorCode)
 		return
 	}
 
 	// Generate response (with scenario overrides if present)
-	result, err := h.responder.GenerateWithOverrides(spec, params, scenarioOverrides)
+	result, err := NewResponder(st.Faker).GenerateWithOverrides(spec, params, scenarioOverrides)
 	if err != nil {
 		h.writeError(w, 500, "Internal Server Error")
-		h.recordRequest(token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 500, Description: "Internal Server Error"}, true, 500)
+		h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 500, Description: "Internal Server Error"}, true, 500)
 		return
 	}
+	result = applyFormatting(params, result)
+	result = applyMediaGroup(st, method, params, result)
+	result = applyMediaEdit(st, method, params, result)
+	replyTo.apply(result)
+	result = applyForward(st, method, params, sources, result)
+
+	// Invite links, polls, Star transactions, game scores, and sticker sets
+	// are kept, so later calls work on what bots were given
+	var stateErr *tgerrors.Error
+	if result, stateErr = h.applyInviteLink(st, token, method, params, result); stateErr == nil {
+		result, stateErr = applyPoll(st, method, params, result)
+	}
+	if stateErr == nil {
+		result, stateErr = applyStars(st, token, method, params, result)
+	}
+	if stateErr == nil {
+		result, stateErr = h.applyGame(st, token, method, params, result)
+	}
+	if stateErr == nil {
+		result, stateErr = applyStickerSet(st, method, params, result)
+	}
+	if stateErr != nil {
+		h.writeErrorResponse(w, stateErr)
+		h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(stateErr), true, stateErr.ErrorCode)
+		return
+	}
+	result = applyTopic(st, method, params, result)
+	result = applyBoosts(st, method, params, result)
+	applyUsers(st, result)
+	applyChat(st, method, params, result)
+	applyMessageChats(st, result)
+	applyMember(st, method, params, result)
+	h.applyBotProfile(st, token, method, scenarioOverrides, result)
+	if scenarioOverrides == nil {
+		result = h.applyBotSettings(st, token, method, params, result)
+		result = h.applyChatMembers(st, token, method, params, result)
+	}
+
+	// Scripted scenarios compute the response from the generated one
+	if scripted != nil {
+		out, err := script.Run(scripted.Script, script.Input{Method: method, Params: params, Call: scriptCall, Result: result})
+		if err != nil {
+			desc := "Internal Server Error: script failed: " + err.Error()
+			h.writeError(w, 500, desc)
+			h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 500, Description: desc}, true, 500)
+			return
+		}
+		if out.Error != nil {
+			h.writeErrorResponse(w, out.Error)
+			h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(out.Error), true, out.Error.ErrorCode)
+			return
+		}
+		result = out.Result
+	}
+
+	if method == "getFile" {
+		if tgErr := h.issueFilePath(token, result); tgErr != nil {
+			h.writeErrorResponse(w, tgErr)
+			h.recordRequest(st, token, method, params, matchedScenarioID, errorBody(tgErr), true, tgErr.ErrorCode)
+			return
+		}
+	}
+	result = h.trackMessages(st, method, params, result)
+
+	// Results may be checked against the spec, so fixtures the faker gets
+	// wrong don't reach strict clients unnoticed
+	if problems := h.checkResult(spec, result); len(problems) > 0 {
+		if h.validateResults == ResultValidationFail {
+			desc := "Internal Server Error: result breaks the spec: " + strings.Join(problems, "; ")
+			h.writeError(w, 500, desc)
+			h.recordRequest(st, token, method, params, matchedScenarioID, APIResponse{OK: false, ErrorCode: 500, Description: desc}, true, 500)
+			return
+		}
+		log.Printf("tg-mock: %s result breaks the spec: %s", method, strings.Join(problems, "; "))
+	}
+
+	if failing {
+		h.writeError(w, failure.ErrorCode, failure.Description)
+		h.recordRequest(st, token, method, params, "outage", APIResponse{OK: false, ErrorCode: failure.ErrorCode, Description: failure.Description}, true, failure.ErrorCode)
+	} else {
+		resp := hooks.Result(result)
+		h.hooks.After(call, resp)
+		if resp.OK {
+			// The bot may get a perturbed copy; the mock keeps working with the result
+			resp.Result = st.Compat.Apply(method, spec.Returns, resp.Result)
+			resp.Result = st.Adversarial.Apply(method, spec.Returns, resp.Result)
+			st.Outage.Succeeded(method, params)
+		}
+		h.writeHookResponse(w, st, call, matchedScenarioID, resp)
+	}
+	h.runPersonas(st, token, spec, params, result)
+	h.announceChatChange(st, token, method, params)
+	h.relayToBots(token, spec, params, result)
+}
+
+// checkResult returns the ways a generated result breaks the spec, if
+// results are validated.
+func (h *BotHandler) checkResult(spec gen.MethodSpec, result interface{}) []string {
+	if h.validateResults == ResultValidationOff {
+		return nil
+	}
+	return h.validator.ValidateResult(spec, result)
+}
+
+// writeHookResponse sends and records a response that may have come from
+// a hook. Error responses without a code are sent as 400 Bad Request.
+func (h *BotHandler) writeHookResponse(w http.ResponseWriter, st *session.State, call *hooks.Call, scenarioID string, resp *hooks.Response) {
+	if resp.OK {
+		h.media.Scan(resp.Result)
+		h.writeSuccess(w, resp.Result)
+		h.recordRequest(st, call.Token, call.Method, call.Params, scenarioID, APIResponse{OK: true, Result: resp.Result}, false, 200)
+		return
+	}
+
+	errResp := &scenario.ErrorResponse{
+		ErrorCode:   resp.ErrorCode,
+		Description: resp.Description,
+		RetryAfter:  resp.RetryAfter,
+	}
+	if errResp.ErrorCode == 0 {
+		errResp.ErrorCode = http.StatusBadRequest
+	}
+	if errResp.Description == "" {
+		errResp.Description = http.StatusText(errResp.ErrorCode)
+	}
+	h.writeErrorResponse(w, errResp)
+	h.recordRequest(st, call.Token, call.Method, call.Params, scenarioID, errorBody(errResp), true, errResp.ErrorCode)
+}
 
-	h.writeSuccess(w, result)
-	h.recordRequest(token, method, params, matchedScenarioID, APIResponse{OK: true, Result: result}, false, 200)
+// issueFilePath makes the file_path returned by getFile downloadable by the
+// requesting token until it expires. Generated files the bot was given get
+// a path and size fitting the content they are downloaded as; like on
+// Telegram, those over the download limit can't be downloaded at all.
+func (h *BotHandler) issueFilePath(token string, result interface{}) *tgerrors.Error {
+	file, ok := result.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	filePath, _ := file["file_path"].(string)
+	if filePath == "" {
+		return nil
+	}
+	fileID, _ := file["file_id"].(string)
+	if m, ok := h.media.Lookup(fileID); ok {
+		if m.Size > maxDownloadSize {
+			return tgerrors.FileTooBig()
+		}
+		filePath = storage.MediaPath(filePath, m)
+		file["file_path"] = filePath
+		if data, _, err := storage.Synthesize(fileID, m, filePath); err == nil {
+			file["file_size"] = int64(len(data))
+		}
+	}
+
+	var size int64
+	switch v := file["file_size"].(type) {
+	case int:
+		size = int64(v)
+	case int64:
+		size = v
+	case float64:
+		size = int64(v)
+	}
+
+	h.filePaths.Issue(token, filePath, fileID, size)
+	return nil
 }
 
 func (h *BotHandler) writeError(w http.ResponseWriter, code int, desc string) {
@@ -186,6 +721,15 @@ func (h *BotHandler) writeError(w http.ResponseWriter, code int, desc string) {
 	})
 }
 
+// writeHTMLError writes an error page like the ones served by Telegram's
+// edge servers, which clients expecting JSON fail to decode.
+func writeHTMLError(w http.ResponseWriter, code int, desc string) {
+	w.Header().Set("Content-Type", "text/html")
+	w.WriteHeader(code)
+	title := html.EscapeString(fmt.Sprintf("%d %s", code, desc))
+	fmt.Fprintf(w, "<html>\r\n<head><title>%s</title></head>\r\n<body>\r\n<center><h1>%s</h1></center>\r\n<hr><center>nginx</center>\r\n</body>\r\n</html>\r\n", title, title)
+}
+
 func (h *BotHandler) writeSuccess(w http.ResponseWriter, result interface{}) {
 	json.NewEncoder(w).Encode(APIResponse{
 		OK:     true,
@@ -193,7 +737,8 @@ func (h *BotHandler) writeSuccess(w http.ResponseWriter, result interface{}) {
 	})
 }
 
-// parseParams extracts parameters from query string, JSON body, and form data
+// parseParams extracts parameters from query string, JSON body, and form
+// data. Files uploaded as multipart parts become descriptions of the file.
 func (h *BotHandler) parseParams(r *http.Request) (map[string]interface{}, error) {
 	params := make(map[string]interface{})
 
@@ -220,6 +765,21 @@ func (h *BotHandler) parseParams(r *http.Request) (map[string]interface{}, error
 					params[key] = values[0]
 				}
 			}
+		} else if strings.HasPrefix(contentType, "multipart/form-data") {
+			if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+				return nil, err
+			}
+			defer r.MultipartForm.RemoveAll()
+			for key, values := range r.MultipartForm.Value {
+				if len(values) > 0 {
+					params[key] = values[0]
+				}
+			}
+			for key, files := range r.MultipartForm.File {
+				if len(files) > 0 {
+					params[key] = uploadParam(files[0])
+				}
+			}
 		}
 	}
 
@@ -228,7 +788,7 @@ func (h *BotHandler) parseParams(r *http.Request) (map[string]interface{}, error
 
 // handleHeaderScenarioWithRecording handles X-TG-Mock-Scenario header-based error scenarios
 // and records the request. It looks up pre-built errors by name and returns the appropriate error response.
-func (h *BotHandler) handleHeaderScenarioWithRecording(w http.ResponseWriter, r *http.Request, token, method string, params map[string]interface{}, name string) bool {
+func (h *BotHandler) handleHeaderScenarioWithRecording(w http.ResponseWriter, r *http.Request, st *session.State, token, method string, params map[string]interface{}, name string) bool {
 	resp := scenario.GetBuiltinError(name)
 	if resp == nil {
 		return false
@@ -237,50 +797,86 @@ func (h *BotHandler) handleHeaderScenarioWithRecording(w http.ResponseWriter, r
 	// Allow retry_after override via header
 	if retryAfter := r.Header.Get("X-TG-Mock-Retry-After"); retryAfter != "" {
 		if val, err := strconv.Atoi(retryAfter); err == nil {
-			resp = &scenario.ErrorResponse{
-				ErrorCode:   resp.ErrorCode,
-				Description: resp.Description,
-				RetryAfter:  val,
-			}
+			overridden := *resp
+			overridden.RetryAfter = val
+			resp = &overridden
 		}
 	}
+	resp = migrateChat(st, params, resp)
 
 	h.writeErrorResponse(w, resp)
 
 	// Record with header: prefix for scenario ID
-	response := map[string]interface{}{
-		"ok":          false,
-		"error_code":  resp.ErrorCode,
-		"description": resp.Description,
-	}
-	if resp.RetryAfter > 0 {
-		response["parameters"] = map[string]interface{}{
-			"retry_after": resp.RetryAfter,
-		}
-	}
-	h.recordRequest(token, method, params, "header:"+name, response, true, resp.ErrorCode)
+	h.recordRequest(st, token, method, params, "header:"+name, errorBody(resp), true, resp.ErrorCode)
 
 	return true
 }
 
 // writeErrorResponse writes a scenario error response with proper formatting
 func (h *BotHandler) writeErrorResponse(w http.ResponseWriter, resp *scenario.ErrorResponse) {
+	if resp.HTML {
+		writeHTMLError(w, resp.ErrorCode, resp.Description)
+		return
+	}
 	w.WriteHeader(resp.ErrorCode)
-	response := map[string]interface{}{
+	json.NewEncoder(w).Encode(errorBody(resp))
+}
+
+// errorBody returns the response body of an error, as sent by
+// writeErrorResponse and recorded by recordRequest.
+func errorBody(resp *scenario.ErrorResponse) map[string]interface{} {
+	body := map[string]interface{}{
 		"ok":          false,
 		"error_code":  resp.ErrorCode,
 		"description": resp.Description,
 	}
-	if resp.RetryAfter > 0 {
-		response["parameters"] = map[string]interface{}{
-			"retry_after": resp.RetryAfter,
-		}
+	if parameters := resp.ResponseParameters(); parameters != nil {
+		body["parameters"] = parameters
+	}
+	return body
+}
+
+// retryAfterOf returns the retry_after of a recorded error response.
+func retryAfterOf(response interface{}) int {
+	body, _ := response.(map[string]interface{})
+	parameters, _ := body["parameters"].(map[string]interface{})
+	retryAfter, _ := int64Value(parameters["retry_after"])
+	return int(retryAfter)
+}
+
+// int64Value returns a whole number set in Go, decoded from JSON or YAML,
+// or passed as a form value.
+func int64Value(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), n == float64(int64(n))
+	case string:
+		parsed, err := parseInt64(n)
+		return parsed, err == nil
 	}
-	json.NewEncoder(w).Encode(response)
+	return 0, false
 }
 
-// handleGetUpdates processes the getUpdates method by returning updates from the queue
-func (h *BotHandler) handleGetUpdates(params map[string]interface{}) []map[string]interface{} {
+// boolParam returns a boolean parameter. Form and query parameters carry
+// booleans as strings.
+func boolParam(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
+
+// handleGetUpdates processes the getUpdates method by returning updates from
+// the queue. With a timeout, it waits for updates when there are none, and
+// reports false if the wait is terminated by another call.
+func (h *BotHandler) handleGetUpdates(ctx context.Context, w http.ResponseWriter, st *session.State, params map[string]interface{}, terminated <-chan struct{}) ([]map[string]interface{}, bool) {
 	offset := int64(0)
 	if o, ok := params["offset"].(float64); ok {
 		offset = int64(o)
@@ -301,12 +897,43 @@ func (h *BotHandler) handleGetUpdates(params map[string]interface{}) []map[strin
 		}
 	}
 
+	timeout := int64(0)
+	if t, ok := params["timeout"].(float64); ok {
+		timeout = int64(t)
+	} else if t, ok := params["timeout"].(string); ok {
+		if parsed, err := parseInt64(t); err == nil {
+			timeout = parsed
+		}
+	}
+
 	// Acknowledge previous updates
 	if offset > 0 {
-		h.updates.Acknowledge(offset)
+		st.Updates.Acknowledge(offset)
 	}
 
-	return h.updates.Get(offset, limit)
+	if timeout <= 0 {
+		return st.Updates.Get(offset, limit), true
+	}
+	// Long polls wait in real time, whatever the mock clock says, and may
+	// wait longer than the server's write timeout
+	wait := time.Duration(timeout) * time.Second
+	extendWriteDeadline(w, wait)
+	ctx, cancel := context.WithTimeout(ctx, wait)
+	defer cancel()
+	go func() {
+		select {
+		case <-terminated:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	result := st.Updates.Wait(ctx, offset, limit)
+	select {
+	case <-terminated:
+		return nil, false
+	default:
+		return result, true
+	}
 }
 
 // parseInt64 parses a string to int64
@@ -324,8 +951,23 @@ func parseInt(s string) (int, error) {
 }
 
 // recordRequest records a request to the inspector
-func (h *BotHandler) recordRequest(token, method string, params map[string]interface{}, scenarioID string, response interface{}, isError bool, statusCode int) {
-	h.recorder.Record(inspector.RequestRecord{
+func (h *BotHandler) recordRequest(st *session.State, token, method string, params map[string]interface{}, scenarioID string, response interface{}, isError bool, statusCode int) {
+	if statusCode == http.StatusTooManyRequests {
+		// Rejections of early calls don't extend the wait
+		if scenarioID != "retry_after" {
+			st.Cooldowns.Start(token, messages.ChatKey(params["chat_id"]), retryAfterOf(response))
+		}
+		h.events.Publish(events.Event{
+			Type:    events.TypeRateLimitTripped,
+			Session: st.Name,
+			Data: map[string]interface{}{
+				"method":      method,
+				"token":       token,
+				"scenario_id": scenarioID,
+			},
+		})
+	}
+	st.Recorder.Record(inspector.RequestRecord{
 		Timestamp:  time.Now(),
 		Token:      token,
 		Method:     method,
@@ -334,6 +976,8 @@ func (h *BotHandler) recordRequest(token, method string, params map[string]inter
 		Response:   response,
 		IsError:    isError,
 		StatusCode: statusCode,
+
+		ScenarioRevision: st.Scenarios.Revision(),
 	})
 }
 
@@ -347,23 +991,24 @@ func (h *BotHandler) parseParamsOrEmpty(r *http.Request) map[string]interface{}
 }
 
 // handleSetWebhook handles the setWebhook Bot API method
-func (h *BotHandler) handleSetWebhook(w http.ResponseWriter, token string, params map[string]interface{}) {
+func (h *BotHandler) handleSetWebhook(w http.ResponseWriter, st *session.State, token string, params map[string]interface{}) {
 	url, _ := params["url"].(string)
 
 	// Empty URL means delete webhook
 	if url == "" {
 		h.webhooks.Delete(token)
 		if dropPending, _ := params["drop_pending_updates"].(bool); dropPending {
-			h.updates.Clear()
+			st.Updates.Clear()
 		}
 		h.writeSuccess(w, true)
-		h.recordRequest(token, "setWebhook", params, "", APIResponse{OK: true, Result: true}, false, 200)
+		h.recordRequest(st, token, "setWebhook", params, "", APIResponse{OK: true, Result: true}, false, 200)
 		return
 	}
 
 	// Build webhook config from params
 	cfg := &webhook.Config{
-		URL: url,
+		URL:     url,
+		Session: st.Name,
 	}
 
 	if secret, ok := params["secret_token"].(string); ok {
@@ -384,33 +1029,34 @@ func (h *BotHandler) handleSetWebhook(w http.ResponseWriter, token string, param
 	}
 
 	h.webhooks.Set(token, cfg)
+	st.LongPolls.Terminate(token)
 
 	// Handle drop_pending_updates
 	if dropPending, _ := params["drop_pending_updates"].(bool); dropPending {
-		h.updates.Clear()
+		st.Updates.Clear()
 	}
 
 	h.writeSuccess(w, true)
-	h.recordRequest(token, "setWebhook", params, "", APIResponse{OK: true, Result: true}, false, 200)
+	h.recordRequest(st, token, "setWebhook", params, "", APIResponse{OK: true, Result: true}, false, 200)
 }
 
 // handleDeleteWebhook handles the deleteWebhook Bot API method
-func (h *BotHandler) handleDeleteWebhook(w http.ResponseWriter, token string, params map[string]interface{}) {
+func (h *BotHandler) handleDeleteWebhook(w http.ResponseWriter, st *session.State, token string, params map[string]interface{}) {
 	h.webhooks.Delete(token)
 
 	// Handle drop_pending_updates
 	if dropPending, _ := params["drop_pending_updates"].(bool); dropPending {
-		h.updates.Clear()
+		st.Updates.Clear()
 	}
 
 	h.writeSuccess(w, true)
-	h.recordRequest(token, "deleteWebhook", params, "", APIResponse{OK: true, Result: true}, false, 200)
+	h.recordRequest(st, token, "deleteWebhook", params, "", APIResponse{OK: true, Result: true}, false, 200)
 }
 
 // handleGetWebhookInfo handles the getWebhookInfo Bot API method
-func (h *BotHandler) handleGetWebhookInfo(w http.ResponseWriter, token string) {
-	pendingCount := h.updates.Pending()
+func (h *BotHandler) handleGetWebhookInfo(w http.ResponseWriter, st *session.State, token string) {
+	pendingCount := st.Updates.Pending()
 	info := h.webhooks.GetInfo(token, pendingCount)
 	h.writeSuccess(w, info)
-	h.recordRequest(token, "getWebhookInfo", nil, "", APIResponse{OK: true, Result: info}, false, 200)
+	h.recordRequest(st, token, "getWebhookInfo", nil, "", APIResponse{OK: true, Result: info}, false, 200)
 }
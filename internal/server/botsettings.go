@@ -0,0 +1,107 @@
+// internal/server/botsettings.go
+package server
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/watzon/tg-mock/internal/botsettings"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// maxBotCommands is the number of commands setMyCommands takes.
+const maxBotCommands = 100
+
+// commandPattern matches the characters command names can have. Their
+// length is limited by the spec of BotCommand.
+var commandPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// botTexts maps the methods setting and getting the texts of a bot to the
+// text, which also names their parameter and result field.
+var botTexts = map[string]string{
+	"setMyName":             botsettings.TextName,
+	"getMyName":             botsettings.TextName,
+	"setMyDescription":      botsettings.TextDescription,
+	"getMyDescription":      botsettings.TextDescription,
+	"setMyShortDescription": botsettings.TextShortDescription,
+	"getMyShortDescription": botsettings.TextShortDescription,
+}
+
+// updateBotSettings stores what a bot sets about itself, per token.
+// Commands that break Telegram's rules fail the call.
+func updateBotSettings(st *session.State, token, method string, params map[string]interface{}) *tgerrors.Error {
+	scope := objectParam(params["scope"])
+	languageCode, _ := params["language_code"].(string)
+	switch method {
+	case "setMyCommands":
+		commands := arrayParam(params["commands"])
+		if resp := checkBotCommands(commands); resp != nil {
+			return resp
+		}
+		st.Bots.SetCommands(token, scope, languageCode, commands)
+	case "deleteMyCommands":
+		st.Bots.DeleteCommands(token, scope, languageCode)
+	case "setMyName", "setMyDescription", "setMyShortDescription":
+		// A missing or empty text removes it
+		text := botTexts[method]
+		value, _ := params[text].(string)
+		st.Bots.SetText(token, text, languageCode, value)
+	}
+	return nil
+}
+
+// checkBotCommands checks commands against Telegram's rules: at most 100
+// commands, named with 1 to 32 lowercase English letters, digits, and
+// underscores, and described in 1 to 256 characters.
+func checkBotCommands(commands []interface{}) *tgerrors.Error {
+	if len(commands) > maxBotCommands {
+		return tgerrors.BotCommandsTooMuch()
+	}
+	for _, c := range commands {
+		command := objectParam(c)
+		name, _ := command["command"].(string)
+		if !commandPattern.MatchString(name) || !fitsLength(name, typeConstraint("BotCommand", "command")) {
+			return tgerrors.BotCommandInvalid()
+		}
+		description, _ := command["description"].(string)
+		if !fitsLength(description, typeConstraint("BotCommand", "description")) {
+			return tgerrors.BotCommandDescriptionInvalid()
+		}
+	}
+	return nil
+}
+
+// applyBotSettings returns the stored settings of a bot as the result of
+// the getters, instead of generated ones. A bot without a name set is
+// named as getMe says; descriptions default to empty.
+func (h *BotHandler) applyBotSettings(st *session.State, token, method string, params map[string]interface{}, result interface{}) interface{} {
+	scope := objectParam(params["scope"])
+	languageCode, _ := params["language_code"].(string)
+	switch method {
+	case "getMyCommands":
+		return st.Bots.Commands(token, scope, languageCode)
+	case "getMyName", "getMyDescription", "getMyShortDescription":
+		text := botTexts[method]
+		value, ok := st.Bots.Text(token, text, languageCode)
+		if !ok && text == botsettings.TextName {
+			value, _ = h.botUser(token)["first_name"].(string)
+		}
+		return map[string]interface{}{text: value}
+	}
+	return result
+}
+
+// arrayParam returns an array parameter. Form and query parameters carry
+// arrays as JSON strings.
+func arrayParam(v interface{}) []interface{} {
+	if str, ok := v.(string); ok {
+		var decoded []interface{}
+		if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	arr, _ := v.([]interface{})
+	return arr
+}
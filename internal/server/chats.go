@@ -0,0 +1,255 @@
+// internal/server/chats.go
+package server
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// chatFields maps the methods changing a chat to the ChatFullInfo field
+// they set.
+var chatFields = map[string]string{
+	"setChatTitle":         "title",
+	"setChatDescription":   "description",
+	"setChatStickerSet":    "sticker_set_name",
+	"deleteChatStickerSet": "sticker_set_name",
+}
+
+// chatParams maps the methods changing a chat to the parameter holding
+// the new value. deleteChatStickerSet has none.
+var chatParams = map[string]string{
+	"setChatTitle":       "title",
+	"setChatDescription": "description",
+	"setChatStickerSet":  "sticker_set_name",
+}
+
+// updateChat applies a call changing a chat to the chat store. Setting a
+// field to the value it already has fails with CHAT_NOT_MODIFIED, as in
+// Telegram; this is only detected once the store knows the field.
+func updateChat(st *session.State, method string, params map[string]interface{}) *tgerrors.Error {
+	field, ok := chatFields[method]
+	if !ok {
+		return nil
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	if chatID == "" {
+		return nil
+	}
+
+	value, _ := params[chatParams[method]].(string)
+	var changed bool
+	if value == "" {
+		// An empty description removes it
+		changed = st.Chats.Unset(chatID, field)
+	} else {
+		changed = st.Chats.Set(chatID, field, value)
+	}
+	if !changed {
+		return tgerrors.ChatNotModified()
+	}
+	return nil
+}
+
+// seededChatFields are the generated ChatFullInfo fields kept for seeded
+// chats: the ones Telegram always sends.
+var seededChatFields = map[string]bool{"id": true, "type": true, "accent_color_id": true, "max_reaction_count": true, "accepted_gift_types": true}
+
+// applyChat overlays the stored state of a chat onto a generated getChat
+// result. Seeded chats are returned as seeded, without random extras.
+func applyChat(st *session.State, method string, params map[string]interface{}, result interface{}) {
+	if method != "getChat" {
+		return
+	}
+	chat, ok := result.(map[string]interface{})
+	chatID := messages.ChatKey(params["chat_id"])
+	if !ok || chatID == "" {
+		return
+	}
+	if st.Chats.Seeded(chatID) {
+		for name := range chat {
+			if !seededChatFields[name] {
+				delete(chat, name)
+			}
+		}
+	}
+	st.Chats.Apply(chatID, chat)
+}
+
+// chatObjectFields are the fields of a Chat, as updates carry it.
+var chatObjectFields = []string{"id", "type", "title", "username", "first_name", "last_name", "is_forum"}
+
+// chatIDParams are the parameters that name a chat by ID or, for public
+// chats, by "@username".
+var chatIDParams = []string{"chat_id", "from_chat_id"}
+
+// resolveUsernames replaces the "@username" chat IDs of a call with the
+// IDs of the known chats that have the username, so the call works on the
+// same chat as calls using its ID. Unknown usernames fail with chat not
+// found, as in Telegram.
+func resolveUsernames(st *session.State, params map[string]interface{}) *tgerrors.Error {
+	for _, name := range chatIDParams {
+		chatID, ok := params[name].(string)
+		if !ok || !strings.HasPrefix(chatID, "@") {
+			continue
+		}
+		resolved, ok := st.Chats.Username(strings.TrimPrefix(chatID, "@"))
+		if !ok {
+			return tgerrors.ChatNotFound()
+		}
+		id, _ := strconv.ParseInt(resolved, 10, 64)
+		params[name] = id
+	}
+	return nil
+}
+
+// applyMessageChats overlays what is known about the chats of the messages
+// in a result, such as the username and title of a seeded channel, onto
+// their generated chats.
+func applyMessageChats(st *session.State, result interface{}) {
+	if album, ok := result.([]interface{}); ok {
+		for _, msg := range album {
+			applyMessageChats(st, msg)
+		}
+		return
+	}
+	msg, ok := result.(map[string]interface{})
+	if !ok {
+		return
+	}
+	chat, ok := msg["chat"].(map[string]interface{})
+	if _, isMessage := msg["message_id"]; !ok || !isMessage {
+		return
+	}
+	known, _ := st.Chats.Get(messages.ChatKey(chat["id"]))
+	for _, name := range chatObjectFields {
+		if v, ok := known[name]; ok && v != nil && name != "id" {
+			chat[name] = v
+		}
+	}
+}
+
+// chatObject returns the Chat a chat ID refers to: generated, with what is
+// known about the chat. Usernames become the chat's username.
+func chatObject(st *session.State, chatID string) map[string]interface{} {
+	params := map[string]interface{}{}
+	if id, err := strconv.ParseInt(chatID, 10, 64); err == nil {
+		params["chat_id"] = id
+	}
+	chat, _ := st.Faker.Generate("Chat", params).(map[string]interface{})
+	if chat == nil {
+		chat = map[string]interface{}{}
+	}
+	if strings.HasPrefix(chatID, "@") {
+		chat["username"] = strings.TrimPrefix(chatID, "@")
+	}
+	known, _ := st.Chats.Get(chatID)
+	for _, name := range chatObjectFields {
+		if v, ok := known[name]; ok && v != nil {
+			chat[name] = v
+		}
+	}
+	return chat
+}
+
+// seedChats adds the chats of the config file to a new session.
+func seedChats(st *session.State, seeds []map[string]interface{}) {
+	for _, chat := range seeds {
+		if _, err := st.Chats.Seed(chat); err != nil {
+			log.Printf("tg-mock: ignoring chat %v: %v", chat["id"], err)
+		}
+	}
+}
+
+// supergroupOffset makes supergroup IDs out of group IDs: a group -N
+// becomes the supergroup -100N (for N below 10^12), as in Telegram.
+const supergroupOffset = 1000000000000
+
+// migrateChat completes an error telling that a group was upgraded to a
+// supergroup. Without a migrate_to_chat_id, the supergroup's ID is derived
+// from the group's. Basic groups are remembered as migrated, so later calls
+// to them fail the same way until the bot switches to the supergroup.
+func migrateChat(st *session.State, params map[string]interface{}, resp *tgerrors.Error) *tgerrors.Error {
+	if resp.Description != tgerrors.GroupUpgraded().Description {
+		return resp
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	groupID, err := strconv.ParseInt(chatID, 10, 64)
+	basicGroup := err == nil && groupID < 0 && groupID > -supergroupOffset
+
+	if resp.MigrateToChatID == 0 {
+		migrated := *resp
+		if id, ok := int64Value(resp.Parameters["migrate_to_chat_id"]); ok && id != 0 {
+			migrated.MigrateToChatID = id
+		} else if basicGroup {
+			migrated.MigrateToChatID = groupID - supergroupOffset
+		} else {
+			migrated.MigrateToChatID = -supergroupOffset - st.Faker.NextChatID()
+		}
+		resp = &migrated
+	}
+	if basicGroup {
+		st.Chats.Set(chatID, "migrate_to_chat_id", resp.MigrateToChatID)
+		st.Chats.Set(strconv.FormatInt(resp.MigrateToChatID, 10), "type", "supergroup")
+	}
+	return resp
+}
+
+// migratedChat returns the error of calls to a group that was upgraded to
+// a supergroup.
+func migratedChat(st *session.State, params map[string]interface{}) *tgerrors.Error {
+	chatID := messages.ChatKey(params["chat_id"])
+	if chatID == "" {
+		return nil
+	}
+	v, _ := st.Chats.Field(chatID, "migrate_to_chat_id")
+	id, ok := v.(float64)
+	if !ok {
+		return nil
+	}
+	return tgerrors.GroupMigrated(int64(id))
+}
+
+// groupChat reports whether a chat_id names a group or channel rather
+// than a private chat: their IDs are negative, and only they have public
+// usernames bots can write to.
+func groupChat(chatID string) bool {
+	return strings.HasPrefix(chatID, "-") || strings.HasPrefix(chatID, "@")
+}
+
+// announceChatChange sends the service message Telegram posts when a bot
+// renames a chat.
+func (h *BotHandler) announceChatChange(st *session.State, token, method string, params map[string]interface{}) {
+	if method != "setChatTitle" {
+		return
+	}
+	title, _ := params["title"].(string)
+	chatID := messages.ChatKey(params["chat_id"])
+
+	chat := map[string]interface{}{"type": "supergroup", "title": title}
+	if typ, ok := st.Chats.Field(chatID, "type"); ok && typ != nil {
+		chat["type"] = typ
+	}
+	if id, err := strconv.ParseInt(chatID, 10, 64); err == nil {
+		chat["id"] = id
+	} else {
+		chat["id"] = -st.Faker.NextChatID()
+		chat["username"] = strings.TrimPrefix(chatID, "@")
+	}
+
+	msg := map[string]interface{}{
+		"message_id":     st.Faker.NextMessageID(),
+		"date":           st.Faker.Now().Unix(),
+		"chat":           chat,
+		"new_chat_title": title,
+	}
+	updateType := "message"
+	if chat["type"] == "channel" {
+		updateType = "channel_post"
+	}
+	h.deliverUpdates(st, token, []map[string]interface{}{{updateType: msg}})
+}
@@ -0,0 +1,40 @@
+// internal/server/constraints.go
+package server
+
+import (
+	"unicode/utf8"
+
+	"github.com/watzon/tg-mock/gen"
+)
+
+// fieldConstraint returns the constraint the spec states for the field
+// name among fields, or nil if it states none.
+func fieldConstraint(fields []gen.FieldSpec, name string) *gen.Constraint {
+	for _, f := range fields {
+		if f.Name == name {
+			return f.Constraint
+		}
+	}
+	return nil
+}
+
+// typeConstraint returns the constraint of a field of a spec type.
+func typeConstraint(typeName, name string) *gen.Constraint {
+	return fieldConstraint(gen.Types[typeName].Fields, name)
+}
+
+// inBounds reports whether n is within the bounds of c. Constraints
+// without bounds allow any n.
+func inBounds(n int64, c *gen.Constraint) bool {
+	return c == nil || c.Max == 0 || (n >= c.Min && n <= c.Max)
+}
+
+// fitsLength reports whether the length of s, in the unit of c, is within
+// its bounds.
+func fitsLength(s string, c *gen.Constraint) bool {
+	n := int64(len(s))
+	if c != nil && c.Unit == "characters" {
+		n = int64(utf8.RuneCountInString(s))
+	}
+	return inBounds(n, c)
+}
@@ -2,44 +2,122 @@
 package server
 
 import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
 	"net/http"
+	"net/http/httptest"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/adversarial"
+	"github.com/watzon/tg-mock/internal/apiversion"
+	"github.com/watzon/tg-mock/internal/archive"
+	"github.com/watzon/tg-mock/internal/boosts"
+	"github.com/watzon/tg-mock/internal/botgroup"
+	"github.com/watzon/tg-mock/internal/chaos"
+	"github.com/watzon/tg-mock/internal/chats"
+	"github.com/watzon/tg-mock/internal/clock"
+	"github.com/watzon/tg-mock/internal/compat"
+	"github.com/watzon/tg-mock/internal/events"
+	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/floodlimit"
+	"github.com/watzon/tg-mock/internal/guard"
 	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/instance"
+	"github.com/watzon/tg-mock/internal/latency"
+	"github.com/watzon/tg-mock/internal/members"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/outage"
+	"github.com/watzon/tg-mock/internal/persona"
+	"github.com/watzon/tg-mock/internal/polls"
 	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/script"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/storage"
 	"github.com/watzon/tg-mock/internal/tokens"
-	"github.com/watzon/tg-mock/internal/updates"
+	"github.com/watzon/tg-mock/internal/users"
 	"github.com/watzon/tg-mock/internal/webhook"
 )
 
+const (
+	// defaultWaitTimeout is used by /requests/wait when no timeout is given.
+	defaultWaitTimeout = 5 * time.Second
+	// maxWaitTimeout caps how long a single wait request may block.
+	maxWaitTimeout = 2 * time.Minute
+)
+
+// Lifecycle lets the control API stop or restart the server it belongs to.
+type Lifecycle interface {
+	// RequestShutdown begins a graceful shutdown without blocking.
+	RequestShutdown()
+	// Restart returns the server to its startup state.
+	Restart()
+}
+
 type ControlHandler struct {
-	scenarios *scenario.Engine
-	tokens    *tokens.Registry
-	updates   *updates.Queue
-	requests  *inspector.Recorder
-	webhooks  *webhook.Registry
+	sessions     *session.Manager
+	tokens       *tokens.Registry
+	webhooks     *webhook.Registry
+	files        storage.Store
+	events       *events.Bus
+	guard        *guard.Guard
+	groups       *botgroup.Registry
+	instances    *instance.Registry
+	bots         *BotHandler
+	clock        *clock.Clock
+	lifecycle    Lifecycle
+	controlToken string
 }
 
-func NewControlHandler(scenarios *scenario.Engine, tokens *tokens.Registry, updates *updates.Queue, requests *inspector.Recorder, webhooks *webhook.Registry) *ControlHandler {
+func NewControlHandler(sessions *session.Manager, tokens *tokens.Registry, webhooks *webhook.Registry, files storage.Store, events *events.Bus, guard *guard.Guard, groups *botgroup.Registry, instances *instance.Registry, bots *BotHandler, clk *clock.Clock, lifecycle Lifecycle, controlToken string) *ControlHandler {
 	return &ControlHandler{
-		scenarios: scenarios,
-		tokens:    tokens,
-		updates:   updates,
-		requests:  requests,
-		webhooks:  webhooks,
+		sessions:     sessions,
+		tokens:       tokens,
+		webhooks:     webhooks,
+		files:        files,
+		events:       events,
+		guard:        guard,
+		groups:       groups,
+		instances:    instances,
+		bots:         bots,
+		clock:        clk,
+		lifecycle:    lifecycle,
+		controlToken: controlToken,
 	}
 }
 
+// session returns the session selected for the request, falling back
+// to the default session.
+func (h *ControlHandler) session(r *http.Request) *session.State {
+	if st := session.FromContext(r.Context()); st != nil {
+		return st
+	}
+	return h.sessions.Default()
+}
+
 func (h *ControlHandler) Routes() chi.Router {
 	r := chi.NewRouter()
+	r.Use(h.authenticate)
 
 	// Scenarios
 	r.Route("/scenarios", func(r chi.Router) {
 		r.Get("/", h.listScenarios)
 		r.Post("/", h.addScenario)
 		r.Delete("/", h.clearScenarios)
+		r.Get("/history", h.scenarioHistory)
+		r.Put("/{id}", h.updateScenario)
 		r.Delete("/{id}", h.removeScenario)
 	})
 
@@ -48,6 +126,12 @@ func (h *ControlHandler) Routes() chi.Router {
 		r.Post("/", h.registerToken)
 		r.Delete("/{token}", h.deleteToken)
 		r.Patch("/{token}", h.updateToken)
+		r.Get("/{token}/budget", h.getBudget)
+		r.Put("/{token}/budget", h.setBudget)
+		r.Delete("/{token}/budget", h.deleteBudget)
+		r.Get("/{token}/concurrency", h.getConcurrency)
+		r.Put("/{token}/concurrency", h.setConcurrency)
+		r.Delete("/{token}/concurrency", h.deleteConcurrency)
 		// Per-token update injection with webhook routing
 		r.Post("/{token}/updates", h.injectTokenUpdate)
 	})
@@ -56,6 +140,7 @@ func (h *ControlHandler) Routes() chi.Router {
 	r.Route("/updates", func(r chi.Router) {
 		r.Get("/", h.listUpdates)
 		r.Post("/", h.addUpdate)
+		r.Post("/generate", h.generateUpdate)
 		r.Delete("/", h.clearUpdates)
 	})
 
@@ -72,22 +157,249 @@ func (h *ControlHandler) Routes() chi.Router {
 	r.Route("/requests", func(r chi.Router) {
 		r.Get("/", h.listRequests)
 		r.Delete("/", h.clearRequests)
+		r.Get("/wait", h.waitRequests)
+		r.Post("/{id}/replay", h.replayRequest)
+	})
+
+	// Messages sent by bots, in their current state
+	r.Route("/messages", func(r chi.Router) {
+		r.Get("/", h.listMessages)
+		r.Delete("/", h.clearMessages)
+		r.Get("/{chat_id}/{message_id}", h.getMessage)
+	})
+
+	// Per-chat activity archive
+	r.Get("/archive/{chat_id}", h.exportArchive)
+
+	// Chat action visibility
+	r.Route("/chats", func(r chi.Router) {
+		r.Get("/", h.listChats)
+		r.Post("/", h.seedChat)
+		r.Delete("/", h.clearChats)
+		r.Get("/{chat_id}", h.getChat)
+		r.Delete("/{chat_id}", h.deleteChat)
+	})
+
+	r.Route("/users", func(r chi.Router) {
+		r.Get("/", h.listUsers)
+		r.Post("/", h.seedUser)
+		r.Delete("/", h.clearUsers)
+		r.Get("/{user_id}", h.getUser)
+		r.Delete("/{user_id}", h.deleteUser)
+	})
+
+	r.Route("/members", func(r chi.Router) {
+		r.Get("/", h.listMembers)
+		r.Get("/{chat_id}", h.listMembers)
+		r.Get("/{chat_id}/{user_id}", h.getMember)
+		r.Put("/{chat_id}/{user_id}", h.setMember)
+		r.Delete("/{chat_id}/{user_id}", h.deleteMember)
+	})
+
+	r.Route("/invite-links", func(r chi.Router) {
+		r.Get("/", h.listInviteLinks)
+		r.Get("/{chat_id}", h.listInviteLinks)
+	})
+
+	r.Route("/topics", func(r chi.Router) {
+		r.Get("/", h.listTopics)
+		r.Get("/{chat_id}", h.listTopics)
+	})
+
+	r.Route("/polls", func(r chi.Router) {
+		r.Get("/", h.listPolls)
+		r.Get("/{poll_id}", h.getPoll)
+		r.Post("/{poll_id}/answers", h.answerPoll)
+	})
+
+	r.Get("/games", h.listGames)
+
+	r.Route("/sticker-sets", func(r chi.Router) {
+		r.Get("/", h.listStickerSets)
+		r.Get("/{name}", h.getStickerSet)
+	})
+
+	r.Route("/boosts", func(r chi.Router) {
+		r.Get("/", h.listBoosts)
+		r.Get("/{chat_id}", h.listBoosts)
+		r.Post("/{chat_id}", h.seedBoost)
+		r.Delete("/{chat_id}/{boost_id}", h.deleteBoost)
+	})
+
+	r.Route("/stars", func(r chi.Router) {
+		r.Get("/", h.listStarLedgers)
+		r.Get("/{token}", h.getStarLedger)
+		r.Post("/{token}", h.addStarTransaction)
+		r.Delete("/{token}", h.deleteStarLedger)
+	})
+
+	r.Route("/bots", func(r chi.Router) {
+		r.Get("/", h.listBots)
+		r.Get("/{token}", h.getBot)
+	})
+
+	r.Route("/chat-actions", func(r chi.Router) {
+		r.Get("/", h.listChatActions)
+		r.Get("/{chat_id}", h.getChatAction)
+	})
+
+	// Inline query answer deadlines
+	r.Route("/inline-queries", func(r chi.Router) {
+		r.Get("/", h.listInlineQueries)
+		r.Get("/{query_id}", h.getInlineQuery)
+	})
+
+	// Callback query answer deadlines, and button presses
+	r.Get("/callback-queries", h.listCallbackQueries)
+	r.Post("/simulate/callback", h.simulateCallback)
+	r.Post("/simulate/boost", h.simulateBoost)
+	r.Post("/simulate/boost-removal", h.simulateBoostRemoval)
+
+	// Complete updates made up from a few fields
+	r.Post("/simulate/{kind}", h.simulateUpdate)
+
+	// Scripted conversations with the bot
+	r.Post("/conversations", h.runConversation)
+
+	// Auto-responder personas
+	r.Route("/personas", func(r chi.Router) {
+		r.Get("/", h.listPersonas)
+		r.Delete("/", h.clearPersonas)
+		r.Get("/{chat_id}", h.getPersona)
+		r.Put("/{chat_id}", h.setPersona)
+		r.Delete("/{chat_id}", h.deletePersona)
+	})
+
+	// Response perturbation for client tolerance tests
+	r.Get("/compat", h.getCompat)
+	r.Put("/compat", h.setCompat)
+	r.Delete("/compat", h.deleteCompat)
+
+	// Adversarial responses for hardening client deserializers
+	r.Get("/adversarial", h.getAdversarial)
+	r.Put("/adversarial", h.setAdversarial)
+	r.Delete("/adversarial", h.deleteAdversarial)
+
+	// Bursts of server errors, as during Telegram restarts
+	r.Get("/chaos", h.getChaos)
+	r.Put("/chaos", h.setChaos)
+	r.Delete("/chaos", h.deleteChaos)
+	r.Get("/outage", h.getOutage)
+	r.Post("/outage", h.startOutage)
+	r.Delete("/outage", h.stopOutage)
+
+	// Telegram's flood limits
+	r.Get("/flood-limits", h.getFloodLimits)
+	r.Put("/flood-limits", h.setFloodLimits)
+	r.Delete("/flood-limits", h.deleteFloodLimits)
+	r.Get("/retry-after", h.getRetryAfter)
+	r.Put("/retry-after", h.setRetryAfter)
+	r.Delete("/retry-after", h.clearRetryAfter)
+
+	// Server-wide response latency
+	r.Get("/latency", h.getLatency)
+	r.Put("/latency", h.setLatency)
+	r.Delete("/latency", h.deleteLatency)
+
+	// API version simulation
+	r.Get("/api-version", h.getAPIVersion)
+	r.Put("/api-version", h.setAPIVersion)
+
+	// The mock clock, shared by all sessions
+	r.Get("/clock", h.getClock)
+	r.Put("/clock", h.setClock)
+	r.Post("/clock/advance", h.advanceClock)
+	r.Delete("/clock", h.resetClock)
+
+	// JSON Schema of the spec's types
+	r.Get("/schema/{type}", h.getSchema)
+
+	// Fixtures of the spec's types
+	r.Get("/generate/{type}", h.generateFixture)
+
+	// Group chats shared by several bots
+	r.Route("/bot-groups", func(r chi.Router) {
+		r.Get("/", h.listBotGroups)
+		r.Delete("/", h.clearBotGroups)
+		r.Get("/{chat_id}", h.getBotGroup)
+		r.Put("/{chat_id}", h.setBotGroup)
+		r.Delete("/{chat_id}", h.deleteBotGroup)
+	})
+
+	// Statistics
+	r.Get("/stats", h.getStats)
+	r.Get("/report", h.getReport)
+
+	// Orchestration event webhooks
+	r.Route("/events/webhooks", func(r chi.Router) {
+		r.Get("/", h.listEventWebhooks)
+		r.Post("/", h.addEventWebhook)
+		r.Delete("/", h.clearEventWebhooks)
+		r.Delete("/{id}", h.removeEventWebhook)
+	})
+
+	// Sessions
+	r.Route("/sessions", func(r chi.Router) {
+		r.Get("/", h.listSessions)
+		r.Delete("/{name}", h.deleteSession)
+	})
+
+	// Ephemeral instances
+	r.Route("/instances", func(r chi.Router) {
+		r.Get("/", h.listInstances)
+		r.Post("/", h.createInstance)
+		r.Get("/{id}", h.getInstance)
+		r.Delete("/{id}", h.deleteInstance)
 	})
 
 	// State
 	r.Post("/reset", h.reset)
 	r.Get("/state", h.getState)
+	r.Get("/snapshot", h.exportSnapshot)
+	r.Post("/snapshot", h.importSnapshot)
+
+	// Lifecycle
+	r.Post("/shutdown", h.shutdown)
+	r.Post("/restart", h.restart)
 
 	return r
 }
 
+// authenticate rejects control requests without the configured control token.
+// The token is accepted as a bearer token or in the X-TG-Mock-Control-Token header.
+func (h *ControlHandler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.controlToken == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		provided := r.Header.Get("X-TG-Mock-Control-Token")
+		if auth := r.Header.Get("Authorization"); provided == "" && strings.HasPrefix(auth, "Bearer ") {
+			provided = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(h.controlToken)) != 1 {
+			http.Error(w, "invalid or missing control token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Scenarios handlers
 
 func (h *ControlHandler) listScenarios(w http.ResponseWriter, r *http.Request) {
-	scenarios := h.scenarios.List()
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"scenarios": scenarios,
+	offset, ok := pageOffset(r)
+	if !ok {
+		http.Error(w, "invalid cursor", http.StatusBadRequest)
+		return
+	}
+	scenarios := h.session(r).Scenarios.List()
+	start, end, next := pageBounds(len(scenarios), offset, pageLimit(r, 0))
+	writeListing(w, r, map[string]interface{}{
+		"scenarios":   scenarios[start:end],
+		"count":       len(scenarios),
+		"next_cursor": next,
 	})
 }
 
@@ -97,8 +409,12 @@ func (h *ControlHandler) addScenario(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if err := validateScript(&s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-	id := h.scenarios.Add(&s)
+	id := h.session(r).Scenarios.Add(&s)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
@@ -106,14 +422,71 @@ func (h *ControlHandler) addScenario(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// validateScript checks the script of a scenario. A scenario answering
+// with an error never runs its script, so the two can't be combined.
+func validateScript(s *scenario.Scenario) error {
+	if !s.HasScript() {
+		return nil
+	}
+	if s.IsError() {
+		return fmt.Errorf("script can't be combined with response")
+	}
+	if err := script.Compile(s.Script); err != nil {
+		return fmt.Errorf("invalid script: %w", err)
+	}
+	return nil
+}
+
 func (h *ControlHandler) clearScenarios(w http.ResponseWriter, r *http.Request) {
-	h.scenarios.Clear()
+	h.session(r).Scenarios.Clear()
 	w.WriteHeader(http.StatusNoContent)
 }
 
+func (h *ControlHandler) updateScenario(w http.ResponseWriter, r *http.Request) {
+	var s scenario.Scenario
+	if err := json.NewDecoder(r.Body).Decode(&s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := validateScript(&s); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !h.session(r).Scenarios.Update(chi.URLParam(r, "id"), &s) {
+		http.Error(w, "scenario not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(&s)
+}
+
+// scenarioHistory returns the changes to the scenario set between two
+// revisions, so the scenario_revision of two recorded requests can be
+// compared: the changes in (from, to] explain any difference.
+func (h *ControlHandler) scenarioHistory(w http.ResponseWriter, r *http.Request) {
+	var from, to int64
+	for name, dst := range map[string]*int64{"from": &from, "to": &to} {
+		if v := r.URL.Query().Get(name); v != "" {
+			parsed, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || parsed < 0 {
+				http.Error(w, "invalid "+name, http.StatusBadRequest)
+				return
+			}
+			*dst = parsed
+		}
+	}
+
+	engine := h.session(r).Scenarios
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"revision": engine.Revision(),
+		"changes":  engine.History(from, to),
+	})
+}
+
 func (h *ControlHandler) removeScenario(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
-	if h.scenarios.Remove(id) {
+	if h.session(r).Scenarios.Remove(id) {
 		w.WriteHeader(http.StatusNoContent)
 	} else {
 		http.Error(w, "scenario not found", http.StatusNotFound)
@@ -124,9 +497,10 @@ func (h *ControlHandler) removeScenario(w http.ResponseWriter, r *http.Request)
 
 func (h *ControlHandler) registerToken(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Token   string        `json:"token"`
-		Status  tokens.Status `json:"status"`
-		BotName string        `json:"bot_name"`
+		Token   string             `json:"token"`
+		Status  tokens.Status      `json:"status"`
+		BotName string             `json:"bot_name"`
+		Profile *tokens.BotProfile `json:"profile"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -140,6 +514,7 @@ func (h *ControlHandler) registerToken(w http.ResponseWriter, r *http.Request) {
 	h.tokens.Register(req.Token, tokens.TokenInfo{
 		Status:  req.Status,
 		BotName: req.BotName,
+		Profile: req.Profile,
 	})
 
 	w.WriteHeader(http.StatusCreated)
@@ -169,170 +544,2055 @@ func (h *ControlHandler) updateToken(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Updates handlers
-
-func (h *ControlHandler) listUpdates(w http.ResponseWriter, r *http.Request) {
-	updates := h.updates.Get(0, 100)
+func (h *ControlHandler) getBudget(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	budget, ok := h.tokens.GetBudget(token)
+	if !ok {
+		http.Error(w, "budget not found", http.StatusNotFound)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"updates": updates,
-		"pending": h.updates.Pending(),
-	})
+	json.NewEncoder(w).Encode(budget)
 }
 
-func (h *ControlHandler) addUpdate(w http.ResponseWriter, r *http.Request) {
-	var update map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+func (h *ControlHandler) setBudget(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	var budget tokens.Budget
+	if err := json.NewDecoder(r.Body).Decode(&budget); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	if budget.Calls < 0 || budget.Used < 0 {
+		http.Error(w, "calls and used must not be negative", http.StatusBadRequest)
+		return
+	}
 
-	id := h.updates.Add(update)
-	w.Header().Set("Content-Type", "application/json")
+	h.tokens.SetBudget(token, budget)
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"update_id": id,
-	})
 }
 
-func (h *ControlHandler) clearUpdates(w http.ResponseWriter, r *http.Request) {
-	h.updates.Clear()
+func (h *ControlHandler) deleteBudget(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	h.tokens.DeleteBudget(token)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// Requests handlers
+func (h *ControlHandler) getConcurrency(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	status, ok := h.tokens.GetConcurrency(token)
+	if !ok {
+		http.Error(w, "concurrency limit not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
 
-func (h *ControlHandler) listRequests(w http.ResponseWriter, r *http.Request) {
-	method := r.URL.Query().Get("method")
-	token := r.URL.Query().Get("token")
-	limit := 100
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
-			limit = parsed
-		}
+func (h *ControlHandler) setConcurrency(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	var limit tokens.ConcurrencyLimit
+	if err := json.NewDecoder(r.Body).Decode(&limit); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if limit.Limit < 1 {
+		http.Error(w, "limit must be at least 1", http.StatusBadRequest)
+		return
+	}
+	if limit.QueueTimeoutMs < 0 || limit.RetryAfter < 0 {
+		http.Error(w, "queue_timeout_ms and retry_after must not be negative", http.StatusBadRequest)
+		return
+	}
+	switch limit.Mode {
+	case "", tokens.ConcurrencyQueue, tokens.ConcurrencyReject:
+	default:
+		http.Error(w, "mode must be queue or reject", http.StatusBadRequest)
+		return
 	}
 
-	requests := h.requests.List(method, token, limit)
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"requests": requests,
-		"count":    h.requests.Count(),
-	})
+	h.tokens.SetConcurrency(token, limit)
+	w.WriteHeader(http.StatusCreated)
 }
 
-func (h *ControlHandler) clearRequests(w http.ResponseWriter, r *http.Request) {
-	h.requests.Clear()
+func (h *ControlHandler) deleteConcurrency(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	h.tokens.DeleteConcurrency(token)
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// State handlers
+// Updates handlers
 
-func (h *ControlHandler) reset(w http.ResponseWriter, r *http.Request) {
-	h.scenarios.Clear()
-	h.updates.Clear()
-	h.requests.Clear()
-	h.webhooks.Clear()
-	w.WriteHeader(http.StatusNoContent)
-}
+func (h *ControlHandler) listUpdates(w http.ResponseWriter, r *http.Request) {
+	var cursor int64
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		parsed, err := strconv.ParseInt(c, 10, 64)
+		if err != nil || parsed < 1 {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+		cursor = parsed
+	}
+	limit := pageLimit(r, 100)
 
-func (h *ControlHandler) getState(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"scenarios_count":   len(h.scenarios.List()),
-		"updates_pending":   h.updates.Pending(),
-		"requests_recorded": h.requests.Count(),
-		"webhooks_count":    len(h.webhooks.List()),
+	// Updates are paged by update_id, like getUpdates offsets, so pages
+	// stay stable while updates are consumed
+	queue := h.session(r).Updates
+	updates := queue.Get(cursor, limit+1)
+	var next interface{}
+	if len(updates) > limit {
+		updates = updates[:limit]
+		next = strconv.FormatInt(updates[limit-1]["update_id"].(int64)+1, 10)
+	}
+	writeListing(w, r, map[string]interface{}{
+		"updates":     updates,
+		"pending":     queue.Pending(),
+		"count":       queue.Pending(),
+		"next_cursor": next,
 	})
 }
 
-// Webhook handlers
+func (h *ControlHandler) addUpdate(w http.ResponseWriter, r *http.Request) {
+	var update map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
-func (h *ControlHandler) listWebhooks(w http.ResponseWriter, r *http.Request) {
-	webhooks := h.webhooks.List()
+	st := h.session(r)
+	if !h.admitUpdate(w, st) {
+		return
+	}
+
+	trackQueries(st, update)
+	id := st.Updates.Add(update)
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"webhooks": webhooks,
-		"count":    len(webhooks),
+		"update_id": id,
 	})
 }
 
-func (h *ControlHandler) clearWebhooks(w http.ResponseWriter, r *http.Request) {
-	h.webhooks.Clear()
-	w.WriteHeader(http.StatusNoContent)
-}
+// generateUpdate queues a complete update of the requested kind, made up by
+// the faker. The other fields of the request, such as chat_id, user_id,
+// and text, shape the update.
+func (h *ControlHandler) generateUpdate(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	kind, _ := req["kind"].(string)
+	if kind == "" {
+		kind = "message"
+	}
+	delete(req, "kind")
 
-func (h *ControlHandler) getWebhook(w http.ResponseWriter, r *http.Request) {
-	token := chi.URLParam(r, "token")
-	cfg := h.webhooks.Get(token)
-	if cfg == nil {
-		http.Error(w, "webhook not found", http.StatusNotFound)
+	st := h.session(r)
+	update, err := st.Faker.GenerateUpdate(kind, req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("%v; want one of %s", err, strings.Join(faker.UpdateKinds(), ", ")), http.StatusBadRequest)
+		return
+	}
+	if !h.admitUpdate(w, st) {
 		return
 	}
+
+	// The queue numbers the update
+	delete(update, "update_id")
+	trackQueries(st, update)
+	id := st.Updates.Add(update)
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(cfg)
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"update_id": id,
+		"update":    update,
+	})
 }
 
-func (h *ControlHandler) setWebhook(w http.ResponseWriter, r *http.Request) {
-	token := chi.URLParam(r, "token")
-
-	var cfg webhook.Config
-	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+// admitUpdate applies the queue memory limit before an update is queued,
+// responding with 507 Insufficient Storage if the update is rejected.
+func (h *ControlHandler) admitUpdate(w http.ResponseWriter, st *session.State) bool {
+	if err := h.guard.Admit(guard.Queue, st.Name, st.Updates); err != nil {
+		http.Error(w, "update queue memory limit reached", http.StatusInsufficientStorage)
+		return false
 	}
+	return true
+}
 
-	h.webhooks.Set(token, &cfg)
-	w.WriteHeader(http.StatusCreated)
+// trackQueries starts the answer deadline of an injected inline or
+// callback query.
+func trackQueries(st *session.State, update map[string]interface{}) {
+	if query, ok := update["inline_query"].(map[string]interface{}); ok {
+		if id, ok := query["id"].(string); ok && id != "" {
+			st.InlineQueries.Track(id)
+		}
+	}
+	if query, ok := update["callback_query"].(map[string]interface{}); ok {
+		data, _ := query["data"].(string)
+		if id, ok := query["id"].(string); ok && id != "" {
+			st.Callbacks.Track(id, data)
+		}
+	}
 }
 
-func (h *ControlHandler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
-	token := chi.URLParam(r, "token")
-	h.webhooks.Delete(token)
+func (h *ControlHandler) clearUpdates(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Updates.Clear()
 	w.WriteHeader(http.StatusNoContent)
 }
 
-// injectTokenUpdate injects an update for a specific token, routing to webhook if active
-func (h *ControlHandler) injectTokenUpdate(w http.ResponseWriter, r *http.Request) {
-	token := chi.URLParam(r, "token")
+// Requests handlers
 
-	var update map[string]interface{}
-	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+// listRequests returns matching requests newest first, a page at a time.
+// The next_cursor of a response is passed as cursor to fetch the next page.
+func (h *ControlHandler) listRequests(w http.ResponseWriter, r *http.Request) {
+	filter, err := requestFilter(r)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	limit := pageLimit(r, 100)
 
-	w.Header().Set("Content-Type", "application/json")
-
-	// Check if webhook is active for this token
-	if h.webhooks.IsActive(token) {
-		// Deliver via webhook
-		result, err := h.webhooks.Deliver(token, update)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+	var cursor int64
+	if c := r.URL.Query().Get("cursor"); c != "" {
+		parsed, err := strconv.ParseInt(c, 10, 64)
+		if err != nil || parsed < 1 {
+			http.Error(w, "invalid cursor", http.StatusBadRequest)
 			return
 		}
+		cursor = parsed
+	}
 
-		response := map[string]interface{}{
-			"delivered":   true,
-			"success":     result.Success,
-			"status_code": result.StatusCode,
-			"duration_ms": result.DurationMs,
-		}
-		if result.Error != "" {
-			response["error"] = result.Error
+	recorder := h.session(r).Recorder
+	requests, next := recorder.Page(filter, cursor, limit)
+	var nextCursor interface{}
+	if next > 0 {
+		nextCursor = strconv.FormatInt(next, 10)
+	}
+	writeListing(w, r, map[string]interface{}{
+		"requests":    requests,
+		"count":       recorder.Count(),
+		"next_cursor": nextCursor,
+	})
+}
+
+// waitRequests blocks until enough matching requests have been recorded or
+// the timeout elapses, so tests don't have to poll listRequests in a loop.
+func (h *ControlHandler) waitRequests(w http.ResponseWriter, r *http.Request) {
+	filter, err := requestFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	count := 1
+	if c := r.URL.Query().Get("count"); c != "" {
+		parsed, err := strconv.Atoi(c)
+		if err != nil || parsed < 1 {
+			http.Error(w, "invalid count", http.StatusBadRequest)
+			return
 		}
-		// Include method result if webhook returned a method call
-		if result.MethodResult != nil {
-			response["method_result"] = result.MethodResult
+		count = parsed
+	}
+	timeout := defaultWaitTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		parsed, err := parseWaitTimeout(t)
+		if err != nil {
+			http.Error(w, "invalid timeout", http.StatusBadRequest)
+			return
 		}
-		json.NewEncoder(w).Encode(response)
-	} else {
-		// Queue for polling
-		id := h.updates.Add(update)
-		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"queued":    true,
-			"update_id": id,
-		})
+		timeout = parsed
+	}
+
+	extendWriteDeadline(w, timeout)
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	recorder := h.session(r).Recorder
+	requests, err := recorder.Wait(ctx, filter, count)
+	w.Header().Set("Content-Type", "application/json")
+	if err != nil {
+		w.WriteHeader(http.StatusRequestTimeout)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"requests": requests,
+		"count":    recorder.Count(),
+	})
+}
+
+// replayRequest sends a recorded request through the Bot API handler
+// again, in the same session, so it meets the current scenarios and state.
+// The body may override the token and individual parameters; a null
+// parameter removes it.
+func (h *ControlHandler) replayRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid request ID", http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	rec, ok := st.Recorder.Get(id)
+	if !ok {
+		http.Error(w, "request not found", http.StatusNotFound)
+		return
+	}
+
+	var overrides struct {
+		Token  string                 `json:"token"`
+		Params map[string]interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&overrides); err != nil && err != io.EOF {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token := rec.Token
+	if overrides.Token != "" {
+		token = overrides.Token
+	}
+	params := make(map[string]interface{}, len(rec.Params)+len(overrides.Params))
+	for k, v := range rec.Params {
+		params[k] = v
+	}
+	for k, v := range overrides.Params {
+		if v == nil {
+			delete(params, k)
+		} else {
+			params[k] = v
+		}
+	}
+
+	body, err := json.Marshal(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	req, err := http.NewRequestWithContext(r.Context(), http.MethodPost, "/bot"+token+"/"+rec.Method, bytes.NewReader(body))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	// Header-based scenarios were chosen by the client, so they apply again
+	if name, ok := strings.CutPrefix(rec.ScenarioID, "header:"); ok {
+		req.Header.Set("X-TG-Mock-Scenario", name)
+	}
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	rctx.URLParams.Add("method", rec.Method)
+	ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+	ctx = session.WithState(ctx, st)
+
+	rw := httptest.NewRecorder()
+	h.bots.Handle(rw, req.WithContext(ctx))
+
+	var response interface{}
+	json.Unmarshal(rw.Body.Bytes(), &response)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"replay_of":   id,
+		"token":       token,
+		"method":      rec.Method,
+		"params":      params,
+		"status_code": rw.Code,
+		"response":    response,
+	})
+}
+
+// requestFilter reads the recorder filter from the query parameters
+// method, token, scenario_id, since, until, status_code, and is_error.
+func requestFilter(r *http.Request) (inspector.Filter, error) {
+	q := r.URL.Query()
+	filter := inspector.Filter{
+		Method:     q.Get("method"),
+		Token:      q.Get("token"),
+		ScenarioID: q.Get("scenario_id"),
+	}
+
+	var err error
+	if s := q.Get("since"); s != "" {
+		if filter.Since, err = parseTimestamp(s); err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if s := q.Get("until"); s != "" {
+		if filter.Until, err = parseTimestamp(s); err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	if s := q.Get("status_code"); s != "" {
+		if filter.StatusCode, err = strconv.Atoi(s); err != nil {
+			return filter, fmt.Errorf("invalid status_code: %w", err)
+		}
+	}
+	if s := q.Get("is_error"); s != "" {
+		isError, err := strconv.ParseBool(s)
+		if err != nil {
+			return filter, fmt.Errorf("invalid is_error: %w", err)
+		}
+		filter.IsError = &isError
+	}
+	return filter, nil
+}
+
+// parseTimestamp accepts an RFC 3339 time or Unix seconds.
+func parseTimestamp(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither RFC 3339 nor Unix seconds", s)
+	}
+	return time.Unix(0, int64(secs*float64(time.Second))), nil
+}
+
+// parseWaitTimeout accepts a Go duration ("5s", "500ms") or a plain number
+// of seconds, clamped to maxWaitTimeout.
+func parseWaitTimeout(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		secs, convErr := strconv.ParseFloat(s, 64)
+		if convErr != nil {
+			return 0, err
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative timeout %q", s)
+	}
+	if d > maxWaitTimeout {
+		d = maxWaitTimeout
+	}
+	return d, nil
+}
+
+func (h *ControlHandler) clearRequests(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Recorder.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Message handlers
+
+func (h *ControlHandler) listMessages(w http.ResponseWriter, r *http.Request) {
+	entries := h.session(r).Messages.List(r.URL.Query().Get("chat_id"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"messages": entries,
+		"count":    len(entries),
+	})
+}
+
+func (h *ControlHandler) getMessage(w http.ResponseWriter, r *http.Request) {
+	messageID, ok := messages.MessageID(chi.URLParam(r, "message_id"))
+	if !ok {
+		http.Error(w, "invalid message_id", http.StatusBadRequest)
+		return
+	}
+	msg, ok := h.session(r).Messages.Get(chi.URLParam(r, "chat_id"), messageID)
+	if !ok {
+		http.Error(w, "message not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(msg)
+}
+
+func (h *ControlHandler) clearMessages(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Messages.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Archive handlers
+
+// exportArchive streams the history of a chat as JSON Lines: injected
+// updates and the bot's calls, oldest first.
+func (h *ControlHandler) exportArchive(w http.ResponseWriter, r *http.Request) {
+	st := h.session(r)
+	chatID := chi.URLParam(r, "chat_id")
+	events := st.Archive.Chat(chatID, st.Recorder.Find(inspector.Filter{}, 0))
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="tg-mock-chat-%s.jsonl"`, sanitizeFilename(chatID)))
+	archive.WriteJSONL(w, events)
+}
+
+// sanitizeFilename keeps the characters of a chat ID that are safe in a
+// file name.
+func sanitizeFilename(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '@' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// Chat handlers
+
+func (h *ControlHandler) listChats(w http.ResponseWriter, r *http.Request) {
+	entries := h.session(r).Chats.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"chats": entries,
+		"count": len(entries),
+	})
+}
+
+func (h *ControlHandler) seedChat(w http.ResponseWriter, r *http.Request) {
+	var chat map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&chat); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	chatID, err := st.Chats.Seed(chat)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	fields, _ := st.Chats.Get(chatID)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(chats.Entry{ChatID: chatID, Fields: fields})
+}
+
+func (h *ControlHandler) clearChats(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Chats.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) getChat(w http.ResponseWriter, r *http.Request) {
+	chatID := chi.URLParam(r, "chat_id")
+	fields, ok := h.session(r).Chats.Get(chatID)
+	if !ok {
+		http.Error(w, "chat not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(chats.Entry{ChatID: chatID, Fields: fields})
+}
+
+func (h *ControlHandler) deleteChat(w http.ResponseWriter, r *http.Request) {
+	if !h.session(r).Chats.Delete(chi.URLParam(r, "chat_id")) {
+		http.Error(w, "chat not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// User handlers
+
+func (h *ControlHandler) listUsers(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Users.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"users": list,
+		"count": len(list),
+	})
+}
+
+func (h *ControlHandler) seedUser(w http.ResponseWriter, r *http.Request) {
+	var user map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	seeded, err := h.session(r).Users.Seed(user)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(seeded)
+}
+
+func (h *ControlHandler) clearUsers(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Users.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) getUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := users.ID(chi.URLParam(r, "user_id"))
+	if !ok {
+		http.Error(w, "invalid user_id", http.StatusBadRequest)
+		return
+	}
+	user, ok := h.session(r).Users.Get(id)
+	if !ok {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
+func (h *ControlHandler) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := users.ID(chi.URLParam(r, "user_id"))
+	if !ok {
+		http.Error(w, "invalid user_id", http.StatusBadRequest)
+		return
+	}
+	if !h.session(r).Users.Delete(id) {
+		http.Error(w, "user not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Member handlers
+
+func (h *ControlHandler) listMembers(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Members.List(chi.URLParam(r, "chat_id"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"members": list,
+		"count":   len(list),
+	})
+}
+
+// memberParams returns the chat and user of a member route.
+func memberParams(w http.ResponseWriter, r *http.Request) (string, int64, bool) {
+	userID, ok := users.ID(chi.URLParam(r, "user_id"))
+	if !ok {
+		http.Error(w, "invalid user_id", http.StatusBadRequest)
+		return "", 0, false
+	}
+	return chi.URLParam(r, "chat_id"), userID, true
+}
+
+func (h *ControlHandler) getMember(w http.ResponseWriter, r *http.Request) {
+	chatID, userID, ok := memberParams(w, r)
+	if !ok {
+		return
+	}
+	fields, ok := h.session(r).Members.Get(chatID, userID)
+	if !ok {
+		http.Error(w, "member not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(members.Member{ChatID: chatID, UserID: userID, Fields: fields})
+}
+
+func (h *ControlHandler) setMember(w http.ResponseWriter, r *http.Request) {
+	chatID, userID, ok := memberParams(w, r)
+	if !ok {
+		return
+	}
+	var fields map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fields); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.session(r).Members.Set(chatID, userID, fields); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) deleteMember(w http.ResponseWriter, r *http.Request) {
+	chatID, userID, ok := memberParams(w, r)
+	if !ok {
+		return
+	}
+	if !h.session(r).Members.Delete(chatID, userID) {
+		http.Error(w, "member not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Invite link handlers
+
+func (h *ControlHandler) listInviteLinks(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).InviteLinks.List(chi.URLParam(r, "chat_id"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"invite_links": list,
+		"count":        len(list),
+	})
+}
+
+// Topic handlers
+
+func (h *ControlHandler) listTopics(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Topics.List(chi.URLParam(r, "chat_id"))
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"topics": list,
+		"count":  len(list),
+	})
+}
+
+// Poll handlers
+
+func (h *ControlHandler) listPolls(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Polls.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"polls": list,
+		"count": len(list),
+	})
+}
+
+func (h *ControlHandler) getPoll(w http.ResponseWriter, r *http.Request) {
+	entry, ok := h.session(r).Polls.Get(chi.URLParam(r, "poll_id"))
+	if !ok {
+		http.Error(w, "poll not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(entry)
+}
+
+// answerPoll simulates a user voting in a poll a bot sent. The bot gets a
+// poll update with the new results and, unless the poll is anonymous, a
+// poll_answer update naming the voter.
+func (h *ControlHandler) answerPoll(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		UserID    int64 `json:"user_id"`
+		OptionIDs []int `json:"option_ids"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	if req.UserID == 0 {
+		req.UserID = st.Faker.NextUserID() + 100000000
+	}
+	if !h.admitUpdate(w, st) {
+		return
+	}
+
+	pollID := chi.URLParam(r, "poll_id")
+	poll, err := st.Polls.Vote(pollID, req.UserID, req.OptionIDs)
+	if errors.Is(err, polls.ErrUnknownPoll) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updateIDs := []int64{st.Updates.Add(map[string]interface{}{"poll": poll})}
+	if poll["is_anonymous"] != true {
+		user := simulatedUser(st, req.UserID)
+		optionIDs := req.OptionIDs
+		if optionIDs == nil {
+			optionIDs = []int{}
+		}
+		updateIDs = append(updateIDs, st.Updates.Add(map[string]interface{}{
+			"poll_answer": map[string]interface{}{
+				"poll_id":    pollID,
+				"user":       user,
+				"option_ids": optionIDs,
+			},
+		}))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"poll":       poll,
+		"update_ids": updateIDs,
+	})
+}
+
+// Game handlers
+
+func (h *ControlHandler) listGames(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Games.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"games": list,
+		"count": len(list),
+	})
+}
+
+// Sticker set handlers
+
+func (h *ControlHandler) listStickerSets(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).StickerSets.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sticker_sets": list,
+		"count":        len(list),
+	})
+}
+
+func (h *ControlHandler) getStickerSet(w http.ResponseWriter, r *http.Request) {
+	set, ok := h.session(r).StickerSets.Get(chi.URLParam(r, "name"))
+	if !ok {
+		http.Error(w, "sticker set not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(set)
+}
+
+// Boost handlers
+
+func (h *ControlHandler) listBoosts(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Boosts.List(chi.URLParam(r, "chat_id"))
+	if list == nil {
+		list = []boosts.Entry{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"boosts": list,
+		"count":  len(list),
+	})
+}
+
+// addBoost stores the ChatBoost a request describes, filling in what it
+// leaves out. Without a source, the boost is a Premium boost from user_id,
+// or from a new user.
+func (h *ControlHandler) addBoost(st *session.State, chatID string, req map[string]interface{}) (map[string]interface{}, error) {
+	userID, _ := users.ID(req["user_id"])
+	boost := map[string]interface{}{}
+	for name, v := range req {
+		if name != "chat_id" && name != "user_id" {
+			boost[name] = v
+		}
+	}
+	if _, ok := boost["boost_id"]; !ok {
+		boost["boost_id"] = strconv.FormatInt(st.Faker.RandomInt64(1e15, 1e16), 36)
+	}
+	if _, ok := boost["source"]; !ok {
+		if userID == 0 {
+			userID = st.Faker.NextUserID() + 100000000
+		}
+		boost["source"] = map[string]interface{}{"source": "premium", "user": simulatedUser(st, userID)}
+	}
+	return st.Boosts.Add(chatID, boost)
+}
+
+func (h *ControlHandler) seedBoost(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	boost, err := h.addBoost(h.session(r), chi.URLParam(r, "chat_id"), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(boost)
+}
+
+func (h *ControlHandler) deleteBoost(w http.ResponseWriter, r *http.Request) {
+	if _, ok := h.session(r).Boosts.Remove(chi.URLParam(r, "chat_id"), chi.URLParam(r, "boost_id")); !ok {
+		http.Error(w, "boost not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// simulateBoost adds a boost to a chat and sends the bot a chat_boost
+// update about it.
+func (h *ControlHandler) simulateBoost(w http.ResponseWriter, r *http.Request) {
+	var req map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	chatID := messages.ChatKey(req["chat_id"])
+	if chatID == "" {
+		http.Error(w, "chat_id is required", http.StatusBadRequest)
+		return
+	}
+	if !h.admitUpdate(w, st) {
+		return
+	}
+	boost, err := h.addBoost(st, chatID, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	updateID := st.Updates.Add(map[string]interface{}{
+		"chat_boost": map[string]interface{}{
+			"chat":  chatObject(st, chatID),
+			"boost": boost,
+		},
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"update_id": updateID,
+		"boost":     boost,
+	})
+}
+
+// simulateBoostRemoval removes a boost from a chat and sends the bot a
+// removed_chat_boost update about it.
+func (h *ControlHandler) simulateBoostRemoval(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChatID  interface{} `json:"chat_id"`
+		BoostID string      `json:"boost_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	chatID := messages.ChatKey(req.ChatID)
+	if _, ok := st.Boosts.Get(chatID, req.BoostID); !ok {
+		http.Error(w, "boost not found", http.StatusNotFound)
+		return
+	}
+	if !h.admitUpdate(w, st) {
+		return
+	}
+	boost, _ := st.Boosts.Remove(chatID, req.BoostID)
+	updateID := st.Updates.Add(map[string]interface{}{
+		"removed_chat_boost": map[string]interface{}{
+			"chat":        chatObject(st, chatID),
+			"boost_id":    req.BoostID,
+			"remove_date": st.Faker.Now().Unix(),
+			"source":      boost["source"],
+		},
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{"update_id": updateID})
+}
+
+// Star transaction handlers
+
+func (h *ControlHandler) listStarLedgers(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Stars.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"ledgers": list,
+		"count":   len(list),
+	})
+}
+
+func (h *ControlHandler) getStarLedger(w http.ResponseWriter, r *http.Request) {
+	list := h.session(r).Stars.Transactions(chi.URLParam(r, "token"), 0, 0)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"transactions": list,
+		"count":        len(list),
+	})
+}
+
+// addStarTransaction seeds a StarTransaction into a bot's ledger. The id
+// defaults to a generated charge ID.
+func (h *ControlHandler) addStarTransaction(w http.ResponseWriter, r *http.Request) {
+	var tx map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&tx); err != nil || tx == nil {
+		http.Error(w, "invalid transaction", http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	if _, ok := tx["id"]; !ok {
+		tx["id"] = "stxn_" + strconv.FormatInt(st.Faker.RandomInt64(1e17, 1e18), 36)
+	}
+	added, err := st.Stars.Add(chi.URLParam(r, "token"), tx)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(added)
+}
+
+func (h *ControlHandler) deleteStarLedger(w http.ResponseWriter, r *http.Request) {
+	if !h.session(r).Stars.Delete(chi.URLParam(r, "token")) {
+		http.Error(w, "bot has no transactions", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Bot settings handlers
+
+func (h *ControlHandler) listBots(w http.ResponseWriter, r *http.Request) {
+	bots := h.session(r).Bots.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"bots":  bots,
+		"count": len(bots),
+	})
+}
+
+func (h *ControlHandler) getBot(w http.ResponseWriter, r *http.Request) {
+	bot, ok := h.session(r).Bots.Get(chi.URLParam(r, "token"))
+	if !ok {
+		http.Error(w, "bot has no settings", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(bot)
+}
+
+// Chat action handlers
+
+func (h *ControlHandler) listChatActions(w http.ResponseWriter, r *http.Request) {
+	actions := h.session(r).ChatActions.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"chat_actions": actions,
+		"count":        len(actions),
+	})
+}
+
+func (h *ControlHandler) getChatAction(w http.ResponseWriter, r *http.Request) {
+	status, ok := h.session(r).ChatActions.Get(chi.URLParam(r, "chat_id"))
+	if !ok {
+		http.Error(w, "no chat action sent to this chat", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// Inline query handlers
+
+func (h *ControlHandler) listInlineQueries(w http.ResponseWriter, r *http.Request) {
+	queries := h.session(r).InlineQueries.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"inline_queries": queries,
+		"count":          len(queries),
+	})
+}
+
+func (h *ControlHandler) getInlineQuery(w http.ResponseWriter, r *http.Request) {
+	status, ok := h.session(r).InlineQueries.Get(chi.URLParam(r, "query_id"))
+	if !ok {
+		http.Error(w, "inline query not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(status)
+}
+
+// Callback query handlers
+
+func (h *ControlHandler) listCallbackQueries(w http.ResponseWriter, r *http.Request) {
+	queries := h.session(r).Callbacks.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"callback_queries": queries,
+		"count":            len(queries),
+	})
+}
+
+// simulateCallback presses an inline button of a message the bot sent:
+// it queues the callback_query update Telegram sends and starts its
+// answer deadline. The button must exist on the stored message.
+func (h *ControlHandler) simulateCallback(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ChatID       interface{} `json:"chat_id"`
+		MessageID    int64       `json:"message_id"`
+		CallbackData string      `json:"callback_data"`
+		UserID       int64       `json:"user_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	st := h.session(r)
+	chatID := messages.ChatKey(req.ChatID)
+	msg, ok := st.Messages.Get(chatID, req.MessageID)
+	if !ok {
+		http.Error(w, "message not found", http.StatusNotFound)
+		return
+	}
+	if !hasCallbackButton(msg, req.CallbackData) {
+		http.Error(w, "message has no button with this callback_data", http.StatusBadRequest)
+		return
+	}
+	if !h.admitUpdate(w, st) {
+		return
+	}
+
+	update := callbackUpdate(st, chatID, msg, req.CallbackData, req.UserID)
+	trackQueries(st, update)
+	updateID := st.Updates.Add(update)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"update_id":         updateID,
+		"callback_query_id": update["callback_query"].(map[string]interface{})["id"],
+	})
+}
+
+// callbackUpdate returns the callback_query update of a user pressing the
+// button with the data on a message. A user ID of 0 is a new user.
+func callbackUpdate(st *session.State, chatID string, msg map[string]interface{}, data string, userID int64) map[string]interface{} {
+	if userID == 0 {
+		userID = st.Faker.NextUserID() + 100000000
+	}
+	return map[string]interface{}{
+		"callback_query": map[string]interface{}{
+			"id":            strconv.FormatInt(st.Faker.RandomInt64(1e17, 1e18), 10),
+			"from":          simulatedUser(st, userID),
+			"message":       msg,
+			"chat_instance": chatInstance(chatID),
+			"data":          data,
+		},
+	}
+}
+
+// simulatedUser returns the User behind a simulated action: the seeded
+// user with the ID, or the profile generated for it.
+func simulatedUser(st *session.State, userID int64) map[string]interface{} {
+	user, _ := st.Faker.Generate("User", map[string]interface{}{"user_id": userID}).(map[string]interface{})
+	st.Users.Apply(user)
+	return user
+}
+
+// hasCallbackButton reports whether a message's inline keyboard has a
+// button with the callback data.
+func hasCallbackButton(msg map[string]interface{}, data string) bool {
+	return findCallbackButton(msg, func(button map[string]interface{}) bool {
+		return button["callback_data"] == data
+	}) != nil
+}
+
+// findCallbackButton returns the first button with callback data of a
+// message's inline keyboard that match accepts, or nil.
+func findCallbackButton(msg map[string]interface{}, match func(button map[string]interface{}) bool) map[string]interface{} {
+	markup, _ := msg["reply_markup"].(map[string]interface{})
+	rows, _ := markup["inline_keyboard"].([]interface{})
+	for _, row := range rows {
+		buttons, _ := row.([]interface{})
+		for _, b := range buttons {
+			button, ok := b.(map[string]interface{})
+			if _, isCallback := button["callback_data"].(string); ok && isCallback && match(button) {
+				return button
+			}
+		}
+	}
+	return nil
+}
+
+// chatInstance returns the chat_instance of callback queries from a chat:
+// an opaque identifier that stays the same for the chat.
+func chatInstance(chatID string) string {
+	h := fnv.New64a()
+	h.Write([]byte(chatID))
+	return strconv.FormatUint(h.Sum64()>>1, 10)
+}
+
+// Persona handlers
+
+func (h *ControlHandler) listPersonas(w http.ResponseWriter, r *http.Request) {
+	personas := h.session(r).Personas.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"personas": personas,
+		"count":    len(personas),
+	})
+}
+
+func (h *ControlHandler) clearPersonas(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Personas.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) getPersona(w http.ResponseWriter, r *http.Request) {
+	p, ok := h.session(r).Personas.Get(chi.URLParam(r, "chat_id"))
+	if !ok {
+		http.Error(w, "no persona attached to this chat", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+func (h *ControlHandler) setPersona(w http.ResponseWriter, r *http.Request) {
+	var p persona.Persona
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.ChatID = chi.URLParam(r, "chat_id")
+
+	st := h.session(r)
+	if err := st.Personas.Set(p); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p, _ = st.Personas.Get(p.ChatID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(p)
+}
+
+func (h *ControlHandler) deletePersona(w http.ResponseWriter, r *http.Request) {
+	if !h.session(r).Personas.Delete(chi.URLParam(r, "chat_id")) {
+		http.Error(w, "no persona attached to this chat", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Compat handlers
+
+func (h *ControlHandler) getCompat(w http.ResponseWriter, r *http.Request) {
+	cfg, stats := h.session(r).Compat.Get()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": cfg != nil,
+		"config":  cfg,
+		"stats":   stats,
+	})
+}
+
+func (h *ControlHandler) setCompat(w http.ResponseWriter, r *http.Request) {
+	var cfg compat.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.session(r).Compat.Set(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.getCompat(w, r)
+}
+
+func (h *ControlHandler) deleteCompat(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Compat.Disable()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Adversarial handlers
+
+func (h *ControlHandler) getAdversarial(w http.ResponseWriter, r *http.Request) {
+	cfg, stats := h.session(r).Adversarial.Get()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": cfg != nil,
+		"config":  cfg,
+		"stats":   stats,
+	})
+}
+
+func (h *ControlHandler) setAdversarial(w http.ResponseWriter, r *http.Request) {
+	var cfg adversarial.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.session(r).Adversarial.Set(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.getAdversarial(w, r)
+}
+
+func (h *ControlHandler) deleteAdversarial(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Adversarial.Disable()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Chaos handlers
+
+func (h *ControlHandler) getChaos(w http.ResponseWriter, r *http.Request) {
+	cfg, stats := h.session(r).Chaos.Get()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": cfg != nil,
+		"config":  cfg,
+		"stats":   stats,
+	})
+}
+
+func (h *ControlHandler) setChaos(w http.ResponseWriter, r *http.Request) {
+	var cfg chaos.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.session(r).Chaos.Set(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.getChaos(w, r)
+}
+
+func (h *ControlHandler) deleteChaos(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Chaos.Disable()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Flood limit handlers
+
+func (h *ControlHandler) getFloodLimits(w http.ResponseWriter, r *http.Request) {
+	cfg, stats := h.session(r).FloodLimit.Get()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": cfg != nil,
+		"config":  cfg,
+		"stats":   stats,
+	})
+}
+
+func (h *ControlHandler) setFloodLimits(w http.ResponseWriter, r *http.Request) {
+	var cfg floodlimit.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.session(r).FloodLimit.Set(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.getFloodLimits(w, r)
+}
+
+func (h *ControlHandler) deleteFloodLimits(w http.ResponseWriter, r *http.Request) {
+	h.session(r).FloodLimit.Disable()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Retry-after enforcement handlers
+
+func (h *ControlHandler) getRetryAfter(w http.ResponseWriter, r *http.Request) {
+	cooldowns := h.session(r).Cooldowns
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled":   cooldowns.Enabled(),
+		"cooldowns": cooldowns.List(),
+		"rejected":  cooldowns.Rejected(),
+	})
+}
+
+func (h *ControlHandler) setRetryAfter(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Enabled bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.session(r).Cooldowns.SetEnabled(req.Enabled)
+	h.getRetryAfter(w, r)
+}
+
+// clearRetryAfter lifts the active cooldowns, leaving enforcement on.
+func (h *ControlHandler) clearRetryAfter(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Cooldowns.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Latency handlers
+
+func (h *ControlHandler) getLatency(w http.ResponseWriter, r *http.Request) {
+	cfg := h.bots.latency.Get()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"enabled": cfg != nil,
+		"config":  cfg,
+	})
+}
+
+func (h *ControlHandler) setLatency(w http.ResponseWriter, r *http.Request) {
+	var cfg latency.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.bots.latency.Set(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.getLatency(w, r)
+}
+
+func (h *ControlHandler) deleteLatency(w http.ResponseWriter, r *http.Request) {
+	h.bots.latency.Disable()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Outage handlers
+
+func (h *ControlHandler) getOutage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.session(r).Outage.Status())
+}
+
+func (h *ControlHandler) startOutage(w http.ResponseWriter, r *http.Request) {
+	var cfg outage.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.session(r).Outage.Start(cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(h.session(r).Outage.Status())
+}
+
+func (h *ControlHandler) stopOutage(w http.ResponseWriter, r *http.Request) {
+	h.session(r).Outage.Stop()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// API version handlers
+
+func (h *ControlHandler) getAPIVersion(w http.ResponseWriter, r *http.Request) {
+	gate := h.session(r).APIVersion
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"version":      gate.Version().String(),
+		"spec_version": gen.Version,
+		"too_new":      gate.Calls(),
+	})
+}
+
+func (h *ControlHandler) setAPIVersion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Version string `json:"version"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	v, err := apiversion.ParseSupported(req.Version)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.session(r).APIVersion.SetVersion(v)
+	h.getAPIVersion(w, r)
+}
+
+// Clock handlers
+
+func (h *ControlHandler) getClock(w http.ResponseWriter, r *http.Request) {
+	now := h.clock.Now()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"now":    now.UTC().Format(time.RFC3339Nano),
+		"unix":   now.Unix(),
+		"frozen": h.clock.Frozen(),
+	})
+}
+
+// setClock moves the clock to a time, given as RFC 3339 or as a Unix
+// timestamp like Telegram's dates, and freezes or unfreezes it. Either
+// may be left out.
+func (h *ControlHandler) setClock(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Time   string `json:"time"`
+		Unix   *int64 `json:"unix"`
+		Frozen *bool  `json:"frozen"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var target *time.Time
+	switch {
+	case req.Time != "" && req.Unix != nil:
+		http.Error(w, "time and unix are mutually exclusive", http.StatusBadRequest)
+		return
+	case req.Time != "":
+		t, err := time.Parse(time.RFC3339, req.Time)
+		if err != nil {
+			http.Error(w, "invalid time: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		target = &t
+	case req.Unix != nil:
+		t := time.Unix(*req.Unix, 0)
+		target = &t
+	}
+
+	// Freezing first stops the clock exactly at the target
+	if req.Frozen != nil && *req.Frozen {
+		h.clock.Freeze()
+	}
+	if target != nil {
+		h.clock.Set(*target)
+	}
+	if req.Frozen != nil && !*req.Frozen {
+		h.clock.Unfreeze()
+	}
+	h.getClock(w, r)
+}
+
+func (h *ControlHandler) advanceClock(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		DurationMs int64 `json:"duration_ms"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.clock.Advance(time.Duration(req.DurationMs) * time.Millisecond)
+	h.getClock(w, r)
+}
+
+// resetClock returns the clock to the system time, or to the epoch of a
+// deterministic server.
+func (h *ControlHandler) resetClock(w http.ResponseWriter, r *http.Request) {
+	h.clock.Reset()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getSchema serves the JSON Schema of a Bot API type. Schemas refer to
+// each other as "Message.json", so the type may be given with the suffix.
+func (h *ControlHandler) getSchema(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimSuffix(chi.URLParam(r, "type"), ".json")
+	data, ok := gen.Schema(name)
+	if !ok {
+		http.Error(w, "unknown type "+name, http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.Write(data)
+}
+
+// generateFixture serves a generated object of a spec type, as a fixture
+// for tests outside Go. A seed makes it reproducible, with the faker of
+// the session started afresh from it, and overrides, a JSON object, set
+// fields of it as response data overrides do.
+func (h *ControlHandler) generateFixture(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "type")
+	if _, ok := gen.Types[name]; !ok {
+		http.Error(w, "unknown type "+name, http.StatusNotFound)
+		return
+	}
+
+	f := h.session(r).Faker
+	if s := r.URL.Query().Get("seed"); s != "" {
+		seed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			http.Error(w, "invalid seed: "+s, http.StatusBadRequest)
+			return
+		}
+		f = f.WithSeed(seed)
+	}
+	var overrides map[string]interface{}
+	if s := r.URL.Query().Get("overrides"); s != "" {
+		if err := json.Unmarshal([]byte(s), &overrides); err != nil {
+			http.Error(w, "invalid overrides: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(f.GenerateWithOverrides(name, nil, overrides))
+}
+
+// Bot group handlers
+
+func (h *ControlHandler) listBotGroups(w http.ResponseWriter, r *http.Request) {
+	groups := h.groups.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"groups": groups,
+		"count":  len(groups),
+	})
+}
+
+func (h *ControlHandler) clearBotGroups(w http.ResponseWriter, r *http.Request) {
+	h.groups.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) getBotGroup(w http.ResponseWriter, r *http.Request) {
+	g, ok := h.groups.Get(chi.URLParam(r, "chat_id"))
+	if !ok {
+		http.Error(w, "bot group not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g)
+}
+
+func (h *ControlHandler) setBotGroup(w http.ResponseWriter, r *http.Request) {
+	var g botgroup.Group
+	if err := json.NewDecoder(r.Body).Decode(&g); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	g.ChatID = chi.URLParam(r, "chat_id")
+
+	if err := h.groups.Set(g); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	g, _ = h.groups.Get(g.ChatID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(g)
+}
+
+func (h *ControlHandler) deleteBotGroup(w http.ResponseWriter, r *http.Request) {
+	if !h.groups.Delete(chi.URLParam(r, "chat_id")) {
+		http.Error(w, "bot group not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Statistics handlers
+
+// getStats exports per-chat request statistics as JSON or, with
+// format=csv, as CSV with one row per chat (or per chat and method with
+// group=method).
+func (h *ControlHandler) getStats(w http.ResponseWriter, r *http.Request) {
+	stats := h.session(r).Recorder.Stats()
+
+	switch r.URL.Query().Get("format") {
+	case "", "json":
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"chats": stats,
+			"count": len(stats),
+		})
+	case "csv":
+		byMethod := r.URL.Query().Get("group") == "method"
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", `attachment; filename="tg-mock-stats.csv"`)
+		writeStatsCSV(w, stats, byMethod)
+	default:
+		http.Error(w, "unsupported format", http.StatusBadRequest)
+	}
+}
+
+// getReport analyzes the recorded requests for client bugs. The
+// duplicate_sends section flags messages that were likely sent twice
+// because of a retry after an error response. The window query parameter
+// sets how long after the error repeated sends count as retries.
+func (h *ControlHandler) getReport(w http.ResponseWriter, r *http.Request) {
+	window := inspector.DefaultDuplicateWindow
+	if v := r.URL.Query().Get("window"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			http.Error(w, "invalid window", http.StatusBadRequest)
+			return
+		}
+		window = d
+	}
+
+	duplicates := h.session(r).Recorder.DuplicateSends(window)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"duplicate_sends": map[string]interface{}{
+			"window": window.String(),
+			"count":  len(duplicates),
+			"items":  duplicates,
+		},
+	})
+}
+
+func writeStatsCSV(w io.Writer, stats []inspector.ChatStats, byMethod bool) {
+	cw := csv.NewWriter(w)
+	defer cw.Flush()
+
+	if byMethod {
+		cw.Write([]string{"chat_id", "method", "requests", "errors"})
+		for _, s := range stats {
+			methods := make([]string, 0, len(s.Methods))
+			for method := range s.Methods {
+				methods = append(methods, method)
+			}
+			sort.Strings(methods)
+			for _, method := range methods {
+				m := s.Methods[method]
+				cw.Write([]string{s.ChatID, method, strconv.Itoa(m.Requests), strconv.Itoa(m.Errors)})
+			}
+		}
+		return
+	}
+
+	cw.Write([]string{"chat_id", "requests", "sent", "edits", "deletes", "errors"})
+	for _, s := range stats {
+		cw.Write([]string{
+			s.ChatID,
+			strconv.Itoa(s.Requests),
+			strconv.Itoa(s.Sent),
+			strconv.Itoa(s.Edits),
+			strconv.Itoa(s.Deletes),
+			strconv.Itoa(s.Errors),
+		})
+	}
+}
+
+// State handlers
+
+func (h *ControlHandler) reset(w http.ResponseWriter, r *http.Request) {
+	st := h.session(r)
+	st.Scenarios.Reset()
+	st.Updates.Clear()
+	st.Recorder.Clear()
+	st.Messages.Clear()
+	st.Chats.Clear()
+	st.Bots.Clear()
+	st.Users.Clear()
+	st.Members.Clear()
+	st.InviteLinks.Clear()
+	st.Topics.Clear()
+	st.Polls.Clear()
+	st.Games.Clear()
+	st.StickerSets.Clear()
+	st.Boosts.Clear()
+	st.Stars.Clear()
+	st.ChatActions.Reset()
+	st.InlineQueries.Reset()
+	st.Callbacks.Reset()
+	st.Personas.Clear()
+	st.Compat.Disable()
+	st.Adversarial.Disable()
+	st.APIVersion.Reset()
+	st.Outage.Reset()
+	st.Chaos.Disable()
+	st.FloodLimit.Disable()
+	st.Cooldowns.Clear()
+	st.Archive.Clear()
+	h.webhooks.ClearSession(st.Name)
+	h.events.Publish(events.Event{
+		Type:    events.TypeStateReset,
+		Session: st.Name,
+		Data:    map[string]interface{}{"reason": "reset"},
+	})
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) getState(w http.ResponseWriter, r *http.Request) {
+	st := h.session(r)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"session":           st.Name,
+		"scenarios_count":   len(st.Scenarios.List()),
+		"updates_pending":   st.Updates.Pending(),
+		"requests_recorded": st.Recorder.Count(),
+		"messages_stored":   st.Messages.Count(),
+		"webhooks_count":    len(h.webhooks.List()),
+		"memory":            h.memoryState(st),
+	})
+}
+
+// memoryState reports memory use against the configured ceilings, along
+// with recent breaches. Recorder, queue, and message usage is per session;
+// files are shared by all sessions.
+func (h *ControlHandler) memoryState(st *session.State) map[string]interface{} {
+	usage := map[guard.Component]int64{
+		guard.Recorder: st.Recorder.Size(),
+		guard.Queue:    st.Updates.Size(),
+		guard.Messages: st.Messages.Size(),
+		guard.Files:    h.files.Size(),
+	}
+	components := make(map[string]interface{}, len(usage))
+	for _, c := range guard.Components {
+		components[string(c)] = map[string]interface{}{
+			"used":  usage[c],
+			"limit": h.guard.Limit(c),
+		}
+	}
+
+	breaches, total := h.guard.Breaches()
+	return map[string]interface{}{
+		"policy":         h.guard.Policy(),
+		"components":     components,
+		"breaches":       breaches,
+		"breaches_count": total,
+	}
+}
+
+// Lifecycle handlers
+
+// requireLifecycle reports whether lifecycle endpoints may be used. They
+// are only enabled when a control token protects the control API.
+func (h *ControlHandler) requireLifecycle(w http.ResponseWriter) bool {
+	if h.controlToken == "" {
+		http.Error(w, "lifecycle endpoints require a control token (--control-token)", http.StatusForbidden)
+		return false
+	}
+	if h.lifecycle == nil {
+		http.Error(w, "lifecycle control not available", http.StatusNotImplemented)
+		return false
+	}
+	return true
+}
+
+func (h *ControlHandler) shutdown(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLifecycle(w) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "shutting_down",
+	})
+	h.lifecycle.RequestShutdown()
+}
+
+func (h *ControlHandler) restart(w http.ResponseWriter, r *http.Request) {
+	if !h.requireLifecycle(w) {
+		return
+	}
+	h.lifecycle.Restart()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": "restarted",
+	})
+}
+
+// Session handlers
+
+func (h *ControlHandler) listSessions(w http.ResponseWriter, r *http.Request) {
+	sessions := h.sessions.List()
+	result := make([]map[string]interface{}, 0, len(sessions))
+	for _, st := range sessions {
+		result = append(result, map[string]interface{}{
+			"name":              st.Name,
+			"scenarios_count":   len(st.Scenarios.List()),
+			"updates_pending":   st.Updates.Pending(),
+			"requests_recorded": st.Recorder.Count(),
+			"messages_stored":   st.Messages.Count(),
+		})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"sessions": result,
+		"count":    len(result),
+	})
+}
+
+func (h *ControlHandler) deleteSession(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if h.sessions.Delete(name) {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		http.Error(w, "session not found", http.StatusNotFound)
+	}
+}
+
+// Instance handlers
+
+// instanceRequest is the body of POST /instances.
+type instanceRequest struct {
+	Seed  int64 `json:"seed"`
+	TTLMs int64 `json:"ttl_ms"`
+}
+
+// instanceView describes an instance along with the URLs to reach it.
+type instanceView struct {
+	instance.Instance
+	TTLMs      int64  `json:"ttl_ms"`
+	URL        string `json:"url"`
+	ControlURL string `json:"control_url"`
+}
+
+func viewInstance(r *http.Request, inst instance.Instance) instanceView {
+	url := requestBaseURL(r) + "/instances/" + inst.ID
+	return instanceView{
+		Instance:   inst,
+		TTLMs:      time.Until(inst.ExpiresAt).Milliseconds(),
+		URL:        url,
+		ControlURL: url + "/__control",
+	}
+}
+
+// createInstance starts an isolated instance. Its url replaces
+// https://api.telegram.org in the bot under test.
+func (h *ControlHandler) createInstance(w http.ResponseWriter, r *http.Request) {
+	var req instanceRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.TTLMs < 0 {
+		http.Error(w, "ttl_ms must not be negative", http.StatusBadRequest)
+		return
+	}
+	inst, err := h.instances.Create(req.Seed, time.Duration(req.TTLMs)*time.Millisecond)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(viewInstance(r, inst))
+}
+
+func (h *ControlHandler) listInstances(w http.ResponseWriter, r *http.Request) {
+	list := h.instances.List()
+	result := make([]instanceView, 0, len(list))
+	for _, inst := range list {
+		result = append(result, viewInstance(r, inst))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"instances": result,
+		"count":     len(result),
+	})
+}
+
+func (h *ControlHandler) getInstance(w http.ResponseWriter, r *http.Request) {
+	inst, ok := h.instances.Get(chi.URLParam(r, "id"))
+	if !ok {
+		http.Error(w, "instance not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(viewInstance(r, inst))
+}
+
+func (h *ControlHandler) deleteInstance(w http.ResponseWriter, r *http.Request) {
+	if !h.instances.Delete(chi.URLParam(r, "id")) {
+		http.Error(w, "instance not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requestBaseURL returns the scheme and host the request was sent to,
+// honoring the X-Forwarded-Proto and X-Forwarded-Host headers set by
+// reverse proxies.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	host := r.Host
+	if fwd := r.Header.Get("X-Forwarded-Host"); fwd != "" {
+		host = fwd
+	}
+	return scheme + "://" + host
+}
+
+// Event webhook handlers
+
+func (h *ControlHandler) listEventWebhooks(w http.ResponseWriter, r *http.Request) {
+	subs := h.events.List()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"webhooks": subs,
+		"count":    len(subs),
+	})
+}
+
+func (h *ControlHandler) addEventWebhook(w http.ResponseWriter, r *http.Request) {
+	var sub events.Subscription
+	if err := json.NewDecoder(r.Body).Decode(&sub); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if sub.URL == "" {
+		http.Error(w, "url is required", http.StatusBadRequest)
+		return
+	}
+
+	id := h.events.Subscribe(sub)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id": id,
+	})
+}
+
+func (h *ControlHandler) clearEventWebhooks(w http.ResponseWriter, r *http.Request) {
+	h.events.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) removeEventWebhook(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	if h.events.Unsubscribe(id) {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		http.Error(w, "event webhook not found", http.StatusNotFound)
+	}
+}
+
+// Webhook handlers
+
+func (h *ControlHandler) listWebhooks(w http.ResponseWriter, r *http.Request) {
+	offset, ok := pageOffset(r)
+	if !ok {
+		http.Error(w, "invalid cursor", http.StatusBadRequest)
+		return
+	}
+	webhooks := h.webhooks.List()
+	tokens := make([]string, 0, len(webhooks))
+	for token := range webhooks {
+		tokens = append(tokens, token)
+	}
+	sort.Strings(tokens)
+
+	start, end, next := pageBounds(len(tokens), offset, pageLimit(r, 0))
+	page := make(map[string]*webhook.Config, end-start)
+	for _, token := range tokens[start:end] {
+		page[token] = webhooks[token]
+	}
+	writeListing(w, r, map[string]interface{}{
+		"webhooks":    page,
+		"count":       len(webhooks),
+		"next_cursor": next,
+	})
+}
+
+func (h *ControlHandler) clearWebhooks(w http.ResponseWriter, r *http.Request) {
+	h.webhooks.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ControlHandler) getWebhook(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	cfg := h.webhooks.Get(token)
+	if cfg == nil {
+		http.Error(w, "webhook not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cfg)
+}
+
+func (h *ControlHandler) setWebhook(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	var cfg webhook.Config
+	if err := json.NewDecoder(r.Body).Decode(&cfg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cfg.Session = h.session(r).Name
+	h.webhooks.Set(token, &cfg)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *ControlHandler) deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+	h.webhooks.Delete(token)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// injectTokenUpdate injects an update for a specific token, routing to webhook if active
+func (h *ControlHandler) injectTokenUpdate(w http.ResponseWriter, r *http.Request) {
+	token := chi.URLParam(r, "token")
+
+	var update map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	h.dispatchUpdate(w, r, h.session(r), token, update, nil)
 }
@@ -0,0 +1,94 @@
+// internal/server/conversation.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/watzon/tg-mock/internal/conversation"
+	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/tracing"
+)
+
+// runConversation plays the YAML conversation script in the body against
+// the bot and responds with the report: 200 if the bot answered as
+// expected, and 422 Unprocessable Entity otherwise. The response comes
+// when the script is over.
+func (h *ControlHandler) runConversation(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	script, err := conversation.Parse(data)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d := &conversationDriver{h: h, st: h.session(r), token: script.Token}
+	report := conversation.Run(tracing.Extract(r.Context(), r.Header), script, d)
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Passed {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(report)
+}
+
+// conversationDriver plays the user of a conversation script in a
+// session, sending updates to the bot of token.
+type conversationDriver struct {
+	h     *ControlHandler
+	st    *session.State
+	token string
+}
+
+func (d *conversationDriver) Simulate(ctx context.Context, kind string, fields map[string]interface{}) (map[string]interface{}, error) {
+	update, err := d.h.simulatedUpdate(d.st, d.token, kind, fields)
+	if err != nil {
+		return nil, err
+	}
+	return update, d.send(ctx, update)
+}
+
+func (d *conversationDriver) Press(ctx context.Context, chatID, userID int64, button string) (map[string]interface{}, error) {
+	key := messages.ChatKey(chatID)
+	stored := d.st.Messages.List(key)
+	for i := len(stored) - 1; i >= 0; i-- {
+		msg := stored[i].Message
+		found := findCallbackButton(msg, func(b map[string]interface{}) bool {
+			return b["text"] == button || b["callback_data"] == button
+		})
+		if found == nil {
+			continue
+		}
+		update := callbackUpdate(d.st, key, msg, found["callback_data"].(string), userID)
+		return update, d.send(ctx, update)
+	}
+	return nil, fmt.Errorf("no message in chat %d has a button %q", chatID, button)
+}
+
+func (d *conversationDriver) Recorder() *inspector.Recorder {
+	return d.st.Recorder
+}
+
+// send sends an update to the bot. A webhook that fails to take it fails
+// the step, since the bot never saw the update.
+func (d *conversationDriver) send(ctx context.Context, update map[string]interface{}) error {
+	result, err := d.h.sendUpdate(ctx, d.st, d.token, update)
+	if err != nil {
+		return err
+	}
+	if result != nil && !result.Success {
+		if result.Error != "" {
+			return fmt.Errorf("webhook delivery failed: %s", result.Error)
+		}
+		return fmt.Errorf("webhook delivery failed with status %d", result.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// internal/server/enums.go
+package server
+
+import (
+	"strings"
+
+	"github.com/watzon/tg-mock/gen"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// parseModes are the values of parse_mode, which the spec doesn't list.
+var parseModes = []string{"HTML", "MarkdownV2", "Markdown"}
+
+// checkEnums checks that the enumerated string parameters of a call, such
+// as the action of sendChatAction, and the enumerated fields of the
+// objects it sends, such as the format of an InputSticker, have one of the
+// values the spec lists. Parse modes are checked wherever they appear, as
+// in InputMedia and reply_parameters; they are case-insensitive and may be
+// empty.
+func checkEnums(spec gen.MethodSpec, params map[string]interface{}) *tgerrors.Error {
+	if resp := checkParseModes(params); resp != nil {
+		return resp
+	}
+	if name := wrongEnum(spec.Fields, params); name != "" {
+		return wrongParameter(name)
+	}
+	for _, f := range spec.Fields {
+		// Items of several types, such as the media of an album, are
+		// checked by their own handlers
+		if len(f.Types) != 1 {
+			continue
+		}
+		fields := gen.Types[gen.ParseType(f.Types...).Base()].Fields
+		objects := arrayParam(params[f.Name])
+		if obj := objectParam(params[f.Name]); obj != nil {
+			objects = []interface{}{obj}
+		}
+		for _, o := range objects {
+			obj, _ := o.(map[string]interface{})
+			if name := wrongEnum(fields, obj); name != "" {
+				return wrongParameter(name)
+			}
+		}
+	}
+	return nil
+}
+
+// wrongEnum returns the name of the first enumerated string field of
+// values that doesn't have one of its values, or "".
+func wrongEnum(fields []gen.FieldSpec, values map[string]interface{}) string {
+	for _, f := range fields {
+		if f.Constraint == nil || len(f.Constraint.Enum) == 0 || f.Types[0] != "String" {
+			continue
+		}
+		if v, ok := values[f.Name]; ok && !oneOf(v, f.Constraint.Enum) {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// checkParseModes checks the fields named parse_mode or ending in
+// _parse_mode of v and of the objects and arrays it holds.
+func checkParseModes(v interface{}) *tgerrors.Error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if name == "parse_mode" || strings.HasSuffix(name, "_parse_mode") {
+				if mode, ok := field.(string); ok && mode != "" && !foldOneOf(mode, parseModes) {
+					return tgerrors.UnsupportedParseMode()
+				}
+				continue
+			}
+			if str, ok := field.(string); ok && strings.HasPrefix(strings.TrimSpace(str), "{") {
+				field = objectParam(str)
+			} else if ok && strings.HasPrefix(strings.TrimSpace(str), "[") {
+				field = arrayParam(str)
+			}
+			if resp := checkParseModes(field); resp != nil {
+				return resp
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if resp := checkParseModes(item); resp != nil {
+				return resp
+			}
+		}
+	}
+	return nil
+}
+
+// wrongParameter returns the error for an enumerated parameter with an
+// unknown value.
+func wrongParameter(name string) *tgerrors.Error {
+	if name == "action" {
+		return tgerrors.WrongParameterAction()
+	}
+	return tgerrors.WrongParameter(name)
+}
+
+// oneOf reports whether v is one of the string values.
+func oneOf(v interface{}, values []string) bool {
+	s, ok := v.(string)
+	if !ok {
+		return false
+	}
+	for _, value := range values {
+		if s == value {
+			return true
+		}
+	}
+	return false
+}
+
+// foldOneOf reports whether s is one of values, ignoring case.
+func foldOneOf(s string, values []string) bool {
+	for _, value := range values {
+		if strings.EqualFold(s, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// internal/server/formatting.go
+package server
+
+import (
+	"strings"
+	"unicode/utf16"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/markup"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// formattedFields pairs the text fields of send and edit calls with the
+// fields holding their entities.
+var formattedFields = []struct{ text, entities string }{
+	{"text", "entities"},
+	{"caption", "caption_entities"},
+}
+
+// checkFormatting checks that the text and caption of a call parse in its
+// parse_mode and that the plain text fits the length the spec gives for
+// the field after entities parsing, in UTF-16 code units. fields are the
+// parameters of the method, or the fields of an InputMedia.
+func checkFormatting(fields []gen.FieldSpec, params map[string]interface{}) *tgerrors.Error {
+	for _, f := range formattedFields {
+		if _, ok := params[f.text].(string); !ok {
+			continue
+		}
+		plain, _, resp := formatText(params, f.text, f.entities)
+		if resp != nil {
+			return resp
+		}
+		// Other texts, such as the text of answerCallbackQuery, aren't
+		// message texts and have limits of their own
+		c := fieldConstraint(fields, f.text)
+		if c == nil || !c.Parsed {
+			continue
+		}
+		length := int64(len(utf16.Encode([]rune(plain))))
+		switch {
+		case f.text == "caption" && length > c.Max:
+			return tgerrors.MessageCaptionTooLong()
+		case f.text == "text" && (length < c.Min || (c.Min > 0 && strings.TrimSpace(plain) == "")):
+			return tgerrors.MessageTextEmpty()
+		case f.text == "text" && length > c.Max:
+			return tgerrors.MessageTooLong()
+		}
+	}
+	return nil
+}
+
+// formatText returns the plain text and entities of a text field, as
+// Telegram makes them: explicit entities are used as given, and otherwise
+// the markup of parse_mode is parsed. Mentions, hashtags, URLs, and the
+// other entities Telegram detects are added to parsed text.
+func formatText(params map[string]interface{}, field, entitiesField string) (string, []interface{}, *tgerrors.Error) {
+	text, _ := params[field].(string)
+	if entities := arrayParam(params[entitiesField]); len(entities) > 0 {
+		return text, entities, nil
+	}
+	mode, _ := params["parse_mode"].(string)
+	plain, parsed, err := markup.Parse(text, mode)
+	if err != nil {
+		return "", nil, tgerrors.CantParseEntities(err.Error())
+	}
+	return plain, entityFields(markup.Detect(plain, parsed)), nil
+}
+
+// entityFields returns entities as MessageEntity objects.
+func entityFields(entities []markup.Entity) []interface{} {
+	var fields []interface{}
+	for _, e := range entities {
+		fields = append(fields, e.Fields())
+	}
+	return fields
+}
+
+// applyFormatting replaces the text and caption of a generated message
+// with their plain text and sets their entities.
+func applyFormatting(params map[string]interface{}, result interface{}) interface{} {
+	msg, ok := result.(map[string]interface{})
+	if !ok {
+		return result
+	}
+	for _, f := range formattedFields {
+		if _, ok := params[f.text].(string); !ok {
+			continue
+		}
+		if _, ok := msg[f.text]; !ok {
+			continue
+		}
+		text, entities, resp := formatText(params, f.text, f.entities)
+		if resp != nil {
+			continue
+		}
+		setFormatted(msg, f.text, f.entities, text, entities)
+	}
+	return msg
+}
+
+// setFormatted sets a text field and its entities on a message, leaving
+// out entities when there are none.
+func setFormatted(msg map[string]interface{}, field, entitiesField, text string, entities []interface{}) {
+	msg[field] = text
+	if len(entities) > 0 {
+		msg[entitiesField] = entities
+	} else {
+		delete(msg, entitiesField)
+	}
+}
@@ -0,0 +1,233 @@
+// internal/server/forward.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// forwardMethods are the methods that forward or copy messages. Their
+// results are built from the messages they refer to.
+var forwardMethods = map[string]bool{
+	"forwardMessage":  true,
+	"forwardMessages": true,
+	"copyMessage":     true,
+	"copyMessages":    true,
+}
+
+// envelopeFields are the fields of a message that describe how it was
+// sent rather than what it contains. Forwards and copies take them from
+// the new message, not from the original.
+var envelopeFields = map[string]bool{
+	"message_id":             true,
+	"message_thread_id":      true,
+	"direct_messages_topic":  true,
+	"from":                   true,
+	"sender_chat":            true,
+	"sender_boost_count":     true,
+	"sender_business_bot":    true,
+	"date":                   true,
+	"business_connection_id": true,
+	"chat":                   true,
+	"forward_origin":         true,
+	"is_topic_message":       true,
+	"is_automatic_forward":   true,
+	"reply_to_message":       true,
+	"external_reply":         true,
+	"quote":                  true,
+	"reply_to_story":         true,
+	"edit_date":              true,
+	"has_protected_content":  true,
+	"is_from_offline":        true,
+	"media_group_id":         true,
+	"author_signature":       true,
+	"effect_id":              true,
+	"reply_markup":           true,
+}
+
+// resolveForward looks up the messages a forward or copy call refers to,
+// in the order they are given. Messages the bot sent and messages
+// injected as updates can be forwarded; others are not found.
+func resolveForward(st *session.State, method string, params map[string]interface{}) ([]map[string]interface{}, *tgerrors.Error) {
+	if !forwardMethods[method] {
+		return nil, nil
+	}
+	var ids []interface{}
+	if method == "forwardMessage" || method == "copyMessage" {
+		ids = []interface{}{params["message_id"]}
+	} else {
+		ids = arrayParam(params["message_ids"])
+	}
+
+	fromChat := messages.ChatKey(params["from_chat_id"])
+	sources := make([]map[string]interface{}, 0, len(ids))
+	for _, v := range ids {
+		messageID, ok := messages.MessageID(v)
+		if !ok {
+			return nil, tgerrors.MessageToForwardNotFound()
+		}
+		msg, found := st.Messages.Get(fromChat, messageID)
+		if !found {
+			msg, found = st.Archive.Message(fromChat, messageID)
+		}
+		if !found {
+			return nil, tgerrors.MessageToForwardNotFound()
+		}
+		sources = append(sources, msg)
+	}
+	return sources, nil
+}
+
+// applyForward builds the result of a forward or copy call from the
+// messages it refers to. Forwards keep the content of the originals and
+// say where they came from in forward_origin; copies keep the content
+// only, and are stored since their result is just their ID.
+func applyForward(st *session.State, method string, params map[string]interface{}, sources []map[string]interface{}, result interface{}) interface{} {
+	if !forwardMethods[method] {
+		return result
+	}
+	copying := method == "copyMessage" || method == "copyMessages"
+
+	if method == "forwardMessage" {
+		msg, ok := result.(map[string]interface{})
+		if !ok || len(sources) == 0 {
+			return result
+		}
+		return forwardedMessage(params, sources[0], msg)
+	}
+
+	// The other methods answer with MessageIds, so the messages they
+	// create are generated and stored here
+	envelopeParams := map[string]interface{}{"chat_id": params["chat_id"]}
+	if method == "copyMessage" {
+		envelopeParams["reply_markup"] = params["reply_markup"]
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	ids := make([]interface{}, 0, len(sources))
+	for _, source := range sources {
+		envelope, _ := st.Faker.Generate("Message", envelopeParams).(map[string]interface{})
+		if envelope == nil {
+			continue
+		}
+		var msg map[string]interface{}
+		if copying {
+			msg = copiedMessage(method, params, source, envelope)
+		} else {
+			msg = forwardedMessage(params, source, envelope)
+		}
+		storeMessage(st.Messages, chatID, msg)
+		ids = append(ids, map[string]interface{}{"message_id": msg["message_id"]})
+	}
+	if method == "copyMessage" {
+		if len(ids) == 0 {
+			return result
+		}
+		return ids[0]
+	}
+	return ids
+}
+
+// forwardedMessage returns the content of source in the envelope of a new
+// message, with the origin of source. Forwarding a forward keeps the
+// origin of the first message.
+func forwardedMessage(params map[string]interface{}, source, envelope map[string]interface{}) map[string]interface{} {
+	msg := withContent(source, envelope)
+	if origin, ok := source["forward_origin"]; ok {
+		msg["forward_origin"] = origin
+	} else {
+		msg["forward_origin"] = messageOrigin(source)
+	}
+	delete(msg, "reply_markup")
+	if boolParam(params["protect_content"]) {
+		msg["has_protected_content"] = true
+	}
+	return msg
+}
+
+// copiedMessage returns the content of source in the envelope of a new
+// message, with the caption changes of a copy call.
+func copiedMessage(method string, params map[string]interface{}, source, envelope map[string]interface{}) map[string]interface{} {
+	msg := withContent(source, envelope)
+	if _, ok := msg["caption"]; ok || hasMedia(msg) {
+		if method == "copyMessage" {
+			if _, ok := params["caption"].(string); ok {
+				caption, entities, _ := formatText(params, "caption", "caption_entities")
+				setFormatted(msg, "caption", "caption_entities", caption, entities)
+			}
+			if _, ok := params["show_caption_above_media"]; ok {
+				msg["show_caption_above_media"] = boolParam(params["show_caption_above_media"])
+			}
+		} else if boolParam(params["remove_caption"]) {
+			delete(msg, "caption")
+			delete(msg, "caption_entities")
+		}
+	}
+	if boolParam(params["protect_content"]) {
+		msg["has_protected_content"] = true
+	}
+	return msg
+}
+
+// withContent returns the envelope fields of envelope with the content
+// fields of source.
+func withContent(source, envelope map[string]interface{}) map[string]interface{} {
+	msg := make(map[string]interface{}, len(source)+len(envelope))
+	for k, v := range source {
+		if !envelopeFields[k] {
+			msg[k] = v
+		}
+	}
+	for k, v := range envelope {
+		if envelopeFields[k] {
+			msg[k] = v
+		}
+	}
+	return msg
+}
+
+// messageOrigin returns the MessageOrigin of a message: the channel it
+// was posted in, the chat it was sent on behalf of, or its sender.
+func messageOrigin(source map[string]interface{}) map[string]interface{} {
+	origin := map[string]interface{}{"date": source["date"]}
+	chat, _ := source["chat"].(map[string]interface{})
+	if chat["type"] == "channel" {
+		origin["type"] = "channel"
+		origin["chat"] = chat
+		origin["message_id"] = source["message_id"]
+		if signature, ok := source["author_signature"]; ok {
+			origin["author_signature"] = signature
+		}
+		return origin
+	}
+	if senderChat, ok := source["sender_chat"].(map[string]interface{}); ok {
+		origin["type"] = "chat"
+		origin["sender_chat"] = senderChat
+		if signature, ok := source["author_signature"]; ok {
+			origin["author_signature"] = signature
+		}
+		return origin
+	}
+	if from, ok := source["from"].(map[string]interface{}); ok {
+		origin["type"] = "user"
+		origin["sender_user"] = from
+		return origin
+	}
+	origin["type"] = "hidden_user"
+	origin["sender_user_name"] = "Deleted Account"
+	return origin
+}
+
+// mediaFields are the message fields that hold media a caption can
+// describe.
+var mediaFields = []string{"animation", "audio", "document", "paid_media", "photo", "video", "voice"}
+
+// hasMedia reports whether a message has media that can have a caption.
+func hasMedia(msg map[string]interface{}) bool {
+	for _, name := range mediaFields {
+		if _, ok := msg[name]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+// internal/server/games.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/internal/games"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// applyGame keeps the scores bots set with setGameScore, so that
+// getGameHighScores returns the leaderboard they built. Games without
+// scores get generated high scores.
+func (h *BotHandler) applyGame(st *session.State, token, method string, params map[string]interface{}, result interface{}) (interface{}, *tgerrors.Error) {
+	if method != "setGameScore" && method != "getGameHighScores" {
+		return result, nil
+	}
+	game, ok := gameParam(params)
+	if !ok {
+		return result, nil
+	}
+	userID, _ := int64Value(params["user_id"])
+
+	switch method {
+	case "setGameScore":
+		score, _ := int64Value(params["score"])
+		if err := st.Games.Set(game, userID, score, boolParam(params["force"])); err != nil {
+			return nil, tgerrors.BotScoreNotModified()
+		}
+		// Inline messages answer True, other messages the game message
+		if game.InlineMessageID != "" {
+			return true, nil
+		}
+		if msg, ok := st.Messages.Get(game.ChatID, game.MessageID); ok {
+			return msg, nil
+		}
+		msg, ok := result.(map[string]interface{})
+		if !ok {
+			msg, _ = st.Faker.Generate("Message", params).(map[string]interface{})
+		}
+		msg["message_id"] = game.MessageID
+		return msg, nil
+
+	case "getGameHighScores":
+		list, ok := st.Games.HighScores(game, userID)
+		if !ok {
+			return result, nil
+		}
+		scores := make([]interface{}, len(list))
+		for i, hs := range list {
+			scores[i] = map[string]interface{}{
+				"position": hs.Position,
+				"user":     h.memberUser(st, token, hs.UserID),
+				"score":    hs.Score,
+			}
+		}
+		return scores, nil
+	}
+	return result, nil
+}
+
+// gameParam returns the game message a call targets: inline_message_id,
+// or chat_id and message_id.
+func gameParam(params map[string]interface{}) (games.Game, bool) {
+	if id, _ := params["inline_message_id"].(string); id != "" {
+		return games.Game{InlineMessageID: id}, true
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	messageID, ok := messages.MessageID(params["message_id"])
+	if chatID == "" || !ok {
+		return games.Game{}, false
+	}
+	return games.Game{ChatID: chatID, MessageID: messageID}, true
+}
@@ -0,0 +1,118 @@
+// internal/server/inputmedia.go
+package server
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// fileIDPattern matches the characters file IDs are made of.
+var fileIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// captionFields are the message fields an InputMedia sets along with its
+// media.
+var captionFields = []string{"caption", "caption_entities", "show_caption_above_media", "has_media_spoiler"}
+
+// checkMedia checks the InputMedia of sendMediaGroup and editMessageMedia
+// calls.
+func checkMedia(method string, params map[string]interface{}) *tgerrors.Error {
+	switch method {
+	case "sendMediaGroup":
+		return checkMediaGroup(params)
+	case "editMessageMedia":
+		return checkInputMedia(params, params["media"], false)
+	}
+	return nil
+}
+
+// checkInputMedia checks an InputMedia against its spec: it must have a
+// known type, the fields that type requires, and files that are file IDs,
+// URLs, or attach:// references to parts uploaded with the request.
+// Albums only take the media types that can be grouped, and captions must
+// parse in the item's parse_mode.
+func checkInputMedia(params map[string]interface{}, v interface{}, album bool) *tgerrors.Error {
+	item := objectParam(v)
+	if item == nil {
+		return tgerrors.CantParseInputMedia("expected an Object")
+	}
+	kind, ok := item["type"].(string)
+	if !ok {
+		return tgerrors.CantParseInputMedia(`field "type" must be of type String`)
+	}
+	spec, ok := inputMediaSpec(kind)
+	if _, groupable := mediaRights[kind]; !ok || (album && !groupable) {
+		return tgerrors.CantParseInputMedia(`type "` + kind + `" is unsupported`)
+	}
+	for _, f := range spec.Fields {
+		if _, ok := item[f.Name]; f.Required && !ok {
+			return tgerrors.CantParseInputMedia(`field "` + f.Name + `" is not specified`)
+		}
+	}
+	for _, name := range []string{"media", "thumbnail"} {
+		if v, ok := item[name]; ok && !validInputFile(params, v) {
+			return tgerrors.WrongFileIdentifier()
+		}
+	}
+	return checkFormatting(spec.Fields, item)
+}
+
+// inputMediaSpec returns the spec of the InputMedia subtype with a type
+// field, such as InputMediaPhoto for "photo".
+func inputMediaSpec(kind string) (gen.TypeSpec, bool) {
+	if kind == "" {
+		return gen.TypeSpec{}, false
+	}
+	name := "InputMedia" + strings.ToUpper(kind[:1]) + kind[1:]
+	for _, subtype := range gen.Types["InputMedia"].Subtypes {
+		if subtype == name {
+			return gen.Types[name], true
+		}
+	}
+	return gen.TypeSpec{}, false
+}
+
+// validInputFile reports whether an InputMedia file is a file ID, an HTTP
+// URL, or an attach:// reference to an uploaded part.
+func validInputFile(params map[string]interface{}, v interface{}) bool {
+	ref, ok := v.(string)
+	switch {
+	case !ok || ref == "":
+		return false
+	case strings.HasPrefix(ref, attachPrefix):
+		_, ok := inputFile(params, ref)
+		return ok
+	case strings.HasPrefix(ref, "http://"), strings.HasPrefix(ref, "https://"):
+		return true
+	default:
+		return fileIDPattern.MatchString(ref)
+	}
+}
+
+// applyMediaEdit replaces the media of the message editMessageMedia
+// returns with the media it was given.
+func applyMediaEdit(st *session.State, method string, params map[string]interface{}, result interface{}) interface{} {
+	msg, ok := result.(map[string]interface{})
+	if method != "editMessageMedia" || !ok {
+		return result
+	}
+	item := objectParam(params["media"])
+	kind, _ := item["type"].(string)
+	generated, _ := st.Faker.Generate("Message", map[string]interface{}{kind: true}).(map[string]interface{})
+	media, ok := generated[kind]
+	if !ok {
+		return result
+	}
+	if file, ok := inputFile(params, item["media"]); ok {
+		applyInputFile(media, file)
+	}
+	for _, name := range append(append([]string{}, mediaFields...), captionFields...) {
+		delete(msg, name)
+	}
+	msg[kind] = media
+	applyInputMedia(msg, kind, item)
+	return msg
+}
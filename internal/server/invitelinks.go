@@ -0,0 +1,111 @@
+// internal/server/invitelinks.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// inviteLinkParams are the parameters setting fields of the same name on
+// an invite link.
+var inviteLinkParams = []string{"name", "expire_date", "member_limit", "creates_join_request", "subscription_period", "subscription_price"}
+
+// applyInviteLink keeps the invite links bots create, so that the links
+// they edit, revoke, and export are the ones they were given. Links the
+// store doesn't know are recorded as they are first used. getChat
+// returns the primary link exported last.
+func (h *BotHandler) applyInviteLink(st *session.State, token, method string, params map[string]interface{}, result interface{}) (interface{}, *tgerrors.Error) {
+	chatID := messages.ChatKey(params["chat_id"])
+	if chatID == "" {
+		return result, nil
+	}
+	inviteLink, _ := params["invite_link"].(string)
+
+	switch method {
+	case "createChatInviteLink", "createChatSubscriptionInviteLink":
+		link := h.newInviteLink(st, token, params, result)
+		st.InviteLinks.Add(chatID, link)
+		return link, nil
+
+	case "editChatInviteLink", "editChatSubscriptionInviteLink":
+		changes := inviteLinkChanges(params)
+		link, known, resp := st.InviteLinks.Edit(chatID, inviteLink, changes)
+		if resp != nil {
+			return nil, resp
+		}
+		if !known {
+			link = h.newInviteLink(st, token, params, result)
+			link["invite_link"] = inviteLink
+			st.InviteLinks.Add(chatID, link)
+		}
+		return link, nil
+
+	case "revokeChatInviteLink":
+		link, known := st.InviteLinks.Revoke(chatID, inviteLink)
+		if !known {
+			link = h.newInviteLink(st, token, params, result)
+			link["invite_link"] = inviteLink
+			link["is_revoked"] = true
+			st.InviteLinks.Add(chatID, link)
+		}
+		return link, nil
+
+	case "exportChatInviteLink":
+		link := h.newInviteLink(st, token, params, nil)
+		link["is_primary"] = true
+		st.InviteLinks.Add(chatID, link)
+		return link["invite_link"], nil
+
+	case "getChat":
+		chat, ok := result.(map[string]interface{})
+		if link, primary := st.InviteLinks.Primary(chatID); ok && primary {
+			chat["invite_link"] = link["invite_link"]
+		}
+	}
+	return result, nil
+}
+
+// newInviteLink returns a ChatInviteLink created by the bot with the
+// fields a call sets. Its URL is the generated one, if any.
+func (h *BotHandler) newInviteLink(st *session.State, token string, params map[string]interface{}, result interface{}) map[string]interface{} {
+	generated, _ := result.(map[string]interface{})
+	url, _ := generated["invite_link"].(string)
+	if url == "" {
+		generated, _ = st.Faker.Generate("ChatInviteLink", params).(map[string]interface{})
+		url, _ = generated["invite_link"].(string)
+	}
+	link := map[string]interface{}{
+		"invite_link":          url,
+		"creator":              h.botUser(token),
+		"creates_join_request": false,
+		"is_primary":           false,
+		"is_revoked":           false,
+	}
+	for name, v := range inviteLinkChanges(params) {
+		link[name] = v
+	}
+	return link
+}
+
+// inviteLinkChanges returns the invite link fields a call sets.
+func inviteLinkChanges(params map[string]interface{}) map[string]interface{} {
+	changes := make(map[string]interface{})
+	for _, name := range inviteLinkParams {
+		v, ok := params[name]
+		if !ok {
+			continue
+		}
+		switch name {
+		case "name":
+			changes[name] = v
+		case "creates_join_request":
+			changes[name] = boolParam(v)
+		default:
+			if n, ok := int64Value(v); ok {
+				changes[name] = n
+			}
+		}
+	}
+	return changes
+}
@@ -0,0 +1,116 @@
+// internal/server/keyboard.go
+package server
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/watzon/tg-mock/gen"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// Inline keyboard limits, as Telegram enforces them. The spec doesn't
+// state them.
+const (
+	maxRowButtons      = 8
+	maxKeyboardButtons = 100
+)
+
+// checkReplyMarkup checks the inline keyboard of a call's reply_markup:
+// rows are arrays of buttons, each button has a text and exactly one
+// action, callback data is 1 to 64 bytes as the spec says, URLs are valid,
+// and the keyboard has at most 8 buttons per row and 100 buttons in total.
+func checkReplyMarkup(params map[string]interface{}) *tgerrors.Error {
+	markup := objectParam(params["reply_markup"])
+	keyboard, ok := markup["inline_keyboard"]
+	if !ok {
+		return nil
+	}
+	rows, ok := keyboard.([]interface{})
+	if !ok {
+		return tgerrors.InlineKeyboardInvalid()
+	}
+
+	buttons := 0
+	for _, r := range rows {
+		row, ok := r.([]interface{})
+		if !ok {
+			return tgerrors.InlineKeyboardInvalid()
+		}
+		buttons += len(row)
+		if len(row) > maxRowButtons || buttons > maxKeyboardButtons {
+			return tgerrors.ReplyMarkupTooLong()
+		}
+		for _, b := range row {
+			if resp := checkInlineButton(b); resp != nil {
+				return resp
+			}
+		}
+	}
+	return nil
+}
+
+// checkInlineButton checks one InlineKeyboardButton. Its actions are the
+// optional fields of the spec, such as url and callback_data. Fields that
+// are null, or a pay that is false, are no action, as clients that send
+// every field leave them so.
+func checkInlineButton(v interface{}) *tgerrors.Error {
+	button, ok := v.(map[string]interface{})
+	if !ok {
+		return tgerrors.CantParseInlineKeyboardButton("InlineKeyboardButton must be an Object")
+	}
+	if _, ok := button["text"].(string); !ok {
+		return tgerrors.CantParseInlineKeyboardButton(`Field "text" must be of type String`)
+	}
+
+	var actions []string
+	for _, f := range gen.Types["InlineKeyboardButton"].Fields {
+		if value := button[f.Name]; value != nil && value != false && !f.Required {
+			actions = append(actions, f.Name)
+		}
+	}
+	switch len(actions) {
+	case 0:
+		return tgerrors.CantParseInlineKeyboardButton("Text buttons are unallowed in the inline keyboard")
+	case 1:
+	default:
+		return tgerrors.CantParseInlineKeyboardButton("Button must have exactly one of the fields " + strings.Join(actions, ", "))
+	}
+
+	switch actions[0] {
+	case "callback_data":
+		data, ok := button["callback_data"].(string)
+		if !ok || !fitsLength(data, typeConstraint("InlineKeyboardButton", "callback_data")) {
+			return tgerrors.ButtonDataInvalid()
+		}
+	case "url":
+		if !validButtonURL(button["url"], "http", "https", "tg") {
+			return tgerrors.ButtonURLInvalid()
+		}
+	case "login_url", "web_app":
+		// Login and Web App URLs must be HTTPS
+		if !validButtonURL(objectParam(button[actions[0]])["url"], "https") {
+			return tgerrors.ButtonURLInvalid()
+		}
+	}
+	return nil
+}
+
+// validButtonURL reports whether v is an absolute URL with one of the
+// given schemes.
+func validButtonURL(v interface{}, schemes ...string) bool {
+	link, ok := v.(string)
+	if !ok {
+		return false
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,99 @@
+// internal/server/mediagroup.go
+package server
+
+import (
+	"strconv"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// checkMediaGroup checks the media of a sendMediaGroup call: there must
+// be as many valid InputMedia as the spec allows, 2 to 10, and audio files and documents can only be
+// grouped with media of the same type.
+func checkMediaGroup(params map[string]interface{}) *tgerrors.Error {
+	media := arrayParam(params["media"])
+	if !inBounds(int64(len(media)), fieldConstraint(gen.Methods["sendMediaGroup"].Fields, "media")) {
+		return tgerrors.MediaGroupSizeInvalid()
+	}
+	kinds := map[string]bool{}
+	for _, item := range media {
+		if err := checkInputMedia(params, item, true); err != nil {
+			return err
+		}
+		kind, _ := objectParam(item)["type"].(string)
+		kinds[kind] = true
+	}
+	if (kinds["audio"] || kinds["document"]) && len(kinds) > 1 {
+		return tgerrors.MediaGroupMixed()
+	}
+	return nil
+}
+
+// applyMediaGroup answers sendMediaGroup with one message per media,
+// sent together: they have consecutive IDs, the same date, and share a
+// media_group_id. Each message has the media, caption, and spoiler given
+// for it.
+func applyMediaGroup(st *session.State, method string, params map[string]interface{}, result interface{}) interface{} {
+	if method != "sendMediaGroup" {
+		return result
+	}
+	chatID, ok := int64Value(params["chat_id"])
+	if !ok {
+		return result
+	}
+
+	groupID := strconv.FormatInt(st.Faker.RandomInt64(1e17, 1e18-1), 10)
+	var date interface{}
+	var album []interface{}
+	for _, v := range arrayParam(params["media"]) {
+		item := objectParam(v)
+		kind, _ := item["type"].(string)
+		msg, _ := st.Faker.Generate("Message", map[string]interface{}{"chat_id": chatID, kind: true}).(map[string]interface{})
+		if msg == nil {
+			continue
+		}
+		if date == nil {
+			date = msg["date"]
+		}
+		msg["date"] = date
+		msg["media_group_id"] = groupID
+		if file, ok := inputFile(params, item["media"]); ok {
+			applyInputFile(msg[kind], file)
+		}
+		applyInputMedia(msg, kind, item)
+		album = append(album, msg)
+	}
+	if len(album) == 0 {
+		return result
+	}
+	return album
+}
+
+// inputMediaFields are the InputMedia fields that describe the media
+// itself, by media type.
+var inputMediaFields = map[string][]string{
+	"audio": {"duration", "performer", "title"},
+	"video": {"width", "height", "duration", "supports_streaming"},
+}
+
+// applyInputMedia copies what an InputMedia says about its message and
+// media into a generated message.
+func applyInputMedia(msg map[string]interface{}, kind string, item map[string]interface{}) {
+	if caption, entities, _ := formatText(item, "caption", "caption_entities"); caption != "" {
+		setFormatted(msg, "caption", "caption_entities", caption, entities)
+		if boolParam(item["show_caption_above_media"]) {
+			msg["show_caption_above_media"] = true
+		}
+	}
+	if boolParam(item["has_spoiler"]) {
+		msg["has_media_spoiler"] = true
+	}
+	media, _ := msg[kind].(map[string]interface{})
+	for _, name := range inputMediaFields[kind] {
+		if v, ok := item[name]; ok && media != nil {
+			media[name] = v
+		}
+	}
+}
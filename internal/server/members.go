@@ -0,0 +1,103 @@
+// internal/server/members.go
+package server
+
+import (
+	"strconv"
+
+	"github.com/watzon/tg-mock/internal/members"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/users"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// updateMember applies the moderation methods to the members of a chat.
+func updateMember(st *session.State, method string, params map[string]interface{}) *tgerrors.Error {
+	chatID := messages.ChatKey(params["chat_id"])
+	userID, ok := users.ID(params["user_id"])
+	if chatID == "" || !ok {
+		return nil
+	}
+	until, _ := int64Value(params["until_date"])
+
+	switch method {
+	case "banChatMember":
+		return st.Members.Ban(chatID, userID, until)
+	case "unbanChatMember":
+		return st.Members.Unban(chatID, userID, boolParam(params["only_if_banned"]))
+	case "restrictChatMember":
+		independent := boolParam(params["use_independent_chat_permissions"])
+		return st.Members.Restrict(chatID, userID, objectParam(params["permissions"]), independent, until)
+	case "promoteChatMember":
+		rights := make(map[string]interface{}, len(members.AdminRights))
+		for _, right := range members.AdminRights {
+			rights[right] = boolParam(params[right])
+		}
+		return st.Members.Promote(chatID, userID, rights)
+	}
+	return nil
+}
+
+// applyMember replaces the status in a generated getChatMember result with
+// the known one, keeping the user.
+func applyMember(st *session.State, method string, params map[string]interface{}, result interface{}) {
+	if method != "getChatMember" {
+		return
+	}
+	member, ok := result.(map[string]interface{})
+	chatID := messages.ChatKey(params["chat_id"])
+	userID, idOK := users.ID(params["user_id"])
+	if !ok || chatID == "" || !idOK {
+		return
+	}
+	fields, ok := st.Members.Get(chatID, userID)
+	if !ok {
+		return
+	}
+	for name := range member {
+		if name != "user" {
+			delete(member, name)
+		}
+	}
+	for name, v := range fields {
+		member[name] = v
+	}
+}
+
+// applyChatMembers answers getChatAdministrators and getChatMemberCount
+// from the member store once it knows members of the chat, so that bots
+// see the administrators they promoted and the members that are left.
+func (h *BotHandler) applyChatMembers(st *session.State, token, method string, params map[string]interface{}, result interface{}) interface{} {
+	if method != "getChatAdministrators" && method != "getChatMemberCount" {
+		return result
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	if chatID == "" || len(st.Members.List(chatID)) == 0 {
+		return result
+	}
+	if method == "getChatMemberCount" {
+		return st.Members.Count(chatID)
+	}
+
+	admins := st.Members.Administrators(chatID)
+	list := make([]interface{}, 0, len(admins))
+	for _, m := range admins {
+		m.Fields["user"] = h.memberUser(st, token, m.UserID)
+		list = append(list, m.Fields)
+	}
+	return list
+}
+
+// memberUser returns the User of a known member: the bot itself, a seeded
+// user, or the profile generated for the ID.
+func (h *BotHandler) memberUser(st *session.State, token string, userID int64) map[string]interface{} {
+	if id, err := strconv.ParseInt(botID(token), 10, 64); err == nil && id == userID {
+		return h.botUser(token)
+	}
+	user, _ := st.Faker.Generate("User", map[string]interface{}{"user_id": userID}).(map[string]interface{})
+	if user == nil {
+		user = map[string]interface{}{"id": userID, "is_bot": false}
+	}
+	st.Users.Apply(user)
+	return user
+}
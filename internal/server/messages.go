@@ -0,0 +1,233 @@
+// internal/server/messages.go
+package server
+
+import (
+	"time"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// editMethods edit an existing message. As in Telegram, editing a message
+// without passing reply_markup removes its inline keyboard.
+var editMethods = map[string]bool{
+	"editMessageText":         true,
+	"editMessageCaption":      true,
+	"editMessageMedia":        true,
+	"editMessageLiveLocation": true,
+	"stopMessageLiveLocation": true,
+	"editMessageReplyMarkup":  true,
+}
+
+// returnsMessages reports whether a method's result is stored in the
+// message store.
+func returnsMessages(spec gen.MethodSpec) bool {
+	result := spec.Result.Primary()
+	if result.IsArray() {
+		result = result.Elem.Primary()
+	}
+	return !result.IsArray() && result.Name == "Message"
+}
+
+// trackMessages stores the messages in a successful result, applies edits
+// to stored messages, and keeps chat action state in sync. It returns the
+// result to send to the bot.
+func (h *BotHandler) trackMessages(st *session.State, method string, params map[string]interface{}, result interface{}) interface{} {
+	chatID := messages.ChatKey(params["chat_id"])
+	if method == "sendChatAction" {
+		action, _ := params["action"].(string)
+		st.ChatActions.Record(chatID, action)
+		return result
+	}
+	if editMethods[method] {
+		return applyEdit(st.Messages, st.Faker.Now(), method, chatID, params, result)
+	}
+
+	sent := false
+	switch r := result.(type) {
+	case map[string]interface{}:
+		sent = storeMessage(st.Messages, chatID, r)
+	case []interface{}:
+		for _, item := range r {
+			if msg, ok := item.(map[string]interface{}); ok {
+				sent = storeMessage(st.Messages, chatID, msg) || sent
+			}
+		}
+	}
+
+	// A new message from the bot hides its chat action
+	if sent {
+		st.ChatActions.Clear(chatID)
+	}
+	return result
+}
+
+// storeMessage stores msg under the chat_id the bot used, falling back to
+// the ID of the message's chat. It returns false if msg isn't a message.
+func storeMessage(store *messages.Store, chatID string, msg map[string]interface{}) bool {
+	chat, ok := msg["chat"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if chatID == "" {
+		chatID = messages.ChatKey(chat["id"])
+	}
+	store.Put(chatID, msg)
+	return true
+}
+
+// applyEdit applies an edit* call to the stored message and returns the
+// edited message. Messages the store doesn't know yet are adopted from the
+// generated result, so bots can edit messages sent before tracking began.
+func applyEdit(store *messages.Store, now time.Time, method, chatID string, params map[string]interface{}, result interface{}) interface{} {
+	generated, ok := result.(map[string]interface{})
+	messageID, hasID := messages.MessageID(params["message_id"])
+	if !ok || chatID == "" || !hasID {
+		// Inline messages (inline_message_id) aren't stored
+		return result
+	}
+
+	msg, found := store.Get(chatID, messageID)
+	if !found {
+		msg = generated
+		msg["message_id"] = messageID
+	}
+
+	switch method {
+	case "editMessageText":
+		// The generated message carries the parsed text and its entities
+		if text, ok := generated["text"].(string); ok {
+			entities, _ := generated["entities"].([]interface{})
+			setFormatted(msg, "text", "entities", text, entities)
+		}
+	case "editMessageCaption":
+		if caption, ok := generated["caption"].(string); ok {
+			entities, _ := generated["caption_entities"].([]interface{})
+			setFormatted(msg, "caption", "caption_entities", caption, entities)
+		} else {
+			delete(msg, "caption")
+			delete(msg, "caption_entities")
+		}
+	case "editMessageMedia":
+		// The generated message carries the new media and its caption
+		for _, name := range append(append([]string{}, mediaFields...), captionFields...) {
+			if v, ok := generated[name]; ok {
+				msg[name] = v
+			} else {
+				delete(msg, name)
+			}
+		}
+	}
+
+	// The generated message carries the parsed inline keyboard, if any
+	if markup, ok := generated["reply_markup"]; ok {
+		msg["reply_markup"] = markup
+	} else {
+		delete(msg, "reply_markup")
+	}
+	msg["edit_date"] = now.Unix()
+
+	store.Put(chatID, msg)
+	return msg
+}
+
+// runPersonas lets the personas of the chats the bot just sent messages to
+// respond. Their updates are delivered to the bot's webhook if one is set
+// and queued for getUpdates otherwise.
+func (h *BotHandler) runPersonas(st *session.State, token string, spec gen.MethodSpec, params map[string]interface{}, result interface{}) {
+	var responses []map[string]interface{}
+	for _, msg := range sentMessages(spec, result) {
+		chatID := messages.ChatKey(params["chat_id"])
+		if chat, ok := msg["chat"].(map[string]interface{}); ok && chatID == "" {
+			chatID = messages.ChatKey(chat["id"])
+		}
+		responses = append(responses, st.Personas.Respond(chatID, msg, st.Faker)...)
+	}
+	h.deliverUpdates(st, token, responses)
+}
+
+// deliverUpdates delivers updates to the bot's webhook if one is set and
+// queues them for getUpdates otherwise.
+func (h *BotHandler) deliverUpdates(st *session.State, token string, responses []map[string]interface{}) {
+	if len(responses) == 0 {
+		return
+	}
+
+	if h.webhooks.IsActive(token) {
+		// Deliver after the bot has its response, as Telegram would
+		go func() {
+			for _, update := range responses {
+				update["update_id"] = st.Faker.NextUpdateID()
+				h.webhooks.Deliver(token, update)
+			}
+		}()
+		return
+	}
+	for _, update := range responses {
+		if h.guard.Admit(guard.Queue, st.Name, st.Updates) != nil {
+			return
+		}
+		st.Updates.Add(update)
+	}
+}
+
+// sentMessages returns the new messages in the result of a method, if it
+// sends any. Edited messages aren't included.
+func sentMessages(spec gen.MethodSpec, result interface{}) []map[string]interface{} {
+	if !returnsMessages(spec) || editMethods[spec.Name] {
+		return nil
+	}
+
+	var sent []map[string]interface{}
+	switch r := result.(type) {
+	case map[string]interface{}:
+		sent = append(sent, r)
+	case []interface{}:
+		for _, item := range r {
+			if msg, ok := item.(map[string]interface{}); ok {
+				sent = append(sent, msg)
+			}
+		}
+	}
+	return sent
+}
+
+// deleteMessages removes the messages deleteMessage and deleteMessages
+// delete from the message store. Messages older than the delete window
+// can't be deleted. Both accept messages the store doesn't know, since they
+// may have been sent before tracking began; deleteMessages skips messages
+// that are too old, as Telegram does, and fails only if all of them are.
+func deleteMessages(st *session.State, method string, params map[string]interface{}) *tgerrors.Error {
+	chatID := messages.ChatKey(params["chat_id"])
+	switch method {
+	case "deleteMessage":
+		messageID, ok := messages.MessageID(params["message_id"])
+		if !ok {
+			return nil
+		}
+		if st.Messages.Remove(chatID, messageID) == messages.ErrTooOld {
+			return tgerrors.MessageCantBeDeleted()
+		}
+
+	case "deleteMessages":
+		deleted, tooOld := 0, 0
+		for _, v := range arrayParam(params["message_ids"]) {
+			messageID, ok := messages.MessageID(v)
+			if !ok {
+				continue
+			}
+			if st.Messages.Remove(chatID, messageID) == messages.ErrTooOld {
+				tooOld++
+			} else {
+				deleted++
+			}
+		}
+		if deleted == 0 && tooOld > 0 {
+			return tgerrors.MessageCantBeDeleted()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+// internal/server/paging.go
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// pageLimit returns the limit query parameter of a listing, or def if it
+// is missing or not a positive number. A def of 0 lists everything.
+func pageLimit(r *http.Request, def int) int {
+	if l := r.URL.Query().Get("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 {
+			return parsed
+		}
+	}
+	return def
+}
+
+// pageOffset returns the cursor of a listing paged by position.
+func pageOffset(r *http.Request) (int, bool) {
+	c := r.URL.Query().Get("cursor")
+	if c == "" {
+		return 0, true
+	}
+	parsed, err := strconv.Atoi(c)
+	if err != nil || parsed < 1 {
+		return 0, false
+	}
+	return parsed, true
+}
+
+// pageBounds returns the part of a listing of total items starting at
+// offset, and the cursor of the next page (nil on the last page).
+func pageBounds(total, offset, limit int) (start, end int, next interface{}) {
+	start = offset
+	if start > total {
+		start = total
+	}
+	end = total
+	if limit > 0 && start+limit < total {
+		end = start + limit
+		next = strconv.Itoa(end)
+	}
+	return start, end, next
+}
+
+// writeListing writes a listing as JSON with an ETag of its contents. If
+// the client already has that version, as told by If-None-Match, only 304
+// Not Modified is sent, so pollers don't transfer unchanged state again.
+func writeListing(w http.ResponseWriter, r *http.Request, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", "no-cache")
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(append(body, '\n'))
+}
+
+// etagMatches reports whether an If-None-Match header lists etag. Weak
+// validators match their strong form.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+// internal/server/permissions.go
+package server
+
+import (
+	"strconv"
+
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/members"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// sendRight is the permission a send method needs in a group, and what
+// the error says the bot can't send without it.
+type sendRight struct {
+	permission string
+	what       string
+}
+
+// sendRights maps send methods to the permission they need. Other methods
+// sending messages need can_send_messages.
+var sendRights = map[string]sendRight{
+	"sendPhoto":     {"can_send_photos", "photos"},
+	"sendVideo":     {"can_send_videos", "videos"},
+	"sendAudio":     {"can_send_audios", "audio files"},
+	"sendDocument":  {"can_send_documents", "documents"},
+	"sendVoice":     {"can_send_voice_notes", "voice notes"},
+	"sendVideoNote": {"can_send_video_notes", "video notes"},
+	"sendPoll":      {"can_send_polls", "polls"},
+	"sendSticker":   {"can_send_other_messages", "stickers"},
+	"sendAnimation": {"can_send_other_messages", "animations"},
+	"sendDice":      {"can_send_other_messages", "dice"},
+	"sendGame":      {"can_send_other_messages", "games"},
+}
+
+// mediaRights maps the InputMedia types of sendMediaGroup to the
+// permission they need.
+var mediaRights = map[string]sendRight{
+	"photo":    sendRights["sendPhoto"],
+	"video":    sendRights["sendVideo"],
+	"audio":    sendRights["sendAudio"],
+	"document": sendRights["sendDocument"],
+}
+
+var textRight = sendRight{"can_send_messages", "text messages"}
+
+// botMembership returns the error Telegram gives a bot calling a method
+// on a chat it was removed from, or sending a message to one it can't
+// write to. Only chats where the bot's status is known are checked; the
+// bot's status is that of its user ID in the member store.
+func botMembership(st *session.State, token string, spec gen.MethodSpec, method string, params map[string]interface{}) *tgerrors.Error {
+	chatID := messages.ChatKey(params["chat_id"])
+	botUserID, err := strconv.ParseInt(botID(token), 10, 64)
+	if chatID == "" || err != nil {
+		return nil
+	}
+	chatType := st.Chats.Type(chatID)
+	if chatType == "private" {
+		return nil
+	}
+	member, ok := st.Members.Get(chatID, botUserID)
+	if !ok {
+		return nil
+	}
+
+	status, _ := member["status"].(string)
+	if status == members.StatusKicked {
+		return botKicked(chatType)
+	}
+	if !members.Present(member) {
+		return notMember(chatType)
+	}
+	if !returnsMessages(spec) || editMethods[method] {
+		return nil
+	}
+
+	granted := member
+	switch status {
+	case members.StatusCreator:
+		return nil
+	case members.StatusAdministrator:
+		if chatType == "channel" && member["can_post_messages"] != true {
+			return tgerrors.ChatWriteForbidden()
+		}
+		return nil
+	case members.StatusMember:
+		if chatType == "channel" {
+			return tgerrors.ChatWriteForbidden()
+		}
+		// Members have the default permissions of the chat, when known
+		permissions, ok := st.Chats.Field(chatID, "permissions")
+		if !ok {
+			return nil
+		}
+		granted, _ = permissions.(map[string]interface{})
+	}
+	for _, right := range neededRights(method, params) {
+		if granted[right.permission] != true {
+			return tgerrors.NotEnoughRightsSend(right.what)
+		}
+	}
+	return nil
+}
+
+// neededRights returns the permissions a call sending messages needs.
+func neededRights(method string, params map[string]interface{}) []sendRight {
+	if method == "sendMediaGroup" {
+		var rights []sendRight
+		for _, item := range arrayParam(params["media"]) {
+			kind, _ := objectParam(item)["type"].(string)
+			if right, ok := mediaRights[kind]; ok {
+				rights = append(rights, right)
+			}
+		}
+		return rights
+	}
+	if right, ok := sendRights[method]; ok {
+		return []sendRight{right}
+	}
+	return []sendRight{textRight}
+}
+
+// botKicked returns the error for a bot kicked from a chat of a type.
+func botKicked(chatType string) *tgerrors.Error {
+	switch chatType {
+	case "group":
+		return tgerrors.BotKickedGroup()
+	case "channel":
+		return tgerrors.BotKickedChannel()
+	}
+	return tgerrors.BotKickedSupergroup()
+}
+
+// notMember returns the error for a bot that isn't in a chat of a type.
+func notMember(chatType string) *tgerrors.Error {
+	switch chatType {
+	case "group":
+		return tgerrors.NotMemberGroup()
+	case "channel":
+		return tgerrors.NotMemberChannel()
+	}
+	return tgerrors.NotMemberSupergroup()
+}
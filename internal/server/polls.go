@@ -0,0 +1,88 @@
+// internal/server/polls.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// applyPoll keeps the polls bots send, with the question and options they
+// gave, and answers stopPoll with the stored poll and its votes.
+func applyPoll(st *session.State, method string, params map[string]interface{}, result interface{}) (interface{}, *tgerrors.Error) {
+	chatID := messages.ChatKey(params["chat_id"])
+	switch method {
+	case "sendPoll":
+		msg, ok := result.(map[string]interface{})
+		messageID, hasID := messages.MessageID(msg["message_id"])
+		if !ok || !hasID {
+			return result, nil
+		}
+		poll := newPoll(st, params)
+		msg["poll"] = poll
+		st.Polls.Add(chatID, messageID, poll)
+
+	case "stopPoll":
+		messageID, ok := messages.MessageID(params["message_id"])
+		if !ok {
+			return result, nil
+		}
+		poll, known, err := st.Polls.Stop(chatID, messageID)
+		if err != nil {
+			return nil, tgerrors.PollAlreadyClosed()
+		}
+		if known {
+			return poll, nil
+		}
+	}
+	return result, nil
+}
+
+// newPoll returns the Poll a sendPoll call creates, without votes.
+func newPoll(st *session.State, params map[string]interface{}) map[string]interface{} {
+	generated, _ := st.Faker.Generate("Poll", params).(map[string]interface{})
+	options := []interface{}{}
+	for _, item := range arrayParam(params["options"]) {
+		option := map[string]interface{}{"voter_count": 0}
+		if text, ok := item.(string); ok {
+			option["text"] = text
+		} else if input := objectParam(item); input != nil {
+			option["text"] = input["text"]
+			if entities := arrayParam(input["text_entities"]); entities != nil {
+				option["text_entities"] = entities
+			}
+		}
+		options = append(options, option)
+	}
+
+	poll := map[string]interface{}{
+		"id":                      generated["id"],
+		"question":                params["question"],
+		"options":                 options,
+		"total_voter_count":       0,
+		"is_closed":               boolParam(params["is_closed"]),
+		"is_anonymous":            true,
+		"type":                    "regular",
+		"allows_multiple_answers": boolParam(params["allows_multiple_answers"]),
+	}
+	if v, ok := params["is_anonymous"]; ok {
+		poll["is_anonymous"] = boolParam(v)
+	}
+	if kind, ok := params["type"].(string); ok && kind != "" {
+		poll["type"] = kind
+	}
+	for _, name := range []string{"correct_option_id", "open_period", "close_date"} {
+		if n, ok := int64Value(params[name]); ok {
+			poll[name] = n
+		}
+	}
+	if explanation, ok := params["explanation"].(string); ok {
+		poll["explanation"] = explanation
+	}
+	for _, name := range []string{"question_entities", "explanation_entities"} {
+		if entities := arrayParam(params[name]); entities != nil {
+			poll[name] = entities
+		}
+	}
+	return poll
+}
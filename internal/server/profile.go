@@ -0,0 +1,112 @@
+// internal/server/profile.go
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/watzon/tg-mock/internal/botsettings"
+	"github.com/watzon/tg-mock/internal/config"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/tokens"
+)
+
+// botProfileConfig converts a bot profile from the config file.
+func botProfileConfig(pc *config.BotProfileConfig) *tokens.BotProfile {
+	if pc == nil {
+		return nil
+	}
+	return &tokens.BotProfile{
+		FirstName:               pc.FirstName,
+		LastName:                pc.LastName,
+		Username:                pc.Username,
+		CanJoinGroups:           pc.CanJoinGroups,
+		CanReadAllGroupMessages: pc.CanReadAllGroupMessages,
+		SupportsInlineQueries:   pc.SupportsInlineQueries,
+		CanConnectToBusiness:    pc.CanConnectToBusiness,
+		HasMainWebApp:           pc.HasMainWebApp,
+	}
+}
+
+// botUser returns the User of the bot a token belongs to. Its ID is the
+// numeric part of the token, as in Telegram, and its names come from the
+// token's profile or bot_name.
+func (h *BotHandler) botUser(token string) map[string]interface{} {
+	idPart, _, _ := strings.Cut(token, ":")
+	id, _ := strconv.ParseInt(idPart, 10, 64)
+
+	user := map[string]interface{}{
+		"id":         id,
+		"is_bot":     true,
+		"first_name": "Bot " + idPart,
+		"username":   "bot" + idPart + "_bot",
+	}
+	info, _ := h.registry.Get(token)
+	if info.BotName != "" {
+		user["first_name"] = info.BotName
+		user["username"] = info.BotName
+	}
+	if p := info.Profile; p != nil {
+		if p.FirstName != "" {
+			user["first_name"] = p.FirstName
+		}
+		if p.LastName != "" {
+			user["last_name"] = p.LastName
+		}
+		if p.Username != "" {
+			user["username"] = p.Username
+		}
+	}
+	return user
+}
+
+// botProfile returns the User getMe answers with: the bot's User and what
+// it is allowed to do.
+func (h *BotHandler) botProfile(token string) map[string]interface{} {
+	user := h.botUser(token)
+	user["can_join_groups"] = true
+	user["can_read_all_group_messages"] = false
+	user["supports_inline_queries"] = false
+	user["can_connect_to_business"] = false
+	user["has_main_web_app"] = false
+	if info, _ := h.registry.Get(token); info.Profile != nil {
+		p := info.Profile
+		if p.CanJoinGroups != nil {
+			user["can_join_groups"] = *p.CanJoinGroups
+		}
+		user["can_read_all_group_messages"] = p.CanReadAllGroupMessages
+		user["supports_inline_queries"] = p.SupportsInlineQueries
+		user["can_connect_to_business"] = p.CanConnectToBusiness
+		user["has_main_web_app"] = p.HasMainWebApp
+	}
+	return user
+}
+
+// applyBotProfile makes getMe describe the bot the token belongs to, so
+// that it agrees with the token, the messages the bot sends, and the name
+// it set with setMyName. The User is the profile alone, so fields the
+// profile doesn't set are left out; fields set by a scenario's
+// response_data are kept.
+func (h *BotHandler) applyBotProfile(st *session.State, token, method string, overrides map[string]interface{}, result interface{}) {
+	if method != "getMe" {
+		return
+	}
+	user, ok := result.(map[string]interface{})
+	if !ok {
+		return
+	}
+	profile := h.botProfile(token)
+	if name, ok := st.Bots.Text(token, botsettings.TextName, ""); ok {
+		profile["first_name"] = name
+	}
+	for field := range user {
+		if _, overridden := overrides[field]; !overridden {
+			delete(user, field)
+		}
+	}
+	for field, value := range profile {
+		if _, overridden := overrides[field]; !overridden {
+			user[field] = value
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// internal/server/quote.go
+package server
+
+import (
+	"encoding/json"
+	"unicode/utf16"
+
+	"github.com/watzon/tg-mock/internal/markup"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// maxQuoteLength is the longest quote Telegram accepts, in UTF-16 code
+// units after entities parsing.
+const maxQuoteLength = 1024
+
+// reply is what a send call's reply_parameters resolve to.
+type reply struct {
+	// message is the replied-to message, if it is known and in the same
+	// chat.
+	message map[string]interface{}
+	// quote is the TextQuote of the reply, if it quotes the message.
+	quote map[string]interface{}
+}
+
+// resolveReply looks up the message a send call replies to and checks its
+// quote against the message's text or caption. Messages the bot sent and
+// messages injected as updates can be replied to and quoted. Replies to
+// other messages fail, unless allow_sending_without_reply is set, in which
+// case the message is sent as no reply at all.
+func resolveReply(st *session.State, params map[string]interface{}) (*reply, *tgerrors.Error) {
+	rp := replyParameters(params)
+	if rp == nil {
+		return nil, nil
+	}
+	messageID, ok := messages.MessageID(rp["message_id"])
+	if !ok {
+		return nil, nil
+	}
+	chatID := messages.ChatKey(params["chat_id"])
+	replyChat := messages.ChatKey(rp["chat_id"])
+	if replyChat == "" {
+		replyChat = chatID
+	}
+
+	original, found := st.Messages.Get(replyChat, messageID)
+	if !found {
+		original, found = st.Archive.Message(replyChat, messageID)
+	}
+	if !found {
+		if boolParam(rp["allow_sending_without_reply"]) {
+			return nil, nil
+		}
+		return nil, tgerrors.MessageToReplyNotFound()
+	}
+	result := &reply{}
+	if replyChat == chatID {
+		// As in Telegram, the replied-to message doesn't carry its own reply
+		delete(original, "reply_to_message")
+		result.message = original
+	}
+
+	quote, _ := rp["quote"].(string)
+	if quote == "" {
+		return result, nil
+	}
+	mode, _ := rp["quote_parse_mode"].(string)
+	plain, parsed, err := markup.Parse(quote, mode)
+	if err != nil {
+		return nil, tgerrors.CantParseEntities(err.Error())
+	}
+	text := utf16.Encode([]rune(plain))
+	if len(text) == 0 || len(text) > maxQuoteLength {
+		return nil, tgerrors.QuoteTextInvalid()
+	}
+
+	position := -1
+	if p, ok := rp["quote_position"].(float64); ok {
+		position = int(p)
+	}
+	source, _ := original["text"].(string)
+	if source == "" {
+		source, _ = original["caption"].(string)
+	}
+	position, ok = findQuote(utf16.Encode([]rune(source)), text, position)
+	if !ok {
+		return nil, tgerrors.QuoteTextInvalid()
+	}
+
+	result.quote = map[string]interface{}{
+		"text":      string(utf16.Decode(text)),
+		"position":  position,
+		"is_manual": true,
+	}
+	if entities, ok := rp["quote_entities"].([]interface{}); ok && len(entities) > 0 {
+		result.quote["entities"] = entities
+	} else if len(parsed) > 0 {
+		result.quote["entities"] = entityFields(parsed)
+	}
+	return result, nil
+}
+
+// replyParameters returns the ReplyParameters of a send call. Calls made
+// before Bot API 7.0 pass reply_to_message_id and
+// allow_sending_without_reply instead, which are still accepted.
+func replyParameters(params map[string]interface{}) map[string]interface{} {
+	if rp := objectParam(params["reply_parameters"]); rp != nil {
+		return rp
+	}
+	if _, ok := params["reply_to_message_id"]; !ok {
+		return nil
+	}
+	return map[string]interface{}{
+		"message_id":                  params["reply_to_message_id"],
+		"allow_sending_without_reply": params["allow_sending_without_reply"],
+	}
+}
+
+// apply adds the replied-to message and the quote to a sent message, or
+// to each message of an album.
+func (r *reply) apply(result interface{}) {
+	if album, ok := result.([]interface{}); ok {
+		for _, msg := range album {
+			r.apply(msg)
+		}
+		return
+	}
+	msg, ok := result.(map[string]interface{})
+	if r == nil || !ok {
+		return
+	}
+	if r.message != nil {
+		msg["reply_to_message"] = r.message
+	}
+	if r.quote != nil {
+		msg["quote"] = r.quote
+	}
+}
+
+// findQuote returns the position of quote in source, both in UTF-16 code
+// units. If quote occurs more than once, the occurrence closest to want
+// is used, as Telegram does with quote_position.
+func findQuote(source, quote []uint16, want int) (int, bool) {
+	best := -1
+	for i := 0; i+len(quote) <= len(source); i++ {
+		if !equalUnits(source[i:i+len(quote)], quote) {
+			continue
+		}
+		if best < 0 || distance(i, want) < distance(best, want) {
+			best = i
+		}
+	}
+	return best, best >= 0
+}
+
+func equalUnits(a, b []uint16) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func distance(a, b int) int {
+	if b < 0 {
+		return a
+	}
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
+
+// objectParam returns an object parameter. Form and query parameters carry
+// objects as JSON strings.
+func objectParam(v interface{}) map[string]interface{} {
+	if str, ok := v.(string); ok {
+		var decoded map[string]interface{}
+		if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+			return nil
+		}
+		return decoded
+	}
+	obj, _ := v.(map[string]interface{})
+	return obj
+}
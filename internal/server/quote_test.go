@@ -0,0 +1,34 @@
+// internal/server/quote_test.go
+package server
+
+import (
+	"testing"
+	"unicode/utf16"
+)
+
+func TestFindQuote(t *testing.T) {
+	units := func(s string) []uint16 { return utf16.Encode([]rune(s)) }
+
+	tests := []struct {
+		name   string
+		source string
+		quote  string
+		want   int
+		pos    int
+		ok     bool
+	}{
+		{"first occurrence", "ha ha ha", "ha", -1, 0, true},
+		{"closest to position", "ha ha ha", "ha", 5, 6, true},
+		{"utf-16 positions", "😀 hello", "hello", -1, 3, true},
+		{"missing", "hello world", "bye", -1, 0, false},
+		{"longer than source", "hi", "hello", -1, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos, ok := findQuote(units(tt.source), units(tt.quote), tt.want)
+			if ok != tt.ok || (ok && pos != tt.pos) {
+				t.Errorf("expected %d %v, got %d %v", tt.pos, tt.ok, pos, ok)
+			}
+		})
+	}
+}
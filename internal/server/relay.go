@@ -0,0 +1,38 @@
+// internal/server/relay.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/messages"
+)
+
+// relayToBots delivers the messages a bot just sent to a shared group chat
+// to the other bots in that group, as message updates sent by the bot.
+func (h *BotHandler) relayToBots(token string, spec gen.MethodSpec, params map[string]interface{}, result interface{}) {
+	for _, msg := range sentMessages(spec, result) {
+		chatID := messages.ChatKey(params["chat_id"])
+		if chat, ok := msg["chat"].(map[string]interface{}); ok && chatID == "" {
+			chatID = messages.ChatKey(chat["id"])
+		}
+		for _, member := range h.groups.Route(chatID, token) {
+			relayed := make(map[string]interface{}, len(msg))
+			for k, v := range msg {
+				relayed[k] = v
+			}
+			relayed["from"] = h.botUser(token)
+			update := map[string]interface{}{"message": relayed}
+
+			st := h.sessions.Get(member.Session)
+			if h.webhooks.IsActive(member.Token) {
+				update["update_id"] = st.Faker.NextUpdateID()
+				go h.webhooks.Deliver(member.Token, update)
+				continue
+			}
+			if h.guard.Admit(guard.Queue, st.Name, st.Updates) != nil {
+				continue
+			}
+			st.Updates.Add(update)
+		}
+	}
+}
@@ -27,12 +27,10 @@ func (r *Responder) Generate(spec gen.MethodSpec, params map[string]interface{})
 // GenerateWithOverrides produces a response with user-specified overrides.
 // The overrides map allows scenarios to specify custom values for specific fields.
 func (r *Responder) GenerateWithOverrides(spec gen.MethodSpec, params, overrides map[string]interface{}) (interface{}, error) {
-	if len(spec.Returns) == 0 {
+	if spec.Result.Base() == "" {
 		return true, nil
 	}
-
-	returnType := spec.Returns[0]
-	return r.faker.GenerateWithOverrides(returnType, params, overrides), nil
+	return r.faker.GenerateType(spec.Result, params, overrides), nil
 }
 
 // ExecuteMethod implements the webhook.MethodExecutor interface.
@@ -105,6 +105,26 @@ func TestGenerateResponse(t *testing.T) {
 		}
 	})
 
+	t.Run("getChatAdministrators returns array of ChatMember", func(t *testing.T) {
+		spec := gen.Methods["getChatAdministrators"]
+		params := map[string]interface{}{"chat_id": int64(12345)}
+
+		result, err := r.Generate(spec, params)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		arr, ok := result.([]interface{})
+		if !ok {
+			t.Fatalf("expected []interface{}, got %T", result)
+		}
+		for _, item := range arr {
+			if _, ok := item.(map[string]interface{}); !ok {
+				t.Errorf("expected ChatMember object, got %T", item)
+			}
+		}
+	})
+
 	t.Run("message IDs are unique and incrementing", func(t *testing.T) {
 		f2 := faker.New(faker.Config{Seed: 12345})
 		r2 := NewResponder(f2) // Fresh responder for this test
@@ -185,6 +205,50 @@ func TestGenerateResponse(t *testing.T) {
 			t.Error("file_path should exist")
 		}
 	})
+
+	t.Run("union returns generate the first alternative", func(t *testing.T) {
+		spec := gen.Methods["editMessageText"]
+		if !spec.Result.IsUnion() || spec.Result.String() != "Message or Boolean" {
+			t.Fatalf("unexpected return type %s", spec.Result)
+		}
+
+		result, err := r.Generate(spec, map[string]interface{}{"chat_id": 1, "message_id": 2, "text": "hi"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if _, ok := result.(map[string]interface{}); !ok {
+			t.Fatalf("expected a Message, got %T", result)
+		}
+	})
+
+	t.Run("nested arrays", func(t *testing.T) {
+		spec := gen.MethodSpec{
+			Name:    "nested",
+			Returns: []string{"Array of Array of PhotoSize"},
+			Result:  gen.ParseType("Array of Array of PhotoSize"),
+		}
+		if spec.Result.Base() != "PhotoSize" || spec.Result.String() != spec.Returns[0] {
+			t.Fatalf("unexpected parse %+v", spec.Result)
+		}
+
+		result, err := r.Generate(spec, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		outer, ok := result.([]interface{})
+		if !ok {
+			t.Fatalf("expected an array, got %T", result)
+		}
+		for _, item := range outer {
+			inner, ok := item.([]interface{})
+			if !ok || len(inner) != 3 {
+				t.Fatalf("expected arrays of 3 PhotoSizes, got %#v", item)
+			}
+			if _, ok := inner[0].(map[string]interface{})["file_id"]; !ok {
+				t.Errorf("expected a PhotoSize, got %#v", inner[0])
+			}
+		}
+	})
 }
 
 func TestGenerateWithOverrides(t *testing.T) {
@@ -3,19 +3,61 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"log"
+	"mime"
+	"net"
 	"net/http"
+	"path"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/adversarial"
+	"github.com/watzon/tg-mock/internal/apiversion"
+	"github.com/watzon/tg-mock/internal/archive"
+	"github.com/watzon/tg-mock/internal/boosts"
+	"github.com/watzon/tg-mock/internal/botgroup"
+	"github.com/watzon/tg-mock/internal/botsettings"
+	"github.com/watzon/tg-mock/internal/callbackquery"
+	"github.com/watzon/tg-mock/internal/chaos"
+	"github.com/watzon/tg-mock/internal/chataction"
+	"github.com/watzon/tg-mock/internal/chats"
+	"github.com/watzon/tg-mock/internal/clock"
+	"github.com/watzon/tg-mock/internal/compat"
 	"github.com/watzon/tg-mock/internal/config"
+	"github.com/watzon/tg-mock/internal/dashboard"
+	"github.com/watzon/tg-mock/internal/events"
 	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/floodlimit"
+	"github.com/watzon/tg-mock/internal/games"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/hooks"
+	"github.com/watzon/tg-mock/internal/inlinequery"
 	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/instance"
+	"github.com/watzon/tg-mock/internal/invitelinks"
+	"github.com/watzon/tg-mock/internal/latency"
+	"github.com/watzon/tg-mock/internal/members"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/outage"
+	"github.com/watzon/tg-mock/internal/persona"
+	"github.com/watzon/tg-mock/internal/polls"
 	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/stars"
+	"github.com/watzon/tg-mock/internal/stickersets"
 	"github.com/watzon/tg-mock/internal/storage"
+	"github.com/watzon/tg-mock/internal/systemd"
 	"github.com/watzon/tg-mock/internal/tokens"
+	"github.com/watzon/tg-mock/internal/topics"
+	"github.com/watzon/tg-mock/internal/tracing"
 	"github.com/watzon/tg-mock/internal/updates"
+	"github.com/watzon/tg-mock/internal/users"
 	"github.com/watzon/tg-mock/internal/webhook"
 )
 
@@ -24,13 +66,24 @@ type Server struct {
 	httpServer      *http.Server
 	port            int
 	tokenRegistry   *tokens.Registry
-	scenarioEngine  *scenario.Engine
-	updateQueue     *updates.Queue
-	requestRecorder *inspector.Recorder
+	sessions        *session.Manager
+	instances       *instance.Registry
 	webhookRegistry *webhook.Registry
 	fileStore       storage.Store
+	filePaths       *storage.PathRegistry
+	media           *storage.MediaRegistry
+	events          *events.Bus
+	tracer          *tracing.Tracer
+	guard           *guard.Guard
+	groups          *botgroup.Registry
+	hooks           *hooks.Chain
+	clock           *clock.Clock
+	latency         *latency.Profile
 	botHandler      *BotHandler
 	controlHandler  *ControlHandler
+	cfg             Config
+	shutdownOnce    sync.Once
+	done            chan struct{}
 }
 
 type Config struct {
@@ -40,6 +93,118 @@ type Config struct {
 	Tokens     map[string]config.TokenConfig
 	Scenarios  []config.ScenarioConfig
 	StorageDir string
+
+	// ControlToken, when set, must be presented on every control API request
+	// and enables the shutdown and restart endpoints.
+	ControlToken string
+
+	// FilePathTTL is how long file paths returned by getFile can be
+	// downloaded. Zero uses storage.DefaultPathTTL.
+	FilePathTTL time.Duration
+
+	// Deterministic makes whole responses reproducible: the clock stands
+	// still at an epoch derived from FakerSeed (1 if unset) until it is
+	// set or advanced, so dates repeat along with the seeded IDs.
+	Deterministic bool
+
+	// OTLPEndpoint is the OTLP/HTTP collector that spans are exported to.
+	// Tracing headers are propagated even when it is empty.
+	OTLPEndpoint string
+
+	// MemoryLimits caps the approximate bytes held by each in-memory store,
+	// and MemoryPolicy decides what happens when a limit is reached.
+	MemoryLimits map[guard.Component]int64
+	MemoryPolicy guard.Policy
+
+	// CORSOrigins are the browser origins allowed to call the control API
+	// ("*" allows any). CORS headers are not sent when it is empty.
+	CORSOrigins []string
+
+	// Journal, if set, receives every recorded request of every session.
+	Journal *inspector.Journal
+
+	// BotGroups are group chats shared by several bots.
+	BotGroups []config.BotGroupConfig
+
+	// Compat, if set, perturbs the responses of every new session to test
+	// client tolerance of missing and unknown fields.
+	Compat *compat.Config
+	// Adversarial, if set, makes the responses of every new session hard
+	// to deserialize, to harden client libraries.
+	Adversarial *adversarial.Config
+	// Chaos, if set, fails random calls of every new session.
+	Chaos *chaos.Config
+	// FloodLimits, if set, enforces Telegram's flood limits in every new
+	// session.
+	FloodLimits *floodlimit.Config
+	// EnforceRetryAfter makes every new session reject calls made before
+	// the retry_after of a 429 elapsed.
+	EnforceRetryAfter bool
+	// Latency, if set, delays every Bot API response. Unlike most
+	// settings it applies to the whole server rather than to a session.
+	Latency *latency.Config
+
+	// CallbackQueryTimeout is how long callback queries can be answered in
+	// every new session. Zero uses callbackquery.DefaultValidity.
+	CallbackQueryTimeout time.Duration
+	// MessageDeleteWindow is how long after they were sent messages can be
+	// deleted in every new session. Zero uses
+	// messages.DefaultDeleteWindow.
+	MessageDeleteWindow time.Duration
+	// ValidateResults checks every generated result against the spec,
+	// logging or failing results that miss required fields or have values
+	// of the wrong type.
+	ValidateResults ResultValidation
+	// FakerOptionalFields is how many optional fields the objects
+	// generated in every session have.
+	FakerOptionalFields faker.Density
+	// FakerLocales are the languages of the names, titles, and texts
+	// generated in every session. None means English.
+	FakerLocales []string
+	// FakerDatasets replace or extend the faker's word lists in every
+	// session.
+	FakerDatasets map[string]faker.Dataset
+	// FakerGenerators and FakerModifiers are registered with the faker of
+	// every session, by type name.
+	FakerGenerators map[string]faker.GeneratorFunc
+	FakerModifiers  map[string]faker.ModifierFunc
+
+	// APIVersion is the Bot API version simulated by every new session.
+	// Methods added after it answer 404 as in real Telegram. The zero
+	// version simulates the latest one.
+	APIVersion apiversion.Version
+
+	// Personas are attached to their chats in every new session. Invalid
+	// personas are skipped.
+	Personas []persona.Persona
+
+	// Hooks intercept every Bot API call, in order. More can be added
+	// later through Hooks.
+	Hooks []hooks.Hook
+
+	// Chats are seeded in every new session, so getChat returns known
+	// chats.
+	Chats []map[string]interface{}
+	// Users are seeded in every new session, so responses mentioning them
+	// show known profiles.
+	Users []map[string]interface{}
+	// Updates are queued in every new session, so it starts with a known
+	// conversation.
+	Updates []map[string]interface{}
+	// Traffic sends updates at regular intervals until shutdown.
+	Traffic []config.TrafficConfig
+}
+
+// DeterministicEpoch returns where the clock of a deterministic server
+// with the given seed starts: within the year after 2024-01-01 00:00:00
+// UTC, at a whole second derived from the seed.
+func DeterministicEpoch(seed int64) time.Time {
+	const year = 365 * 24 * 60 * 60
+	offset := seed % year
+	if offset < 0 {
+		offset += year
+	}
+	return time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC).Add(time.Duration(offset) * time.Second)
 }
 
 func New(cfg Config) *Server {
@@ -51,58 +216,123 @@ func New(cfg Config) *Server {
 	r.Use(middleware.Recoverer)
 
 	registry := tokens.NewRegistry()
-	scenarioEngine := scenario.NewEngine()
-	updateQueue := updates.NewQueue()
-	requestRecorder := inspector.NewRecorder()
-
-	// Create faker with configured seed
-	f := faker.New(faker.Config{
-		Seed: cfg.FakerSeed,
-	})
-
-	// Create responder with faker
-	responder := NewResponder(f)
-
-	// Create webhook registry with responder for method execution
-	webhookRegistry := webhook.NewRegistry(responder)
-
-	// Load tokens from config
-	for token, info := range cfg.Tokens {
-		registry.Register(token, tokens.TokenInfo{
-			Status:  tokens.Status(info.Status),
-			BotName: info.BotName,
-		})
-
-		// Load webhook config if present
-		if info.Webhook != nil {
-			webhookRegistry.Set(token, &webhook.Config{
-				URL:            info.Webhook.URL,
-				SecretToken:    info.Webhook.SecretToken,
-				IPAddress:      info.Webhook.IPAddress,
-				MaxConnections: info.Webhook.MaxConnections,
-				AllowedUpdates: info.Webhook.AllowedUpdates,
-			})
+	clk := clock.New()
+	if cfg.Deterministic {
+		if cfg.FakerSeed == 0 {
+			cfg.FakerSeed = 1
 		}
+		clk = clock.NewAt(DeterministicEpoch(cfg.FakerSeed))
 	}
 
-	// Load scenarios from config
-	for _, sc := range cfg.Scenarios {
-		s := &scenario.Scenario{
-			Method:       sc.Method,
-			Match:        sc.Match,
-			Times:        sc.Times,
-			ResponseData: sc.ResponseData,
+	// Every session starts from the configured scenarios with its own
+	// faker, so ID counters and seeded output are isolated per session.
+	newSession := func(name string, seed int64) *session.State {
+		engine := scenario.NewEngine()
+		for _, sc := range cfg.Scenarios {
+			engine.Add(newConfigScenario(sc))
 		}
-		// Only add error response if error_code is specified
-		if sc.Response.ErrorCode > 0 {
-			s.Response = &scenario.ErrorResponse{
-				ErrorCode:   sc.Response.ErrorCode,
-				Description: sc.Response.Description,
-				RetryAfter:  sc.Response.RetryAfter,
+		recorder := inspector.NewRecorder()
+		if cfg.Journal != nil {
+			recorder.OnRecord = func(req inspector.RequestRecord) {
+				cfg.Journal.Append(name, req)
 			}
 		}
-		scenarioEngine.Add(s)
+		personas := persona.NewRegistry(clk.Now)
+		for _, p := range cfg.Personas {
+			personas.Set(p)
+		}
+		chatArchive := archive.New()
+		queue := updates.NewQueue()
+		queue.OnAdd = chatArchive.AddUpdate
+		mutator := compat.NewMutator()
+		if cfg.Compat != nil {
+			mutator.Set(*cfg.Compat)
+		}
+		injector := chaos.NewInjector()
+		if cfg.Chaos != nil {
+			injector.Set(*cfg.Chaos)
+		}
+		limiter := floodlimit.NewLimiter(clk.Now)
+		if cfg.FloodLimits != nil {
+			limiter.Set(*cfg.FloodLimits)
+		}
+		cooldowns := floodlimit.NewCooldowns(clk.Now)
+		cooldowns.SetEnabled(cfg.EnforceRetryAfter)
+		st := &session.State{
+			Name:          name,
+			Scenarios:     engine,
+			Updates:       queue,
+			LongPolls:     updates.NewLongPolls(),
+			Recorder:      recorder,
+			Messages:      messages.NewStore(clk.Now, cfg.MessageDeleteWindow),
+			Chats:         chats.NewStore(),
+			Bots:          botsettings.NewStore(),
+			Users:         users.NewStore(),
+			Members:       members.NewStore(clk.Now),
+			InviteLinks:   invitelinks.NewStore(),
+			Topics:        topics.NewStore(),
+			Polls:         polls.NewStore(),
+			Games:         games.NewStore(),
+			StickerSets:   stickersets.NewStore(),
+			Boosts:        boosts.NewStore(clk.Now),
+			Stars:         stars.NewStore(clk.Now),
+			ChatActions:   chataction.NewTracker(clk.Now),
+			InlineQueries: inlinequery.NewTracker(clk.Now),
+			Callbacks:     callbackquery.NewTracker(clk.Now, cfg.CallbackQueryTimeout),
+			Personas:      personas,
+			Compat:        mutator,
+			APIVersion:    apiversion.NewGate(cfg.APIVersion, clk.Now),
+			Outage:        outage.NewBurst(clk.Now),
+			Chaos:         injector,
+			FloodLimit:    limiter,
+			Cooldowns:     cooldowns,
+			Archive:       chatArchive,
+			Faker: faker.New(faker.Config{
+				Seed:           seed,
+				OptionalFields: cfg.FakerOptionalFields,
+				Locales:        cfg.FakerLocales,
+				Datasets:       cfg.FakerDatasets,
+				Now:            clk.Now,
+			}),
+		}
+		for name, fn := range cfg.FakerGenerators {
+			st.Faker.RegisterGenerator(name, fn)
+		}
+		for name, fn := range cfg.FakerModifiers {
+			st.Faker.RegisterModifier(name, fn)
+		}
+		st.Adversarial = adversarial.NewMutator(st.Faker)
+		if cfg.Adversarial != nil {
+			st.Adversarial.Set(*cfg.Adversarial)
+		}
+		seedChats(st, cfg.Chats)
+		seedUsers(st, cfg.Users)
+		queueStartupUpdates(st, cfg.Updates, clk.Now())
+		return st
 	}
+	sessions := session.NewManager(func(name string) *session.State {
+		return newSession(name, cfg.FakerSeed)
+	})
+
+	// Instances are sessions with their own seed that expire
+	instances := instance.NewRegistry(clk.Now)
+	instances.OnCreate = func(inst instance.Instance) {
+		sessions.Put(newSession(inst.Session, inst.Seed))
+	}
+	instances.OnRemove = func(inst instance.Instance) {
+		sessions.Delete(inst.Session)
+	}
+
+	var exporter *tracing.Exporter
+	if cfg.OTLPEndpoint != "" {
+		exporter = tracing.NewExporter(cfg.OTLPEndpoint, "tg-mock")
+	}
+	tracer := tracing.NewTracer(exporter)
+
+	// Create webhook registry; methods returned by webhooks run in the default session
+	webhookRegistry := webhook.NewRegistry(defaultSessionExecutor{sessions})
+	webhookRegistry.SetTracer(tracer)
+	webhookRegistry.SetClock(clk.Now)
 
 	// Enable token registry if any tokens are configured
 	registryEnabled := len(cfg.Tokens) > 0
@@ -115,22 +345,189 @@ func New(cfg Config) *Server {
 	} else {
 		fileStore = storage.NewMemoryStore()
 	}
+	filePaths := storage.NewPathRegistry(clk.Now, cfg.FilePathTTL)
+	// Photos in updates are downloadable like the ones in responses
+	media := storage.NewMediaRegistry(0)
+	webhookRegistry.OnDeliver = func(_ string, update map[string]interface{}) {
+		media.Scan(update)
+	}
+	eventBus := events.NewBus()
+
+	memGuard := guard.New(cfg.MemoryLimits, cfg.MemoryPolicy)
+	memGuard.OnBreach = func(b guard.Breach) {
+		eventBus.Publish(events.Event{
+			Type:    events.TypeMemoryLimitReached,
+			Session: b.Session,
+			Data: map[string]interface{}{
+				"component": b.Component,
+				"used":      b.Used,
+				"limit":     b.Limit,
+				"action":    b.Action,
+				"evicted":   b.Evicted,
+			},
+		})
+	}
+
+	groups := botgroup.NewRegistry()
+	chain := hooks.NewChain(cfg.Hooks...)
+	profile := latency.NewProfile()
+	if cfg.Latency != nil {
+		profile.Set(*cfg.Latency)
+	}
 
 	s := &Server{
 		router:          r,
 		port:            cfg.Port,
 		tokenRegistry:   registry,
-		scenarioEngine:  scenarioEngine,
-		updateQueue:     updateQueue,
-		requestRecorder: requestRecorder,
+		sessions:        sessions,
+		instances:       instances,
 		webhookRegistry: webhookRegistry,
 		fileStore:       fileStore,
-		botHandler:      NewBotHandler(registry, scenarioEngine, updateQueue, responder, requestRecorder, webhookRegistry, registryEnabled),
-		controlHandler:  NewControlHandler(scenarioEngine, registry, updateQueue, requestRecorder, webhookRegistry),
+		filePaths:       filePaths,
+		media:           media,
+		events:          eventBus,
+		tracer:          tracer,
+		guard:           memGuard,
+		groups:          groups,
+		hooks:           chain,
+		clock:           clk,
+		latency:         profile,
+		botHandler:      NewBotHandler(registry, sessions, webhookRegistry, filePaths, media, eventBus, tracer, memGuard, groups, chain, profile, cfg.ValidateResults, registryEnabled),
+		cfg:             cfg,
+		done:            make(chan struct{}),
 	}
+	s.controlHandler = NewControlHandler(sessions, registry, webhookRegistry, fileStore, eventBus, memGuard, groups, instances, s.botHandler, clk, s, cfg.ControlToken)
 
+	s.loadConfigState()
 	s.setupRoutes()
+	s.runTraffic()
+
+	return s
+}
+
+// defaultSessionExecutor executes webhook-returned methods with the
+// responder of whatever the default session currently is.
+type defaultSessionExecutor struct {
+	sessions *session.Manager
+}
+
+func (e defaultSessionExecutor) ExecuteMethod(spec gen.MethodSpec, params map[string]interface{}) (interface{}, error) {
+	return NewResponder(e.sessions.Default().Faker).ExecuteMethod(spec, params)
+}
+
+// loadConfigState registers the tokens, webhooks, budgets, concurrency
+// limits, and bot groups from the config.
+func (s *Server) loadConfigState() {
+	for token, info := range s.cfg.Tokens {
+		s.tokenRegistry.Register(token, tokens.TokenInfo{
+			Status:  tokens.Status(info.Status),
+			BotName: info.BotName,
+			Profile: botProfileConfig(info.Profile),
+		})
+
+		// Load webhook config if present
+		if info.Webhook != nil {
+			s.webhookRegistry.Set(token, &webhook.Config{
+				URL:            info.Webhook.URL,
+				SecretToken:    info.Webhook.SecretToken,
+				IPAddress:      info.Webhook.IPAddress,
+				MaxConnections: info.Webhook.MaxConnections,
+				AllowedUpdates: info.Webhook.AllowedUpdates,
+			})
+		}
+
+		if info.Budget != nil {
+			s.tokenRegistry.SetBudget(token, tokens.Budget{
+				Calls:       info.Budget.Calls,
+				ErrorCode:   info.Budget.ErrorCode,
+				Description: info.Budget.Description,
+				RetryAfter:  info.Budget.RetryAfter,
+			})
+		}
 
+		if info.Concurrency != nil {
+			s.tokenRegistry.SetConcurrency(token, tokens.ConcurrencyLimit{
+				Limit:          info.Concurrency.Limit,
+				Mode:           tokens.ConcurrencyMode(info.Concurrency.Mode),
+				QueueTimeoutMs: int(info.Concurrency.QueueTimeout / time.Millisecond),
+				RetryAfter:     info.Concurrency.RetryAfter,
+			})
+		}
+	}
+
+	for _, gc := range s.cfg.BotGroups {
+		g := botgroup.Group{ChatID: gc.ChatID, BotsSeeBots: gc.BotsSeeBots}
+		for _, m := range gc.Bots {
+			g.Members = append(g.Members, botgroup.Member{Token: m.Token, Session: m.Session})
+		}
+		if err := s.groups.Set(g); err != nil {
+			log.Printf("tg-mock: ignoring bot group %q: %v", gc.ChatID, err)
+		}
+	}
+}
+
+// Restart returns the server to the state it had at startup, as if the
+// process had been restarted, without closing the listener. Orchestration
+// event webhooks are kept so runners are notified of the restart.
+func (s *Server) Restart() {
+	s.instances.Clear()
+	s.sessions.Reset()
+	s.tokenRegistry.Restore(nil)
+	s.tokenRegistry.RestoreBudgets(nil)
+	s.tokenRegistry.RestoreConcurrency(nil)
+	s.webhookRegistry.Clear()
+	s.groups.Clear()
+	s.fileStore.Clear()
+	s.filePaths.Clear()
+	s.media.Clear()
+	s.guard.Clear()
+	s.clock.Reset()
+	s.latency.Disable()
+	if s.cfg.Latency != nil {
+		s.latency.Set(*s.cfg.Latency)
+	}
+	s.loadConfigState()
+	s.events.Publish(events.Event{
+		Type: events.TypeStateReset,
+		Data: map[string]interface{}{"reason": "restart"},
+	})
+}
+
+// RequestShutdown asynchronously stops the server. It is used by the
+// control API, where the request must complete before connections drain.
+func (s *Server) RequestShutdown() {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+	}()
+}
+
+// Done returns a channel that is closed once shutdown has completed.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// newConfigScenario converts a scenario from the config file.
+func newConfigScenario(sc config.ScenarioConfig) *scenario.Scenario {
+	s := &scenario.Scenario{
+		Method:       sc.Method,
+		Match:        sc.Match,
+		Times:        sc.Times,
+		ResponseData: sc.ResponseData,
+		Script:       sc.Script,
+	}
+	// Only add error response if error_code is specified
+	if sc.Response.ErrorCode > 0 {
+		s.Response = &scenario.ErrorResponse{
+			ErrorCode:       sc.Response.ErrorCode,
+			Description:     sc.Response.Description,
+			RetryAfter:      sc.Response.RetryAfter,
+			MigrateToChatID: sc.Response.MigrateToChatID,
+			Parameters:      sc.Response.Parameters,
+			HTML:            sc.Response.HTML,
+		}
+	}
 	return s
 }
 
@@ -140,22 +537,88 @@ func (s *Server) setupRoutes() {
 		w.Write([]byte("ok"))
 	})
 
+	// Web dashboard; it drives the control API from the browser
+	s.router.Method(http.MethodGet, "/__dashboard", dashboard.Handler())
+
+	s.router.Group(s.mountAPI)
+
+	// Session-scoped API for clients that can't set custom headers
+	s.router.Route("/session/{session}", s.mountAPI)
+
+	// Ephemeral instances handed out by /__control/instances
+	s.router.Route("/instances/{instance}", func(r chi.Router) {
+		r.Use(s.withInstance)
+		s.mountRoutes(r)
+	})
+}
+
+// mountAPI registers the control, Bot API, and file routes on r.
+// Requests are bound to the session named by the {session} URL parameter
+// or the X-TG-Mock-Session header.
+func (s *Server) mountAPI(r chi.Router) {
+	r.Use(s.withSession)
+	s.mountRoutes(r)
+}
+
+// mountRoutes registers the control, Bot API, and file routes on r. A
+// middleware must have bound the request to a session.
+func (s *Server) mountRoutes(r chi.Router) {
 	// Control API
-	s.router.Mount("/__control", s.controlHandler.Routes())
+	r.With(cors(s.cfg.CORSOrigins)).Mount("/__control", s.controlHandler.Routes())
 
 	// Bot API routes
-	s.router.Route("/bot{token}", func(r chi.Router) {
+	r.Route("/bot{token}", func(r chi.Router) {
 		r.Post("/{method}", s.botHandler.Handle)
 		r.Get("/{method}", s.botHandler.Handle)
 	})
 
 	// File download endpoint
-	s.router.Get("/file/bot{token}/{path:.*}", s.handleFileDownload)
+	r.Get("/file/bot{token}/*", s.handleFileDownload)
+}
+
+// withSession attaches the selected session to the request context.
+func (s *Server) withSession(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := chi.URLParam(r, "session")
+		if name == "" {
+			name = r.Header.Get(session.Header)
+		}
+		st := s.sessions.Get(name)
+		next.ServeHTTP(w, r.WithContext(session.WithState(r.Context(), st)))
+	})
 }
 
+// withInstance binds the request to the session of the instance named by
+// the {instance} URL parameter. Unknown and expired instances answer 404
+// in the Bot API's error format, since bots are their main callers.
+func (s *Server) withInstance(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var st *session.State
+		inst, ok := s.instances.Get(chi.URLParam(r, "instance"))
+		if ok {
+			st, ok = s.sessions.Lookup(inst.Session)
+		}
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(APIResponse{
+				OK:          false,
+				ErrorCode:   http.StatusNotFound,
+				Description: "Not Found: instance expired or unknown",
+			})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(session.WithState(r.Context(), st)))
+	})
+}
+
+// maxDownloadSize matches the Bot API's 20 MB download limit and caps the
+// size of placeholder content served for generated files.
+const maxDownloadSize = 20 << 20
+
 func (s *Server) handleFileDownload(w http.ResponseWriter, r *http.Request) {
 	token := chi.URLParam(r, "token")
-	// path := chi.URLParam(r, "path") // Will be used in future implementation
+	filePath := chi.URLParam(r, "*")
 
 	// Validate token format
 	if !tokens.ValidateFormat(token) {
@@ -163,25 +626,107 @@ func (s *Server) handleFileDownload(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Find file by path
-	// For now, return 404 - actual implementation needs file lookup by path
-	http.Error(w, "File not found", http.StatusNotFound)
+	// Only paths issued to this token by getFile, and not yet expired, can
+	// be downloaded. Telegram answers unknown and expired paths alike, so
+	// clients have to call getFile again to get a fresh path.
+	lease, err := s.filePaths.Resolve(token, filePath)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(APIResponse{
+			OK:          false,
+			ErrorCode:   http.StatusNotFound,
+			Description: "Not Found",
+		})
+		return
+	}
+
+	data, meta, err := s.fileStore.Get(lease.FileID)
+	contentType := meta.MimeType
+	if m, ok := s.media.Lookup(lease.FileID); err != nil && ok {
+		// Generated media: serve a valid file of its type
+		data, contentType, err = storage.Synthesize(lease.FileID, m, filePath)
+	}
+	if err != nil {
+		// Generated file: serve placeholder content of the advertised size
+		size := lease.Size
+		if size < 0 || size > maxDownloadSize {
+			size = maxDownloadSize
+		}
+		data = make([]byte, size)
+		contentType = mime.TypeByExtension(path.Ext(filePath))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	w.Write(data)
+}
+
+// writeTimeout is how long the server gives a handler to respond.
+// Handlers that wait, such as long polls, extend it by how long they wait.
+const writeTimeout = 30 * time.Second
+
+// extendWriteDeadline gives a handler that waits for up to d the usual
+// time to write its response once the wait is over. Writers that can't
+// set deadlines, such as test recorders, have none to extend.
+func extendWriteDeadline(w http.ResponseWriter, d time.Duration) {
+	http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d + writeTimeout))
 }
 
+// Start listens and serves until the server is shut down.
+// When started via systemd socket activation, the first passed socket is
+// used instead of the configured port, and readiness is reported to the
+// service manager once the server is accepting connections.
 func (s *Server) Start() error {
 	s.httpServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", s.port),
 		Handler:      s.router,
 		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		WriteTimeout: writeTimeout,
 	}
 
-	fmt.Printf("tg-mock listening on :%d\n", s.port)
-	return s.httpServer.ListenAndServe()
+	listeners, err := systemd.Listeners()
+	if err != nil {
+		return fmt.Errorf("socket activation: %w", err)
+	}
+
+	var ln net.Listener
+	if len(listeners) > 0 {
+		ln = listeners[0]
+		for _, extra := range listeners[1:] {
+			extra.Close()
+		}
+	} else {
+		ln, err = net.Listen("tcp", s.httpServer.Addr)
+		if err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("tg-mock listening on %s\n", ln.Addr())
+
+	systemd.Notify("READY=1")
+	stopWatchdog := make(chan struct{})
+	defer close(stopWatchdog)
+	go systemd.RunWatchdog(stopWatchdog)
+
+	return s.httpServer.Serve(ln)
 }
 
+// Shutdown gracefully stops the server, notifying systemd that the
+// service is stopping.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.httpServer.Shutdown(ctx)
+	systemd.Notify("STOPPING=1")
+	var err error
+	if s.httpServer != nil {
+		err = s.httpServer.Shutdown(ctx)
+	}
+	s.tracer.Shutdown(ctx)
+	s.shutdownOnce.Do(func() { close(s.done) })
+	return err
 }
 
 // Router returns the chi.Router for testing purposes.
@@ -189,3 +734,25 @@ func (s *Server) Shutdown(ctx context.Context) error {
 func (s *Server) Router() chi.Router {
 	return s.router
 }
+
+// Sessions returns the session manager, for white-box access to the
+// per-session scenarios, update queues, and recorders.
+func (s *Server) Sessions() *session.Manager {
+	return s.sessions
+}
+
+// Webhooks returns the webhook registry.
+func (s *Server) Webhooks() *webhook.Registry {
+	return s.webhookRegistry
+}
+
+// Hooks returns the hooks intercepting Bot API calls. They are kept
+// across restarts.
+func (s *Server) Hooks() *hooks.Chain {
+	return s.hooks
+}
+
+// Tokens returns the token registry.
+func (s *Server) Tokens() *tokens.Registry {
+	return s.tokenRegistry
+}
@@ -0,0 +1,203 @@
+// internal/server/simulate.go
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/tracing"
+	"github.com/watzon/tg-mock/internal/webhook"
+)
+
+// simulateUpdate sends the bot a complete update of the kind in the path,
+// such as "message" or "chat_member", made up by the faker from a few
+// fields like chat_id, user_id, and text. "command" is a message with a
+// bot command. Seeded users and chats appear as seeded, and messages are
+// stored, so the bot can reply to them, and an edit of a stored message
+// changes it. The update goes to the webhook of token, if it has one.
+func (h *ControlHandler) simulateUpdate(w http.ResponseWriter, r *http.Request) {
+	req := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	token, _ := req["token"].(string)
+	delete(req, "token")
+
+	st := h.session(r)
+	update, err := h.simulatedUpdate(st, token, chi.URLParam(r, "kind"), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.dispatchUpdate(w, r, st, token, update, map[string]interface{}{"update": update})
+}
+
+// simulatedUpdate makes up an update of the kind from the fields of req
+// for simulateUpdate, and stores its message.
+func (h *ControlHandler) simulatedUpdate(st *session.State, token, kind string, req map[string]interface{}) (map[string]interface{}, error) {
+	if kind == "command" {
+		command, _ := req["command"].(string)
+		command = strings.TrimPrefix(command, "/")
+		if command == "" {
+			return nil, errors.New("command is required")
+		}
+		text := "/" + command
+		if args, _ := req["args"].(string); args != "" {
+			text += " " + args
+		}
+		delete(req, "command")
+		delete(req, "args")
+		req["text"] = text
+		kind = "message"
+	}
+
+	update, err := st.Faker.GenerateUpdate(kind, req)
+	if err != nil {
+		return nil, fmt.Errorf("%v; want command or one of %s", err, strings.Join(faker.UpdateKinds(), ", "))
+	}
+	applySeeded(st, update)
+	storeSimulatedMessage(st, kind, update, req)
+
+	// Queued updates are numbered by the queue
+	if !h.webhooks.IsActive(token) {
+		delete(update, "update_id")
+	}
+	return update, nil
+}
+
+// applySeeded makes the users and chats of an update the seeded ones, if
+// they were seeded.
+func applySeeded(st *session.State, v interface{}) {
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			applySeeded(st, item)
+		}
+	case map[string]interface{}:
+		for name, field := range v {
+			obj, ok := field.(map[string]interface{})
+			switch {
+			case ok && (name == "from" || name == "user"):
+				st.Users.Apply(obj)
+			case ok && (name == "chat" || name == "sender_chat"):
+				known, _ := st.Chats.Get(messages.ChatKey(obj["id"]))
+				for _, name := range chatObjectFields {
+					if v, ok := known[name]; ok && v != nil {
+						obj[name] = v
+					}
+				}
+			}
+			applySeeded(st, field)
+		}
+	}
+}
+
+// storeSimulatedMessage stores the message of a simulated update, so the
+// bot can reply to or forward it. An edit of a stored message,
+// named by the message_id of the request, keeps the stored message and
+// changes its text or caption.
+func storeSimulatedMessage(st *session.State, kind string, update, req map[string]interface{}) {
+	msg, ok := update[kind].(map[string]interface{})
+	if !ok {
+		return
+	}
+	isMessage := false
+	for _, name := range messageUpdateTypes {
+		isMessage = isMessage || name == kind
+	}
+	if !isMessage {
+		return
+	}
+	chat, _ := msg["chat"].(map[string]interface{})
+	chatID := messages.ChatKey(chat["id"])
+
+	if id, ok := messages.MessageID(req["message_id"]); ok && strings.HasPrefix(kind, "edited_") {
+		if stored, ok := st.Messages.Get(chatID, id); ok {
+			for _, name := range []string{"text", "caption"} {
+				if v, ok := req[name]; ok {
+					stored[name] = v
+				}
+			}
+			stored["edit_date"] = msg["edit_date"]
+			update[kind] = stored
+			msg = stored
+		}
+	}
+	st.Messages.Put(chatID, msg)
+}
+
+// dispatchUpdate delivers an update to the webhook of token if it has one
+// and queues it for getUpdates otherwise, and writes what became of it
+// along with the fields of extra.
+func (h *ControlHandler) dispatchUpdate(w http.ResponseWriter, r *http.Request, st *session.State, token string, update, extra map[string]interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	response := map[string]interface{}{}
+	for k, v := range extra {
+		response[k] = v
+	}
+
+	result, err := h.sendUpdate(tracing.Extract(r.Context(), r.Header), st, token, update)
+	if errors.Is(err, errQueueFull) {
+		http.Error(w, err.Error(), http.StatusInsufficientStorage)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if result != nil {
+		response["delivered"] = true
+		response["success"] = result.Success
+		response["status_code"] = result.StatusCode
+		response["duration_ms"] = result.DurationMs
+		if result.Error != "" {
+			response["error"] = result.Error
+		}
+		// Include method result if webhook returned a method call
+		if result.MethodResult != nil {
+			response["method_result"] = result.MethodResult
+		}
+		json.NewEncoder(w).Encode(response)
+		return
+	}
+
+	response["queued"] = true
+	response["update_id"] = update["update_id"]
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(response)
+}
+
+// errQueueFull is returned by sendUpdate when the update queue memory
+// limit rejects an update.
+var errQueueFull = errors.New("update queue memory limit reached")
+
+// sendUpdate delivers an update to the webhook of token if it has one,
+// returning the result of the delivery, and queues it for getUpdates
+// otherwise, returning nil. Either way the update gets an update_id.
+func (h *ControlHandler) sendUpdate(ctx context.Context, st *session.State, token string, update map[string]interface{}) (*webhook.DeliveryResult, error) {
+	if h.webhooks.IsActive(token) {
+		if _, ok := update["update_id"]; !ok {
+			update["update_id"] = st.Faker.NextUpdateID()
+		}
+		trackQueries(st, update)
+		return h.webhooks.DeliverContext(ctx, token, update)
+	}
+	if err := h.guard.Admit(guard.Queue, st.Name, st.Updates); err != nil {
+		return nil, errQueueFull
+	}
+	trackQueries(st, update)
+	st.Updates.Add(update)
+	return nil, nil
+}
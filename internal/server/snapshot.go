@@ -0,0 +1,205 @@
+// internal/server/snapshot.go
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/boosts"
+	"github.com/watzon/tg-mock/internal/botsettings"
+	"github.com/watzon/tg-mock/internal/chats"
+	"github.com/watzon/tg-mock/internal/games"
+	"github.com/watzon/tg-mock/internal/guard"
+	"github.com/watzon/tg-mock/internal/invitelinks"
+	"github.com/watzon/tg-mock/internal/members"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/polls"
+	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/stars"
+	"github.com/watzon/tg-mock/internal/storage"
+	"github.com/watzon/tg-mock/internal/tokens"
+	"github.com/watzon/tg-mock/internal/topics"
+	"github.com/watzon/tg-mock/internal/webhook"
+)
+
+// snapshotVersion is bumped whenever the snapshot document changes incompatibly.
+const snapshotVersion = 1
+
+// Snapshot is a complete, serializable copy of the mock server state.
+// Recorded requests are intentionally excluded: a snapshot describes the
+// baseline a test starts from, not the history of what happened.
+type Snapshot struct {
+	Version     int                                `json:"version"`
+	CreatedAt   time.Time                          `json:"created_at"`
+	Scenarios   []scenarioSnapshot                 `json:"scenarios"`
+	Tokens      map[string]tokens.TokenInfo        `json:"tokens"`
+	Budgets     map[string]tokens.Budget           `json:"budgets,omitempty"`
+	Concurrency map[string]tokens.ConcurrencyLimit `json:"concurrency,omitempty"`
+	Webhooks    map[string]*webhook.Config         `json:"webhooks"`
+	Updates     updatesSnapshot                    `json:"updates"`
+	Messages    []messages.Entry                   `json:"messages,omitempty"`
+	Chats       []chats.Entry                      `json:"chats,omitempty"`
+	Bots        []botsettings.Bot                  `json:"bots,omitempty"`
+	Users       []map[string]interface{}           `json:"users,omitempty"`
+	Members     []members.Member                   `json:"members,omitempty"`
+	InviteLinks []invitelinks.Link                 `json:"invite_links,omitempty"`
+	Topics      []topics.Topic                     `json:"topics,omitempty"`
+	Polls       []polls.Entry                      `json:"polls,omitempty"`
+	Games       []games.Board                      `json:"games,omitempty"`
+	StickerSets []map[string]interface{}           `json:"sticker_sets,omitempty"`
+	Boosts      []boosts.Entry                     `json:"boosts,omitempty"`
+	Stars       []stars.Ledger                     `json:"stars,omitempty"`
+	Files       []storage.File                     `json:"files"`
+}
+
+// scenarioSnapshot wraps a scenario with its usage counter, which is not
+// part of the scenario's regular JSON representation.
+type scenarioSnapshot struct {
+	*scenario.Scenario
+	Used int `json:"used"`
+}
+
+type updatesSnapshot struct {
+	Pending      []map[string]interface{} `json:"pending"`
+	LastUpdateID int64                    `json:"last_update_id"`
+}
+
+// snapshot captures the current server state. Scenarios and updates are
+// taken from the given session; tokens, webhooks, and files are global.
+func (h *ControlHandler) snapshot(st *session.State) (*Snapshot, error) {
+	files, err := h.files.List()
+	if err != nil {
+		return nil, err
+	}
+
+	pending, lastID := st.Updates.Snapshot()
+
+	scenarios := st.Scenarios.List()
+	snap := &Snapshot{
+		Version:     snapshotVersion,
+		CreatedAt:   time.Now().UTC(),
+		Scenarios:   make([]scenarioSnapshot, len(scenarios)),
+		Tokens:      h.tokens.List(),
+		Budgets:     h.tokens.Budgets(),
+		Concurrency: h.tokens.ConcurrencyLimits(),
+		Webhooks:    h.webhooks.List(),
+		Updates: updatesSnapshot{
+			Pending:      pending,
+			LastUpdateID: lastID,
+		},
+		Messages:    st.Messages.List(""),
+		Chats:       st.Chats.List(),
+		Bots:        st.Bots.List(),
+		Users:       st.Users.List(),
+		Members:     st.Members.List(""),
+		InviteLinks: st.InviteLinks.List(""),
+		Topics:      st.Topics.List(""),
+		Polls:       st.Polls.List(),
+		Games:       st.Games.List(),
+		StickerSets: st.StickerSets.List(),
+		Boosts:      st.Boosts.List(""),
+		Stars:       st.Stars.List(),
+		Files:       files,
+	}
+	for i, s := range scenarios {
+		snap.Scenarios[i] = scenarioSnapshot{Scenario: s, Used: s.Used()}
+	}
+
+	return snap, nil
+}
+
+// restore replaces the current server state with the snapshot contents.
+// The snapshot is checked in full before anything is replaced, so a
+// rejected one leaves the state as it was.
+func (h *ControlHandler) restore(st *session.State, snap *Snapshot) error {
+	if snap.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version %d", snap.Version)
+	}
+
+	scenarios := make([]*scenario.Scenario, 0, len(snap.Scenarios))
+	for _, s := range snap.Scenarios {
+		if s.Scenario == nil {
+			continue
+		}
+		s.Scenario.SetUsed(s.Used)
+		scenarios = append(scenarios, s.Scenario)
+	}
+
+	// Files are admitted into a staging store first, so the memory guard
+	// rejects or evicts them as it would the stored files
+	staged := storage.NewMemoryStore()
+	for _, f := range snap.Files {
+		if err := h.guard.Admit(guard.Files, "", staged); err != nil {
+			return err
+		}
+		if err := staged.Put(f); err != nil {
+			return err
+		}
+	}
+
+	if err := h.files.Clear(); err != nil {
+		return err
+	}
+	for _, f := range snap.Files {
+		// Files the guard evicted while staging stay out
+		if _, _, err := staged.Get(f.ID); err != nil {
+			continue
+		}
+		if err := h.files.Put(f); err != nil {
+			return err
+		}
+	}
+
+	st.Scenarios.Restore(scenarios)
+	h.tokens.Restore(snap.Tokens)
+	h.tokens.RestoreBudgets(snap.Budgets)
+	h.tokens.RestoreConcurrency(snap.Concurrency)
+	h.webhooks.Restore(snap.Webhooks)
+	st.Updates.Restore(snap.Updates.Pending, snap.Updates.LastUpdateID)
+	st.Messages.Restore(snap.Messages)
+	st.Chats.Restore(snap.Chats)
+	st.Bots.Restore(snap.Bots)
+	st.Users.Restore(snap.Users)
+	st.Members.Restore(snap.Members)
+	st.InviteLinks.Restore(snap.InviteLinks)
+	st.Topics.Restore(snap.Topics)
+	st.Polls.Restore(snap.Polls)
+	st.Games.Restore(snap.Games)
+	st.StickerSets.Restore(snap.StickerSets)
+	st.Boosts.Restore(snap.Boosts)
+	st.Stars.Restore(snap.Stars)
+
+	return nil
+}
+
+func (h *ControlHandler) exportSnapshot(w http.ResponseWriter, r *http.Request) {
+	snap, err := h.snapshot(h.session(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(snap)
+}
+
+func (h *ControlHandler) importSnapshot(w http.ResponseWriter, r *http.Request) {
+	var snap Snapshot
+	if err := json.NewDecoder(r.Body).Decode(&snap); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.restore(h.session(r), &snap); err != nil {
+		code := http.StatusBadRequest
+		if errors.Is(err, guard.ErrLimitExceeded) {
+			code = http.StatusInsufficientStorage
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,36 @@
+// internal/server/stars.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/stars"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// applyStars answers getStarTransactions from the bot's ledger and records
+// refunds of the payments in it. Bots without transactions get generated
+// ones, and refunds of payments the ledger doesn't know are accepted.
+func applyStars(st *session.State, token, method string, params map[string]interface{}, result interface{}) (interface{}, *tgerrors.Error) {
+	switch method {
+	case "getStarTransactions":
+		if !st.Stars.Known(token) {
+			return result, nil
+		}
+		offset, _ := int64Value(params["offset"])
+		limit, _ := int64Value(params["limit"])
+		return map[string]interface{}{
+			"transactions": st.Stars.Transactions(token, int(offset), int(limit)),
+		}, nil
+
+	case "refundStarPayment":
+		userID, _ := int64Value(params["user_id"])
+		chargeID, _ := params["telegram_payment_charge_id"].(string)
+		switch _, err := st.Stars.Refund(token, userID, chargeID); err {
+		case stars.ErrAlreadyRefunded:
+			return nil, tgerrors.ChargeAlreadyRefunded()
+		case stars.ErrWrongUser:
+			return nil, tgerrors.UserIDInvalid()
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,103 @@
+// internal/server/stickersets.go
+package server
+
+import (
+	"strings"
+
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// applyStickerSet keeps the sticker sets bots create and the changes they
+// make to them, so that getStickerSet returns the accumulated set. Calls
+// on sets the store doesn't know succeed without changing it, and
+// getStickerSet answers them with a generated set.
+func applyStickerSet(st *session.State, method string, params map[string]interface{}, result interface{}) (interface{}, *tgerrors.Error) {
+	name, _ := params["name"].(string)
+	switch method {
+	case "createNewStickerSet":
+		stickerType, _ := params["sticker_type"].(string)
+		if stickerType == "" {
+			stickerType = "regular"
+		}
+		stickers := []interface{}{}
+		for _, item := range arrayParam(params["stickers"]) {
+			if input := objectParam(item); input != nil {
+				stickers = append(stickers, newSticker(st, name, stickerType, boolParam(params["needs_repainting"]), input))
+			}
+		}
+		err := st.StickerSets.Create(map[string]interface{}{
+			"name":         name,
+			"title":        params["title"],
+			"sticker_type": stickerType,
+			"stickers":     stickers,
+		})
+		if err != nil && name != "" {
+			return nil, tgerrors.StickerSetNameOccupied()
+		}
+
+	case "getStickerSet":
+		if set, ok := st.StickerSets.Get(name); ok {
+			return set, nil
+		}
+
+	case "setStickerSetTitle":
+		title, _ := params["title"].(string)
+		st.StickerSets.SetTitle(name, title)
+
+	case "addStickerToSet", "replaceStickerInSet":
+		set, ok := st.StickerSets.Get(name)
+		input := objectParam(params["sticker"])
+		if !ok || input == nil {
+			break
+		}
+		stickerType, _ := set["sticker_type"].(string)
+		sticker := newSticker(st, name, stickerType, false, input)
+		if method == "addStickerToSet" {
+			st.StickerSets.AddSticker(name, sticker)
+		} else {
+			old, _ := params["old_sticker"].(string)
+			st.StickerSets.ReplaceSticker(name, old, sticker)
+		}
+
+	case "deleteStickerFromSet":
+		fileID, _ := params["sticker"].(string)
+		st.StickerSets.DeleteSticker(fileID)
+
+	case "setStickerPositionInSet":
+		fileID, _ := params["sticker"].(string)
+		position, _ := int64Value(params["position"])
+		st.StickerSets.MoveSticker(fileID, int(position))
+
+	case "deleteStickerSet":
+		st.StickerSets.Delete(name)
+	}
+	return result, nil
+}
+
+// newSticker returns the Sticker an InputSticker adds to a set. A sticker
+// given by file ID keeps it; uploads and URLs get a generated one.
+func newSticker(st *session.State, setName, stickerType string, needsRepainting bool, input map[string]interface{}) map[string]interface{} {
+	sticker, _ := st.Faker.Generate("Sticker", nil).(map[string]interface{})
+	if fileID, _ := input["sticker"].(string); fileID != "" && !strings.HasPrefix(fileID, "attach://") && !strings.Contains(fileID, "://") {
+		sticker["file_id"] = fileID
+	}
+	format, _ := input["format"].(string)
+	sticker["type"] = stickerType
+	sticker["set_name"] = setName
+	sticker["is_animated"] = format == "animated"
+	sticker["is_video"] = format == "video"
+	if emojis := arrayParam(input["emoji_list"]); len(emojis) > 0 {
+		sticker["emoji"] = emojis[0]
+	}
+	if mask := objectParam(input["mask_position"]); mask != nil && stickerType == "mask" {
+		sticker["mask_position"] = mask
+	}
+	if stickerType == "custom_emoji" {
+		sticker["custom_emoji_id"] = sticker["file_unique_id"]
+		if needsRepainting {
+			sticker["needs_repainting"] = true
+		}
+	}
+	return sticker
+}
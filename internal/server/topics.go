@@ -0,0 +1,101 @@
+// internal/server/topics.go
+package server
+
+import (
+	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/topics"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// forumChat reports whether the topics of a chat are checked: once a bot
+// created topics in it, or if it was seeded as a forum.
+func forumChat(st *session.State, chatID string) bool {
+	if st.Topics.Known(chatID) {
+		return true
+	}
+	isForum, _ := st.Chats.Field(chatID, "is_forum")
+	return isForum == true
+}
+
+// updateTopic applies the calls changing forum topics, and checks that
+// messages are sent to topics that exist and are open.
+func updateTopic(st *session.State, spec gen.MethodSpec, method string, params map[string]interface{}) *tgerrors.Error {
+	chatID := messages.ChatKey(params["chat_id"])
+	if chatID == "" || !forumChat(st, chatID) {
+		return nil
+	}
+	threadID, hasThread := int64Value(params["message_thread_id"])
+
+	switch method {
+	case "editForumTopic":
+		var name, icon *string
+		if v, ok := params["name"].(string); ok {
+			name = &v
+		}
+		if v, ok := params["icon_custom_emoji_id"].(string); ok {
+			icon = &v
+		}
+		return st.Topics.Edit(chatID, threadID, name, icon)
+	case "closeForumTopic":
+		return st.Topics.SetClosed(chatID, threadID, true)
+	case "reopenForumTopic":
+		return st.Topics.SetClosed(chatID, threadID, false)
+	case "deleteForumTopic":
+		return st.Topics.Delete(chatID, threadID)
+	}
+
+	if !hasThread || threadID == topics.GeneralThreadID || !returnsMessages(spec) || editMethods[method] {
+		return nil
+	}
+	topic, ok := st.Topics.Get(chatID, threadID)
+	if !ok {
+		return tgerrors.MessageThreadNotFound()
+	}
+	if topic.Closed {
+		return tgerrors.TopicClosed()
+	}
+	return nil
+}
+
+// applyTopic stores the topics bots create, and marks messages sent to a
+// topic as topic messages.
+func applyTopic(st *session.State, method string, params map[string]interface{}, result interface{}) interface{} {
+	chatID := messages.ChatKey(params["chat_id"])
+	if chatID == "" {
+		return result
+	}
+
+	if method == "createForumTopic" {
+		generated, _ := result.(map[string]interface{})
+		threadID, _ := int64Value(generated["message_thread_id"])
+		topic := topics.Topic{ChatID: chatID, MessageThreadID: threadID}
+		topic.Name, _ = params["name"].(string)
+		topic.IconColor, _ = int64Value(params["icon_color"])
+		topic.IconCustomEmojiID, _ = params["icon_custom_emoji_id"].(string)
+		return st.Topics.Create(topic).ForumTopic()
+	}
+
+	threadID, ok := int64Value(params["message_thread_id"])
+	if !ok || editMethods[method] {
+		return result
+	}
+	if _, known := st.Topics.Get(chatID, threadID); !known {
+		return result
+	}
+	markTopic := func(v interface{}) {
+		if msg, ok := v.(map[string]interface{}); ok && msg["message_id"] != nil {
+			msg["message_thread_id"] = threadID
+			msg["is_topic_message"] = true
+		}
+	}
+	if list, ok := result.([]interface{}); ok {
+		for _, msg := range list {
+			markTopic(msg)
+		}
+	} else {
+		markTopic(result)
+	}
+	return result
+}
@@ -0,0 +1,90 @@
+// internal/server/traffic.go
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/config"
+	"github.com/watzon/tg-mock/internal/session"
+)
+
+// messageUpdateTypes are the update fields holding a Message.
+var messageUpdateTypes = []string{
+	"message", "edited_message", "channel_post", "edited_channel_post",
+	"business_message", "edited_business_message",
+}
+
+// newConfigUpdate turns an update from the config file into one to
+// deliver. The template is copied, and messages without a message_id or
+// date get fresh ones, so an update can be sent more than once.
+func newConfigUpdate(st *session.State, template map[string]interface{}, now time.Time) map[string]interface{} {
+	var update map[string]interface{}
+	data, err := json.Marshal(template)
+	if err != nil || json.Unmarshal(data, &update) != nil {
+		return nil
+	}
+	for _, field := range messageUpdateTypes {
+		msg, ok := update[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if _, ok := msg["message_id"]; !ok {
+			msg["message_id"] = st.Faker.NextMessageID()
+		}
+		if _, ok := msg["date"]; !ok {
+			msg["date"] = now.Unix()
+		}
+	}
+	return update
+}
+
+// queueStartupUpdates queues the updates of the config file in a new
+// session, so it starts with a known conversation.
+func queueStartupUpdates(st *session.State, templates []map[string]interface{}, now time.Time) {
+	for _, template := range templates {
+		update := newConfigUpdate(st, template, now)
+		if update == nil {
+			continue
+		}
+		trackQueries(st, update)
+		st.Updates.Add(update)
+	}
+}
+
+// runTraffic sends the scheduled updates of the config file until the
+// server shuts down.
+func (s *Server) runTraffic() {
+	for _, tc := range s.cfg.Traffic {
+		if tc.Every <= 0 || len(tc.Update) == 0 {
+			log.Printf("tg-mock: ignoring traffic without an interval or update")
+			continue
+		}
+		go s.sendTraffic(tc)
+	}
+}
+
+func (s *Server) sendTraffic(tc config.TrafficConfig) {
+	ticker := time.NewTicker(tc.Every)
+	defer ticker.Stop()
+	for sent := 0; tc.Count == 0 || sent < tc.Count; sent++ {
+		select {
+		case <-ticker.C:
+		case <-s.done:
+			return
+		}
+
+		// Resolved on every tick, as restarts replace the sessions
+		st := s.sessions.Default()
+		if tc.Session != "" {
+			st = s.sessions.Get(tc.Session)
+		}
+		update := newConfigUpdate(st, tc.Update, s.clock.Now())
+		if update == nil {
+			return
+		}
+		trackQueries(st, update)
+		s.botHandler.deliverUpdates(st, tc.Token, []map[string]interface{}{update})
+	}
+}
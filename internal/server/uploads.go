@@ -0,0 +1,97 @@
+// internal/server/uploads.go
+package server
+
+import (
+	"mime/multipart"
+	"strings"
+)
+
+// maxUploadMemory is how much of a multipart request is held in memory;
+// larger files are spooled to temporary files while it is parsed.
+const maxUploadMemory = 32 << 20
+
+// attachPrefix starts references to files uploaded in the same request,
+// as in "attach://photo1".
+const attachPrefix = "attach://"
+
+// uploadParam describes a file uploaded as a multipart part. The file
+// itself isn't kept; generated media take their name, type, and size
+// from the description.
+func uploadParam(fh *multipart.FileHeader) map[string]interface{} {
+	upload := map[string]interface{}{
+		"file_name": fh.Filename,
+		"file_size": fh.Size,
+	}
+	if mimeType := fh.Header.Get("Content-Type"); mimeType != "" && mimeType != "application/octet-stream" {
+		upload["mime_type"] = mimeType
+	}
+	return upload
+}
+
+// isUpload reports whether a parameter is a file uploaded as a multipart
+// part.
+func isUpload(v interface{}) bool {
+	upload, ok := v.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, ok = upload["file_size"].(int64)
+	return ok
+}
+
+// inputFile resolves an InputFile parameter: an attach:// reference is
+// replaced by the part it names. It returns false for references to parts
+// that weren't uploaded.
+func inputFile(params map[string]interface{}, v interface{}) (interface{}, bool) {
+	ref, ok := v.(string)
+	if !ok || !strings.HasPrefix(ref, attachPrefix) {
+		return v, true
+	}
+	part, ok := params[strings.TrimPrefix(ref, attachPrefix)]
+	if !ok || !isUpload(part) {
+		return nil, false
+	}
+	return part, true
+}
+
+// applyInputFile makes generated media reflect the file they were sent
+// as: an upload gives its name, type, and size, and a file_id is kept.
+// URLs are downloaded by Telegram, so their media stay generated. media
+// is a file object or, for photos, an array of PhotoSizes.
+func applyInputFile(media interface{}, file interface{}) {
+	var target map[string]interface{}
+	photo := false
+	switch m := media.(type) {
+	case map[string]interface{}:
+		target = m
+	case []map[string]interface{}:
+		if len(m) > 0 {
+			target, photo = m[len(m)-1], true
+		}
+	case []interface{}:
+		if len(m) > 0 {
+			target, _ = m[len(m)-1].(map[string]interface{})
+			photo = true
+		}
+	}
+	if target == nil {
+		return
+	}
+
+	switch f := file.(type) {
+	case map[string]interface{}:
+		target["file_size"] = f["file_size"]
+		if photo {
+			return
+		}
+		for _, name := range []string{"file_name", "mime_type"} {
+			if v, ok := f[name]; ok {
+				target[name] = v
+			}
+		}
+	case string:
+		if f != "" && !strings.Contains(f, "://") {
+			target["file_id"] = f
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// internal/server/users.go
+package server
+
+import (
+	"log"
+
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/users"
+)
+
+// applyUsers gives every user in a generated response the profile it was
+// seeded with, and private chats with seeded users their names.
+func applyUsers(st *session.State, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if _, ok := v["is_bot"]; ok {
+			st.Users.Apply(v)
+		} else if v["type"] == "private" {
+			applyPrivateChat(st, v)
+		}
+		for _, child := range v {
+			applyUsers(st, child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			applyUsers(st, child)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			applyUsers(st, child)
+		}
+	}
+}
+
+// applyPrivateChat names a private chat after its seeded user.
+func applyPrivateChat(st *session.State, chat map[string]interface{}) {
+	id, ok := users.ID(chat["id"])
+	if !ok {
+		return
+	}
+	user, ok := st.Users.Get(id)
+	if !ok {
+		return
+	}
+	for _, field := range []string{"first_name", "last_name", "username"} {
+		if value, ok := user[field]; ok {
+			chat[field] = value
+		} else {
+			delete(chat, field)
+		}
+	}
+}
+
+// seedUsers adds the users of the config file to a new session.
+func seedUsers(st *session.State, seeds []map[string]interface{}) {
+	for _, user := range seeds {
+		if _, err := st.Users.Seed(user); err != nil {
+			log.Printf("tg-mock: ignoring user %v: %v", user["id"], err)
+		}
+	}
+}
@@ -2,7 +2,9 @@
 package server
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 
 	"github.com/watzon/tg-mock/gen"
 )
@@ -30,3 +32,152 @@ func (v *Validator) Validate(spec gen.MethodSpec, params map[string]interface{})
 
 	return nil
 }
+
+// ResultValidation says what happens to generated results that break the
+// spec.
+type ResultValidation string
+
+const (
+	// ResultValidationOff returns results unchecked.
+	ResultValidationOff ResultValidation = ""
+	// ResultValidationLog logs results that break the spec and returns
+	// them anyway.
+	ResultValidationLog ResultValidation = "log"
+	// ResultValidationFail answers 500 instead of results that break the
+	// spec.
+	ResultValidationFail ResultValidation = "fail"
+)
+
+// ParseResultValidation parses a result validation mode: off, log, or
+// fail. The empty string is off.
+func ParseResultValidation(s string) (ResultValidation, error) {
+	switch s {
+	case "", "off":
+		return ResultValidationOff, nil
+	case "log":
+		return ResultValidationLog, nil
+	case "fail":
+		return ResultValidationFail, nil
+	}
+	return ResultValidationOff, fmt.Errorf("invalid result validation %q: want off, log, or fail", s)
+}
+
+// ValidateResult checks a result against the return type of a method, as
+// a strict client would decode it: every object has the fields its type
+// requires, and every value has the type the spec gives it. It returns
+// the problems found, such as "result.chat.id: missing required field".
+func (v *Validator) ValidateResult(spec gen.MethodSpec, result interface{}) []string {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return []string{"result: " + err.Error()}
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return []string{"result: " + err.Error()}
+	}
+	return checkValue("result", spec.Result, decoded)
+}
+
+// checkValue returns the problems of v as a value of type t. A union
+// accepts a value that is any of its alternatives; otherwise the problems
+// of the closest alternative are returned.
+func checkValue(path string, t gen.TypeRef, v interface{}) []string {
+	switch {
+	case t.IsUnion():
+		var closest []string
+		for i, alt := range t.Union {
+			problems := checkValue(path, alt, v)
+			if len(problems) == 0 {
+				return nil
+			}
+			if i == 0 || len(problems) < len(closest) {
+				closest = problems
+			}
+		}
+		return closest
+	case t.IsArray():
+		items, ok := v.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected %s, got %s", path, t, jsonType(v))}
+		}
+		var problems []string
+		for i, item := range items {
+			problems = append(problems, checkValue(fmt.Sprintf("%s[%d]", path, i), *t.Elem, item)...)
+		}
+		return problems
+	}
+
+	var ok bool
+	switch t.Name {
+	case "Integer":
+		n, isNumber := v.(float64)
+		ok = isNumber && n == math.Trunc(n)
+	case "Float":
+		_, ok = v.(float64)
+	case "String":
+		_, ok = v.(string)
+	case "Boolean":
+		_, ok = v.(bool)
+	case "True":
+		ok = v == true
+	default:
+		typ, known := gen.Types[t.Name]
+		if !known {
+			// Such as InputFile, which results don't contain
+			return nil
+		}
+		obj, isObject := v.(map[string]interface{})
+		if !isObject {
+			break
+		}
+		if len(typ.Subtypes) > 0 {
+			union := gen.TypeRef{}
+			for _, name := range typ.Subtypes {
+				union.Union = append(union.Union, gen.TypeRef{Name: name})
+			}
+			return checkValue(path, union, v)
+		}
+		return checkFields(path, typ, obj)
+	}
+	if !ok {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, t, jsonType(v))}
+	}
+	return nil
+}
+
+// checkFields returns the problems of the fields of an object of type typ.
+// Fields the spec doesn't know are allowed.
+func checkFields(path string, typ gen.TypeSpec, obj map[string]interface{}) []string {
+	var problems []string
+	for _, f := range typ.Fields {
+		v, ok := obj[f.Name]
+		switch {
+		case !ok && f.Required:
+			problems = append(problems, path+"."+f.Name+": missing required field")
+		case ok && v == nil:
+			// Telegram leaves out fields without a value
+			problems = append(problems, path+"."+f.Name+": null")
+		case ok:
+			problems = append(problems, checkValue(path+"."+f.Name, gen.ParseType(f.Types...), v)...)
+		}
+	}
+	return problems
+}
+
+// jsonType names the JSON type of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
@@ -2,9 +2,12 @@
 package server
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/faker"
 )
 
 func TestValidateRequest(t *testing.T) {
@@ -51,3 +54,94 @@ func TestValidateRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateResult(t *testing.T) {
+	v := NewValidator()
+
+	user := map[string]interface{}{"id": 1, "is_bot": true, "first_name": "Bot"}
+	tests := []struct {
+		name   string
+		method string
+		result interface{}
+		want   []string
+	}{
+		{"valid", "getMe", user, nil},
+		{"missing field", "getMe", map[string]interface{}{"id": 1, "first_name": "Bot"}, []string{"result.is_bot: missing required field"}},
+		{"wrong type", "getMe", map[string]interface{}{"id": 1.5, "is_bot": "yes", "first_name": "Bot"}, []string{
+			"result.id: expected Integer, got number",
+			"result.is_bot: expected Boolean, got string",
+		}},
+		{"null field", "getMe", map[string]interface{}{"id": 1, "is_bot": true, "first_name": "Bot", "username": nil}, []string{"result.username: null"}},
+		{"nested", "sendMessage", map[string]interface{}{"message_id": 1, "date": 0, "chat": map[string]interface{}{"type": "private"}}, []string{"result.chat.id: missing required field"}},
+		{"array", "getUpdates", []interface{}{map[string]interface{}{"update_id": 1}, map[string]interface{}{}}, []string{"result[1].update_id: missing required field"}},
+		{"union result", "editMessageText", true, nil},
+		{"subtypes", "getChatMember", map[string]interface{}{"status": "member", "user": user}, nil},
+		{"no subtype", "getChatMember", map[string]interface{}{"status": "member"}, []string{"result.user: missing required field"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.ValidateResult(gen.Methods[tt.method], tt.result)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseResultValidation(t *testing.T) {
+	for s, want := range map[string]ResultValidation{"": ResultValidationOff, "off": ResultValidationOff, "log": ResultValidationLog, "fail": ResultValidationFail} {
+		if got, err := ParseResultValidation(s); err != nil || got != want {
+			t.Errorf("%q: expected %q, got %q %v", s, want, got, err)
+		}
+	}
+	if _, err := ParseResultValidation("strict"); err == nil {
+		t.Error("expected an unknown mode to be rejected")
+	}
+}
+
+func TestFakerTypesMatchSpec(t *testing.T) {
+	// Every type, whether it has a dedicated generator or is generated
+	// from its spec
+	var types []string
+	for name := range gen.Types {
+		// InputFile is uploaded rather than sent as JSON
+		if name != "InputFile" {
+			types = append(types, name)
+		}
+	}
+	// Several seeds, to go down the generators' random branches
+	for seed := int64(1); seed <= 20; seed++ {
+		f := faker.New(faker.Config{Seed: seed})
+		for _, name := range types {
+			data, _ := json.Marshal(f.Generate(name, map[string]interface{}{"chat_id": int64(-100)}))
+			var decoded interface{}
+			json.Unmarshal(data, &decoded)
+			if problems := checkValue("result", gen.ParseType(name), decoded); len(problems) > 0 {
+				t.Errorf("seed %d: %s breaks the spec: %q", seed, name, problems)
+			}
+		}
+	}
+}
+
+func TestFakerUpdateKinds(t *testing.T) {
+	f := faker.New(faker.Config{Seed: 1})
+	for _, kind := range faker.UpdateKinds() {
+		update, err := f.GenerateUpdate(kind, nil)
+		if err != nil {
+			t.Errorf("%s: %v", kind, err)
+			continue
+		}
+		if _, ok := update[kind]; !ok || len(update) != 2 {
+			t.Errorf("%s: expected update_id and %s, got %v", kind, kind, update)
+		}
+		data, _ := json.Marshal(update)
+		var decoded interface{}
+		json.Unmarshal(data, &decoded)
+		if problems := checkValue("update", gen.ParseType("Update"), decoded); len(problems) > 0 {
+			t.Errorf("%s breaks the spec: %q", kind, problems)
+		}
+	}
+	if _, err := f.GenerateUpdate("telepathy", nil); err == nil {
+		t.Error("expected an unknown kind to be rejected")
+	}
+}
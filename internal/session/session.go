@@ -0,0 +1,188 @@
+// Package session isolates mutable mock state into named namespaces so that
+// independent test runs can share one tg-mock instance without interfering.
+package session
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/adversarial"
+	"github.com/watzon/tg-mock/internal/apiversion"
+	"github.com/watzon/tg-mock/internal/archive"
+	"github.com/watzon/tg-mock/internal/boosts"
+	"github.com/watzon/tg-mock/internal/botsettings"
+	"github.com/watzon/tg-mock/internal/callbackquery"
+	"github.com/watzon/tg-mock/internal/chaos"
+	"github.com/watzon/tg-mock/internal/chataction"
+	"github.com/watzon/tg-mock/internal/chats"
+	"github.com/watzon/tg-mock/internal/compat"
+	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/floodlimit"
+	"github.com/watzon/tg-mock/internal/games"
+	"github.com/watzon/tg-mock/internal/inlinequery"
+	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/invitelinks"
+	"github.com/watzon/tg-mock/internal/members"
+	"github.com/watzon/tg-mock/internal/messages"
+	"github.com/watzon/tg-mock/internal/outage"
+	"github.com/watzon/tg-mock/internal/persona"
+	"github.com/watzon/tg-mock/internal/polls"
+	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/stars"
+	"github.com/watzon/tg-mock/internal/stickersets"
+	"github.com/watzon/tg-mock/internal/topics"
+	"github.com/watzon/tg-mock/internal/updates"
+	"github.com/watzon/tg-mock/internal/users"
+)
+
+// Header is the request header used to select a session.
+const Header = "X-TG-Mock-Session"
+
+// DefaultName is the name of the session used when none is selected.
+const DefaultName = ""
+
+// State is the isolated state belonging to a single session.
+type State struct {
+	Name          string
+	Scenarios     *scenario.Engine
+	Updates       *updates.Queue
+	LongPolls     *updates.LongPolls
+	Recorder      *inspector.Recorder
+	Messages      *messages.Store
+	Chats         *chats.Store
+	Bots          *botsettings.Store
+	Users         *users.Store
+	Members       *members.Store
+	InviteLinks   *invitelinks.Store
+	Topics        *topics.Store
+	Polls         *polls.Store
+	Games         *games.Store
+	StickerSets   *stickersets.Store
+	Boosts        *boosts.Store
+	Stars         *stars.Store
+	ChatActions   *chataction.Tracker
+	InlineQueries *inlinequery.Tracker
+	Callbacks     *callbackquery.Tracker
+	Personas      *persona.Registry
+	Compat        *compat.Mutator
+	Adversarial   *adversarial.Mutator
+	APIVersion    *apiversion.Gate
+	Outage        *outage.Burst
+	Chaos         *chaos.Injector
+	FloodLimit    *floodlimit.Limiter
+	Cooldowns     *floodlimit.Cooldowns
+	Archive       *archive.Archive
+	Faker         *faker.Faker
+}
+
+// Factory creates the initial state for a newly seen session.
+type Factory func(name string) *State
+
+// Manager owns all sessions and lazily creates them on first use.
+type Manager struct {
+	mu       sync.RWMutex
+	sessions map[string]*State
+	factory  Factory
+}
+
+// NewManager creates a session manager. The default session is created
+// immediately so it always exists.
+func NewManager(factory Factory) *Manager {
+	m := &Manager{
+		sessions: make(map[string]*State),
+		factory:  factory,
+	}
+	m.sessions[DefaultName] = factory(DefaultName)
+	return m
+}
+
+// Default returns the default session.
+func (m *Manager) Default() *State {
+	return m.Get(DefaultName)
+}
+
+// Get returns the named session, creating it if it doesn't exist yet.
+func (m *Manager) Get(name string) *State {
+	m.mu.RLock()
+	st, ok := m.sessions[name]
+	m.mu.RUnlock()
+	if ok {
+		return st
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if st, ok := m.sessions[name]; ok {
+		return st
+	}
+	st = m.factory(name)
+	m.sessions[name] = st
+	return st
+}
+
+// Put adds a session created outside the factory, replacing any session
+// with the same name.
+func (m *Manager) Put(st *State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[st.Name] = st
+}
+
+// Lookup returns the named session without creating it.
+func (m *Manager) Lookup(name string) (*State, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	st, ok := m.sessions[name]
+	return st, ok
+}
+
+// List returns all sessions sorted by name. The default session comes first.
+func (m *Manager) List() []*State {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	result := make([]*State, 0, len(m.sessions))
+	for _, st := range m.sessions {
+		result = append(result, st)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// Delete discards a named session. The default session cannot be deleted.
+// Returns true if a session was removed.
+func (m *Manager) Delete(name string) bool {
+	if name == DefaultName {
+		return false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.sessions[name]; ok {
+		delete(m.sessions, name)
+		return true
+	}
+	return false
+}
+
+// Reset discards every session, including the default one, and recreates
+// the default session from the factory.
+func (m *Manager) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions = map[string]*State{
+		DefaultName: m.factory(DefaultName),
+	}
+}
+
+type contextKey struct{}
+
+// WithState returns a copy of ctx carrying the given session.
+func WithState(ctx context.Context, st *State) context.Context {
+	return context.WithValue(ctx, contextKey{}, st)
+}
+
+// FromContext returns the session stored in ctx, or nil if there is none.
+func FromContext(ctx context.Context) *State {
+	st, _ := ctx.Value(contextKey{}).(*State)
+	return st
+}
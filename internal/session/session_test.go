@@ -0,0 +1,109 @@
+// internal/session/session_test.go
+package session
+
+import (
+	"context"
+	"testing"
+
+	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/updates"
+)
+
+func newTestManager() *Manager {
+	return NewManager(func(name string) *State {
+		return &State{
+			Name:      name,
+			Scenarios: scenario.NewEngine(),
+			Updates:   updates.NewQueue(),
+			Recorder:  inspector.NewRecorder(),
+		}
+	})
+}
+
+func TestManager_DefaultExists(t *testing.T) {
+	m := newTestManager()
+
+	if _, ok := m.Lookup(DefaultName); !ok {
+		t.Fatal("expected default session to exist")
+	}
+	if m.Default() != m.Get(DefaultName) {
+		t.Error("Default() should return the same state as Get(DefaultName)")
+	}
+}
+
+func TestManager_Isolation(t *testing.T) {
+	m := newTestManager()
+
+	a := m.Get("a")
+	b := m.Get("b")
+	if a == b {
+		t.Fatal("expected different sessions to have different state")
+	}
+	if m.Get("a") != a {
+		t.Error("expected Get to return the existing session")
+	}
+
+	a.Updates.Add(map[string]interface{}{"message": "only in a"})
+	if b.Updates.Pending() != 0 {
+		t.Error("updates leaked between sessions")
+	}
+	if m.Default().Updates.Pending() != 0 {
+		t.Error("updates leaked into default session")
+	}
+}
+
+func TestManager_ListAndDelete(t *testing.T) {
+	m := newTestManager()
+	m.Get("job-2")
+	m.Get("job-1")
+
+	list := m.List()
+	if len(list) != 3 {
+		t.Fatalf("got %d sessions, want 3", len(list))
+	}
+	if list[0].Name != DefaultName || list[1].Name != "job-1" || list[2].Name != "job-2" {
+		t.Errorf("unexpected order: %q, %q, %q", list[0].Name, list[1].Name, list[2].Name)
+	}
+
+	if !m.Delete("job-1") {
+		t.Error("expected Delete to return true for existing session")
+	}
+	if m.Delete("job-1") {
+		t.Error("expected Delete to return false for missing session")
+	}
+	if m.Delete(DefaultName) {
+		t.Error("default session must not be deletable")
+	}
+}
+
+func TestContext(t *testing.T) {
+	if FromContext(context.Background()) != nil {
+		t.Error("expected nil session for empty context")
+	}
+
+	st := &State{Name: "ctx"}
+	ctx := WithState(context.Background(), st)
+	if FromContext(ctx) != st {
+		t.Error("expected session from context")
+	}
+}
+
+func TestManager_Reset(t *testing.T) {
+	m := newTestManager()
+	oldDefault := m.Default()
+	oldDefault.Updates.Add(map[string]interface{}{})
+	m.Get("job")
+
+	m.Reset()
+
+	if _, ok := m.Lookup("job"); ok {
+		t.Error("expected named sessions to be discarded")
+	}
+	if m.Default() == oldDefault {
+		t.Error("expected default session to be recreated")
+	}
+	if m.Default().Updates.Pending() != 0 {
+		t.Error("expected fresh default session")
+	}
+}
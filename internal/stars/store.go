@@ -0,0 +1,192 @@
+// Package stars keeps a ledger of the Telegram Star transactions of each
+// bot, so that getStarTransactions returns what tests seeded and
+// refundStarPayment refunds the payments in it.
+package stars
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// Errors returned by Refund.
+var (
+	ErrAlreadyRefunded = errors.New("payment has already been refunded")
+	ErrWrongUser       = errors.New("payment was made by another user")
+)
+
+// MaxLimit is the most transactions getStarTransactions returns at once.
+const MaxLimit = 100
+
+// Ledger is the list of Star transactions of a bot, keyed by its token.
+type Ledger struct {
+	Token        string                   `json:"token"`
+	Transactions []map[string]interface{} `json:"transactions"`
+}
+
+// Store holds the ledgers of bots. Values are copied on the way in and
+// out.
+type Store struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	ledgers map[string][]map[string]interface{}
+}
+
+// NewStore creates an empty store reading the time from now, which dates
+// refunds and transactions seeded without a date.
+func NewStore(now func() time.Time) *Store {
+	return &Store{now: now, ledgers: make(map[string][]map[string]interface{})}
+}
+
+// Add appends a StarTransaction to the ledger of the bot with token and
+// returns it. The transaction needs an id and a positive amount; date
+// defaults to now.
+func (s *Store) Add(token string, tx map[string]interface{}) (map[string]interface{}, error) {
+	tx = jsoncopy.Map(tx)
+	if id, _ := tx["id"].(string); id == "" {
+		return nil, fmt.Errorf("transaction needs an id")
+	}
+	if amount, ok := tx["amount"].(float64); !ok || amount <= 0 || amount != float64(int64(amount)) {
+		return nil, fmt.Errorf("transaction needs a positive integer amount")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := tx["date"]; !ok {
+		tx["date"] = float64(s.now().Unix())
+	}
+	s.ledgers[token] = append(s.ledgers[token], tx)
+	s.sort(token)
+	return jsoncopy.Map(tx), nil
+}
+
+// Known reports whether the bot with token has any transactions.
+func (s *Store) Known(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.ledgers[token]) > 0
+}
+
+// Transactions returns the transactions of the bot with token in
+// chronological order, skipping offset of them and returning at most
+// limit. A limit outside 1-100 returns up to 100.
+func (s *Store) Transactions(token string, offset, limit int) []map[string]interface{} {
+	if limit < 1 || limit > MaxLimit {
+		limit = MaxLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ledger := s.ledgers[token]
+	list := make([]map[string]interface{}, 0)
+	for i := offset; i < len(ledger) && len(list) < limit; i++ {
+		list = append(list, jsoncopy.Map(ledger[i]))
+	}
+	return list
+}
+
+// Refund refunds the payment with the given charge ID that the user made
+// to the bot with token, adding a transaction to the user. It returns
+// false if the ledger has no such payment from a user.
+func (s *Store) Refund(token string, userID int64, chargeID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var payment map[string]interface{}
+	for _, tx := range s.ledgers[token] {
+		if tx["id"] != chargeID {
+			continue
+		}
+		if _, ok := tx["receiver"]; ok {
+			return true, ErrAlreadyRefunded
+		}
+		if source, ok := tx["source"].(map[string]interface{}); ok && source["type"] == "user" {
+			payment = tx
+		}
+	}
+	if payment == nil {
+		return false, nil
+	}
+	source := payment["source"].(map[string]interface{})
+	user, _ := source["user"].(map[string]interface{})
+	if id, _ := user["id"].(float64); int64(id) != userID {
+		return true, ErrWrongUser
+	}
+
+	refund := map[string]interface{}{
+		"id":       chargeID,
+		"amount":   payment["amount"],
+		"date":     float64(s.now().Unix()),
+		"receiver": jsoncopy.Map(source),
+	}
+	if nanos, ok := payment["nanostar_amount"]; ok {
+		refund["nanostar_amount"] = nanos
+	}
+	s.ledgers[token] = append(s.ledgers[token], refund)
+	s.sort(token)
+	return true, nil
+}
+
+// List returns the ledgers of every bot, ordered by token.
+func (s *Store) List() []Ledger {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Ledger, 0, len(s.ledgers))
+	for token, ledger := range s.ledgers {
+		l := Ledger{Token: token, Transactions: make([]map[string]interface{}, len(ledger))}
+		for i, tx := range ledger {
+			l.Transactions[i] = jsoncopy.Map(tx)
+		}
+		list = append(list, l)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Token < list[j].Token })
+	return list
+}
+
+// Restore replaces the store contents with the given ledgers.
+func (s *Store) Restore(list []Ledger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ledgers = make(map[string][]map[string]interface{}, len(list))
+	for _, l := range list {
+		for _, tx := range l.Transactions {
+			s.ledgers[l.Token] = append(s.ledgers[l.Token], jsoncopy.Map(tx))
+		}
+		s.sort(l.Token)
+	}
+}
+
+// Delete forgets the transactions of the bot with token. It returns false
+// if it had none.
+func (s *Store) Delete(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.ledgers[token]; !ok {
+		return false
+	}
+	delete(s.ledgers, token)
+	return true
+}
+
+// Clear forgets all transactions.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ledgers = make(map[string][]map[string]interface{})
+}
+
+// sort keeps a ledger in chronological order. Transactions of the same
+// date keep the order they were added in. Callers must hold s.mu.
+func (s *Store) sort(token string) {
+	ledger := s.ledgers[token]
+	sort.SliceStable(ledger, func(i, j int) bool {
+		di, _ := ledger[i]["date"].(float64)
+		dj, _ := ledger[j]["date"].(float64)
+		return di < dj
+	})
+}
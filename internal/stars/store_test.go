@@ -0,0 +1,83 @@
+// internal/stars/store_test.go
+package stars
+
+import (
+	"testing"
+	"time"
+)
+
+func payment(id string, userID int64, amount int, date int64) map[string]interface{} {
+	return map[string]interface{}{
+		"id":     id,
+		"amount": amount,
+		"date":   date,
+		"source": map[string]interface{}{
+			"type":             "user",
+			"transaction_type": "invoice_payment",
+			"user":             map[string]interface{}{"id": userID, "is_bot": false, "first_name": "Ann"},
+		},
+	}
+}
+
+func TestStore_AddAndTransactions(t *testing.T) {
+	s := NewStore(func() time.Time { return time.Unix(1700000300, 0) })
+
+	if _, err := s.Add("bot", map[string]interface{}{"amount": 5}); err == nil {
+		t.Error("expected a transaction without an id to be rejected")
+	}
+	if _, err := s.Add("bot", map[string]interface{}{"id": "x", "amount": -5}); err == nil {
+		t.Error("expected a negative amount to be rejected")
+	}
+
+	s.Add("bot", payment("b", 42, 10, 1700000200))
+	s.Add("bot", payment("a", 42, 20, 1700000100))
+	tx, err := s.Add("bot", map[string]interface{}{"id": "c", "amount": 1})
+	if err != nil || tx["date"] != float64(1700000300) {
+		t.Fatalf("expected the date to default to now, got %v %v", tx, err)
+	}
+
+	list := s.Transactions("bot", 0, 0)
+	if len(list) != 3 || list[0]["id"] != "a" || list[1]["id"] != "b" || list[2]["id"] != "c" {
+		t.Errorf("expected transactions in chronological order, got %v", list)
+	}
+	if list := s.Transactions("bot", 1, 1); len(list) != 1 || list[0]["id"] != "b" {
+		t.Errorf("unexpected page %v", list)
+	}
+	if list := s.Transactions("other", 0, 10); list == nil || len(list) != 0 || s.Known("other") {
+		t.Errorf("expected other bots to have no transactions, got %v", list)
+	}
+}
+
+func TestStore_Refund(t *testing.T) {
+	s := NewStore(func() time.Time { return time.Unix(1700000500, 0) })
+	s.Add("bot", payment("charge-1", 42, 25, 1700000100))
+
+	if known, err := s.Refund("bot", 42, "unknown"); known || err != nil {
+		t.Errorf("expected unknown charges to be reported, got %v %v", known, err)
+	}
+	if _, err := s.Refund("bot", 7, "charge-1"); err != ErrWrongUser {
+		t.Errorf("expected a refund to another user to fail, got %v", err)
+	}
+	if known, err := s.Refund("bot", 42, "charge-1"); !known || err != nil {
+		t.Fatalf("expected the refund to succeed, got %v %v", known, err)
+	}
+	list := s.Transactions("bot", 0, 0)
+	if len(list) != 2 {
+		t.Fatalf("expected a refund transaction, got %v", list)
+	}
+	refund := list[1]
+	receiver, _ := refund["receiver"].(map[string]interface{})
+	if refund["amount"] != float64(25) || refund["date"] != float64(1700000500) || receiver["type"] != "user" {
+		t.Errorf("unexpected refund %v", refund)
+	}
+	if _, err := s.Refund("bot", 42, "charge-1"); err != ErrAlreadyRefunded {
+		t.Errorf("expected a second refund to fail, got %v", err)
+	}
+
+	snapshot := s.List()
+	s.Clear()
+	s.Restore(snapshot)
+	if list := s.Transactions("bot", 0, 0); len(list) != 2 {
+		t.Errorf("expected restored transactions, got %v", list)
+	}
+}
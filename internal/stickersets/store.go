@@ -0,0 +1,196 @@
+// Package stickersets keeps the sticker sets bots create, so that
+// getStickerSet returns the set with the stickers that were added to it
+// instead of a random one.
+package stickersets
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// ErrNameOccupied is returned by Create for a name that is already taken.
+var ErrNameOccupied = errors.New("sticker set name is already occupied")
+
+// Store holds sticker sets as StickerSet objects, keyed by name. Values
+// are copied on the way in and out.
+type Store struct {
+	mu   sync.Mutex
+	sets map[string]map[string]interface{}
+}
+
+// NewStore creates an empty sticker set store.
+func NewStore() *Store {
+	return &Store{sets: make(map[string]map[string]interface{})}
+}
+
+// Create stores a new sticker set. The set needs a name that isn't taken.
+func (s *Store) Create(set map[string]interface{}) error {
+	name, _ := set["name"].(string)
+	if name == "" {
+		return errors.New("sticker set needs a name")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sets[name]; ok {
+		return ErrNameOccupied
+	}
+	s.sets[name] = jsoncopy.Map(set)
+	return nil
+}
+
+// Get returns a sticker set by name.
+func (s *Store) Get(name string) (map[string]interface{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set, ok := s.sets[name]
+	if !ok {
+		return nil, false
+	}
+	return jsoncopy.Map(set), true
+}
+
+// SetTitle changes the title of a set. It returns false if the set isn't
+// known.
+func (s *Store) SetTitle(name, title string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set, ok := s.sets[name]
+	if ok {
+		set["title"] = title
+	}
+	return ok
+}
+
+// AddSticker appends a Sticker to a set. It returns false if the set isn't
+// known.
+func (s *Store) AddSticker(name string, sticker map[string]interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set, ok := s.sets[name]
+	if !ok {
+		return false
+	}
+	set["stickers"] = append(stickers(set), jsoncopy.Map(sticker))
+	return true
+}
+
+// ReplaceSticker replaces the sticker with the file ID old in a set,
+// keeping its position. It returns false if the set has no such sticker.
+func (s *Store) ReplaceSticker(name, old string, sticker map[string]interface{}) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := stickers(s.sets[name])
+	if i := index(list, old); i >= 0 {
+		list[i] = jsoncopy.Map(sticker)
+		return true
+	}
+	return false
+}
+
+// DeleteSticker removes the sticker with a file ID from the set it is in.
+// It returns false if no known set has it.
+func (s *Store) DeleteSticker(fileID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, set := range s.sets {
+		list := stickers(set)
+		if i := index(list, fileID); i >= 0 {
+			set["stickers"] = append(list[:i], list[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// MoveSticker moves the sticker with a file ID to a zero-based position in
+// its set. Positions past the end move it last. It returns false if no
+// known set has the sticker.
+func (s *Store) MoveSticker(fileID string, position int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, set := range s.sets {
+		list := stickers(set)
+		i := index(list, fileID)
+		if i < 0 {
+			continue
+		}
+		sticker := list[i]
+		list = append(list[:i], list[i+1:]...)
+		if position < 0 {
+			position = 0
+		}
+		if position > len(list) {
+			position = len(list)
+		}
+		list = append(list[:position], append([]interface{}{sticker}, list[position:]...)...)
+		set["stickers"] = list
+		return true
+	}
+	return false
+}
+
+// Delete forgets a sticker set. It returns false if it wasn't known.
+func (s *Store) Delete(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sets[name]; !ok {
+		return false
+	}
+	delete(s.sets, name)
+	return true
+}
+
+// List returns the known sticker sets, ordered by name.
+func (s *Store) List() []map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.sets))
+	for name := range s.sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := make([]map[string]interface{}, len(names))
+	for i, name := range names {
+		list[i] = jsoncopy.Map(s.sets[name])
+	}
+	return list
+}
+
+// Restore replaces the store contents with the given sets. Sets without a
+// name are skipped.
+func (s *Store) Restore(list []map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sets = make(map[string]map[string]interface{}, len(list))
+	for _, set := range list {
+		if name, _ := set["name"].(string); name != "" {
+			s.sets[name] = jsoncopy.Map(set)
+		}
+	}
+}
+
+// Clear forgets all sticker sets.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sets = make(map[string]map[string]interface{})
+}
+
+// stickers returns the stickers of a set.
+func stickers(set map[string]interface{}) []interface{} {
+	list, _ := set["stickers"].([]interface{})
+	return list
+}
+
+// index returns the position of the sticker with a file ID, or -1.
+func index(list []interface{}, fileID string) int {
+	for i, item := range list {
+		if sticker, ok := item.(map[string]interface{}); ok && sticker["file_id"] == fileID {
+			return i
+		}
+	}
+	return -1
+}
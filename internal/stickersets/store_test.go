@@ -0,0 +1,82 @@
+// internal/stickersets/store_test.go
+package stickersets
+
+import "testing"
+
+func sticker(fileID string) map[string]interface{} {
+	return map[string]interface{}{"file_id": fileID, "type": "regular"}
+}
+
+func fileIDs(t *testing.T, s *Store, name string) []string {
+	t.Helper()
+	set, ok := s.Get(name)
+	if !ok {
+		t.Fatalf("expected set %q to be known", name)
+	}
+	var ids []string
+	for _, item := range set["stickers"].([]interface{}) {
+		ids = append(ids, item.(map[string]interface{})["file_id"].(string))
+	}
+	return ids
+}
+
+func TestStore_Create(t *testing.T) {
+	s := NewStore()
+	set := map[string]interface{}{
+		"name":         "cats_by_bot",
+		"title":        "Cats",
+		"sticker_type": "regular",
+		"stickers":     []interface{}{sticker("a")},
+	}
+	if err := s.Create(set); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Create(set); err != ErrNameOccupied {
+		t.Errorf("expected a taken name to be rejected, got %v", err)
+	}
+	if !s.SetTitle("cats_by_bot", "More cats") || s.SetTitle("dogs_by_bot", "Dogs") {
+		t.Error("expected only known sets to be renamed")
+	}
+	if got, _ := s.Get("cats_by_bot"); got["title"] != "More cats" {
+		t.Errorf("expected the new title, got %v", got)
+	}
+	if !s.Delete("cats_by_bot") || s.Delete("cats_by_bot") {
+		t.Error("expected the set to be deleted once")
+	}
+}
+
+func TestStore_Stickers(t *testing.T) {
+	s := NewStore()
+	s.Create(map[string]interface{}{"name": "cats_by_bot", "title": "Cats", "stickers": []interface{}{sticker("a")}})
+
+	if !s.AddSticker("cats_by_bot", sticker("b")) || s.AddSticker("dogs_by_bot", sticker("x")) {
+		t.Fatal("expected stickers to be added to known sets only")
+	}
+	s.AddSticker("cats_by_bot", sticker("c"))
+	if ids := fileIDs(t, s, "cats_by_bot"); len(ids) != 3 || ids[2] != "c" {
+		t.Fatalf("unexpected stickers %v", ids)
+	}
+
+	if !s.MoveSticker("c", 0) {
+		t.Fatal("expected the sticker to move")
+	}
+	if ids := fileIDs(t, s, "cats_by_bot"); ids[0] != "c" || ids[1] != "a" || ids[2] != "b" {
+		t.Errorf("expected the sticker first, got %v", ids)
+	}
+	if !s.ReplaceSticker("cats_by_bot", "a", sticker("d")) || s.ReplaceSticker("cats_by_bot", "a", sticker("e")) {
+		t.Error("expected a sticker to be replaced once")
+	}
+	if !s.DeleteSticker("b") || s.DeleteSticker("b") {
+		t.Error("expected a sticker to be deleted once")
+	}
+	if ids := fileIDs(t, s, "cats_by_bot"); len(ids) != 2 || ids[0] != "c" || ids[1] != "d" {
+		t.Errorf("unexpected stickers %v", ids)
+	}
+
+	snapshot := s.List()
+	s.Clear()
+	s.Restore(snapshot)
+	if ids := fileIDs(t, s, "cats_by_bot"); len(ids) != 2 {
+		t.Errorf("expected restored stickers, got %v", ids)
+	}
+}
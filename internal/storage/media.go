@@ -0,0 +1,214 @@
+// internal/storage/media.go
+package storage
+
+import "sync"
+
+// DefaultMediaLimit is how many generated files a MediaRegistry remembers
+// before it forgets the oldest.
+const DefaultMediaLimit = 100000
+
+// Media describes a file the mock made up in a response, so that it can be
+// downloaded later as content of the right kind rather than zeros.
+type Media struct {
+	// Kind is the object the file came in: "photo", "document", "voice",
+	// "audio", "video", "animation", "video_note", or "sticker".
+	Kind     string
+	MimeType string
+	// Size is the file_size the bot was told, or 0 if it wasn't.
+	Size     int64
+	Width    int
+	Height   int
+	Duration int
+}
+
+// MediaRegistry remembers the generated files that bots were given, by
+// file_id.
+type MediaRegistry struct {
+	mu    sync.Mutex
+	files map[string]Media
+	order []string // file_ids, oldest first
+	limit int
+}
+
+// NewMediaRegistry creates a registry remembering up to limit files. A
+// limit of zero uses DefaultMediaLimit.
+func NewMediaRegistry(limit int) *MediaRegistry {
+	if limit <= 0 {
+		limit = DefaultMediaLimit
+	}
+	return &MediaRegistry{files: make(map[string]Media), limit: limit}
+}
+
+// Remember notes what the file with fileID is, forgetting the oldest file
+// if the registry is full.
+func (r *MediaRegistry) Remember(fileID string, m Media) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.files[fileID]; !ok {
+		if len(r.order) >= r.limit {
+			delete(r.files, r.order[0])
+			r.order = r.order[1:]
+		}
+		r.order = append(r.order, fileID)
+	}
+	r.files[fileID] = m
+}
+
+// Lookup returns what the file with fileID is, if it is remembered.
+func (r *MediaRegistry) Lookup(fileID string) (Media, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	m, ok := r.files[fileID]
+	return m, ok
+}
+
+// Scan remembers the files in a Bot API object, such as a method result or
+// an update, wherever they are nested: photo sizes, chat photos, and the
+// documents, voice messages, audio, videos, animations, video notes, and
+// stickers of messages and sticker sets.
+func (r *MediaRegistry) Scan(v interface{}) {
+	r.scan("", v)
+}
+
+// scan is Scan for a value found under key.
+func (r *MediaRegistry) scan(key string, v interface{}) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if fileID, m, ok := photoSize(v); ok {
+			r.Remember(fileID, m)
+			return
+		}
+		if fileID, m, ok := mediaFile(key, v); ok {
+			r.Remember(fileID, m)
+		}
+		// ChatPhoto has no dimensions, but Telegram's are fixed
+		if small, ok := v["small_file_id"].(string); ok {
+			r.Remember(small, Media{Kind: "photo", Width: 160, Height: 160})
+		}
+		if big, ok := v["big_file_id"].(string); ok {
+			r.Remember(big, Media{Kind: "photo", Width: 640, Height: 640})
+		}
+		for k, child := range v {
+			r.scan(k, child)
+		}
+	case []interface{}:
+		for _, child := range v {
+			r.scan(key, child)
+		}
+	case []map[string]interface{}:
+		for _, child := range v {
+			r.scan(key, child)
+		}
+	}
+}
+
+// Clear forgets every file.
+func (r *MediaRegistry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.files = make(map[string]Media)
+	r.order = nil
+}
+
+// photoSizeFields are the fields of a PhotoSize. Objects with other fields,
+// such as videos and stickers, have dimensions too but aren't photos.
+var photoSizeFields = map[string]bool{
+	"file_id":        true,
+	"file_unique_id": true,
+	"width":          true,
+	"height":         true,
+	"file_size":      true,
+}
+
+// photoSize reports whether obj is a PhotoSize, and describes it.
+func photoSize(obj map[string]interface{}) (string, Media, bool) {
+	for key := range obj {
+		if !photoSizeFields[key] {
+			return "", Media{}, false
+		}
+	}
+	fileID, _ := obj["file_id"].(string)
+	width, wok := number(obj["width"])
+	height, hok := number(obj["height"])
+	if fileID == "" || !wok || !hok {
+		return "", Media{}, false
+	}
+	size, _ := number(obj["file_size"])
+	return fileID, Media{Kind: "photo", Width: int(width), Height: int(height), Size: size}, true
+}
+
+// mediaKinds maps the fields of messages that hold files to their kind.
+var mediaKinds = map[string]string{
+	"document":   "document",
+	"voice":      "voice",
+	"audio":      "audio",
+	"video":      "video",
+	"animation":  "animation",
+	"video_note": "video_note",
+	"sticker":    "sticker",
+	"stickers":   "sticker",
+}
+
+// defaultMimeTypes are the MIME types of kinds of files that don't say
+// theirs.
+var defaultMimeTypes = map[string]string{
+	"document":   "application/octet-stream",
+	"voice":      "audio/ogg",
+	"audio":      "audio/mpeg",
+	"video":      "video/mp4",
+	"animation":  "video/mp4",
+	"video_note": "video/mp4",
+}
+
+// mediaFile reports whether obj, found under key, is a file other than a
+// photo, and describes it.
+func mediaFile(key string, obj map[string]interface{}) (string, Media, bool) {
+	fileID, _ := obj["file_id"].(string)
+	kind := mediaKinds[key]
+	if _, ok := obj["is_animated"]; ok {
+		kind = "sticker"
+	}
+	if fileID == "" || kind == "" {
+		return "", Media{}, false
+	}
+
+	m := Media{Kind: kind}
+	m.MimeType, _ = obj["mime_type"].(string)
+	m.Size, _ = number(obj["file_size"])
+	width, _ := number(obj["width"])
+	height, _ := number(obj["height"])
+	duration, _ := number(obj["duration"])
+	m.Width, m.Height, m.Duration = int(width), int(height), int(duration)
+	if length, ok := number(obj["length"]); ok {
+		// Video notes are square
+		m.Width, m.Height = int(length), int(length)
+	}
+
+	if kind == "sticker" {
+		switch {
+		case obj["is_animated"] == true:
+			m.MimeType = "application/x-tgsticker"
+		case obj["is_video"] == true:
+			m.MimeType = "video/webm"
+		default:
+			m.MimeType = "image/webp"
+		}
+	}
+	if m.MimeType == "" {
+		m.MimeType = defaultMimeTypes[kind]
+	}
+	return fileID, m, true
+}
+
+// number converts a number, as generated or decoded, to int64.
+func number(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,77 @@
+// internal/storage/media_test.go
+package storage
+
+import "testing"
+
+func TestMediaRegistry_Scan(t *testing.T) {
+	r := NewMediaRegistry(0)
+	r.Scan(map[string]interface{}{
+		"message_id": 1,
+		"photo": []interface{}{
+			map[string]interface{}{"file_id": "small", "file_unique_id": "s", "width": 90, "height": 60},
+			map[string]interface{}{"file_id": "large", "file_unique_id": "l", "width": float64(800), "height": float64(533), "file_size": 1234},
+		},
+		"video": map[string]interface{}{"file_id": "video", "width": 640, "height": 480, "duration": 5},
+	})
+
+	if m, ok := r.Lookup("large"); !ok || m.Kind != "photo" || m.Width != 800 || m.Height != 533 {
+		t.Errorf("got %+v, %v for the large photo", m, ok)
+	}
+	if _, ok := r.Lookup("small"); !ok {
+		t.Error("expected every photo size to be remembered")
+	}
+	if m, ok := r.Lookup("video"); !ok || m.Kind != "video" || m.MimeType != "video/mp4" || m.Duration != 5 {
+		t.Errorf("got %+v, %v for the video", m, ok)
+	}
+
+	r.Clear()
+	if _, ok := r.Lookup("large"); ok {
+		t.Error("expected Clear to forget the photos")
+	}
+}
+
+func TestMediaRegistry_ScanMedia(t *testing.T) {
+	r := NewMediaRegistry(0)
+	r.Scan([]interface{}{
+		map[string]interface{}{"voice": map[string]interface{}{"file_id": "voice", "duration": 3, "file_size": 4096}},
+		map[string]interface{}{"document": map[string]interface{}{"file_id": "doc", "mime_type": "application/pdf"}},
+		map[string]interface{}{"video_note": map[string]interface{}{"file_id": "note", "length": 240}},
+		map[string]interface{}{"stickers": []interface{}{
+			map[string]interface{}{"file_id": "tgs", "is_animated": true, "is_video": false},
+			map[string]interface{}{"file_id": "webm", "is_animated": false, "is_video": true},
+			map[string]interface{}{"file_id": "webp", "is_animated": false, "is_video": false},
+		}},
+		map[string]interface{}{"photo": map[string]interface{}{"small_file_id": "small", "big_file_id": "big"}},
+	})
+
+	for id, want := range map[string]Media{
+		"voice": {Kind: "voice", MimeType: "audio/ogg", Size: 4096, Duration: 3},
+		"doc":   {Kind: "document", MimeType: "application/pdf"},
+		"note":  {Kind: "video_note", MimeType: "video/mp4", Width: 240, Height: 240},
+		"tgs":   {Kind: "sticker", MimeType: "application/x-tgsticker"},
+		"webm":  {Kind: "sticker", MimeType: "video/webm"},
+		"webp":  {Kind: "sticker", MimeType: "image/webp"},
+		"big":   {Kind: "photo", Width: 640, Height: 640},
+	} {
+		if got, _ := r.Lookup(id); got != want {
+			t.Errorf("got %+v for %s, want %+v", got, id, want)
+		}
+	}
+}
+
+func TestMediaRegistry_Limit(t *testing.T) {
+	r := NewMediaRegistry(2)
+	r.Remember("a", Media{Kind: "photo"})
+	r.Remember("b", Media{Kind: "photo"})
+	r.Remember("a", Media{Kind: "photo", Width: 10})
+	r.Remember("c", Media{Kind: "photo"})
+
+	if _, ok := r.Lookup("a"); ok {
+		t.Error("expected the oldest file to be forgotten")
+	}
+	for _, id := range []string{"b", "c"} {
+		if _, ok := r.Lookup(id); !ok {
+			t.Errorf("expected %s to be remembered", id)
+		}
+	}
+}
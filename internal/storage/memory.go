@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"sync"
 )
 
@@ -13,12 +14,15 @@ type memoryFile struct {
 	data     []byte
 	metadata FileMetadata
 	path     string
+	seq      int64 // Insertion order, used to evict the oldest file
 }
 
 // MemoryStore is an in-memory implementation of the Store interface.
 type MemoryStore struct {
 	mu    sync.RWMutex
 	files map[string]*memoryFile
+	bytes int64
+	seq   int64
 }
 
 // Ensure MemoryStore implements Store interface at compile time.
@@ -43,7 +47,7 @@ func (s *MemoryStore) Store(data []byte, filename string, mimeType string) (stri
 	dataCopy := make([]byte, len(data))
 	copy(dataCopy, data)
 
-	s.files[fileID] = &memoryFile{
+	s.put(fileID, &memoryFile{
 		data: dataCopy,
 		metadata: FileMetadata{
 			Filename: filename,
@@ -51,7 +55,7 @@ func (s *MemoryStore) Store(data []byte, filename string, mimeType string) (stri
 			Size:     int64(len(data)),
 		},
 		path: path,
-	}
+	})
 
 	return fileID, nil
 }
@@ -87,7 +91,10 @@ func (s *MemoryStore) Delete(fileID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	delete(s.files, fileID)
+	if file, ok := s.files[fileID]; ok {
+		s.bytes -= int64(len(file.data))
+		delete(s.files, fileID)
+	}
 	return nil
 }
 
@@ -97,9 +104,95 @@ func (s *MemoryStore) Clear() error {
 	defer s.mu.Unlock()
 
 	s.files = make(map[string]*memoryFile)
+	s.bytes = 0
+	return nil
+}
+
+// Size returns the number of bytes of file data held.
+func (s *MemoryStore) Size() int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bytes
+}
+
+// EvictOldest removes the file that was stored first. It returns false if
+// the store is empty.
+func (s *MemoryStore) EvictOldest() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var oldestID string
+	var oldest *memoryFile
+	for id, file := range s.files {
+		if oldest == nil || file.seq < oldest.seq {
+			oldestID, oldest = id, file
+		}
+	}
+	if oldest == nil {
+		return false
+	}
+	s.bytes -= int64(len(oldest.data))
+	delete(s.files, oldestID)
+	return true
+}
+
+// List returns every stored file including its data.
+func (s *MemoryStore) List() ([]File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]File, 0, len(s.files))
+	for id, file := range s.files {
+		result = append(result, File{
+			ID:       id,
+			Path:     file.path,
+			Metadata: file.metadata,
+			Data:     file.data,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result, nil
+}
+
+// Put stores a file under its existing ID, replacing any file with the same ID.
+func (s *MemoryStore) Put(file File) error {
+	if file.ID == "" {
+		return fmt.Errorf("file ID is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dataCopy := make([]byte, len(file.Data))
+	copy(dataCopy, file.Data)
+
+	metadata := file.Metadata
+	metadata.Size = int64(len(dataCopy))
+
+	path := file.Path
+	if path == "" {
+		path = fmt.Sprintf("documents/%s", metadata.Filename)
+	}
+
+	s.put(file.ID, &memoryFile{
+		data:     dataCopy,
+		metadata: metadata,
+		path:     path,
+	})
 	return nil
 }
 
+// put stores a file under id, replacing any existing one. s.mu must be held.
+func (s *MemoryStore) put(id string, file *memoryFile) {
+	if old, ok := s.files[id]; ok {
+		s.bytes -= int64(len(old.data))
+	}
+	s.seq++
+	file.seq = s.seq
+	s.files[id] = file
+	s.bytes += int64(len(file.data))
+}
+
 // generateFileID creates a unique file ID using crypto/rand.
 func (s *MemoryStore) generateFileID() string {
 	b := make([]byte, 16)
@@ -122,3 +122,69 @@ func TestMemoryStore_UniqueFileIDs(t *testing.T) {
 		ids[fileID] = true
 	}
 }
+
+func TestMemoryStore_ListAndPut(t *testing.T) {
+	s := NewMemoryStore()
+
+	fileID, err := s.Store([]byte("hello"), "hello.txt", "text/plain")
+	if err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	files, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(files) != 1 || files[0].ID != fileID {
+		t.Fatalf("List() = %+v, want one file with ID %s", files, fileID)
+	}
+
+	restored := NewMemoryStore()
+	if err := restored.Put(files[0]); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	data, meta, err := restored.Get(fileID)
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(data) != "hello" || meta.Filename != "hello.txt" {
+		t.Errorf("got %q (%s), want hello (hello.txt)", data, meta.Filename)
+	}
+
+	if err := restored.Put(File{}); err == nil {
+		t.Error("expected error for file without ID")
+	}
+}
+
+func TestMemoryStore_SizeAndEvict(t *testing.T) {
+	store := NewMemoryStore()
+
+	first, _ := store.Store([]byte("first"), "a.txt", "text/plain")
+	store.Store([]byte("second"), "b.txt", "text/plain")
+	if store.Size() != 11 {
+		t.Errorf("got size %d, want 11", store.Size())
+	}
+
+	// Replacing a file accounts for the old data
+	store.Put(File{ID: first, Data: []byte("1st")})
+	if store.Size() != 9 {
+		t.Errorf("got size %d after replace, want 9", store.Size())
+	}
+
+	// The oldest file is the second one, as the first was replaced after it
+	if !store.EvictOldest() {
+		t.Fatal("expected EvictOldest to succeed")
+	}
+	if _, _, err := store.Get(first); err != nil {
+		t.Errorf("expected replaced file to survive eviction, got %v", err)
+	}
+	if store.Size() != 3 {
+		t.Errorf("got size %d after evict, want 3", store.Size())
+	}
+
+	store.Delete(first)
+	if store.Size() != 0 || store.EvictOldest() {
+		t.Error("expected store to be empty")
+	}
+}
@@ -0,0 +1,100 @@
+// internal/storage/paths.go
+package storage
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultPathTTL is how long a file_path returned by getFile stays valid.
+// The Bot API guarantees download links for at least one hour.
+const DefaultPathTTL = time.Hour
+
+// ErrPathExpired is returned when a file path was issued but is no longer valid.
+var ErrPathExpired = errors.New("file path expired")
+
+// PathLease describes a file path handed out by getFile.
+type PathLease struct {
+	FileID    string
+	Size      int64
+	ExpiresAt time.Time
+}
+
+// PathRegistry tracks the file paths issued to each bot token, so that
+// downloads are scoped to the token that called getFile and stop working
+// once the path expires.
+type PathRegistry struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	leases map[string]PathLease // keyed by token + "/" + path
+	now    func() time.Time
+}
+
+// NewPathRegistry creates a registry whose paths expire after ttl, read
+// from now (time.Now if nil). A ttl of zero uses DefaultPathTTL.
+func NewPathRegistry(now func() time.Time, ttl time.Duration) *PathRegistry {
+	if now == nil {
+		now = time.Now
+	}
+	if ttl <= 0 {
+		ttl = DefaultPathTTL
+	}
+	return &PathRegistry{
+		ttl:    ttl,
+		leases: make(map[string]PathLease),
+		now:    now,
+	}
+}
+
+// TTL returns how long issued paths stay valid.
+func (r *PathRegistry) TTL() time.Duration {
+	return r.ttl
+}
+
+// Issue registers path as downloadable by token until the TTL elapses.
+// Issuing the same path again extends its lifetime. Leases that expired
+// more than a TTL ago are dropped so the registry doesn't grow without bound.
+func (r *PathRegistry) Issue(token, path, fileID string, size int64) PathLease {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for key, lease := range r.leases {
+		if now.Sub(lease.ExpiresAt) > r.ttl {
+			delete(r.leases, key)
+		}
+	}
+
+	lease := PathLease{
+		FileID:    fileID,
+		Size:      size,
+		ExpiresAt: now.Add(r.ttl),
+	}
+	r.leases[token+"/"+path] = lease
+	return lease
+}
+
+// Resolve returns the lease for a path issued to token. It returns
+// ErrNotFound if the path was never issued to the token (or expired long
+// ago) and ErrPathExpired if it was issued but has since expired.
+func (r *PathRegistry) Resolve(token, path string) (PathLease, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lease, ok := r.leases[token+"/"+path]
+	if !ok {
+		return PathLease{}, ErrNotFound
+	}
+	if !r.now().Before(lease.ExpiresAt) {
+		return PathLease{}, ErrPathExpired
+	}
+	return lease, nil
+}
+
+// Clear forgets every issued path.
+func (r *PathRegistry) Clear() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.leases = make(map[string]PathLease)
+}
@@ -0,0 +1,65 @@
+// internal/storage/paths_test.go
+package storage
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPathRegistry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewPathRegistry(func() time.Time { return now }, time.Hour)
+
+	r.Issue("123:abc", "photos/file_1.jpg", "file-1", 2048)
+
+	lease, err := r.Resolve("123:abc", "photos/file_1.jpg")
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if lease.FileID != "file-1" || lease.Size != 2048 {
+		t.Errorf("unexpected lease %+v", lease)
+	}
+
+	// Paths are scoped to the token that requested them
+	if _, err := r.Resolve("456:def", "photos/file_1.jpg"); err != ErrNotFound {
+		t.Errorf("got %v for other token, want ErrNotFound", err)
+	}
+	if _, err := r.Resolve("123:abc", "photos/unknown.jpg"); err != ErrNotFound {
+		t.Errorf("got %v for unknown path, want ErrNotFound", err)
+	}
+
+	now = now.Add(59 * time.Minute)
+	if _, err := r.Resolve("123:abc", "photos/file_1.jpg"); err != nil {
+		t.Errorf("expected path to be valid before TTL, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := r.Resolve("123:abc", "photos/file_1.jpg"); err != ErrPathExpired {
+		t.Errorf("got %v after TTL, want ErrPathExpired", err)
+	}
+
+	// Calling getFile again issues a fresh lease
+	r.Issue("123:abc", "photos/file_1.jpg", "file-1", 2048)
+	if _, err := r.Resolve("123:abc", "photos/file_1.jpg"); err != nil {
+		t.Errorf("expected reissued path to be valid, got %v", err)
+	}
+}
+
+func TestPathRegistry_DropsOldLeases(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	r := NewPathRegistry(func() time.Time { return now }, time.Minute)
+
+	r.Issue("123:abc", "documents/old.pdf", "old", 1)
+	now = now.Add(3 * time.Minute)
+	r.Issue("123:abc", "documents/new.pdf", "new", 1)
+
+	if _, err := r.Resolve("123:abc", "documents/old.pdf"); err != ErrNotFound {
+		t.Errorf("got %v for long-expired path, want ErrNotFound", err)
+	}
+}
+
+func TestPathRegistry_DefaultTTL(t *testing.T) {
+	if ttl := NewPathRegistry(nil, 0).TTL(); ttl != DefaultPathTTL {
+		t.Errorf("TTL = %v, want %v", ttl, DefaultPathTTL)
+	}
+}
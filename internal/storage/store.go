@@ -8,9 +8,17 @@ var ErrNotFound = errors.New("file not found")
 
 // FileMetadata contains metadata about a stored file.
 type FileMetadata struct {
-	Filename string
-	MimeType string
-	Size     int64
+	Filename string `json:"filename"`
+	MimeType string `json:"mime_type"`
+	Size     int64  `json:"size"`
+}
+
+// File is a complete stored file, used when exporting and importing store contents.
+type File struct {
+	ID       string       `json:"file_id"`
+	Path     string       `json:"file_path"`
+	Metadata FileMetadata `json:"metadata"`
+	Data     []byte       `json:"data"`
 }
 
 // Store defines the interface for file storage operations.
@@ -29,4 +37,17 @@ type Store interface {
 
 	// Clear removes all files from the store.
 	Clear() error
+
+	// List returns every stored file including its data.
+	List() ([]File, error)
+
+	// Put stores a file under its existing ID, replacing any file with the same ID.
+	Put(file File) error
+
+	// Size returns the number of bytes of file data held.
+	Size() int64
+
+	// EvictOldest removes the file that was stored first, returning false if
+	// the store is empty.
+	EvictOldest() bool
 }
@@ -0,0 +1,193 @@
+// internal/storage/synth.go
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"path"
+	"strings"
+)
+
+// synthesizer makes up a file of a MIME type. Files are padded to m.Size
+// where the format allows, and are as small as they can be otherwise.
+type synthesizer func(m Media, rng *rand.Rand) ([]byte, error)
+
+// synthesizers are the MIME types Synthesize can make up files of.
+var synthesizers = map[string]synthesizer{
+	"image/jpeg":              synthesizeJPEG,
+	"image/png":               synthesizePNG,
+	"image/gif":               synthesizeGIF,
+	"image/webp":              synthesizeWebP,
+	"application/x-tgsticker": synthesizeTGS,
+	"audio/ogg":               synthesizeOgg,
+	"audio/mpeg":              synthesizeMP3,
+	"video/mp4":               synthesizeMP4,
+	"video/webm":              synthesizeWebM,
+	"application/pdf":         synthesizePDF,
+	"application/json":        synthesizeJSON,
+	"text/plain":              synthesizeText,
+}
+
+// Synthesize makes up the content of a generated file, which is the same
+// for the same file_id: a minimal valid file of its MIME type, padded to
+// its size where the format allows. Photos are images of their dimensions,
+// as PNG if the path ends in .png and as JPEG otherwise. It returns the
+// content and its MIME type, or an error for MIME types it can't make up.
+func Synthesize(fileID string, m Media, filePath string) ([]byte, string, error) {
+	h := fnv.New64a()
+	h.Write([]byte(fileID))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	mimeType := m.MimeType
+	if m.Kind == "photo" {
+		mimeType = "image/jpeg"
+		if strings.EqualFold(path.Ext(filePath), ".png") {
+			mimeType = "image/png"
+		}
+	}
+	synthesize, ok := synthesizers[mimeType]
+	if !ok {
+		return nil, "", fmt.Errorf("can't synthesize %s files", mimeType)
+	}
+	data, err := synthesize(m, rng)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mimeType, nil
+}
+
+// mediaFolders are the folders of Telegram's file paths, by kind of file.
+var mediaFolders = map[string]string{
+	"photo":      "photos",
+	"document":   "documents",
+	"voice":      "voice",
+	"audio":      "music",
+	"video":      "videos",
+	"animation":  "animations",
+	"video_note": "video_notes",
+	"sticker":    "stickers",
+}
+
+// mimeExtensions are the file extensions of MIME types in file paths.
+var mimeExtensions = map[string]string{
+	"image/jpeg":              ".jpg",
+	"image/png":               ".png",
+	"image/gif":               ".gif",
+	"image/webp":              ".webp",
+	"application/x-tgsticker": ".tgs",
+	"audio/ogg":               ".oga",
+	"audio/mpeg":              ".mp3",
+	"video/mp4":               ".mp4",
+	"video/webm":              ".webm",
+	"application/pdf":         ".pdf",
+	"application/json":        ".json",
+	"text/plain":              ".txt",
+}
+
+// MediaPath returns the file_path Telegram would give a file: the folder
+// of its kind and the extension of its MIME type, keeping the name of a
+// generated path.
+func MediaPath(filePath string, m Media) string {
+	ext := path.Ext(filePath)
+	name := strings.TrimSuffix(path.Base(filePath), ext)
+	if name == "" || name == "." || name == "/" {
+		name = "file_0"
+	}
+	if m.Kind == "photo" {
+		ext = ".jpg"
+	} else if e, ok := mimeExtensions[m.MimeType]; ok {
+		ext = e
+	}
+	folder, ok := mediaFolders[m.Kind]
+	if !ok {
+		folder = "documents"
+	}
+	return folder + "/" + name + ext
+}
+
+// split divides n bytes into parts of min to max bytes each, as even as
+// they can be. It reports false if n can't be divided so.
+func split(n, min, max int) ([]int, bool) {
+	if n == 0 {
+		return nil, true
+	}
+	if n < min {
+		return nil, false
+	}
+	count := (n + max - 1) / max
+	parts := make([]int, count)
+	for i := range parts {
+		parts[i] = n / count
+		if i < n%count {
+			parts[i]++
+		}
+		if parts[i] < min {
+			return nil, false
+		}
+	}
+	return parts, true
+}
+
+// textLine is what text files are made of.
+const textLine = "This file was generated by tg-mock.\n"
+
+// synthesizeText writes text lines, cut off at the file's size.
+func synthesizeText(m Media, _ *rand.Rand) ([]byte, error) {
+	if m.Size <= 0 {
+		return []byte(textLine), nil
+	}
+	data := bytes.Repeat([]byte(textLine), int(m.Size)/len(textLine)+1)
+	return data[:m.Size], nil
+}
+
+// synthesizeJSON writes a JSON object followed by whitespace.
+func synthesizeJSON(m Media, _ *rand.Rand) ([]byte, error) {
+	data := []byte(`{"generated_by":"tg-mock"}`)
+	if pad := int(m.Size) - len(data) - 1; pad >= 0 {
+		data = append(data, bytes.Repeat([]byte(" "), pad)...)
+	}
+	return append(data, '\n'), nil
+}
+
+// synthesizePDF writes a PDF document of one empty page, padded with a
+// comment before its objects.
+func synthesizePDF(m Media, _ *rand.Rand) ([]byte, error) {
+	build := func(pad int) []byte {
+		var buf bytes.Buffer
+		buf.WriteString("%PDF-1.4\n")
+		if pad >= 2 {
+			buf.WriteString("%" + strings.Repeat(" ", pad-2) + "\n")
+		}
+		objects := []string{
+			"<< /Type /Catalog /Pages 2 0 R >>",
+			"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>",
+		}
+		offsets := make([]int, len(objects))
+		for i, obj := range objects {
+			offsets[i] = buf.Len()
+			fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+		}
+		xref := buf.Len()
+		fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+		for _, offset := range offsets {
+			fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+		}
+		fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+		return buf.Bytes()
+	}
+
+	data := build(0)
+	// The padding moves the xref, whose offset may take more digits
+	pad := int(m.Size) - len(data)
+	for i := 0; i < 3 && pad >= 2; i++ {
+		padded := build(pad)
+		if len(padded) == int(m.Size) {
+			return padded, nil
+		}
+		pad -= len(padded) - int(m.Size)
+	}
+	return data, nil
+}
@@ -0,0 +1,331 @@
+// internal/storage/synth_av.go
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"math/rand"
+)
+
+// durationOf returns the duration of a file in seconds, at least one.
+func durationOf(m Media) int {
+	if m.Duration < 1 {
+		return 1
+	}
+	return m.Duration
+}
+
+// Opus voice messages are 20 ms frames of silence at 48 kHz.
+var opusSilence = []byte{0xF8, 0xFF, 0xFE}
+
+const (
+	opusFrameSamples = 960
+	opusPreSkip      = 312
+	opusVendor       = "tg-mock"
+	// oggHeaderSize is the size of a page header without its lacing values.
+	oggHeaderSize = 27
+)
+
+// oggCRC is the CRC table of Ogg pages: polynomial 0x04c11db7, unreflected.
+var oggCRC = func() (table [256]uint32) {
+	for i := range table {
+		r := uint32(i) << 24
+		for j := 0; j < 8; j++ {
+			if r&0x80000000 != 0 {
+				r = r<<1 ^ 0x04C11DB7
+			} else {
+				r <<= 1
+			}
+		}
+		table[i] = r
+	}
+	return table
+}()
+
+// oggWriter writes the pages of a logical Ogg stream.
+type oggWriter struct {
+	buf bytes.Buffer
+	seq uint32
+}
+
+// page writes a page of segments with their lacing values.
+func (w *oggWriter) page(flags byte, granule uint64, lacing, data []byte) {
+	start := w.buf.Len()
+	header := make([]byte, oggHeaderSize)
+	copy(header, "OggS")
+	header[5] = flags
+	binary.LittleEndian.PutUint64(header[6:], granule)
+	binary.LittleEndian.PutUint32(header[14:], 0x74676D6B) // serial number
+	binary.LittleEndian.PutUint32(header[18:], w.seq)
+	header[26] = byte(len(lacing))
+	w.buf.Write(header)
+	w.buf.Write(lacing)
+	w.buf.Write(data)
+	w.seq++
+
+	page := w.buf.Bytes()[start:]
+	var crc uint32
+	for _, b := range page {
+		crc = crc<<8 ^ oggCRC[byte(crc>>24)^b]
+	}
+	binary.LittleEndian.PutUint32(page[22:], crc)
+}
+
+// packet writes a packet on pages of its own, flagging the first page
+// with flags and giving the last one granule.
+func (w *oggWriter) packet(flags byte, granule uint64, p []byte) {
+	lacing := oggLacing(len(p))
+	for len(lacing) > 0 {
+		n := len(lacing)
+		if n > 255 {
+			n = 255
+		}
+		size := 0
+		for _, l := range lacing[:n] {
+			size += int(l)
+		}
+		pageGranule := granule
+		if n < len(lacing) {
+			// No packet ends on the page
+			pageGranule = math.MaxUint64
+		}
+		w.page(flags, pageGranule, lacing[:n], p[:size])
+		flags = 0x01 // continued packet
+		lacing, p = lacing[n:], p[size:]
+	}
+}
+
+// oggLacing returns the lacing values of a packet of n bytes.
+func oggLacing(n int) []byte {
+	lacing := make([]byte, 0, n/255+1)
+	for ; n >= 255; n -= 255 {
+		lacing = append(lacing, 255)
+	}
+	return append(lacing, byte(n))
+}
+
+// oggPacketSize returns how many bytes a packet of n bytes takes on pages
+// of its own.
+func oggPacketSize(n int) int {
+	segments := n/255 + 1
+	pages := (segments + 254) / 255
+	return n + segments + oggHeaderSize*pages
+}
+
+// oggAudioSize returns how many bytes frames of silence take, 255 to a page.
+func oggAudioSize(frames int) int {
+	return frames*(len(opusSilence)+1) + oggHeaderSize*((frames+254)/255)
+}
+
+// synthesizeOgg writes a voice message: Opus silence of the file's
+// duration in an Ogg stream, padded in the comment header. Silence is cut
+// short if the file's size can't hold it.
+func synthesizeOgg(m Media, _ *rand.Rand) ([]byte, error) {
+	head := make([]byte, 19)
+	copy(head, "OpusHead")
+	head[8] = 1 // version
+	head[9] = 1 // channels
+	binary.LittleEndian.PutUint16(head[10:], opusPreSkip)
+	binary.LittleEndian.PutUint32(head[12:], 48000)
+
+	tagsSize := 8 + 4 + len(opusVendor) + 4
+	frames := durationOf(m) * 50
+	tagsLen := tagsSize
+	if m.Size > 0 {
+		fixed := oggPacketSize(len(head)) + oggPacketSize(tagsSize)
+		for frames > 1 && fixed+oggAudioSize(frames) > int(m.Size) {
+			frames = frames * 9 / 10
+		}
+		// The largest comment header that fits, which fits exactly unless
+		// the size falls where another page or lacing value begins
+		want := int(m.Size) - oggPacketSize(len(head)) - oggAudioSize(frames)
+		lo, hi := tagsSize, want
+		for lo < hi {
+			mid := (lo + hi + 1) / 2
+			if oggPacketSize(mid) <= want {
+				lo = mid
+			} else {
+				hi = mid - 1
+			}
+		}
+		if oggPacketSize(lo) <= want {
+			tagsLen = lo
+		}
+	}
+	// Padding after the comments starts with a clear bit
+	tags := make([]byte, tagsLen)
+	copy(tags, "OpusTags")
+	binary.LittleEndian.PutUint32(tags[8:], uint32(len(opusVendor)))
+	copy(tags[12:], opusVendor)
+
+	var w oggWriter
+	w.packet(0x02, 0, head) // beginning of stream
+	w.packet(0, 0, tags)
+	for written := 0; written < frames; {
+		n := frames - written
+		if n > 255 {
+			n = 255
+		}
+		lacing := bytes.Repeat([]byte{byte(len(opusSilence))}, n)
+		data := bytes.Repeat(opusSilence, n)
+		written += n
+		var flags byte
+		if written == frames {
+			flags = 0x04 // end of stream
+		}
+		w.page(flags, uint64(opusPreSkip+written*opusFrameSamples), lacing, data)
+	}
+	return w.buf.Bytes(), nil
+}
+
+// MP3 audio is silent MPEG-1 Layer III frames of 128 kbps at 44.1 kHz.
+var mp3FrameHeader = []byte{0xFF, 0xFB, 0x90, 0x64}
+
+const (
+	mp3FrameSize    = 417
+	mp3FrameSamples = 1152
+	id3HeaderSize   = 10
+)
+
+// synthesizeMP3 writes silence of the file's duration, after an ID3 tag
+// of padding. Silence is cut short if the file's size can't hold it.
+func synthesizeMP3(m Media, _ *rand.Rand) ([]byte, error) {
+	frames := (durationOf(m)*44100 + mp3FrameSamples - 1) / mp3FrameSamples
+	pad := 0
+	if m.Size > 0 {
+		if max := (int(m.Size) - id3HeaderSize) / mp3FrameSize; frames > max {
+			frames = max
+		}
+		if frames < 1 {
+			frames = 1
+		}
+		if pad = int(m.Size) - id3HeaderSize - frames*mp3FrameSize; pad < 0 {
+			pad = 0
+		}
+	}
+
+	data := make([]byte, id3HeaderSize+pad+frames*mp3FrameSize)
+	copy(data, "ID3")
+	data[3] = 3 // version 2.3
+	for i := 0; i < 4; i++ {
+		// Sizes are sync-safe: 7 bits to a byte
+		data[9-i] = byte(pad >> (7 * i) & 0x7F)
+	}
+	for i := 0; i < frames; i++ {
+		copy(data[id3HeaderSize+pad+i*mp3FrameSize:], mp3FrameHeader)
+	}
+	return data, nil
+}
+
+// box returns an ISO base media file format box.
+func box(kind string, payload ...[]byte) []byte {
+	size := 8
+	for _, p := range payload {
+		size += len(p)
+	}
+	b := make([]byte, 8, size)
+	binary.BigEndian.PutUint32(b, uint32(size))
+	copy(b[4:], kind)
+	for _, p := range payload {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// synthesizeMP4 writes an MP4 file without tracks, of the file's duration,
+// padded with a free box.
+func synthesizeMP4(m Media, _ *rand.Rand) ([]byte, error) {
+	ftyp := box("ftyp", []byte("isom\x00\x00\x02\x00isomiso2mp41"))
+
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:], 1000) // timescale
+	binary.BigEndian.PutUint32(mvhd[16:], uint32(durationOf(m)*1000))
+	binary.BigEndian.PutUint32(mvhd[20:], 0x00010000) // rate 1.0
+	binary.BigEndian.PutUint16(mvhd[24:], 0x0100)     // volume 1.0
+	for i, v := range []uint32{0x00010000, 0, 0, 0, 0x00010000, 0, 0, 0, 0x40000000} {
+		binary.BigEndian.PutUint32(mvhd[36+4*i:], v) // unity matrix
+	}
+	binary.BigEndian.PutUint32(mvhd[96:], 1) // next track ID
+	data := append(ftyp, box("moov", box("mvhd", mvhd))...)
+
+	if n := int(m.Size) - len(data); n >= 8 {
+		data = append(data, box("free", make([]byte, n-8))...)
+	}
+	return data, nil
+}
+
+// ebml returns an EBML element with an 8-byte size, so that sizes can be
+// worked out ahead.
+func ebml(id uint32, payload ...[]byte) []byte {
+	var b []byte
+	for shift := 24; shift >= 0; shift -= 8 {
+		if byte(id>>shift) != 0 || len(b) > 0 {
+			b = append(b, byte(id>>shift))
+		}
+	}
+	size := 0
+	for _, p := range payload {
+		size += len(p)
+	}
+	b = append(b, 0x01)
+	for shift := 48; shift >= 0; shift -= 8 {
+		b = append(b, byte(size>>shift))
+	}
+	for _, p := range payload {
+		b = append(b, p...)
+	}
+	return b
+}
+
+// ebmlUint returns the big-endian bytes of an unsigned integer element.
+func ebmlUint(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	return b
+}
+
+// synthesizeWebM writes a video sticker: a WebM file with a VP9 track of
+// the file's dimensions and duration but no frames, padded with a void
+// element.
+func synthesizeWebM(m Media, _ *rand.Rand) ([]byte, error) {
+	width, height := m.Width, m.Height
+	if width <= 0 || height <= 0 {
+		width, height = 512, 512
+	}
+	duration := make([]byte, 8)
+	binary.BigEndian.PutUint64(duration, math.Float64bits(float64(durationOf(m)*1000)))
+
+	header := ebml(0x1A45DFA3,
+		ebml(0x4286, ebmlUint(1)),    // EBMLVersion
+		ebml(0x42F7, ebmlUint(1)),    // EBMLReadVersion
+		ebml(0x42F2, ebmlUint(4)),    // EBMLMaxIDLength
+		ebml(0x42F3, ebmlUint(8)),    // EBMLMaxSizeLength
+		ebml(0x4282, []byte("webm")), // DocType
+		ebml(0x4287, ebmlUint(4)),    // DocTypeVersion
+		ebml(0x4285, ebmlUint(2)),    // DocTypeReadVersion
+	)
+	info := ebml(0x1549A966,
+		ebml(0x2AD7B1, ebmlUint(1000000)), // TimestampScale
+		ebml(0x4489, duration),
+		ebml(0x4D80, []byte("tg-mock")), // MuxingApp
+		ebml(0x5741, []byte("tg-mock")), // WritingApp
+	)
+	tracks := ebml(0x1654AE6B, ebml(0xAE, // TrackEntry
+		ebml(0xD7, ebmlUint(1)),     // TrackNumber
+		ebml(0x73C5, ebmlUint(1)),   // TrackUID
+		ebml(0x83, ebmlUint(1)),     // TrackType: video
+		ebml(0x86, []byte("V_VP9")), // CodecID
+		ebml(0xE0, ebml(0xB0, ebmlUint(uint64(width))), ebml(0xBA, ebmlUint(uint64(height)))),
+	))
+
+	// A void element is an ID and a size before its padding
+	segment := [][]byte{info, tracks}
+	if n := int(m.Size) - len(header) - len(ebml(0x18538067, info, tracks)) - 9; n >= 0 {
+		segment = append(segment, ebml(0xEC, make([]byte, n)))
+	}
+	return append(header, ebml(0x18538067, segment...)...), nil
+}
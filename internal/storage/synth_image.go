@@ -0,0 +1,223 @@
+// internal/storage/synth_image.go
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"strings"
+)
+
+// maxImageSide bounds the dimensions of synthesized images, so that absurd
+// widths and heights set by scenarios don't exhaust memory.
+const maxImageSide = 5120
+
+// Documents don't say their dimensions, so their images get these.
+const (
+	defaultImageWidth  = 320
+	defaultImageHeight = 240
+)
+
+// synthesizeJPEG encodes an image of the file's dimensions, padded with
+// comment segments after the start of the image.
+func synthesizeJPEG(m Media, rng *rand.Rand) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, synthesizeImage(m, rng), &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	// A segment is a marker, a length, and up to 65533 bytes
+	parts, ok := split(int(m.Size)-len(data), 4, 65537)
+	if !ok || len(parts) == 0 {
+		return data, nil
+	}
+	padded := make([]byte, 0, m.Size)
+	padded = append(padded, data[:2]...)
+	for _, n := range parts {
+		padded = append(padded, 0xFF, 0xFE, byte((n-2)>>8), byte(n-2))
+		padded = append(padded, make([]byte, n-4)...)
+	}
+	return append(padded, data[2:]...), nil
+}
+
+// synthesizePNG encodes an image of the file's dimensions, padded with a
+// private ancillary chunk before the end of the image.
+func synthesizePNG(m Media, rng *rand.Rand) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, synthesizeImage(m, rng)); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	// A chunk is a length, a type, the data, and a CRC
+	n := int(m.Size) - len(data) - 12
+	if n < 0 {
+		return data, nil
+	}
+	chunk := make([]byte, 12+n)
+	binary.BigEndian.PutUint32(chunk, uint32(n))
+	copy(chunk[4:], "paDd")
+	binary.BigEndian.PutUint32(chunk[8+n:], crc32.ChecksumIEEE(chunk[4:8+n]))
+	end := len(data) - 12 // IEND
+	padded := make([]byte, 0, m.Size)
+	padded = append(padded, data[:end]...)
+	padded = append(padded, chunk...)
+	return append(padded, data[end:]...), nil
+}
+
+// synthesizeGIF encodes an image of the file's dimensions, padded with a
+// comment extension before the trailer.
+func synthesizeGIF(m Media, rng *rand.Rand) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, synthesizeImage(m, rng), nil); err != nil {
+		return nil, err
+	}
+	data := buf.Bytes()
+	// The extension is an introducer, a label, sub-blocks of a length and
+	// up to 255 bytes, and a terminator
+	n := int(m.Size) - len(data) - 3
+	parts, ok := split(n, 2, 256)
+	if n < 0 || !ok {
+		return data, nil
+	}
+	padded := make([]byte, 0, m.Size)
+	padded = append(padded, data[:len(data)-1]...)
+	padded = append(padded, 0x21, 0xFE)
+	for _, part := range parts {
+		padded = append(padded, byte(part-1))
+		padded = append(padded, make([]byte, part-1)...)
+	}
+	return append(padded, 0x00, 0x3B), nil
+}
+
+// webpPixel is a lossless WebP image of a single pixel.
+var webpPixel = []byte{
+	'R', 'I', 'F', 'F', 0x1A, 0x00, 0x00, 0x00, 'W', 'E', 'B', 'P',
+	'V', 'P', '8', 'L', 0x0D, 0x00, 0x00, 0x00,
+	0x2F, 0x00, 0x00, 0x00, 0x10, 0x07, 0x10, 0x11, 0x11, 0x88, 0x88, 0xFE, 0x07, 0x00,
+}
+
+// synthesizeWebP writes a WebP image of a pixel, padded with an unknown
+// chunk, which readers skip. Chunks take an even number of bytes, so odd
+// sizes are missed by one.
+func synthesizeWebP(m Media, _ *rand.Rand) ([]byte, error) {
+	n := (int(m.Size) - len(webpPixel) - 8) &^ 1
+	if n < 0 {
+		return append([]byte(nil), webpPixel...), nil
+	}
+	data := make([]byte, len(webpPixel)+8+n)
+	copy(data, webpPixel)
+	binary.LittleEndian.PutUint32(data[4:], uint32(len(data)-8))
+	copy(data[len(webpPixel):], "JUNK")
+	binary.LittleEndian.PutUint32(data[len(webpPixel)+4:], uint32(n))
+	return data, nil
+}
+
+// synthesizeTGS writes an animated sticker: an empty Lottie animation,
+// gzipped and padded with trailing whitespace.
+func synthesizeTGS(m Media, _ *rand.Rand) ([]byte, error) {
+	width, height := m.Width, m.Height
+	if width <= 0 || height <= 0 {
+		width, height = 512, 512
+	}
+	animation, err := json.Marshal(map[string]interface{}{
+		"tgs": 1, "v": "5.5.2", "fr": 60, "ip": 0, "op": 180,
+		"w": width, "h": height, "nm": "tg-mock", "ddd": 0,
+		"assets": []interface{}{}, "layers": []interface{}{},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Padding is whitespace after the JSON, stored uncompressed; the gzip
+	// comment makes up the few bytes the block headers step over. Readers
+	// cap comments, so the comment can't carry the padding by itself.
+	compress := func(pad int, comment string) ([]byte, error) {
+		var buf bytes.Buffer
+		zw, _ := gzip.NewWriterLevel(&buf, gzip.NoCompression)
+		zw.Comment = comment
+		zw.Write(animation)
+		zw.Write(bytes.Repeat([]byte{' '}, pad))
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	data, err := compress(0, "")
+	if err != nil || int64(len(data)) >= m.Size {
+		return data, err
+	}
+	lo, hi := 0, int(m.Size)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if data, err = compress(mid, ""); err != nil {
+			return nil, err
+		}
+		if int64(len(data)) <= m.Size {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	if data, err = compress(lo, ""); err != nil {
+		return nil, err
+	}
+	// The comment ends with a NUL byte
+	if n := int(m.Size) - len(data) - 1; n >= 0 {
+		return compress(lo, strings.Repeat(" ", n))
+	}
+	return data, nil
+}
+
+// synthesizeImage draws a diagonal gradient between two random colors, so
+// that images differ and compress like photos rather than flat fills.
+func synthesizeImage(m Media, rng *rand.Rand) image.Image {
+	width, height := m.Width, m.Height
+	if width <= 0 || height <= 0 {
+		width, height = defaultImageWidth, defaultImageHeight
+	}
+	width = clampSide(width)
+	height = clampSide(height)
+	from := color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+	to := color.RGBA{uint8(rng.Intn(256)), uint8(rng.Intn(256)), uint8(rng.Intn(256)), 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	span := width + height - 2
+	if span == 0 {
+		span = 1
+	}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			t := (x + y) * 255 / span
+			img.SetRGBA(x, y, color.RGBA{
+				R: mix(from.R, to.R, t),
+				G: mix(from.G, to.G, t),
+				B: mix(from.B, to.B, t),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
+
+// clampSide keeps an image dimension between 1 and maxImageSide.
+func clampSide(n int) int {
+	if n < 1 {
+		return 1
+	}
+	if n > maxImageSide {
+		return maxImageSide
+	}
+	return n
+}
+
+// mix blends a into b by t out of 255.
+func mix(a, b uint8, t int) uint8 {
+	return uint8((int(a)*(255-t) + int(b)*t) / 255)
+}
@@ -0,0 +1,214 @@
+// internal/storage/synth_test.go
+package storage
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestSynthesize_Photo(t *testing.T) {
+	m := Media{Kind: "photo", Width: 320, Height: 240}
+
+	for _, tt := range []struct {
+		path, mimeType, format string
+	}{
+		{"photos/file_1.jpg", "image/jpeg", "jpeg"},
+		{"photos/file_1.png", "image/png", "png"},
+	} {
+		data, mimeType, err := Synthesize("AgACAgIAAx", m, tt.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if mimeType != tt.mimeType {
+			t.Errorf("%s: got MIME type %s, want %s", tt.path, mimeType, tt.mimeType)
+		}
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: not an image: %v", tt.path, err)
+		}
+		if format != tt.format || cfg.Width != 320 || cfg.Height != 240 {
+			t.Errorf("%s: got a %dx%d %s", tt.path, cfg.Width, cfg.Height, format)
+		}
+	}
+
+	a, _, _ := Synthesize("AgACAgIAAx", m, "photos/file_1.jpg")
+	b, _, _ := Synthesize("AgACAgIAAx", m, "photos/file_1.jpg")
+	c, _, _ := Synthesize("AgACAgIAAy", m, "photos/file_1.jpg")
+	if !bytes.Equal(a, b) {
+		t.Error("expected the same file_id to give the same image")
+	}
+	if bytes.Equal(a, c) {
+		t.Error("expected other file_ids to give other images")
+	}
+}
+
+func TestSynthesize_Sizes(t *testing.T) {
+	for mimeType := range synthesizers {
+		for _, size := range []int64{0, 1, 50000, 50001, 70000, 200000} {
+			m := Media{Kind: "document", MimeType: mimeType, Size: size, Duration: 2}
+			if mimeType == "audio/ogg" {
+				m.Kind = "voice"
+			}
+			data, got, err := Synthesize("BQACAgIAAx", m, "documents/file_1")
+			if err != nil {
+				t.Fatalf("%s of %d bytes: %v", mimeType, size, err)
+			}
+			if got != mimeType {
+				t.Errorf("%s: got MIME type %s", mimeType, got)
+			}
+			// Chunks of WebP files have even sizes
+			exact := size >= 50000 && !(mimeType == "image/webp" && size%2 == 1)
+			if exact && int64(len(data)) != size {
+				t.Errorf("%s: got %d bytes, want %d", mimeType, len(data), size)
+			}
+			if err := checkFormat(mimeType, data); err != "" {
+				t.Errorf("%s of %d bytes: %s", mimeType, size, err)
+			}
+		}
+	}
+}
+
+// checkFormat returns what is wrong with data as a file of mimeType.
+func checkFormat(mimeType string, data []byte) string {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif":
+		if _, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+			return "not an image: " + err.Error()
+		}
+	case "image/webp":
+		if string(data[:4]) != "RIFF" || string(data[8:16]) != "WEBPVP8L" || int(binary.LittleEndian.Uint32(data[4:])) != len(data)-8 {
+			return "not a WebP file"
+		}
+	case "application/x-tgsticker":
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return "not gzip: " + err.Error()
+		}
+		var animation map[string]interface{}
+		if err := json.NewDecoder(zr).Decode(&animation); err != nil || animation["tgs"] != float64(1) {
+			return "not a Lottie animation"
+		}
+	case "audio/ogg":
+		return checkOgg(data)
+	case "audio/mpeg":
+		if string(data[:3]) != "ID3" {
+			return "no ID3 tag"
+		}
+		pad := int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+		frames := data[10+pad:]
+		if len(frames) == 0 || len(frames)%mp3FrameSize != 0 || !bytes.Equal(frames[:4], mp3FrameHeader) {
+			return "no MP3 frames after the tag"
+		}
+	case "video/mp4":
+		var kinds string
+		for rest := data; len(rest) > 0; {
+			size := int(binary.BigEndian.Uint32(rest))
+			if size < 8 || size > len(rest) {
+				return "broken box"
+			}
+			kinds += string(rest[4:8]) + " "
+			rest = rest[size:]
+		}
+		if kinds != "ftyp moov " && kinds != "ftyp moov free " {
+			return "unexpected boxes " + kinds
+		}
+	case "video/webm":
+		if !bytes.HasPrefix(data, []byte{0x1A, 0x45, 0xDF, 0xA3}) || !bytes.Contains(data, []byte("webm")) {
+			return "not a WebM file"
+		}
+		header := 4 + 8 + int(binary.BigEndian.Uint64(data[4:])&0xFFFFFFFFFFFFFF)
+		segment := data[header:]
+		if !bytes.HasPrefix(segment, []byte{0x18, 0x53, 0x80, 0x67}) || 12+int(binary.BigEndian.Uint64(segment[4:])&0xFFFFFFFFFFFFFF) != len(segment) {
+			return "broken segment"
+		}
+	case "application/pdf":
+		m := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(data)
+		if !bytes.HasPrefix(data, []byte("%PDF-1.4\n")) || m == nil {
+			return "not a PDF file"
+		}
+		offset, _ := strconv.Atoi(string(m[1]))
+		if !bytes.HasPrefix(data[offset:], []byte("xref\n")) {
+			return "startxref doesn't point at the xref table"
+		}
+	case "application/json":
+		if !json.Valid(data) {
+			return "invalid JSON"
+		}
+	}
+	return ""
+}
+
+// checkOgg returns what is wrong with data as an Ogg Opus stream.
+func checkOgg(data []byte) string {
+	var packets [][]byte
+	var packet []byte
+	var last byte
+	for rest := data; len(rest) > 0; {
+		if len(rest) < 27 || string(rest[:4]) != "OggS" {
+			return "broken page"
+		}
+		lacing := rest[27 : 27+int(rest[26])]
+		size := 27 + len(lacing)
+		for _, l := range lacing {
+			packet = append(packet, rest[size:size+int(l)]...)
+			size += int(l)
+			if l < 255 {
+				packets = append(packets, packet)
+				packet = nil
+			}
+		}
+		page := append([]byte(nil), rest[:size]...)
+		want := binary.LittleEndian.Uint32(page[22:])
+		binary.LittleEndian.PutUint32(page[22:], 0)
+		var crc uint32
+		for _, b := range page {
+			crc = crc<<8 ^ oggCRC[byte(crc>>24)^b]
+		}
+		if crc != want {
+			return "bad page CRC"
+		}
+		last = rest[5]
+		rest = rest[size:]
+	}
+	switch {
+	case len(packets) < 3:
+		return "too few packets"
+	case !bytes.HasPrefix(packets[0], []byte("OpusHead")) || !bytes.HasPrefix(packets[1], []byte("OpusTags")):
+		return "missing Opus headers"
+	case data[5] != 0x02 || last != 0x04:
+		return "stream isn't flagged"
+	}
+	return ""
+}
+
+func TestOggCRC(t *testing.T) {
+	// Ogg pages use the unreflected CRC-32 polynomial
+	if oggCRC[1] != 0x04C11DB7 {
+		t.Errorf("unexpected CRC table entry %#x", oggCRC[1])
+	}
+}
+
+func TestMediaPath(t *testing.T) {
+	for _, tt := range []struct {
+		m    Media
+		want string
+	}{
+		{Media{Kind: "photo"}, "photos/file_4449.jpg"},
+		{Media{Kind: "voice", MimeType: "audio/ogg"}, "voice/file_4449.oga"},
+		{Media{Kind: "sticker", MimeType: "application/x-tgsticker"}, "stickers/file_4449.tgs"},
+		{Media{Kind: "document", MimeType: "application/octet-stream"}, "documents/file_4449.webp"},
+	} {
+		if got := MediaPath("voice/file_4449.webp", tt.m); got != tt.want {
+			t.Errorf("MediaPath for %+v = %q, want %q", tt.m, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package systemd
+
+import "syscall"
+
+func setCloseOnExec(fd int) {
+	syscall.CloseOnExec(fd)
+}
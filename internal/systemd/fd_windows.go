@@ -0,0 +1,6 @@
+//go:build windows
+
+package systemd
+
+// setCloseOnExec is a no-op on Windows, which has no socket activation.
+func setCloseOnExec(fd int) {}
@@ -0,0 +1,95 @@
+//go:build !windows
+
+// internal/systemd/systemd_test.go
+package systemd
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestListeners_NotActivated(t *testing.T) {
+	t.Setenv("LISTEN_PID", "")
+	t.Setenv("LISTEN_FDS", "")
+
+	listeners, err := Listeners()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if listeners != nil {
+		t.Errorf("expected no listeners, got %d", len(listeners))
+	}
+}
+
+func TestListeners_OtherPID(t *testing.T) {
+	t.Setenv("LISTEN_PID", strconv.Itoa(os.Getpid()+1))
+	t.Setenv("LISTEN_FDS", "1")
+
+	listeners, err := Listeners()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if listeners != nil {
+		t.Error("expected listeners meant for another process to be ignored")
+	}
+	if os.Getenv("LISTEN_FDS") != "" {
+		t.Error("expected LISTEN_FDS to be unset")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	t.Run("no socket", func(t *testing.T) {
+		t.Setenv("NOTIFY_SOCKET", "")
+		sent, err := Notify("READY=1")
+		if sent || err != nil {
+			t.Errorf("Notify() = %v, %v; want false, nil", sent, err)
+		}
+	})
+
+	t.Run("delivers state", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notify.sock")
+		conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+		if err != nil {
+			t.Skipf("unixgram sockets unavailable: %v", err)
+		}
+		defer conn.Close()
+
+		t.Setenv("NOTIFY_SOCKET", path)
+		sent, err := Notify("READY=1")
+		if !sent || err != nil {
+			t.Fatalf("Notify() = %v, %v; want true, nil", sent, err)
+		}
+
+		buf := make([]byte, 64)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf[:n]) != "READY=1" {
+			t.Errorf("got %q, want READY=1", buf[:n])
+		}
+	})
+}
+
+func TestWatchdogInterval(t *testing.T) {
+	t.Setenv("WATCHDOG_PID", "")
+	t.Setenv("WATCHDOG_USEC", "")
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("interval = %v, want 0 when disabled", got)
+	}
+
+	t.Setenv("WATCHDOG_USEC", "10000000")
+	if got := WatchdogInterval(); got != 5*time.Second {
+		t.Errorf("interval = %v, want 5s", got)
+	}
+
+	t.Setenv("WATCHDOG_PID", strconv.Itoa(os.Getpid()+1))
+	if got := WatchdogInterval(); got != 0 {
+		t.Errorf("interval = %v, want 0 for another process", got)
+	}
+}
@@ -0,0 +1,108 @@
+// internal/tokens/budget.go
+package tokens
+
+// Budget makes a token start failing after a number of successful calls,
+// modeling quota exhaustion or a key being suspended mid-run.
+type Budget struct {
+	// Calls is the number of successful calls allowed before failing.
+	Calls int `json:"calls"`
+	// Used is the number of successful calls made so far.
+	Used int `json:"used"`
+
+	// The error returned once the budget is exhausted.
+	ErrorCode   int    `json:"error_code,omitempty"`
+	Description string `json:"description,omitempty"`
+	RetryAfter  int    `json:"retry_after,omitempty"`
+}
+
+// Default error returned by an exhausted budget when none is configured.
+const (
+	DefaultBudgetErrorCode   = 401
+	DefaultBudgetDescription = "Unauthorized"
+)
+
+// Exhausted reports whether every allowed call has been used.
+func (b Budget) Exhausted() bool {
+	return b.Used >= b.Calls
+}
+
+// SetBudget installs a failure budget for a token, replacing any existing one.
+// Missing error fields are filled with the defaults.
+func (r *Registry) SetBudget(token string, budget Budget) {
+	if budget.ErrorCode == 0 {
+		budget.ErrorCode = DefaultBudgetErrorCode
+	}
+	if budget.Description == "" {
+		budget.Description = DefaultBudgetDescription
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budgets[token] = budget
+}
+
+// GetBudget returns the failure budget for a token, if any.
+func (r *Registry) GetBudget(token string) (Budget, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	budget, ok := r.budgets[token]
+	return budget, ok
+}
+
+// DeleteBudget removes the failure budget for a token.
+func (r *Registry) DeleteBudget(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.budgets, token)
+}
+
+// TryCharge counts a call against the token's budget if it has calls
+// left, reporting whether the call is allowed. Checking and charging
+// happen at once, so concurrent calls can't overdraw the budget. Tokens
+// without a budget are always allowed, and get a zero Budget.
+func (r *Registry) TryCharge(token string) (Budget, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	budget, ok := r.budgets[token]
+	if !ok {
+		return Budget{}, true
+	}
+	if budget.Exhausted() {
+		return budget, false
+	}
+	budget.Used++
+	r.budgets[token] = budget
+	return budget, true
+}
+
+// RefundBudget gives back a call charged by TryCharge, for calls that
+// turned out to fail. It is a no-op for tokens without a budget.
+func (r *Registry) RefundBudget(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if budget, ok := r.budgets[token]; ok && budget.Used > 0 {
+		budget.Used--
+		r.budgets[token] = budget
+	}
+}
+
+// Budgets returns a copy of all failure budgets.
+func (r *Registry) Budgets() map[string]Budget {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]Budget, len(r.budgets))
+	for token, budget := range r.budgets {
+		result[token] = budget
+	}
+	return result
+}
+
+// RestoreBudgets replaces all failure budgets with the given set.
+func (r *Registry) RestoreBudgets(budgets map[string]Budget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.budgets = make(map[string]Budget, len(budgets))
+	for token, budget := range budgets {
+		r.budgets[token] = budget
+	}
+}
@@ -0,0 +1,97 @@
+// internal/tokens/budget_test.go
+package tokens
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestBudget(t *testing.T) {
+	r := NewRegistry()
+	r.SetBudget("123:abc", Budget{Calls: 2})
+
+	budget, ok := r.GetBudget("123:abc")
+	if !ok {
+		t.Fatal("expected budget to be set")
+	}
+	if budget.ErrorCode != DefaultBudgetErrorCode || budget.Description != DefaultBudgetDescription {
+		t.Errorf("expected default error, got %d %q", budget.ErrorCode, budget.Description)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, ok := r.TryCharge("123:abc"); !ok {
+			t.Fatalf("budget exhausted after %d calls, want 2", i)
+		}
+	}
+	if budget, ok := r.TryCharge("123:abc"); ok || !budget.Exhausted() {
+		t.Error("expected budget to be exhausted after 2 calls")
+	}
+
+	// Refunded calls can be made again
+	r.RefundBudget("123:abc")
+	if _, ok := r.TryCharge("123:abc"); !ok {
+		t.Error("expected a refunded call to be allowed")
+	}
+
+	// Tokens without a budget are always allowed and never charged
+	if _, ok := r.TryCharge("456:def"); !ok {
+		t.Error("expected a token without a budget to be allowed")
+	}
+	r.RefundBudget("456:def")
+	if _, ok := r.GetBudget("456:def"); ok {
+		t.Error("expected no budget for 456:def")
+	}
+
+	r.DeleteBudget("123:abc")
+	if _, ok := r.GetBudget("123:abc"); ok {
+		t.Error("expected budget to be deleted")
+	}
+}
+
+func TestBudgetsAndRestore(t *testing.T) {
+	r := NewRegistry()
+	r.SetBudget("123:abc", Budget{Calls: 5, ErrorCode: 429, Description: "Too Many Requests"})
+	r.TryCharge("123:abc")
+
+	saved := r.Budgets()
+	r.SetBudget("456:def", Budget{Calls: 1})
+	r.TryCharge("123:abc")
+
+	r.RestoreBudgets(saved)
+
+	if _, ok := r.GetBudget("456:def"); ok {
+		t.Error("expected budget set after Budgets to be gone")
+	}
+	budget, ok := r.GetBudget("123:abc")
+	if !ok || budget.Used != 1 || budget.ErrorCode != 429 {
+		t.Errorf("expected 123:abc budget to be restored, got %+v", budget)
+	}
+}
+
+func TestTryCharge_Concurrent(t *testing.T) {
+	r := NewRegistry()
+	r.SetBudget("123:abc", Budget{Calls: 10})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, ok := r.TryCharge("123:abc"); ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 10 {
+		t.Errorf("got %d calls allowed, want 10", allowed)
+	}
+	if budget, _ := r.GetBudget("123:abc"); budget.Used != 10 {
+		t.Errorf("got %d calls used, want 10", budget.Used)
+	}
+}
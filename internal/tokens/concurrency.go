@@ -0,0 +1,253 @@
+// internal/tokens/concurrency.go
+package tokens
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ConcurrencyMode decides what happens to requests beyond a token's
+// in-flight limit.
+type ConcurrencyMode string
+
+const (
+	// ConcurrencyQueue makes excess requests wait for a free slot.
+	ConcurrencyQueue ConcurrencyMode = "queue"
+	// ConcurrencyReject fails excess requests immediately.
+	ConcurrencyReject ConcurrencyMode = "reject"
+)
+
+// DefaultConcurrencyRetryAfter is the retry_after returned with rejected
+// requests when none is configured.
+const DefaultConcurrencyRetryAfter = 1
+
+// ErrConcurrencyLimit is returned by AcquireConcurrency when a request is
+// rejected, or gives up waiting, because the token has too many requests
+// in flight.
+var ErrConcurrencyLimit = errors.New("too many concurrent requests")
+
+// ConcurrencyLimit caps the number of requests a token may have in flight,
+// modeling the connection ceiling Telegram applies to each bot.
+type ConcurrencyLimit struct {
+	// Limit is the number of requests allowed in flight at once.
+	Limit int `json:"limit"`
+	// Mode is ConcurrencyQueue (the default) or ConcurrencyReject.
+	Mode ConcurrencyMode `json:"mode,omitempty"`
+	// QueueTimeoutMs is how long a queued request waits for a slot before
+	// being rejected. Zero waits until the client gives up.
+	QueueTimeoutMs int `json:"queue_timeout_ms,omitempty"`
+	// RetryAfter is returned with rejected requests.
+	RetryAfter int `json:"retry_after,omitempty"`
+}
+
+// ConcurrencyStatus is a concurrency limit together with its current usage.
+type ConcurrencyStatus struct {
+	ConcurrencyLimit
+	InFlight     int `json:"in_flight"`
+	Queued       int `json:"queued"`
+	PeakInFlight int `json:"peak_in_flight"`
+	// Waited counts requests that had to queue for a slot.
+	Waited int `json:"waited"`
+	// Rejected counts requests refused or timed out in the queue.
+	Rejected int `json:"rejected"`
+}
+
+// limiter tracks the in-flight requests of one token.
+type limiter struct {
+	mu      sync.Mutex
+	cfg     ConcurrencyLimit
+	active  int
+	waiters []chan struct{}
+	peak    int
+	waited  int
+	reject  int
+	// removed is set when the limit is deleted, so requests still holding
+	// or waiting for its slots drain without being limited.
+	removed bool
+}
+
+func (l *limiter) acquire(ctx context.Context) error {
+	l.mu.Lock()
+	if l.removed || l.active < l.cfg.Limit {
+		l.take()
+		l.mu.Unlock()
+		return nil
+	}
+	if l.cfg.Mode == ConcurrencyReject {
+		l.reject++
+		l.mu.Unlock()
+		return ErrConcurrencyLimit
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.waited++
+	timeout := time.Duration(l.cfg.QueueTimeoutMs) * time.Millisecond
+	l.mu.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	select {
+	case <-ready:
+		return nil
+	case <-expired:
+	case <-ctx.Done():
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i, w := range l.waiters {
+		if w == ready {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			l.reject++
+			return ErrConcurrencyLimit
+		}
+	}
+	// A slot was handed over while giving up; keep it
+	return nil
+}
+
+// take claims a slot. l.mu must be held.
+func (l *limiter) take() {
+	l.active++
+	if l.active > l.peak {
+		l.peak = l.active
+	}
+}
+
+func (l *limiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.admit()
+}
+
+// admit hands free slots to queued requests in arrival order. l.mu must
+// be held.
+func (l *limiter) admit() {
+	for len(l.waiters) > 0 && (l.removed || l.active < l.cfg.Limit) {
+		ready := l.waiters[0]
+		l.waiters = l.waiters[1:]
+		l.take()
+		close(ready)
+	}
+}
+
+func (l *limiter) status() ConcurrencyStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return ConcurrencyStatus{
+		ConcurrencyLimit: l.cfg,
+		InFlight:         l.active,
+		Queued:           len(l.waiters),
+		PeakInFlight:     l.peak,
+		Waited:           l.waited,
+		Rejected:         l.reject,
+	}
+}
+
+// SetConcurrency installs an in-flight limit for a token, replacing any
+// existing one. Requests already in flight keep their slots, and queued
+// requests are admitted if the new limit leaves room. Missing fields are
+// filled with the defaults.
+func (r *Registry) SetConcurrency(token string, limit ConcurrencyLimit) {
+	if limit.Mode == "" {
+		limit.Mode = ConcurrencyQueue
+	}
+	if limit.RetryAfter == 0 {
+		limit.RetryAfter = DefaultConcurrencyRetryAfter
+	}
+
+	r.mu.Lock()
+	l, ok := r.limiters[token]
+	if !ok {
+		l = &limiter{}
+		r.limiters[token] = l
+	}
+	r.mu.Unlock()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = limit
+	l.admit()
+}
+
+// GetConcurrency returns the in-flight limit and usage for a token, if any.
+func (r *Registry) GetConcurrency(token string) (ConcurrencyStatus, bool) {
+	r.mu.RLock()
+	l, ok := r.limiters[token]
+	r.mu.RUnlock()
+	if !ok {
+		return ConcurrencyStatus{}, false
+	}
+	return l.status(), true
+}
+
+// DeleteConcurrency removes the in-flight limit for a token. Queued
+// requests are admitted immediately.
+func (r *Registry) DeleteConcurrency(token string) {
+	r.mu.Lock()
+	l, ok := r.limiters[token]
+	delete(r.limiters, token)
+	r.mu.Unlock()
+	if ok {
+		l.remove()
+	}
+}
+
+func (l *limiter) remove() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.removed = true
+	l.admit()
+}
+
+// AcquireConcurrency claims an in-flight slot for a request made with
+// token, queueing or rejecting it according to the token's limit. The
+// returned release function must be called when the response has been
+// written. Tokens without a limit always succeed.
+func (r *Registry) AcquireConcurrency(ctx context.Context, token string) (release func(), err error) {
+	r.mu.RLock()
+	l, ok := r.limiters[token]
+	r.mu.RUnlock()
+	if !ok {
+		return func() {}, nil
+	}
+	if err := l.acquire(ctx); err != nil {
+		return nil, err
+	}
+	return l.release, nil
+}
+
+// ConcurrencyLimits returns a copy of all in-flight limits.
+func (r *Registry) ConcurrencyLimits() map[string]ConcurrencyLimit {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]ConcurrencyLimit, len(r.limiters))
+	for token, l := range r.limiters {
+		result[token] = l.status().ConcurrencyLimit
+	}
+	return result
+}
+
+// RestoreConcurrency replaces all in-flight limits with the given set.
+// Usage counters start from zero.
+func (r *Registry) RestoreConcurrency(limits map[string]ConcurrencyLimit) {
+	r.mu.Lock()
+	old := r.limiters
+	r.limiters = make(map[string]*limiter, len(limits))
+	for token, limit := range limits {
+		r.limiters[token] = &limiter{cfg: limit}
+	}
+	r.mu.Unlock()
+
+	for _, l := range old {
+		l.remove()
+	}
+}
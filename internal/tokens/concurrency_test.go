@@ -0,0 +1,155 @@
+// internal/tokens/concurrency_test.go
+package tokens
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyReject(t *testing.T) {
+	r := NewRegistry()
+	r.SetConcurrency("123:abc", ConcurrencyLimit{Limit: 1, Mode: ConcurrencyReject})
+
+	release, err := r.AcquireConcurrency(context.Background(), "123:abc")
+	if err != nil {
+		t.Fatalf("first request rejected: %v", err)
+	}
+	if _, err := r.AcquireConcurrency(context.Background(), "123:abc"); !errors.Is(err, ErrConcurrencyLimit) {
+		t.Fatalf("expected ErrConcurrencyLimit, got %v", err)
+	}
+	release()
+
+	release, err = r.AcquireConcurrency(context.Background(), "123:abc")
+	if err != nil {
+		t.Fatalf("request after release rejected: %v", err)
+	}
+	release()
+
+	status, _ := r.GetConcurrency("123:abc")
+	if status.InFlight != 0 || status.PeakInFlight != 1 || status.Rejected != 1 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	if status.RetryAfter != DefaultConcurrencyRetryAfter {
+		t.Errorf("expected default retry_after, got %d", status.RetryAfter)
+	}
+
+	// Tokens without a limit are never throttled
+	if _, err := r.AcquireConcurrency(context.Background(), "456:def"); err != nil {
+		t.Errorf("unlimited token rejected: %v", err)
+	}
+}
+
+func TestConcurrencyQueue(t *testing.T) {
+	r := NewRegistry()
+	r.SetConcurrency("123:abc", ConcurrencyLimit{Limit: 1})
+
+	release, _ := r.AcquireConcurrency(context.Background(), "123:abc")
+
+	acquired := make(chan func())
+	go func() {
+		next, err := r.AcquireConcurrency(context.Background(), "123:abc")
+		if err != nil {
+			t.Errorf("queued request rejected: %v", err)
+		}
+		acquired <- next
+	}()
+
+	waitFor(t, func() bool {
+		status, _ := r.GetConcurrency("123:abc")
+		return status.Queued == 1
+	})
+	select {
+	case <-acquired:
+		t.Fatal("queued request acquired a slot while the limit was reached")
+	default:
+	}
+
+	release()
+	select {
+	case next := <-acquired:
+		next()
+	case <-time.After(time.Second):
+		t.Fatal("queued request was not admitted after release")
+	}
+
+	status, _ := r.GetConcurrency("123:abc")
+	if status.InFlight != 0 || status.Waited != 1 || status.Rejected != 0 {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestConcurrencyQueueTimeout(t *testing.T) {
+	r := NewRegistry()
+	r.SetConcurrency("123:abc", ConcurrencyLimit{Limit: 1, QueueTimeoutMs: 20})
+
+	release, _ := r.AcquireConcurrency(context.Background(), "123:abc")
+	defer release()
+
+	if _, err := r.AcquireConcurrency(context.Background(), "123:abc"); !errors.Is(err, ErrConcurrencyLimit) {
+		t.Fatalf("expected queued request to time out, got %v", err)
+	}
+	status, _ := r.GetConcurrency("123:abc")
+	if status.Queued != 0 || status.Rejected != 1 {
+		t.Errorf("unexpected status %+v", status)
+	}
+}
+
+func TestConcurrencyDeleteAdmitsQueued(t *testing.T) {
+	r := NewRegistry()
+	r.SetConcurrency("123:abc", ConcurrencyLimit{Limit: 1})
+	release, _ := r.AcquireConcurrency(context.Background(), "123:abc")
+	defer release()
+
+	done := make(chan error)
+	go func() {
+		_, err := r.AcquireConcurrency(context.Background(), "123:abc")
+		done <- err
+	}()
+	waitFor(t, func() bool {
+		status, _ := r.GetConcurrency("123:abc")
+		return status.Queued == 1
+	})
+
+	r.DeleteConcurrency("123:abc")
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("queued request rejected after delete: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("queued request was not admitted after delete")
+	}
+	if _, ok := r.GetConcurrency("123:abc"); ok {
+		t.Error("expected limit to be deleted")
+	}
+}
+
+func TestConcurrencyLimitsAndRestore(t *testing.T) {
+	r := NewRegistry()
+	r.SetConcurrency("123:abc", ConcurrencyLimit{Limit: 2, Mode: ConcurrencyReject, RetryAfter: 3})
+
+	saved := r.ConcurrencyLimits()
+	r.SetConcurrency("456:def", ConcurrencyLimit{Limit: 1})
+	r.RestoreConcurrency(saved)
+
+	if _, ok := r.GetConcurrency("456:def"); ok {
+		t.Error("expected 456:def limit to be removed by restore")
+	}
+	status, ok := r.GetConcurrency("123:abc")
+	if !ok || status.Limit != 2 || status.Mode != ConcurrencyReject || status.RetryAfter != 3 {
+		t.Errorf("unexpected restored limit %+v", status)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
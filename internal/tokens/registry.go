@@ -15,13 +15,30 @@ const (
 )
 
 type TokenInfo struct {
-	Status  Status
-	BotName string
+	Status  Status      `json:"status"`
+	BotName string      `json:"bot_name,omitempty"`
+	Profile *BotProfile `json:"profile,omitempty"`
+}
+
+// BotProfile is the bot a token belongs to, as getMe returns it. Unset
+// names are derived from the token.
+type BotProfile struct {
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Username  string `json:"username,omitempty"`
+	// CanJoinGroups defaults to true, as for new bots.
+	CanJoinGroups           *bool `json:"can_join_groups,omitempty"`
+	CanReadAllGroupMessages bool  `json:"can_read_all_group_messages,omitempty"`
+	SupportsInlineQueries   bool  `json:"supports_inline_queries,omitempty"`
+	CanConnectToBusiness    bool  `json:"can_connect_to_business,omitempty"`
+	HasMainWebApp           bool  `json:"has_main_web_app,omitempty"`
 }
 
 type Registry struct {
-	mu     sync.RWMutex
-	tokens map[string]TokenInfo
+	mu       sync.RWMutex
+	tokens   map[string]TokenInfo
+	budgets  map[string]Budget
+	limiters map[string]*limiter
 }
 
 var tokenPattern = regexp.MustCompile(`^\d+:[A-Za-z0-9_-]+$`)
@@ -32,7 +49,9 @@ func ValidateFormat(token string) bool {
 
 func NewRegistry() *Registry {
 	return &Registry{
-		tokens: make(map[string]TokenInfo),
+		tokens:   make(map[string]TokenInfo),
+		budgets:  make(map[string]Budget),
+		limiters: make(map[string]*limiter),
 	}
 }
 
@@ -65,3 +84,24 @@ func (r *Registry) UpdateStatus(token string, status Status) bool {
 	}
 	return false
 }
+
+// List returns a copy of all registered tokens.
+func (r *Registry) List() map[string]TokenInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	result := make(map[string]TokenInfo, len(r.tokens))
+	for token, info := range r.tokens {
+		result[token] = info
+	}
+	return result
+}
+
+// Restore replaces all registered tokens with the given set.
+func (r *Registry) Restore(tokens map[string]TokenInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens = make(map[string]TokenInfo, len(tokens))
+	for token, info := range tokens {
+		r.tokens[token] = info
+	}
+}
@@ -100,3 +100,22 @@ func TestRegistryUpdateStatus(t *testing.T) {
 		t.Error("expected UpdateStatus to return false for unknown token")
 	}
 }
+
+func TestRegistryListAndRestore(t *testing.T) {
+	r := NewRegistry()
+	r.Register("123:abc", TokenInfo{Status: StatusActive, BotName: "TestBot"})
+
+	saved := r.List()
+	r.Register("456:def", TokenInfo{Status: StatusBanned})
+	r.Delete("123:abc")
+
+	r.Restore(saved)
+
+	if _, ok := r.Get("456:def"); ok {
+		t.Error("expected token registered after List to be gone")
+	}
+	info, ok := r.Get("123:abc")
+	if !ok || info.BotName != "TestBot" {
+		t.Errorf("expected 123:abc to be restored, got %+v", info)
+	}
+}
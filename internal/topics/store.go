@@ -0,0 +1,189 @@
+// Package topics keeps the forum topics bots create, so that editing,
+// closing, and deleting topics, and sending messages to them, work on the
+// topics that exist.
+package topics
+
+import (
+	"sort"
+	"sync"
+
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// GeneralThreadID is the thread of the General topic every forum has.
+const GeneralThreadID = 1
+
+// IconColors are the icon colors Telegram allows for topics.
+var IconColors = []int64{7322096, 16766590, 13338331, 9367192, 16749490, 16478047}
+
+// Topic is a forum topic of a chat.
+type Topic struct {
+	ChatID            string `json:"chat_id"`
+	MessageThreadID   int64  `json:"message_thread_id"`
+	Name              string `json:"name"`
+	IconColor         int64  `json:"icon_color"`
+	IconCustomEmojiID string `json:"icon_custom_emoji_id,omitempty"`
+	Closed            bool   `json:"is_closed"`
+}
+
+// ForumTopic returns the ForumTopic object of a topic.
+func (t Topic) ForumTopic() map[string]interface{} {
+	topic := map[string]interface{}{
+		"message_thread_id": t.MessageThreadID,
+		"name":              t.Name,
+		"icon_color":        t.IconColor,
+	}
+	if t.IconCustomEmojiID != "" {
+		topic["icon_custom_emoji_id"] = t.IconCustomEmojiID
+	}
+	return topic
+}
+
+// Store holds the forum topics of chats. A chat the store knows keeps
+// being known after its topics are deleted, so that sending to them fails.
+type Store struct {
+	mu    sync.Mutex
+	chats map[string]map[int64]*Topic
+}
+
+// NewStore creates an empty topic store.
+func NewStore() *Store {
+	return &Store{chats: make(map[string]map[int64]*Topic)}
+}
+
+// Create adds a topic and returns it. A topic with the same thread ID
+// moves the new topic to the next free one. Without an icon color, one of
+// the allowed colors is picked by thread.
+func (s *Store) Create(t Topic) Topic {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	topics, ok := s.chats[t.ChatID]
+	if !ok {
+		topics = make(map[int64]*Topic)
+		s.chats[t.ChatID] = topics
+	}
+	if t.MessageThreadID <= GeneralThreadID {
+		t.MessageThreadID = GeneralThreadID + 1
+	}
+	for topics[t.MessageThreadID] != nil {
+		t.MessageThreadID++
+	}
+	if t.IconColor == 0 {
+		t.IconColor = IconColors[int(t.MessageThreadID)%len(IconColors)]
+	}
+	t.Closed = false
+	topics[t.MessageThreadID] = &t
+	return t
+}
+
+// Get returns a topic of a chat.
+func (s *Store) Get(chatID string, threadID int64) (Topic, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.chats[chatID][threadID]
+	if !ok {
+		return Topic{}, false
+	}
+	return *t, true
+}
+
+// Known reports whether the store knows the topics of a chat.
+func (s *Store) Known(chatID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.chats[chatID]
+	return ok
+}
+
+// Edit changes the name and icon of a topic; nil values are kept. It
+// fails if the topic doesn't exist or nothing changes.
+func (s *Store) Edit(chatID string, threadID int64, name, iconCustomEmojiID *string) *tgerrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.chats[chatID][threadID]
+	if !ok {
+		return tgerrors.MessageThreadNotFound()
+	}
+	changed := false
+	if name != nil && *name != t.Name {
+		t.Name, changed = *name, true
+	}
+	if iconCustomEmojiID != nil && *iconCustomEmojiID != t.IconCustomEmojiID {
+		t.IconCustomEmojiID, changed = *iconCustomEmojiID, true
+	}
+	if !changed {
+		return tgerrors.TopicNotModified()
+	}
+	return nil
+}
+
+// SetClosed closes or reopens a topic. It fails if the topic doesn't
+// exist or already is.
+func (s *Store) SetClosed(chatID string, threadID int64, closed bool) *tgerrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.chats[chatID][threadID]
+	if !ok {
+		return tgerrors.MessageThreadNotFound()
+	}
+	if t.Closed == closed {
+		return tgerrors.TopicNotModified()
+	}
+	t.Closed = closed
+	return nil
+}
+
+// Delete removes a topic. It fails if the topic doesn't exist.
+func (s *Store) Delete(chatID string, threadID int64) *tgerrors.Error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.chats[chatID][threadID]; !ok {
+		return tgerrors.MessageThreadNotFound()
+	}
+	delete(s.chats[chatID], threadID)
+	return nil
+}
+
+// List returns the topics of a chat, or of every chat if chatID is empty,
+// ordered by chat and thread.
+func (s *Store) List(chatID string) []Topic {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := []Topic{}
+	for id, topics := range s.chats {
+		if chatID != "" && id != chatID {
+			continue
+		}
+		for _, t := range topics {
+			list = append(list, *t)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ChatID != list[j].ChatID {
+			return list[i].ChatID < list[j].ChatID
+		}
+		return list[i].MessageThreadID < list[j].MessageThreadID
+	})
+	return list
+}
+
+// Restore replaces the store contents with the given topics.
+func (s *Store) Restore(list []Topic) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[int64]*Topic)
+	for _, t := range list {
+		t := t
+		if s.chats[t.ChatID] == nil {
+			s.chats[t.ChatID] = make(map[int64]*Topic)
+		}
+		s.chats[t.ChatID][t.MessageThreadID] = &t
+	}
+}
+
+// Clear forgets all topics.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chats = make(map[string]map[int64]*Topic)
+}
@@ -0,0 +1,44 @@
+// internal/topics/store_test.go
+package topics
+
+import "testing"
+
+func TestStore_Lifecycle(t *testing.T) {
+	s := NewStore()
+	a := s.Create(Topic{ChatID: "-100", MessageThreadID: 5, Name: "News"})
+	b := s.Create(Topic{ChatID: "-100", MessageThreadID: 5, Name: "Help", IconColor: 9367192})
+	if a.MessageThreadID != 5 || b.MessageThreadID != 6 {
+		t.Errorf("expected distinct threads, got %d and %d", a.MessageThreadID, b.MessageThreadID)
+	}
+	if a.IconColor == 0 || b.IconColor != 9367192 {
+		t.Errorf("expected an allowed color unless one is given, got %d and %d", a.IconColor, b.IconColor)
+	}
+
+	name := "Announcements"
+	if err := s.Edit("-100", 5, &name, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Edit("-100", 5, &name, nil); err == nil || err.Description != "Bad Request: TOPIC_NOT_MODIFIED" {
+		t.Errorf("expected an unchanged topic to fail, got %v", err)
+	}
+
+	if err := s.SetClosed("-100", 5, true); err != nil {
+		t.Fatal(err)
+	}
+	if topic, _ := s.Get("-100", 5); !topic.Closed || topic.Name != name {
+		t.Errorf("expected a closed, renamed topic, got %+v", topic)
+	}
+	if err := s.SetClosed("-100", 5, true); err == nil {
+		t.Error("expected closing a closed topic to fail")
+	}
+
+	if err := s.Delete("-100", 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("-100", 5); err == nil || err.Description != "Bad Request: message thread not found" {
+		t.Errorf("expected a deleted topic to be gone, got %v", err)
+	}
+	if !s.Known("-100") || s.Known("-200") {
+		t.Error("expected only chats with topics to be known")
+	}
+}
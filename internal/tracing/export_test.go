@@ -0,0 +1,80 @@
+// internal/tracing/export_test.go
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExporter(t *testing.T) {
+	received := make(chan otlpRequest, 1)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		var req otlpRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		received <- req
+	}))
+	defer collector.Close()
+
+	exp := NewExporter(collector.URL, "")
+	tracer := NewTracer(exp)
+
+	_, span := tracer.Start(context.Background(), "sendMessage", KindServer)
+	span.SetAttribute("telegram.method", "sendMessage")
+	span.SetAttribute("http.response.status_code", 400)
+	span.SetError("Bad Request: chat_id is required")
+	span.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := tracer.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	var req otlpRequest
+	select {
+	case req = <-received:
+	case <-time.After(time.Second):
+		t.Fatal("no spans exported")
+	}
+
+	rs := req.ResourceSpans[0]
+	if rs.Resource.Attributes[0].Value["stringValue"] != "tg-mock" {
+		t.Errorf("unexpected resource %+v", rs.Resource)
+	}
+	spans := rs.ScopeSpans[0].Spans
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	s := spans[0]
+	if s.Name != "sendMessage" || s.Kind != KindServer {
+		t.Errorf("unexpected span %+v", s)
+	}
+	if s.Status == nil || s.Status.Code != 2 {
+		t.Errorf("expected error status, got %+v", s.Status)
+	}
+	if len(s.Attributes) != 2 || s.Attributes[0].Key != "http.response.status_code" || s.Attributes[0].Value["intValue"] != "400" {
+		t.Errorf("unexpected attributes %+v", s.Attributes)
+	}
+}
+
+func TestNewExporterURL(t *testing.T) {
+	tests := map[string]string{
+		"http://localhost:4318":             "http://localhost:4318/v1/traces",
+		"http://localhost:4318/":            "http://localhost:4318/v1/traces",
+		"http://collector/custom/v1/traces": "http://collector/custom/v1/traces",
+	}
+	for endpoint, want := range tests {
+		exp := NewExporter(endpoint, "")
+		if got := exp.URL(); got != want {
+			t.Errorf("NewExporter(%q).URL() = %q, want %q", endpoint, got, want)
+		}
+		exp.Shutdown(context.Background())
+	}
+}
@@ -0,0 +1,102 @@
+// internal/tracing/tracing_test.go
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestParseTraceparent(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		valid   bool
+		sampled bool
+	}{
+		{"sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true, true},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true, false},
+		{"future version with extra fields", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true, true},
+		{"empty", "", false, false},
+		{"invalid version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false, false},
+		{"extra fields in version 00", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false, false},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false, false},
+		{"zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false, false},
+		{"short trace id", "00-4bf92f3577b34da6-00f067aa0ba902b7-01", false, false},
+		{"not hex", "00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc, ok := ParseTraceparent(tt.header)
+			if ok != tt.valid {
+				t.Fatalf("valid = %v, want %v", ok, tt.valid)
+			}
+			if ok && sc.Sampled != tt.sampled {
+				t.Errorf("sampled = %v, want %v", sc.Sampled, tt.sampled)
+			}
+		})
+	}
+}
+
+func TestTraceparentRoundTrip(t *testing.T) {
+	header := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, ok := ParseTraceparent(header)
+	if !ok {
+		t.Fatal("failed to parse traceparent")
+	}
+	if got := sc.Traceparent(); got != header {
+		t.Errorf("Traceparent() = %q, want %q", got, header)
+	}
+}
+
+func TestStartJoinsRemoteTrace(t *testing.T) {
+	h := http.Header{}
+	h.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := Extract(context.Background(), h)
+
+	var tracer *Tracer
+	ctx, span := tracer.Start(ctx, "sendMessage", KindServer)
+	defer span.End()
+
+	sc := span.SpanContext()
+	if got := sc.Traceparent()[3:35]; got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace ID = %s, want remote trace ID", got)
+	}
+	if span.parent != (SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7}) {
+		t.Errorf("parent = %x, want remote span ID", span.parent)
+	}
+
+	// Child spans and injected headers continue the same trace
+	_, child := tracer.Start(ctx, "scenario.match", KindInternal)
+	if child.sc.TraceID != sc.TraceID || child.parent != sc.SpanID {
+		t.Error("expected child span to be parented to the server span")
+	}
+
+	out := http.Header{}
+	Inject(ctx, out)
+	if out.Get(TraceparentHeader) != sc.Traceparent() {
+		t.Errorf("injected %q, want %q", out.Get(TraceparentHeader), sc.Traceparent())
+	}
+}
+
+func TestStartNewTrace(t *testing.T) {
+	_, span := NewTracer(nil).Start(context.Background(), "getMe", KindServer)
+	sc := span.SpanContext()
+	if !sc.IsValid() || !sc.Sampled {
+		t.Errorf("expected a new sampled trace, got %+v", sc)
+	}
+	if span.parent != (SpanID{}) {
+		t.Error("expected root span to have no parent")
+	}
+}
+
+func TestNilSpan(t *testing.T) {
+	var span *Span
+	span.SetAttribute("key", "value")
+	span.SetError("boom")
+	span.End()
+	if span.SpanContext().IsValid() {
+		t.Error("expected nil span to have an invalid span context")
+	}
+}
@@ -0,0 +1,50 @@
+// internal/updates/longpoll.go
+package updates
+
+import "sync"
+
+// LongPolls tracks the getUpdates calls in progress, so that a bot has one
+// at a time, as in Telegram: a new call terminates the one in progress for
+// the same token.
+type LongPolls struct {
+	mu     sync.Mutex
+	active map[string]chan struct{}
+}
+
+// NewLongPolls creates a tracker without calls in progress.
+func NewLongPolls() *LongPolls {
+	return &LongPolls{active: make(map[string]chan struct{})}
+}
+
+// Start registers a call of the token, terminating the one in progress, if
+// any. The returned channel is closed if the call is terminated in turn,
+// and done must be called when the call ends.
+func (p *LongPolls) Start(token string) (terminated <-chan struct{}, done func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.active[token]; ok {
+		close(old)
+	}
+	ch := make(chan struct{})
+	p.active[token] = ch
+	return ch, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.active[token] == ch {
+			delete(p.active, token)
+		}
+	}
+}
+
+// Terminate terminates the call of the token in progress, such as when a
+// webhook is set. It reports whether there was one.
+func (p *LongPolls) Terminate(token string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	ch, ok := p.active[token]
+	if ok {
+		close(ch)
+		delete(p.active, token)
+	}
+	return ok
+}
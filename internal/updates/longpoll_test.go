@@ -0,0 +1,43 @@
+// internal/updates/longpoll_test.go
+package updates
+
+import "testing"
+
+func TestLongPolls(t *testing.T) {
+	p := NewLongPolls()
+
+	first, doneFirst := p.Start("123:abc")
+	other, doneOther := p.Start("456:def")
+	second, doneSecond := p.Start("123:abc")
+
+	select {
+	case <-first:
+	default:
+		t.Error("expected a new call to terminate the one in progress")
+	}
+	select {
+	case <-other:
+		t.Error("expected calls of other tokens to go on")
+	default:
+	}
+
+	// The terminated call ending doesn't unregister the new one
+	doneFirst()
+	if !p.Terminate("123:abc") {
+		t.Fatal("expected a call in progress to terminate")
+	}
+	select {
+	case <-second:
+	default:
+		t.Error("expected Terminate to terminate the call")
+	}
+	doneSecond()
+	if p.Terminate("123:abc") {
+		t.Error("expected no call in progress after Terminate")
+	}
+
+	doneOther()
+	if p.Terminate("456:def") {
+		t.Error("expected no call in progress after it ended")
+	}
+}
@@ -2,6 +2,8 @@
 package updates
 
 import (
+	"context"
+	"encoding/json"
 	"sync"
 	"sync/atomic"
 )
@@ -10,13 +12,22 @@ import (
 type Queue struct {
 	mu        sync.RWMutex
 	updates   []map[string]interface{}
+	sizes     []int64 // Approximate memory held by each update
+	bytes     int64
 	idCounter int64
+	// changed is closed and replaced whenever updates are added, waking up
+	// any callers blocked in Wait.
+	changed chan struct{}
+
+	// OnAdd, if set, is called with every update added, in order.
+	OnAdd func(update map[string]interface{})
 }
 
 // NewQueue creates a new empty update queue.
 func NewQueue() *Queue {
 	return &Queue{
 		updates: make([]map[string]interface{}, 0),
+		changed: make(chan struct{}),
 	}
 }
 
@@ -27,11 +38,17 @@ func (q *Queue) Add(update map[string]interface{}) int64 {
 	defer q.mu.Unlock()
 
 	// Assign update_id if not present
-	if _, ok := update["update_id"]; !ok {
+	if id, ok := normalizeID(update["update_id"]); ok {
+		update["update_id"] = id
+	} else {
 		update["update_id"] = atomic.AddInt64(&q.idCounter, 1)
 	}
 
-	q.updates = append(q.updates, update)
+	q.append(update)
+	if q.OnAdd != nil {
+		q.OnAdd(update)
+	}
+	q.notify()
 	return update["update_id"].(int64)
 }
 
@@ -56,6 +73,27 @@ func (q *Queue) Get(offset int64, limit int) []map[string]interface{} {
 	return result
 }
 
+// Wait blocks until there are updates with update_id >= offset, or ctx is
+// done, and returns up to limit of them, like Get. It returns no updates
+// if ctx is done first.
+func (q *Queue) Wait(ctx context.Context, offset int64, limit int) []map[string]interface{} {
+	for {
+		q.mu.RLock()
+		changed := q.changed
+		q.mu.RUnlock()
+
+		if result := q.Get(offset, limit); len(result) > 0 {
+			return result
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return make([]map[string]interface{}, 0)
+		}
+	}
+}
+
 // Acknowledge removes updates with update_id < offset.
 // This is used to confirm that updates have been processed.
 func (q *Queue) Acknowledge(offset int64) {
@@ -64,12 +102,17 @@ func (q *Queue) Acknowledge(offset int64) {
 
 	// Remove updates with update_id < offset
 	newUpdates := make([]map[string]interface{}, 0)
-	for _, u := range q.updates {
+	newSizes := make([]int64, 0)
+	q.bytes = 0
+	for i, u := range q.updates {
 		if u["update_id"].(int64) >= offset {
 			newUpdates = append(newUpdates, u)
+			newSizes = append(newSizes, q.sizes[i])
+			q.bytes += q.sizes[i]
 		}
 	}
 	q.updates = newUpdates
+	q.sizes = newSizes
 }
 
 // Clear removes all updates from the queue.
@@ -77,6 +120,29 @@ func (q *Queue) Clear() {
 	q.mu.Lock()
 	defer q.mu.Unlock()
 	q.updates = make([]map[string]interface{}, 0)
+	q.sizes = nil
+	q.bytes = 0
+}
+
+// Size returns the approximate number of bytes held by pending updates.
+func (q *Queue) Size() int64 {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.bytes
+}
+
+// EvictOldest drops the oldest pending update without it being delivered.
+// It returns false if the queue is empty.
+func (q *Queue) EvictOldest() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.updates) == 0 {
+		return false
+	}
+	q.bytes -= q.sizes[0]
+	q.updates = q.updates[1:]
+	q.sizes = q.sizes[1:]
+	return true
 }
 
 // Pending returns the count of pending updates in the queue.
@@ -85,3 +151,70 @@ func (q *Queue) Pending() int {
 	defer q.mu.RUnlock()
 	return len(q.updates)
 }
+
+// Snapshot returns a copy of all pending updates along with the last
+// auto-assigned update_id.
+func (q *Queue) Snapshot() ([]map[string]interface{}, int64) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	result := make([]map[string]interface{}, len(q.updates))
+	copy(result, q.updates)
+	return result, atomic.LoadInt64(&q.idCounter)
+}
+
+// Restore replaces the queue contents and the update_id counter.
+// Updates without a usable update_id are assigned one after lastID.
+func (q *Queue) Restore(updates []map[string]interface{}, lastID int64) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	atomic.StoreInt64(&q.idCounter, lastID)
+	q.updates = make([]map[string]interface{}, 0, len(updates))
+	q.sizes = make([]int64, 0, len(updates))
+	q.bytes = 0
+	for _, u := range updates {
+		if id, ok := normalizeID(u["update_id"]); ok {
+			u["update_id"] = id
+		} else {
+			u["update_id"] = atomic.AddInt64(&q.idCounter, 1)
+		}
+		q.append(u)
+	}
+	q.notify()
+}
+
+// append adds an update to the end of the queue, accounting for its size.
+// q.mu must be held.
+func (q *Queue) append(update map[string]interface{}) {
+	var size int64
+	if data, err := json.Marshal(update); err == nil {
+		size = int64(len(data))
+	}
+	q.updates = append(q.updates, update)
+	q.sizes = append(q.sizes, size)
+	q.bytes += size
+}
+
+// notify wakes up the callers blocked in Wait. q.mu must be held.
+func (q *Queue) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// normalizeID converts a decoded update_id to int64.
+// JSON decoding yields float64 or json.Number rather than int64.
+func normalizeID(v interface{}) (int64, bool) {
+	switch id := v.(type) {
+	case int64:
+		return id, true
+	case int:
+		return int64(id), true
+	case float64:
+		return int64(id), true
+	case json.Number:
+		n, err := id.Int64()
+		return n, err == nil
+	}
+	return 0, false
+}
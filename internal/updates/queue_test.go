@@ -2,8 +2,10 @@
 package updates
 
 import (
+	"context"
 	"sync"
 	"testing"
+	"time"
 )
 
 func TestQueue_AddAndGet(t *testing.T) {
@@ -237,3 +239,85 @@ func TestQueue_ThreadSafety(t *testing.T) {
 		t.Errorf("pending after concurrent adds = %d, want 100", q.Pending())
 	}
 }
+
+func TestQueue_SnapshotRestore(t *testing.T) {
+	q := NewQueue()
+	q.Add(map[string]interface{}{"message": map[string]interface{}{"text": "hello"}})
+	q.Add(map[string]interface{}{"message": map[string]interface{}{"text": "world"}})
+
+	pending, lastID := q.Snapshot()
+	if len(pending) != 2 || lastID != 2 {
+		t.Fatalf("Snapshot() = %d updates, lastID %d; want 2, 2", len(pending), lastID)
+	}
+
+	q.Clear()
+
+	// Simulate a JSON round-trip where update_id is decoded as float64
+	q.Restore([]map[string]interface{}{
+		{"update_id": float64(2), "message": map[string]interface{}{"text": "world"}},
+	}, lastID)
+
+	updates := q.Get(0, 100)
+	if len(updates) != 1 {
+		t.Fatalf("got %d updates after restore, want 1", len(updates))
+	}
+	if updates[0]["update_id"] != int64(2) {
+		t.Errorf("update_id = %#v, want int64(2)", updates[0]["update_id"])
+	}
+
+	if id := q.Add(map[string]interface{}{}); id != 3 {
+		t.Errorf("next update_id = %d, want 3", id)
+	}
+}
+
+func TestQueue_SizeAndEvict(t *testing.T) {
+	q := NewQueue()
+	if q.Size() != 0 {
+		t.Errorf("expected empty queue to have size 0, got %d", q.Size())
+	}
+
+	q.Add(map[string]interface{}{"message": map[string]interface{}{"text": "hello"}})
+	first := q.Size()
+	if first <= 0 {
+		t.Fatalf("expected positive size, got %d", first)
+	}
+	q.Add(map[string]interface{}{"message": map[string]interface{}{"text": "world"}})
+	q.Add(map[string]interface{}{"message": map[string]interface{}{"text": "again"}})
+
+	if !q.EvictOldest() {
+		t.Fatal("expected EvictOldest to succeed")
+	}
+	updates := q.Get(0, 100)
+	if len(updates) != 2 || updates[0]["update_id"] != int64(2) {
+		t.Errorf("expected oldest update to be evicted, got %v", updates)
+	}
+
+	q.Acknowledge(3)
+	if q.Size() != first {
+		t.Errorf("got size %d after acknowledge, want %d", q.Size(), first)
+	}
+
+	q.Clear()
+	if q.Size() != 0 || q.EvictOldest() {
+		t.Error("expected cleared queue to be empty")
+	}
+}
+
+func TestQueue_Wait(t *testing.T) {
+	q := NewQueue()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if got := q.Wait(ctx, 0, 100); len(got) != 0 {
+		t.Errorf("got %d updates from an empty queue, want 0", len(got))
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		q.Add(map[string]interface{}{"message": "late"})
+	}()
+	got := q.Wait(context.Background(), 0, 100)
+	if len(got) != 1 || got[0]["message"] != "late" {
+		t.Errorf("got %v, want the update added while waiting", got)
+	}
+}
@@ -0,0 +1,142 @@
+// Package users keeps the users seeded for tests, so that every response
+// mentioning one of them shows the profile that was seeded.
+package users
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/watzon/tg-mock/internal/jsoncopy"
+)
+
+// Store holds the seeded users, keyed by ID. Values are copied on the way
+// in and out.
+type Store struct {
+	mu    sync.RWMutex
+	users map[int64]map[string]interface{}
+}
+
+// NewStore creates an empty user store.
+func NewStore() *Store {
+	return &Store{users: make(map[int64]map[string]interface{})}
+}
+
+// Seed adds a user with the given User fields, replacing any user with the
+// same ID, and returns the user. The user needs an integer id and a
+// first_name; is_bot defaults to false.
+func (s *Store) Seed(user map[string]interface{}) (map[string]interface{}, error) {
+	fields := jsoncopy.Map(user)
+	id, ok := ID(fields["id"])
+	if !ok || id == 0 {
+		return nil, fmt.Errorf("user needs an integer id")
+	}
+	if name, _ := fields["first_name"].(string); name == "" {
+		return nil, fmt.Errorf("user needs a first_name")
+	}
+	if _, ok := fields["is_bot"]; !ok {
+		fields["is_bot"] = false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[id] = fields
+	return jsoncopy.Map(fields), nil
+}
+
+// Get returns a seeded user.
+func (s *Store) Get(id int64) (map[string]interface{}, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok {
+		return nil, false
+	}
+	return jsoncopy.Map(user), true
+}
+
+// Apply replaces a generated User object with the seeded user of the same
+// ID, if there is one, and reports whether it did.
+func (s *Store) Apply(user map[string]interface{}) bool {
+	id, ok := ID(user["id"])
+	if !ok {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	seeded, ok := s.users[id]
+	if !ok {
+		return false
+	}
+	for name := range user {
+		delete(user, name)
+	}
+	for name, v := range jsoncopy.Map(seeded) {
+		user[name] = v
+	}
+	return true
+}
+
+// Delete forgets a user. It returns false if the user wasn't seeded.
+func (s *Store) Delete(id int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[id]; !ok {
+		return false
+	}
+	delete(s.users, id)
+	return true
+}
+
+// List returns the seeded users, ordered by ID.
+func (s *Store) List() []map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ids := make([]int64, 0, len(s.users))
+	for id := range s.users {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	list := make([]map[string]interface{}, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, jsoncopy.Map(s.users[id]))
+	}
+	return list
+}
+
+// Restore replaces the store contents with the given users. Users without
+// an ID are skipped.
+func (s *Store) Restore(users []map[string]interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = make(map[int64]map[string]interface{}, len(users))
+	for _, user := range users {
+		if id, ok := ID(user["id"]); ok {
+			s.users[id] = jsoncopy.Map(user)
+		}
+	}
+}
+
+// Clear forgets all users.
+func (s *Store) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users = make(map[int64]map[string]interface{})
+}
+
+// ID converts a user ID from decoded JSON, YAML, or a path to int64.
+func ID(v interface{}) (int64, bool) {
+	switch id := v.(type) {
+	case float64:
+		return int64(id), id == float64(int64(id))
+	case int:
+		return int64(id), true
+	case int64:
+		return id, true
+	case string:
+		n, err := strconv.ParseInt(id, 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
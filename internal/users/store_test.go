@@ -0,0 +1,49 @@
+// internal/users/store_test.go
+package users
+
+import (
+	"testing"
+)
+
+func TestStore_SeedAndApply(t *testing.T) {
+	s := NewStore()
+	if _, err := s.Seed(map[string]interface{}{"id": 1001, "first_name": "Ann", "username": "ann"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, bad := range []map[string]interface{}{{"first_name": "No ID"}, {"id": 1002}, {"id": 1.5, "first_name": "X"}} {
+		if _, err := s.Seed(bad); err == nil {
+			t.Errorf("expected %v to be rejected", bad)
+		}
+	}
+
+	user := map[string]interface{}{"id": float64(1001), "is_bot": false, "first_name": "Random", "last_name": "Name"}
+	if !s.Apply(user) || user["first_name"] != "Ann" || user["username"] != "ann" || user["last_name"] != nil {
+		t.Errorf("expected the seeded profile, got %v", user)
+	}
+	other := map[string]interface{}{"id": float64(7), "first_name": "Random"}
+	if s.Apply(other) || other["first_name"] != "Random" {
+		t.Errorf("expected users that weren't seeded to be left alone, got %v", other)
+	}
+	if seeded, _ := s.Get(1001); seeded["is_bot"] != false {
+		t.Errorf("expected is_bot to default to false, got %v", seeded)
+	}
+}
+
+func TestStore_RestoreAndClear(t *testing.T) {
+	s := NewStore()
+	s.Seed(map[string]interface{}{"id": 1001, "first_name": "Ann"})
+
+	restored := NewStore()
+	restored.Restore(s.List())
+	if _, ok := restored.Get(1001); !ok {
+		t.Error("expected the restored user")
+	}
+	if !restored.Delete(1001) || restored.Delete(1001) {
+		t.Error("expected the user to be deleted once")
+	}
+
+	s.Clear()
+	if len(s.List()) != 0 {
+		t.Error("expected no users after Clear")
+	}
+}
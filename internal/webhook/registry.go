@@ -3,6 +3,7 @@ package webhook
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/watzon/tg-mock/gen"
+	"github.com/watzon/tg-mock/internal/tracing"
 )
 
 // Config represents a registered webhook configuration for a bot token.
@@ -24,6 +26,9 @@ type Config struct {
 	LastErrorDate    *int64   `json:"last_error_date,omitempty"`
 	LastErrorMessage string   `json:"last_error_message,omitempty"`
 	CreatedAt        int64    `json:"created_at"`
+	// Session is the session that set the webhook; resetting it removes the
+	// webhook.
+	Session string `json:"session,omitempty"`
 }
 
 // DeliveryResult captures the result of a webhook delivery attempt.
@@ -60,6 +65,12 @@ type Registry struct {
 	webhooks map[string]*Config
 	client   *http.Client
 	executor MethodExecutor // Executes methods from webhook responses
+	tracer   *tracing.Tracer
+	now      func() time.Time
+
+	// OnDeliver, if set, is called with every update before it is
+	// delivered.
+	OnDeliver func(token string, update map[string]interface{})
 }
 
 // NewRegistry creates a new webhook registry.
@@ -72,15 +83,27 @@ func NewRegistry(executor MethodExecutor) *Registry {
 			Timeout: 10 * time.Second,
 		},
 		executor: executor,
+		now:      time.Now,
 	}
 }
 
+// SetTracer enables tracing of webhook deliveries.
+func (r *Registry) SetTracer(t *tracing.Tracer) {
+	r.tracer = t
+}
+
+// SetClock makes the registry date webhooks and their errors by now
+// rather than the system time.
+func (r *Registry) SetClock(now func() time.Time) {
+	r.now = now
+}
+
 // Set registers or updates a webhook configuration for a token.
 func (r *Registry) Set(token string, cfg *Config) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	if cfg.CreatedAt == 0 {
-		cfg.CreatedAt = time.Now().Unix()
+		cfg.CreatedAt = r.now().Unix()
 	}
 	r.webhooks[token] = cfg
 }
@@ -131,6 +154,30 @@ func (r *Registry) Clear() {
 	r.webhooks = make(map[string]*Config)
 }
 
+// ClearSession removes the webhooks set by the named session, leaving
+// those of other sessions.
+func (r *Registry) ClearSession(session string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for token, cfg := range r.webhooks {
+		if cfg.Session == session {
+			delete(r.webhooks, token)
+		}
+	}
+}
+
+// Restore replaces all webhooks with the given set.
+func (r *Registry) Restore(webhooks map[string]*Config) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.webhooks = make(map[string]*Config, len(webhooks))
+	for token, cfg := range webhooks {
+		if cfg != nil {
+			r.webhooks[token] = cfg
+		}
+	}
+}
+
 // GetInfo returns a WebhookInfo map for the given token,
 // suitable for getWebhookInfo response.
 func (r *Registry) GetInfo(token string, pendingCount int) map[string]interface{} {
@@ -223,6 +270,29 @@ func (r *Registry) executeMethod(method string, params map[string]interface{}) (
 // Deliver sends an update to the registered webhook for a token.
 // Returns the delivery result and any error.
 func (r *Registry) Deliver(token string, update map[string]interface{}) (*DeliveryResult, error) {
+	return r.DeliverContext(context.Background(), token, update)
+}
+
+// DeliverContext is like Deliver, but traces the delivery as a child of the
+// span in ctx and propagates the trace to the webhook via traceparent.
+func (r *Registry) DeliverContext(ctx context.Context, token string, update map[string]interface{}) (*DeliveryResult, error) {
+	if r.OnDeliver != nil {
+		r.OnDeliver(token, update)
+	}
+	ctx, span := r.tracer.Start(ctx, "webhook.deliver", tracing.KindClient)
+	defer span.End()
+
+	result, err := r.deliver(ctx, token, update)
+	if result != nil {
+		span.SetAttribute("http.response.status_code", result.StatusCode)
+		if !result.Success {
+			span.SetError(result.Error)
+		}
+	}
+	return result, err
+}
+
+func (r *Registry) deliver(ctx context.Context, token string, update map[string]interface{}) (*DeliveryResult, error) {
 	// Copy config fields under lock to avoid race conditions
 	r.mu.RLock()
 	cfg := r.webhooks[token]
@@ -247,7 +317,7 @@ func (r *Registry) Deliver(token string, update map[string]interface{}) (*Delive
 	}
 
 	// Create the request
-	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
 	if err != nil {
 		return &DeliveryResult{
 			Success: false,
@@ -256,6 +326,7 @@ func (r *Registry) Deliver(token string, update map[string]interface{}) (*Delive
 	}
 
 	req.Header.Set("Content-Type", "application/json")
+	tracing.Inject(ctx, req.Header)
 
 	// Add secret token header if configured
 	if secretToken != "" {
@@ -271,7 +342,7 @@ func (r *Registry) Deliver(token string, update map[string]interface{}) (*Delive
 		// Update last error
 		r.mu.Lock()
 		if c := r.webhooks[token]; c != nil {
-			now := time.Now().Unix()
+			now := r.now().Unix()
 			c.LastErrorDate = &now
 			c.LastErrorMessage = err.Error()
 		}
@@ -317,7 +388,7 @@ func (r *Registry) Deliver(token string, update map[string]interface{}) (*Delive
 		// Update last error
 		r.mu.Lock()
 		if c := r.webhooks[token]; c != nil {
-			now := time.Now().Unix()
+			now := r.now().Unix()
 			c.LastErrorDate = &now
 			c.LastErrorMessage = resp.Status
 		}
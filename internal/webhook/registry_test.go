@@ -110,6 +110,23 @@ func TestRegistry_Clear(t *testing.T) {
 	}
 }
 
+func TestRegistry_ClearSession(t *testing.T) {
+	r := NewRegistry(nil)
+
+	r.Set("123:abc", &Config{URL: "https://example1.com"})
+	r.Set("456:def", &Config{URL: "https://example2.com", Session: "job-1"})
+	r.Set("789:ghi", &Config{URL: "https://example3.com", Session: "job-2"})
+
+	r.ClearSession("job-1")
+
+	if r.Get("456:def") != nil {
+		t.Error("ClearSession should remove the session's webhooks")
+	}
+	if r.Get("123:abc") == nil || r.Get("789:ghi") == nil {
+		t.Error("ClearSession should keep other sessions' webhooks")
+	}
+}
+
 func TestRegistry_GetInfo(t *testing.T) {
 	r := NewRegistry(nil)
 
@@ -614,3 +631,23 @@ func TestRegistry_WebhookMethodResponse_AnswerCallbackQuery(t *testing.T) {
 		t.Errorf("Response = %v, want true", result.MethodResult.Response)
 	}
 }
+
+func TestRegistry_Restore(t *testing.T) {
+	r := NewRegistry(nil)
+	r.Set("123:abc", &Config{URL: "https://example.com/a"})
+
+	r.Restore(map[string]*Config{
+		"456:def": {URL: "https://example.com/b", CreatedAt: 42},
+	})
+
+	if r.Get("123:abc") != nil {
+		t.Error("expected previous webhook to be removed")
+	}
+	cfg := r.Get("456:def")
+	if cfg == nil || cfg.URL != "https://example.com/b" {
+		t.Fatalf("expected restored webhook, got %+v", cfg)
+	}
+	if cfg.CreatedAt != 42 {
+		t.Errorf("CreatedAt = %d, want 42", cfg.CreatedAt)
+	}
+}
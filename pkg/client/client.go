@@ -0,0 +1,463 @@
+// Package client is a Go client for the tg-mock control API. It lets test
+// suites register scenarios, inject updates, inspect recorded requests, and
+// reset state without building HTTP requests by hand.
+//
+//	c := client.New("http://localhost:8081")
+//	c.AddScenario(ctx, client.Scenario{Method: "sendMessage", Times: 1, Response: errors.ChatNotFound()})
+//	// ... exercise the bot ...
+//	if err := c.Verify(ctx, client.RequestFilter{Method: "sendMessage"}, 1); err != nil {
+//		t.Fatal(err)
+//	}
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// Client calls the control API of a tg-mock server. Its fields may be
+// changed before first use; use WithSession for a client bound to another
+// session.
+type Client struct {
+	// BaseURL is the server address, e.g. http://localhost:8081.
+	BaseURL string
+	// HTTPClient sends the requests. If nil, http.DefaultClient is used.
+	HTTPClient *http.Client
+	// ControlToken is sent on every request when the server requires one.
+	ControlToken string
+	// Session selects the session the requests apply to (empty = default).
+	Session string
+}
+
+// New creates a client for the server at baseURL.
+func New(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// WithSession returns a copy of the client bound to the named session.
+func (c *Client) WithSession(name string) *Client {
+	clone := *c
+	clone.Session = name
+	return &clone
+}
+
+// Scenario makes the server answer matching calls with an error or with
+// overridden response data.
+type Scenario struct {
+	ID string `json:"id,omitempty"`
+	// Method to match, or "*" for any method.
+	Method string `json:"method"`
+	// Match lists parameters that must have these values.
+	Match map[string]interface{} `json:"match,omitempty"`
+	// Times is the number of times the scenario triggers (0 = unlimited).
+	Times        int                    `json:"times,omitempty"`
+	Response     *ErrorResponse         `json:"response,omitempty"`
+	ResponseData map[string]interface{} `json:"response_data,omitempty"`
+	// Script is a Lua script computing the response; see the README.
+	Script string `json:"script,omitempty"`
+}
+
+// ErrorResponse is the Bot API error a scenario returns. The constructors
+// in pkg/errors build the errors tg-mock knows about.
+type ErrorResponse = tgerrors.Error
+
+// Request is a Bot API request recorded by the server.
+type Request struct {
+	ID         int64                  `json:"id"`
+	Timestamp  time.Time              `json:"timestamp"`
+	Token      string                 `json:"token"`
+	Method     string                 `json:"method"`
+	Params     map[string]interface{} `json:"params"`
+	ScenarioID string                 `json:"scenario_id,omitempty"`
+	// ScenarioRevision is the revision of the scenario set when the
+	// request was handled; see ScenarioHistory.
+	ScenarioRevision int64       `json:"scenario_revision"`
+	Response         interface{} `json:"response"`
+	IsError          bool        `json:"is_error"`
+	StatusCode       int         `json:"status_code"`
+}
+
+// ScenarioChange is a change to the scenario set. Revision is the revision
+// the change produced.
+type ScenarioChange struct {
+	Revision   int64     `json:"revision"`
+	Timestamp  time.Time `json:"timestamp"`
+	Action     string    `json:"action"` // add, update, remove, clear, or restore
+	ScenarioID string    `json:"scenario_id,omitempty"`
+	Scenario   *Scenario `json:"scenario,omitempty"`
+}
+
+// RequestFilter selects recorded requests. Zero fields match anything.
+type RequestFilter struct {
+	Method     string
+	Token      string
+	ScenarioID string
+	Since      time.Time
+	Until      time.Time
+	StatusCode int
+	// IsError, if set, matches failed or successful requests only.
+	IsError *bool
+	// Params lists parameters that must have these values. It is applied
+	// by the client, after the server has filtered the requests.
+	Params map[string]interface{}
+}
+
+func (f RequestFilter) query() url.Values {
+	q := url.Values{}
+	if f.Method != "" {
+		q.Set("method", f.Method)
+	}
+	if f.Token != "" {
+		q.Set("token", f.Token)
+	}
+	if f.ScenarioID != "" {
+		q.Set("scenario_id", f.ScenarioID)
+	}
+	if !f.Since.IsZero() {
+		q.Set("since", f.Since.Format(time.RFC3339Nano))
+	}
+	if !f.Until.IsZero() {
+		q.Set("until", f.Until.Format(time.RFC3339Nano))
+	}
+	if f.StatusCode != 0 {
+		q.Set("status_code", strconv.Itoa(f.StatusCode))
+	}
+	if f.IsError != nil {
+		q.Set("is_error", strconv.FormatBool(*f.IsError))
+	}
+	return q
+}
+
+// match applies the client-side part of the filter.
+func (f RequestFilter) match(req Request) bool {
+	for key, want := range f.Params {
+		got, ok := req.Params[key]
+		if !ok || !sameJSON(got, want) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameJSON compares values by their JSON encoding, so that 42 matches the
+// float64 42 decoded from a response.
+func sameJSON(a, b interface{}) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(x, y)
+}
+
+// RequestPage is one page of recorded requests, newest first.
+type RequestPage struct {
+	Requests []Request
+	// Count is the number of requests recorded in the session.
+	Count int
+	// NextCursor fetches the next page with ListRequestsPage. It is empty
+	// on the last page.
+	NextCursor string
+}
+
+// AddScenario registers a scenario and returns its ID.
+func (c *Client) AddScenario(ctx context.Context, s Scenario) (string, error) {
+	var resp struct {
+		ID string `json:"id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/scenarios", nil, s, &resp); err != nil {
+		return "", err
+	}
+	return resp.ID, nil
+}
+
+// ListScenarios returns the registered scenarios.
+func (c *Client) ListScenarios(ctx context.Context) ([]Scenario, error) {
+	var resp struct {
+		Scenarios []Scenario `json:"scenarios"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/scenarios", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Scenarios, nil
+}
+
+// UpdateScenario replaces the scenario with the given ID.
+func (c *Client) UpdateScenario(ctx context.Context, id string, s Scenario) error {
+	return c.do(ctx, http.MethodPut, "/scenarios/"+url.PathEscape(id), nil, s, nil)
+}
+
+// ScenarioHistory returns the current revision of the scenario set and
+// the changes that produced revisions after from, up to and including to
+// (0 = latest).
+func (c *Client) ScenarioHistory(ctx context.Context, from, to int64) (int64, []ScenarioChange, error) {
+	q := url.Values{}
+	q.Set("from", strconv.FormatInt(from, 10))
+	q.Set("to", strconv.FormatInt(to, 10))
+	var resp struct {
+		Revision int64            `json:"revision"`
+		Changes  []ScenarioChange `json:"changes"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/scenarios/history", q, nil, &resp); err != nil {
+		return 0, nil, err
+	}
+	return resp.Revision, resp.Changes, nil
+}
+
+// RemoveScenario removes a scenario by ID.
+func (c *Client) RemoveScenario(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/scenarios/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ClearScenarios removes all scenarios.
+func (c *Client) ClearScenarios(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/scenarios", nil, nil, nil)
+}
+
+// InjectUpdate queues an update for getUpdates and returns its update_id.
+func (c *Client) InjectUpdate(ctx context.Context, update map[string]interface{}) (int64, error) {
+	var resp struct {
+		UpdateID int64 `json:"update_id"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/updates", nil, update, &resp); err != nil {
+		return 0, err
+	}
+	return resp.UpdateID, nil
+}
+
+// ListRequests returns all recorded requests matching f, newest first.
+func (c *Client) ListRequests(ctx context.Context, f RequestFilter) ([]Request, error) {
+	var all []Request
+	cursor := ""
+	for {
+		page, err := c.ListRequestsPage(ctx, f, cursor, 0)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Requests...)
+		if page.NextCursor == "" {
+			return all, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// ListRequestsPage returns a page of at most limit recorded requests
+// matching f, starting at cursor (empty = newest). A limit of 0 uses the
+// server's default.
+func (c *Client) ListRequestsPage(ctx context.Context, f RequestFilter, cursor string, limit int) (*RequestPage, error) {
+	q := f.query()
+	if cursor != "" {
+		q.Set("cursor", cursor)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var resp struct {
+		Requests   []Request `json:"requests"`
+		Count      int       `json:"count"`
+		NextCursor *string   `json:"next_cursor"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/requests", q, nil, &resp); err != nil {
+		return nil, err
+	}
+	page := &RequestPage{Count: resp.Count}
+	for _, req := range resp.Requests {
+		if f.match(req) {
+			page.Requests = append(page.Requests, req)
+		}
+	}
+	if resp.NextCursor != nil {
+		page.NextCursor = *resp.NextCursor
+	}
+	return page, nil
+}
+
+// WaitRequests blocks until count requests matching f have been recorded
+// or timeout elapses, and returns them oldest first. Params are not
+// supported by the server and are ignored. On timeout the error is a
+// *APIError with status 408.
+func (c *Client) WaitRequests(ctx context.Context, f RequestFilter, count int, timeout time.Duration) ([]Request, error) {
+	q := f.query()
+	q.Set("count", strconv.Itoa(count))
+	q.Set("timeout", timeout.String())
+	var resp struct {
+		Requests []Request `json:"requests"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/requests/wait", q, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Requests, nil
+}
+
+// AtLeastOnce passed as the times of Verify accepts any number of matching
+// requests but zero.
+const AtLeastOnce = -1
+
+// Verify checks that exactly times recorded requests match f, or at least
+// one if times is AtLeastOnce. A mismatch is reported as a *VerifyError.
+func (c *Client) Verify(ctx context.Context, f RequestFilter, times int) error {
+	requests, err := c.ListRequests(ctx, f)
+	if err != nil {
+		return err
+	}
+	got := len(requests)
+	if (times == AtLeastOnce && got > 0) || got == times {
+		return nil
+	}
+	return &VerifyError{Filter: f, Want: times, Got: got}
+}
+
+// ClearRequests forgets the recorded requests.
+func (c *Client) ClearRequests(ctx context.Context) error {
+	return c.do(ctx, http.MethodDelete, "/requests", nil, nil, nil)
+}
+
+// Reset clears the scenarios, updates, recorded requests, and the rest of
+// the session's state.
+func (c *Client) Reset(ctx context.Context) error {
+	return c.do(ctx, http.MethodPost, "/reset", nil, nil, nil)
+}
+
+// ChatAction is the state of the chat action, such as "typing", that a bot
+// last sent to a chat. Telegram shows it for 5 seconds, or until the bot
+// sends the chat a message.
+type ChatAction struct {
+	ChatID    string    `json:"chat_id"`
+	Action    string    `json:"action"`
+	Visible   bool      `json:"visible"`
+	SentAt    time.Time `json:"sent_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ClearedAt is set when a message from the bot cleared the action
+	// while it was visible, so the action was shown before the message.
+	ClearedAt *time.Time `json:"cleared_at,omitempty"`
+	// Count is the number of sendChatAction calls for the chat since the
+	// session was last reset; messages clearing the action don't reset it.
+	Count int `json:"count"`
+	// Lapses counts refreshes sent after the previous action had expired.
+	Lapses int `json:"lapses"`
+	// MaxIntervalMs is the longest time between refreshes.
+	MaxIntervalMs int64 `json:"max_interval_ms"`
+}
+
+// ChatAction returns the chat action state of a chat, named by the chat_id
+// the bot used. A chat without chat actions gives an error for which
+// IsNotFound is true.
+func (c *Client) ChatAction(ctx context.Context, chatID string) (*ChatAction, error) {
+	var action ChatAction
+	if err := c.do(ctx, http.MethodGet, "/chat-actions/"+url.PathEscape(chatID), nil, nil, &action); err != nil {
+		return nil, err
+	}
+	return &action, nil
+}
+
+// ChatActions returns the chat action state of every chat the bot sent a
+// chat action to, ordered by chat ID.
+func (c *Client) ChatActions(ctx context.Context) ([]ChatAction, error) {
+	var resp struct {
+		ChatActions []ChatAction `json:"chat_actions"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/chat-actions", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.ChatActions, nil
+}
+
+// Instance is an isolated sandbox on the server, with its own base path,
+// faker seed, and state. It is removed once it expires.
+type Instance struct {
+	ID        string    `json:"id"`
+	Session   string    `json:"session"`
+	Seed      int64     `json:"seed"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// TTLMs is the time left when the instance was returned.
+	TTLMs int64 `json:"ttl_ms"`
+	// URL replaces https://api.telegram.org in the bot under test.
+	URL        string `json:"url"`
+	ControlURL string `json:"control_url"`
+}
+
+// CreateInstance starts an isolated instance living for ttl (0 = the
+// server's default). A seed of 0 picks a random one.
+func (c *Client) CreateInstance(ctx context.Context, seed int64, ttl time.Duration) (*Instance, error) {
+	body := map[string]interface{}{"seed": seed, "ttl_ms": ttl.Milliseconds()}
+	var inst Instance
+	if err := c.do(ctx, http.MethodPost, "/instances", nil, body, &inst); err != nil {
+		return nil, err
+	}
+	return &inst, nil
+}
+
+// DeleteInstance removes an instance before it expires.
+func (c *Client) DeleteInstance(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/instances/"+url.PathEscape(id), nil, nil, nil)
+}
+
+// ForInstance returns a copy of the client bound to an instance.
+func (c *Client) ForInstance(inst *Instance) *Client {
+	clone := *c
+	clone.BaseURL = strings.TrimRight(inst.URL, "/")
+	clone.Session = ""
+	return &clone
+}
+
+// do sends a control API request with an optional JSON body and decodes
+// the JSON response into out, if given.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	u := c.BaseURL + "/__control" + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.ControlToken != "" {
+		req.Header.Set("X-TG-Mock-Control-Token", c.ControlToken)
+	}
+	if c.Session != "" {
+		req.Header.Set("X-TG-Mock-Session", c.Session)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &APIError{Method: method, Path: path, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding %s %s response: %w", method, path, err)
+	}
+	return nil
+}
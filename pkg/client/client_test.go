@@ -0,0 +1,215 @@
+// pkg/client/client_test.go
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/watzon/tg-mock/internal/server"
+)
+
+func newTestServer(t *testing.T, cfg server.Config) (*httptest.Server, *Client) {
+	t.Helper()
+	ts := httptest.NewServer(server.New(cfg).Router())
+	t.Cleanup(ts.Close)
+	return ts, New(ts.URL + "/")
+}
+
+func callBot(t *testing.T, ts *httptest.Server, method, body string) int {
+	t.Helper()
+	resp, err := http.Post(ts.URL+"/bot123:abc/"+method, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestClient_Scenarios(t *testing.T) {
+	ts, c := newTestServer(t, server.Config{})
+	ctx := context.Background()
+
+	id, err := c.AddScenario(ctx, Scenario{
+		Method:   "sendMessage",
+		Match:    map[string]interface{}{"chat_id": 999},
+		Times:    1,
+		Response: &ErrorResponse{ErrorCode: 400, Description: "Bad Request: chat not found"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == "" {
+		t.Fatal("expected a scenario ID")
+	}
+
+	scenarios, err := c.ListScenarios(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scenarios) != 1 || scenarios[0].ID != id {
+		t.Errorf("unexpected scenarios: %+v", scenarios)
+	}
+
+	if status := callBot(t, ts, "sendMessage", `{"chat_id":999,"text":"hi"}`); status != http.StatusBadRequest {
+		t.Errorf("expected scenario error, got %d", status)
+	}
+
+	if err := c.RemoveScenario(ctx, "nope"); !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
+
+func TestClient_RequestsAndVerify(t *testing.T) {
+	ts, c := newTestServer(t, server.Config{})
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		callBot(t, ts, "sendMessage", `{"chat_id":42,"text":"hi"}`)
+	}
+	callBot(t, ts, "sendMessage", `{"chat_id":7,"text":"hi"}`)
+	callBot(t, ts, "getMe", `{}`)
+
+	page, err := c.ListRequestsPage(ctx, RequestFilter{Method: "sendMessage"}, "", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Requests) != 2 || page.NextCursor == "" || page.Count != 5 {
+		t.Errorf("unexpected first page: %+v", page)
+	}
+
+	all, err := c.ListRequests(ctx, RequestFilter{Method: "sendMessage"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 4 || all[0].Params["chat_id"] != float64(7) {
+		t.Errorf("expected 4 sendMessage requests newest first, got %+v", all)
+	}
+
+	if err := c.Verify(ctx, RequestFilter{Method: "sendMessage", Params: map[string]interface{}{"chat_id": 42}}, 3); err != nil {
+		t.Error(err)
+	}
+	if err := c.Verify(ctx, RequestFilter{Method: "getMe"}, AtLeastOnce); err != nil {
+		t.Error(err)
+	}
+	err = c.Verify(ctx, RequestFilter{Method: "sendPhoto"}, 1)
+	var verifyErr *VerifyError
+	if !errors.As(err, &verifyErr) || verifyErr.Got != 0 {
+		t.Errorf("expected a VerifyError, got %v", err)
+	}
+
+	if err := c.Reset(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Verify(ctx, RequestFilter{}, 0); err != nil {
+		t.Errorf("expected no requests after reset: %v", err)
+	}
+}
+
+func TestClient_UpdatesAndSessions(t *testing.T) {
+	ts, c := newTestServer(t, server.Config{})
+	ctx := context.Background()
+
+	isolated := c.WithSession("isolated")
+	id, err := isolated.InjectUpdate(ctx, map[string]interface{}{
+		"message": map[string]interface{}{"message_id": 1, "text": "hello"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id == 0 {
+		t.Error("expected an update ID")
+	}
+
+	callBot(t, ts, "getMe", `{}`)
+	requests, err := isolated.ListRequests(ctx, RequestFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 0 {
+		t.Errorf("expected the isolated session to have no requests, got %d", len(requests))
+	}
+}
+
+func TestClient_ChatActions(t *testing.T) {
+	ts, c := newTestServer(t, server.Config{})
+	ctx := context.Background()
+
+	if _, err := c.ChatAction(ctx, "42"); !IsNotFound(err) {
+		t.Errorf("expected not found before any chat action, got %v", err)
+	}
+	if status := callBot(t, ts, "sendChatAction", `{"chat_id":42,"action":"dancing"}`); status != http.StatusBadRequest {
+		t.Errorf("expected an unknown action to be rejected, got %d", status)
+	}
+
+	callBot(t, ts, "sendChatAction", `{"chat_id":42,"action":"typing"}`)
+	action, err := c.ChatAction(ctx, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !action.Visible || action.Action != "typing" || action.ClearedAt != nil {
+		t.Errorf("expected typing to be visible, got %+v", action)
+	}
+
+	callBot(t, ts, "sendMessage", `{"chat_id":42,"text":"Done"}`)
+	actions, err := c.ChatActions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 || actions[0].Visible || actions[0].ClearedAt == nil {
+		t.Errorf("expected the message to clear the action, got %+v", actions)
+	}
+}
+
+func TestClient_ControlToken(t *testing.T) {
+	_, c := newTestServer(t, server.Config{ControlToken: "s3cret"})
+	ctx := context.Background()
+
+	err := c.Reset(ctx)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without control token, got %v", err)
+	}
+
+	c.ControlToken = "s3cret"
+	if err := c.Reset(ctx); err != nil {
+		t.Errorf("expected reset with control token to succeed: %v", err)
+	}
+}
+
+func TestClient_Instances(t *testing.T) {
+	ts, c := newTestServer(t, server.Config{})
+	ctx := context.Background()
+
+	inst, err := c.CreateInstance(ctx, 42, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.Seed != 42 || inst.URL != ts.URL+"/instances/"+inst.ID {
+		t.Errorf("unexpected instance %+v", inst)
+	}
+
+	resp, err := http.Post(inst.URL+"/bot123:abc/getMe", "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	sandbox := c.ForInstance(inst)
+	if err := sandbox.Verify(ctx, RequestFilter{Method: "getMe"}, 1); err != nil {
+		t.Error(err)
+	}
+	if err := c.Verify(ctx, RequestFilter{}, 0); err != nil {
+		t.Errorf("expected the default session to be untouched: %v", err)
+	}
+
+	if err := c.DeleteInstance(ctx, inst.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DeleteInstance(ctx, inst.ID); !IsNotFound(err) {
+		t.Errorf("expected not found error, got %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+// pkg/client/errors.go
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when the control API answers with an error status.
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	// Message is the body of the response.
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("tg-mock: %s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// IsNotFound reports whether err is an APIError with status 404.
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// VerifyError is returned by Verify when the number of matching requests
+// differs from the expected one.
+type VerifyError struct {
+	Filter RequestFilter
+	// Want is the expected number of requests, or AtLeastOnce.
+	Want int
+	Got  int
+}
+
+func (e *VerifyError) Error() string {
+	what := "requests"
+	if e.Filter.Method != "" {
+		what = e.Filter.Method + " calls"
+	}
+	if e.Want == AtLeastOnce {
+		return fmt.Sprintf("tg-mock: expected at least one of %s, got none", what)
+	}
+	return fmt.Sprintf("tg-mock: expected %d %s, got %d", e.Want, what, e.Got)
+}
@@ -0,0 +1,612 @@
+// Package errors is the catalog of Telegram Bot API errors that tg-mock
+// simulates. Each error has a constructor, so scenarios can be composed in
+// Go without repeating error codes and descriptions:
+//
+//	c.AddScenario(ctx, client.Scenario{Method: "sendMessage", Response: errors.ChatNotFound()})
+//	c.AddScenario(ctx, client.Scenario{Method: "*", Response: errors.RateLimit(5)})
+//
+// The same errors can be triggered by name with the X-TG-Mock-Scenario
+// header; Builtin looks them up by that name.
+package errors
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Error is a Bot API error response.
+type Error struct {
+	ErrorCode   int    `json:"error_code"`
+	Description string `json:"description"`
+	// RetryAfter is the number of seconds to wait, for rate limit errors.
+	RetryAfter int `json:"retry_after,omitempty"`
+	// MigrateToChatID is the supergroup a group chat was upgraded to. Sending
+	// to the group fails with it until clients switch to the supergroup.
+	MigrateToChatID int64 `json:"migrate_to_chat_id,omitempty"`
+	// Parameters holds further ResponseParameters fields, or custom ones.
+	// RetryAfter and MigrateToChatID take precedence over the fields of the
+	// same name.
+	Parameters map[string]interface{} `json:"parameters,omitempty"`
+	// HTML sends the error as an HTML page instead of a Bot API response,
+	// as Telegram's edge servers do when the Bot API is unreachable.
+	HTML bool `json:"html,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s", e.ErrorCode, e.Description)
+}
+
+// ResponseParameters returns the parameters object sent with the error, or
+// nil if it has none.
+func (e *Error) ResponseParameters() map[string]interface{} {
+	if len(e.Parameters) == 0 && e.RetryAfter <= 0 && e.MigrateToChatID == 0 {
+		return nil
+	}
+	params := make(map[string]interface{}, len(e.Parameters)+2)
+	for key, value := range e.Parameters {
+		params[key] = value
+	}
+	if e.RetryAfter > 0 {
+		params["retry_after"] = e.RetryAfter
+	}
+	if e.MigrateToChatID != 0 {
+		params["migrate_to_chat_id"] = e.MigrateToChatID
+	}
+	return params
+}
+
+func newError(code int, description string) *Error {
+	return &Error{ErrorCode: code, Description: description}
+}
+
+// Builtin returns the error triggered by the scenario header name, such as
+// "chat_not_found". Each call returns a new Error.
+func Builtin(name string) (*Error, bool) {
+	ctor, ok := catalog[name]
+	if !ok {
+		return nil, false
+	}
+	return ctor(), true
+}
+
+// Names returns the names of all builtin errors, sorted.
+func Names() []string {
+	names := make([]string, 0, len(catalog))
+	for name := range catalog {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// 400 Bad Request - General
+
+// BadRequest returns 400 "Bad Request".
+func BadRequest() *Error { return newError(400, "Bad Request") }
+
+// 400 Bad Request - Chat errors
+
+// ChatNotFound returns 400 "Bad Request: chat not found".
+func ChatNotFound() *Error { return newError(400, "Bad Request: chat not found") }
+
+// ChatAdminRequired returns 400 "Bad Request: CHAT_ADMIN_REQUIRED".
+func ChatAdminRequired() *Error { return newError(400, "Bad Request: CHAT_ADMIN_REQUIRED") }
+
+// ChatNotModified returns 400 "Bad Request: CHAT_NOT_MODIFIED".
+func ChatNotModified() *Error { return newError(400, "Bad Request: CHAT_NOT_MODIFIED") }
+
+// ChatRestricted returns 400 "Bad Request: CHAT_RESTRICTED".
+func ChatRestricted() *Error { return newError(400, "Bad Request: CHAT_RESTRICTED") }
+
+// ChatWriteForbidden returns 400 "Bad Request: CHAT_WRITE_FORBIDDEN".
+func ChatWriteForbidden() *Error { return newError(400, "Bad Request: CHAT_WRITE_FORBIDDEN") }
+
+// InviteHashExpired returns 400 "Bad Request: INVITE_HASH_EXPIRED", sent
+// when a revoked invite link is edited.
+func InviteHashExpired() *Error { return newError(400, "Bad Request: INVITE_HASH_EXPIRED") }
+
+// ChannelPrivate returns 400 "Bad Request: CHANNEL_PRIVATE".
+func ChannelPrivate() *Error { return newError(400, "Bad Request: CHANNEL_PRIVATE") }
+
+// GroupDeactivated returns 400 "Bad Request: group is deactivated".
+func GroupDeactivated() *Error { return newError(400, "Bad Request: group is deactivated") }
+
+// GroupUpgraded returns 400 "Bad Request: group chat was upgraded to a supergroup chat".
+func GroupUpgraded() *Error {
+	return newError(400, "Bad Request: group chat was upgraded to a supergroup chat")
+}
+
+// GroupMigrated returns GroupUpgraded with the ID of the supergroup the
+// chat became.
+func GroupMigrated(supergroupID int64) *Error {
+	e := GroupUpgraded()
+	e.MigrateToChatID = supergroupID
+	return e
+}
+
+// SupergroupChannelOnly returns 400 "Bad Request: method is available for supergroup and channel chats only".
+func SupergroupChannelOnly() *Error {
+	return newError(400, "Bad Request: method is available for supergroup and channel chats only")
+}
+
+// NotInChat returns 400 "Bad Request: not in the chat".
+func NotInChat() *Error { return newError(400, "Bad Request: not in the chat") }
+
+// TopicNotModified returns 400 "Bad Request: TOPIC_NOT_MODIFIED".
+func TopicNotModified() *Error { return newError(400, "Bad Request: TOPIC_NOT_MODIFIED") }
+
+// TopicClosed returns 400 "Bad Request: TOPIC_CLOSED", sent for messages
+// to a closed forum topic.
+func TopicClosed() *Error { return newError(400, "Bad Request: TOPIC_CLOSED") }
+
+// 400 Bad Request - User errors
+
+// UserNotFound returns 400 "Bad Request: user not found".
+func UserNotFound() *Error { return newError(400, "Bad Request: user not found") }
+
+// UserIDInvalid returns 400 "Bad Request: USER_ID_INVALID".
+func UserIDInvalid() *Error { return newError(400, "Bad Request: USER_ID_INVALID") }
+
+// UserIsAdmin returns 400 "Bad Request: user is an administrator of the chat".
+func UserIsAdmin() *Error { return newError(400, "Bad Request: user is an administrator of the chat") }
+
+// ParticipantIDInvalid returns 400 "Bad Request: PARTICIPANT_ID_INVALID".
+func ParticipantIDInvalid() *Error { return newError(400, "Bad Request: PARTICIPANT_ID_INVALID") }
+
+// CantRemoveOwner returns 400 "Bad Request: can't remove chat owner".
+func CantRemoveOwner() *Error { return newError(400, "Bad Request: can't remove chat owner") }
+
+// 400 Bad Request - Message errors
+
+// MessageNotFound returns 400 "Bad Request: message to edit not found".
+func MessageNotFound() *Error { return newError(400, "Bad Request: message to edit not found") }
+
+// MessageNotModified returns 400 "Bad Request: message is not modified".
+func MessageNotModified() *Error { return newError(400, "Bad Request: message is not modified") }
+
+// MessageTextEmpty returns 400 "Bad Request: message text is empty".
+func MessageTextEmpty() *Error { return newError(400, "Bad Request: message text is empty") }
+
+// MessageTooLong returns 400 "Bad Request: message is too long".
+func MessageTooLong() *Error { return newError(400, "Bad Request: message is too long") }
+
+// MessageCaptionTooLong returns 400 "Bad Request: message caption is too
+// long".
+func MessageCaptionTooLong() *Error {
+	return newError(400, "Bad Request: message caption is too long")
+}
+
+// CantParseEntities returns 400 "Bad Request: can't parse entities:
+// <reason>", sent for text whose parse_mode markup is malformed.
+func CantParseEntities(reason string) *Error {
+	return newError(400, "Bad Request: can't parse entities: "+reason)
+}
+
+// MessageCantBeEdited returns 400 "Bad Request: message can't be edited".
+func MessageCantBeEdited() *Error { return newError(400, "Bad Request: message can't be edited") }
+
+// MessageCantBeDeleted returns 400 "Bad Request: message can't be deleted".
+func MessageCantBeDeleted() *Error { return newError(400, "Bad Request: message can't be deleted") }
+
+// MessageToDeleteNotFound returns 400 "Bad Request: message to delete not found".
+func MessageToDeleteNotFound() *Error {
+	return newError(400, "Bad Request: message to delete not found")
+}
+
+// MessageToForwardNotFound returns 400 "Bad Request: message to forward not found".
+func MessageToForwardNotFound() *Error {
+	return newError(400, "Bad Request: message to forward not found")
+}
+
+// MessageIDInvalid returns 400 "Bad Request: MESSAGE_ID_INVALID".
+func MessageIDInvalid() *Error { return newError(400, "Bad Request: MESSAGE_ID_INVALID") }
+
+// MessageThreadNotFound returns 400 "Bad Request: message thread not found".
+func MessageThreadNotFound() *Error { return newError(400, "Bad Request: message thread not found") }
+
+// ReplyMessageNotFound returns 400 "Bad Request: reply message not found".
+func ReplyMessageNotFound() *Error { return newError(400, "Bad Request: reply message not found") }
+
+// MessageToReplyNotFound returns 400 "Bad Request: message to be replied
+// not found".
+func MessageToReplyNotFound() *Error {
+	return newError(400, "Bad Request: message to be replied not found")
+}
+
+// QuoteTextInvalid returns 400 "Bad Request: QUOTE_TEXT_INVALID", sent when
+// a reply quotes text the replied-to message doesn't contain.
+func QuoteTextInvalid() *Error { return newError(400, "Bad Request: QUOTE_TEXT_INVALID") }
+
+// MediaGroupSizeInvalid returns 400 "Bad Request: media group must
+// include 2-10 items".
+func MediaGroupSizeInvalid() *Error {
+	return newError(400, "Bad Request: media group must include 2-10 items")
+}
+
+// MediaGroupMixed returns 400 "Bad Request: documents and audio files
+// can't be mixed with other media in an album".
+func MediaGroupMixed() *Error {
+	return newError(400, "Bad Request: documents and audio files can't be mixed with other media in an album")
+}
+
+// PollAlreadyClosed returns 400 "Bad Request: poll has already been closed".
+func PollAlreadyClosed() *Error { return newError(400, "Bad Request: poll has already been closed") }
+
+// 400 Bad Request - Permission/Rights errors
+
+// NoRightsToSend returns 400 "Bad Request: have no rights to send a message".
+func NoRightsToSend() *Error { return newError(400, "Bad Request: have no rights to send a message") }
+
+// NotEnoughRights returns 400 "Bad Request: not enough rights".
+func NotEnoughRights() *Error { return newError(400, "Bad Request: not enough rights") }
+
+// NotEnoughRightsPin returns 400 "Bad Request: not enough rights to manage pinned messages in the chat".
+func NotEnoughRightsPin() *Error {
+	return newError(400, "Bad Request: not enough rights to manage pinned messages in the chat")
+}
+
+// NotEnoughRightsRestrict returns 400 "Bad Request: not enough rights to restrict/unrestrict chat member".
+func NotEnoughRightsRestrict() *Error {
+	return newError(400, "Bad Request: not enough rights to restrict/unrestrict chat member")
+}
+
+// NotEnoughRightsSendText returns 400 "Bad Request: not enough rights to send text messages to the chat".
+func NotEnoughRightsSendText() *Error {
+	return newError(400, "Bad Request: not enough rights to send text messages to the chat")
+}
+
+// NotEnoughRightsSend returns 400 "Bad Request: not enough rights to send
+// <what> to the chat", such as "photos" or "polls", for restricted bots.
+func NotEnoughRightsSend(what string) *Error {
+	return newError(400, fmt.Sprintf("Bad Request: not enough rights to send %s to the chat", what))
+}
+
+// 400 Bad Request - Admin errors
+
+// AdminRankEmojiNotAllowed returns 400 "Bad Request: ADMIN_RANK_EMOJI_NOT_ALLOWED".
+func AdminRankEmojiNotAllowed() *Error {
+	return newError(400, "Bad Request: ADMIN_RANK_EMOJI_NOT_ALLOWED")
+}
+
+// 400 Bad Request - Inline/Button errors
+
+// ButtonURLInvalid returns 400 "Bad Request: BUTTON_URL_INVALID".
+func ButtonURLInvalid() *Error { return newError(400, "Bad Request: BUTTON_URL_INVALID") }
+
+// InlineButtonURLInvalid returns 400 "Bad Request: inline keyboard button URL".
+func InlineButtonURLInvalid() *Error { return newError(400, "Bad Request: inline keyboard button URL") }
+
+// ButtonDataInvalid returns 400 "Bad Request: BUTTON_DATA_INVALID", sent
+// for callback_data that is empty or longer than 64 bytes.
+func ButtonDataInvalid() *Error { return newError(400, "Bad Request: BUTTON_DATA_INVALID") }
+
+// ReplyMarkupTooLong returns 400 "Bad Request: REPLY_MARKUP_TOO_LONG",
+// sent for keyboards with too many buttons.
+func ReplyMarkupTooLong() *Error { return newError(400, "Bad Request: REPLY_MARKUP_TOO_LONG") }
+
+// InlineKeyboardInvalid returns 400 "Bad Request: field "inline_keyboard"
+// of the InlineKeyboardMarkup should be an Array of Arrays".
+func InlineKeyboardInvalid() *Error {
+	return newError(400, `Bad Request: field "inline_keyboard" of the InlineKeyboardMarkup should be an Array of Arrays`)
+}
+
+// CantParseInlineKeyboardButton returns 400 "Bad Request: can't parse
+// inline keyboard button: <reason>", sent for buttons that aren't
+// well-formed.
+func CantParseInlineKeyboardButton(reason string) *Error {
+	return newError(400, "Bad Request: can't parse inline keyboard button: "+reason)
+}
+
+// 400 Bad Request - File errors
+
+// FileTooBig returns 400 "Bad Request: file is too big".
+func FileTooBig() *Error { return newError(400, "Bad Request: file is too big") }
+
+// InvalidFileID returns 400 "Bad Request: invalid file id".
+func InvalidFileID() *Error { return newError(400, "Bad Request: invalid file id") }
+
+// WrongFileIdentifier returns 400 "Bad Request: wrong file identifier/HTTP
+// URL specified", sent for files that are neither a file_id, a URL, nor
+// an uploaded part.
+func WrongFileIdentifier() *Error {
+	return newError(400, "Bad Request: wrong file identifier/HTTP URL specified")
+}
+
+// CantParseInputMedia returns 400 "Bad Request: can't parse InputMedia:
+// <reason>", sent for InputMedia objects that aren't well-formed.
+func CantParseInputMedia(reason string) *Error {
+	return newError(400, "Bad Request: can't parse InputMedia: "+reason)
+}
+
+// 400 Bad Request - Other
+
+// EntitiesTooLong returns 400 "Bad Request: entities too long".
+func EntitiesTooLong() *Error { return newError(400, "Bad Request: entities too long") }
+
+// MemberNotFound returns 400 "Bad Request: member not found".
+func MemberNotFound() *Error { return newError(400, "Bad Request: member not found") }
+
+// PeerIDInvalid returns 400 "Bad Request: PEER_ID_INVALID".
+func PeerIDInvalid() *Error { return newError(400, "Bad Request: PEER_ID_INVALID") }
+
+// WrongParameterAction returns 400 "Bad Request: wrong parameter action in request".
+func WrongParameterAction() *Error {
+	return newError(400, "Bad Request: wrong parameter action in request")
+}
+
+// WrongParameter returns 400 "Bad Request: wrong parameter <name> in
+// request", sent for an enumerated parameter with an unknown value.
+func WrongParameter(name string) *Error {
+	return newError(400, "Bad Request: wrong parameter "+name+" in request")
+}
+
+// UnsupportedParseMode returns 400 "Bad Request: unsupported parse_mode".
+func UnsupportedParseMode() *Error { return newError(400, "Bad Request: unsupported parse_mode") }
+
+// HideRequesterMissing returns 400 "Bad Request: HIDE_REQUESTER_MISSING".
+func HideRequesterMissing() *Error { return newError(400, "Bad Request: HIDE_REQUESTER_MISSING") }
+
+// ChargeAlreadyRefunded returns 400 "Bad Request: CHARGE_ALREADY_REFUNDED",
+// sent when refundStarPayment is called twice for the same payment.
+func ChargeAlreadyRefunded() *Error { return newError(400, "Bad Request: CHARGE_ALREADY_REFUNDED") }
+
+// BotScoreNotModified returns 400 "Bad Request: BOT_SCORE_NOT_MODIFIED",
+// sent when setGameScore doesn't raise a score without force.
+func BotScoreNotModified() *Error { return newError(400, "Bad Request: BOT_SCORE_NOT_MODIFIED") }
+
+// BotCommandInvalid returns 400 "Bad Request: BOT_COMMAND_INVALID", sent
+// for command names Telegram doesn't allow.
+func BotCommandInvalid() *Error { return newError(400, "Bad Request: BOT_COMMAND_INVALID") }
+
+// BotCommandDescriptionInvalid returns 400 "Bad Request:
+// BOT_COMMAND_DESCRIPTION_INVALID".
+func BotCommandDescriptionInvalid() *Error {
+	return newError(400, "Bad Request: BOT_COMMAND_DESCRIPTION_INVALID")
+}
+
+// BotCommandsTooMuch returns 400 "Bad Request: BOT_COMMANDS_TOO_MUCH",
+// sent for more than 100 commands.
+func BotCommandsTooMuch() *Error { return newError(400, "Bad Request: BOT_COMMANDS_TOO_MUCH") }
+
+// StickerSetNameOccupied returns 400 "Bad Request: sticker set name is
+// already occupied", sent when createNewStickerSet reuses a name.
+func StickerSetNameOccupied() *Error {
+	return newError(400, "Bad Request: sticker set name is already occupied")
+}
+
+// 401 Unauthorized
+
+// Unauthorized returns 401 "Unauthorized".
+func Unauthorized() *Error { return newError(401, "Unauthorized") }
+
+// 403 Forbidden - General
+
+// Forbidden returns 403 "Forbidden".
+func Forbidden() *Error { return newError(403, "Forbidden") }
+
+// 403 Forbidden - Bot blocked/kicked
+
+// BotBlocked returns 403 "Forbidden: bot was blocked by the user".
+func BotBlocked() *Error { return newError(403, "Forbidden: bot was blocked by the user") }
+
+// BotKicked returns 403 "Forbidden: bot was kicked from the chat".
+func BotKicked() *Error { return newError(403, "Forbidden: bot was kicked from the chat") }
+
+// BotKickedChannel returns 403 "Forbidden: bot was kicked from the channel chat".
+func BotKickedChannel() *Error {
+	return newError(403, "Forbidden: bot was kicked from the channel chat")
+}
+
+// BotKickedGroup returns 403 "Forbidden: bot was kicked from the group chat".
+func BotKickedGroup() *Error { return newError(403, "Forbidden: bot was kicked from the group chat") }
+
+// BotKickedSupergroup returns 403 "Forbidden: bot was kicked from the supergroup chat".
+func BotKickedSupergroup() *Error {
+	return newError(403, "Forbidden: bot was kicked from the supergroup chat")
+}
+
+// 403 Forbidden - Bot not member
+
+// NotMemberGroup returns 403 "Forbidden: bot is not a member of the group chat".
+func NotMemberGroup() *Error {
+	return newError(403, "Forbidden: bot is not a member of the group chat")
+}
+
+// NotMemberChannel returns 403 "Forbidden: bot is not a member of the channel chat".
+func NotMemberChannel() *Error {
+	return newError(403, "Forbidden: bot is not a member of the channel chat")
+}
+
+// NotMemberSupergroup returns 403 "Forbidden: bot is not a member of the supergroup chat".
+func NotMemberSupergroup() *Error {
+	return newError(403, "Forbidden: bot is not a member of the supergroup chat")
+}
+
+// 403 Forbidden - Bot can't act
+
+// CantInitiate returns 403 "Forbidden: bot can't initiate conversation with a user".
+func CantInitiate() *Error {
+	return newError(403, "Forbidden: bot can't initiate conversation with a user")
+}
+
+// CantSendToBots returns 403 "Forbidden: bot can't send messages to bots".
+func CantSendToBots() *Error { return newError(403, "Forbidden: bot can't send messages to bots") }
+
+// 403 Forbidden - User status
+
+// UserDeactivated returns 403 "Forbidden: user is deactivated".
+func UserDeactivated() *Error { return newError(403, "Forbidden: user is deactivated") }
+
+// 403 Forbidden - Permissions
+
+// NotEnoughRightsText returns 403 "Forbidden: not enough rights to send text messages".
+func NotEnoughRightsText() *Error {
+	return newError(403, "Forbidden: not enough rights to send text messages")
+}
+
+// NotEnoughRightsPhoto returns 403 "Forbidden: not enough rights to send photos".
+func NotEnoughRightsPhoto() *Error {
+	return newError(403, "Forbidden: not enough rights to send photos")
+}
+
+// 409 Conflict
+
+// WebhookActive returns 409 "Conflict: can't use getUpdates method while webhook is active".
+func WebhookActive() *Error {
+	return newError(409, "Conflict: can't use getUpdates method while webhook is active")
+}
+
+// TerminatedByLongPoll returns 409 "Conflict: terminated by other long poll or webhook".
+func TerminatedByLongPoll() *Error {
+	return newError(409, "Conflict: terminated by other long poll or webhook")
+}
+
+// 429 Rate Limit
+
+// RateLimit is the error returned when a bot sends too many requests. The
+// client must wait retryAfter seconds.
+func RateLimit(retryAfter int) *Error {
+	return &Error{ErrorCode: 429, Description: fmt.Sprintf("Too Many Requests: retry after %d", retryAfter), RetryAfter: retryAfter}
+}
+
+// FloodWait is the flood control error. The client must wait seconds
+// seconds.
+func FloodWait(seconds int) *Error {
+	return &Error{ErrorCode: 429, Description: fmt.Sprintf("Flood control exceeded. Retry in %d seconds", seconds), RetryAfter: seconds}
+}
+
+// 5xx Edge errors
+
+// BadGatewayHTML is the HTML page with status 502 that Telegram's edge
+// servers return when the Bot API server is down. It isn't JSON.
+func BadGatewayHTML() *Error { return &Error{ErrorCode: 502, Description: "Bad Gateway", HTML: true} }
+
+// GatewayTimeoutHTML is the HTML page with status 504 that Telegram's edge
+// servers return when the Bot API server doesn't answer in time. It isn't
+// JSON.
+func GatewayTimeoutHTML() *Error {
+	return &Error{ErrorCode: 504, Description: "Gateway Timeout", HTML: true}
+}
+
+// catalog maps scenario header names to constructors.
+var catalog = map[string]func() *Error{
+	// 400 Bad Request - General
+	"bad_request": BadRequest,
+
+	// 400 Bad Request - Chat errors
+	"chat_not_found":          ChatNotFound,
+	"chat_admin_required":     ChatAdminRequired,
+	"chat_not_modified":       ChatNotModified,
+	"chat_restricted":         ChatRestricted,
+	"chat_write_forbidden":    ChatWriteForbidden,
+	"channel_private":         ChannelPrivate,
+	"invite_hash_expired":     InviteHashExpired,
+	"group_deactivated":       GroupDeactivated,
+	"group_upgraded":          GroupUpgraded,
+	"supergroup_channel_only": SupergroupChannelOnly,
+	"not_in_chat":             NotInChat,
+	"topic_not_modified":      TopicNotModified,
+	"topic_closed":            TopicClosed,
+
+	// 400 Bad Request - User errors
+	"user_not_found":         UserNotFound,
+	"user_id_invalid":        UserIDInvalid,
+	"user_is_admin":          UserIsAdmin,
+	"participant_id_invalid": ParticipantIDInvalid,
+	"cant_remove_owner":      CantRemoveOwner,
+
+	// 400 Bad Request - Message errors
+	"message_not_found":            MessageNotFound,
+	"message_not_modified":         MessageNotModified,
+	"message_text_empty":           MessageTextEmpty,
+	"message_too_long":             MessageTooLong,
+	"message_caption_too_long":     MessageCaptionTooLong,
+	"message_cant_be_edited":       MessageCantBeEdited,
+	"message_cant_be_deleted":      MessageCantBeDeleted,
+	"message_to_delete_not_found":  MessageToDeleteNotFound,
+	"message_to_forward_not_found": MessageToForwardNotFound,
+	"message_id_invalid":           MessageIDInvalid,
+	"message_thread_not_found":     MessageThreadNotFound,
+	"reply_message_not_found":      ReplyMessageNotFound,
+	"message_to_reply_not_found":   MessageToReplyNotFound,
+	"quote_text_invalid":           QuoteTextInvalid,
+	"media_group_size_invalid":     MediaGroupSizeInvalid,
+	"media_group_mixed":            MediaGroupMixed,
+	"poll_already_closed":          PollAlreadyClosed,
+
+	// 400 Bad Request - Permission/Rights errors
+	"no_rights_to_send":           NoRightsToSend,
+	"not_enough_rights":           NotEnoughRights,
+	"not_enough_rights_pin":       NotEnoughRightsPin,
+	"not_enough_rights_restrict":  NotEnoughRightsRestrict,
+	"not_enough_rights_send_text": NotEnoughRightsSendText,
+
+	// 400 Bad Request - Admin errors
+	"admin_rank_emoji_not_allowed": AdminRankEmojiNotAllowed,
+
+	// 400 Bad Request - Inline/Button errors
+	"button_url_invalid":        ButtonURLInvalid,
+	"inline_button_url_invalid": InlineButtonURLInvalid,
+	"button_data_invalid":       ButtonDataInvalid,
+	"reply_markup_too_long":     ReplyMarkupTooLong,
+	"inline_keyboard_invalid":   InlineKeyboardInvalid,
+
+	// 400 Bad Request - File errors
+	"file_too_big":          FileTooBig,
+	"invalid_file_id":       InvalidFileID,
+	"wrong_file_identifier": WrongFileIdentifier,
+
+	// 400 Bad Request - Other
+	"entities_too_long":               EntitiesTooLong,
+	"member_not_found":                MemberNotFound,
+	"peer_id_invalid":                 PeerIDInvalid,
+	"wrong_parameter_action":          WrongParameterAction,
+	"unsupported_parse_mode":          UnsupportedParseMode,
+	"hide_requester_missing":          HideRequesterMissing,
+	"charge_already_refunded":         ChargeAlreadyRefunded,
+	"bot_score_not_modified":          BotScoreNotModified,
+	"bot_command_invalid":             BotCommandInvalid,
+	"bot_command_description_invalid": BotCommandDescriptionInvalid,
+	"bot_commands_too_much":           BotCommandsTooMuch,
+
+	// 401 Unauthorized
+	"unauthorized": Unauthorized,
+
+	// 403 Forbidden - General
+	"forbidden": Forbidden,
+
+	// 403 Forbidden - Bot blocked/kicked
+	"bot_blocked":           BotBlocked,
+	"bot_kicked":            BotKicked,
+	"bot_kicked_channel":    BotKickedChannel,
+	"bot_kicked_group":      BotKickedGroup,
+	"bot_kicked_supergroup": BotKickedSupergroup,
+
+	// 403 Forbidden - Bot not member
+	"not_member_channel":    NotMemberChannel,
+	"not_member_group":      NotMemberGroup,
+	"not_member_supergroup": NotMemberSupergroup,
+
+	// 403 Forbidden - Bot can't act
+	"cant_initiate":     CantInitiate,
+	"cant_send_to_bots": CantSendToBots,
+
+	// 403 Forbidden - User status
+	"user_deactivated": UserDeactivated,
+
+	// 403 Forbidden - Permissions
+	"not_enough_rights_text":  NotEnoughRightsText,
+	"not_enough_rights_photo": NotEnoughRightsPhoto,
+
+	// 409 Conflict
+	"webhook_active":          WebhookActive,
+	"terminated_by_long_poll": TerminatedByLongPoll,
+
+	// 429 Rate Limit
+	"rate_limit": func() *Error { return RateLimit(30) },
+	"flood_wait": func() *Error { return FloodWait(60) },
+
+	// 5xx Edge errors
+	"bad_gateway_html":     BadGatewayHTML,
+	"gateway_timeout_html": GatewayTimeoutHTML,
+}
@@ -0,0 +1,69 @@
+// pkg/errors/errors_test.go
+package errors
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuiltin(t *testing.T) {
+	err, ok := Builtin("chat_not_found")
+	if !ok {
+		t.Fatal("expected chat_not_found in the catalog")
+	}
+	if !reflect.DeepEqual(err, ChatNotFound()) {
+		t.Errorf("expected Builtin to match the constructor, got %+v", err)
+	}
+	err.Description = "changed"
+	if again, _ := Builtin("chat_not_found"); again.Description == "changed" {
+		t.Error("expected Builtin to return a new error every call")
+	}
+
+	if _, ok := Builtin("no_such_error"); ok {
+		t.Error("expected unknown name to be missing")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	err := RateLimit(5)
+	if err.ErrorCode != 429 || err.RetryAfter != 5 || err.Description != "Too Many Requests: retry after 5" {
+		t.Errorf("unexpected rate limit error: %+v", err)
+	}
+	if def, _ := Builtin("rate_limit"); def.RetryAfter != 30 {
+		t.Errorf("expected rate_limit to wait 30 seconds, got %d", def.RetryAfter)
+	}
+}
+
+func TestGroupMigrated(t *testing.T) {
+	err := GroupMigrated(-1001234567890)
+	if err.MigrateToChatID != -1001234567890 || err.Description != GroupUpgraded().Description {
+		t.Errorf("unexpected migration error: %+v", err)
+	}
+}
+
+func TestNames(t *testing.T) {
+	names := Names()
+	for i := 1; i < len(names); i++ {
+		if names[i-1] >= names[i] {
+			t.Fatalf("names not sorted: %q before %q", names[i-1], names[i])
+		}
+	}
+	for _, name := range names {
+		err, _ := Builtin(name)
+		if err.ErrorCode < 400 || err.Description == "" {
+			t.Errorf("%s: incomplete error %+v", name, err)
+		}
+	}
+}
+
+func TestResponseParameters(t *testing.T) {
+	if params := ChatNotFound().ResponseParameters(); params != nil {
+		t.Errorf("expected no parameters, got %v", params)
+	}
+	err := RateLimit(5)
+	err.Parameters = map[string]interface{}{"retry_after": 1, "custom": "x"}
+	params := err.ResponseParameters()
+	if params["retry_after"] != 5 || params["custom"] != "x" {
+		t.Errorf("expected RetryAfter to take precedence over custom fields, got %v", params)
+	}
+}
@@ -0,0 +1,246 @@
+// pkg/tgmock/assert.go
+package tgmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/watzon/tg-mock/internal/session"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+// Assertion checks the calls recorded for a method. Narrow it with
+// WithParam and WithToken, then finish it with Times, Once, AtLeast, or
+// Never. An assertion that is never finished checks at the end of the test
+// that the method was called at least once.
+type Assertion struct {
+	tb       testing.TB
+	recorder *Recorder
+	method   string
+	token    string
+	params   map[string]interface{}
+
+	mu      sync.Mutex
+	checked bool
+}
+
+// AssertCalled starts an assertion on the calls of method in the default
+// session.
+func (s *Server) AssertCalled(tb testing.TB, method string) *Assertion {
+	tb.Helper()
+	return s.Session(session.DefaultName).AssertCalled(tb, method)
+}
+
+// AssertNotCalled fails the test if method was called in the default
+// session.
+func (s *Server) AssertNotCalled(tb testing.TB, method string) {
+	tb.Helper()
+	s.AssertCalled(tb, method).Never()
+}
+
+// ExpectError makes the next call of method in the default session fail
+// with the built-in error name, such as "bot_blocked".
+func (s *Server) ExpectError(method, name string) *Expectation {
+	return s.Session(session.DefaultName).ExpectError(method, name)
+}
+
+// AssertCalled starts an assertion on the calls of method in the session.
+func (s *Session) AssertCalled(tb testing.TB, method string) *Assertion {
+	tb.Helper()
+	a := &Assertion{tb: tb, recorder: s.Recorder(), method: method, params: make(map[string]interface{})}
+	tb.Cleanup(func() {
+		a.mu.Lock()
+		checked := a.checked
+		a.mu.Unlock()
+		if !checked {
+			a.AtLeast(1)
+		}
+	})
+	return a
+}
+
+// ExpectError makes the next call of method in the session fail with the
+// built-in error name, such as "bot_blocked". It panics if there is no
+// such error.
+func (s *Session) ExpectError(method, name string) *Expectation {
+	resp, ok := tgerrors.Builtin(name)
+	if !ok {
+		panic(fmt.Sprintf("tgmock: unknown built-in error %q", name))
+	}
+	sc := Scenario{Method: method, Times: 1, Response: resp}
+	id := s.Scenarios().Add(&sc)
+	return &Expectation{engine: s.Scenarios(), id: id, scenario: sc}
+}
+
+// WithParam only keeps calls whose parameter key equals value. Values are
+// compared as JSON, so 123 matches the chat_id 123 however it was sent.
+func (a *Assertion) WithParam(key string, value interface{}) *Assertion {
+	a.params[key] = value
+	return a
+}
+
+// WithToken only keeps calls made with token.
+func (a *Assertion) WithToken(token string) *Assertion {
+	a.token = token
+	return a
+}
+
+// Times fails the test unless exactly n calls match.
+func (a *Assertion) Times(n int) *Assertion {
+	a.tb.Helper()
+	if got := len(a.Requests()); got != n {
+		a.fail(fmt.Sprintf("expected %d %s, got %d", n, a.describe(), got))
+	}
+	return a
+}
+
+// Once fails the test unless exactly one call matches.
+func (a *Assertion) Once() *Assertion {
+	a.tb.Helper()
+	return a.Times(1)
+}
+
+// AtLeast fails the test unless n or more calls match.
+func (a *Assertion) AtLeast(n int) *Assertion {
+	a.tb.Helper()
+	if got := len(a.Requests()); got < n {
+		a.fail(fmt.Sprintf("expected at least %d %s, got %d", n, a.describe(), got))
+	}
+	return a
+}
+
+// Never fails the test if any call matches.
+func (a *Assertion) Never() *Assertion {
+	a.tb.Helper()
+	return a.Times(0)
+}
+
+// Requests returns the matching calls, oldest first. It counts as
+// finishing the assertion.
+func (a *Assertion) Requests() []RequestRecord {
+	a.mu.Lock()
+	a.checked = true
+	a.mu.Unlock()
+
+	var result []RequestRecord
+	for _, req := range a.recorder.Find(Filter{Method: a.method, Token: a.token}, 0) {
+		if matchParams(req.Params, a.params) {
+			result = append(result, req)
+		}
+	}
+	return result
+}
+
+// Last returns the most recent matching call. It fails the test if there
+// is none.
+func (a *Assertion) Last() RequestRecord {
+	a.tb.Helper()
+	requests := a.Requests()
+	if len(requests) == 0 {
+		a.fail(fmt.Sprintf("expected a %s, got none", a.describe()))
+		return RequestRecord{}
+	}
+	return requests[len(requests)-1]
+}
+
+func (a *Assertion) describe() string {
+	what := a.method + " calls"
+	var conds []string
+	if a.token != "" {
+		conds = append(conds, "token="+a.token)
+	}
+	params := make([]string, 0, len(a.params))
+	for key, value := range a.params {
+		params = append(params, fmt.Sprintf("%s=%v", key, value))
+	}
+	sort.Strings(params)
+	conds = append(conds, params...)
+	if len(conds) > 0 {
+		what += " with " + strings.Join(conds, ", ")
+	}
+	return what
+}
+
+// fail reports a mismatch along with the parameters of every call of the
+// method, which usually shows what went wrong.
+func (a *Assertion) fail(msg string) {
+	a.tb.Helper()
+	var b strings.Builder
+	b.WriteString(msg)
+	for _, req := range a.recorder.Find(Filter{Method: a.method}, 0) {
+		params, _ := json.Marshal(req.Params)
+		fmt.Fprintf(&b, "\n\t#%d %s %s -> %d", req.ID, req.Method, params, req.StatusCode)
+	}
+	a.tb.Error(b.String())
+}
+
+// Expectation is a scenario set up by ExpectError.
+type Expectation struct {
+	engine   *Engine
+	id       string
+	scenario Scenario
+}
+
+// WithParam only fails calls whose parameter key equals value.
+func (e *Expectation) WithParam(key string, value interface{}) *Expectation {
+	match := make(map[string]interface{}, len(e.scenario.Match)+1)
+	for k, v := range e.scenario.Match {
+		match[k] = v
+	}
+	match[key] = normalize(value)
+	e.scenario.Match = match
+	e.update()
+	return e
+}
+
+// Times fails the next n calls instead of one (0 = every call).
+func (e *Expectation) Times(n int) *Expectation {
+	e.scenario.Times = n
+	e.update()
+	return e
+}
+
+// ID returns the ID of the scenario behind the expectation.
+func (e *Expectation) ID() string {
+	return e.id
+}
+
+func (e *Expectation) update() {
+	sc := e.scenario
+	e.engine.Update(e.id, &sc)
+}
+
+// matchParams reports whether params holds every expected value.
+func matchParams(params, expected map[string]interface{}) bool {
+	for key, want := range expected {
+		got, ok := params[key]
+		if !ok {
+			return false
+		}
+		x, err1 := json.Marshal(got)
+		y, err2 := json.Marshal(want)
+		if err1 != nil || err2 != nil || !bytes.Equal(x, y) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize converts a value to what decoding it from JSON gives, which
+// is how scenarios see request parameters.
+func normalize(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
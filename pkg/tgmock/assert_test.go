@@ -0,0 +1,87 @@
+// pkg/tgmock/assert_test.go
+package tgmock
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// recordingTB captures failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Error(args ...interface{}) {
+	for _, a := range args {
+		r.errors = append(r.errors, a.(string))
+	}
+}
+
+func (r *recordingTB) Cleanup(f func()) {
+	r.cleanups = append(r.cleanups, f)
+}
+
+func (r *recordingTB) finish() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+func TestAssertCalled(t *testing.T) {
+	mock := NewTestServer(t, Options{})
+	post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":123,"text":"hi"}`)
+	post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":123,"text":"again"}`)
+	post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":7,"text":"hi"}`)
+
+	mock.AssertCalled(t, "sendMessage").WithParam("chat_id", 123).Times(2)
+	mock.AssertCalled(t, "sendMessage").WithParam("chat_id", 7).WithParam("text", "hi").Once()
+	mock.AssertCalled(t, "sendMessage").WithToken("123:abc").AtLeast(3)
+	mock.AssertNotCalled(t, "sendPhoto")
+	if last := mock.AssertCalled(t, "sendMessage").Last(); last.Params["chat_id"] != float64(7) {
+		t.Errorf("expected the last call to chat 7, got %v", last.Params)
+	}
+
+	rec := &recordingTB{TB: t}
+	mock.AssertCalled(rec, "sendMessage").WithParam("chat_id", 123).Times(1)
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "expected 1 sendMessage calls with chat_id=123, got 2") {
+		t.Errorf("unexpected failure message: %v", rec.errors)
+	}
+	if !strings.Contains(rec.errors[0], `"chat_id":7`) {
+		t.Errorf("expected the failure to list the recorded calls, got %q", rec.errors[0])
+	}
+
+	// An unfinished assertion checks for at least one call at the end
+	rec = &recordingTB{TB: t}
+	mock.AssertCalled(rec, "getMe")
+	mock.AssertCalled(rec, "sendMessage").WithParam("chat_id", 7)
+	rec.finish()
+	if len(rec.errors) != 1 || !strings.Contains(rec.errors[0], "getMe") {
+		t.Errorf("expected only the getMe assertion to fail, got %v", rec.errors)
+	}
+}
+
+func TestExpectError(t *testing.T) {
+	mock := NewTestServer(t, Options{})
+
+	mock.ExpectError("sendPhoto", "bot_blocked").WithParam("chat_id", 42).Times(2)
+	for i, want := range []int{http.StatusForbidden, http.StatusForbidden, http.StatusOK} {
+		if status := post(t, mock.URL+"/bot123:abc/sendPhoto", `{"chat_id":42,"photo":"AgAD"}`); status != want {
+			t.Errorf("call %d: expected %d, got %d", i, want, status)
+		}
+	}
+	if status := post(t, mock.URL+"/bot123:abc/sendPhoto", `{"chat_id":1,"photo":"AgAD"}`); status != http.StatusOK {
+		t.Errorf("expected other chats to be unaffected, got %d", status)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected an unknown error name to panic")
+		}
+	}()
+	mock.ExpectError("sendPhoto", "no_such_error")
+}
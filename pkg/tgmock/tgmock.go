@@ -0,0 +1,261 @@
+// Package tgmock embeds a complete tg-mock server in Go tests. The server
+// runs in-process, so tests can mount it on an httptest.Server and reach
+// into its scenario engine, update queue, and request recorder directly
+// instead of going through the control API.
+//
+//	mock := tgmock.NewTestServer(t, tgmock.Options{FakerSeed: 42})
+//	mock.Scenarios().Add(&tgmock.Scenario{Method: "sendMessage", Response: errors.ChatNotFound()})
+//	// ... point the bot under test at mock.URL ...
+//	if n := len(mock.Recorder().Find(tgmock.Filter{Method: "sendMessage"}, 0)); n != 1 {
+//		t.Fatalf("expected 1 sendMessage, got %d", n)
+//	}
+package tgmock
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/watzon/tg-mock/internal/apiversion"
+	"github.com/watzon/tg-mock/internal/config"
+	"github.com/watzon/tg-mock/internal/faker"
+	"github.com/watzon/tg-mock/internal/hooks"
+	"github.com/watzon/tg-mock/internal/inspector"
+	"github.com/watzon/tg-mock/internal/scenario"
+	"github.com/watzon/tg-mock/internal/server"
+	"github.com/watzon/tg-mock/internal/session"
+	"github.com/watzon/tg-mock/internal/tokens"
+	"github.com/watzon/tg-mock/internal/updates"
+	"github.com/watzon/tg-mock/internal/webhook"
+)
+
+// Types of the server's components, usable in assertions.
+type (
+	Scenario        = scenario.Scenario
+	Engine          = scenario.Engine
+	Queue           = updates.Queue
+	Recorder        = inspector.Recorder
+	RequestRecord   = inspector.RequestRecord
+	Filter          = inspector.Filter
+	Faker           = faker.Faker
+	GeneratorFunc   = faker.GeneratorFunc
+	ModifierFunc    = faker.ModifierFunc
+	WebhookRegistry = webhook.Registry
+	Hook            = hooks.Hook
+	Call            = hooks.Call
+	Response        = hooks.Response
+	BotProfile      = tokens.BotProfile
+)
+
+// Result returns a successful hook response, and Error an error response
+// that vetoes the call.
+var (
+	Result = hooks.Result
+	Error  = hooks.Error
+)
+
+// Token statuses.
+const (
+	StatusActive      = string(tokens.StatusActive)
+	StatusBanned      = string(tokens.StatusBanned)
+	StatusDeactivated = string(tokens.StatusDeactivated)
+)
+
+// Token registers a bot token.
+type Token struct {
+	// Status is StatusActive (the default), StatusBanned, or
+	// StatusDeactivated.
+	Status  string
+	BotName string
+	// Profile is the bot getMe returns; unset names are derived from the
+	// token.
+	Profile *BotProfile
+}
+
+// Options configure an embedded server. The zero value is a server that
+// accepts any well-formed token.
+type Options struct {
+	// FakerSeed makes generated responses reproducible (0 = random).
+	FakerSeed int64
+	// Deterministic also stops the clock at a time derived from the seed,
+	// so that whole responses, dates included, are byte-stable.
+	Deterministic bool
+	// Tokens, if not empty, are the only tokens the server accepts.
+	Tokens map[string]Token
+	// Scenarios are added to every session, like scenarios from the
+	// config file.
+	Scenarios []Scenario
+	// ControlToken, if set, is required on control API requests.
+	ControlToken string
+	// APIVersion is the simulated Bot API version, e.g. "7.0" (empty =
+	// latest).
+	APIVersion string
+	// Verbose logs every request.
+	Verbose bool
+	// Hooks intercept every Bot API call, in order. See Hook.
+	Hooks []Hook
+	// Generators replace the faker's generation of types, by name, and
+	// Modifiers change every generated object of a type, in every
+	// session. See Faker.RegisterGenerator and Faker.RegisterModifier.
+	Generators map[string]GeneratorFunc
+	Modifiers  map[string]ModifierFunc
+}
+
+// Server is an embedded tg-mock server.
+type Server struct {
+	srv *server.Server
+}
+
+// New creates a server. It doesn't listen anywhere; serve its Handler.
+func New(opts Options) (*Server, error) {
+	version, err := apiversion.ParseSupported(opts.APIVersion)
+	if err != nil {
+		return nil, fmt.Errorf("invalid api version: %w", err)
+	}
+
+	cfg := server.Config{
+		Verbose:      opts.Verbose,
+		FakerSeed:    opts.FakerSeed,
+		ControlToken: opts.ControlToken,
+		APIVersion:   version,
+		Hooks:        opts.Hooks,
+
+		Deterministic:   opts.Deterministic,
+		FakerGenerators: opts.Generators,
+		FakerModifiers:  opts.Modifiers,
+	}
+	if len(opts.Tokens) > 0 {
+		cfg.Tokens = make(map[string]config.TokenConfig, len(opts.Tokens))
+		for token, info := range opts.Tokens {
+			status := info.Status
+			if status == "" {
+				status = StatusActive
+			}
+			tc := config.TokenConfig{Status: status, BotName: info.BotName}
+			if p := info.Profile; p != nil {
+				tc.Profile = &config.BotProfileConfig{
+					FirstName:               p.FirstName,
+					LastName:                p.LastName,
+					Username:                p.Username,
+					CanJoinGroups:           p.CanJoinGroups,
+					CanReadAllGroupMessages: p.CanReadAllGroupMessages,
+					SupportsInlineQueries:   p.SupportsInlineQueries,
+					CanConnectToBusiness:    p.CanConnectToBusiness,
+					HasMainWebApp:           p.HasMainWebApp,
+				}
+			}
+			cfg.Tokens[token] = tc
+		}
+	}
+	for _, sc := range opts.Scenarios {
+		c := config.ScenarioConfig{
+			Method:       sc.Method,
+			Match:        sc.Match,
+			Times:        sc.Times,
+			ResponseData: sc.ResponseData,
+			Script:       sc.Script,
+		}
+		if sc.Response != nil {
+			c.Response = config.ResponseConfig{
+				ErrorCode:       sc.Response.ErrorCode,
+				Description:     sc.Response.Description,
+				RetryAfter:      sc.Response.RetryAfter,
+				MigrateToChatID: sc.Response.MigrateToChatID,
+				Parameters:      sc.Response.Parameters,
+				HTML:            sc.Response.HTML,
+			}
+		}
+		cfg.Scenarios = append(cfg.Scenarios, c)
+	}
+	return &Server{srv: server.New(cfg)}, nil
+}
+
+// Handler serves the Bot API, the control API, and the dashboard.
+func (s *Server) Handler() http.Handler {
+	return s.srv.Router()
+}
+
+// Session returns the named session's state, creating the session if
+// needed. The empty name is the default session.
+func (s *Server) Session(name string) *Session {
+	return &Session{st: s.srv.Sessions().Get(name)}
+}
+
+// AddHook adds a hook after the existing ones, replacing any hook with the
+// same name.
+func (s *Server) AddHook(h Hook) {
+	s.srv.Hooks().Add(h)
+}
+
+// RemoveHook removes the named hook.
+func (s *Server) RemoveHook(name string) bool {
+	return s.srv.Hooks().Remove(name)
+}
+
+// Scenarios returns the scenario engine of the default session.
+func (s *Server) Scenarios() *Engine {
+	return s.Session(session.DefaultName).Scenarios()
+}
+
+// Updates returns the update queue of the default session.
+func (s *Server) Updates() *Queue {
+	return s.Session(session.DefaultName).Updates()
+}
+
+// Recorder returns the request recorder of the default session.
+func (s *Server) Recorder() *Recorder {
+	return s.Session(session.DefaultName).Recorder()
+}
+
+// Webhooks returns the webhooks registered by setWebhook or the control
+// API.
+func (s *Server) Webhooks() *WebhookRegistry {
+	return s.srv.Webhooks()
+}
+
+// Restart returns the server to its initial state, as the control API's
+// restart endpoint does.
+func (s *Server) Restart() {
+	s.srv.Restart()
+}
+
+// Session is the isolated state of one session.
+type Session struct {
+	st *session.State
+}
+
+// Name returns the session's name.
+func (s *Session) Name() string { return s.st.Name }
+
+// Scenarios returns the session's scenario engine.
+func (s *Session) Scenarios() *Engine { return s.st.Scenarios }
+
+// Updates returns the session's update queue.
+func (s *Session) Updates() *Queue { return s.st.Updates }
+
+// Recorder returns the session's request recorder.
+func (s *Session) Recorder() *Recorder { return s.st.Recorder }
+
+// Faker returns the session's response generator.
+func (s *Session) Faker() *Faker { return s.st.Faker }
+
+// TestServer is an embedded server listening on a local httptest.Server.
+type TestServer struct {
+	*Server
+	// URL is the base URL of the server, e.g. http://127.0.0.1:41235.
+	URL string
+}
+
+// NewTestServer starts an embedded server on an httptest.Server that is
+// closed when the test finishes. Invalid options fail the test.
+func NewTestServer(tb testing.TB, opts Options) *TestServer {
+	tb.Helper()
+	s, err := New(opts)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	ts := httptest.NewServer(s.Handler())
+	tb.Cleanup(ts.Close)
+	return &TestServer{Server: s, URL: ts.URL}
+}
@@ -0,0 +1,242 @@
+// pkg/tgmock/tgmock_test.go
+package tgmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/watzon/tg-mock/internal/server"
+	tgerrors "github.com/watzon/tg-mock/pkg/errors"
+)
+
+func post(t *testing.T, url, body string) int {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestTestServer(t *testing.T) {
+	mock := NewTestServer(t, Options{
+		FakerSeed: 42,
+		Tokens:    map[string]Token{"123:abc": {BotName: "TestBot"}, "456:def": {Status: StatusBanned}},
+		Scenarios: []Scenario{{Method: "sendMessage", Match: map[string]interface{}{"chat_id": float64(999)}, Response: tgerrors.ChatNotFound()}},
+	})
+
+	if status := post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":1,"text":"hi"}`); status != http.StatusOK {
+		t.Errorf("expected sendMessage to succeed, got %d", status)
+	}
+	if status := post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":999,"text":"hi"}`); status != http.StatusBadRequest {
+		t.Errorf("expected configured scenario to fail the call, got %d", status)
+	}
+	if status := post(t, mock.URL+"/bot456:def/getMe", `{}`); status != http.StatusForbidden {
+		t.Errorf("expected banned token to be rejected, got %d", status)
+	}
+
+	recorded := mock.Recorder().Find(Filter{Method: "sendMessage"}, 0)
+	if len(recorded) != 2 || recorded[1].ScenarioID == "" {
+		t.Errorf("unexpected recorded requests: %+v", recorded)
+	}
+}
+
+func TestWhiteBoxAccess(t *testing.T) {
+	mock := NewTestServer(t, Options{})
+
+	mock.Scenarios().Add(&Scenario{Method: "getMe", Response: tgerrors.Unauthorized()})
+	if status := post(t, mock.URL+"/bot123:abc/getMe", `{}`); status != http.StatusUnauthorized {
+		t.Errorf("expected scenario added through the engine to apply, got %d", status)
+	}
+
+	mock.Updates().Add(map[string]interface{}{"message": map[string]interface{}{"text": "hi"}})
+	if mock.Updates().Pending() != 1 {
+		t.Errorf("expected 1 pending update, got %d", mock.Updates().Pending())
+	}
+
+	// Sessions are isolated
+	isolated := mock.Session("isolated")
+	if isolated.Updates().Pending() != 0 || isolated.Recorder().Count() != 0 {
+		t.Error("expected a new session to start empty")
+	}
+
+	mock.Restart()
+	if len(mock.Scenarios().List()) != 0 || mock.Recorder().Count() != 0 {
+		t.Error("expected restart to clear the default session")
+	}
+}
+
+func TestNewRejectsInvalidVersion(t *testing.T) {
+	if _, err := New(Options{APIVersion: "not-a-version"}); err == nil {
+		t.Error("expected an invalid API version to be rejected")
+	}
+}
+
+func TestHooks(t *testing.T) {
+	mock := NewTestServer(t, Options{
+		Hooks: []Hook{{
+			Name:    "no-links",
+			Methods: []string{"sendMessage"},
+			Before: func(call *Call) *Response {
+				if text, _ := call.Params["text"].(string); strings.Contains(text, "http://") {
+					return Error(400, "Bad Request: links are not allowed")
+				}
+				call.Params["text"] = strings.ToUpper(call.Params["text"].(string))
+				return nil
+			},
+			After: func(call *Call, resp *Response) {
+				resp.Result.(map[string]interface{})["text"] = call.Params["text"]
+			},
+		}},
+	})
+
+	if status := post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":1,"text":"see http://example.com"}`); status != http.StatusBadRequest {
+		t.Errorf("expected the hook to veto the call, got %d", status)
+	}
+	post(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":1,"text":"hello"}`)
+	last := mock.AssertCalled(t, "sendMessage").WithParam("text", "HELLO").Last()
+	var sent struct {
+		Result struct {
+			Text string `json:"text"`
+		} `json:"result"`
+	}
+	data, _ := json.Marshal(last.Response)
+	json.Unmarshal(data, &sent)
+	if sent.Result.Text != "HELLO" {
+		t.Errorf("expected the After hook to change the result, got %q", sent.Result.Text)
+	}
+	vetoed := mock.AssertCalled(t, "sendMessage").WithParam("text", "see http://example.com").Last()
+	if vetoed.ScenarioID != "hook:no-links" || !vetoed.IsError {
+		t.Errorf("expected the veto to be recorded, got %+v", vetoed)
+	}
+
+	mock.AddHook(Hook{Name: "me", Before: func(call *Call) *Response {
+		return Result(map[string]interface{}{"id": 1, "is_bot": true, "first_name": "Hooked"})
+	}})
+	post(t, mock.URL+"/bot123:abc/getMe", `{}`)
+	if me := mock.AssertCalled(t, "getMe").Last(); me.ScenarioID != "hook:me" {
+		t.Errorf("expected the hook to answer getMe, got %+v", me)
+	}
+	if !mock.RemoveHook("me") || mock.RemoveHook("me") {
+		t.Error("expected the hook to be removed once")
+	}
+}
+
+func TestFakerGenerators(t *testing.T) {
+	mock := NewTestServer(t, Options{
+		FakerSeed: 42,
+		Generators: map[string]GeneratorFunc{
+			"UserProfilePhotos": func(f *Faker, params map[string]interface{}) interface{} {
+				return map[string]interface{}{"total_count": int64(42), "photos": []interface{}{}}
+			},
+		},
+		Modifiers: map[string]ModifierFunc{
+			"Chat": func(f *Faker, obj map[string]interface{}) {
+				obj["username"] = "golden_chat"
+			},
+		},
+	})
+
+	var photos struct {
+		Result struct {
+			TotalCount int64 `json:"total_count"`
+		} `json:"result"`
+	}
+	call(t, mock.URL+"/bot123:abc/getUserProfilePhotos", `{"user_id":1}`, &photos)
+	if photos.Result.TotalCount != 42 {
+		t.Errorf("expected the custom generator's result, got %+v", photos.Result)
+	}
+
+	// Modifiers reach objects nested in others
+	var sent struct {
+		Result struct {
+			Chat struct {
+				Username string `json:"username"`
+			} `json:"chat"`
+		} `json:"result"`
+	}
+	call(t, mock.URL+"/bot123:abc/sendMessage", `{"chat_id":-100,"text":"hi"}`, &sent)
+	if sent.Result.Chat.Username != "golden_chat" {
+		t.Errorf("expected the modified chat, got %+v", sent.Result)
+	}
+
+	// Generators can also be registered at runtime, per session
+	mock.Session("").Faker().RegisterGenerator("UserProfilePhotos", nil)
+	call(t, mock.URL+"/bot123:abc/getUserProfilePhotos", `{"user_id":1}`, &photos)
+	if photos.Result.TotalCount == 42 {
+		t.Error("expected the built-in generator after unregistering")
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	run := func() []string {
+		mock := NewTestServer(t, Options{FakerSeed: 7, Deterministic: true})
+		var bodies []string
+		for _, req := range []struct{ method, body string }{
+			{"getMe", `{}`},
+			{"sendMessage", `{"chat_id":1,"text":"hi"}`},
+			{"getChat", `{"chat_id":-100}`},
+			{"editMessageText", `{"chat_id":1,"message_id":1,"text":"edited"}`},
+		} {
+			resp, err := http.Post(mock.URL+"/bot123:abc/"+req.method, "application/json", strings.NewReader(req.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			bodies = append(bodies, string(body))
+		}
+
+		// Uploaded files get seeded file IDs too
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		mw.WriteField("chat_id", "1")
+		fw, _ := mw.CreateFormFile("document", "notes.txt")
+		fw.Write([]byte("hello"))
+		mw.Close()
+		resp, err := http.Post(mock.URL+"/bot123:abc/sendDocument", mw.FormDataContentType(), &buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return append(bodies, string(body))
+	}
+
+	first, second := run(), run()
+	for i := range first {
+		if first[i] != second[i] {
+			t.Errorf("response %d differs between runs:\n%s\n%s", i, first[i], second[i])
+		}
+	}
+
+	var sent struct {
+		Result struct {
+			Date int64 `json:"date"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(first[1]), &sent); err != nil {
+		t.Fatal(err)
+	}
+	if want := server.DeterministicEpoch(7).Unix(); sent.Result.Date != want {
+		t.Errorf("expected the message dated %d, got %d", want, sent.Result.Date)
+	}
+}
+
+func call(t *testing.T, url, body string, result interface{}) {
+	t.Helper()
+	resp, err := http.Post(url, "application/json", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,123 @@
+// Package tgmocktest starts tg-mock in a Docker container for integration
+// tests, using testcontainers-go. It lives in its own module so that the
+// main module doesn't depend on Docker tooling.
+//
+//	mock, err := tgmocktest.RunContainer(ctx)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer mock.Terminate(ctx)
+//
+//	// Point the bot under test at mock.URL instead of https://api.telegram.org
+//	mock.Client.AddScenario(ctx, client.Scenario{Method: "sendMessage", Response: errors.ChatNotFound()})
+package tgmocktest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/watzon/tg-mock/pkg/client"
+)
+
+// DefaultImage is the image started when WithImage isn't given.
+const DefaultImage = "ghcr.io/watzon/tg-mock:latest"
+
+// port is the port tg-mock listens on inside the container.
+const port = "8081/tcp"
+
+// configPath is where WithConfigFile copies the config file.
+const configPath = "/config.yaml"
+
+// Container is a running tg-mock container.
+type Container struct {
+	testcontainers.Container
+	// URL is the base URL of the server, for both the Bot API and the
+	// control API, e.g. http://localhost:32771.
+	URL string
+	// Client is a control API client connected to the server.
+	Client *client.Client
+}
+
+type options struct {
+	image        string
+	configFile   string
+	controlToken string
+	args         []string
+}
+
+// Option configures RunContainer.
+type Option func(*options)
+
+// WithImage starts the given image instead of DefaultImage.
+func WithImage(image string) Option {
+	return func(o *options) { o.image = image }
+}
+
+// WithConfigFile copies a tg-mock config file from the host into the
+// container and loads it.
+func WithConfigFile(path string) Option {
+	return func(o *options) { o.configFile = path }
+}
+
+// WithControlToken protects the control API with token. The returned
+// Client is set up to send it.
+func WithControlToken(token string) Option {
+	return func(o *options) { o.controlToken = token }
+}
+
+// WithArgs passes extra command line flags to tg-mock, e.g.
+// WithArgs("--faker-seed", "42").
+func WithArgs(args ...string) Option {
+	return func(o *options) { o.args = append(o.args, args...) }
+}
+
+// RunContainer starts tg-mock, waits until /health answers, and returns
+// the container with its base URL and a connected control client. The
+// caller must Terminate the container.
+func RunContainer(ctx context.Context, opts ...Option) (*Container, error) {
+	o := options{image: DefaultImage}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cmd := []string{"--port", "8081", "--storage-dir", "/data"}
+	req := testcontainers.ContainerRequest{
+		Image:        o.image,
+		ExposedPorts: []string{port},
+		WaitingFor:   wait.ForHTTP("/health").WithPort(port),
+	}
+	if o.configFile != "" {
+		req.Files = append(req.Files, testcontainers.ContainerFile{
+			HostFilePath:      o.configFile,
+			ContainerFilePath: configPath,
+			FileMode:          0o644,
+		})
+		cmd = append(cmd, "--config", configPath)
+	}
+	if o.controlToken != "" {
+		cmd = append(cmd, "--control-token", o.controlToken)
+	}
+	req.Cmd = append(cmd, o.args...)
+
+	ctr, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		if ctr != nil {
+			ctr.Terminate(ctx)
+		}
+		return nil, fmt.Errorf("starting tg-mock: %w", err)
+	}
+
+	url, err := ctr.PortEndpoint(ctx, port, "http")
+	if err != nil {
+		ctr.Terminate(ctx)
+		return nil, fmt.Errorf("resolving tg-mock port: %w", err)
+	}
+	c := client.New(url)
+	c.ControlToken = o.controlToken
+	return &Container{Container: ctr, URL: url, Client: c}, nil
+}
@@ -0,0 +1,35 @@
+// pkg/tgmocktest/tgmocktest_test.go
+package tgmocktest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/watzon/tg-mock/pkg/client"
+)
+
+func TestRunContainer(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx := context.Background()
+
+	mock, err := RunContainer(ctx, WithControlToken("s3cret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { mock.Terminate(ctx) })
+
+	resp, err := http.Get(mock.URL + "/bot123:abc/getMe")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected getMe to succeed, got %d", resp.StatusCode)
+	}
+
+	if err := mock.Client.Verify(ctx, client.RequestFilter{Method: "getMe"}, 1); err != nil {
+		t.Error(err)
+	}
+}